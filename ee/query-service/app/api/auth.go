@@ -10,6 +10,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/SigNoz/signoz/pkg/query-service/constants"
+	"github.com/SigNoz/signoz/pkg/types/ssotypes"
 	"github.com/SigNoz/signoz/pkg/valuer"
 )
 
@@ -96,7 +97,12 @@ func (ah *APIHandler) receiveSAML(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	nextPage, err := ah.Signoz.Modules.User.PrepareSsoRedirect(ctx, redirectUri, email, ah.opts.JWT)
+	identity := &ssotypes.SSOIdentity{Email: email, Attributes: map[string][]string{}}
+	for name := range assertionInfo.Values {
+		identity.Attributes[name] = assertionInfo.Values.GetAll(name)
+	}
+
+	nextPage, err := ah.Signoz.Modules.User.PrepareSsoRedirect(ctx, redirectUri, domain, identity, ah.opts.JWT)
 	if err != nil {
 		zap.L().Error("[receiveSAML] failed to generate redirect URI after successful login ", zap.String("domain", domain.String()), zap.Error(err))
 		handleSsoError(w, r, redirectUri)
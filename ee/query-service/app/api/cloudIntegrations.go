@@ -146,6 +146,7 @@ func (ah *APIHandler) getOrCreateCloudIntegrationPAT(ctx context.Context, orgId
 
 	newPAT, err := types.NewStorableAPIKey(
 		integrationPATName,
+		orgIdUUID,
 		integrationUser.ID,
 		types.RoleViewer,
 		0,
@@ -26,7 +26,7 @@ func (store *config) Get(ctx context.Context, orgID string) (*alertmanagertypes.
 
 	err := store.
 		sqlstore.
-		BunDB().
+		BunDBCtx(ctx).
 		NewSelect().
 		Model(storeableConfig).
 		Where("org_id = ?", orgID).
@@ -87,7 +87,7 @@ func (store *config) GetChannelByID(ctx context.Context, orgID string, id valuer
 
 	err := store.
 		sqlstore.
-		BunDB().
+		BunDBCtx(ctx).
 		NewSelect().
 		Model(channel).
 		Where("org_id = ?", orgID).
@@ -143,7 +143,7 @@ func (store *config) ListChannels(ctx context.Context, orgID string) ([]*alertma
 
 	err := store.
 		sqlstore.
-		BunDB().
+		BunDBCtx(ctx).
 		NewSelect().
 		Model(&channels).
 		Where("org_id = ?", orgID).
@@ -160,7 +160,7 @@ func (store *config) ListAllChannels(ctx context.Context) ([]*alertmanagertypes.
 
 	err := store.
 		sqlstore.
-		BunDB().
+		BunDBCtx(ctx).
 		NewSelect().
 		Model(&channels).
 		Scan(ctx)
@@ -182,7 +182,7 @@ func (store *config) GetMatchers(ctx context.Context, orgID string) (map[string]
 
 	err := store.
 		sqlstore.
-		BunDB().
+		BunDBCtx(ctx).
 		NewSelect().
 		Column("id", "data").
 		Model(&matchers).
@@ -2,13 +2,16 @@ package alertmanager
 
 import (
 	"context"
+	"encoding/json"
 	"io"
 	"net/http"
 	"time"
 
 	"github.com/SigNoz/signoz/pkg/errors"
 	"github.com/SigNoz/signoz/pkg/http/render"
+	"github.com/SigNoz/signoz/pkg/modules/audit"
 	"github.com/SigNoz/signoz/pkg/types/alertmanagertypes"
+	"github.com/SigNoz/signoz/pkg/types/audittypes"
 	"github.com/SigNoz/signoz/pkg/types/authtypes"
 	"github.com/SigNoz/signoz/pkg/valuer"
 	"github.com/gorilla/mux"
@@ -16,11 +19,13 @@ import (
 
 type API struct {
 	alertmanager Alertmanager
+	audit        audit.Module
 }
 
-func NewAPI(alertmanager Alertmanager) *API {
+func NewAPI(alertmanager Alertmanager, audit audit.Module) *API {
 	return &API{
 		alertmanager: alertmanager,
+		audit:        audit,
 	}
 }
 
@@ -196,12 +201,20 @@ func (api *API) UpdateChannelByID(rw http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	before, err := api.alertmanager.GetChannelByID(ctx, claims.OrgID, id)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
 	err = api.alertmanager.UpdateChannelByReceiverAndID(ctx, claims.OrgID, receiver, id)
 	if err != nil {
 		render.Error(rw, err)
 		return
 	}
 
+	api.recordChannel(ctx, claims.OrgID, audittypes.ActionUpdate, id, before, json.RawMessage(body))
+
 	render.Success(rw, http.StatusNoContent, nil)
 }
 
@@ -233,12 +246,20 @@ func (api *API) DeleteChannelByID(rw http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	before, err := api.alertmanager.GetChannelByID(ctx, claims.OrgID, id)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
 	err = api.alertmanager.DeleteChannelByID(ctx, claims.OrgID, id)
 	if err != nil {
 		render.Error(rw, err)
 		return
 	}
 
+	api.recordChannel(ctx, claims.OrgID, audittypes.ActionDelete, id, before, nil)
+
 	render.Success(rw, http.StatusNoContent, nil)
 }
 
@@ -265,11 +286,32 @@ func (api *API) CreateChannel(rw http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	err = api.alertmanager.CreateChannel(ctx, claims.OrgID, receiver)
+	channel, err := api.alertmanager.CreateChannel(ctx, claims.OrgID, receiver)
 	if err != nil {
 		render.Error(rw, err)
 		return
 	}
 
+	api.recordChannel(ctx, claims.OrgID, audittypes.ActionCreate, channel.ID, nil, json.RawMessage(body))
+
 	render.Success(rw, http.StatusNoContent, nil)
 }
+
+// recordChannel records a change to the channel, the receiver configuration is recorded instead of the channel so
+// that its secrets get redacted.
+func (api *API) recordChannel(ctx context.Context, orgID string, action audittypes.Action, id valuer.UUID, before *alertmanagertypes.Channel, after json.RawMessage) {
+	orgUUID, err := valuer.NewUUID(orgID)
+	if err != nil {
+		return
+	}
+
+	var beforeReceiver, afterReceiver any
+	if before != nil {
+		beforeReceiver = json.RawMessage(before.Data)
+	}
+	if after != nil {
+		afterReceiver = after
+	}
+
+	api.audit.Record(ctx, orgUUID, action, audittypes.NewResource(audittypes.ResourceTypeChannel, id.StringValue()), beforeReceiver, afterReceiver)
+}
@@ -355,19 +355,19 @@ func (provider *provider) UpdateChannelByReceiverAndID(ctx context.Context, orgI
 	return nil
 }
 
-func (provider *provider) CreateChannel(ctx context.Context, orgID string, receiver alertmanagertypes.Receiver) error {
+func (provider *provider) CreateChannel(ctx context.Context, orgID string, receiver alertmanagertypes.Receiver) (*alertmanagertypes.Channel, error) {
 	channel := alertmanagertypes.NewChannelFromReceiver(receiver, orgID)
 
 	config, err := provider.configStore.Get(ctx, orgID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if err := config.CreateReceiver(receiver); err != nil {
-		return err
+		return nil, err
 	}
 
-	return provider.configStore.CreateChannel(ctx, channel, alertmanagertypes.WithCb(func(ctx context.Context) error {
+	if err := provider.configStore.CreateChannel(ctx, channel, alertmanagertypes.WithCb(func(ctx context.Context) error {
 		url := provider.url.JoinPath(routesPath)
 
 		body, err := json.Marshal(alertmanagertypes.MSTeamsV2ReceiverToMSTeamsReceiver(receiver))
@@ -398,7 +398,11 @@ func (provider *provider) CreateChannel(ctx context.Context, orgID string, recei
 		}
 
 		return nil
-	}))
+	})); err != nil {
+		return nil, err
+	}
+
+	return channel, nil
 }
 
 func (provider *provider) DeleteChannelByID(ctx context.Context, orgID string, channelID valuer.UUID) error {
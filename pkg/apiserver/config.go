@@ -1,8 +1,11 @@
 package apiserver
 
 import (
+	"net/netip"
+	"strings"
 	"time"
 
+	"github.com/SigNoz/signoz/pkg/errors"
 	"github.com/SigNoz/signoz/pkg/factory"
 )
 
@@ -10,6 +13,9 @@ import (
 type Config struct {
 	Timeout Timeout `mapstructure:"timeout"`
 	Logging Logging `mapstructure:"logging"`
+	// The addresses or CIDR ranges of the proxies in front of signoz. The client address is only taken from the
+	// X-Forwarded-For header of the requests sent by these proxies.
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
 }
 
 type Timeout struct {
@@ -51,5 +57,30 @@ func newConfig() factory.Config {
 }
 
 func (c Config) Validate() error {
-	return nil
+	_, err := c.TrustedProxyPrefixes()
+	return err
+}
+
+// TrustedProxyPrefixes parses the trusted proxies, single addresses are turned into prefixes of their own.
+func (c Config) TrustedProxyPrefixes() ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(c.TrustedProxies))
+	for _, proxy := range c.TrustedProxies {
+		if strings.Contains(proxy, "/") {
+			prefix, err := netip.ParsePrefix(proxy)
+			if err != nil {
+				return nil, errors.WrapInvalidInputf(err, errors.CodeInvalidInput, "invalid trusted proxy %q", proxy)
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+
+		addr, err := netip.ParseAddr(proxy)
+		if err != nil {
+			return nil, errors.WrapInvalidInputf(err, errors.CodeInvalidInput, "invalid trusted proxy %q", proxy)
+		}
+		addr = addr.Unmap()
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+
+	return prefixes, nil
 }
@@ -2,6 +2,7 @@ package apiserver
 
 import (
 	"context"
+	"net/netip"
 	"testing"
 	"time"
 
@@ -17,6 +18,7 @@ func TestNewWithEnvProvider(t *testing.T) {
 	t.Setenv("SIGNOZ_APISERVER_TIMEOUT_MAX", "700s")
 	t.Setenv("SIGNOZ_APISERVER_TIMEOUT_EXCLUDED__ROUTES", "/excluded1,/excluded2")
 	t.Setenv("SIGNOZ_APISERVER_LOGGING_EXCLUDED__ROUTES", "/api/v1/health1")
+	t.Setenv("SIGNOZ_APISERVER_TRUSTED__PROXIES", "10.0.0.0/8,192.0.2.1")
 
 	conf, err := config.New(
 		context.Background(),
@@ -51,7 +53,14 @@ func TestNewWithEnvProvider(t *testing.T) {
 				"/api/v1/health1",
 			},
 		},
+		TrustedProxies: []string{"10.0.0.0/8", "192.0.2.1"},
 	}
 
 	assert.Equal(t, expected, actual)
+
+	prefixes, err := actual.TrustedProxyPrefixes()
+	require.NoError(t, err)
+	assert.Equal(t, []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("192.0.2.1/32")}, prefixes)
+
+	assert.Error(t, Config{TrustedProxies: []string{"proxy.local"}}.Validate())
 }
@@ -7,6 +7,7 @@ import (
 )
 
 type Emailing interface {
-	// Sends an HTML email to the given address with the given subject and template name and data.
-	SendHTML(context.Context, string, string, emailtypes.TemplateName, map[string]any) error
+	// Sends an HTML email to the given address with the given subject and template name and data, along with the given
+	// attachments.
+	SendHTML(context.Context, string, string, emailtypes.TemplateName, map[string]any, ...emailtypes.Attachment) error
 }
@@ -12,17 +12,20 @@ var _ emailing.Emailing = (*Provider)(nil)
 type Provider struct {
 	SentEmailCountByTo           map[string]int
 	SentEmailCountByTemplateName map[emailtypes.TemplateName]int
+	SentAttachmentsByTo          map[string][]emailtypes.Attachment
 }
 
 func New() *Provider {
 	return &Provider{
 		SentEmailCountByTo:           make(map[string]int),
 		SentEmailCountByTemplateName: make(map[emailtypes.TemplateName]int),
+		SentAttachmentsByTo:          make(map[string][]emailtypes.Attachment),
 	}
 }
 
-func (provider *Provider) SendHTML(ctx context.Context, to string, subject string, templateName emailtypes.TemplateName, data map[string]any) error {
+func (provider *Provider) SendHTML(ctx context.Context, to string, subject string, templateName emailtypes.TemplateName, data map[string]any, attachments ...emailtypes.Attachment) error {
 	provider.SentEmailCountByTo[to]++
 	provider.SentEmailCountByTemplateName[templateName]++
+	provider.SentAttachmentsByTo[to] = append(provider.SentAttachmentsByTo[to], attachments...)
 	return nil
 }
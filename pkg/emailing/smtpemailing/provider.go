@@ -58,7 +58,7 @@ func New(ctx context.Context, providerSettings factory.ProviderSettings, config
 	return &provider{settings: settings, store: store, client: client}, nil
 }
 
-func (provider *provider) SendHTML(ctx context.Context, to string, subject string, templateName emailtypes.TemplateName, data map[string]any) error {
+func (provider *provider) SendHTML(ctx context.Context, to string, subject string, templateName emailtypes.TemplateName, data map[string]any, attachments ...emailtypes.Attachment) error {
 	toAddress, err := mail.ParseAddressList(to)
 	if err != nil {
 		return err
@@ -74,5 +74,15 @@ func (provider *provider) SendHTML(ctx context.Context, to string, subject strin
 		return err
 	}
 
-	return provider.client.Do(ctx, toAddress, subject, client.ContentTypeHTML, content)
+	clientAttachments := make([]client.Attachment, 0, len(attachments))
+	for _, attachment := range attachments {
+		clientAttachments = append(clientAttachments, client.Attachment{
+			Name:        attachment.Name,
+			ContentType: attachment.ContentType,
+			Data:        attachment.Data,
+			Inline:      attachment.Inline,
+		})
+	}
+
+	return provider.client.Do(ctx, toAddress, subject, client.ContentTypeHTML, content, clientAttachments...)
 }
@@ -1,31 +1,40 @@
 package middleware
 
 import (
+	"bytes"
+	"encoding/json"
+	"io"
 	"log/slog"
 	"net/http"
+	"strings"
 	"time"
 
+	"github.com/SigNoz/signoz/pkg/errors"
+	"github.com/SigNoz/signoz/pkg/http/render"
 	"github.com/SigNoz/signoz/pkg/sharder"
 	"github.com/SigNoz/signoz/pkg/sqlstore"
 	"github.com/SigNoz/signoz/pkg/types"
 	"github.com/SigNoz/signoz/pkg/types/authtypes"
 	"github.com/SigNoz/signoz/pkg/valuer"
+	"github.com/gorilla/mux"
 )
 
 const (
 	apiKeyCrossOrgMessage string = "::API-KEY-CROSS-ORG::"
+	apiKeyDeniedMessage   string = "::API-KEY-DENIED::"
 )
 
 type APIKey struct {
-	store   sqlstore.SQLStore
-	uuid    *authtypes.UUID
-	headers []string
-	logger  *slog.Logger
-	sharder sharder.Sharder
+	store         sqlstore.SQLStore
+	uuid          *authtypes.UUID
+	headers       []string
+	logger        *slog.Logger
+	sharder       sharder.Sharder
+	clientAddress *ClientAddress
 }
 
-func NewAPIKey(store sqlstore.SQLStore, headers []string, logger *slog.Logger, sharder sharder.Sharder) *APIKey {
-	return &APIKey{store: store, uuid: authtypes.NewUUID(), headers: headers, logger: logger, sharder: sharder}
+func NewAPIKey(store sqlstore.SQLStore, headers []string, logger *slog.Logger, sharder sharder.Sharder, clientAddress *ClientAddress) *APIKey {
+	return &APIKey{store: store, uuid: authtypes.NewUUID(), headers: headers, logger: logger, sharder: sharder, clientAddress: clientAddress}
 }
 
 func (a *APIKey) Wrap(next http.Handler) http.Handler {
@@ -56,6 +65,7 @@ func (a *APIKey) Wrap(next http.Handler) http.Handler {
 			NewSelect().
 			Model(&apiKey).
 			Where("token = ?", apiKeyToken).
+			Where("revoked = false").
 			Scan(r.Context())
 		if err != nil {
 			next.ServeHTTP(w, r)
@@ -76,11 +86,36 @@ func (a *APIKey) Wrap(next http.Handler) http.Handler {
 			return
 		}
 
+		// the key acts in its org with at most the role its user has there
+		membership := types.OrgMembership{}
+		err = a.store.BunDB().NewSelect().Model(&membership).Where("user_id = ?", apiKey.UserID).Where("org_id = ?", apiKey.OrgID).Scan(r.Context())
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		role, err := types.NewRole(membership.Role)
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if apiKey.Role.IsLowerThan(role) {
+			role = apiKey.Role
+		}
+
+		if err := a.authorize(r, &apiKey, membership.OrgID); err != nil {
+			a.logger.WarnContext(r.Context(), apiKeyDeniedMessage, "api_key_id", apiKey.ID, "error", err)
+			render.Error(w, err)
+			return
+		}
+
 		jwt := authtypes.Claims{
-			UserID: user.ID.String(),
-			Role:   apiKey.Role,
-			Email:  user.Email,
-			OrgID:  user.OrgID,
+			UserID:   user.ID.String(),
+			Role:     role,
+			Email:    user.Email,
+			OrgID:    membership.OrgID,
+			APIKeyID: apiKey.ID.StringValue(),
 		}
 
 		ctx = authtypes.NewContextWithClaims(ctx, jwt)
@@ -101,8 +136,17 @@ func (a *APIKey) Wrap(next http.Handler) http.Handler {
 
 		next.ServeHTTP(w, r)
 
-		apiKey.LastUsed = time.Now()
-		_, err = a.store.BunDB().NewUpdate().Model(&apiKey).Column("last_used").Where("token = ?", apiKeyToken).Where("revoked = false").Exec(r.Context())
+		_, err = a.
+			store.
+			BunDB().
+			NewUpdate().
+			Model(new(types.StorableAPIKey)).
+			Set("last_used = ?", time.Now()).
+			Set("last_used_ip = ?", a.clientAddress.Resolve(r)).
+			Set("usage_count = usage_count + 1").
+			Where("token = ?", apiKeyToken).
+			Where("revoked = false").
+			Exec(r.Context())
 		if err != nil {
 			a.logger.ErrorContext(r.Context(), "failed to update last used of api key", "error", err)
 		}
@@ -110,3 +154,85 @@ func (a *APIKey) Wrap(next http.Handler) http.Handler {
 	})
 
 }
+
+// authorize enforces the allowed ips and the scopes of the api key, on top of the role checked by the routes.
+func (a *APIKey) authorize(req *http.Request, apiKey *types.StorableAPIKey, orgID string) error {
+	if !apiKey.AllowsIP(a.clientAddress.Resolve(req)) {
+		return errors.New(errors.TypeForbidden, errors.CodeForbidden, "the api key is not allowed to be used from this address")
+	}
+
+	scope, err := apiKey.ScopeOf(req.Method, req.URL.Path)
+	if err != nil {
+		return err
+	}
+
+	if scope == types.APIKeyScopeRulesManage && len(apiKey.RuleLabels) > 0 {
+		return a.authorizeRule(req, apiKey, orgID)
+	}
+
+	return nil
+}
+
+// authorizeRule checks that both the rule being changed and the rule it is changed into have the rule labels of the api key.
+// Listing the rules of the org is denied.
+func (a *APIKey) authorizeRule(req *http.Request, apiKey *types.StorableAPIKey, orgID string) error {
+	deniedErr := errors.New(errors.TypeForbidden, errors.CodeForbidden, "the api key is only allowed to manage the rules with its rule labels")
+
+	id, ok := mux.Vars(req)["id"]
+	// the listing has the rules of the whole org, the key gets its rules by id
+	if !ok && req.Method == http.MethodGet {
+		return deniedErr
+	}
+
+	if ok {
+		var data string
+		err := a.
+			store.
+			BunDB().
+			NewSelect().
+			Table("rule").
+			Column("data").
+			Where("id = ?", id).
+			Where("org_id = ?", orgID).
+			Scan(req.Context(), &data)
+		// missing rules are reported by the handlers
+		if err == nil {
+			if labels, _ := ruleLabels([]byte(data)); !apiKey.MatchesRuleLabels(labels) {
+				return deniedErr
+			}
+		}
+	}
+
+	if req.Method == http.MethodGet || req.Method == http.MethodDelete || strings.Contains(req.URL.Path, "/history/") {
+		return nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "failed to read request body")
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	labels, ok := ruleLabels(body)
+	// a patch without labels keeps the ones of the stored rule
+	if !ok && req.Method == http.MethodPatch {
+		return nil
+	}
+
+	if !apiKey.MatchesRuleLabels(labels) {
+		return deniedErr
+	}
+
+	return nil
+}
+
+func ruleLabels(data []byte) (map[string]string, bool) {
+	rule := struct {
+		Labels map[string]string `json:"labels"`
+	}{}
+	if err := json.Unmarshal(data, &rule); err != nil {
+		return nil, false
+	}
+
+	return rule.Labels, rule.Labels != nil
+}
@@ -0,0 +1,182 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/factory/factorytest"
+	"github.com/SigNoz/signoz/pkg/query-service/utils"
+	"github.com/SigNoz/signoz/pkg/sharder"
+	"github.com/SigNoz/signoz/pkg/sharder/noopsharder"
+	"github.com/SigNoz/signoz/pkg/types"
+	"github.com/SigNoz/signoz/pkg/types/authtypes"
+	"github.com/SigNoz/signoz/pkg/types/ruletypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeyRestrictions(t *testing.T) {
+	ctx := context.Background()
+	sqlStore := utils.NewQueryServiceDBForTests(t)
+	require.NoError(t, utils.CreateTestOrg(t, sqlStore))
+	orgID, err := utils.GetTestOrgId(sqlStore)
+	require.NoError(t, err)
+
+	user, err := types.NewUser("jane", "jane@example.com", types.RoleAdmin.String(), orgID.StringValue())
+	require.NoError(t, err)
+	_, err = sqlStore.BunDB().NewInsert().Model(user).Exec(ctx)
+	require.NoError(t, err)
+	_, err = sqlStore.BunDB().NewInsert().Model(types.NewOrgMembership(user.ID.StringValue(), orgID.StringValue(), types.RoleAdmin)).Exec(ctx)
+	require.NoError(t, err)
+
+	apiKey, err := types.NewStorableAPIKey("terraform", orgID, user.ID, types.RoleAdmin, 0)
+	require.NoError(t, err)
+	apiKey.Scopes = []types.APIKeyScope{types.APIKeyScopeRulesManage}
+	apiKey.RuleLabels = map[string]string{"team": "payments"}
+	apiKey.AllowedIPs = []string{"192.0.2.0/24"}
+	_, err = sqlStore.BunDB().NewInsert().Model(apiKey).Exec(ctx)
+	require.NoError(t, err)
+
+	rules := map[string]string{}
+	for _, team := range []string{"payments", "search"} {
+		rule := &ruletypes.Rule{
+			Identifiable: types.Identifiable{ID: valuer.GenerateUUID()},
+			Data:         `{"alert":"cpu","labels":{"team":"` + team + `"}}`,
+			OrgID:        orgID.StringValue(),
+		}
+		_, err = sqlStore.BunDB().NewInsert().Model(rule).Exec(ctx)
+		require.NoError(t, err)
+		rules[team] = rule.ID.StringValue()
+	}
+
+	noop, err := noopsharder.New(ctx, factorytest.NewSettings(), sharder.Config{})
+	require.NoError(t, err)
+
+	router := mux.NewRouter()
+	router.Use(NewAPIKey(sqlStore, []string{"SIGNOZ-API-KEY"}, slog.New(slog.NewTextHandler(io.Discard, nil)), noop, NewClientAddress(nil)).Wrap)
+	handler := func(rw http.ResponseWriter, req *http.Request) {
+		if _, err := authtypes.ClaimsFromContext(req.Context()); err != nil {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		// the body is still readable after the checks
+		if _, err := io.ReadAll(req.Body); err != nil {
+			rw.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		rw.WriteHeader(http.StatusNoContent)
+	}
+	router.HandleFunc("/api/v1/rules", handler).Methods(http.MethodGet, http.MethodPost)
+	router.HandleFunc("/api/v1/rules/{id}", handler).Methods(http.MethodGet, http.MethodPut, http.MethodPatch, http.MethodDelete)
+	router.HandleFunc("/api/v1/dashboards", handler).Methods(http.MethodGet)
+
+	testCases := []struct {
+		name          string
+		method        string
+		path          string
+		body          string
+		remoteAddress string
+		status        int
+	}{
+		{name: "CreateMatchingRule", method: http.MethodPost, path: "/api/v1/rules", body: `{"labels":{"team":"payments","severity":"critical"}}`, status: http.StatusNoContent},
+		{name: "CreateOtherRule", method: http.MethodPost, path: "/api/v1/rules", body: `{"labels":{"team":"search"}}`, status: http.StatusForbidden},
+		{name: "EditMatchingRule", method: http.MethodPut, path: "/api/v1/rules/" + rules["payments"], body: `{"labels":{"team":"payments"}}`, status: http.StatusNoContent},
+		{name: "EditRuleOutOfLabels", method: http.MethodPut, path: "/api/v1/rules/" + rules["payments"], body: `{"labels":{"team":"search"}}`, status: http.StatusForbidden},
+		{name: "PatchWithoutLabels", method: http.MethodPatch, path: "/api/v1/rules/" + rules["payments"], body: `{"disabled":true}`, status: http.StatusNoContent},
+		{name: "GetMatchingRule", method: http.MethodGet, path: "/api/v1/rules/" + rules["payments"], status: http.StatusNoContent},
+		{name: "GetOtherRule", method: http.MethodGet, path: "/api/v1/rules/" + rules["search"], status: http.StatusForbidden},
+		{name: "ListRules", method: http.MethodGet, path: "/api/v1/rules", status: http.StatusForbidden},
+		{name: "DeleteOtherRule", method: http.MethodDelete, path: "/api/v1/rules/" + rules["search"], status: http.StatusForbidden},
+		{name: "OutOfScope", method: http.MethodGet, path: "/api/v1/dashboards", status: http.StatusForbidden},
+		{name: "DisallowedAddress", method: http.MethodPost, path: "/api/v1/rules", body: `{"labels":{"team":"payments"}}`, remoteAddress: "198.51.100.1:1234", status: http.StatusForbidden},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			req := httptest.NewRequest(testCase.method, testCase.path, strings.NewReader(testCase.body))
+			req.Header.Set("SIGNOZ-API-KEY", apiKey.Token)
+			req.RemoteAddr = "192.0.2.10:1234"
+			if testCase.remoteAddress != "" {
+				req.RemoteAddr = testCase.remoteAddress
+			}
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			assert.Equal(t, testCase.status, rec.Code)
+		})
+	}
+
+	storedAPIKey := new(types.StorableAPIKey)
+	require.NoError(t, sqlStore.BunDB().NewSelect().Model(storedAPIKey).Where("id = ?", apiKey.ID).Scan(ctx))
+	assert.Equal(t, int64(4), storedAPIKey.UsageCount)
+	assert.Equal(t, "192.0.2.10", storedAPIKey.LastUsedIP)
+	assert.Equal(t, apiKey.RuleLabels, storedAPIKey.RuleLabels)
+	assert.WithinDuration(t, time.Now(), storedAPIKey.LastUsed, time.Minute)
+}
+
+func TestAPIKeyOrg(t *testing.T) {
+	ctx := context.Background()
+	sqlStore := utils.NewQueryServiceDBForTests(t)
+	require.NoError(t, utils.CreateTestOrg(t, sqlStore))
+	homeOrgID, err := utils.GetTestOrgId(sqlStore)
+	require.NoError(t, err)
+	customerOrg := types.NewOrganization("customer")
+	_, err = sqlStore.BunDB().NewInsert().Model(customerOrg).Exec(ctx)
+	require.NoError(t, err)
+
+	user, err := types.NewUser("jane", "jane@example.com", types.RoleAdmin.String(), homeOrgID.StringValue())
+	require.NoError(t, err)
+	_, err = sqlStore.BunDB().NewInsert().Model(user).Exec(ctx)
+	require.NoError(t, err)
+	_, err = sqlStore.BunDB().NewInsert().Model(types.NewOrgMembership(user.ID.StringValue(), homeOrgID.StringValue(), types.RoleAdmin)).Exec(ctx)
+	require.NoError(t, err)
+	membership := types.NewOrgMembership(user.ID.StringValue(), customerOrg.ID.StringValue(), types.RoleViewer)
+	_, err = sqlStore.BunDB().NewInsert().Model(membership).Exec(ctx)
+	require.NoError(t, err)
+
+	apiKey, err := types.NewStorableAPIKey("customer", customerOrg.ID, user.ID, types.RoleAdmin, 0)
+	require.NoError(t, err)
+	_, err = sqlStore.BunDB().NewInsert().Model(apiKey).Exec(ctx)
+	require.NoError(t, err)
+
+	noop, err := noopsharder.New(ctx, factorytest.NewSettings(), sharder.Config{})
+	require.NoError(t, err)
+
+	var claims authtypes.Claims
+	handler := NewAPIKey(sqlStore, []string{"SIGNOZ-API-KEY"}, slog.New(slog.NewTextHandler(io.Discard, nil)), noop, NewClientAddress(nil)).Wrap(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		var err error
+		if claims, err = authtypes.ClaimsFromContext(req.Context()); err != nil {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		rw.WriteHeader(http.StatusNoContent)
+	}))
+
+	serve := func() int {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/dashboards", nil)
+		req.Header.Set("SIGNOZ-API-KEY", apiKey.Token)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// the key acts in its org with at most the role of its user there
+	require.Equal(t, http.StatusNoContent, serve())
+	assert.Equal(t, customerOrg.ID.StringValue(), claims.OrgID)
+	assert.Equal(t, types.RoleViewer, claims.Role)
+
+	_, err = sqlStore.BunDB().NewDelete().Model(membership).WherePK().Exec(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, serve())
+}
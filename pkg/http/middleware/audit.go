@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/SigNoz/signoz/pkg/types/audittypes"
+)
+
+type Audit struct {
+	clientAddress *ClientAddress
+}
+
+func NewAudit(clientAddress *ClientAddress) *Audit {
+	return &Audit{clientAddress: clientAddress}
+}
+
+// Wrap attaches the origin of the request to its context for the audit events of the changes it makes.
+func (a *Audit) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		origin := audittypes.Origin{
+			ClientAddress: a.clientAddress.Resolve(req),
+			UserAgent:     req.UserAgent(),
+		}
+
+		next.ServeHTTP(rw, req.WithContext(audittypes.NewContextWithOrigin(req.Context(), origin)))
+	})
+}
@@ -15,14 +15,15 @@ const (
 )
 
 type Auth struct {
-	jwt     *authtypes.JWT
-	headers []string
-	sharder sharder.Sharder
-	logger  *slog.Logger
+	jwt      *authtypes.JWT
+	headers  []string
+	sharder  sharder.Sharder
+	sessions authtypes.SessionValidator
+	logger   *slog.Logger
 }
 
-func NewAuth(jwt *authtypes.JWT, headers []string, sharder sharder.Sharder, logger *slog.Logger) *Auth {
-	return &Auth{jwt: jwt, headers: headers, sharder: sharder, logger: logger}
+func NewAuth(jwt *authtypes.JWT, headers []string, sharder sharder.Sharder, sessions authtypes.SessionValidator, logger *slog.Logger) *Auth {
+	return &Auth{jwt: jwt, headers: headers, sharder: sharder, sessions: sessions, logger: logger}
 }
 
 func (a *Auth) Wrap(next http.Handler) http.Handler {
@@ -50,6 +51,13 @@ func (a *Auth) Wrap(next http.Handler) http.Handler {
 			return
 		}
 
+		// the token is left out of the request when its session has been revoked
+		if err := a.sessions.ValidateSession(r.Context(), claims); err != nil {
+			a.logger.DebugContext(r.Context(), "rejected the token of an invalid session", "claims", claims, "error", err)
+			next.ServeHTTP(w, r)
+			return
+		}
+
 		r = r.WithContext(ctx)
 
 		next.ServeHTTP(w, r)
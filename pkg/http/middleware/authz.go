@@ -3,6 +3,7 @@ package middleware
 import (
 	"log/slog"
 	"net/http"
+	"slices"
 
 	"github.com/SigNoz/signoz/pkg/http/render"
 	"github.com/SigNoz/signoz/pkg/types/authtypes"
@@ -14,17 +15,26 @@ const (
 )
 
 type AuthZ struct {
-	logger *slog.Logger
+	logger           *slog.Logger
+	permissionGetter authtypes.PermissionGetter
 }
 
-func NewAuthZ(logger *slog.Logger) *AuthZ {
+func NewAuthZ(logger *slog.Logger, permissionGetter authtypes.PermissionGetter) *AuthZ {
 	if logger == nil {
 		panic("cannot build authz middleware, logger is empty")
 	}
 
-	return &AuthZ{logger: logger}
+	if permissionGetter == nil {
+		panic("cannot build authz middleware, permission getter is empty")
+	}
+
+	return &AuthZ{logger: logger, permissionGetter: permissionGetter}
 }
 
+// ViewAccess allows any member of the org. It is only meant for the routes every member needs
+// whatever their permissions (their own account, preferences and sessions, the features and the
+// license of the org) and for the routes whose handlers check the permissions on the resource
+// themselves (search, favorites and teams), every other route uses PermissionAccess.
 func (middleware *AuthZ) ViewAccess(next http.HandlerFunc) http.HandlerFunc {
 	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
 		claims, err := authtypes.ClaimsFromContext(req.Context())
@@ -43,7 +53,9 @@ func (middleware *AuthZ) ViewAccess(next http.HandlerFunc) http.HandlerFunc {
 	})
 }
 
-func (middleware *AuthZ) EditAccess(next http.HandlerFunc) http.HandlerFunc {
+// AdminAccess only allows the admins of the org, whatever the permissions granted to the others by
+// custom roles. It is meant for the routes that must not be delegated through a permission.
+func (middleware *AuthZ) AdminAccess(next http.HandlerFunc) http.HandlerFunc {
 	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
 		claims, err := authtypes.ClaimsFromContext(req.Context())
 		if err != nil {
@@ -51,7 +63,7 @@ func (middleware *AuthZ) EditAccess(next http.HandlerFunc) http.HandlerFunc {
 			return
 		}
 
-		if err := claims.IsEditor(); err != nil {
+		if err := claims.IsAdmin(); err != nil {
 			middleware.logger.WarnContext(req.Context(), authzDeniedMessage, "claims", claims)
 			render.Error(rw, err)
 			return
@@ -61,7 +73,9 @@ func (middleware *AuthZ) EditAccess(next http.HandlerFunc) http.HandlerFunc {
 	})
 }
 
-func (middleware *AuthZ) AdminAccess(next http.HandlerFunc) http.HandlerFunc {
+// PermissionAccess allows the request if the built-in role of the claims or one of the custom roles
+// assigned to the user (or to the api key) grants the permission.
+func (middleware *AuthZ) PermissionAccess(permission authtypes.Permission, next http.HandlerFunc) http.HandlerFunc {
 	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
 		claims, err := authtypes.ClaimsFromContext(req.Context())
 		if err != nil {
@@ -69,12 +83,24 @@ func (middleware *AuthZ) AdminAccess(next http.HandlerFunc) http.HandlerFunc {
 			return
 		}
 
-		if err := claims.IsAdmin(); err != nil {
-			middleware.logger.WarnContext(req.Context(), authzDeniedMessage, "claims", claims)
+		deniedErr := claims.HasPermission(permission)
+		if deniedErr == nil {
+			next(rw, req)
+			return
+		}
+
+		permissions, err := middleware.permissionGetter.GetPermissions(req.Context(), claims)
+		if err != nil {
 			render.Error(rw, err)
 			return
 		}
 
+		if !slices.Contains(permissions, permission) {
+			middleware.logger.WarnContext(req.Context(), authzDeniedMessage, "claims", claims, "permission", permission.StringValue())
+			render.Error(rw, deniedErr)
+			return
+		}
+
 		next(rw, req)
 	})
 }
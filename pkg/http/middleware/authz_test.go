@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/SigNoz/signoz/pkg/types"
+	"github.com/SigNoz/signoz/pkg/types/authtypes"
+	"github.com/stretchr/testify/assert"
+)
+
+type permissionGetter map[string][]authtypes.Permission
+
+func (getter permissionGetter) GetPermissions(_ context.Context, claims authtypes.Claims) ([]authtypes.Permission, error) {
+	return getter[claims.UserID], nil
+}
+
+func TestAuthZPermissionAccess(t *testing.T) {
+	m := NewAuthZ(slog.New(slog.NewTextHandler(io.Discard, nil)), permissionGetter{
+		"on-call": {authtypes.PermissionAlertsSilence},
+	})
+
+	testCases := []struct {
+		name       string
+		claims     authtypes.Claims
+		permission authtypes.Permission
+		status     int
+	}{
+		{name: "BuiltInRole", claims: authtypes.Claims{UserID: "editor", Role: types.RoleEditor}, permission: authtypes.PermissionAlertsSilence, status: http.StatusNoContent},
+		{name: "CustomRole", claims: authtypes.Claims{UserID: "on-call", Role: types.RoleViewer}, permission: authtypes.PermissionAlertsSilence, status: http.StatusNoContent},
+		{name: "CustomRoleOtherPermission", claims: authtypes.Claims{UserID: "on-call", Role: types.RoleViewer}, permission: authtypes.PermissionDashboardsDelete, status: http.StatusForbidden},
+		{name: "NoCustomRole", claims: authtypes.Claims{UserID: "viewer", Role: types.RoleViewer}, permission: authtypes.PermissionAlertsSilence, status: http.StatusForbidden},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := m.PermissionAccess(tc.permission, func(rw http.ResponseWriter, _ *http.Request) {
+				rw.WriteHeader(http.StatusNoContent)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			req = req.WithContext(authtypes.NewContextWithClaims(req.Context(), tc.claims))
+			rec := httptest.NewRecorder()
+			handler(rec, req)
+
+			assert.Equal(t, tc.status, rec.Code)
+		})
+	}
+}
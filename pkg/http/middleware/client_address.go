@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// ClientAddress resolves the address of the client sending a request. The X-Forwarded-For header can be set by
+// anyone, so it is only followed through the proxies that are trusted.
+type ClientAddress struct {
+	trustedProxies []netip.Prefix
+}
+
+func NewClientAddress(trustedProxies []netip.Prefix) *ClientAddress {
+	return &ClientAddress{trustedProxies: trustedProxies}
+}
+
+// Resolve returns the address of the peer of the request when it is not a trusted proxy. Otherwise it returns the
+// right most address of the X-Forwarded-For header that is not a trusted proxy, as the addresses left of it can be
+// forged by the client.
+func (c *ClientAddress) Resolve(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		host = req.RemoteAddr
+	}
+
+	if !c.isTrusted(host) {
+		return host
+	}
+
+	forwardedFor := strings.Split(strings.Join(req.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(forwardedFor) - 1; i >= 0; i-- {
+		address := strings.TrimSpace(forwardedFor[i])
+		if address == "" {
+			continue
+		}
+
+		if !c.isTrusted(address) {
+			return address
+		}
+
+		host = address
+	}
+
+	return host
+}
+
+func (c *ClientAddress) isTrusted(address string) bool {
+	addr, err := netip.ParseAddr(address)
+	if err != nil {
+		return false
+	}
+
+	addr = addr.Unmap()
+	for _, prefix := range c.trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
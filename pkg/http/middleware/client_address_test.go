@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientAddressResolve(t *testing.T) {
+	clientAddress := NewClientAddress([]netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")})
+
+	testCases := []struct {
+		name          string
+		remoteAddress string
+		forwardedFor  []string
+		expected      string
+	}{
+		{name: "Direct", remoteAddress: "198.51.100.1:1234", expected: "198.51.100.1"},
+		{name: "ForgedByUntrustedPeer", remoteAddress: "198.51.100.1:1234", forwardedFor: []string{"192.0.2.10"}, expected: "198.51.100.1"},
+		{name: "TrustedProxy", remoteAddress: "10.0.0.1:1234", forwardedFor: []string{"192.0.2.10"}, expected: "192.0.2.10"},
+		{name: "ForgedBehindTrustedProxy", remoteAddress: "10.0.0.1:1234", forwardedFor: []string{"192.0.2.10, 198.51.100.1"}, expected: "198.51.100.1"},
+		{name: "ChainOfTrustedProxies", remoteAddress: "10.0.0.1:1234", forwardedFor: []string{"192.0.2.10, 10.0.0.2", "10.0.0.3"}, expected: "192.0.2.10"},
+		{name: "TrustedProxyWithoutHeader", remoteAddress: "10.0.0.1:1234", expected: "10.0.0.1"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = testCase.remoteAddress
+			for _, value := range testCase.forwardedFor {
+				req.Header.Add("X-Forwarded-For", value)
+			}
+
+			assert.Equal(t, testCase.expected, clientAddress.Resolve(req))
+		})
+	}
+}
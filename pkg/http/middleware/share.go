@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/SigNoz/signoz/pkg/errors"
+	"github.com/SigNoz/signoz/pkg/http/render"
+	"github.com/SigNoz/signoz/pkg/types/sharetypes"
+)
+
+// Share authenticates the public routes of share links. The token of a link only grants access to the routes wrapped
+// with Access, they can only read the resource of the link and query its widgets.
+type Share struct {
+	authenticator sharetypes.Authenticator
+	headers       []string
+}
+
+func NewShare(authenticator sharetypes.Authenticator, headers []string) *Share {
+	return &Share{authenticator: authenticator, headers: headers}
+}
+
+func (s *Share) Access(next http.HandlerFunc) http.HandlerFunc {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		var token string
+		for _, header := range s.headers {
+			if token = req.Header.Get(header); token != "" {
+				break
+			}
+		}
+
+		if token == "" {
+			render.Error(rw, errors.New(errors.TypeUnauthenticated, errors.CodeUnauthenticated, "missing share token"))
+			return
+		}
+
+		link, err := s.authenticator.Authenticate(req.Context(), token)
+		if err != nil {
+			render.Error(rw, err)
+			return
+		}
+
+		next(rw, req.WithContext(sharetypes.NewContextWithShareLink(req.Context(), link)))
+	})
+}
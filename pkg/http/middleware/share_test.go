@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/SigNoz/signoz/pkg/errors"
+	"github.com/SigNoz/signoz/pkg/types/sharetypes"
+	"github.com/stretchr/testify/assert"
+)
+
+type shareAuthenticator map[string]*sharetypes.ShareLink
+
+func (authenticator shareAuthenticator) Authenticate(_ context.Context, token string) (*sharetypes.ShareLink, error) {
+	link, ok := authenticator[token]
+	if !ok {
+		return nil, errors.New(errors.TypeForbidden, sharetypes.ErrCodeShareLinkRevoked, "share link has been revoked")
+	}
+
+	return link, nil
+}
+
+func TestShareAccess(t *testing.T) {
+	link := &sharetypes.ShareLink{ResourceType: sharetypes.ResourceTypeDashboard, ResourceID: "dashboard"}
+	m := NewShare(shareAuthenticator{"active": link}, []string{sharetypes.HeaderToken})
+
+	testCases := []struct {
+		name   string
+		token  string
+		status int
+	}{
+		{name: "Active", token: "active", status: http.StatusNoContent},
+		{name: "Revoked", token: "revoked", status: http.StatusForbidden},
+		{name: "Missing", token: "", status: http.StatusUnauthorized},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := m.Access(func(rw http.ResponseWriter, req *http.Request) {
+				shared, err := sharetypes.ShareLinkFromContext(req.Context())
+				assert.NoError(t, err)
+				assert.Equal(t, link, shared)
+				rw.WriteHeader(http.StatusNoContent)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			if tc.token != "" {
+				req.Header.Set(sharetypes.HeaderToken, tc.token)
+			}
+			rec := httptest.NewRecorder()
+			handler(rec, req)
+
+			assert.Equal(t, tc.status, rec.Code)
+		})
+	}
+}
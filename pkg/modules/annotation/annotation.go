@@ -0,0 +1,47 @@
+package annotation
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/SigNoz/signoz/pkg/types/annotationtypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+)
+
+type Module interface {
+	// List lists the stored annotations matching the params along the ones derived from the telemetry, the annotations
+	// of the dashboards the user of the context can't view are left out
+	List(ctx context.Context, orgID valuer.UUID, params *annotationtypes.ListParams) ([]*annotationtypes.Annotation, error)
+
+	Create(ctx context.Context, orgID valuer.UUID, createdBy string, postable *annotationtypes.PostableAnnotation) (*annotationtypes.Annotation, error)
+
+	// CreateDeploy creates the annotation of the deploy of a version of a service
+	CreateDeploy(ctx context.Context, orgID valuer.UUID, createdBy string, deploy *annotationtypes.PostableDeploy) (*annotationtypes.Annotation, error)
+
+	// Get gets the annotation, the annotations of the dashboards the user of the context can't view are not found
+	Get(ctx context.Context, orgID valuer.UUID, id valuer.UUID) (*annotationtypes.Annotation, error)
+
+	Update(ctx context.Context, orgID valuer.UUID, id valuer.UUID, updatable *annotationtypes.UpdatableAnnotation) (*annotationtypes.Annotation, error)
+
+	Delete(ctx context.Context, orgID valuer.UUID, id valuer.UUID) error
+}
+
+type Handler interface {
+	// Lists the annotations
+	List(http.ResponseWriter, *http.Request)
+
+	// Creates the annotation
+	Create(http.ResponseWriter, *http.Request)
+
+	// Creates the annotation of a deploy, for CI pipelines
+	CreateDeploy(http.ResponseWriter, *http.Request)
+
+	// Gets the annotation
+	Get(http.ResponseWriter, *http.Request)
+
+	// Updates the annotation
+	Update(http.ResponseWriter, *http.Request)
+
+	// Deletes the annotation
+	Delete(http.ResponseWriter, *http.Request)
+}
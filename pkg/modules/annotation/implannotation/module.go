@@ -0,0 +1,206 @@
+package implannotation
+
+import (
+	"context"
+	"slices"
+
+	"github.com/SigNoz/signoz/pkg/errors"
+	"github.com/SigNoz/signoz/pkg/modules/annotation"
+	"github.com/SigNoz/signoz/pkg/modules/audit"
+	"github.com/SigNoz/signoz/pkg/modules/dashboard"
+	"github.com/SigNoz/signoz/pkg/telemetrystore"
+	"github.com/SigNoz/signoz/pkg/types/annotationtypes"
+	"github.com/SigNoz/signoz/pkg/types/audittypes"
+	"github.com/SigNoz/signoz/pkg/types/ruletypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+)
+
+type module struct {
+	store     annotationtypes.Store
+	telemetry *telemetry
+	dashboard dashboard.Module
+	audit     audit.Module
+}
+
+func NewModule(store annotationtypes.Store, telemetryStore telemetrystore.TelemetryStore, ruleStore ruletypes.RuleStore, dashboard dashboard.Module, audit audit.Module) annotation.Module {
+	return &module{
+		store:     store,
+		telemetry: &telemetry{telemetryStore: telemetryStore, ruleStore: ruleStore},
+		dashboard: dashboard,
+		audit:     audit,
+	}
+}
+
+func (module *module) List(ctx context.Context, orgID valuer.UUID, params *annotationtypes.ListParams) ([]*annotationtypes.Annotation, error) {
+	if err := module.checkDashboard(ctx, orgID, params.DashboardID); err != nil {
+		return nil, err
+	}
+
+	storedAnnotations, err := module.store.List(ctx, orgID, params.Start, params.End)
+	if err != nil {
+		return nil, err
+	}
+
+	derivedAnnotations, err := module.listDerived(ctx, orgID, params)
+	if err != nil {
+		return nil, err
+	}
+
+	// the annotations of the dashboards hidden from the user are left out
+	visible := map[string]bool{params.DashboardID: true}
+	annotations := make([]*annotationtypes.Annotation, 0, len(storedAnnotations)+len(derivedAnnotations))
+	for _, annotation := range slices.Concat(storedAnnotations, derivedAnnotations) {
+		if !params.Matches(annotation) {
+			continue
+		}
+
+		if _, ok := visible[annotation.DashboardID]; !ok {
+			err := module.checkDashboard(ctx, orgID, annotation.DashboardID)
+			if err != nil && !errors.Ast(err, errors.TypeNotFound) {
+				return nil, err
+			}
+
+			visible[annotation.DashboardID] = err == nil
+		}
+
+		if visible[annotation.DashboardID] {
+			annotations = append(annotations, annotation)
+		}
+	}
+
+	annotationtypes.Sort(annotations)
+	return annotations, nil
+}
+
+func (module *module) Create(ctx context.Context, orgID valuer.UUID, createdBy string, postable *annotationtypes.PostableAnnotation) (*annotationtypes.Annotation, error) {
+	return module.create(ctx, orgID, createdBy, annotationtypes.SourceUser, postable)
+}
+
+func (module *module) CreateDeploy(ctx context.Context, orgID valuer.UUID, createdBy string, deploy *annotationtypes.PostableDeploy) (*annotationtypes.Annotation, error) {
+	postable, err := annotationtypes.NewPostableAnnotationFromDeploy(deploy)
+	if err != nil {
+		return nil, err
+	}
+
+	return module.create(ctx, orgID, createdBy, annotationtypes.SourceDeploy, postable)
+}
+
+func (module *module) Get(ctx context.Context, orgID valuer.UUID, id valuer.UUID) (*annotationtypes.Annotation, error) {
+	return module.getAnnotation(ctx, orgID, id)
+}
+
+func (module *module) Update(ctx context.Context, orgID valuer.UUID, id valuer.UUID, updatable *annotationtypes.UpdatableAnnotation) (*annotationtypes.Annotation, error) {
+	annotation, err := module.getAnnotation(ctx, orgID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	before := *annotation
+	if err := annotation.Update(updatable); err != nil {
+		return nil, err
+	}
+
+	if err := module.checkDashboard(ctx, orgID, annotation.DashboardID); err != nil {
+		return nil, err
+	}
+
+	if err := module.store.Update(ctx, annotation); err != nil {
+		return nil, err
+	}
+
+	module.audit.Record(ctx, orgID, audittypes.ActionUpdate, audittypes.NewResource(audittypes.ResourceTypeAnnotation, annotation.ID.StringValue()), &before, annotation)
+	return annotation, nil
+}
+
+func (module *module) Delete(ctx context.Context, orgID valuer.UUID, id valuer.UUID) error {
+	annotation, err := module.getAnnotation(ctx, orgID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := module.store.Delete(ctx, orgID, id); err != nil {
+		return err
+	}
+
+	module.audit.Record(ctx, orgID, audittypes.ActionDelete, audittypes.NewResource(audittypes.ResourceTypeAnnotation, annotation.ID.StringValue()), annotation, nil)
+	return nil
+}
+
+func (module *module) create(ctx context.Context, orgID valuer.UUID, createdBy string, source annotationtypes.Source, postable *annotationtypes.PostableAnnotation) (*annotationtypes.Annotation, error) {
+	annotation, err := annotationtypes.NewAnnotation(orgID, createdBy, source, postable)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := module.checkDashboard(ctx, orgID, annotation.DashboardID); err != nil {
+		return nil, err
+	}
+
+	if err := module.store.Create(ctx, annotation); err != nil {
+		return nil, err
+	}
+
+	module.audit.Record(ctx, orgID, audittypes.ActionCreate, audittypes.NewResource(audittypes.ResourceTypeAnnotation, annotation.ID.StringValue()), nil, annotation)
+	return annotation, nil
+}
+
+// listDerived lists the annotations derived from the telemetry for the sources of the params.
+func (module *module) listDerived(ctx context.Context, orgID valuer.UUID, params *annotationtypes.ListParams) ([]*annotationtypes.Annotation, error) {
+	annotations := make([]*annotationtypes.Annotation, 0)
+	if len(params.Derived) == 0 {
+		return annotations, nil
+	}
+
+	if module.telemetry.telemetryStore == nil {
+		return nil, errors.New(errors.TypeUnsupported, errors.CodeUnsupported, "annotations cannot be derived without a telemetry store")
+	}
+
+	if slices.Contains(params.Derived, annotationtypes.SourceAlert) {
+		transitions, err := module.telemetry.listAlertTransitions(ctx, orgID, params)
+		if err != nil {
+			return nil, err
+		}
+
+		annotations = append(annotations, annotationtypes.NewAlertAnnotations(orgID, transitions)...)
+	}
+
+	if slices.Contains(params.Derived, annotationtypes.SourceVersion) {
+		versions, err := module.telemetry.listServiceVersions(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+
+		annotations = append(annotations, annotationtypes.NewVersionAnnotations(orgID, versions, params.Start)...)
+	}
+
+	return annotations, nil
+}
+
+// getAnnotation gets the annotation once the user of the context can access the dashboard it is scoped to, the
+// annotations of the dashboards hidden from the user don't exist for them.
+func (module *module) getAnnotation(ctx context.Context, orgID valuer.UUID, id valuer.UUID) (*annotationtypes.Annotation, error) {
+	annotation, err := module.store.Get(ctx, orgID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := module.checkDashboard(ctx, orgID, annotation.DashboardID); err != nil {
+		if errors.Ast(err, errors.TypeNotFound) {
+			return nil, errors.Newf(errors.TypeNotFound, annotationtypes.ErrCodeAnnotationNotFound, "annotation with id %s doesn't exist", id.StringValue())
+		}
+
+		return nil, err
+	}
+
+	return annotation, nil
+}
+
+// checkDashboard checks that the dashboard the annotations are scoped to exists and can be accessed.
+func (module *module) checkDashboard(ctx context.Context, orgID valuer.UUID, dashboardID string) error {
+	if dashboardID == "" {
+		return nil
+	}
+
+	_, err := module.dashboard.Get(ctx, orgID, valuer.MustNewUUID(dashboardID))
+	return err
+}
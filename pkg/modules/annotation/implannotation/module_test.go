@@ -0,0 +1,132 @@
+package implannotation
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/SigNoz/signoz/pkg/analytics/analyticstest"
+	"github.com/SigNoz/signoz/pkg/errors"
+	"github.com/SigNoz/signoz/pkg/factory/factorytest"
+	"github.com/SigNoz/signoz/pkg/modules/audit/implaudit"
+	"github.com/SigNoz/signoz/pkg/modules/dashboard/impldashboard"
+	"github.com/SigNoz/signoz/pkg/query-service/utils"
+	"github.com/SigNoz/signoz/pkg/ruler/rulestore/sqlrulestore"
+	"github.com/SigNoz/signoz/pkg/telemetrystore"
+	"github.com/SigNoz/signoz/pkg/telemetrystore/telemetrystoretest"
+	"github.com/SigNoz/signoz/pkg/types"
+	"github.com/SigNoz/signoz/pkg/types/annotationtypes"
+	"github.com/SigNoz/signoz/pkg/types/authtypes"
+	"github.com/SigNoz/signoz/pkg/types/dashboardtypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+	cmock "github.com/srikanthccv/ClickHouse-go-mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModule(t *testing.T) {
+	sqlStore := utils.NewQueryServiceDBForTests(t)
+	require.NoError(t, utils.CreateTestOrg(t, sqlStore))
+	orgID, err := utils.GetTestOrgId(sqlStore)
+	require.NoError(t, err)
+
+	userID := valuer.GenerateUUID()
+	ctx := authtypes.NewContextWithClaims(context.Background(), authtypes.Claims{UserID: userID.StringValue(), Email: "editor@example.com", OrgID: orgID.StringValue(), Role: types.RoleEditor})
+
+	providerSettings := factorytest.NewSettings()
+	audit := implaudit.NewModule(implaudit.NewStore(sqlStore), providerSettings)
+	dashboard := impldashboard.NewModule(sqlStore, providerSettings, analyticstest.New(), audit)
+	telemetryStore := telemetrystoretest.New(telemetrystore.Config{Provider: "clickhouse"}, sqlmock.QueryMatcherRegexp)
+	module := NewModule(NewStore(sqlStore), telemetryStore, sqlrulestore.NewRuleStore(sqlStore), dashboard, audit)
+
+	data := dashboardtypes.PostableDashboard{}
+	require.NoError(t, json.Unmarshal([]byte(`{"title": "checkout", "widgets": []}`), &data))
+	created, err := dashboard.Create(ctx, orgID, "editor@example.com", userID, data)
+	require.NoError(t, err)
+
+	now := time.Now().Truncate(time.Millisecond)
+	_, err = module.Create(ctx, orgID, "editor@example.com", &annotationtypes.PostableAnnotation{StartTime: now.Add(-2 * time.Hour), Text: "incident", Tags: []string{"sev1"}})
+	require.NoError(t, err)
+	scoped, err := module.Create(ctx, orgID, "editor@example.com", &annotationtypes.PostableAnnotation{DashboardID: created.ID, StartTime: now.Add(-time.Hour), Text: "load test"})
+	require.NoError(t, err)
+	deploy, err := module.CreateDeploy(ctx, orgID, "ci@example.com", &annotationtypes.PostableDeploy{Service: "checkout", Environment: "prod", Version: "1.4.2"})
+	require.NoError(t, err)
+	assert.Equal(t, annotationtypes.SourceDeploy, deploy.Source)
+
+	_, err = module.Create(ctx, orgID, "editor@example.com", &annotationtypes.PostableAnnotation{DashboardID: valuer.GenerateUUID().StringValue(), StartTime: now, Text: "missing"})
+	assert.True(t, errors.Ast(err, errors.TypeNotFound))
+
+	for _, tc := range []struct {
+		name        string
+		params      *annotationtypes.ListParams
+		annotations int
+	}{
+		{name: "All", params: &annotationtypes.ListParams{Start: now.Add(-3 * time.Hour), End: now.Add(time.Minute)}, annotations: 3},
+		{name: "TimeRange", params: &annotationtypes.ListParams{Start: now.Add(-90 * time.Minute), End: now.Add(time.Minute)}, annotations: 2},
+		{name: "OtherService", params: &annotationtypes.ListParams{Start: now.Add(-3 * time.Hour), End: now.Add(time.Minute), Service: "cart"}, annotations: 2},
+		{name: "Tags", params: &annotationtypes.ListParams{Start: now.Add(-3 * time.Hour), End: now.Add(time.Minute), Tags: []string{"deploy"}}, annotations: 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			annotations, err := module.List(ctx, orgID, tc.params)
+			require.NoError(t, err)
+			assert.Len(t, annotations, tc.annotations)
+		})
+	}
+
+	// the annotations of a dashboard are only listed on it
+	otherDashboard := dashboardtypes.PostableDashboard{}
+	require.NoError(t, json.Unmarshal([]byte(`{"title": "cart", "widgets": []}`), &otherDashboard))
+	other, err := dashboard.Create(ctx, orgID, "editor@example.com", userID, otherDashboard)
+	require.NoError(t, err)
+	annotations, err := module.List(ctx, orgID, &annotationtypes.ListParams{Start: now.Add(-3 * time.Hour), End: now.Add(time.Minute), DashboardID: other.ID})
+	require.NoError(t, err)
+	assert.Len(t, annotations, 2)
+	_, err = module.List(ctx, orgID, &annotationtypes.ListParams{Start: now.Add(-3 * time.Hour), End: now.Add(time.Minute), DashboardID: valuer.GenerateUUID().StringValue()})
+	assert.True(t, errors.Ast(err, errors.TypeNotFound))
+
+	end := now.Add(-30 * time.Minute)
+	updated, err := module.Update(ctx, orgID, scoped.ID, &annotationtypes.UpdatableAnnotation{DashboardID: created.ID, StartTime: now.Add(-time.Hour), EndTime: &end, Text: "load test"})
+	require.NoError(t, err)
+	require.NotNil(t, updated.EndTime)
+
+	// the annotations of the dashboards restricted to others don't exist for them
+	_, err = dashboard.SetGrants(ctx, orgID, valuer.MustNewUUID(created.ID), &dashboardtypes.PostableGrants{Grants: []*dashboardtypes.PostableGrant{
+		{SubjectType: dashboardtypes.SubjectTypeUser, SubjectID: userID, Permission: dashboardtypes.PermissionViewer},
+	}})
+	require.NoError(t, err)
+	john := authtypes.NewContextWithClaims(context.Background(), authtypes.Claims{UserID: valuer.GenerateUUID().StringValue(), Email: "john@example.com", OrgID: orgID.StringValue(), Role: types.RoleEditor})
+	_, err = module.Get(john, orgID, scoped.ID)
+	assert.True(t, errors.Ast(err, errors.TypeNotFound))
+	err = module.Delete(john, orgID, scoped.ID)
+	assert.True(t, errors.Ast(err, errors.TypeNotFound))
+	annotations, err = module.List(john, orgID, &annotationtypes.ListParams{Start: now.Add(-3 * time.Hour), End: now.Add(time.Minute)})
+	require.NoError(t, err)
+	assert.Len(t, annotations, 2)
+
+	require.NoError(t, module.Delete(ctx, orgID, scoped.ID))
+	_, err = module.Get(ctx, orgID, scoped.ID)
+	assert.True(t, errors.Ast(err, errors.TypeNotFound))
+
+	// the changes of versions are derived from the resources of the spans
+	telemetryStore.Mock().ExpectSelect("signoz_traces.distributed_signoz_index_v3").WillReturnRows(cmock.NewRows(
+		[]cmock.ColumnType{
+			{Name: "service", Type: "String"},
+			{Name: "environment", Type: "String"},
+			{Name: "version", Type: "String"},
+			{Name: "first_seen", Type: "DateTime64(9)"},
+		},
+		[][]any{
+			{"checkout", "prod", "1.4.1", now.Add(-2 * time.Hour)},
+			{"checkout", "prod", "1.4.2", now.Add(-10 * time.Minute)},
+		},
+	))
+
+	annotations, err = module.List(ctx, orgID, &annotationtypes.ListParams{Start: now.Add(-3 * time.Hour), End: now.Add(time.Minute), Service: "checkout", Derived: []annotationtypes.Source{annotationtypes.SourceVersion}})
+	require.NoError(t, err)
+	require.Len(t, annotations, 3)
+	assert.Equal(t, annotationtypes.SourceVersion, annotations[1].Source)
+	assert.Equal(t, "checkout changed from 1.4.1 to 1.4.2", annotations[1].Text)
+	assert.NoError(t, telemetryStore.Mock().ExpectationsWereMet())
+}
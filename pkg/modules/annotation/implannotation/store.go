@@ -0,0 +1,99 @@
+package implannotation
+
+import (
+	"context"
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/sqlstore"
+	"github.com/SigNoz/signoz/pkg/types/annotationtypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+)
+
+type store struct {
+	sqlstore sqlstore.SQLStore
+}
+
+func NewStore(sqlstore sqlstore.SQLStore) annotationtypes.Store {
+	return &store{sqlstore: sqlstore}
+}
+
+func (store *store) Create(ctx context.Context, annotation *annotationtypes.Annotation) error {
+	_, err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewInsert().
+		Model(annotation).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (store *store) Get(ctx context.Context, orgID valuer.UUID, id valuer.UUID) (*annotationtypes.Annotation, error) {
+	annotation := new(annotationtypes.Annotation)
+	err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewSelect().
+		Model(annotation).
+		Where("org_id = ?", orgID).
+		Where("id = ?", id).
+		Scan(ctx)
+	if err != nil {
+		return nil, store.sqlstore.WrapNotFoundErrf(err, annotationtypes.ErrCodeAnnotationNotFound, "annotation with id %s doesn't exist", id.StringValue())
+	}
+
+	return annotation, nil
+}
+
+func (store *store) Update(ctx context.Context, annotation *annotationtypes.Annotation) error {
+	_, err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewUpdate().
+		Model(annotation).
+		WherePK().
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (store *store) Delete(ctx context.Context, orgID valuer.UUID, id valuer.UUID) error {
+	_, err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewDelete().
+		Model(new(annotationtypes.Annotation)).
+		Where("org_id = ?", orgID).
+		Where("id = ?", id).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (store *store) List(ctx context.Context, orgID valuer.UUID, start time.Time, end time.Time) ([]*annotationtypes.Annotation, error) {
+	annotations := make([]*annotationtypes.Annotation, 0)
+	err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewSelect().
+		Model(&annotations).
+		Where("org_id = ?", orgID).
+		Where("start_time <= ?", end).
+		Where("COALESCE(end_time, start_time) >= ?", start).
+		Order("start_time ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return annotations, nil
+}
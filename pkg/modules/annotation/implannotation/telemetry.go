@@ -0,0 +1,108 @@
+package implannotation
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/telemetrystore"
+	"github.com/SigNoz/signoz/pkg/telemetrytraces"
+	"github.com/SigNoz/signoz/pkg/types/annotationtypes"
+	"github.com/SigNoz/signoz/pkg/types/ruletypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+)
+
+const (
+	ruleStateHistoryTable = "signoz_analytics.distributed_rule_state_history_v0"
+	// versionLookback is how long before the time range the versions are looked up, the versions seen then were
+	// already running at its start
+	versionLookback = 24 * time.Hour
+)
+
+// telemetry reads the events the annotations are derived from.
+type telemetry struct {
+	telemetryStore telemetrystore.TelemetryStore
+	ruleStore      ruletypes.RuleStore
+}
+
+// listAlertTransitions lists the changes of the overall states of the alert rules of the org in the time range.
+func (telemetry *telemetry) listAlertTransitions(ctx context.Context, orgID valuer.UUID, params *annotationtypes.ListParams) ([]annotationtypes.AlertTransition, error) {
+	rules, err := telemetry.ruleStore.GetStoredRules(ctx, orgID.StringValue())
+	if err != nil {
+		return nil, err
+	}
+
+	transitions := make([]annotationtypes.AlertTransition, 0)
+	if len(rules) == 0 {
+		return transitions, nil
+	}
+
+	ruleIDs := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		ruleIDs = append(ruleIDs, rule.ID.StringValue())
+	}
+
+	// every series of the rule is written on a change of the overall state, the service and the environment are the
+	// ones of any of them
+	query := `SELECT
+		rule_id,
+		any(rule_name) AS rule_name,
+		overall_state,
+		unix_milli,
+		any(JSONExtractString(labels, 'service.name')) AS service,
+		any(JSONExtractString(labels, 'deployment.environment')) AS environment
+	FROM ` + ruleStateHistoryTable + `
+	WHERE has(?, rule_id)
+		AND overall_state_changed = true
+		AND unix_milli >= ? AND unix_milli <= ?
+	GROUP BY rule_id, overall_state, unix_milli
+	ORDER BY unix_milli ASC
+	LIMIT ?`
+
+	err = telemetry.telemetryStore.ClickhouseDB().Select(ctx, &transitions, query, ruleIDs, params.Start.UnixMilli(), params.End.UnixMilli(), annotationtypes.MaxDerived)
+	if err != nil {
+		return nil, err
+	}
+
+	return transitions, nil
+}
+
+// listServiceVersions lists the versions of the services seen in the resources of the spans in the time range and in
+// the lookback before it, along with the time each of them was first seen.
+func (telemetry *telemetry) listServiceVersions(ctx context.Context, params *annotationtypes.ListParams) ([]annotationtypes.ServiceVersion, error) {
+	query := fmt.Sprintf(`SELECT
+		resource_string_service$$name AS service,
+		resources_string['deployment.environment'] AS environment,
+		resources_string['service.version'] AS version,
+		min(timestamp) AS first_seen
+	FROM %s.%s
+	WHERE timestamp >= ? AND timestamp <= ?
+		AND ts_bucket_start >= ? AND ts_bucket_start <= ?
+		AND mapContains(resources_string, 'service.version')`, telemetrytraces.DBName, telemetrytraces.SpanIndexV3TableName)
+
+	since := params.Start.Add(-versionLookback)
+	args := []any{
+		strconv.FormatInt(since.UnixNano(), 10),
+		strconv.FormatInt(params.End.UnixNano(), 10),
+		strconv.FormatInt(since.Unix()-1800, 10),
+		strconv.FormatInt(params.End.Unix(), 10),
+	}
+	if params.Service != "" {
+		query += ` AND resource_string_service$$name = ?`
+		args = append(args, params.Service)
+	}
+
+	query += `
+	GROUP BY service, environment, version
+	ORDER BY first_seen ASC
+	LIMIT ?`
+	args = append(args, annotationtypes.MaxDerived)
+
+	versions := make([]annotationtypes.ServiceVersion, 0)
+	if err := telemetry.telemetryStore.ClickhouseDB().Select(ctx, &versions, query, args...); err != nil {
+		return nil, err
+	}
+
+	return versions, nil
+}
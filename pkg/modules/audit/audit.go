@@ -0,0 +1,24 @@
+package audit
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/SigNoz/signoz/pkg/types/audittypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+)
+
+type Module interface {
+	// Record appends an event for the change made by the actor of the context. Failures are logged, they don't fail the change.
+	Record(ctx context.Context, orgID valuer.UUID, action audittypes.Action, resource audittypes.Resource, before any, after any)
+
+	// List lists the events of the org matching the params, the most recent first
+	List(ctx context.Context, orgID valuer.UUID, params *audittypes.ListParams) (*audittypes.GettableEvents, error)
+}
+
+type Handler interface {
+	List(http.ResponseWriter, *http.Request)
+
+	// Export streams all the events matching the filters as csv or json
+	Export(http.ResponseWriter, *http.Request)
+}
@@ -0,0 +1,135 @@
+package implaudit
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/errors"
+	"github.com/SigNoz/signoz/pkg/http/render"
+	"github.com/SigNoz/signoz/pkg/modules/audit"
+	"github.com/SigNoz/signoz/pkg/types/audittypes"
+	"github.com/SigNoz/signoz/pkg/types/authtypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+)
+
+type handler struct {
+	module audit.Module
+}
+
+func NewHandler(module audit.Module) audit.Handler {
+	return &handler{module: module}
+}
+
+func (handler *handler) List(rw http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	claims, err := authtypes.ClaimsFromContext(ctx)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	params, err := audittypes.NewListParams(r)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	events, err := handler.module.List(ctx, valuer.MustNewUUID(claims.OrgID), params)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	render.Success(rw, http.StatusOK, events)
+}
+
+func (handler *handler) Export(rw http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), time.Minute)
+	defer cancel()
+
+	claims, err := authtypes.ClaimsFromContext(ctx)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	params, err := audittypes.NewListParams(r)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "csv"
+	}
+
+	var write func(*audittypes.GettableEvent) error
+	var flush func() error
+	switch format {
+	case "csv":
+		writer := csv.NewWriter(rw)
+		write = func(event *audittypes.GettableEvent) error {
+			record, err := event.CSVRecord()
+			if err != nil {
+				return err
+			}
+			return writer.Write(record)
+		}
+		flush = func() error {
+			writer.Flush()
+			return writer.Error()
+		}
+
+		rw.Header().Set("Content-Type", "text/csv")
+		rw.Header().Set("Content-Disposition", `attachment; filename="audit.csv"`)
+		rw.WriteHeader(http.StatusOK)
+		if err := writer.Write(audittypes.CSVHeader); err != nil {
+			return
+		}
+	case "json":
+		encoder := json.NewEncoder(rw)
+		write = func(event *audittypes.GettableEvent) error { return encoder.Encode(event) }
+		flush = func() error { return nil }
+
+		rw.Header().Set("Content-Type", "application/x-ndjson")
+		rw.Header().Set("Content-Disposition", `attachment; filename="audit.ndjson"`)
+		rw.WriteHeader(http.StatusOK)
+	default:
+		render.Error(rw, errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "invalid format: %s, must be one of csv, json", format))
+		return
+	}
+
+	// the response has started, errors can only cut the export short. Events recorded during the export would shift
+	// the pages, the export stops at the time it started.
+	if params.End.IsZero() {
+		params.End = time.Now()
+	}
+	params.Limit = audittypes.MaxLimit
+	for {
+		events, err := handler.module.List(ctx, valuer.MustNewUUID(claims.OrgID), params)
+		if err != nil {
+			return
+		}
+
+		for _, event := range events.Events {
+			if err := write(event); err != nil {
+				return
+			}
+		}
+
+		if err := flush(); err != nil {
+			return
+		}
+
+		params.Offset += len(events.Events)
+		if len(events.Events) < params.Limit || params.Offset >= events.Total {
+			return
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package implaudit
+
+import (
+	"context"
+
+	"github.com/SigNoz/signoz/pkg/factory"
+	"github.com/SigNoz/signoz/pkg/modules/audit"
+	"github.com/SigNoz/signoz/pkg/types/audittypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+)
+
+type module struct {
+	store    audittypes.Store
+	settings factory.ScopedProviderSettings
+}
+
+func NewModule(store audittypes.Store, providerSettings factory.ProviderSettings) audit.Module {
+	settings := factory.NewScopedProviderSettings(providerSettings, "github.com/SigNoz/signoz/pkg/modules/audit/implaudit")
+	return &module{store: store, settings: settings}
+}
+
+func (module *module) Record(ctx context.Context, orgID valuer.UUID, action audittypes.Action, resource audittypes.Resource, before any, after any) {
+	event, err := audittypes.NewEvent(ctx, orgID, action, resource, before, after)
+	if err == nil {
+		// the change is done at this point, the event is written even if the request got cancelled
+		err = module.store.Create(context.WithoutCancel(ctx), event)
+	}
+
+	if err != nil {
+		module.settings.Logger().ErrorContext(ctx, "failed to record audit event", "org_id", orgID, "action", action, "resource_type", resource.Type, "resource_id", resource.ID, "error", err)
+	}
+}
+
+func (module *module) List(ctx context.Context, orgID valuer.UUID, params *audittypes.ListParams) (*audittypes.GettableEvents, error) {
+	storableEvents, total, err := module.store.List(ctx, orgID, params)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]*audittypes.GettableEvent, len(storableEvents))
+	for i, storableEvent := range storableEvents {
+		events[i] = audittypes.NewGettableEvent(storableEvent)
+	}
+
+	return &audittypes.GettableEvents{Events: events, Total: total}, nil
+}
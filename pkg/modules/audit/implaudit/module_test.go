@@ -0,0 +1,52 @@
+package implaudit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/factory/factorytest"
+	"github.com/SigNoz/signoz/pkg/query-service/utils"
+	"github.com/SigNoz/signoz/pkg/types/audittypes"
+	"github.com/SigNoz/signoz/pkg/types/authtypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModule(t *testing.T) {
+	sqlStore := utils.NewQueryServiceDBForTests(t)
+	require.NoError(t, utils.CreateTestOrg(t, sqlStore))
+	orgID, err := utils.GetTestOrgId(sqlStore)
+	require.NoError(t, err)
+
+	module := NewModule(NewStore(sqlStore), factorytest.NewSettings())
+	ctx := authtypes.NewContextWithClaims(context.Background(), authtypes.Claims{UserID: "user", Email: "jane@example.com", OrgID: orgID.StringValue()})
+
+	dashboard := audittypes.NewResource(audittypes.ResourceTypeDashboard, "dashboard")
+	module.Record(ctx, orgID, audittypes.ActionCreate, dashboard, nil, map[string]any{"title": "hosts"})
+	module.Record(ctx, orgID, audittypes.ActionUpdate, dashboard, map[string]any{"title": "hosts"}, map[string]any{"title": "nodes"})
+	module.Record(context.Background(), orgID, audittypes.ActionDelete, audittypes.NewResource(audittypes.ResourceTypeRule, "rule"), map[string]any{"alert": "cpu"}, nil)
+
+	events, err := module.List(ctx, orgID, &audittypes.ListParams{Limit: audittypes.DefaultLimit})
+	require.NoError(t, err)
+	require.Equal(t, 3, events.Total)
+	// most recent first
+	assert.Equal(t, audittypes.ActionDelete, events.Events[0].Action)
+	assert.Equal(t, audittypes.ActorTypeSystem, events.Events[0].ActorType)
+
+	events, err = module.List(ctx, orgID, &audittypes.ListParams{ResourceType: "dashboard", Action: "update", Limit: audittypes.DefaultLimit})
+	require.NoError(t, err)
+	require.Len(t, events.Events, 1)
+	assert.Equal(t, "user", events.Events[0].ActorID)
+	assert.Equal(t, []*audittypes.Change{{Path: "title", Before: "hosts", After: "nodes"}}, events.Events[0].Changes)
+
+	events, err = module.List(ctx, orgID, &audittypes.ListParams{ActorID: "user", Limit: 1, Offset: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 2, events.Total)
+	require.Len(t, events.Events, 1)
+	assert.Equal(t, audittypes.ActionCreate, events.Events[0].Action)
+
+	events, err = module.List(ctx, orgID, &audittypes.ListParams{Start: time.Now().Add(time.Hour), Limit: audittypes.DefaultLimit})
+	require.NoError(t, err)
+	assert.Empty(t, events.Events)
+}
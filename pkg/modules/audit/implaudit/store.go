@@ -0,0 +1,76 @@
+package implaudit
+
+import (
+	"context"
+
+	"github.com/SigNoz/signoz/pkg/sqlstore"
+	"github.com/SigNoz/signoz/pkg/types/audittypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+)
+
+type store struct {
+	sqlstore sqlstore.SQLStore
+}
+
+func NewStore(sqlstore sqlstore.SQLStore) audittypes.Store {
+	return &store{sqlstore: sqlstore}
+}
+
+func (store *store) Create(ctx context.Context, event *audittypes.StorableEvent) error {
+	_, err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewInsert().
+		Model(event).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (store *store) List(ctx context.Context, orgID valuer.UUID, params *audittypes.ListParams) ([]*audittypes.StorableEvent, int, error) {
+	events := make([]*audittypes.StorableEvent, 0)
+	query := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewSelect().
+		Model(&events).
+		Where("org_id = ?", orgID)
+
+	if params.ActorID != "" {
+		query = query.Where("actor_id = ?", params.ActorID)
+	}
+
+	if params.Action != "" {
+		query = query.Where("action = ?", params.Action)
+	}
+
+	if params.ResourceType != "" {
+		query = query.Where("resource_type = ?", params.ResourceType)
+	}
+
+	if params.ResourceID != "" {
+		query = query.Where("resource_id = ?", params.ResourceID)
+	}
+
+	if !params.Start.IsZero() {
+		query = query.Where("timestamp >= ?", params.Start)
+	}
+
+	if !params.End.IsZero() {
+		query = query.Where("timestamp <= ?", params.End)
+	}
+
+	total, err := query.
+		Order("timestamp DESC").
+		Limit(params.Limit).
+		Offset(params.Offset).
+		ScanAndCount(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return events, total, nil
+}
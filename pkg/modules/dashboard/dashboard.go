@@ -12,16 +12,67 @@ import (
 type Module interface {
 	Create(ctx context.Context, orgID valuer.UUID, createdBy string, creator valuer.UUID, data dashboardtypes.PostableDashboard) (*dashboardtypes.Dashboard, error)
 
+	// ImportGrafana creates a dashboard translated from the grafana dashboard, the panels that cannot be translated are
+	// reported instead of failing the import
+	ImportGrafana(ctx context.Context, orgID valuer.UUID, createdBy string, creator valuer.UUID, grafana *dashboardtypes.GrafanaDashboard) (*dashboardtypes.Dashboard, *dashboardtypes.GrafanaImportReport, error)
+
+	// Get gets the dashboard once the user of the context is allowed to view it, the dashboards the user cannot view are
+	// not found
 	Get(ctx context.Context, orgID valuer.UUID, id valuer.UUID) (*dashboardtypes.Dashboard, error)
 
+	// List lists the dashboards the user of the context is allowed to view
 	List(ctx context.Context, orgID valuer.UUID) ([]*dashboardtypes.Dashboard, error)
 
-	Update(ctx context.Context, orgID valuer.UUID, id valuer.UUID, updatedBy string, data dashboardtypes.UpdatableDashboard) (*dashboardtypes.Dashboard, error)
+	// Update updates the data of the dashboard and stores it as a new revision with the optional message, the user of
+	// the context must be an editor of the dashboard
+	Update(ctx context.Context, orgID valuer.UUID, id valuer.UUID, updatedBy string, data dashboardtypes.UpdatableDashboard, message string) (*dashboardtypes.Dashboard, error)
+
+	// Replace replaces the data of the dashboard whatever its lock, it is used to apply the dashboards of bundles
+	Replace(ctx context.Context, orgID valuer.UUID, id valuer.UUID, updatedBy string, data dashboardtypes.UpdatableDashboard, message string) (*dashboardtypes.Dashboard, error)
 
+	// LockUnlock locks or unlocks the dashboard, the user of the context must be an owner of the dashboard
 	LockUnlock(ctx context.Context, orgID valuer.UUID, id valuer.UUID, updatedBy string, lock bool) error
 
+	// Delete deletes the dashboard, the user of the context must be an owner of the dashboard once it has grants
 	Delete(ctx context.Context, orgID valuer.UUID, id valuer.UUID) error
 
+	// GetGrants gets the grants of the dashboard along with the ones inherited from its folder
+	GetGrants(ctx context.Context, orgID valuer.UUID, id valuer.UUID) (*dashboardtypes.GettableGrants, error)
+
+	// SetGrants replaces the grants of the dashboard, the user of the context must be an owner of the dashboard
+	SetGrants(ctx context.Context, orgID valuer.UUID, id valuer.UUID, grants *dashboardtypes.PostableGrants) (*dashboardtypes.GettableGrants, error)
+
+	// Move moves the dashboard in or out of a folder, the user of the context must be an editor of the folder
+	Move(ctx context.Context, orgID valuer.UUID, id valuer.UUID, updatedBy string, movable *dashboardtypes.MovableDashboard) (*dashboardtypes.Dashboard, error)
+
+	CreateFolder(ctx context.Context, orgID valuer.UUID, createdBy string, folder *dashboardtypes.PostableFolder) (*dashboardtypes.GettableFolder, error)
+
+	GetFolder(ctx context.Context, orgID valuer.UUID, id valuer.UUID) (*dashboardtypes.GettableFolder, error)
+
+	// ListFolders lists the folders the user of the context is allowed to view
+	ListFolders(ctx context.Context, orgID valuer.UUID) ([]*dashboardtypes.GettableFolder, error)
+
+	UpdateFolder(ctx context.Context, orgID valuer.UUID, id valuer.UUID, updatedBy string, folder *dashboardtypes.UpdatableFolder) (*dashboardtypes.GettableFolder, error)
+
+	// DeleteFolder deletes the folder once its dashboards have been moved out of it
+	DeleteFolder(ctx context.Context, orgID valuer.UUID, id valuer.UUID) error
+
+	GetFolderGrants(ctx context.Context, orgID valuer.UUID, id valuer.UUID) (*dashboardtypes.GettableGrants, error)
+
+	// SetFolderGrants replaces the grants of the folder, its dashboards inherit them
+	SetFolderGrants(ctx context.Context, orgID valuer.UUID, id valuer.UUID, grants *dashboardtypes.PostableGrants) (*dashboardtypes.GettableGrants, error)
+
+	// ListRevisions lists the revisions of the dashboard without their data, the latest first
+	ListRevisions(ctx context.Context, orgID valuer.UUID, id valuer.UUID) ([]*dashboardtypes.GettableRevision, error)
+
+	GetRevision(ctx context.Context, orgID valuer.UUID, id valuer.UUID, version int) (*dashboardtypes.GettableRevision, error)
+
+	// DiffRevisions diffs the data of two revisions of the dashboard widget by widget
+	DiffRevisions(ctx context.Context, orgID valuer.UUID, id valuer.UUID, from int, to int) (*dashboardtypes.RevisionDiff, error)
+
+	// RestoreRevision updates the dashboard with the data of the revision, the restore is stored as a new revision
+	RestoreRevision(ctx context.Context, orgID valuer.UUID, id valuer.UUID, version int, updatedBy string) (*dashboardtypes.Dashboard, error)
+
 	GetByMetricNames(ctx context.Context, orgID valuer.UUID, metricNames []string) (map[string][]map[string]string, error)
 
 	statsreporter.StatsCollector
@@ -35,4 +86,42 @@ type Handler interface {
 	LockUnlock(http.ResponseWriter, *http.Request)
 
 	Delete(http.ResponseWriter, *http.Request)
+
+	ListRevisions(http.ResponseWriter, *http.Request)
+
+	GetRevision(http.ResponseWriter, *http.Request)
+
+	DiffRevisions(http.ResponseWriter, *http.Request)
+
+	RestoreRevision(http.ResponseWriter, *http.Request)
+
+	// Schema renders the JSON schema of the data of dashboards
+	Schema(http.ResponseWriter, *http.Request)
+
+	// Upgrade renders the data of the request body upgraded to a later schema version once it is valid
+	Upgrade(http.ResponseWriter, *http.Request)
+
+	// ImportGrafana creates a dashboard from the grafana dashboard json of the request body and renders it along with
+	// the report of the translation
+	ImportGrafana(http.ResponseWriter, *http.Request)
+
+	GetGrants(http.ResponseWriter, *http.Request)
+
+	SetGrants(http.ResponseWriter, *http.Request)
+
+	Move(http.ResponseWriter, *http.Request)
+
+	CreateFolder(http.ResponseWriter, *http.Request)
+
+	GetFolder(http.ResponseWriter, *http.Request)
+
+	ListFolders(http.ResponseWriter, *http.Request)
+
+	UpdateFolder(http.ResponseWriter, *http.Request)
+
+	DeleteFolder(http.ResponseWriter, *http.Request)
+
+	GetFolderGrants(http.ResponseWriter, *http.Request)
+
+	SetFolderGrants(http.ResponseWriter, *http.Request)
 }
@@ -3,7 +3,9 @@ package impldashboard
 import (
 	"context"
 	"encoding/json"
+	"io"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/SigNoz/signoz/pkg/errors"
@@ -61,6 +63,49 @@ func (handler *handler) Create(rw http.ResponseWriter, r *http.Request) {
 	render.Success(rw, http.StatusCreated, gettableDashboard)
 }
 
+func (handler *handler) ImportGrafana(rw http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	claims, err := authtypes.ClaimsFromContext(ctx)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	orgID, err := valuer.NewUUID(claims.OrgID)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		render.Error(rw, errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "failed to read request body"))
+		return
+	}
+
+	grafana, err := dashboardtypes.NewGrafanaDashboard(body)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	dashboard, report, err := handler.module.ImportGrafana(ctx, orgID, claims.Email, valuer.MustNewUUID(claims.UserID), grafana)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	gettableDashboard, err := dashboardtypes.NewGettableDashboardFromDashboard(dashboard)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	render.Success(rw, http.StatusCreated, &dashboardtypes.GettableGrafanaImport{Dashboard: gettableDashboard, Report: report})
+}
+
 func (handler *handler) Update(rw http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
 	defer cancel()
@@ -95,7 +140,7 @@ func (handler *handler) Update(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	dashboard, err := handler.module.Update(ctx, orgID, dashboardID, claims.Email, req)
+	dashboard, err := handler.module.Update(ctx, orgID, dashboardID, claims.Email, req, r.URL.Query().Get("message"))
 	if err != nil {
 		render.Error(rw, err)
 		return
@@ -181,3 +226,450 @@ func (handler *handler) Delete(rw http.ResponseWriter, r *http.Request) {
 
 	render.Success(rw, http.StatusNoContent, nil)
 }
+
+func (handler *handler) ListRevisions(rw http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	claims, err := authtypes.ClaimsFromContext(ctx)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	dashboardID, err := valuer.NewUUID(mux.Vars(r)["id"])
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	revisions, err := handler.module.ListRevisions(ctx, valuer.MustNewUUID(claims.OrgID), dashboardID)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	render.Success(rw, http.StatusOK, revisions)
+}
+
+func (handler *handler) GetRevision(rw http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	claims, err := authtypes.ClaimsFromContext(ctx)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	dashboardID, err := valuer.NewUUID(mux.Vars(r)["id"])
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	version, err := parseVersion(mux.Vars(r)["version"], "version")
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	revision, err := handler.module.GetRevision(ctx, valuer.MustNewUUID(claims.OrgID), dashboardID, version)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	render.Success(rw, http.StatusOK, revision)
+}
+
+func (handler *handler) DiffRevisions(rw http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	claims, err := authtypes.ClaimsFromContext(ctx)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	dashboardID, err := valuer.NewUUID(mux.Vars(r)["id"])
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	from, err := parseVersion(r.URL.Query().Get("from"), "from")
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	to, err := parseVersion(r.URL.Query().Get("to"), "to")
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	diff, err := handler.module.DiffRevisions(ctx, valuer.MustNewUUID(claims.OrgID), dashboardID, from, to)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	render.Success(rw, http.StatusOK, diff)
+}
+
+func (handler *handler) RestoreRevision(rw http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	claims, err := authtypes.ClaimsFromContext(ctx)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	dashboardID, err := valuer.NewUUID(mux.Vars(r)["id"])
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	version, err := parseVersion(mux.Vars(r)["version"], "version")
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	dashboard, err := handler.module.RestoreRevision(ctx, valuer.MustNewUUID(claims.OrgID), dashboardID, version, claims.Email)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	render.Success(rw, http.StatusOK, dashboard)
+}
+
+func (handler *handler) GetGrants(rw http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	claims, err := authtypes.ClaimsFromContext(ctx)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	id, err := valuer.NewUUID(mux.Vars(r)["id"])
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	grants, err := handler.module.GetGrants(ctx, valuer.MustNewUUID(claims.OrgID), id)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	render.Success(rw, http.StatusOK, grants)
+}
+
+func (handler *handler) SetGrants(rw http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	claims, err := authtypes.ClaimsFromContext(ctx)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	id, err := valuer.NewUUID(mux.Vars(r)["id"])
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	req := new(dashboardtypes.PostableGrants)
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		render.Error(rw, errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "failed to decode request body"))
+		return
+	}
+
+	grants, err := handler.module.SetGrants(ctx, valuer.MustNewUUID(claims.OrgID), id, req)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	render.Success(rw, http.StatusOK, grants)
+}
+
+func (handler *handler) Move(rw http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	claims, err := authtypes.ClaimsFromContext(ctx)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	id, err := valuer.NewUUID(mux.Vars(r)["id"])
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	req := new(dashboardtypes.MovableDashboard)
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		render.Error(rw, errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "failed to decode request body"))
+		return
+	}
+
+	dashboard, err := handler.module.Move(ctx, valuer.MustNewUUID(claims.OrgID), id, claims.Email, req)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	render.Success(rw, http.StatusOK, dashboard)
+}
+
+func (handler *handler) CreateFolder(rw http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	claims, err := authtypes.ClaimsFromContext(ctx)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	req := new(dashboardtypes.PostableFolder)
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		render.Error(rw, errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "failed to decode request body"))
+		return
+	}
+
+	folder, err := handler.module.CreateFolder(ctx, valuer.MustNewUUID(claims.OrgID), claims.Email, req)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	render.Success(rw, http.StatusCreated, folder)
+}
+
+func (handler *handler) GetFolder(rw http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	claims, err := authtypes.ClaimsFromContext(ctx)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	id, err := valuer.NewUUID(mux.Vars(r)["id"])
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	folder, err := handler.module.GetFolder(ctx, valuer.MustNewUUID(claims.OrgID), id)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	render.Success(rw, http.StatusOK, folder)
+}
+
+func (handler *handler) ListFolders(rw http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	claims, err := authtypes.ClaimsFromContext(ctx)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	folders, err := handler.module.ListFolders(ctx, valuer.MustNewUUID(claims.OrgID))
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	render.Success(rw, http.StatusOK, folders)
+}
+
+func (handler *handler) UpdateFolder(rw http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	claims, err := authtypes.ClaimsFromContext(ctx)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	id, err := valuer.NewUUID(mux.Vars(r)["id"])
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	req := new(dashboardtypes.UpdatableFolder)
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		render.Error(rw, errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "failed to decode request body"))
+		return
+	}
+
+	folder, err := handler.module.UpdateFolder(ctx, valuer.MustNewUUID(claims.OrgID), id, claims.Email, req)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	render.Success(rw, http.StatusOK, folder)
+}
+
+func (handler *handler) DeleteFolder(rw http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	claims, err := authtypes.ClaimsFromContext(ctx)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	id, err := valuer.NewUUID(mux.Vars(r)["id"])
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	if err := handler.module.DeleteFolder(ctx, valuer.MustNewUUID(claims.OrgID), id); err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	render.Success(rw, http.StatusNoContent, nil)
+}
+
+func (handler *handler) GetFolderGrants(rw http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	claims, err := authtypes.ClaimsFromContext(ctx)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	id, err := valuer.NewUUID(mux.Vars(r)["id"])
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	grants, err := handler.module.GetFolderGrants(ctx, valuer.MustNewUUID(claims.OrgID), id)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	render.Success(rw, http.StatusOK, grants)
+}
+
+func (handler *handler) SetFolderGrants(rw http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	claims, err := authtypes.ClaimsFromContext(ctx)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	id, err := valuer.NewUUID(mux.Vars(r)["id"])
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	req := new(dashboardtypes.PostableGrants)
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		render.Error(rw, errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "failed to decode request body"))
+		return
+	}
+
+	grants, err := handler.module.SetFolderGrants(ctx, valuer.MustNewUUID(claims.OrgID), id, req)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	render.Success(rw, http.StatusOK, grants)
+}
+
+func (handler *handler) Schema(rw http.ResponseWriter, r *http.Request) {
+	version, err := parseSchemaVersion(r)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	render.Success(rw, http.StatusOK, dashboardtypes.NewJSONSchema(version))
+}
+
+func (handler *handler) Upgrade(rw http.ResponseWriter, r *http.Request) {
+	version, err := parseSchemaVersion(r)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	req := dashboardtypes.StorableDashboardData{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		render.Error(rw, errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "failed to decode request body"))
+		return
+	}
+
+	data, err := req.Upgrade(version)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	if err := data.Validate(); err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	render.Success(rw, http.StatusOK, data)
+}
+
+// parseSchemaVersion parses the version of the query params, the latest version if it is missing.
+func parseSchemaVersion(r *http.Request) (dashboardtypes.SchemaVersion, error) {
+	version := r.URL.Query().Get("version")
+	if version == "" {
+		return dashboardtypes.LatestSchemaVersion, nil
+	}
+
+	return dashboardtypes.NewSchemaVersion(version)
+}
+
+func parseVersion(value string, name string) (int, error) {
+	version, err := strconv.Atoi(value)
+	if err != nil || version < 1 {
+		return 0, errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "%s must be a positive integer, got %q", name, value)
+	}
+
+	return version, nil
+}
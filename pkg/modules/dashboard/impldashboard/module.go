@@ -2,14 +2,19 @@ package impldashboard
 
 import (
 	"context"
+	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/SigNoz/signoz/pkg/analytics"
 	"github.com/SigNoz/signoz/pkg/errors"
 	"github.com/SigNoz/signoz/pkg/factory"
+	"github.com/SigNoz/signoz/pkg/modules/audit"
 	"github.com/SigNoz/signoz/pkg/modules/dashboard"
 	"github.com/SigNoz/signoz/pkg/sqlstore"
 	"github.com/SigNoz/signoz/pkg/types/analyticstypes"
+	"github.com/SigNoz/signoz/pkg/types/audittypes"
+	"github.com/SigNoz/signoz/pkg/types/authtypes"
 	"github.com/SigNoz/signoz/pkg/types/dashboardtypes"
 	"github.com/SigNoz/signoz/pkg/valuer"
 )
@@ -18,14 +23,16 @@ type module struct {
 	store     dashboardtypes.Store
 	settings  factory.ScopedProviderSettings
 	analytics analytics.Analytics
+	audit     audit.Module
 }
 
-func NewModule(sqlstore sqlstore.SQLStore, settings factory.ProviderSettings, analytics analytics.Analytics) dashboard.Module {
+func NewModule(sqlstore sqlstore.SQLStore, settings factory.ProviderSettings, analytics analytics.Analytics, audit audit.Module) dashboard.Module {
 	scopedProviderSettings := factory.NewScopedProviderSettings(settings, "github.com/SigNoz/signoz/pkg/modules/impldashboard")
 	return &module{
 		store:     NewStore(sqlstore),
 		settings:  scopedProviderSettings,
 		analytics: analytics,
+		audit:     audit,
 	}
 }
 
@@ -40,11 +47,24 @@ func (module *module) Create(ctx context.Context, orgID valuer.UUID, createdBy s
 		return nil, err
 	}
 
-	err = module.store.Create(ctx, storableDashboard)
+	revision, err := dashboardtypes.NewStorableRevision(dashboard, 1, "", 0)
 	if err != nil {
 		return nil, err
 	}
 
+	err = module.store.RunInTx(ctx, func(ctx context.Context) error {
+		if err := module.store.Create(ctx, storableDashboard); err != nil {
+			return err
+		}
+
+		return module.store.CreateRevision(ctx, revision, dashboardtypes.MaxRevisions)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	module.audit.Record(ctx, orgID, audittypes.ActionCreate, audittypes.NewResource(audittypes.ResourceTypeDashboard, dashboard.ID), nil, dashboard)
+
 	module.analytics.Send(ctx,
 		analyticstypes.Track{
 			UserId:     creator.String(),
@@ -61,16 +81,23 @@ func (module *module) Create(ctx context.Context, orgID valuer.UUID, createdBy s
 	return dashboard, nil
 }
 
-func (module *module) Get(ctx context.Context, orgID valuer.UUID, id valuer.UUID) (*dashboardtypes.Dashboard, error) {
-	storableDashboard, err := module.store.Get(ctx, orgID, id)
+func (module *module) ImportGrafana(ctx context.Context, orgID valuer.UUID, createdBy string, creator valuer.UUID, grafana *dashboardtypes.GrafanaDashboard) (*dashboardtypes.Dashboard, *dashboardtypes.GrafanaImportReport, error) {
+	data, report := grafana.Translate()
+
+	dashboard, err := module.Create(ctx, orgID, createdBy, creator, data)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	dashboard, err := dashboardtypes.NewDashboardFromStorableDashboard(storableDashboard)
+	return dashboard, report, nil
+}
+
+func (module *module) Get(ctx context.Context, orgID valuer.UUID, id valuer.UUID) (*dashboardtypes.Dashboard, error) {
+	dashboard, _, _, err := module.getDashboard(ctx, orgID, id, dashboardtypes.PermissionViewer)
 	if err != nil {
 		return nil, err
 	}
+
 	return dashboard, nil
 }
 
@@ -85,43 +112,147 @@ func (module *module) List(ctx context.Context, orgID valuer.UUID) ([]*dashboard
 		return nil, err
 	}
 
-	return dashboards, nil
+	access, err := module.access(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	grants, err := module.store.ListGrants(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	folders, err := module.store.ListFolders(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.DeleteFunc(dashboards, func(dashboard *dashboardtypes.Dashboard) bool {
+		path := dashboardtypes.NewFolderPath(folders, dashboard.FolderID)
+		return !access.DashboardPermission(dashboard, path, grants).Includes(dashboardtypes.PermissionViewer)
+	}), nil
 }
 
-func (module *module) Update(ctx context.Context, orgID valuer.UUID, id valuer.UUID, updatedBy string, updatableDashboard dashboardtypes.UpdatableDashboard) (*dashboardtypes.Dashboard, error) {
-	dashboard, err := module.Get(ctx, orgID, id)
+func (module *module) Update(ctx context.Context, orgID valuer.UUID, id valuer.UUID, updatedBy string, updatableDashboard dashboardtypes.UpdatableDashboard, message string) (*dashboardtypes.Dashboard, error) {
+	dashboard, _, _, err := module.getDashboard(ctx, orgID, id, dashboardtypes.PermissionEditor)
 	if err != nil {
 		return nil, err
 	}
 
+	before := audittypes.NewSnapshot(dashboard)
 	err = dashboard.Update(updatableDashboard, updatedBy)
 	if err != nil {
 		return nil, err
 	}
 
-	storableDashboard, err := dashboardtypes.NewStorableDashboardFromDashboard(dashboard)
-	if err != nil {
+	if err := module.updateWithRevision(ctx, dashboard, message, 0); err != nil {
 		return nil, err
 	}
 
-	err = module.store.Update(ctx, orgID, storableDashboard)
+	module.audit.Record(ctx, orgID, audittypes.ActionUpdate, audittypes.NewResource(audittypes.ResourceTypeDashboard, dashboard.ID), before, dashboard)
+	return dashboard, nil
+}
+
+func (module *module) Replace(ctx context.Context, orgID valuer.UUID, id valuer.UUID, updatedBy string, updatableDashboard dashboardtypes.UpdatableDashboard, message string) (*dashboardtypes.Dashboard, error) {
+	dashboard, _, _, err := module.getDashboard(ctx, orgID, id, dashboardtypes.PermissionEditor)
 	if err != nil {
 		return nil, err
 	}
 
+	before := audittypes.NewSnapshot(dashboard)
+	if err := dashboard.Replace(updatableDashboard, updatedBy); err != nil {
+		return nil, err
+	}
+
+	if err := module.updateWithRevision(ctx, dashboard, message, 0); err != nil {
+		return nil, err
+	}
+
+	module.audit.Record(ctx, orgID, audittypes.ActionUpdate, audittypes.NewResource(audittypes.ResourceTypeDashboard, dashboard.ID), before, dashboard)
 	return dashboard, nil
 }
 
-func (module *module) LockUnlock(ctx context.Context, orgID valuer.UUID, id valuer.UUID, updatedBy string, lock bool) error {
-	dashboard, err := module.Get(ctx, orgID, id)
+func (module *module) ListRevisions(ctx context.Context, orgID valuer.UUID, id valuer.UUID) ([]*dashboardtypes.GettableRevision, error) {
+	if _, err := module.Get(ctx, orgID, id); err != nil {
+		return nil, err
+	}
+
+	revisions, err := module.store.ListRevisions(ctx, orgID, id)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	gettables := make([]*dashboardtypes.GettableRevision, 0, len(revisions))
+	for _, revision := range revisions {
+		gettables = append(gettables, dashboardtypes.NewGettableRevision(revision, false))
+	}
+
+	return gettables, nil
+}
+
+func (module *module) GetRevision(ctx context.Context, orgID valuer.UUID, id valuer.UUID, version int) (*dashboardtypes.GettableRevision, error) {
+	if _, err := module.Get(ctx, orgID, id); err != nil {
+		return nil, err
 	}
 
-	err = dashboard.LockUnlock(ctx, lock, updatedBy)
+	revision, err := module.store.GetRevision(ctx, orgID, id, version)
+	if err != nil {
+		return nil, err
+	}
+
+	return dashboardtypes.NewGettableRevision(revision, true), nil
+}
+
+func (module *module) DiffRevisions(ctx context.Context, orgID valuer.UUID, id valuer.UUID, from int, to int) (*dashboardtypes.RevisionDiff, error) {
+	if _, err := module.Get(ctx, orgID, id); err != nil {
+		return nil, err
+	}
+
+	fromRevision, err := module.store.GetRevision(ctx, orgID, id, from)
+	if err != nil {
+		return nil, err
+	}
+
+	toRevision, err := module.store.GetRevision(ctx, orgID, id, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return dashboardtypes.NewRevisionDiff(fromRevision, toRevision), nil
+}
+
+func (module *module) RestoreRevision(ctx context.Context, orgID valuer.UUID, id valuer.UUID, version int, updatedBy string) (*dashboardtypes.Dashboard, error) {
+	dashboard, _, _, err := module.getDashboard(ctx, orgID, id, dashboardtypes.PermissionEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	revision, err := module.store.GetRevision(ctx, orgID, id, version)
+	if err != nil {
+		return nil, err
+	}
+
+	before := audittypes.NewSnapshot(dashboard)
+	if err := dashboard.Restore(revision, updatedBy); err != nil {
+		return nil, err
+	}
+
+	if err := module.updateWithRevision(ctx, dashboard, fmt.Sprintf("Restored version %d", version), version); err != nil {
+		return nil, err
+	}
+
+	module.audit.Record(ctx, orgID, audittypes.ActionUpdate, audittypes.NewResource(audittypes.ResourceTypeDashboard, dashboard.ID), before, dashboard)
+	return dashboard, nil
+}
+
+func (module *module) LockUnlock(ctx context.Context, orgID valuer.UUID, id valuer.UUID, updatedBy string, lock bool) error {
+	dashboard, _, _, err := module.getDashboard(ctx, orgID, id, dashboardtypes.PermissionOwner)
 	if err != nil {
 		return err
 	}
+
+	before := audittypes.NewSnapshot(dashboard)
+	dashboard.LockUnlock(lock, updatedBy)
 	storableDashboard, err := dashboardtypes.NewStorableDashboardFromDashboard(dashboard)
 	if err != nil {
 		return err
@@ -132,20 +263,393 @@ func (module *module) LockUnlock(ctx context.Context, orgID valuer.UUID, id valu
 		return err
 	}
 
+	module.audit.Record(ctx, orgID, audittypes.ActionUpdate, audittypes.NewResource(audittypes.ResourceTypeDashboard, dashboard.ID), before, dashboard)
 	return nil
 }
 
 func (module *module) Delete(ctx context.Context, orgID valuer.UUID, id valuer.UUID) error {
-	dashboard, err := module.Get(ctx, orgID, id)
+	dashboard, permission, grants, err := module.getDashboard(ctx, orgID, id, dashboardtypes.PermissionEditor)
 	if err != nil {
 		return err
 	}
 
+	if err := authorize(permission, dashboardtypes.ManagePermission(grants), errors.CodeNotFound, "dashboard", id); err != nil {
+		return err
+	}
+
 	if dashboard.Locked {
 		return errors.New(errors.TypeInvalidInput, errors.CodeInvalidInput, "dashboard is locked, please unlock the dashboard to be delete it")
 	}
 
-	return module.store.Delete(ctx, orgID, id)
+	err = module.store.Delete(ctx, orgID, id)
+	if err != nil {
+		return err
+	}
+
+	module.audit.Record(ctx, orgID, audittypes.ActionDelete, audittypes.NewResource(audittypes.ResourceTypeDashboard, dashboard.ID), dashboard, nil)
+	return nil
+}
+
+func (module *module) GetGrants(ctx context.Context, orgID valuer.UUID, id valuer.UUID) (*dashboardtypes.GettableGrants, error) {
+	dashboard, permission, grants, err := module.getDashboard(ctx, orgID, id, dashboardtypes.PermissionViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	return newGettableGrants(dashboard, grants, permission), nil
+}
+
+func (module *module) SetGrants(ctx context.Context, orgID valuer.UUID, id valuer.UUID, postable *dashboardtypes.PostableGrants) (*dashboardtypes.GettableGrants, error) {
+	dashboard, permission, grants, err := module.getDashboard(ctx, orgID, id, dashboardtypes.PermissionOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	before := newGettableGrants(dashboard, grants, permission)
+	ownGrants, err := dashboardtypes.NewGrants(orgID, dashboardtypes.ResourceTypeDashboard, id, postable)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := module.store.SetGrants(ctx, orgID, dashboardtypes.ResourceTypeDashboard, id, ownGrants); err != nil {
+		return nil, err
+	}
+
+	after := dashboardtypes.NewGettableGrants(ownGrants, before.Inherited, permission)
+	module.audit.Record(ctx, orgID, audittypes.ActionUpdate, audittypes.NewResource(audittypes.ResourceTypeDashboard, dashboard.ID), before, after)
+	return after, nil
+}
+
+func (module *module) Move(ctx context.Context, orgID valuer.UUID, id valuer.UUID, updatedBy string, movable *dashboardtypes.MovableDashboard) (*dashboardtypes.Dashboard, error) {
+	if err := movable.Validate(); err != nil {
+		return nil, err
+	}
+
+	dashboard, permission, grants, err := module.getDashboard(ctx, orgID, id, dashboardtypes.PermissionEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorize(permission, dashboardtypes.ManagePermission(grants), errors.CodeNotFound, "dashboard", id); err != nil {
+		return nil, err
+	}
+
+	if movable.FolderID != "" {
+		if _, _, _, err := module.getFolder(ctx, orgID, valuer.MustNewUUID(movable.FolderID), dashboardtypes.PermissionEditor); err != nil {
+			return nil, err
+		}
+	}
+
+	before := audittypes.NewSnapshot(dashboard)
+	dashboard.Move(movable.FolderID, updatedBy)
+	storableDashboard, err := dashboardtypes.NewStorableDashboardFromDashboard(dashboard)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := module.store.Update(ctx, orgID, storableDashboard); err != nil {
+		return nil, err
+	}
+
+	module.audit.Record(ctx, orgID, audittypes.ActionUpdate, audittypes.NewResource(audittypes.ResourceTypeDashboard, dashboard.ID), before, dashboard)
+	return dashboard, nil
+}
+
+func (module *module) CreateFolder(ctx context.Context, orgID valuer.UUID, createdBy string, postable *dashboardtypes.PostableFolder) (*dashboardtypes.GettableFolder, error) {
+	folder, err := dashboardtypes.NewFolder(orgID, createdBy, postable)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := module.validateParent(ctx, orgID, folder); err != nil {
+		return nil, err
+	}
+
+	if err := module.store.CreateFolder(ctx, folder); err != nil {
+		return nil, err
+	}
+
+	module.audit.Record(ctx, orgID, audittypes.ActionCreate, audittypes.NewResource(audittypes.ResourceTypeDashboardFolder, folder.ID.StringValue()), nil, folder)
+	return dashboardtypes.NewGettableFolder(folder, dashboardtypes.PermissionOwner), nil
+}
+
+func (module *module) GetFolder(ctx context.Context, orgID valuer.UUID, id valuer.UUID) (*dashboardtypes.GettableFolder, error) {
+	path, permission, _, err := module.getFolder(ctx, orgID, id, dashboardtypes.PermissionViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	return dashboardtypes.NewGettableFolder(path[0], permission), nil
+}
+
+func (module *module) ListFolders(ctx context.Context, orgID valuer.UUID) ([]*dashboardtypes.GettableFolder, error) {
+	folders, err := module.store.ListFolders(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	access, err := module.access(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	grants, err := module.store.ListGrants(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	gettables := make([]*dashboardtypes.GettableFolder, 0, len(folders))
+	for _, folder := range folders {
+		permission := access.FolderPermission(dashboardtypes.NewFolderPath(folders, folder.ID.StringValue()), grants)
+		if permission.Includes(dashboardtypes.PermissionViewer) {
+			gettables = append(gettables, dashboardtypes.NewGettableFolder(folder, permission))
+		}
+	}
+
+	return gettables, nil
+}
+
+func (module *module) UpdateFolder(ctx context.Context, orgID valuer.UUID, id valuer.UUID, updatedBy string, updatable *dashboardtypes.UpdatableFolder) (*dashboardtypes.GettableFolder, error) {
+	path, permission, grants, err := module.getFolder(ctx, orgID, id, dashboardtypes.PermissionEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	folder := path[0]
+	before := *folder
+	if err := folder.Update(updatedBy, updatable); err != nil {
+		return nil, err
+	}
+
+	// moving the folder changes the grants its dashboards inherit
+	if folder.ParentID != before.ParentID {
+		if err := authorize(permission, dashboardtypes.ManagePermission(grants), dashboardtypes.ErrCodeFolderNotFound, "folder", id); err != nil {
+			return nil, err
+		}
+
+		if err := module.validateParent(ctx, orgID, folder); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := module.store.UpdateFolder(ctx, folder); err != nil {
+		return nil, err
+	}
+
+	module.audit.Record(ctx, orgID, audittypes.ActionUpdate, audittypes.NewResource(audittypes.ResourceTypeDashboardFolder, folder.ID.StringValue()), &before, folder)
+	return dashboardtypes.NewGettableFolder(folder, permission), nil
+}
+
+func (module *module) DeleteFolder(ctx context.Context, orgID valuer.UUID, id valuer.UUID) error {
+	path, permission, grants, err := module.getFolder(ctx, orgID, id, dashboardtypes.PermissionEditor)
+	if err != nil {
+		return err
+	}
+
+	if err := authorize(permission, dashboardtypes.ManagePermission(grants), dashboardtypes.ErrCodeFolderNotFound, "folder", id); err != nil {
+		return err
+	}
+
+	if err := module.store.DeleteFolder(ctx, orgID, id); err != nil {
+		return err
+	}
+
+	module.audit.Record(ctx, orgID, audittypes.ActionDelete, audittypes.NewResource(audittypes.ResourceTypeDashboardFolder, id.StringValue()), path[0], nil)
+	return nil
+}
+
+func (module *module) GetFolderGrants(ctx context.Context, orgID valuer.UUID, id valuer.UUID) (*dashboardtypes.GettableGrants, error) {
+	path, permission, grants, err := module.getFolder(ctx, orgID, id, dashboardtypes.PermissionViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	return dashboardtypes.NewGettableGrants(dashboardtypes.FolderGrants(path[0], grants), dashboardtypes.PathGrants(path[1:], grants), permission), nil
+}
+
+func (module *module) SetFolderGrants(ctx context.Context, orgID valuer.UUID, id valuer.UUID, postable *dashboardtypes.PostableGrants) (*dashboardtypes.GettableGrants, error) {
+	path, permission, grants, err := module.getFolder(ctx, orgID, id, dashboardtypes.PermissionOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	folderGrants, err := dashboardtypes.NewGrants(orgID, dashboardtypes.ResourceTypeFolder, id, postable)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := module.store.SetGrants(ctx, orgID, dashboardtypes.ResourceTypeFolder, id, folderGrants); err != nil {
+		return nil, err
+	}
+
+	inherited := dashboardtypes.PathGrants(path[1:], grants)
+	before := dashboardtypes.NewGettableGrants(dashboardtypes.FolderGrants(path[0], grants), inherited, permission)
+	after := dashboardtypes.NewGettableGrants(folderGrants, inherited, permission)
+	module.audit.Record(ctx, orgID, audittypes.ActionUpdate, audittypes.NewResource(audittypes.ResourceTypeDashboardFolder, id.StringValue()), before, after)
+	return after, nil
+}
+
+// access resolves the access of the user of the context, the work done in the background runs with the claims of the
+// user it is done on behalf of.
+func (module *module) access(ctx context.Context) (*dashboardtypes.Access, error) {
+	claims, err := authtypes.ClaimsFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := valuer.NewUUID(claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	teamIDs, err := module.store.ListTeamIDs(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return dashboardtypes.NewAccess(claims, teamIDs), nil
+}
+
+// getDashboard gets the dashboard once the user of the context has the permission on it, along with the permission and
+// the grants of the dashboard and of the folders it is in.
+func (module *module) getDashboard(ctx context.Context, orgID valuer.UUID, id valuer.UUID, required dashboardtypes.Permission) (*dashboardtypes.Dashboard, dashboardtypes.Permission, []*dashboardtypes.Grant, error) {
+	storableDashboard, err := module.store.Get(ctx, orgID, id)
+	if err != nil {
+		return nil, dashboardtypes.Permission{}, nil, err
+	}
+
+	dashboard, err := dashboardtypes.NewDashboardFromStorableDashboard(storableDashboard)
+	if err != nil {
+		return nil, dashboardtypes.Permission{}, nil, err
+	}
+
+	access, err := module.access(ctx)
+	if err != nil {
+		return nil, dashboardtypes.Permission{}, nil, err
+	}
+
+	grants, err := module.store.ListGrants(ctx, orgID)
+	if err != nil {
+		return nil, dashboardtypes.Permission{}, nil, err
+	}
+
+	folders, err := module.store.ListFolders(ctx, orgID)
+	if err != nil {
+		return nil, dashboardtypes.Permission{}, nil, err
+	}
+
+	path := dashboardtypes.NewFolderPath(folders, dashboard.FolderID)
+	permission := access.DashboardPermission(dashboard, path, grants)
+	if err := authorize(permission, required, errors.CodeNotFound, "dashboard", id); err != nil {
+		return nil, dashboardtypes.Permission{}, nil, err
+	}
+
+	return dashboard, permission, dashboardtypes.DashboardGrants(dashboard, path, grants), nil
+}
+
+// getFolder gets the path of the folder once the user of the context has the permission on it, the folder followed by
+// its ancestors, along with the permission and the grants of the folders of the path.
+func (module *module) getFolder(ctx context.Context, orgID valuer.UUID, id valuer.UUID, required dashboardtypes.Permission) ([]*dashboardtypes.Folder, dashboardtypes.Permission, []*dashboardtypes.Grant, error) {
+	if _, err := module.store.GetFolder(ctx, orgID, id); err != nil {
+		return nil, dashboardtypes.Permission{}, nil, err
+	}
+
+	access, err := module.access(ctx)
+	if err != nil {
+		return nil, dashboardtypes.Permission{}, nil, err
+	}
+
+	grants, err := module.store.ListGrants(ctx, orgID)
+	if err != nil {
+		return nil, dashboardtypes.Permission{}, nil, err
+	}
+
+	folders, err := module.store.ListFolders(ctx, orgID)
+	if err != nil {
+		return nil, dashboardtypes.Permission{}, nil, err
+	}
+
+	path := dashboardtypes.NewFolderPath(folders, id.StringValue())
+	permission := access.FolderPermission(path, grants)
+	if err := authorize(permission, required, dashboardtypes.ErrCodeFolderNotFound, "folder", id); err != nil {
+		return nil, dashboardtypes.Permission{}, nil, err
+	}
+
+	return path, permission, dashboardtypes.PathGrants(path, grants), nil
+}
+
+// validateParent checks that the user of the context can edit the parent of the folder and that the folder can be
+// nested in it.
+func (module *module) validateParent(ctx context.Context, orgID valuer.UUID, folder *dashboardtypes.Folder) error {
+	if folder.ParentID == "" {
+		return nil
+	}
+
+	parentPath, _, _, err := module.getFolder(ctx, orgID, valuer.MustNewUUID(folder.ParentID), dashboardtypes.PermissionEditor)
+	if err != nil {
+		return err
+	}
+
+	folders, err := module.store.ListFolders(ctx, orgID)
+	if err != nil {
+		return err
+	}
+
+	return folder.ValidateParent(parentPath, max(folder.Depth(folders), 1))
+}
+
+// authorize checks that the permission includes the required one, the dashboards and folders the user cannot view
+// are reported as not found to keep them hidden.
+func authorize(permission dashboardtypes.Permission, required dashboardtypes.Permission, notFound errors.Code, kind string, id valuer.UUID) error {
+	if !permission.Includes(dashboardtypes.PermissionViewer) {
+		return errors.Newf(errors.TypeNotFound, notFound, "%s with id %s doesn't exist", kind, id)
+	}
+
+	if !permission.Includes(required) {
+		return errors.Newf(errors.TypeForbidden, errors.CodeForbidden, "the %s permission on the %s with id %s is required", required.StringValue(), kind, id)
+	}
+
+	return nil
+}
+
+// newGettableGrants splits the grants of the dashboard into its own and the ones inherited from its folders.
+func newGettableGrants(dashboard *dashboardtypes.Dashboard, grants []*dashboardtypes.Grant, permission dashboardtypes.Permission) *dashboardtypes.GettableGrants {
+	own, inherited := make([]*dashboardtypes.Grant, 0), make([]*dashboardtypes.Grant, 0)
+	for _, grant := range grants {
+		if grant.ResourceType == dashboardtypes.ResourceTypeDashboard && grant.ResourceID.StringValue() == dashboard.ID {
+			own = append(own, grant)
+		} else {
+			inherited = append(inherited, grant)
+		}
+	}
+
+	return dashboardtypes.NewGettableGrants(own, inherited, permission)
+}
+
+// updateWithRevision stores the updated data of the dashboard along with the revision recording it.
+func (module *module) updateWithRevision(ctx context.Context, dashboard *dashboardtypes.Dashboard, message string, restoredFrom int) error {
+	storableDashboard, err := dashboardtypes.NewStorableDashboardFromDashboard(dashboard)
+	if err != nil {
+		return err
+	}
+
+	return module.store.RunInTx(ctx, func(ctx context.Context) error {
+		if err := module.store.Update(ctx, dashboard.OrgID, storableDashboard); err != nil {
+			return err
+		}
+
+		version, err := module.store.GetLatestVersion(ctx, dashboard.OrgID, storableDashboard.ID)
+		if err != nil {
+			return err
+		}
+
+		revision, err := dashboardtypes.NewStorableRevision(dashboard, version+1, message, restoredFrom)
+		if err != nil {
+			return err
+		}
+
+		return module.store.CreateRevision(ctx, revision, dashboardtypes.MaxRevisions)
+	})
 }
 
 func (module *module) GetByMetricNames(ctx context.Context, orgID valuer.UUID, metricNames []string) (map[string][]map[string]string, error) {
@@ -0,0 +1,196 @@
+package impldashboard
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/SigNoz/signoz/pkg/analytics/analyticstest"
+	"github.com/SigNoz/signoz/pkg/errors"
+	"github.com/SigNoz/signoz/pkg/factory/factorytest"
+	"github.com/SigNoz/signoz/pkg/modules/audit/implaudit"
+	"github.com/SigNoz/signoz/pkg/query-service/utils"
+	"github.com/SigNoz/signoz/pkg/types"
+	"github.com/SigNoz/signoz/pkg/types/authtypes"
+	"github.com/SigNoz/signoz/pkg/types/dashboardtypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModuleRevisions(t *testing.T) {
+	sqlStore := utils.NewQueryServiceDBForTests(t)
+	require.NoError(t, utils.CreateTestOrg(t, sqlStore))
+	orgID, err := utils.GetTestOrgId(sqlStore)
+	require.NoError(t, err)
+	ctx := authtypes.NewContextWithClaims(context.Background(), authtypes.Claims{UserID: valuer.GenerateUUID().StringValue(), Email: "john@example.com", OrgID: orgID.StringValue(), Role: types.RoleEditor})
+
+	providerSettings := factorytest.NewSettings()
+	module := NewModule(sqlStore, providerSettings, analyticstest.New(), implaudit.NewModule(implaudit.NewStore(sqlStore), providerSettings))
+
+	widget := func(id string, title string) map[string]any {
+		return map[string]any{"id": id, "title": title, "panelTypes": "row"}
+	}
+
+	dashboard, err := module.Create(ctx, orgID, "jane@example.com", valuer.GenerateUUID(), dashboardtypes.PostableDashboard{"title": "payments", "widgets": []any{widget("a", "latency")}})
+	require.NoError(t, err)
+	id := valuer.MustNewUUID(dashboard.ID)
+
+	_, err = module.Update(ctx, orgID, id, "john@example.com", dashboardtypes.UpdatableDashboard{"title": "payments", "widgets": []any{widget("a", "latency"), widget("b", "errors")}}, "add errors")
+	require.NoError(t, err)
+	_, err = module.Update(ctx, orgID, id, "john@example.com", dashboardtypes.UpdatableDashboard{"title": "payments", "widgets": []any{widget("a", "p99 latency"), widget("b", "errors")}}, "")
+	require.NoError(t, err)
+
+	revisions, err := module.ListRevisions(ctx, orgID, id)
+	require.NoError(t, err)
+	require.Len(t, revisions, 3)
+	assert.Equal(t, 3, revisions[0].Version)
+	assert.Equal(t, "add errors", revisions[1].Message)
+	assert.Equal(t, "jane@example.com", revisions[2].CreatedBy)
+	assert.Nil(t, revisions[0].Data)
+
+	revision, err := module.GetRevision(ctx, orgID, id, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "payments", revision.Data["title"])
+	_, err = module.GetRevision(ctx, orgID, id, 4)
+	assert.True(t, errors.Ast(err, errors.TypeNotFound))
+
+	diff, err := module.DiffRevisions(ctx, orgID, id, 1, 3)
+	require.NoError(t, err)
+	require.Len(t, diff.Widgets, 2)
+	assert.Equal(t, dashboardtypes.WidgetChangeTypeModified, diff.Widgets[0].Type)
+	assert.Equal(t, dashboardtypes.WidgetChangeTypeAdded, diff.Widgets[1].Type)
+
+	// restoring is an update of its own, the later revisions are kept
+	restored, err := module.RestoreRevision(ctx, orgID, id, 1, "jane@example.com")
+	require.NoError(t, err)
+	assert.Len(t, restored.Data["widgets"], 1)
+	revisions, err = module.ListRevisions(ctx, orgID, id)
+	require.NoError(t, err)
+	require.Len(t, revisions, 4)
+	assert.Equal(t, 1, revisions[0].RestoredFrom)
+
+	// the oldest revisions are pruned past the retention
+	for i := 0; i < dashboardtypes.MaxRevisions; i++ {
+		_, err = module.Update(ctx, orgID, id, "john@example.com", dashboardtypes.UpdatableDashboard{"title": "payments", "description": strconv.Itoa(i)}, "")
+		require.NoError(t, err)
+	}
+	revisions, err = module.ListRevisions(ctx, orgID, id)
+	require.NoError(t, err)
+	require.Len(t, revisions, dashboardtypes.MaxRevisions)
+	assert.Equal(t, dashboardtypes.MaxRevisions+4, revisions[0].Version)
+
+	require.NoError(t, module.Delete(ctx, orgID, id))
+	_, err = module.ListRevisions(ctx, orgID, id)
+	assert.True(t, errors.Ast(err, errors.TypeNotFound))
+}
+
+func TestModuleGrants(t *testing.T) {
+	sqlStore := utils.NewQueryServiceDBForTests(t)
+	require.NoError(t, utils.CreateTestOrg(t, sqlStore))
+	orgID, err := utils.GetTestOrgId(sqlStore)
+	require.NoError(t, err)
+
+	providerSettings := factorytest.NewSettings()
+	module := NewModule(sqlStore, providerSettings, analyticstest.New(), implaudit.NewModule(implaudit.NewStore(sqlStore), providerSettings))
+
+	userContext := func(email string, role types.Role) (context.Context, valuer.UUID) {
+		userID := valuer.GenerateUUID()
+		return authtypes.NewContextWithClaims(context.Background(), authtypes.Claims{UserID: userID.StringValue(), Email: email, OrgID: orgID.StringValue(), Role: role}), userID
+	}
+	jane, _ := userContext("jane@example.com", types.RoleEditor)
+	john, johnID := userContext("john@example.com", types.RoleEditor)
+	bob, bobID := userContext("bob@example.com", types.RoleEditor)
+	admin, _ := userContext("admin@example.com", types.RoleAdmin)
+
+	revenue, err := module.Create(jane, orgID, "jane@example.com", valuer.GenerateUUID(), dashboardtypes.PostableDashboard{"title": "revenue"})
+	require.NoError(t, err)
+	revenueID := valuer.MustNewUUID(revenue.ID)
+	costs, err := module.Create(jane, orgID, "jane@example.com", valuer.GenerateUUID(), dashboardtypes.PostableDashboard{"title": "costs"})
+	require.NoError(t, err)
+	costsID := valuer.MustNewUUID(costs.ID)
+
+	// dashboards without grants are open to the whole org
+	_, err = module.Update(bob, orgID, revenueID, "bob@example.com", dashboardtypes.UpdatableDashboard{"title": "revenue", "description": "monthly"}, "")
+	require.NoError(t, err)
+	_, err = module.SetGrants(bob, orgID, revenueID, &dashboardtypes.PostableGrants{})
+	assert.True(t, errors.Ast(err, errors.TypeForbidden))
+
+	_, err = module.SetGrants(jane, orgID, revenueID, &dashboardtypes.PostableGrants{Grants: []*dashboardtypes.PostableGrant{
+		{SubjectType: dashboardtypes.SubjectTypeUser, SubjectID: johnID, Permission: dashboardtypes.PermissionViewer},
+	}})
+	require.NoError(t, err)
+
+	_, err = module.Get(bob, orgID, revenueID)
+	assert.True(t, errors.Ast(err, errors.TypeNotFound))
+	dashboards, err := module.List(bob, orgID)
+	require.NoError(t, err)
+	require.Len(t, dashboards, 1)
+	assert.Equal(t, costs.ID, dashboards[0].ID)
+
+	_, err = module.Get(john, orgID, revenueID)
+	require.NoError(t, err)
+	_, err = module.Update(john, orgID, revenueID, "john@example.com", dashboardtypes.UpdatableDashboard{"title": "revenue"}, "")
+	assert.True(t, errors.Ast(err, errors.TypeForbidden))
+	assert.True(t, errors.Ast(module.Delete(john, orgID, revenueID), errors.TypeForbidden))
+	assert.True(t, errors.Ast(module.LockUnlock(john, orgID, revenueID, "john@example.com", true), errors.TypeForbidden))
+
+	// the dashboards of a folder inherit its grants
+	folder, err := module.CreateFolder(admin, orgID, "admin@example.com", &dashboardtypes.PostableFolder{Name: "finance"})
+	require.NoError(t, err)
+	_, err = module.SetFolderGrants(admin, orgID, folder.ID, &dashboardtypes.PostableGrants{Grants: []*dashboardtypes.PostableGrant{
+		{SubjectType: dashboardtypes.SubjectTypeUser, SubjectID: bobID, Permission: dashboardtypes.PermissionViewer},
+	}})
+	require.NoError(t, err)
+
+	_, err = module.Move(jane, orgID, costsID, "jane@example.com", &dashboardtypes.MovableDashboard{FolderID: folder.ID.StringValue()})
+	assert.True(t, errors.Ast(err, errors.TypeNotFound))
+	_, err = module.Move(admin, orgID, costsID, "admin@example.com", &dashboardtypes.MovableDashboard{FolderID: folder.ID.StringValue()})
+	require.NoError(t, err)
+
+	_, err = module.Get(john, orgID, costsID)
+	assert.True(t, errors.Ast(err, errors.TypeNotFound))
+	folders, err := module.ListFolders(john, orgID)
+	require.NoError(t, err)
+	assert.Empty(t, folders)
+
+	grants, err := module.GetGrants(bob, orgID, costsID)
+	require.NoError(t, err)
+	assert.Empty(t, grants.Grants)
+	require.Len(t, grants.Inherited, 1)
+	assert.Equal(t, dashboardtypes.PermissionViewer, grants.Permission)
+
+	// the creator of the dashboard stays its owner
+	_, err = module.Update(jane, orgID, costsID, "jane@example.com", dashboardtypes.UpdatableDashboard{"title": "costs", "description": "monthly"}, "")
+	require.NoError(t, err)
+
+	// nested folders inherit the grants of their ancestors
+	payroll, err := module.CreateFolder(admin, orgID, "admin@example.com", &dashboardtypes.PostableFolder{Name: "payroll", ParentID: folder.ID.StringValue()})
+	require.NoError(t, err)
+	_, err = module.CreateFolder(bob, orgID, "bob@example.com", &dashboardtypes.PostableFolder{Name: "bonuses", ParentID: payroll.ID.StringValue()})
+	assert.True(t, errors.Ast(err, errors.TypeForbidden))
+	_, err = module.GetFolder(john, orgID, payroll.ID)
+	assert.True(t, errors.Asc(err, dashboardtypes.ErrCodeFolderNotFound))
+
+	grants, err = module.GetFolderGrants(bob, orgID, payroll.ID)
+	require.NoError(t, err)
+	assert.Empty(t, grants.Grants)
+	require.Len(t, grants.Inherited, 1)
+	assert.Equal(t, dashboardtypes.PermissionViewer, grants.Permission)
+
+	_, err = module.UpdateFolder(admin, orgID, folder.ID, "admin@example.com", &dashboardtypes.UpdatableFolder{Name: "finance", ParentID: payroll.ID.StringValue()})
+	assert.True(t, errors.Ast(err, errors.TypeInvalidInput))
+
+	err = module.DeleteFolder(admin, orgID, folder.ID)
+	assert.True(t, errors.Asc(err, dashboardtypes.ErrCodeFolderNotEmpty))
+	_, err = module.Move(admin, orgID, costsID, "admin@example.com", &dashboardtypes.MovableDashboard{})
+	require.NoError(t, err)
+	err = module.DeleteFolder(admin, orgID, folder.ID)
+	assert.True(t, errors.Asc(err, dashboardtypes.ErrCodeFolderNotEmpty))
+	require.NoError(t, module.DeleteFolder(admin, orgID, payroll.ID))
+	require.NoError(t, module.DeleteFolder(admin, orgID, folder.ID))
+
+	dashboards, err = module.List(john, orgID)
+	require.NoError(t, err)
+	assert.Len(t, dashboards, 2)
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/SigNoz/signoz/pkg/errors"
 	"github.com/SigNoz/signoz/pkg/sqlstore"
 	"github.com/SigNoz/signoz/pkg/types/dashboardtypes"
+	"github.com/SigNoz/signoz/pkg/types/teamtypes"
 	"github.com/SigNoz/signoz/pkg/valuer"
 )
 
@@ -20,7 +21,7 @@ func NewStore(sqlstore sqlstore.SQLStore) dashboardtypes.Store {
 func (store *store) Create(ctx context.Context, storabledashboard *dashboardtypes.StorableDashboard) error {
 	_, err := store.
 		sqlstore.
-		BunDB().
+		BunDBCtx(ctx).
 		NewInsert().
 		Model(storabledashboard).
 		Exec(ctx)
@@ -36,7 +37,7 @@ func (store *store) Get(ctx context.Context, orgID valuer.UUID, id valuer.UUID)
 
 	err := store.
 		sqlstore.
-		BunDB().
+		BunDBCtx(ctx).
 		NewSelect().
 		Model(storableDashboard).
 		Where("id = ?", id).
@@ -54,7 +55,7 @@ func (store *store) List(ctx context.Context, orgID valuer.UUID) ([]*dashboardty
 
 	err := store.
 		sqlstore.
-		BunDB().
+		BunDBCtx(ctx).
 		NewSelect().
 		Model(&storableDashboards).
 		Where("org_id = ?", orgID).
@@ -69,7 +70,7 @@ func (store *store) List(ctx context.Context, orgID valuer.UUID) ([]*dashboardty
 func (store *store) Update(ctx context.Context, orgID valuer.UUID, storableDashboard *dashboardtypes.StorableDashboard) error {
 	_, err := store.
 		sqlstore.
-		BunDB().
+		BunDBCtx(ctx).
 		NewUpdate().
 		Model(storableDashboard).
 		WherePK().
@@ -83,17 +84,319 @@ func (store *store) Update(ctx context.Context, orgID valuer.UUID, storableDashb
 }
 
 func (store *store) Delete(ctx context.Context, orgID valuer.UUID, id valuer.UUID) error {
+	return store.sqlstore.RunInTxCtx(ctx, nil, func(ctx context.Context) error {
+		_, err := store.
+			sqlstore.
+			BunDBCtx(ctx).
+			NewDelete().
+			Model(new(dashboardtypes.StorableRevision)).
+			Where("org_id = ?", orgID).
+			Where("dashboard_id = ?", id).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		_, err = store.
+			sqlstore.
+			BunDBCtx(ctx).
+			NewDelete().
+			Model(new(dashboardtypes.Grant)).
+			Where("org_id = ?", orgID).
+			Where("resource_type = ?", dashboardtypes.ResourceTypeDashboard).
+			Where("resource_id = ?", id).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		_, err = store.
+			sqlstore.
+			BunDBCtx(ctx).
+			NewDelete().
+			Model(new(dashboardtypes.StorableDashboard)).
+			Where("id = ?", id).
+			Where("org_id = ?", orgID).
+			Exec(ctx)
+		if err != nil {
+			return store.sqlstore.WrapNotFoundErrf(err, errors.CodeNotFound, "dashboard with id %s doesn't exist", id)
+		}
+
+		return nil
+	})
+}
+
+func (store *store) CreateRevision(ctx context.Context, revision *dashboardtypes.StorableRevision, retention int) error {
+	return store.sqlstore.RunInTxCtx(ctx, nil, func(ctx context.Context) error {
+		_, err := store.
+			sqlstore.
+			BunDBCtx(ctx).
+			NewInsert().
+			Model(revision).
+			Exec(ctx)
+		if err != nil {
+			return store.sqlstore.WrapAlreadyExistsErrf(err, errors.CodeAlreadyExists, "revision %d of dashboard with id %s already exists", revision.Version, revision.DashboardID)
+		}
+
+		_, err = store.
+			sqlstore.
+			BunDBCtx(ctx).
+			NewDelete().
+			Model(new(dashboardtypes.StorableRevision)).
+			Where("org_id = ?", revision.OrgID).
+			Where("dashboard_id = ?", revision.DashboardID).
+			Where("version <= ?", revision.Version-retention).
+			Exec(ctx)
+		return err
+	})
+}
+
+func (store *store) GetRevision(ctx context.Context, orgID valuer.UUID, dashboardID valuer.UUID, version int) (*dashboardtypes.StorableRevision, error) {
+	revision := new(dashboardtypes.StorableRevision)
+	err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewSelect().
+		Model(revision).
+		Where("org_id = ?", orgID).
+		Where("dashboard_id = ?", dashboardID).
+		Where("version = ?", version).
+		Scan(ctx)
+	if err != nil {
+		return nil, store.sqlstore.WrapNotFoundErrf(err, dashboardtypes.ErrCodeRevisionNotFound, "revision %d of dashboard with id %s doesn't exist", version, dashboardID)
+	}
+
+	return revision, nil
+}
+
+func (store *store) ListRevisions(ctx context.Context, orgID valuer.UUID, dashboardID valuer.UUID) ([]*dashboardtypes.StorableRevision, error) {
+	revisions := make([]*dashboardtypes.StorableRevision, 0)
+	err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewSelect().
+		Model(&revisions).
+		ExcludeColumn("data").
+		Where("org_id = ?", orgID).
+		Where("dashboard_id = ?", dashboardID).
+		Order("version DESC").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+func (store *store) GetLatestVersion(ctx context.Context, orgID valuer.UUID, dashboardID valuer.UUID) (int, error) {
+	var version int
+	err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewSelect().
+		Model(new(dashboardtypes.StorableRevision)).
+		ColumnExpr("COALESCE(MAX(version), 0)").
+		Where("org_id = ?", orgID).
+		Where("dashboard_id = ?", dashboardID).
+		Scan(ctx, &version)
+	if err != nil {
+		return 0, err
+	}
+
+	return version, nil
+}
+
+func (store *store) CreateFolder(ctx context.Context, folder *dashboardtypes.Folder) error {
 	_, err := store.
 		sqlstore.
-		BunDB().
-		NewDelete().
-		Model(new(dashboardtypes.StorableDashboard)).
+		BunDBCtx(ctx).
+		NewInsert().
+		Model(folder).
+		Exec(ctx)
+	if err != nil {
+		return store.sqlstore.WrapAlreadyExistsErrf(err, dashboardtypes.ErrCodeFolderAlreadyExists, "folder with name %s already exists", folder.Name)
+	}
+
+	return nil
+}
+
+func (store *store) GetFolder(ctx context.Context, orgID valuer.UUID, id valuer.UUID) (*dashboardtypes.Folder, error) {
+	folder := new(dashboardtypes.Folder)
+	err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewSelect().
+		Model(folder).
+		Where("org_id = ?", orgID).
 		Where("id = ?", id).
+		Scan(ctx)
+	if err != nil {
+		return nil, store.sqlstore.WrapNotFoundErrf(err, dashboardtypes.ErrCodeFolderNotFound, "folder with id %s doesn't exist", id)
+	}
+
+	return folder, nil
+}
+
+func (store *store) ListFolders(ctx context.Context, orgID valuer.UUID) ([]*dashboardtypes.Folder, error) {
+	folders := make([]*dashboardtypes.Folder, 0)
+	err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewSelect().
+		Model(&folders).
 		Where("org_id = ?", orgID).
+		Order("name ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return folders, nil
+}
+
+func (store *store) UpdateFolder(ctx context.Context, folder *dashboardtypes.Folder) error {
+	_, err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewUpdate().
+		Model(folder).
+		WherePK().
+		Where("org_id = ?", folder.OrgID).
 		Exec(ctx)
 	if err != nil {
-		return store.sqlstore.WrapNotFoundErrf(err, errors.CodeNotFound, "dashboard with id %s doesn't exist", id)
+		return store.sqlstore.WrapAlreadyExistsErrf(err, dashboardtypes.ErrCodeFolderAlreadyExists, "folder with name %s already exists", folder.Name)
 	}
 
 	return nil
 }
+
+func (store *store) DeleteFolder(ctx context.Context, orgID valuer.UUID, id valuer.UUID) error {
+	return store.sqlstore.RunInTxCtx(ctx, nil, func(ctx context.Context) error {
+		count, err := store.
+			sqlstore.
+			BunDBCtx(ctx).
+			NewSelect().
+			Model(new(dashboardtypes.StorableDashboard)).
+			Where("org_id = ?", orgID).
+			Where("folder_id = ?", id).
+			Count(ctx)
+		if err != nil {
+			return err
+		}
+
+		// deleting the folder would open its dashboards to the whole org
+		if count > 0 {
+			return errors.Newf(errors.TypeInvalidInput, dashboardtypes.ErrCodeFolderNotEmpty, "folder with id %s has %d dashboards, move them out of the folder to delete it", id, count)
+		}
+
+		count, err = store.
+			sqlstore.
+			BunDBCtx(ctx).
+			NewSelect().
+			Model(new(dashboardtypes.Folder)).
+			Where("org_id = ?", orgID).
+			Where("parent_id = ?", id).
+			Count(ctx)
+		if err != nil {
+			return err
+		}
+
+		if count > 0 {
+			return errors.Newf(errors.TypeInvalidInput, dashboardtypes.ErrCodeFolderNotEmpty, "folder with id %s has %d folders, move them out of the folder to delete it", id, count)
+		}
+
+		_, err = store.
+			sqlstore.
+			BunDBCtx(ctx).
+			NewDelete().
+			Model(new(dashboardtypes.Grant)).
+			Where("org_id = ?", orgID).
+			Where("resource_type = ?", dashboardtypes.ResourceTypeFolder).
+			Where("resource_id = ?", id).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		_, err = store.
+			sqlstore.
+			BunDBCtx(ctx).
+			NewDelete().
+			Model(new(dashboardtypes.Folder)).
+			Where("org_id = ?", orgID).
+			Where("id = ?", id).
+			Exec(ctx)
+		return err
+	})
+}
+
+func (store *store) ListGrants(ctx context.Context, orgID valuer.UUID) ([]*dashboardtypes.Grant, error) {
+	grants := make([]*dashboardtypes.Grant, 0)
+	err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewSelect().
+		Model(&grants).
+		Where("org_id = ?", orgID).
+		Order("created_at ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return grants, nil
+}
+
+func (store *store) SetGrants(ctx context.Context, orgID valuer.UUID, resourceType dashboardtypes.ResourceType, resourceID valuer.UUID, grants []*dashboardtypes.Grant) error {
+	return store.sqlstore.RunInTxCtx(ctx, nil, func(ctx context.Context) error {
+		_, err := store.
+			sqlstore.
+			BunDBCtx(ctx).
+			NewDelete().
+			Model(new(dashboardtypes.Grant)).
+			Where("org_id = ?", orgID).
+			Where("resource_type = ?", resourceType).
+			Where("resource_id = ?", resourceID).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		if len(grants) == 0 {
+			return nil
+		}
+
+		_, err = store.
+			sqlstore.
+			BunDBCtx(ctx).
+			NewInsert().
+			Model(&grants).
+			Exec(ctx)
+		return err
+	})
+}
+
+func (store *store) ListTeamIDs(ctx context.Context, userID valuer.UUID) ([]valuer.UUID, error) {
+	members := make([]*teamtypes.Member, 0)
+	err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewSelect().
+		Model(&members).
+		Where("user_id = ?", userID).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	teamIDs := make([]valuer.UUID, 0, len(members))
+	for _, member := range members {
+		teamIDs = append(teamIDs, member.TeamID)
+	}
+
+	return teamIDs, nil
+}
+
+func (store *store) RunInTx(ctx context.Context, cb func(ctx context.Context) error) error {
+	return store.sqlstore.RunInTxCtx(ctx, nil, cb)
+}
@@ -0,0 +1,206 @@
+package implprovisioning
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+
+	"github.com/SigNoz/signoz/pkg/alertmanager"
+	"github.com/SigNoz/signoz/pkg/errors"
+	"github.com/SigNoz/signoz/pkg/modules/audit"
+	"github.com/SigNoz/signoz/pkg/modules/dashboard"
+	"github.com/SigNoz/signoz/pkg/modules/provisioning"
+	"github.com/SigNoz/signoz/pkg/modules/savedview"
+	"github.com/SigNoz/signoz/pkg/types/authtypes"
+	"github.com/SigNoz/signoz/pkg/types/provisioningtypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+)
+
+type module struct {
+	store        provisioningtypes.Store
+	dashboard    dashboard.Module
+	savedView    savedview.Module
+	alertmanager alertmanager.Alertmanager
+	audit        audit.Module
+}
+
+func NewModule(store provisioningtypes.Store, dashboard dashboard.Module, savedView savedview.Module, alertmanager alertmanager.Alertmanager, audit audit.Module) provisioning.Module {
+	return &module{
+		store:        store,
+		dashboard:    dashboard,
+		savedView:    savedView,
+		alertmanager: alertmanager,
+		audit:        audit,
+	}
+}
+
+func (module *module) Apply(ctx context.Context, orgID valuer.UUID, bundle *provisioningtypes.Bundle, dryRun bool, rules provisioning.RuleManager) (*provisioningtypes.Plan, error) {
+	if err := bundle.Validate(); err != nil {
+		return nil, err
+	}
+
+	claims, err := authtypes.ClaimsFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	provisioners := module.newProvisioners(claims, rules)
+
+	// the specs of the whole bundle are validated before making any change
+	checksums := map[provisioningtypes.Kind]map[string]string{}
+	for _, kind := range provisioningtypes.Kinds {
+		checksums[kind] = map[string]string{}
+		for _, resource := range bundle.Resources(kind) {
+			if err := provisioners[kind].validate(resource.Spec); err != nil {
+				return nil, wrapf(err, "invalid spec of %s %q", kind.StringValue(), resource.ExternalID)
+			}
+
+			checksum, err := resource.Checksum()
+			if err != nil {
+				return nil, err
+			}
+			checksums[kind][resource.ExternalID] = checksum
+		}
+	}
+
+	managed, err := module.store.List(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	managedByKind := map[provisioningtypes.Kind]map[string]*provisioningtypes.Managed{}
+	for _, kind := range provisioningtypes.Kinds {
+		managedByKind[kind] = map[string]*provisioningtypes.Managed{}
+	}
+	for _, item := range managed {
+		if _, ok := managedByKind[item.Kind]; ok {
+			managedByKind[item.Kind][item.ExternalID] = item
+		}
+	}
+
+	plan := provisioningtypes.NewPlan(dryRun)
+	// the changes to the stores are rolled back when the bundle fails part way, the state the rule manager and the
+	// alertmanager keep in memory catches up on their next sync
+	err = module.store.RunInTx(ctx, func(ctx context.Context) error {
+		for _, kind := range provisioningtypes.Kinds {
+			provisioner := provisioners[kind]
+			for _, resource := range bundle.Resources(kind) {
+				checksum := checksums[kind][resource.ExternalID]
+				existing, ok := managedByKind[kind][resource.ExternalID]
+
+				action := provisioningtypes.ActionCreate
+				resourceID := ""
+				if ok {
+					// the resources deleted since the last apply are created again
+					found, err := provisioner.exists(ctx, orgID, existing.ResourceID)
+					if err != nil {
+						return err
+					}
+
+					if found {
+						resourceID = existing.ResourceID
+						action = provisioningtypes.ActionUpdate
+						if existing.Checksum == checksum {
+							action = provisioningtypes.ActionUnchanged
+						}
+					}
+				}
+
+				change := plan.Add(kind, resource.ExternalID, action, resourceID)
+				if dryRun || action == provisioningtypes.ActionUnchanged {
+					continue
+				}
+
+				if action == provisioningtypes.ActionCreate {
+					resourceID, err = provisioner.create(ctx, orgID, resource.Spec)
+				} else {
+					err = provisioner.update(ctx, orgID, resourceID, resource.Spec)
+				}
+				if err != nil {
+					return wrapf(err, "failed to %s %s %q", action.StringValue(), kind.StringValue(), resource.ExternalID)
+				}
+				change.ResourceID = resourceID
+
+				if existing == nil {
+					existing = provisioningtypes.NewManaged(orgID, kind, resource.ExternalID, resourceID, checksum)
+				} else {
+					existing.Update(resourceID, checksum)
+				}
+
+				if err := module.store.Upsert(ctx, existing); err != nil {
+					return err
+				}
+			}
+		}
+
+		// resources are deleted in the reverse order of their creation so that channels are deleted after their rules
+		kinds := slices.Clone(provisioningtypes.Kinds)
+		slices.Reverse(kinds)
+		for _, kind := range kinds {
+			// the kinds left out of the bundle are not managed by it
+			if !bundle.Declares(kind) {
+				continue
+			}
+
+			externalIDs := make([]string, 0)
+			for externalID := range managedByKind[kind] {
+				if _, ok := checksums[kind][externalID]; !ok {
+					externalIDs = append(externalIDs, externalID)
+				}
+			}
+			slices.Sort(externalIDs)
+
+			for _, externalID := range externalIDs {
+				existing := managedByKind[kind][externalID]
+				plan.Add(kind, externalID, provisioningtypes.ActionDelete, existing.ResourceID)
+				if dryRun {
+					continue
+				}
+
+				if err := provisioners[kind].delete(ctx, orgID, existing.ResourceID); err != nil && !isNotFound(err) {
+					return wrapf(err, "failed to delete %s %q", kind.StringValue(), externalID)
+				}
+
+				if err := module.store.Delete(ctx, orgID, kind, externalID); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+func (module *module) ListManaged(ctx context.Context, orgID valuer.UUID) ([]*provisioningtypes.Managed, error) {
+	return module.store.List(ctx, orgID)
+}
+
+func (module *module) CheckUnmanaged(ctx context.Context, orgID valuer.UUID, kind provisioningtypes.Kind, resourceID string) error {
+	managed, err := module.store.GetByResourceID(ctx, orgID, kind, resourceID)
+	if err != nil {
+		if errors.Ast(err, errors.TypeNotFound) {
+			return nil
+		}
+
+		return err
+	}
+
+	return provisioningtypes.NewErrManaged(managed)
+}
+
+// wrapf prefixes the message of the error with the resource of the bundle it failed on, keeping its type and code.
+func wrapf(err error, format string, args ...any) error {
+	t, c, m, _, u, a := errors.Unwrapb(err)
+	return errors.Wrapf(err, t, c, "%s: %s", fmt.Sprintf(format, args...), m).WithUrl(u).WithAdditional(a...)
+}
+
+// isNotFound reports whether the resource no longer exists, not all the modules wrap the errors of their stores.
+func isNotFound(err error) bool {
+	return errors.Ast(err, errors.TypeNotFound) || errors.Is(err, sql.ErrNoRows)
+}
@@ -0,0 +1,230 @@
+package implprovisioning
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/alertmanager"
+	"github.com/SigNoz/signoz/pkg/alertmanager/alertmanagerserver"
+	"github.com/SigNoz/signoz/pkg/alertmanager/signozalertmanager"
+	"github.com/SigNoz/signoz/pkg/analytics/analyticstest"
+	"github.com/SigNoz/signoz/pkg/errors"
+	"github.com/SigNoz/signoz/pkg/factory/factorytest"
+	"github.com/SigNoz/signoz/pkg/modules/audit/implaudit"
+	"github.com/SigNoz/signoz/pkg/modules/dashboard/impldashboard"
+	"github.com/SigNoz/signoz/pkg/modules/organization/implorganization"
+	"github.com/SigNoz/signoz/pkg/modules/preference/implpreference"
+	"github.com/SigNoz/signoz/pkg/modules/savedview/implsavedview"
+	"github.com/SigNoz/signoz/pkg/query-service/utils"
+	"github.com/SigNoz/signoz/pkg/sharder"
+	"github.com/SigNoz/signoz/pkg/sharder/noopsharder"
+	"github.com/SigNoz/signoz/pkg/types"
+	"github.com/SigNoz/signoz/pkg/types/authtypes"
+	"github.com/SigNoz/signoz/pkg/types/preferencetypes"
+	"github.com/SigNoz/signoz/pkg/types/provisioningtypes"
+	"github.com/SigNoz/signoz/pkg/types/ruletypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rules keeps the rules in memory, the rule manager needs a running query service.
+type rules map[valuer.UUID]string
+
+func (rules rules) GetRule(_ context.Context, id valuer.UUID) (*ruletypes.GettableRule, error) {
+	if _, ok := rules[id]; !ok {
+		return nil, sql.ErrNoRows
+	}
+
+	return &ruletypes.GettableRule{Id: id.StringValue()}, nil
+}
+
+func (rules rules) CreateRule(_ context.Context, rule string) (*ruletypes.GettableRule, error) {
+	id := valuer.GenerateUUID()
+	rules[id] = rule
+	return &ruletypes.GettableRule{Id: id.StringValue()}, nil
+}
+
+func (rules rules) EditRule(_ context.Context, rule string, id valuer.UUID) error {
+	rules[id] = rule
+	return nil
+}
+
+func (rules rules) DeleteRule(_ context.Context, id string) error {
+	delete(rules, valuer.MustNewUUID(id))
+	return nil
+}
+
+// failingRules fails to create rules, to apply bundles failing part way.
+type failingRules struct {
+	rules
+}
+
+func (failingRules) CreateRule(_ context.Context, _ string) (*ruletypes.GettableRule, error) {
+	return nil, errors.Newf(errors.TypeInternal, errors.CodeInternal, "rule manager is down")
+}
+
+func newDashboardSpec(title string) json.RawMessage {
+	return json.RawMessage(`{"title": "` + title + `", "version": "v4", "widgets": [{"id": "a", "panelTypes": "row"}, {"id": "b", "panelTypes": "row"}], "layout": [{"i": "a", "x": 0, "y": 0, "w": 12, "h": 1}, {"i": "b", "x": 0, "y": 1, "w": 12, "h": 1}]}`)
+}
+
+func TestModuleApply(t *testing.T) {
+	sqlStore := utils.NewQueryServiceDBForTests(t)
+	require.NoError(t, utils.CreateTestOrg(t, sqlStore))
+	orgID, err := utils.GetTestOrgId(sqlStore)
+	require.NoError(t, err)
+
+	ctx := authtypes.NewContextWithClaims(context.Background(), authtypes.Claims{UserID: valuer.GenerateUUID().StringValue(), Email: "ci@example.com", OrgID: orgID.StringValue(), Role: types.RoleAdmin})
+
+	providerSettings := factorytest.NewSettings()
+	noop, err := noopsharder.New(ctx, providerSettings, sharder.Config{})
+	require.NoError(t, err)
+	am, err := signozalertmanager.New(ctx, providerSettings, alertmanager.Config{Provider: "signoz", Signoz: alertmanager.Signoz{PollInterval: 10 * time.Second, Config: alertmanagerserver.NewConfig()}}, sqlStore, implorganization.NewGetter(implorganization.NewStore(sqlStore), noop))
+	require.NoError(t, err)
+	require.NoError(t, am.SetDefaultConfig(ctx, orgID.StringValue()))
+
+	audit := implaudit.NewModule(implaudit.NewStore(sqlStore), providerSettings)
+	dashboard := impldashboard.NewModule(sqlStore, providerSettings, analyticstest.New(), audit)
+	preference := implpreference.NewModule(implpreference.NewStore(sqlStore), preferencetypes.NewAvailablePreference())
+	savedView := implsavedview.NewModule(implsavedview.NewStore(sqlStore), preference, providerSettings, audit)
+	module := NewModule(NewStore(sqlStore), dashboard, savedView, am, audit)
+	ruleManager := rules{}
+
+	bundle := &provisioningtypes.Bundle{
+		Channels:   []*provisioningtypes.Resource{{ExternalID: "payments-webhook", Spec: json.RawMessage(`{"name": "payments-webhook", "webhook_configs": [{"url": "https://example.com/hook"}]}`)}},
+		Rules:      []*provisioningtypes.Resource{{ExternalID: "payments/latency", Spec: json.RawMessage(`{"alert": "latency", "expr": "up == 0"}`)}},
+		Dashboards: []*provisioningtypes.Resource{{ExternalID: "payments/overview.json", Spec: newDashboardSpec("payments")}},
+		SavedViews: []*provisioningtypes.Resource{{ExternalID: "payments/errors", Spec: json.RawMessage(`{"name": "errors", "sourcePage": "logs", "panelType": "list", "compositeQuery": {"queries": [{"type": "clickhouse_sql", "spec": {"name": "A", "query": "SELECT 1"}}]}}`)}},
+	}
+
+	// dry runs plan the changes without making them
+	plan, err := module.Apply(ctx, orgID, bundle, true, ruleManager)
+	require.NoError(t, err)
+	assert.True(t, plan.DryRun)
+	require.Len(t, plan.Changes, 4)
+	for _, change := range plan.Changes {
+		assert.Equal(t, provisioningtypes.ActionCreate, change.Action)
+	}
+	assert.Equal(t, provisioningtypes.KindChannel, plan.Changes[0].Kind)
+	managed, err := module.ListManaged(ctx, orgID)
+	require.NoError(t, err)
+	assert.Empty(t, managed)
+	assert.Empty(t, ruleManager)
+
+	plan, err = module.Apply(ctx, orgID, bundle, false, ruleManager)
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 4)
+	dashboardID := plan.Changes[2].ResourceID
+	require.NotEmpty(t, dashboardID)
+	assert.Len(t, ruleManager, 1)
+
+	created, err := dashboard.Get(ctx, orgID, valuer.MustNewUUID(dashboardID))
+	require.NoError(t, err)
+	assert.True(t, created.Locked)
+
+	// managed resources are read-only outside of their bundle
+	err = module.CheckUnmanaged(ctx, orgID, provisioningtypes.KindDashboard, dashboardID)
+	assert.True(t, errors.Asc(err, provisioningtypes.ErrCodeResourceManaged))
+	assert.NoError(t, module.CheckUnmanaged(ctx, orgID, provisioningtypes.KindDashboard, valuer.GenerateUUID().StringValue()))
+
+	// applying the same bundle changes nothing, the dashboard is replaced as a whole when changed
+	bundle.Dashboards[0].Spec = json.RawMessage(`{"title": "payments", "version": "v4"}`)
+	plan, err = module.Apply(ctx, orgID, bundle, false, ruleManager)
+	require.NoError(t, err)
+	actions := map[provisioningtypes.Kind]provisioningtypes.Action{}
+	for _, change := range plan.Changes {
+		actions[change.Kind] = change.Action
+	}
+	assert.Equal(t, map[provisioningtypes.Kind]provisioningtypes.Action{
+		provisioningtypes.KindChannel:   provisioningtypes.ActionUnchanged,
+		provisioningtypes.KindRule:      provisioningtypes.ActionUnchanged,
+		provisioningtypes.KindDashboard: provisioningtypes.ActionUpdate,
+		provisioningtypes.KindSavedView: provisioningtypes.ActionUnchanged,
+	}, actions)
+
+	updated, err := dashboard.Get(ctx, orgID, valuer.MustNewUUID(dashboardID))
+	require.NoError(t, err)
+	assert.True(t, updated.Locked)
+	assert.Empty(t, updated.Data.GetWidgetIds())
+	revisions, err := dashboard.ListRevisions(ctx, orgID, valuer.MustNewUUID(dashboardID))
+	require.NoError(t, err)
+	assert.Equal(t, revisionMessage, revisions[0].Message)
+
+	// the resources deleted outside of the bundle are created again
+	require.NoError(t, ruleManager.DeleteRule(ctx, plan.Changes[1].ResourceID))
+	plan, err = module.Apply(ctx, orgID, bundle, true, ruleManager)
+	require.NoError(t, err)
+	assert.Equal(t, provisioningtypes.ActionCreate, plan.Changes[1].Action)
+
+	// invalid specs fail the whole bundle
+	_, err = module.Apply(ctx, orgID, &provisioningtypes.Bundle{Dashboards: []*provisioningtypes.Resource{{ExternalID: "broken", Spec: json.RawMessage(`{"version": "v4"}`)}}}, false, ruleManager)
+	assert.True(t, errors.Ast(err, errors.TypeInvalidInput))
+
+	// the kinds left out of the bundle are left alone
+	plan, err = module.Apply(ctx, orgID, &provisioningtypes.Bundle{}, false, ruleManager)
+	require.NoError(t, err)
+	assert.Empty(t, plan.Changes)
+	managed, err = module.ListManaged(ctx, orgID)
+	require.NoError(t, err)
+	assert.Len(t, managed, 4)
+
+	// the resources removed from the bundle are deleted
+	empty := []*provisioningtypes.Resource{}
+	plan, err = module.Apply(ctx, orgID, &provisioningtypes.Bundle{Channels: empty, Rules: empty, Dashboards: empty, SavedViews: empty}, false, ruleManager)
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 4)
+	assert.Equal(t, provisioningtypes.KindSavedView, plan.Changes[0].Kind)
+	for _, change := range plan.Changes {
+		assert.Equal(t, provisioningtypes.ActionDelete, change.Action)
+	}
+
+	_, err = dashboard.Get(ctx, orgID, valuer.MustNewUUID(dashboardID))
+	assert.True(t, errors.Ast(err, errors.TypeNotFound))
+	channels, err := am.ListChannels(ctx, orgID.StringValue())
+	require.NoError(t, err)
+	assert.Empty(t, channels)
+	managed, err = module.ListManaged(ctx, orgID)
+	require.NoError(t, err)
+	assert.Empty(t, managed)
+}
+
+func TestModuleApplyRollback(t *testing.T) {
+	sqlStore := utils.NewQueryServiceDBForTests(t)
+	require.NoError(t, utils.CreateTestOrg(t, sqlStore))
+	orgID, err := utils.GetTestOrgId(sqlStore)
+	require.NoError(t, err)
+
+	ctx := authtypes.NewContextWithClaims(context.Background(), authtypes.Claims{UserID: valuer.GenerateUUID().StringValue(), Email: "ci@example.com", OrgID: orgID.StringValue(), Role: types.RoleAdmin})
+
+	providerSettings := factorytest.NewSettings()
+	noop, err := noopsharder.New(ctx, providerSettings, sharder.Config{})
+	require.NoError(t, err)
+	am, err := signozalertmanager.New(ctx, providerSettings, alertmanager.Config{Provider: "signoz", Signoz: alertmanager.Signoz{PollInterval: 10 * time.Second, Config: alertmanagerserver.NewConfig()}}, sqlStore, implorganization.NewGetter(implorganization.NewStore(sqlStore), noop))
+	require.NoError(t, err)
+	require.NoError(t, am.SetDefaultConfig(ctx, orgID.StringValue()))
+
+	audit := implaudit.NewModule(implaudit.NewStore(sqlStore), providerSettings)
+	dashboard := impldashboard.NewModule(sqlStore, providerSettings, analyticstest.New(), audit)
+	preference := implpreference.NewModule(implpreference.NewStore(sqlStore), preferencetypes.NewAvailablePreference())
+	savedView := implsavedview.NewModule(implsavedview.NewStore(sqlStore), preference, providerSettings, audit)
+	module := NewModule(NewStore(sqlStore), dashboard, savedView, am, audit)
+
+	bundle := &provisioningtypes.Bundle{
+		Channels: []*provisioningtypes.Resource{{ExternalID: "payments-webhook", Spec: json.RawMessage(`{"name": "payments-webhook", "webhook_configs": [{"url": "https://example.com/hook"}]}`)}},
+		Rules:    []*provisioningtypes.Resource{{ExternalID: "payments/latency", Spec: json.RawMessage(`{"alert": "latency", "expr": "up == 0"}`)}},
+	}
+
+	// the channel created before the rule failed is rolled back
+	_, err = module.Apply(ctx, orgID, bundle, false, failingRules{rules: rules{}})
+	require.Error(t, err)
+
+	channels, err := am.ListChannels(ctx, orgID.StringValue())
+	require.NoError(t, err)
+	assert.Empty(t, channels)
+	managed, err := module.ListManaged(ctx, orgID)
+	require.NoError(t, err)
+	assert.Empty(t, managed)
+}
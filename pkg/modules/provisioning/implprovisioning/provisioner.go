@@ -0,0 +1,335 @@
+package implprovisioning
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/SigNoz/signoz/pkg/errors"
+	"github.com/SigNoz/signoz/pkg/modules/provisioning"
+	"github.com/SigNoz/signoz/pkg/types/alertmanagertypes"
+	"github.com/SigNoz/signoz/pkg/types/audittypes"
+	"github.com/SigNoz/signoz/pkg/types/authtypes"
+	"github.com/SigNoz/signoz/pkg/types/dashboardtypes"
+	"github.com/SigNoz/signoz/pkg/types/provisioningtypes"
+	"github.com/SigNoz/signoz/pkg/types/ruletypes"
+	"github.com/SigNoz/signoz/pkg/types/savedviewtypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+)
+
+// message of the revisions of the dashboards updated by bundles
+const revisionMessage string = "Applied from bundle"
+
+// provisioner makes the changes of a bundle to the resources of a kind through the module managing them, the specs are
+// the bodies of the requests creating the resources.
+type provisioner interface {
+	validate(spec json.RawMessage) error
+	exists(ctx context.Context, orgID valuer.UUID, id string) (bool, error)
+	create(ctx context.Context, orgID valuer.UUID, spec json.RawMessage) (string, error)
+	update(ctx context.Context, orgID valuer.UUID, id string, spec json.RawMessage) error
+	delete(ctx context.Context, orgID valuer.UUID, id string) error
+}
+
+func (module *module) newProvisioners(claims authtypes.Claims, rules provisioning.RuleManager) map[provisioningtypes.Kind]provisioner {
+	return map[provisioningtypes.Kind]provisioner{
+		provisioningtypes.KindChannel:   &channelProvisioner{module: module},
+		provisioningtypes.KindRule:      &ruleProvisioner{rules: rules},
+		provisioningtypes.KindDashboard: &dashboardProvisioner{module: module, claims: claims},
+		provisioningtypes.KindSavedView: &savedViewProvisioner{module: module, claims: claims},
+	}
+}
+
+// exists reports whether the error of a get is the resource not being found.
+func exists(err error) (bool, error) {
+	if err == nil {
+		return true, nil
+	}
+
+	if isNotFound(err) {
+		return false, nil
+	}
+
+	return false, err
+}
+
+func newErrInvalidSpec(err error) error {
+	if errors.Ast(err, errors.TypeInvalidInput) {
+		return err
+	}
+
+	return errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "%s", err.Error())
+}
+
+type dashboardProvisioner struct {
+	module *module
+	claims authtypes.Claims
+}
+
+func (provisioner *dashboardProvisioner) parse(spec json.RawMessage) (dashboardtypes.StorableDashboardData, error) {
+	data := dashboardtypes.StorableDashboardData{}
+	if err := json.Unmarshal(spec, &data); err != nil {
+		return nil, newErrInvalidSpec(err)
+	}
+
+	upgraded, err := data.Upgrade(dashboardtypes.MinSchemaVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := upgraded.Validate(); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (provisioner *dashboardProvisioner) validate(spec json.RawMessage) error {
+	_, err := provisioner.parse(spec)
+	return err
+}
+
+func (provisioner *dashboardProvisioner) exists(ctx context.Context, orgID valuer.UUID, id string) (bool, error) {
+	dashboardID, err := valuer.NewUUID(id)
+	if err != nil {
+		return false, nil
+	}
+
+	_, err = provisioner.module.dashboard.Get(ctx, orgID, dashboardID)
+	return exists(err)
+}
+
+// create creates the dashboard locked, managed dashboards are only changed by bundles.
+func (provisioner *dashboardProvisioner) create(ctx context.Context, orgID valuer.UUID, spec json.RawMessage) (string, error) {
+	data, err := provisioner.parse(spec)
+	if err != nil {
+		return "", err
+	}
+
+	creator, err := valuer.NewUUID(provisioner.claims.UserID)
+	if err != nil {
+		return "", err
+	}
+
+	dashboard, err := provisioner.module.dashboard.Create(ctx, orgID, provisioner.claims.Email, creator, data)
+	if err != nil {
+		return "", err
+	}
+
+	if err := provisioner.module.dashboard.LockUnlock(ctx, orgID, valuer.MustNewUUID(dashboard.ID), provisioner.claims.Email, true); err != nil {
+		return "", err
+	}
+
+	return dashboard.ID, nil
+}
+
+func (provisioner *dashboardProvisioner) update(ctx context.Context, orgID valuer.UUID, id string, spec json.RawMessage) error {
+	data, err := provisioner.parse(spec)
+	if err != nil {
+		return err
+	}
+
+	_, err = provisioner.module.dashboard.Replace(ctx, orgID, valuer.MustNewUUID(id), provisioner.claims.Email, data, revisionMessage)
+	return err
+}
+
+func (provisioner *dashboardProvisioner) delete(ctx context.Context, orgID valuer.UUID, id string) error {
+	dashboardID, err := valuer.NewUUID(id)
+	if err != nil {
+		return err
+	}
+
+	if err := provisioner.module.dashboard.LockUnlock(ctx, orgID, dashboardID, provisioner.claims.Email, false); err != nil {
+		return err
+	}
+
+	return provisioner.module.dashboard.Delete(ctx, orgID, dashboardID)
+}
+
+type savedViewProvisioner struct {
+	module *module
+	claims authtypes.Claims
+}
+
+func (provisioner *savedViewProvisioner) parse(spec json.RawMessage) (*savedviewtypes.PostableSavedView, error) {
+	view := new(savedviewtypes.PostableSavedView)
+	if err := json.Unmarshal(spec, view); err != nil {
+		return nil, newErrInvalidSpec(err)
+	}
+
+	if err := view.Validate(); err != nil {
+		return nil, newErrInvalidSpec(err)
+	}
+
+	return view, nil
+}
+
+func (provisioner *savedViewProvisioner) validate(spec json.RawMessage) error {
+	_, err := provisioner.parse(spec)
+	return err
+}
+
+func (provisioner *savedViewProvisioner) exists(ctx context.Context, orgID valuer.UUID, id string) (bool, error) {
+	viewID, err := valuer.NewUUID(id)
+	if err != nil {
+		return false, nil
+	}
+
+	_, err = provisioner.module.savedView.Get(ctx, orgID, viewID)
+	return exists(err)
+}
+
+func (provisioner *savedViewProvisioner) create(ctx context.Context, orgID valuer.UUID, spec json.RawMessage) (string, error) {
+	postable, err := provisioner.parse(spec)
+	if err != nil {
+		return "", err
+	}
+
+	view, err := provisioner.module.savedView.Create(ctx, orgID, provisioner.claims.Email, postable)
+	if err != nil {
+		return "", err
+	}
+
+	return view.ID.StringValue(), nil
+}
+
+func (provisioner *savedViewProvisioner) update(ctx context.Context, orgID valuer.UUID, id string, spec json.RawMessage) error {
+	updatable, err := provisioner.parse(spec)
+	if err != nil {
+		return err
+	}
+
+	_, err = provisioner.module.savedView.Update(ctx, orgID, valuer.MustNewUUID(id), provisioner.claims.Email, updatable)
+	return err
+}
+
+func (provisioner *savedViewProvisioner) delete(ctx context.Context, orgID valuer.UUID, id string) error {
+	viewID, err := valuer.NewUUID(id)
+	if err != nil {
+		return err
+	}
+
+	return provisioner.module.savedView.Delete(ctx, orgID, viewID)
+}
+
+// channelProvisioner records the changes to the channels in the audit log, the alertmanager leaves it to its api.
+type channelProvisioner struct {
+	module *module
+}
+
+func (provisioner *channelProvisioner) parse(spec json.RawMessage) (alertmanagertypes.Receiver, error) {
+	receiver, err := alertmanagertypes.NewReceiver(string(spec))
+	if err != nil {
+		return receiver, newErrInvalidSpec(err)
+	}
+
+	return receiver, nil
+}
+
+func (provisioner *channelProvisioner) validate(spec json.RawMessage) error {
+	_, err := provisioner.parse(spec)
+	return err
+}
+
+func (provisioner *channelProvisioner) exists(ctx context.Context, orgID valuer.UUID, id string) (bool, error) {
+	channelID, err := valuer.NewUUID(id)
+	if err != nil {
+		return false, nil
+	}
+
+	_, err = provisioner.module.alertmanager.GetChannelByID(ctx, orgID.StringValue(), channelID)
+	return exists(err)
+}
+
+func (provisioner *channelProvisioner) create(ctx context.Context, orgID valuer.UUID, spec json.RawMessage) (string, error) {
+	receiver, err := provisioner.parse(spec)
+	if err != nil {
+		return "", err
+	}
+
+	channel, err := provisioner.module.alertmanager.CreateChannel(ctx, orgID.StringValue(), receiver)
+	if err != nil {
+		return "", err
+	}
+
+	provisioner.module.audit.Record(ctx, orgID, audittypes.ActionCreate, audittypes.NewResource(audittypes.ResourceTypeChannel, channel.ID.StringValue()), nil, spec)
+	return channel.ID.StringValue(), nil
+}
+
+func (provisioner *channelProvisioner) update(ctx context.Context, orgID valuer.UUID, id string, spec json.RawMessage) error {
+	receiver, err := provisioner.parse(spec)
+	if err != nil {
+		return err
+	}
+
+	channelID := valuer.MustNewUUID(id)
+	before, err := provisioner.module.alertmanager.GetChannelByID(ctx, orgID.StringValue(), channelID)
+	if err != nil {
+		return err
+	}
+
+	if err := provisioner.module.alertmanager.UpdateChannelByReceiverAndID(ctx, orgID.StringValue(), receiver, channelID); err != nil {
+		return err
+	}
+
+	provisioner.module.audit.Record(ctx, orgID, audittypes.ActionUpdate, audittypes.NewResource(audittypes.ResourceTypeChannel, id), json.RawMessage(before.Data), spec)
+	return nil
+}
+
+func (provisioner *channelProvisioner) delete(ctx context.Context, orgID valuer.UUID, id string) error {
+	channelID, err := valuer.NewUUID(id)
+	if err != nil {
+		return err
+	}
+
+	before, err := provisioner.module.alertmanager.GetChannelByID(ctx, orgID.StringValue(), channelID)
+	if err != nil {
+		return err
+	}
+
+	if err := provisioner.module.alertmanager.DeleteChannelByID(ctx, orgID.StringValue(), channelID); err != nil {
+		return err
+	}
+
+	provisioner.module.audit.Record(ctx, orgID, audittypes.ActionDelete, audittypes.NewResource(audittypes.ResourceTypeChannel, id), json.RawMessage(before.Data), nil)
+	return nil
+}
+
+// ruleProvisioner changes the rules of the org of the claims of the context, the rule manager records them in the
+// audit log.
+type ruleProvisioner struct {
+	rules provisioning.RuleManager
+}
+
+func (provisioner *ruleProvisioner) validate(spec json.RawMessage) error {
+	if _, err := ruletypes.ParsePostableRule(spec); err != nil {
+		return newErrInvalidSpec(err)
+	}
+
+	return nil
+}
+
+func (provisioner *ruleProvisioner) exists(ctx context.Context, _ valuer.UUID, id string) (bool, error) {
+	ruleID, err := valuer.NewUUID(id)
+	if err != nil {
+		return false, nil
+	}
+
+	_, err = provisioner.rules.GetRule(ctx, ruleID)
+	return exists(err)
+}
+
+func (provisioner *ruleProvisioner) create(ctx context.Context, _ valuer.UUID, spec json.RawMessage) (string, error) {
+	rule, err := provisioner.rules.CreateRule(ctx, string(spec))
+	if err != nil {
+		return "", err
+	}
+
+	return rule.Id, nil
+}
+
+func (provisioner *ruleProvisioner) update(ctx context.Context, _ valuer.UUID, id string, spec json.RawMessage) error {
+	return provisioner.rules.EditRule(ctx, string(spec), valuer.MustNewUUID(id))
+}
+
+func (provisioner *ruleProvisioner) delete(ctx context.Context, _ valuer.UUID, id string) error {
+	return provisioner.rules.DeleteRule(ctx, id)
+}
@@ -0,0 +1,92 @@
+package implprovisioning
+
+import (
+	"context"
+
+	"github.com/SigNoz/signoz/pkg/errors"
+	"github.com/SigNoz/signoz/pkg/sqlstore"
+	"github.com/SigNoz/signoz/pkg/types/provisioningtypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+)
+
+type store struct {
+	sqlstore sqlstore.SQLStore
+}
+
+func NewStore(sqlstore sqlstore.SQLStore) provisioningtypes.Store {
+	return &store{sqlstore: sqlstore}
+}
+
+func (store *store) List(ctx context.Context, orgID valuer.UUID) ([]*provisioningtypes.Managed, error) {
+	managed := make([]*provisioningtypes.Managed, 0)
+	err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewSelect().
+		Model(&managed).
+		Where("org_id = ?", orgID).
+		Order("kind ASC", "external_id ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return managed, nil
+}
+
+func (store *store) GetByResourceID(ctx context.Context, orgID valuer.UUID, kind provisioningtypes.Kind, resourceID string) (*provisioningtypes.Managed, error) {
+	managed := new(provisioningtypes.Managed)
+	err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewSelect().
+		Model(managed).
+		Where("org_id = ?", orgID).
+		Where("kind = ?", kind).
+		Where("resource_id = ?", resourceID).
+		Scan(ctx)
+	if err != nil {
+		return nil, store.sqlstore.WrapNotFoundErrf(err, errors.CodeNotFound, "%s: %s is not managed by a bundle", kind.StringValue(), resourceID)
+	}
+
+	return managed, nil
+}
+
+func (store *store) Upsert(ctx context.Context, managed *provisioningtypes.Managed) error {
+	_, err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewInsert().
+		Model(managed).
+		On("CONFLICT (org_id, kind, external_id) DO UPDATE").
+		Set("resource_id = EXCLUDED.resource_id").
+		Set("checksum = EXCLUDED.checksum").
+		Set("updated_at = EXCLUDED.updated_at").
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (store *store) Delete(ctx context.Context, orgID valuer.UUID, kind provisioningtypes.Kind, externalID string) error {
+	_, err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewDelete().
+		Model(new(provisioningtypes.Managed)).
+		Where("org_id = ?", orgID).
+		Where("kind = ?", kind).
+		Where("external_id = ?", externalID).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (store *store) RunInTx(ctx context.Context, cb func(ctx context.Context) error) error {
+	return store.sqlstore.RunInTxCtx(ctx, nil, cb)
+}
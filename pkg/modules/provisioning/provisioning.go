@@ -0,0 +1,38 @@
+package provisioning
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/SigNoz/signoz/pkg/types/provisioningtypes"
+	"github.com/SigNoz/signoz/pkg/types/ruletypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+)
+
+type Module interface {
+	// Apply reconciles the resources of the org with the bundle in a transaction, the resources of the bundle are
+	// created or updated and the managed resources of the kinds it declares but no longer in it are deleted. The plan of
+	// a dry run is computed without making any change.
+	Apply(ctx context.Context, orgID valuer.UUID, bundle *provisioningtypes.Bundle, dryRun bool, rules RuleManager) (*provisioningtypes.Plan, error)
+
+	// ListManaged lists the resources of the org managed by bundles
+	ListManaged(ctx context.Context, orgID valuer.UUID) ([]*provisioningtypes.Managed, error)
+
+	// CheckUnmanaged returns a forbidden error when the resource is managed by a bundle
+	CheckUnmanaged(ctx context.Context, orgID valuer.UUID, kind provisioningtypes.Kind, resourceID string) error
+}
+
+// RuleManager manages the rules of the org of the claims of the context.
+type RuleManager interface {
+	GetRule(ctx context.Context, id valuer.UUID) (*ruletypes.GettableRule, error)
+
+	CreateRule(ctx context.Context, rule string) (*ruletypes.GettableRule, error)
+
+	EditRule(ctx context.Context, rule string, id valuer.UUID) error
+
+	DeleteRule(ctx context.Context, id string) error
+}
+
+type Handler interface {
+	ListManaged(http.ResponseWriter, *http.Request)
+}
@@ -0,0 +1,453 @@
+package implreport
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/emailing"
+	"github.com/SigNoz/signoz/pkg/errors"
+	"github.com/SigNoz/signoz/pkg/factory"
+	"github.com/SigNoz/signoz/pkg/modules/audit"
+	"github.com/SigNoz/signoz/pkg/modules/dashboard"
+	"github.com/SigNoz/signoz/pkg/modules/organization"
+	"github.com/SigNoz/signoz/pkg/modules/report"
+	"github.com/SigNoz/signoz/pkg/modules/user"
+	"github.com/SigNoz/signoz/pkg/querier"
+	"github.com/SigNoz/signoz/pkg/types/audittypes"
+	"github.com/SigNoz/signoz/pkg/types/dashboardtypes"
+	"github.com/SigNoz/signoz/pkg/types/emailtypes"
+	qbtypes "github.com/SigNoz/signoz/pkg/types/querybuildertypes/querybuildertypesv5"
+	"github.com/SigNoz/signoz/pkg/types/reporttypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+)
+
+// interval is how often the due reports are looked up, it is the resolution of the schedules.
+const interval = time.Minute
+
+var slugRegex = regexp.MustCompile(`[^a-z0-9]+`)
+
+type module struct {
+	store     reporttypes.Store
+	dashboard dashboard.Module
+	querier   querier.Querier
+	emailing  emailing.Emailing
+	orgGetter organization.Getter
+	user      user.Module
+	audit     audit.Module
+	settings  factory.ScopedProviderSettings
+	stopC     chan struct{}
+}
+
+// emailPanel is a panel of the email of a report, the chart is an inline attachment.
+type emailPanel struct {
+	Title  string
+	Chart  template.URL
+	Min    string
+	Max    string
+	Tables []*emailTable
+	Error  string
+}
+
+type emailTable struct {
+	Columns []string
+	Rows    []*emailRow
+	Omitted int
+}
+
+type emailRow struct {
+	// the color of the series of the row on the chart of the panel
+	Color template.CSS
+	Cells []string
+}
+
+func NewModule(store reporttypes.Store, dashboard dashboard.Module, querier querier.Querier, emailing emailing.Emailing, orgGetter organization.Getter, user user.Module, providerSettings factory.ProviderSettings, audit audit.Module) report.Module {
+	settings := factory.NewScopedProviderSettings(providerSettings, "github.com/SigNoz/signoz/pkg/modules/report/implreport")
+	return &module{
+		store:     store,
+		dashboard: dashboard,
+		querier:   querier,
+		emailing:  emailing,
+		orgGetter: orgGetter,
+		user:      user,
+		audit:     audit,
+		settings:  settings,
+		stopC:     make(chan struct{}),
+	}
+}
+
+func (module *module) Start(ctx context.Context) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-module.stopC:
+			return nil
+		case <-ticker.C:
+			if err := module.SendDue(ctx); err != nil {
+				module.settings.Logger().ErrorContext(ctx, "failed to send due reports", "error", err)
+			}
+		}
+	}
+}
+
+func (module *module) Stop(ctx context.Context) error {
+	close(module.stopC)
+	return nil
+}
+
+func (module *module) Create(ctx context.Context, orgID valuer.UUID, createdBy string, postable *reporttypes.PostableReport) (*reporttypes.Report, error) {
+	report, err := reporttypes.NewReport(orgID, createdBy, postable, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if _, _, err := module.getPanels(ctx, report); err != nil {
+		return nil, err
+	}
+
+	if err := module.store.Create(ctx, report); err != nil {
+		return nil, err
+	}
+
+	module.audit.Record(ctx, orgID, audittypes.ActionCreate, audittypes.NewResource(audittypes.ResourceTypeReport, report.ID.StringValue()), nil, report)
+	return report, nil
+}
+
+func (module *module) Get(ctx context.Context, orgID valuer.UUID, id valuer.UUID) (*reporttypes.Report, error) {
+	return module.store.Get(ctx, orgID, id)
+}
+
+func (module *module) List(ctx context.Context, orgID valuer.UUID) ([]*reporttypes.Report, error) {
+	return module.store.List(ctx, orgID)
+}
+
+func (module *module) Update(ctx context.Context, orgID valuer.UUID, id valuer.UUID, updatedBy string, postable *reporttypes.PostableReport) (*reporttypes.Report, error) {
+	report, err := module.store.Get(ctx, orgID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	before := *report
+	if err := report.Update(updatedBy, postable, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if _, _, err := module.getPanels(ctx, report); err != nil {
+		return nil, err
+	}
+
+	if err := module.store.Update(ctx, report); err != nil {
+		return nil, err
+	}
+
+	module.audit.Record(ctx, orgID, audittypes.ActionUpdate, audittypes.NewResource(audittypes.ResourceTypeReport, id.StringValue()), &before, report)
+	return report, nil
+}
+
+func (module *module) Delete(ctx context.Context, orgID valuer.UUID, id valuer.UUID) error {
+	report, err := module.store.Get(ctx, orgID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := module.store.Delete(ctx, orgID, id); err != nil {
+		return err
+	}
+
+	module.audit.Record(ctx, orgID, audittypes.ActionDelete, audittypes.NewResource(audittypes.ResourceTypeReport, id.StringValue()), report, nil)
+	return nil
+}
+
+func (module *module) ListDeliveries(ctx context.Context, orgID valuer.UUID, id valuer.UUID) ([]*reporttypes.Delivery, error) {
+	if _, err := module.store.Get(ctx, orgID, id); err != nil {
+		return nil, err
+	}
+
+	return module.store.ListDeliveries(ctx, orgID, id)
+}
+
+func (module *module) Send(ctx context.Context, orgID valuer.UUID, id valuer.UUID, recipients []string) (*reporttypes.Delivery, error) {
+	report, err := module.store.Get(ctx, orgID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(recipients) == 0 {
+		recipients = report.Recipients
+	}
+
+	if err := reporttypes.ValidateRecipients(recipients); err != nil {
+		return nil, err
+	}
+
+	// the report is only sent to the addresses it is configured with and to the users of the org
+	for _, recipient := range recipients {
+		if slices.Contains(report.Recipients, recipient) {
+			continue
+		}
+
+		if _, err := module.user.GetUserByEmailInOrg(ctx, orgID.StringValue(), recipient); err != nil {
+			if errors.Ast(err, errors.TypeNotFound) {
+				return nil, errors.Newf(errors.TypeInvalidInput, reporttypes.ErrCodeReportInvalid, "recipient %s is neither a recipient of the report nor a user of the org", recipient)
+			}
+
+			return nil, err
+		}
+	}
+
+	return module.send(ctx, report, reporttypes.TriggerManual, recipients)
+}
+
+func (module *module) SendDue(ctx context.Context) error {
+	orgs, err := module.orgGetter.ListByOwnedKeyRange(ctx)
+	if err != nil {
+		return err
+	}
+
+	orgIDs := make([]valuer.UUID, 0, len(orgs))
+	for _, org := range orgs {
+		orgIDs = append(orgIDs, org.ID)
+	}
+
+	now := time.Now()
+	reports, err := module.store.ListDue(ctx, orgIDs, now)
+	if err != nil {
+		return err
+	}
+
+	for _, report := range reports {
+		// the report is rescheduled before it is sent so that a failing report is retried at its next run only
+		if err := report.Reschedule(now); err != nil {
+			module.settings.Logger().ErrorContext(ctx, "failed to reschedule report", "report_id", report.ID, "org_id", report.OrgID, "error", err)
+			continue
+		}
+
+		// another instance sending the report first moves its next run past now
+		claimed, err := module.store.Claim(ctx, report, now)
+		if err != nil {
+			module.settings.Logger().ErrorContext(ctx, "failed to reschedule report", "report_id", report.ID, "org_id", report.OrgID, "error", err)
+			continue
+		}
+
+		if !claimed {
+			continue
+		}
+
+		// the report is sent on behalf of its creator, it only reports the dashboards the creator can still view
+		creatorCtx, err := module.user.NewContextWithClaimsOf(ctx, report.OrgID.StringValue(), report.CreatedBy)
+		if err != nil {
+			module.settings.Logger().ErrorContext(ctx, "failed to send report on behalf of its creator", "report_id", report.ID, "org_id", report.OrgID, "error", err)
+			continue
+		}
+
+		delivery, err := module.send(creatorCtx, report, reporttypes.TriggerSchedule, report.Recipients)
+		if err != nil {
+			module.settings.Logger().ErrorContext(ctx, "failed to record report delivery", "report_id", report.ID, "org_id", report.OrgID, "error", err)
+			continue
+		}
+
+		if delivery.Status != reporttypes.StatusSuccess {
+			module.settings.Logger().WarnContext(ctx, "report was not fully delivered", "report_id", report.ID, "org_id", report.OrgID, "status", delivery.Status, "error", delivery.Error)
+		}
+	}
+
+	return nil
+}
+
+// send renders the report and emails it to each recipient, the returned delivery records the outcome and is only
+// missing when it could not be stored.
+func (module *module) send(ctx context.Context, report *reporttypes.Report, trigger reporttypes.Trigger, recipients []string) (*reporttypes.Delivery, error) {
+	now := time.Now()
+	delivery := reporttypes.NewDelivery(report, trigger, recipients, now)
+
+	data, attachments, panelErrs, err := module.render(ctx, report, now)
+	if err == nil {
+		errs := make([]error, 0)
+		for _, recipient := range recipients {
+			if err := module.emailing.SendHTML(ctx, recipient, "Report: "+report.Name, emailtypes.TemplateNameReportEmail, data, attachments...); err != nil {
+				errs = append(errs, errors.Wrapf(err, errors.TypeInternal, errors.CodeInternal, "failed to send to %s", recipient))
+			}
+		}
+
+		err = errors.Join(errs...)
+	}
+
+	delivery.Finish(panelErrs, err, time.Now())
+	if err := module.store.CreateDelivery(ctx, delivery); err != nil {
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+// render queries the panels of the report over its time range ending at now and returns the data of the email along
+// with its charts and CSV attachments. Panels failing to query are reported in the email and in the returned panel
+// errors.
+func (module *module) render(ctx context.Context, report *reporttypes.Report, now time.Time) (map[string]any, []emailtypes.Attachment, []error, error) {
+	title, panels, err := module.getPanels(ctx, report)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	start, end := report.Resolve(now)
+	emailPanels := make([]*emailPanel, 0, len(panels))
+	attachments := make([]emailtypes.Attachment, 0)
+	panelErrs := make([]error, 0)
+	for i, panel := range panels {
+		rendered, panelAttachments, err := module.renderPanel(ctx, report, i, panel, start, end)
+		if err != nil {
+			rendered = &emailPanel{Title: panel.Title, Error: reporttypes.ErrorMessage(err)}
+			panelErrs = append(panelErrs, errors.Wrapf(err, errors.TypeInternal, errors.CodeInternal, "%s", panel.Title))
+		}
+
+		emailPanels = append(emailPanels, rendered)
+		attachments = append(attachments, panelAttachments...)
+	}
+
+	location := report.Location()
+	return map[string]any{
+		"Name":     report.Name,
+		"Title":    title,
+		"Start":    time.UnixMilli(int64(start)).In(location).Format(time.RFC1123),
+		"End":      time.UnixMilli(int64(end)).In(location).Format(time.RFC1123),
+		"Panels":   emailPanels,
+		"Attached": report.AttachCSV,
+	}, attachments, panelErrs, nil
+}
+
+func (module *module) renderPanel(ctx context.Context, report *reporttypes.Report, i int, panel *reporttypes.Panel, start uint64, end uint64) (*emailPanel, []emailtypes.Attachment, error) {
+	requestType := panel.PanelType.RequestType()
+	if requestType == qbtypes.RequestTypeDistribution {
+		return nil, nil, errors.Newf(errors.TypeUnsupported, errors.CodeUnsupported, "%s panels cannot be reported", panel.PanelType)
+	}
+
+	response, err := module.querier.QueryRange(ctx, report.OrgID, &qbtypes.QueryRangeRequest{
+		SchemaVersion:  "v5",
+		Start:          start,
+		End:            end,
+		RequestType:    requestType,
+		CompositeQuery: qbtypes.CompositeQuery{Queries: panel.Queries},
+		Variables:      report.Variables,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	results, err := reporttypes.NewResults(response)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rendered := &emailPanel{Title: panel.Title}
+	attachments := make([]emailtypes.Attachment, 0)
+	name := fmt.Sprintf("%02d-%s", i+1, slug(panel.Title))
+
+	if requestType == qbtypes.RequestTypeTimeSeries {
+		chart, err := reporttypes.NewChart(results.Series(), int64(start), int64(end))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		attachments = append(attachments, emailtypes.Attachment{Name: name + ".png", ContentType: "image/png", Data: chart.Image, Inline: true})
+		rendered.Chart = template.URL("cid:" + name + ".png")
+		rendered.Min = fmt.Sprint(chart.Min)
+		rendered.Max = fmt.Sprint(chart.Max)
+	}
+
+	for _, table := range results.Tables() {
+		renderedTable := &emailTable{Columns: table.Columns, Omitted: max(len(table.Rows)-reporttypes.MaxRows, 0)}
+		for j, cells := range table.Rows[:min(len(table.Rows), reporttypes.MaxRows)] {
+			row := &emailRow{Cells: cells}
+			if requestType == qbtypes.RequestTypeTimeSeries && j < reporttypes.MaxChartSeries {
+				row.Color = template.CSS(reporttypes.ChartColor(j))
+			}
+			renderedTable.Rows = append(renderedTable.Rows, row)
+		}
+		rendered.Tables = append(rendered.Tables, renderedTable)
+	}
+
+	if report.AttachCSV {
+		csv, err := results.CSV()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		attachments = append(attachments, emailtypes.Attachment{Name: name + ".csv", ContentType: "text/csv", Data: csv})
+	}
+
+	return rendered, attachments, nil
+}
+
+// getPanels returns the title of the dashboard of the report along with the panels it queries, the queries of the
+// widgets of the dashboard are converted to v5.
+func (module *module) getPanels(ctx context.Context, report *reporttypes.Report) (string, []*reporttypes.Panel, error) {
+	if report.DashboardID == "" {
+		return "", report.Panels, nil
+	}
+
+	id, err := valuer.NewUUID(report.DashboardID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	dashboard, err := module.dashboard.Get(ctx, report.OrgID, id)
+	if err != nil {
+		return "", nil, err
+	}
+
+	spec, err := dashboardtypes.NewSpec(dashboard.Data)
+	if err != nil {
+		return "", nil, err
+	}
+
+	panels := make([]*reporttypes.Panel, 0)
+	found := make(map[string]bool)
+	for _, widget := range spec.Widgets {
+		if widget == nil || widget.Query == nil || (len(report.WidgetIDs) > 0 && !slices.Contains(report.WidgetIDs, widget.ID)) {
+			continue
+		}
+
+		if widget.PanelType == dashboardtypes.PanelTypeRow || widget.PanelType == dashboardtypes.PanelTypeEmptyWidget {
+			continue
+		}
+
+		compositeQuery, err := widget.Query.CompositeQuery()
+		if err != nil {
+			return "", nil, err
+		}
+
+		found[widget.ID] = true
+		panels = append(panels, &reporttypes.Panel{Title: widget.Title, PanelType: widget.PanelType, Queries: compositeQuery.Queries})
+	}
+
+	for _, widgetID := range report.WidgetIDs {
+		if !found[widgetID] {
+			return "", nil, errors.Newf(errors.TypeNotFound, errors.CodeNotFound, "widget: %s does not exist in dashboard: %s or has no queries", widgetID, report.DashboardID)
+		}
+	}
+
+	if len(panels) == 0 {
+		return "", nil, errors.Newf(errors.TypeInvalidInput, reporttypes.ErrCodeReportInvalid, "dashboard: %s has no panels to report", report.DashboardID)
+	}
+
+	if len(panels) > reporttypes.MaxPanels {
+		panels = panels[:reporttypes.MaxPanels]
+	}
+
+	return spec.Title, panels, nil
+}
+
+func slug(title string) string {
+	slug := strings.Trim(slugRegex.ReplaceAllString(strings.ToLower(title), "-"), "-")
+	if slug == "" {
+		return "panel"
+	}
+
+	return slug
+}
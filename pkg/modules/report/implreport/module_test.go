@@ -0,0 +1,274 @@
+package implreport
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/analytics/analyticstest"
+	"github.com/SigNoz/signoz/pkg/emailing/emailingtest"
+	"github.com/SigNoz/signoz/pkg/emailing/templatestore/filetemplatestore"
+	"github.com/SigNoz/signoz/pkg/errors"
+	"github.com/SigNoz/signoz/pkg/factory/factorytest"
+	"github.com/SigNoz/signoz/pkg/modules/audit/implaudit"
+	"github.com/SigNoz/signoz/pkg/modules/dashboard/impldashboard"
+	"github.com/SigNoz/signoz/pkg/modules/organization/implorganization"
+	"github.com/SigNoz/signoz/pkg/modules/user/impluser"
+	"github.com/SigNoz/signoz/pkg/query-service/utils"
+	"github.com/SigNoz/signoz/pkg/sharder"
+	"github.com/SigNoz/signoz/pkg/sharder/noopsharder"
+	"github.com/SigNoz/signoz/pkg/types"
+	"github.com/SigNoz/signoz/pkg/types/authtypes"
+	"github.com/SigNoz/signoz/pkg/types/dashboardtypes"
+	"github.com/SigNoz/signoz/pkg/types/emailtypes"
+	qbtypes "github.com/SigNoz/signoz/pkg/types/querybuildertypes/querybuildertypesv5"
+	"github.com/SigNoz/signoz/pkg/types/reporttypes"
+	"github.com/SigNoz/signoz/pkg/types/telemetrytypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const dashboardData = `{
+	"version": "v5",
+	"title": "redis",
+	"layout": [{"i": "a", "x": 0, "y": 0, "w": 6, "h": 3}, {"i": "b", "x": 6, "y": 0, "w": 6, "h": 3}],
+	"widgets": [{
+		"id": "a",
+		"title": "hits",
+		"panelTypes": "graph",
+		"query": {"queryType": "promql", "queries": [{"type": "promql", "spec": {"name": "A", "query": "sum(rate(redis_keyspace_hits[5m])) by (host)"}}]}
+	}, {
+		"id": "b",
+		"title": "keys",
+		"panelTypes": "value",
+		"query": {"queryType": "clickhouse_sql", "queries": [{"type": "clickhouse_sql", "spec": {"name": "A", "query": "SELECT count() FROM signoz_logs.distributed_logs_v2"}}]}
+	}]
+}`
+
+// testQuerier answers every time series query with a series per host and fails the other queries.
+type testQuerier struct {
+	requests []*qbtypes.QueryRangeRequest
+}
+
+func (querier *testQuerier) QueryRange(ctx context.Context, orgID valuer.UUID, req *qbtypes.QueryRangeRequest) (*qbtypes.QueryRangeResponse, error) {
+	querier.requests = append(querier.requests, req)
+	if req.RequestType != qbtypes.RequestTypeTimeSeries {
+		return nil, errors.New(errors.TypeInvalidInput, errors.CodeInvalidInput, "table signoz_logs.distributed_logs_v2 does not exist")
+	}
+
+	series := make([]*qbtypes.TimeSeries, 0)
+	for _, host := range []string{"redis-0", "redis-1"} {
+		series = append(series, &qbtypes.TimeSeries{
+			Labels: []*qbtypes.Label{{Key: telemetrytypes.TelemetryFieldKey{Name: "host"}, Value: host}},
+			Values: []*qbtypes.TimeSeriesValue{{Timestamp: int64(req.Start), Value: 1}, {Timestamp: int64(req.End), Value: 3}},
+		})
+	}
+
+	return &qbtypes.QueryRangeResponse{
+		Type: req.RequestType,
+		Data: struct {
+			Results []any `json:"results"`
+		}{Results: []any{&qbtypes.TimeSeriesData{QueryName: "A", Aggregations: []*qbtypes.AggregationBucket{{Series: series}}}}},
+	}, nil
+}
+
+func newTestModule(t *testing.T) (context.Context, valuer.UUID, *module, *testQuerier, *emailingtest.Provider, valuer.UUID) {
+	sqlStore := utils.NewQueryServiceDBForTests(t)
+	require.NoError(t, utils.CreateTestOrg(t, sqlStore))
+	orgID, err := utils.GetTestOrgId(sqlStore)
+	require.NoError(t, err)
+
+	providerSettings := factorytest.NewSettings()
+	sharder, err := noopsharder.New(context.TODO(), providerSettings, sharder.Config{})
+	require.NoError(t, err)
+	audit := implaudit.NewModule(implaudit.NewStore(sqlStore), providerSettings)
+	dashboard := impldashboard.NewModule(sqlStore, providerSettings, analyticstest.New(), audit)
+
+	// the scheduled reports are sent on behalf of their creator
+	user := impluser.NewModule(impluser.NewStore(sqlStore, providerSettings), authtypes.NewJWT("", time.Hour, time.Hour), emailingtest.New(), providerSettings, nil, nil, analyticstest.New(), audit)
+	editor, err := types.NewUser("editor", "editor@example.com", types.RoleEditor.String(), orgID.StringValue())
+	require.NoError(t, err)
+	require.NoError(t, user.CreateUser(context.Background(), editor))
+	me, err := types.NewUser("me", "me@example.com", types.RoleViewer.String(), orgID.StringValue())
+	require.NoError(t, err)
+	require.NoError(t, user.CreateUser(context.Background(), me))
+
+	userID := editor.ID
+	ctx := authtypes.NewContextWithClaims(context.Background(), authtypes.Claims{UserID: userID.StringValue(), Email: "editor@example.com", OrgID: orgID.StringValue(), Role: types.RoleEditor})
+
+	data := dashboardtypes.PostableDashboard{}
+	require.NoError(t, json.Unmarshal([]byte(dashboardData), &data))
+	created, err := dashboard.Create(ctx, orgID, "editor@example.com", userID, data)
+	require.NoError(t, err)
+
+	querier := &testQuerier{}
+	emailing := emailingtest.New()
+	module := NewModule(NewStore(sqlStore), dashboard, querier, emailing, implorganization.NewGetter(implorganization.NewStore(sqlStore), sharder), user, providerSettings, audit).(*module)
+	return ctx, orgID, module, querier, emailing, valuer.MustNewUUID(created.ID)
+}
+
+func TestModuleSendDashboardReport(t *testing.T) {
+	ctx, orgID, module, querier, emailing, dashboardID := newTestModule(t)
+
+	_, err := module.Create(ctx, orgID, "editor@example.com", &reporttypes.PostableReport{
+		Name:        "redis",
+		DashboardID: dashboardID.StringValue(),
+		WidgetIDs:   []string{"c"},
+		Cron:        "0 9 * * *",
+		Timezone:    "UTC",
+		TimeRange:   "1d",
+		Recipients:  []string{"oncall@example.com"},
+	})
+	assert.True(t, errors.Ast(err, errors.TypeNotFound))
+
+	report, err := module.Create(ctx, orgID, "editor@example.com", &reporttypes.PostableReport{
+		Name:        "redis",
+		DashboardID: dashboardID.StringValue(),
+		Variables:   map[string]any{"host": "redis-0"},
+		Cron:        "0 9 * * *",
+		Timezone:    "UTC",
+		TimeRange:   "1d",
+		Recipients:  []string{"oncall@example.com", "sre@example.com"},
+		AttachCSV:   true,
+		Enabled:     true,
+	})
+	require.NoError(t, err)
+	assert.True(t, report.NextRunAt.After(time.Now()))
+
+	// sending now only emails the given recipients and keeps the schedule
+	delivery, err := module.Send(ctx, orgID, report.ID, []string{"me@example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, reporttypes.TriggerManual, delivery.Trigger)
+	assert.Equal(t, reporttypes.StatusPartial, delivery.Status)
+	assert.Contains(t, delivery.Error, "keys")
+	assert.Equal(t, 1, emailing.SentEmailCountByTo["me@example.com"])
+	assert.Equal(t, 1, emailing.SentEmailCountByTemplateName[emailtypes.TemplateNameReportEmail])
+	assert.Zero(t, emailing.SentEmailCountByTo["oncall@example.com"])
+
+	require.Len(t, querier.requests, 2)
+	assert.Equal(t, map[string]any{"host": "redis-0"}, querier.requests[0].Variables)
+	assert.Equal(t, uint64(24*time.Hour/time.Millisecond), querier.requests[0].End-querier.requests[0].Start)
+
+	// the chart of the failed panel is missing but the data of the other panel is attached
+	attachments := emailing.SentAttachmentsByTo["me@example.com"]
+	require.Len(t, attachments, 2)
+	assert.Equal(t, "01-hits.png", attachments[0].Name)
+	assert.True(t, attachments[0].Inline)
+	assert.Equal(t, "01-hits.csv", attachments[1].Name)
+	assert.False(t, attachments[1].Inline)
+
+	_, err = module.Send(ctx, orgID, report.ID, []string{"me"})
+	assert.True(t, errors.Ast(err, errors.TypeInvalidInput))
+
+	// the report is not sent to addresses outside of the org
+	_, err = module.Send(ctx, orgID, report.ID, []string{"someone@elsewhere.com"})
+	assert.True(t, errors.Asc(err, reporttypes.ErrCodeReportInvalid))
+
+	deliveries, err := module.ListDeliveries(ctx, orgID, report.ID)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, []string{"me@example.com"}, deliveries[0].Recipients)
+
+	stored, err := module.Get(ctx, orgID, report.ID)
+	require.NoError(t, err)
+	assert.True(t, stored.NextRunAt.Equal(report.NextRunAt))
+
+	require.NoError(t, module.Delete(ctx, orgID, report.ID))
+	_, err = module.ListDeliveries(ctx, orgID, report.ID)
+	assert.True(t, errors.Asc(err, reporttypes.ErrCodeReportNotFound))
+}
+
+func TestModuleSendDue(t *testing.T) {
+	ctx, orgID, module, _, emailing, _ := newTestModule(t)
+
+	report, err := module.Create(ctx, orgID, "editor@example.com", &reporttypes.PostableReport{
+		Name: "errors",
+		Panels: []*reporttypes.Panel{{
+			Title:     "error rate",
+			PanelType: dashboardtypes.PanelTypeGraph,
+			Queries:   []qbtypes.QueryEnvelope{{Type: qbtypes.QueryTypePromQL, Spec: qbtypes.PromQuery{Name: "A", Query: "sum(rate(errors_total[5m]))"}}},
+		}},
+		Cron:       "*/5 * * * *",
+		Timezone:   "UTC",
+		TimeRange:  "1h",
+		Recipients: []string{"oncall@example.com"},
+		Enabled:    true,
+	})
+	require.NoError(t, err)
+
+	// nothing is due yet
+	require.NoError(t, module.SendDue(ctx))
+	assert.Zero(t, emailing.SentEmailCountByTo["oncall@example.com"])
+
+	report.NextRunAt = time.Now().Add(-time.Minute)
+	require.NoError(t, module.store.Update(ctx, report))
+
+	require.NoError(t, module.SendDue(ctx))
+	assert.Equal(t, 1, emailing.SentEmailCountByTo["oncall@example.com"])
+
+	deliveries, err := module.ListDeliveries(ctx, orgID, report.ID)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, reporttypes.TriggerSchedule, deliveries[0].Trigger)
+	assert.Equal(t, reporttypes.StatusSuccess, deliveries[0].Status)
+
+	stored, err := module.Get(ctx, orgID, report.ID)
+	require.NoError(t, err)
+	assert.True(t, stored.NextRunAt.After(time.Now()))
+
+	// the report is only sent once per run
+	require.NoError(t, module.SendDue(ctx))
+	assert.Equal(t, 1, emailing.SentEmailCountByTo["oncall@example.com"])
+
+	// a run claimed by another instance is not sent again
+	due := *stored
+	due.NextRunAt = time.Now().Add(-time.Minute)
+	require.NoError(t, module.store.Update(ctx, &due))
+	listed, err := module.store.ListDue(ctx, []valuer.UUID{orgID}, time.Now())
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	require.NoError(t, listed[0].Reschedule(time.Now()))
+	claimed, err := module.store.Claim(ctx, listed[0], time.Now())
+	require.NoError(t, err)
+	assert.True(t, claimed)
+	claimed, err = module.store.Claim(ctx, &due, time.Now())
+	require.NoError(t, err)
+	assert.False(t, claimed)
+}
+
+func TestModuleRenderTemplate(t *testing.T) {
+	ctx, orgID, module, _, _, dashboardID := newTestModule(t)
+
+	report, err := reporttypes.NewReport(orgID, "editor@example.com", &reporttypes.PostableReport{
+		Name:        "redis <daily>",
+		DashboardID: dashboardID.StringValue(),
+		Cron:        "0 9 * * *",
+		Timezone:    "Asia/Kolkata",
+		TimeRange:   "1d",
+		Recipients:  []string{"oncall@example.com"},
+	}, time.Now())
+	require.NoError(t, err)
+
+	data, _, panelErrs, err := module.render(ctx, report, time.Now())
+	require.NoError(t, err)
+	require.Len(t, panelErrs, 1)
+
+	store, err := filetemplatestore.NewStore(ctx, "../../../../templates/email", emailtypes.Templates, factorytest.NewSettings().Logger)
+	require.NoError(t, err)
+	template, err := store.Get(ctx, emailtypes.TemplateNameReportEmail)
+	require.NoError(t, err)
+
+	content, err := emailtypes.NewContent(template, data)
+	require.NoError(t, err)
+
+	html := string(content)
+	assert.Contains(t, html, "redis &lt;daily&gt;")
+	assert.Contains(t, html, `src="cid:01-hits.png"`)
+	assert.Contains(t, html, "border-left: 4px solid #4e79a7")
+	assert.Contains(t, html, "A{host=redis-1}")
+	assert.Contains(t, html, "does not exist")
+	assert.Equal(t, 1, strings.Count(html, "<img"))
+}
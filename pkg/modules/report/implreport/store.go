@@ -0,0 +1,217 @@
+package implreport
+
+import (
+	"context"
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/sqlstore"
+	"github.com/SigNoz/signoz/pkg/types/reporttypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+	"github.com/uptrace/bun"
+)
+
+type store struct {
+	sqlstore sqlstore.SQLStore
+}
+
+func NewStore(sqlstore sqlstore.SQLStore) reporttypes.Store {
+	return &store{sqlstore: sqlstore}
+}
+
+func (store *store) Create(ctx context.Context, report *reporttypes.Report) error {
+	_, err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewInsert().
+		Model(report).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (store *store) Get(ctx context.Context, orgID valuer.UUID, id valuer.UUID) (*reporttypes.Report, error) {
+	report := new(reporttypes.Report)
+	err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewSelect().
+		Model(report).
+		Where("org_id = ?", orgID).
+		Where("id = ?", id).
+		Scan(ctx)
+	if err != nil {
+		return nil, store.sqlstore.WrapNotFoundErrf(err, reporttypes.ErrCodeReportNotFound, "report with id: %s does not exist in org: %s", id.StringValue(), orgID.StringValue())
+	}
+
+	return report, nil
+}
+
+func (store *store) List(ctx context.Context, orgID valuer.UUID) ([]*reporttypes.Report, error) {
+	reports := make([]*reporttypes.Report, 0)
+	err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewSelect().
+		Model(&reports).
+		Where("org_id = ?", orgID).
+		Order("created_at DESC").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return reports, nil
+}
+
+func (store *store) ListDue(ctx context.Context, orgIDs []valuer.UUID, now time.Time) ([]*reporttypes.Report, error) {
+	reports := make([]*reporttypes.Report, 0)
+	if len(orgIDs) == 0 {
+		return reports, nil
+	}
+
+	err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewSelect().
+		Model(&reports).
+		Where("org_id IN (?)", bun.In(orgIDs)).
+		Where("enabled = ?", true).
+		Where("next_run_at <= ?", now).
+		Order("next_run_at ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return reports, nil
+}
+
+func (store *store) Update(ctx context.Context, report *reporttypes.Report) error {
+	_, err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewUpdate().
+		Model(report).
+		Where("org_id = ?", report.OrgID).
+		Where("id = ?", report.ID).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (store *store) Claim(ctx context.Context, report *reporttypes.Report, now time.Time) (bool, error) {
+	result, err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewUpdate().
+		Model(report).
+		Column("next_run_at").
+		Where("org_id = ?", report.OrgID).
+		Where("id = ?", report.ID).
+		Where("next_run_at <= ?", now).
+		Exec(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	claimed, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return claimed == 1, nil
+}
+
+func (store *store) Delete(ctx context.Context, orgID valuer.UUID, id valuer.UUID) error {
+	return store.sqlstore.RunInTxCtx(ctx, nil, func(ctx context.Context) error {
+		_, err := store.
+			sqlstore.
+			BunDBCtx(ctx).
+			NewDelete().
+			Model(new(reporttypes.Delivery)).
+			Where("org_id = ?", orgID).
+			Where("report_id = ?", id).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		_, err = store.
+			sqlstore.
+			BunDBCtx(ctx).
+			NewDelete().
+			Model(new(reporttypes.Report)).
+			Where("org_id = ?", orgID).
+			Where("id = ?", id).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+func (store *store) CreateDelivery(ctx context.Context, delivery *reporttypes.Delivery) error {
+	return store.sqlstore.RunInTxCtx(ctx, nil, func(ctx context.Context) error {
+		_, err := store.
+			sqlstore.
+			BunDBCtx(ctx).
+			NewInsert().
+			Model(delivery).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		// only the latest deliveries of a report are kept
+		_, err = store.
+			sqlstore.
+			BunDBCtx(ctx).
+			NewDelete().
+			Model(new(reporttypes.Delivery)).
+			Where("org_id = ?", delivery.OrgID).
+			Where("report_id = ?", delivery.ReportID).
+			Where("id NOT IN (?)", store.
+				sqlstore.
+				BunDBCtx(ctx).
+				NewSelect().
+				Model(new(reporttypes.Delivery)).
+				Column("id").
+				Where("org_id = ?", delivery.OrgID).
+				Where("report_id = ?", delivery.ReportID).
+				Order("started_at DESC").
+				Limit(reporttypes.MaxDeliveries)).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+func (store *store) ListDeliveries(ctx context.Context, orgID valuer.UUID, id valuer.UUID) ([]*reporttypes.Delivery, error) {
+	deliveries := make([]*reporttypes.Delivery, 0)
+	err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewSelect().
+		Model(&deliveries).
+		Where("org_id = ?", orgID).
+		Where("report_id = ?", id).
+		Order("started_at DESC").
+		Limit(reporttypes.MaxDeliveries).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
@@ -0,0 +1,59 @@
+package report
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/SigNoz/signoz/pkg/factory"
+	"github.com/SigNoz/signoz/pkg/types/reporttypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+)
+
+type Module interface {
+	// Create creates a report of the org, it is first sent at the next time of its schedule
+	Create(ctx context.Context, orgID valuer.UUID, createdBy string, report *reporttypes.PostableReport) (*reporttypes.Report, error)
+
+	// Get gets the report of the org
+	Get(ctx context.Context, orgID valuer.UUID, id valuer.UUID) (*reporttypes.Report, error)
+
+	// List lists the reports of the org
+	List(ctx context.Context, orgID valuer.UUID) ([]*reporttypes.Report, error)
+
+	// Update replaces the report and reschedules it
+	Update(ctx context.Context, orgID valuer.UUID, id valuer.UUID, updatedBy string, report *reporttypes.PostableReport) (*reporttypes.Report, error)
+
+	// Delete deletes the report along with its deliveries
+	Delete(ctx context.Context, orgID valuer.UUID, id valuer.UUID) error
+
+	// ListDeliveries lists the latest deliveries of the report
+	ListDeliveries(ctx context.Context, orgID valuer.UUID, id valuer.UUID) ([]*reporttypes.Delivery, error)
+
+	// Send sends the report now, to the given recipients instead of those of the report when any, without changing its
+	// schedule. The given recipients must be recipients of the report or users of the org.
+	Send(ctx context.Context, orgID valuer.UUID, id valuer.UUID, recipients []string) (*reporttypes.Delivery, error)
+
+	// SendDue sends the reports of the orgs owned by the instance whose scheduled run has come, on behalf of their
+	// creators
+	SendDue(ctx context.Context) error
+
+	// The due reports are sent every minute while the module is started
+	factory.Service
+}
+
+type Handler interface {
+	Create(http.ResponseWriter, *http.Request)
+
+	Get(http.ResponseWriter, *http.Request)
+
+	List(http.ResponseWriter, *http.Request)
+
+	Update(http.ResponseWriter, *http.Request)
+
+	Delete(http.ResponseWriter, *http.Request)
+
+	// ListDeliveries lists the delivery history of the report
+	ListDeliveries(http.ResponseWriter, *http.Request)
+
+	// Send sends the report now, e.g. to test it
+	Send(http.ResponseWriter, *http.Request)
+}
@@ -0,0 +1,200 @@
+package implrole
+
+import (
+	"context"
+	"slices"
+
+	"github.com/SigNoz/signoz/pkg/modules/role"
+	"github.com/SigNoz/signoz/pkg/modules/user"
+	"github.com/SigNoz/signoz/pkg/types/authtypes"
+	"github.com/SigNoz/signoz/pkg/types/roletypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+)
+
+type module struct {
+	store roletypes.Store
+	user  user.Module
+}
+
+func NewModule(store roletypes.Store, user user.Module) role.Module {
+	return &module{store: store, user: user}
+}
+
+func (module *module) Create(ctx context.Context, orgID valuer.UUID, createdBy string, postable *roletypes.PostableRole) (*roletypes.GettableRole, error) {
+	if err := postable.Validate(); err != nil {
+		return nil, err
+	}
+
+	storable, err := roletypes.NewStorableRole(orgID, createdBy, postable)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := module.store.Create(ctx, storable); err != nil {
+		return nil, err
+	}
+
+	return roletypes.NewGettableRole(storable, nil)
+}
+
+func (module *module) Get(ctx context.Context, orgID valuer.UUID, id valuer.UUID) (*roletypes.GettableRole, error) {
+	storable, err := module.store.Get(ctx, orgID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	assignments, err := module.store.ListAssignments(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	return roletypes.NewGettableRole(storable, assignmentsOf(assignments, id))
+}
+
+func (module *module) List(ctx context.Context, orgID valuer.UUID) ([]*roletypes.GettableRole, error) {
+	storables, err := module.store.List(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	assignments, err := module.store.ListAssignments(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	gettables := make([]*roletypes.GettableRole, 0, len(storables))
+	for _, storable := range storables {
+		gettable, err := roletypes.NewGettableRole(storable, assignmentsOf(assignments, storable.ID))
+		if err != nil {
+			return nil, err
+		}
+		gettables = append(gettables, gettable)
+	}
+
+	return gettables, nil
+}
+
+func (module *module) Update(ctx context.Context, orgID valuer.UUID, id valuer.UUID, updatedBy string, postable *roletypes.PostableRole) (*roletypes.GettableRole, error) {
+	if err := postable.Validate(); err != nil {
+		return nil, err
+	}
+
+	storable, err := module.store.Get(ctx, orgID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := storable.Update(updatedBy, postable); err != nil {
+		return nil, err
+	}
+
+	if err := module.store.Update(ctx, storable); err != nil {
+		return nil, err
+	}
+
+	return module.Get(ctx, orgID, id)
+}
+
+func (module *module) Delete(ctx context.Context, orgID valuer.UUID, id valuer.UUID) error {
+	if _, err := module.store.Get(ctx, orgID, id); err != nil {
+		return err
+	}
+
+	return module.store.Delete(ctx, orgID, id)
+}
+
+func (module *module) Assign(ctx context.Context, orgID valuer.UUID, id valuer.UUID, subject roletypes.Subject) error {
+	if _, err := module.store.Get(ctx, orgID, id); err != nil {
+		return err
+	}
+
+	// the subject has to belong to the org of the role
+	switch subject.Type {
+	case roletypes.SubjectTypeUser:
+		if _, err := module.user.GetUserByID(ctx, orgID.StringValue(), subject.ID.StringValue()); err != nil {
+			return err
+		}
+	case roletypes.SubjectTypeAPIKey:
+		if _, err := module.user.GetAPIKey(ctx, orgID, subject.ID); err != nil {
+			return err
+		}
+	}
+
+	return module.store.CreateAssignment(ctx, roletypes.NewStorableRoleAssignment(orgID, id, subject))
+}
+
+func (module *module) Unassign(ctx context.Context, orgID valuer.UUID, id valuer.UUID, subject roletypes.Subject) error {
+	return module.store.DeleteAssignment(ctx, orgID, id, subject)
+}
+
+func (module *module) GetPermissions(ctx context.Context, claims authtypes.Claims) ([]authtypes.Permission, error) {
+	_, permissions, err := module.getCustomRolePermissions(ctx, claims)
+	return permissions, err
+}
+
+func (module *module) GetEffectivePermissions(ctx context.Context, claims authtypes.Claims) (*roletypes.GettablePermissions, error) {
+	roles, permissions, err := module.getCustomRolePermissions(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	effective := authtypes.PermissionsOfRole(claims.Role)
+	for _, permission := range permissions {
+		if !slices.Contains(effective, permission) {
+			effective = append(effective, permission)
+		}
+	}
+
+	return &roletypes.GettablePermissions{
+		Role:        claims.Role,
+		CustomRoles: roles,
+		Permissions: effective,
+	}, nil
+}
+
+// getCustomRolePermissions gets the names of the custom roles of the subject of the claims and the permissions they grant.
+func (module *module) getCustomRolePermissions(ctx context.Context, claims authtypes.Claims) ([]string, []authtypes.Permission, error) {
+	orgID, err := valuer.NewUUID(claims.OrgID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	subject, err := roletypes.NewSubjectFromClaims(claims)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	storables, err := module.store.ListBySubject(ctx, orgID, subject)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	names := []string{}
+	permissions := []authtypes.Permission{}
+	for _, storable := range storables {
+		rolePermissions, err := storable.GetPermissions()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		names = append(names, storable.Name)
+		for _, permission := range rolePermissions {
+			if !slices.Contains(permissions, permission) {
+				permissions = append(permissions, permission)
+			}
+		}
+	}
+
+	return names, permissions, nil
+}
+
+func assignmentsOf(assignments []*roletypes.StorableRoleAssignment, roleID valuer.UUID) []*roletypes.StorableRoleAssignment {
+	filtered := []*roletypes.StorableRoleAssignment{}
+	for _, assignment := range assignments {
+		if assignment.RoleID == roleID {
+			filtered = append(filtered, assignment)
+		}
+	}
+
+	return filtered
+}
@@ -0,0 +1,181 @@
+package implrole
+
+import (
+	"context"
+
+	"github.com/SigNoz/signoz/pkg/errors"
+	"github.com/SigNoz/signoz/pkg/sqlstore"
+	"github.com/SigNoz/signoz/pkg/types/roletypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+)
+
+type store struct {
+	sqlstore sqlstore.SQLStore
+}
+
+func NewStore(sqlstore sqlstore.SQLStore) roletypes.Store {
+	return &store{sqlstore: sqlstore}
+}
+
+func (store *store) Create(ctx context.Context, role *roletypes.StorableRole) error {
+	_, err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewInsert().
+		Model(role).
+		Exec(ctx)
+	if err != nil {
+		return store.sqlstore.WrapAlreadyExistsErrf(err, roletypes.ErrCodeRoleAlreadyExists, "role with name: %s already exists in org: %s", role.Name, role.OrgID.StringValue())
+	}
+
+	return nil
+}
+
+func (store *store) Get(ctx context.Context, orgID valuer.UUID, id valuer.UUID) (*roletypes.StorableRole, error) {
+	role := new(roletypes.StorableRole)
+	err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewSelect().
+		Model(role).
+		Where("org_id = ?", orgID).
+		Where("id = ?", id).
+		Scan(ctx)
+	if err != nil {
+		return nil, store.sqlstore.WrapNotFoundErrf(err, roletypes.ErrCodeRoleNotFound, "role with id: %s does not exist in org: %s", id.StringValue(), orgID.StringValue())
+	}
+
+	return role, nil
+}
+
+func (store *store) List(ctx context.Context, orgID valuer.UUID) ([]*roletypes.StorableRole, error) {
+	roles := make([]*roletypes.StorableRole, 0)
+	err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewSelect().
+		Model(&roles).
+		Where("org_id = ?", orgID).
+		Order("name ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+func (store *store) Update(ctx context.Context, role *roletypes.StorableRole) error {
+	_, err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewUpdate().
+		Model(role).
+		Where("org_id = ?", role.OrgID).
+		Where("id = ?", role.ID).
+		Exec(ctx)
+	if err != nil {
+		return store.sqlstore.WrapAlreadyExistsErrf(err, roletypes.ErrCodeRoleAlreadyExists, "role with name: %s already exists in org: %s", role.Name, role.OrgID.StringValue())
+	}
+
+	return nil
+}
+
+func (store *store) Delete(ctx context.Context, orgID valuer.UUID, id valuer.UUID) error {
+	return store.sqlstore.RunInTxCtx(ctx, nil, func(ctx context.Context) error {
+		_, err := store.
+			sqlstore.
+			BunDBCtx(ctx).
+			NewDelete().
+			Model(new(roletypes.StorableRoleAssignment)).
+			Where("org_id = ?", orgID).
+			Where("role_id = ?", id).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		_, err = store.
+			sqlstore.
+			BunDBCtx(ctx).
+			NewDelete().
+			Model(new(roletypes.StorableRole)).
+			Where("org_id = ?", orgID).
+			Where("id = ?", id).
+			Exec(ctx)
+		return err
+	})
+}
+
+func (store *store) CreateAssignment(ctx context.Context, assignment *roletypes.StorableRoleAssignment) error {
+	_, err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewInsert().
+		Model(assignment).
+		Exec(ctx)
+	if err != nil {
+		return store.sqlstore.WrapAlreadyExistsErrf(err, roletypes.ErrCodeRoleAssignmentAlreadyExists, "role: %s is already assigned to %s: %s", assignment.RoleID.StringValue(), assignment.SubjectType.StringValue(), assignment.SubjectID.StringValue())
+	}
+
+	return nil
+}
+
+func (store *store) DeleteAssignment(ctx context.Context, orgID valuer.UUID, roleID valuer.UUID, subject roletypes.Subject) error {
+	result, err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewDelete().
+		Model(new(roletypes.StorableRoleAssignment)).
+		Where("org_id = ?", orgID).
+		Where("role_id = ?", roleID).
+		Where("subject_type = ?", subject.Type).
+		Where("subject_id = ?", subject.ID).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return errors.Newf(errors.TypeNotFound, roletypes.ErrCodeRoleAssignmentNotFound, "role: %s is not assigned to %s: %s", roleID.StringValue(), subject.Type.StringValue(), subject.ID.StringValue())
+	}
+
+	return nil
+}
+
+func (store *store) ListAssignments(ctx context.Context, orgID valuer.UUID) ([]*roletypes.StorableRoleAssignment, error) {
+	assignments := make([]*roletypes.StorableRoleAssignment, 0)
+	err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewSelect().
+		Model(&assignments).
+		Where("org_id = ?", orgID).
+		Order("created_at ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return assignments, nil
+}
+
+func (store *store) ListBySubject(ctx context.Context, orgID valuer.UUID, subject roletypes.Subject) ([]*roletypes.StorableRole, error) {
+	roles := make([]*roletypes.StorableRole, 0)
+	err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewSelect().
+		Model(&roles).
+		Join("JOIN custom_role_assignment AS assignment ON assignment.role_id = ?TableAlias.id").
+		Where("?TableAlias.org_id = ?", orgID).
+		Where("assignment.subject_type = ?", subject.Type).
+		Where("assignment.subject_id = ?", subject.ID).
+		OrderExpr("?TableAlias.name ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return roles, nil
+}
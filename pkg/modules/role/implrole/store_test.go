@@ -0,0 +1,65 @@
+package implrole
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SigNoz/signoz/pkg/errors"
+	"github.com/SigNoz/signoz/pkg/query-service/utils"
+	"github.com/SigNoz/signoz/pkg/types/authtypes"
+	"github.com/SigNoz/signoz/pkg/types/roletypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	sqlStore := utils.NewQueryServiceDBForTests(t)
+	require.NoError(t, utils.CreateTestOrg(t, sqlStore))
+	orgID, err := utils.GetTestOrgId(sqlStore)
+	require.NoError(t, err)
+
+	store := NewStore(sqlStore)
+
+	onCall, err := roletypes.NewStorableRole(orgID, "admin@signoz.io", &roletypes.PostableRole{
+		Name:        "on-call",
+		Permissions: []authtypes.Permission{authtypes.PermissionAlertsRead, authtypes.PermissionAlertsSilence},
+	})
+	require.NoError(t, err)
+	require.NoError(t, store.Create(ctx, onCall))
+
+	duplicate, err := roletypes.NewStorableRole(orgID, "admin@signoz.io", &roletypes.PostableRole{
+		Name:        "on-call",
+		Permissions: []authtypes.Permission{authtypes.PermissionDashboardsWrite},
+	})
+	require.NoError(t, err)
+	assert.True(t, errors.Ast(store.Create(ctx, duplicate), errors.TypeAlreadyExists))
+
+	user := roletypes.Subject{Type: roletypes.SubjectTypeUser, ID: valuer.GenerateUUID()}
+	apiKey := roletypes.Subject{Type: roletypes.SubjectTypeAPIKey, ID: user.ID}
+	require.NoError(t, store.CreateAssignment(ctx, roletypes.NewStorableRoleAssignment(orgID, onCall.ID, user)))
+	assert.True(t, errors.Ast(store.CreateAssignment(ctx, roletypes.NewStorableRoleAssignment(orgID, onCall.ID, user)), errors.TypeAlreadyExists))
+
+	roles, err := store.ListBySubject(ctx, orgID, user)
+	require.NoError(t, err)
+	require.Len(t, roles, 1)
+	permissions, err := roles[0].GetPermissions()
+	require.NoError(t, err)
+	assert.Equal(t, []authtypes.Permission{authtypes.PermissionAlertsRead, authtypes.PermissionAlertsSilence}, permissions)
+
+	// roles assigned to a user don't apply to api keys with the same id
+	roles, err = store.ListBySubject(ctx, orgID, apiKey)
+	require.NoError(t, err)
+	assert.Empty(t, roles)
+
+	assert.True(t, errors.Ast(store.DeleteAssignment(ctx, orgID, onCall.ID, apiKey), errors.TypeNotFound))
+
+	require.NoError(t, store.Delete(ctx, orgID, onCall.ID))
+	assignments, err := store.ListAssignments(ctx, orgID)
+	require.NoError(t, err)
+	assert.Empty(t, assignments)
+
+	_, err = store.Get(ctx, orgID, onCall.ID)
+	assert.True(t, errors.Ast(err, errors.TypeNotFound))
+}
@@ -0,0 +1,60 @@
+package role
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/SigNoz/signoz/pkg/types/authtypes"
+	"github.com/SigNoz/signoz/pkg/types/roletypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+)
+
+type Module interface {
+	// Create creates a custom role in the org
+	Create(ctx context.Context, orgID valuer.UUID, createdBy string, role *roletypes.PostableRole) (*roletypes.GettableRole, error)
+
+	// Get gets the custom role along with the subjects it is assigned to
+	Get(ctx context.Context, orgID valuer.UUID, id valuer.UUID) (*roletypes.GettableRole, error)
+
+	// List lists the custom roles of the org
+	List(ctx context.Context, orgID valuer.UUID) ([]*roletypes.GettableRole, error)
+
+	// Update replaces the name, description and permissions of the custom role
+	Update(ctx context.Context, orgID valuer.UUID, id valuer.UUID, updatedBy string, role *roletypes.PostableRole) (*roletypes.GettableRole, error)
+
+	// Delete deletes the custom role and unassigns it from everyone
+	Delete(ctx context.Context, orgID valuer.UUID, id valuer.UUID) error
+
+	// Assign assigns the custom role to a user or an api key of the org
+	Assign(ctx context.Context, orgID valuer.UUID, id valuer.UUID, subject roletypes.Subject) error
+
+	// Unassign removes the custom role from a user or an api key
+	Unassign(ctx context.Context, orgID valuer.UUID, id valuer.UUID, subject roletypes.Subject) error
+
+	// GetEffectivePermissions gets the permissions of the built-in role of the claims and of the custom roles of its subject
+	GetEffectivePermissions(ctx context.Context, claims authtypes.Claims) (*roletypes.GettablePermissions, error)
+
+	authtypes.PermissionGetter
+}
+
+type Handler interface {
+	Create(http.ResponseWriter, *http.Request)
+
+	Get(http.ResponseWriter, *http.Request)
+
+	List(http.ResponseWriter, *http.Request)
+
+	Update(http.ResponseWriter, *http.Request)
+
+	Delete(http.ResponseWriter, *http.Request)
+
+	Assign(http.ResponseWriter, *http.Request)
+
+	Unassign(http.ResponseWriter, *http.Request)
+
+	// ListPermissions lists all the permissions roles can be made of
+	ListPermissions(http.ResponseWriter, *http.Request)
+
+	// GetMyPermissions gets the effective permissions of the caller
+	GetMyPermissions(http.ResponseWriter, *http.Request)
+}
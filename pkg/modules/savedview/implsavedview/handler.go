@@ -4,23 +4,27 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"slices"
 	"time"
 
 	"github.com/SigNoz/signoz/pkg/errors"
 	"github.com/SigNoz/signoz/pkg/http/render"
 	"github.com/SigNoz/signoz/pkg/modules/savedview"
-	v3 "github.com/SigNoz/signoz/pkg/query-service/model/v3"
+	"github.com/SigNoz/signoz/pkg/modules/team"
 	"github.com/SigNoz/signoz/pkg/types/authtypes"
+	"github.com/SigNoz/signoz/pkg/types/savedviewtypes"
+	"github.com/SigNoz/signoz/pkg/types/teamtypes"
 	"github.com/SigNoz/signoz/pkg/valuer"
 	"github.com/gorilla/mux"
 )
 
 type handler struct {
 	module savedview.Module
+	team   team.Module
 }
 
-func NewHandler(module savedview.Module) savedview.Handler {
-	return &handler{module: module}
+func NewHandler(module savedview.Module, team team.Module) savedview.Handler {
+	return &handler{module: module, team: team}
 }
 
 func (handler *handler) Create(w http.ResponseWriter, r *http.Request) {
@@ -33,24 +37,19 @@ func (handler *handler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var view v3.SavedView
-	if err := json.NewDecoder(r.Body).Decode(&view); err != nil {
+	postable := new(savedviewtypes.PostableSavedView)
+	if err := json.NewDecoder(r.Body).Decode(postable); err != nil {
 		render.Error(w, errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "failed to decode request body"))
 		return
 	}
-	// validate the query
-	if err := view.Validate(); err != nil {
-		render.Error(w, errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "failed to validate request body"))
-		return
-	}
 
-	uuid, err := handler.module.CreateView(ctx, claims.OrgID, view)
+	view, err := handler.module.Create(ctx, valuer.MustNewUUID(claims.OrgID), claims.Email, postable)
 	if err != nil {
 		render.Error(w, err)
 		return
 	}
 
-	render.Success(w, http.StatusOK, uuid)
+	render.Success(w, http.StatusCreated, view)
 }
 
 func (handler *handler) Get(w http.ResponseWriter, r *http.Request) {
@@ -63,14 +62,13 @@ func (handler *handler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	viewID := mux.Vars(r)["viewId"]
-	viewUUID, err := valuer.NewUUID(viewID)
+	viewUUID, err := valuer.NewUUID(mux.Vars(r)["viewId"])
 	if err != nil {
 		render.Error(w, errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "failed to parse view id"))
 		return
 	}
 
-	view, err := handler.module.GetView(ctx, claims.OrgID, viewUUID)
+	view, err := handler.module.Get(ctx, valuer.MustNewUUID(claims.OrgID), viewUUID)
 	if err != nil {
 		render.Error(w, err)
 		return
@@ -89,24 +87,19 @@ func (handler *handler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	viewID := mux.Vars(r)["viewId"]
-	viewUUID, err := valuer.NewUUID(viewID)
+	viewUUID, err := valuer.NewUUID(mux.Vars(r)["viewId"])
 	if err != nil {
 		render.Error(w, errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "failed to parse view id"))
 		return
 	}
-	var view v3.SavedView
-	if err := json.NewDecoder(r.Body).Decode(&view); err != nil {
+
+	updatable := new(savedviewtypes.UpdatableSavedView)
+	if err := json.NewDecoder(r.Body).Decode(updatable); err != nil {
 		render.Error(w, errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "failed to decode request body"))
 		return
 	}
-	// validate the query
-	if err := view.Validate(); err != nil {
-		render.Error(w, errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "failed to validate request body"))
-		return
-	}
 
-	err = handler.module.UpdateView(ctx, claims.OrgID, viewUUID, view)
+	view, err := handler.module.Update(ctx, valuer.MustNewUUID(claims.OrgID), viewUUID, claims.Email, updatable)
 	if err != nil {
 		render.Error(w, err)
 		return
@@ -125,14 +118,13 @@ func (handler *handler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	viewID := mux.Vars(r)["viewId"]
-	viewUUID, err := valuer.NewUUID(viewID)
+	viewUUID, err := valuer.NewUUID(mux.Vars(r)["viewId"])
 	if err != nil {
 		render.Error(w, errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "failed to parse view id"))
 		return
 	}
 
-	err = handler.module.DeleteView(ctx, claims.OrgID, viewUUID)
+	err = handler.module.Delete(ctx, valuer.MustNewUUID(claims.OrgID), viewUUID)
 	if err != nil {
 		render.Error(w, err)
 		return
@@ -151,15 +143,107 @@ func (handler *handler) List(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	sourcePage := r.URL.Query().Get("sourcePage")
-	name := r.URL.Query().Get("name")
-	category := r.URL.Query().Get("category")
+	params, err := savedviewtypes.NewListParams(r)
+	if err != nil {
+		render.Error(w, err)
+		return
+	}
 
-	queries, err := handler.module.GetViewsForFilters(r.Context(), claims.OrgID, sourcePage, name, category)
+	views, err := handler.module.List(ctx, valuer.MustNewUUID(claims.OrgID), params)
 	if err != nil {
 		render.Error(w, err)
 		return
 	}
 
-	render.Success(w, http.StatusOK, queries)
+	if teamID := r.URL.Query().Get("teamId"); teamID != "" {
+		teamUUID, err := valuer.NewUUID(teamID)
+		if err != nil {
+			render.Error(w, errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "failed to parse team id"))
+			return
+		}
+
+		ownedIDs, err := handler.team.ListOwnedResourceIDs(ctx, valuer.MustNewUUID(claims.OrgID), teamUUID, teamtypes.ResourceTypeSavedView)
+		if err != nil {
+			render.Error(w, err)
+			return
+		}
+
+		views = slices.DeleteFunc(views, func(view *savedviewtypes.GettableSavedView) bool {
+			return !slices.Contains(ownedIDs, view.ID.StringValue())
+		})
+	}
+
+	render.Success(w, http.StatusOK, views)
+}
+
+func (handler *handler) RecordUsage(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	claims, err := authtypes.ClaimsFromContext(ctx)
+	if err != nil {
+		render.Error(w, err)
+		return
+	}
+
+	viewUUID, err := valuer.NewUUID(mux.Vars(r)["viewId"])
+	if err != nil {
+		render.Error(w, errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "failed to parse view id"))
+		return
+	}
+
+	if err := handler.module.RecordUsage(ctx, valuer.MustNewUUID(claims.OrgID), viewUUID); err != nil {
+		render.Error(w, err)
+		return
+	}
+
+	render.Success(w, http.StatusNoContent, nil)
+}
+
+func (handler *handler) SetDefault(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	claims, err := authtypes.ClaimsFromContext(ctx)
+	if err != nil {
+		render.Error(w, err)
+		return
+	}
+
+	viewUUID, err := valuer.NewUUID(mux.Vars(r)["viewId"])
+	if err != nil {
+		render.Error(w, errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "failed to parse view id"))
+		return
+	}
+
+	if err := handler.module.SetDefault(ctx, valuer.MustNewUUID(claims.OrgID), valuer.MustNewUUID(claims.UserID), viewUUID); err != nil {
+		render.Error(w, err)
+		return
+	}
+
+	render.Success(w, http.StatusNoContent, nil)
+}
+
+func (handler *handler) UnsetDefault(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	claims, err := authtypes.ClaimsFromContext(ctx)
+	if err != nil {
+		render.Error(w, err)
+		return
+	}
+
+	viewUUID, err := valuer.NewUUID(mux.Vars(r)["viewId"])
+	if err != nil {
+		render.Error(w, errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "failed to parse view id"))
+		return
+	}
+
+	if err := handler.module.UnsetDefault(ctx, valuer.MustNewUUID(claims.OrgID), valuer.MustNewUUID(claims.UserID), viewUUID); err != nil {
+		render.Error(w, err)
+		return
+	}
+
+	render.Success(w, http.StatusNoContent, nil)
 }
@@ -2,187 +2,313 @@ package implsavedview
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"strings"
+	"maps"
+	"slices"
 	"time"
 
+	"github.com/SigNoz/signoz/pkg/errors"
+	"github.com/SigNoz/signoz/pkg/factory"
+	"github.com/SigNoz/signoz/pkg/modules/audit"
+	"github.com/SigNoz/signoz/pkg/modules/preference"
 	"github.com/SigNoz/signoz/pkg/modules/savedview"
-	v3 "github.com/SigNoz/signoz/pkg/query-service/model/v3"
-	"github.com/SigNoz/signoz/pkg/sqlstore"
 	"github.com/SigNoz/signoz/pkg/types"
+	"github.com/SigNoz/signoz/pkg/types/audittypes"
 	"github.com/SigNoz/signoz/pkg/types/authtypes"
+	"github.com/SigNoz/signoz/pkg/types/preferencetypes"
+	"github.com/SigNoz/signoz/pkg/types/savedviewtypes"
 	"github.com/SigNoz/signoz/pkg/valuer"
 )
 
 type module struct {
-	sqlstore sqlstore.SQLStore
+	store      savedviewtypes.Store
+	preference preference.Module
+	settings   factory.ScopedProviderSettings
+	audit      audit.Module
 }
 
-func NewModule(sqlstore sqlstore.SQLStore) savedview.Module {
-	return &module{sqlstore: sqlstore}
+func NewModule(store savedviewtypes.Store, preference preference.Module, providerSettings factory.ProviderSettings, audit audit.Module) savedview.Module {
+	settings := factory.NewScopedProviderSettings(providerSettings, "github.com/SigNoz/signoz/pkg/modules/savedview/implsavedview")
+	return &module{store: store, preference: preference, settings: settings, audit: audit}
 }
 
-func (module *module) GetViewsForFilters(ctx context.Context, orgID string, sourcePage string, name string, category string) ([]*v3.SavedView, error) {
-	var views []types.SavedView
-	var err error
-	if len(category) == 0 {
-		err = module.sqlstore.BunDB().NewSelect().Model(&views).Where("org_id = ? AND source_page = ? AND name LIKE ?", orgID, sourcePage, "%"+name+"%").Scan(ctx)
-	} else {
-		err = module.sqlstore.BunDB().NewSelect().Model(&views).Where("org_id = ? AND source_page = ? AND category LIKE ? AND name LIKE ?", orgID, sourcePage, "%"+category+"%", "%"+name+"%").Scan(ctx)
+func (module *module) List(ctx context.Context, orgID valuer.UUID, params *savedviewtypes.ListParams) ([]*savedviewtypes.GettableSavedView, error) {
+	claims, teamIDs, err := module.access(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	storableViews, err := module.store.List(ctx, orgID)
+	if err != nil {
+		return nil, err
 	}
+
+	defaults, err := module.getDefaults(ctx, claims)
 	if err != nil {
-		return nil, fmt.Errorf("error in getting saved views: %s", err.Error())
+		return nil, err
 	}
 
-	var savedViews []*v3.SavedView
-	for _, view := range views {
-		var compositeQuery v3.CompositeQuery
-		err = json.Unmarshal([]byte(view.Data), &compositeQuery)
+	views := make([]*savedviewtypes.GettableSavedView, 0, len(storableViews))
+	for _, storableView := range storableViews {
+		view, err := savedviewtypes.NewSavedViewFromStorable(storableView)
 		if err != nil {
-			return nil, fmt.Errorf("error in unmarshalling explorer query data: %s", err.Error())
+			// a view that can not be read must not hide the others
+			module.settings.Logger().ErrorContext(ctx, "failed to read saved view", "saved_view_id", storableView.ID, "org_id", orgID, "error", err)
+			continue
 		}
-		savedViews = append(savedViews, &v3.SavedView{
-			ID:             view.ID,
-			Name:           view.Name,
-			CreatedAt:      view.CreatedAt,
-			CreatedBy:      view.CreatedBy,
-			UpdatedAt:      view.UpdatedAt,
-			UpdatedBy:      view.UpdatedBy,
-			Tags:           strings.Split(view.Tags, ","),
-			SourcePage:     view.SourcePage,
-			CompositeQuery: &compositeQuery,
-			ExtraData:      view.ExtraData,
-		})
+
+		if !view.VisibleTo(claims, teamIDs) || !params.Matches(view) {
+			continue
+		}
+
+		views = append(views, savedviewtypes.NewGettableSavedView(view, defaults[view.SourcePage] == view.ID.StringValue()))
 	}
-	return savedViews, nil
+
+	return views, nil
 }
 
-func (module *module) CreateView(ctx context.Context, orgID string, view v3.SavedView) (valuer.UUID, error) {
-	data, err := json.Marshal(view.CompositeQuery)
+func (module *module) Create(ctx context.Context, orgID valuer.UUID, createdBy string, postable *savedviewtypes.PostableSavedView) (*savedviewtypes.SavedView, error) {
+	view, err := savedviewtypes.NewSavedView(orgID, createdBy, postable)
 	if err != nil {
-		return valuer.UUID{}, fmt.Errorf("error in marshalling explorer query data: %s", err.Error())
+		return nil, err
+	}
+
+	if err := module.validateTeam(ctx, orgID, view); err != nil {
+		return nil, err
 	}
 
-	uuid := valuer.GenerateUUID()
-	createdAt := time.Now()
-	updatedAt := time.Now()
+	storableView, err := savedviewtypes.NewStorableSavedView(view)
+	if err != nil {
+		return nil, err
+	}
 
-	claims, errv2 := authtypes.ClaimsFromContext(ctx)
-	if errv2 != nil {
-		return valuer.UUID{}, fmt.Errorf("error in getting email from context")
+	if err := module.store.Create(ctx, storableView); err != nil {
+		return nil, err
 	}
 
-	createBy := claims.Email
-	updatedBy := claims.Email
+	module.audit.Record(ctx, orgID, audittypes.ActionCreate, audittypes.NewResource(audittypes.ResourceTypeSavedView, view.ID.StringValue()), nil, view)
+	return view, nil
+}
 
-	dbView := types.SavedView{
-		TimeAuditable: types.TimeAuditable{
-			CreatedAt: createdAt,
-			UpdatedAt: updatedAt,
-		},
-		UserAuditable: types.UserAuditable{
-			CreatedBy: createBy,
-			UpdatedBy: updatedBy,
-		},
-		OrgID: orgID,
-		Identifiable: types.Identifiable{
-			ID: uuid,
-		},
-		Name:       view.Name,
-		Category:   view.Category,
-		SourcePage: view.SourcePage,
-		Tags:       strings.Join(view.Tags, ","),
-		Data:       string(data),
-		ExtraData:  view.ExtraData,
+func (module *module) Get(ctx context.Context, orgID valuer.UUID, id valuer.UUID) (*savedviewtypes.GettableSavedView, error) {
+	view, err := module.getView(ctx, orgID, id)
+	if err != nil {
+		return nil, err
 	}
 
-	_, err = module.sqlstore.BunDB().NewInsert().Model(&dbView).Exec(ctx)
+	claims, _, err := module.access(ctx)
 	if err != nil {
-		return valuer.UUID{}, fmt.Errorf("error in creating saved view: %s", err.Error())
+		return nil, err
 	}
-	return uuid, nil
+
+	defaults, err := module.getDefaults(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	return savedviewtypes.NewGettableSavedView(view, defaults[view.SourcePage] == view.ID.StringValue()), nil
 }
 
-func (module *module) GetView(ctx context.Context, orgID string, uuid valuer.UUID) (*v3.SavedView, error) {
-	var view types.SavedView
-	err := module.sqlstore.BunDB().NewSelect().Model(&view).Where("org_id = ? AND id = ?", orgID, uuid.StringValue()).Scan(ctx)
+func (module *module) Update(ctx context.Context, orgID valuer.UUID, id valuer.UUID, updatedBy string, updatable *savedviewtypes.UpdatableSavedView) (*savedviewtypes.SavedView, error) {
+	view, err := module.getView(ctx, orgID, id)
 	if err != nil {
-		return nil, fmt.Errorf("error in getting saved view: %s", err.Error())
+		return nil, err
 	}
 
-	var compositeQuery v3.CompositeQuery
-	err = json.Unmarshal([]byte(view.Data), &compositeQuery)
+	before := *view
+	if err := view.Update(updatedBy, updatable); err != nil {
+		return nil, err
+	}
+
+	if view.Visibility != before.Visibility || view.TeamID != before.TeamID {
+		claims, _, err := module.access(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if !before.ManageableBy(claims) {
+			return nil, errors.New(errors.TypeForbidden, errors.CodeForbidden, "only the creator of the saved view or an admin can change its visibility")
+		}
+
+		if err := module.validateTeam(ctx, orgID, view); err != nil {
+			return nil, err
+		}
+	}
+
+	storableView, err := savedviewtypes.NewStorableSavedView(view)
 	if err != nil {
-		return nil, fmt.Errorf("error in unmarshalling explorer query data: %s", err.Error())
+		return nil, err
+	}
+
+	if err := module.store.Update(ctx, storableView); err != nil {
+		return nil, err
 	}
-	return &v3.SavedView{
-		ID:             view.ID,
-		Name:           view.Name,
-		Category:       view.Category,
-		CreatedAt:      view.CreatedAt,
-		CreatedBy:      view.CreatedBy,
-		UpdatedAt:      view.UpdatedAt,
-		UpdatedBy:      view.UpdatedBy,
-		SourcePage:     view.SourcePage,
-		Tags:           strings.Split(view.Tags, ","),
-		CompositeQuery: &compositeQuery,
-		ExtraData:      view.ExtraData,
-	}, nil
+
+	module.audit.Record(ctx, orgID, audittypes.ActionUpdate, audittypes.NewResource(audittypes.ResourceTypeSavedView, view.ID.StringValue()), &before, view)
+	return view, nil
 }
 
-func (module *module) UpdateView(ctx context.Context, orgID string, uuid valuer.UUID, view v3.SavedView) error {
-	data, err := json.Marshal(view.CompositeQuery)
+func (module *module) Delete(ctx context.Context, orgID valuer.UUID, id valuer.UUID) error {
+	view, err := module.getView(ctx, orgID, id)
 	if err != nil {
-		return fmt.Errorf("error in marshalling explorer query data: %s", err.Error())
+		return err
 	}
 
-	claims, errv2 := authtypes.ClaimsFromContext(ctx)
-	if errv2 != nil {
-		return fmt.Errorf("error in getting email from context")
+	if err := module.store.Delete(ctx, orgID, id); err != nil {
+		return err
 	}
 
-	updatedAt := time.Now()
-	updatedBy := claims.Email
+	module.audit.Record(ctx, orgID, audittypes.ActionDelete, audittypes.NewResource(audittypes.ResourceTypeSavedView, view.ID.StringValue()), view, nil)
+	return nil
+}
+
+func (module *module) RecordUsage(ctx context.Context, orgID valuer.UUID, id valuer.UUID) error {
+	if _, err := module.getView(ctx, orgID, id); err != nil {
+		return err
+	}
+
+	return module.store.RecordUsage(ctx, orgID, id, time.Now())
+}
+
+func (module *module) SetDefault(ctx context.Context, orgID valuer.UUID, userID valuer.UUID, id valuer.UUID) error {
+	view, err := module.getView(ctx, orgID, id)
+	if err != nil {
+		return err
+	}
 
-	_, err = module.sqlstore.BunDB().NewUpdate().
-		Model(&types.SavedView{}).
-		Set("updated_at = ?, updated_by = ?, name = ?, category = ?, source_page = ?, tags = ?, data = ?, extra_data = ?",
-			updatedAt, updatedBy, view.Name, view.Category, view.SourcePage, strings.Join(view.Tags, ","), data, view.ExtraData).
-		Where("id = ?", uuid.StringValue()).
-		Where("org_id = ?", orgID).
-		Exec(ctx)
+	defaults, err := module.getDefaultsByUser(ctx, userID)
 	if err != nil {
-		return fmt.Errorf("error in updating saved view: %s", err.Error())
+		return err
 	}
-	return nil
+
+	defaults[view.SourcePage] = view.ID.StringValue()
+	return module.preference.UpdateByUser(ctx, userID, preferencetypes.NameDefaultSavedViews, defaults)
 }
 
-func (module *module) DeleteView(ctx context.Context, orgID string, uuid valuer.UUID) error {
-	_, err := module.sqlstore.BunDB().NewDelete().
-		Model(&types.SavedView{}).
-		Where("id = ?", uuid.StringValue()).
-		Where("org_id = ?", orgID).
-		Exec(ctx)
+func (module *module) UnsetDefault(ctx context.Context, _ valuer.UUID, userID valuer.UUID, id valuer.UUID) error {
+	defaults, err := module.getDefaultsByUser(ctx, userID)
 	if err != nil {
-		return fmt.Errorf("error in deleting explorer query: %s", err.Error())
+		return err
 	}
-	return nil
+
+	// the view may have been deleted since it was set as a default
+	count := len(defaults)
+	maps.DeleteFunc(defaults, func(_ string, viewID string) bool { return viewID == id.StringValue() })
+	if len(defaults) == count {
+		return nil
+	}
+
+	return module.preference.UpdateByUser(ctx, userID, preferencetypes.NameDefaultSavedViews, defaults)
 }
 
 func (module *module) Collect(ctx context.Context, orgID valuer.UUID) (map[string]any, error) {
-	savedViews := []*types.SavedView{}
+	views, err := module.store.List(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	return savedviewtypes.NewStatsFromSavedViews(views), nil
+}
+
+// access returns the claims of the user of the context and the teams the user is a member of, the work done in the
+// background runs with the claims of the user it is done on behalf of.
+func (module *module) access(ctx context.Context) (*authtypes.Claims, []valuer.UUID, error) {
+	claims, err := authtypes.ClaimsFromContext(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	userID, err := valuer.NewUUID(claims.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	teamIDs, err := module.store.ListTeamIDs(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &claims, teamIDs, nil
+}
+
+// getView gets the view once it is visible to the user of the context, the views hidden from the user don't exist for
+// them.
+func (module *module) getView(ctx context.Context, orgID valuer.UUID, id valuer.UUID) (*savedviewtypes.SavedView, error) {
+	storableView, err := module.store.Get(ctx, orgID, id)
+	if err != nil {
+		return nil, err
+	}
 
-	err := module.
-		sqlstore.
-		BunDB().
-		NewSelect().
-		Model(&savedViews).
-		Where("org_id = ?", orgID).
-		Scan(ctx)
+	view, err := savedviewtypes.NewSavedViewFromStorable(storableView)
 	if err != nil {
 		return nil, err
 	}
 
-	return types.NewStatsFromSavedViews(savedViews), nil
+	claims, teamIDs, err := module.access(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !view.VisibleTo(claims, teamIDs) {
+		return nil, errors.Newf(errors.TypeNotFound, savedviewtypes.ErrCodeSavedViewNotFound, "saved view with id %s doesn't exist", id.StringValue())
+	}
+
+	return view, nil
+}
+
+// validateTeam checks that the team of a view of team visibility exists, users other than admins can only share views
+// with the teams they are a member of.
+func (module *module) validateTeam(ctx context.Context, orgID valuer.UUID, view *savedviewtypes.SavedView) error {
+	if view.Visibility != savedviewtypes.VisibilityTeam {
+		return nil
+	}
+
+	teamID := valuer.MustNewUUID(view.TeamID)
+	claims, teamIDs, err := module.access(ctx)
+	if err != nil {
+		return err
+	}
+
+	if claims.Role != types.RoleAdmin {
+		if !slices.Contains(teamIDs, teamID) {
+			return errors.Newf(errors.TypeForbidden, errors.CodeForbidden, "saved views can only be shared with the teams you are a member of")
+		}
+
+		return nil
+	}
+
+	exists, err := module.store.TeamExists(ctx, orgID, teamID)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "team with id %s doesn't exist", teamID.StringValue())
+	}
+
+	return nil
+}
+
+// getDefaults gets the default views of the user of the claims.
+func (module *module) getDefaults(ctx context.Context, claims *authtypes.Claims) (map[string]string, error) {
+	userID, err := valuer.NewUUID(claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return module.getDefaultsByUser(ctx, userID)
+}
+
+// getDefaultsByUser gets the ids of the default views of the user by source page, none when the user has never set a
+// default view.
+func (module *module) getDefaultsByUser(ctx context.Context, userID valuer.UUID) (map[string]string, error) {
+	defaults, err := module.preference.GetByUser(ctx, userID, preferencetypes.NameDefaultSavedViews)
+	if err != nil {
+		if errors.Ast(err, errors.TypeNotFound) {
+			return map[string]string{}, nil
+		}
+
+		return nil, err
+	}
+
+	return defaults.Value.StringMap(), nil
 }
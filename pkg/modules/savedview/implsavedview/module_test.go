@@ -0,0 +1,154 @@
+package implsavedview
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/errors"
+	"github.com/SigNoz/signoz/pkg/factory/factorytest"
+	"github.com/SigNoz/signoz/pkg/modules/audit/implaudit"
+	"github.com/SigNoz/signoz/pkg/modules/preference/implpreference"
+	"github.com/SigNoz/signoz/pkg/query-service/utils"
+	"github.com/SigNoz/signoz/pkg/types"
+	"github.com/SigNoz/signoz/pkg/types/authtypes"
+	"github.com/SigNoz/signoz/pkg/types/dashboardtypes"
+	"github.com/SigNoz/signoz/pkg/types/preferencetypes"
+	qbtypes "github.com/SigNoz/signoz/pkg/types/querybuildertypes/querybuildertypesv5"
+	"github.com/SigNoz/signoz/pkg/types/savedviewtypes"
+	"github.com/SigNoz/signoz/pkg/types/teamtypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newPostableSavedView(name string, visibility savedviewtypes.Visibility, teamID string) *savedviewtypes.PostableSavedView {
+	return &savedviewtypes.PostableSavedView{
+		Name:       name,
+		SourcePage: "logs",
+		PanelType:  dashboardtypes.PanelTypeList,
+		CompositeQuery: qbtypes.CompositeQuery{Queries: []qbtypes.QueryEnvelope{
+			{Type: qbtypes.QueryTypeClickHouseSQL, Spec: qbtypes.ClickHouseQuery{Name: "A", Query: "SELECT * FROM signoz_logs.distributed_logs_v2"}},
+		}},
+		Visibility: visibility,
+		TeamID:     teamID,
+	}
+}
+
+func TestModuleVisibility(t *testing.T) {
+	sqlStore := utils.NewQueryServiceDBForTests(t)
+	require.NoError(t, utils.CreateTestOrg(t, sqlStore))
+	orgID, err := utils.GetTestOrgId(sqlStore)
+	require.NoError(t, err)
+
+	providerSettings := factorytest.NewSettings()
+	preference := implpreference.NewModule(implpreference.NewStore(sqlStore), preferencetypes.NewAvailablePreference())
+	module := NewModule(NewStore(sqlStore), preference, providerSettings, implaudit.NewModule(implaudit.NewStore(sqlStore), providerSettings))
+
+	// the team members and the default views are stored for stored users
+	userContext := func(name string, role types.Role) (context.Context, valuer.UUID) {
+		user, err := types.NewUser(name, name+"@example.com", role.String(), orgID.StringValue())
+		require.NoError(t, err)
+		_, err = sqlStore.BunDB().NewInsert().Model(user).Exec(context.Background())
+		require.NoError(t, err)
+		return authtypes.NewContextWithClaims(context.Background(), authtypes.Claims{UserID: user.ID.StringValue(), Email: user.Email, OrgID: orgID.StringValue(), Role: role}), user.ID
+	}
+	jane, janeID := userContext("jane", types.RoleEditor)
+	john, johnID := userContext("john", types.RoleEditor)
+	bob, _ := userContext("bob", types.RoleEditor)
+	admin, _ := userContext("admin", types.RoleAdmin)
+
+	now := time.Now()
+	team := &teamtypes.Team{Identifiable: types.Identifiable{ID: valuer.GenerateUUID()}, TimeAuditable: types.TimeAuditable{CreatedAt: now, UpdatedAt: now}, OrgID: orgID, Name: "payments", DisplayName: "Payments"}
+	_, err = sqlStore.BunDB().NewInsert().Model(team).Exec(context.Background())
+	require.NoError(t, err)
+	for _, userID := range []valuer.UUID{janeID, johnID} {
+		member := &teamtypes.Member{Identifiable: types.Identifiable{ID: valuer.GenerateUUID()}, TimeAuditable: types.TimeAuditable{CreatedAt: now, UpdatedAt: now}, TeamID: team.ID, UserID: userID, Role: teamtypes.RoleMember}
+		_, err = sqlStore.BunDB().NewInsert().Model(member).Exec(context.Background())
+		require.NoError(t, err)
+	}
+
+	private, err := module.Create(jane, orgID, "jane@example.com", newPostableSavedView("private", savedviewtypes.VisibilityPrivate, ""))
+	require.NoError(t, err)
+	shared, err := module.Create(jane, orgID, "jane@example.com", newPostableSavedView("team", savedviewtypes.VisibilityTeam, team.ID.StringValue()))
+	require.NoError(t, err)
+	_, err = module.Create(jane, orgID, "jane@example.com", newPostableSavedView("org", savedviewtypes.Visibility{}, ""))
+	require.NoError(t, err)
+
+	// users can only share views with their own teams
+	_, err = module.Create(bob, orgID, "bob@example.com", newPostableSavedView("team", savedviewtypes.VisibilityTeam, team.ID.StringValue()))
+	assert.True(t, errors.Ast(err, errors.TypeForbidden))
+
+	for _, tc := range []struct {
+		name  string
+		ctx   context.Context
+		views int
+	}{
+		{name: "Creator", ctx: jane, views: 3},
+		{name: "TeamMember", ctx: john, views: 2},
+		{name: "Other", ctx: bob, views: 1},
+		{name: "Admin", ctx: admin, views: 3},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			views, err := module.List(tc.ctx, orgID, &savedviewtypes.ListParams{SourcePage: "logs"})
+			require.NoError(t, err)
+			assert.Len(t, views, tc.views)
+		})
+	}
+
+	_, err = module.Get(bob, orgID, private.ID)
+	assert.True(t, errors.Ast(err, errors.TypeNotFound))
+
+	// the views are only listed on behalf of a user
+	_, err = module.List(context.Background(), orgID, &savedviewtypes.ListParams{})
+	assert.Error(t, err)
+
+	// members of the team can edit the view but not who sees it
+	_, err = module.Update(john, orgID, shared.ID, "john@example.com", newPostableSavedView("team errors", savedviewtypes.VisibilityTeam, team.ID.StringValue()))
+	require.NoError(t, err)
+	_, err = module.Update(john, orgID, shared.ID, "john@example.com", newPostableSavedView("team errors", savedviewtypes.VisibilityOrg, ""))
+	assert.True(t, errors.Ast(err, errors.TypeForbidden))
+
+	// the default view is kept per user and per source page
+	require.NoError(t, module.SetDefault(john, orgID, johnID, shared.ID))
+	err = module.SetDefault(john, orgID, johnID, private.ID)
+	assert.True(t, errors.Ast(err, errors.TypeNotFound))
+
+	view, err := module.Get(john, orgID, shared.ID)
+	require.NoError(t, err)
+	assert.True(t, view.Default)
+	assert.Equal(t, "team errors", view.Name)
+	view, err = module.Get(jane, orgID, shared.ID)
+	require.NoError(t, err)
+	assert.False(t, view.Default)
+
+	require.NoError(t, module.UnsetDefault(john, orgID, johnID, shared.ID))
+	view, err = module.Get(john, orgID, shared.ID)
+	require.NoError(t, err)
+	assert.False(t, view.Default)
+
+	// the views never opened are unused
+	require.NoError(t, module.RecordUsage(john, orgID, shared.ID))
+	require.NoError(t, module.RecordUsage(john, orgID, shared.ID))
+	view, err = module.Get(john, orgID, shared.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), view.UsageCount)
+	require.NotNil(t, view.LastUsedAt)
+
+	since := time.Now().Add(time.Minute)
+	unused, err := module.List(jane, orgID, &savedviewtypes.ListParams{UnusedSince: &since})
+	require.NoError(t, err)
+	assert.Len(t, unused, 3)
+
+	since = time.Now().Add(-time.Minute)
+	unused, err = module.List(jane, orgID, &savedviewtypes.ListParams{UnusedSince: &since})
+	require.NoError(t, err)
+	assert.Empty(t, unused)
+
+	// a view that can not be decoded is left out of the list
+	_, err = sqlStore.BunDB().NewUpdate().Model((*savedviewtypes.StorableSavedView)(nil)).Set("data = ?", "{").Where("id = ?", private.ID).Exec(context.Background())
+	require.NoError(t, err)
+	views, err := module.List(jane, orgID, &savedviewtypes.ListParams{SourcePage: "logs"})
+	require.NoError(t, err)
+	assert.Len(t, views, 2)
+}
@@ -0,0 +1,149 @@
+package implsavedview
+
+import (
+	"context"
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/sqlstore"
+	"github.com/SigNoz/signoz/pkg/types/savedviewtypes"
+	"github.com/SigNoz/signoz/pkg/types/teamtypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+)
+
+type store struct {
+	sqlstore sqlstore.SQLStore
+}
+
+func NewStore(sqlstore sqlstore.SQLStore) savedviewtypes.Store {
+	return &store{sqlstore: sqlstore}
+}
+
+func (store *store) Create(ctx context.Context, view *savedviewtypes.StorableSavedView) error {
+	_, err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewInsert().
+		Model(view).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (store *store) Get(ctx context.Context, orgID valuer.UUID, id valuer.UUID) (*savedviewtypes.StorableSavedView, error) {
+	view := new(savedviewtypes.StorableSavedView)
+	err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewSelect().
+		Model(view).
+		Where("org_id = ?", orgID).
+		Where("id = ?", id).
+		Scan(ctx)
+	if err != nil {
+		return nil, store.sqlstore.WrapNotFoundErrf(err, savedviewtypes.ErrCodeSavedViewNotFound, "saved view with id %s doesn't exist", id.StringValue())
+	}
+
+	return view, nil
+}
+
+func (store *store) List(ctx context.Context, orgID valuer.UUID) ([]*savedviewtypes.StorableSavedView, error) {
+	views := make([]*savedviewtypes.StorableSavedView, 0)
+	err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewSelect().
+		Model(&views).
+		Where("org_id = ?", orgID).
+		Order("created_at DESC").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return views, nil
+}
+
+func (store *store) Update(ctx context.Context, view *savedviewtypes.StorableSavedView) error {
+	_, err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewUpdate().
+		Model(view).
+		ExcludeColumn("usage_count", "last_used_at").
+		WherePK().
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (store *store) Delete(ctx context.Context, orgID valuer.UUID, id valuer.UUID) error {
+	_, err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewDelete().
+		Model(new(savedviewtypes.StorableSavedView)).
+		Where("org_id = ?", orgID).
+		Where("id = ?", id).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (store *store) RecordUsage(ctx context.Context, orgID valuer.UUID, id valuer.UUID, usedAt time.Time) error {
+	_, err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewUpdate().
+		Model(new(savedviewtypes.StorableSavedView)).
+		Set("usage_count = usage_count + 1").
+		Set("last_used_at = ?", usedAt).
+		Where("org_id = ?", orgID).
+		Where("id = ?", id).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (store *store) ListTeamIDs(ctx context.Context, userID valuer.UUID) ([]valuer.UUID, error) {
+	members := make([]*teamtypes.Member, 0)
+	err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewSelect().
+		Model(&members).
+		Where("user_id = ?", userID).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	teamIDs := make([]valuer.UUID, 0, len(members))
+	for _, member := range members {
+		teamIDs = append(teamIDs, member.TeamID)
+	}
+
+	return teamIDs, nil
+}
+
+func (store *store) TeamExists(ctx context.Context, orgID valuer.UUID, teamID valuer.UUID) (bool, error) {
+	return store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewSelect().
+		Model(new(teamtypes.Team)).
+		Where("org_id = ?", orgID).
+		Where("id = ?", teamID).
+		Exists(ctx)
+}
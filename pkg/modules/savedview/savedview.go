@@ -4,21 +4,31 @@ import (
 	"context"
 	"net/http"
 
-	v3 "github.com/SigNoz/signoz/pkg/query-service/model/v3"
 	"github.com/SigNoz/signoz/pkg/statsreporter"
+	"github.com/SigNoz/signoz/pkg/types/savedviewtypes"
 	"github.com/SigNoz/signoz/pkg/valuer"
 )
 
 type Module interface {
-	GetViewsForFilters(ctx context.Context, orgID string, sourcePage string, name string, category string) ([]*v3.SavedView, error)
+	// List lists the saved views visible to the user of the context matching the params
+	List(ctx context.Context, orgID valuer.UUID, params *savedviewtypes.ListParams) ([]*savedviewtypes.GettableSavedView, error)
 
-	CreateView(ctx context.Context, orgID string, view v3.SavedView) (valuer.UUID, error)
+	Create(ctx context.Context, orgID valuer.UUID, createdBy string, postable *savedviewtypes.PostableSavedView) (*savedviewtypes.SavedView, error)
 
-	GetView(ctx context.Context, orgID string, uuid valuer.UUID) (*v3.SavedView, error)
+	Get(ctx context.Context, orgID valuer.UUID, id valuer.UUID) (*savedviewtypes.GettableSavedView, error)
 
-	UpdateView(ctx context.Context, orgID string, uuid valuer.UUID, view v3.SavedView) error
+	Update(ctx context.Context, orgID valuer.UUID, id valuer.UUID, updatedBy string, updatable *savedviewtypes.UpdatableSavedView) (*savedviewtypes.SavedView, error)
 
-	DeleteView(ctx context.Context, orgID string, uuid valuer.UUID) error
+	Delete(ctx context.Context, orgID valuer.UUID, id valuer.UUID) error
+
+	// RecordUsage counts an opening of the saved view
+	RecordUsage(ctx context.Context, orgID valuer.UUID, id valuer.UUID) error
+
+	// SetDefault makes the saved view the one opened by default on its source page for the user
+	SetDefault(ctx context.Context, orgID valuer.UUID, userID valuer.UUID, id valuer.UUID) error
+
+	// UnsetDefault stops opening the saved view by default for the user
+	UnsetDefault(ctx context.Context, orgID valuer.UUID, userID valuer.UUID, id valuer.UUID) error
 
 	statsreporter.StatsCollector
 }
@@ -38,4 +48,13 @@ type Handler interface {
 
 	// Lists the saved views
 	List(http.ResponseWriter, *http.Request)
+
+	// Records an opening of the saved view
+	RecordUsage(http.ResponseWriter, *http.Request)
+
+	// Sets the saved view as the default of its source page
+	SetDefault(http.ResponseWriter, *http.Request)
+
+	// Unsets the saved view as the default of its source page
+	UnsetDefault(http.ResponseWriter, *http.Request)
 }
@@ -0,0 +1,438 @@
+package implscim
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/errors"
+	"github.com/SigNoz/signoz/pkg/http/render"
+	"github.com/SigNoz/signoz/pkg/modules/scim"
+	"github.com/SigNoz/signoz/pkg/types/authtypes"
+	"github.com/SigNoz/signoz/pkg/types/scimtypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+	"github.com/gorilla/mux"
+)
+
+type orgIDContextKey struct{}
+
+type handler struct {
+	module scim.Module
+}
+
+func NewHandler(module scim.Module) scim.Handler {
+	return &handler{module: module}
+}
+
+func (handler *handler) CreateToken(rw http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	claims, err := authtypes.ClaimsFromContext(ctx)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	req := new(scimtypes.PostableToken)
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		render.Error(rw, errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "failed to decode request body"))
+		return
+	}
+
+	token, err := handler.module.CreateToken(ctx, valuer.MustNewUUID(claims.OrgID), claims.Email, req)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	render.Success(rw, http.StatusCreated, token)
+}
+
+func (handler *handler) ListTokens(rw http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	claims, err := authtypes.ClaimsFromContext(ctx)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	tokens, err := handler.module.ListTokens(ctx, valuer.MustNewUUID(claims.OrgID))
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	render.Success(rw, http.StatusOK, tokens)
+}
+
+func (handler *handler) DeleteToken(rw http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	claims, err := authtypes.ClaimsFromContext(ctx)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	id, err := valuer.NewUUID(mux.Vars(r)["id"])
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	if err := handler.module.DeleteToken(ctx, valuer.MustNewUUID(claims.OrgID), id); err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	render.Success(rw, http.StatusNoContent, nil)
+}
+
+func (handler *handler) Authenticate(next http.HandlerFunc) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || token == "" {
+			renderError(rw, errors.New(errors.TypeUnauthenticated, errors.CodeUnauthenticated, "a provisioning token is required"))
+			return
+		}
+
+		orgID, err := handler.module.Authenticate(r.Context(), token)
+		if err != nil {
+			renderError(rw, err)
+			return
+		}
+
+		next(rw, r.WithContext(context.WithValue(r.Context(), orgIDContextKey{}, orgID)))
+	}
+}
+
+func (handler *handler) GetServiceProviderConfig(rw http.ResponseWriter, r *http.Request) {
+	renderResource(rw, http.StatusOK, scimtypes.NewServiceProviderConfig())
+}
+
+func (handler *handler) ListUsers(rw http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	orgID, err := orgIDFromContext(ctx)
+	if err != nil {
+		renderError(rw, err)
+		return
+	}
+
+	query, err := scimtypes.NewListQuery(r)
+	if err != nil {
+		renderError(rw, err)
+		return
+	}
+
+	users, err := handler.module.ListUsers(ctx, orgID, query)
+	if err != nil {
+		renderError(rw, err)
+		return
+	}
+
+	renderResource(rw, http.StatusOK, users)
+}
+
+func (handler *handler) GetUser(rw http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	orgID, id, err := resourceFromRequest(ctx, r)
+	if err != nil {
+		renderError(rw, err)
+		return
+	}
+
+	user, err := handler.module.GetUser(ctx, orgID, id)
+	if err != nil {
+		renderError(rw, err)
+		return
+	}
+
+	renderResource(rw, http.StatusOK, user)
+}
+
+func (handler *handler) CreateUser(rw http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	orgID, err := orgIDFromContext(ctx)
+	if err != nil {
+		renderError(rw, err)
+		return
+	}
+
+	req := new(scimtypes.User)
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		renderError(rw, errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "failed to decode request body"))
+		return
+	}
+
+	user, err := handler.module.CreateUser(ctx, orgID, req)
+	if err != nil {
+		renderError(rw, err)
+		return
+	}
+
+	renderResource(rw, http.StatusCreated, user)
+}
+
+func (handler *handler) ReplaceUser(rw http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	orgID, id, err := resourceFromRequest(ctx, r)
+	if err != nil {
+		renderError(rw, err)
+		return
+	}
+
+	req := new(scimtypes.User)
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		renderError(rw, errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "failed to decode request body"))
+		return
+	}
+
+	user, err := handler.module.ReplaceUser(ctx, orgID, id, req)
+	if err != nil {
+		renderError(rw, err)
+		return
+	}
+
+	renderResource(rw, http.StatusOK, user)
+}
+
+func (handler *handler) PatchUser(rw http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	orgID, id, err := resourceFromRequest(ctx, r)
+	if err != nil {
+		renderError(rw, err)
+		return
+	}
+
+	req := new(scimtypes.PatchRequest)
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		renderError(rw, errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "failed to decode request body"))
+		return
+	}
+
+	user, err := handler.module.PatchUser(ctx, orgID, id, req)
+	if err != nil {
+		renderError(rw, err)
+		return
+	}
+
+	renderResource(rw, http.StatusOK, user)
+}
+
+func (handler *handler) DeleteUser(rw http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	orgID, id, err := resourceFromRequest(ctx, r)
+	if err != nil {
+		renderError(rw, err)
+		return
+	}
+
+	if err := handler.module.DeleteUser(ctx, orgID, id); err != nil {
+		renderError(rw, err)
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+func (handler *handler) ListGroups(rw http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	orgID, err := orgIDFromContext(ctx)
+	if err != nil {
+		renderError(rw, err)
+		return
+	}
+
+	query, err := scimtypes.NewListQuery(r)
+	if err != nil {
+		renderError(rw, err)
+		return
+	}
+
+	groups, err := handler.module.ListGroups(ctx, orgID, query)
+	if err != nil {
+		renderError(rw, err)
+		return
+	}
+
+	renderResource(rw, http.StatusOK, groups)
+}
+
+func (handler *handler) GetGroup(rw http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	orgID, id, err := resourceFromRequest(ctx, r)
+	if err != nil {
+		renderError(rw, err)
+		return
+	}
+
+	group, err := handler.module.GetGroup(ctx, orgID, id)
+	if err != nil {
+		renderError(rw, err)
+		return
+	}
+
+	renderResource(rw, http.StatusOK, group)
+}
+
+func (handler *handler) CreateGroup(rw http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	orgID, err := orgIDFromContext(ctx)
+	if err != nil {
+		renderError(rw, err)
+		return
+	}
+
+	req := new(scimtypes.Group)
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		renderError(rw, errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "failed to decode request body"))
+		return
+	}
+
+	group, err := handler.module.CreateGroup(ctx, orgID, req)
+	if err != nil {
+		renderError(rw, err)
+		return
+	}
+
+	renderResource(rw, http.StatusCreated, group)
+}
+
+func (handler *handler) ReplaceGroup(rw http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	orgID, id, err := resourceFromRequest(ctx, r)
+	if err != nil {
+		renderError(rw, err)
+		return
+	}
+
+	req := new(scimtypes.Group)
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		renderError(rw, errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "failed to decode request body"))
+		return
+	}
+
+	group, err := handler.module.ReplaceGroup(ctx, orgID, id, req)
+	if err != nil {
+		renderError(rw, err)
+		return
+	}
+
+	renderResource(rw, http.StatusOK, group)
+}
+
+func (handler *handler) PatchGroup(rw http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	orgID, id, err := resourceFromRequest(ctx, r)
+	if err != nil {
+		renderError(rw, err)
+		return
+	}
+
+	req := new(scimtypes.PatchRequest)
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		renderError(rw, errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "failed to decode request body"))
+		return
+	}
+
+	group, err := handler.module.PatchGroup(ctx, orgID, id, req)
+	if err != nil {
+		renderError(rw, err)
+		return
+	}
+
+	renderResource(rw, http.StatusOK, group)
+}
+
+func (handler *handler) DeleteGroup(rw http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	orgID, id, err := resourceFromRequest(ctx, r)
+	if err != nil {
+		renderError(rw, err)
+		return
+	}
+
+	if err := handler.module.DeleteGroup(ctx, orgID, id); err != nil {
+		renderError(rw, err)
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+func orgIDFromContext(ctx context.Context) (valuer.UUID, error) {
+	orgID, ok := ctx.Value(orgIDContextKey{}).(valuer.UUID)
+	if !ok {
+		return valuer.UUID{}, errors.New(errors.TypeUnauthenticated, errors.CodeUnauthenticated, "a provisioning token is required")
+	}
+
+	return orgID, nil
+}
+
+// resourceFromRequest returns the org of the request and the id of the resource in its path,
+// ids that aren't valid are reported as not found.
+func resourceFromRequest(ctx context.Context, r *http.Request) (valuer.UUID, valuer.UUID, error) {
+	orgID, err := orgIDFromContext(ctx)
+	if err != nil {
+		return valuer.UUID{}, valuer.UUID{}, err
+	}
+
+	id, err := valuer.NewUUID(mux.Vars(r)["id"])
+	if err != nil {
+		return valuer.UUID{}, valuer.UUID{}, errors.Newf(errors.TypeNotFound, errors.CodeNotFound, "resource %s does not exist", mux.Vars(r)["id"])
+	}
+
+	return orgID, id, nil
+}
+
+// SCIM clients expect bare resources and errors instead of the envelope of render.
+func renderResource(rw http.ResponseWriter, status int, resource interface{}) {
+	body, err := json.Marshal(resource)
+	if err != nil {
+		renderError(rw, err)
+		return
+	}
+
+	rw.Header().Set("Content-Type", scimtypes.ContentType)
+	rw.WriteHeader(status)
+	_, _ = rw.Write(body)
+}
+
+func renderError(rw http.ResponseWriter, err error) {
+	status, scimError := scimtypes.NewError(err)
+	body, _ := json.Marshal(scimError)
+
+	rw.Header().Set("Content-Type", scimtypes.ContentType)
+	rw.WriteHeader(status)
+	_, _ = rw.Write(body)
+}
@@ -0,0 +1,496 @@
+package implscim
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/errors"
+	"github.com/SigNoz/signoz/pkg/factory"
+	"github.com/SigNoz/signoz/pkg/modules/scim"
+	"github.com/SigNoz/signoz/pkg/modules/user"
+	"github.com/SigNoz/signoz/pkg/types"
+	"github.com/SigNoz/signoz/pkg/types/scimtypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+)
+
+type module struct {
+	store    scimtypes.Store
+	user     user.Module
+	settings factory.ScopedProviderSettings
+}
+
+func NewModule(store scimtypes.Store, user user.Module, providerSettings factory.ProviderSettings) scim.Module {
+	settings := factory.NewScopedProviderSettings(providerSettings, "github.com/SigNoz/signoz/pkg/modules/scim/implscim")
+	return &module{store: store, user: user, settings: settings}
+}
+
+func (module *module) CreateToken(ctx context.Context, orgID valuer.UUID, createdBy string, postable *scimtypes.PostableToken) (*scimtypes.GettableToken, error) {
+	storable, token, err := scimtypes.NewStorableToken(orgID, createdBy, postable)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := module.store.CreateToken(ctx, storable); err != nil {
+		return nil, err
+	}
+
+	gettable := scimtypes.NewGettableToken(storable)
+	gettable.Token = token
+	return gettable, nil
+}
+
+func (module *module) ListTokens(ctx context.Context, orgID valuer.UUID) ([]*scimtypes.GettableToken, error) {
+	storables, err := module.store.ListTokens(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := make([]*scimtypes.GettableToken, len(storables))
+	for i, storable := range storables {
+		tokens[i] = scimtypes.NewGettableToken(storable)
+	}
+
+	return tokens, nil
+}
+
+func (module *module) DeleteToken(ctx context.Context, orgID valuer.UUID, id valuer.UUID) error {
+	return module.store.DeleteToken(ctx, orgID, id)
+}
+
+func (module *module) Authenticate(ctx context.Context, token string) (valuer.UUID, error) {
+	storable, err := module.store.GetTokenByHash(ctx, scimtypes.HashToken(token))
+	if err != nil {
+		if errors.Ast(err, errors.TypeNotFound) {
+			return valuer.UUID{}, errors.New(errors.TypeUnauthenticated, errors.CodeUnauthenticated, "invalid provisioning token")
+		}
+		return valuer.UUID{}, err
+	}
+
+	if err := module.store.UpdateTokenLastUsed(ctx, storable.ID, time.Now()); err != nil {
+		module.settings.Logger().ErrorContext(ctx, "failed to update last used of provisioning token", "error", err)
+	}
+
+	return storable.OrgID, nil
+}
+
+func (module *module) ListUsers(ctx context.Context, orgID valuer.UUID, query *scimtypes.ListQuery) (*scimtypes.ListResponse, error) {
+	users, err := module.user.ListUsers(ctx, orgID.StringValue())
+	if err != nil {
+		return nil, err
+	}
+
+	memberships, err := module.listMemberships(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	scimUsers := []*scimtypes.User{}
+	for _, user := range users {
+		// integration users are managed by SigNoz and not by the directory
+		if slices.Contains(types.AllIntegrationUserEmails, types.IntegrationUserEmail(user.Email)) {
+			continue
+		}
+
+		scimUser := scimtypes.NewUser(&user.User, memberships.groupsOf(user.ID))
+		if query.Filter.Matches(scimUser.Attributes()) {
+			scimUsers = append(scimUsers, scimUser)
+		}
+	}
+
+	return scimtypes.NewListResponse(query, scimUsers), nil
+}
+
+func (module *module) GetUser(ctx context.Context, orgID valuer.UUID, id valuer.UUID) (*scimtypes.User, error) {
+	user, err := module.user.GetUserByID(ctx, orgID.StringValue(), id.StringValue())
+	if err != nil {
+		return nil, err
+	}
+
+	memberships, err := module.listMemberships(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	return scimtypes.NewUser(&user.User, memberships.groupsOf(user.ID)), nil
+}
+
+func (module *module) CreateUser(ctx context.Context, orgID valuer.UUID, scimUser *scimtypes.User) (*scimtypes.User, error) {
+	if scimUser.Active != nil && !*scimUser.Active {
+		return nil, errors.New(errors.TypeInvalidInput, errors.CodeInvalidInput, "inactive users can not be provisioned")
+	}
+
+	email, err := scimUser.Email()
+	if err != nil {
+		return nil, err
+	}
+
+	role, ok := scimUser.GetRole()
+	if !ok {
+		role, err = module.mappedRole(ctx, orgID, email, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if role == "" {
+		role = types.RoleViewer
+	}
+
+	user, err := types.NewUser(scimUser.GetDisplayName(email), email, role.String(), orgID.StringValue())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := module.user.CreateUser(ctx, user); err != nil {
+		return nil, err
+	}
+
+	module.settings.Logger().InfoContext(ctx, "provisioned scim user", "email", user.Email, "role", user.Role)
+	return scimtypes.NewUser(user, nil), nil
+}
+
+func (module *module) ReplaceUser(ctx context.Context, orgID valuer.UUID, id valuer.UUID, scimUser *scimtypes.User) (*scimtypes.User, error) {
+	existing, err := module.user.GetUserByID(ctx, orgID.StringValue(), id.StringValue())
+	if err != nil {
+		return nil, err
+	}
+	user := &existing.User
+
+	// leavers are deactivated by the directories, they lose access right away
+	if scimUser.Active != nil && !*scimUser.Active {
+		if err := module.DeleteUser(ctx, orgID, id); err != nil {
+			return nil, err
+		}
+
+		deleted := scimtypes.NewUser(user, nil)
+		deleted.Active = scimUser.Active
+		return deleted, nil
+	}
+
+	email, err := scimUser.Email()
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(email, user.Email) {
+		return nil, errors.Newf(errors.TypeInvalidInput, scimtypes.ErrCodeMutability, "the email of user %s can not be changed to %s", user.Email, email)
+	}
+
+	role, ok := scimUser.GetRole()
+	if !ok {
+		role = types.Role(user.Role)
+	}
+
+	if err := module.updateUser(ctx, user, scimUser.GetDisplayName(email), role); err != nil {
+		return nil, err
+	}
+
+	return module.GetUser(ctx, orgID, id)
+}
+
+func (module *module) PatchUser(ctx context.Context, orgID valuer.UUID, id valuer.UUID, patch *scimtypes.PatchRequest) (*scimtypes.User, error) {
+	scimUser, err := module.GetUser(ctx, orgID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := scimUser.ApplyPatch(patch); err != nil {
+		return nil, err
+	}
+
+	return module.ReplaceUser(ctx, orgID, id, scimUser)
+}
+
+func (module *module) DeleteUser(ctx context.Context, orgID valuer.UUID, id valuer.UUID) error {
+	if err := module.user.DeleteUser(ctx, orgID.StringValue(), id.StringValue()); err != nil {
+		return err
+	}
+
+	module.settings.Logger().InfoContext(ctx, "deprovisioned scim user", "id", id.StringValue())
+	return module.store.DeleteMemberships(ctx, orgID, id)
+}
+
+func (module *module) ListGroups(ctx context.Context, orgID valuer.UUID, query *scimtypes.ListQuery) (*scimtypes.ListResponse, error) {
+	memberships, err := module.listMemberships(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	scimGroups := []*scimtypes.Group{}
+	for _, group := range memberships.groups {
+		scimGroup := scimtypes.NewGroup(group, memberships.membersOf(group.ID))
+		if !query.Filter.Matches(scimGroup.Attributes()) {
+			continue
+		}
+
+		if query.ExcludeMembers {
+			scimGroup.Members = nil
+		}
+		scimGroups = append(scimGroups, scimGroup)
+	}
+
+	return scimtypes.NewListResponse(query, scimGroups), nil
+}
+
+func (module *module) GetGroup(ctx context.Context, orgID valuer.UUID, id valuer.UUID) (*scimtypes.Group, error) {
+	group, err := module.store.GetGroup(ctx, orgID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	memberships, err := module.listMemberships(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	return scimtypes.NewGroup(group, memberships.membersOf(group.ID)), nil
+}
+
+func (module *module) CreateGroup(ctx context.Context, orgID valuer.UUID, scimGroup *scimtypes.Group) (*scimtypes.Group, error) {
+	group, err := scimtypes.NewStorableGroup(orgID, scimGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	memberIDs, err := module.memberIDs(ctx, orgID, scimGroup.Members)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := module.store.CreateGroup(ctx, group, memberIDs); err != nil {
+		return nil, err
+	}
+
+	module.syncRoles(ctx, orgID, memberIDs)
+	return module.GetGroup(ctx, orgID, group.ID)
+}
+
+func (module *module) ReplaceGroup(ctx context.Context, orgID valuer.UUID, id valuer.UUID, scimGroup *scimtypes.Group) (*scimtypes.Group, error) {
+	if scimGroup.DisplayName == "" {
+		return nil, errors.New(errors.TypeInvalidInput, errors.CodeInvalidInput, "displayName of the group is required")
+	}
+
+	current, err := module.GetGroup(ctx, orgID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	group, err := module.store.GetGroup(ctx, orgID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	previousMemberIDs, err := module.memberIDs(ctx, orgID, current.Members)
+	if err != nil {
+		return nil, err
+	}
+
+	memberIDs, err := module.memberIDs(ctx, orgID, scimGroup.Members)
+	if err != nil {
+		return nil, err
+	}
+
+	group.DisplayName = scimGroup.DisplayName
+	group.ExternalID = scimGroup.ExternalID
+	group.UpdatedAt = time.Now()
+	if err := module.store.UpdateGroup(ctx, group, memberIDs); err != nil {
+		return nil, err
+	}
+
+	// the roles of the users who left the group are resolved again too
+	module.syncRoles(ctx, orgID, append(previousMemberIDs, memberIDs...))
+	return module.GetGroup(ctx, orgID, id)
+}
+
+func (module *module) PatchGroup(ctx context.Context, orgID valuer.UUID, id valuer.UUID, patch *scimtypes.PatchRequest) (*scimtypes.Group, error) {
+	scimGroup, err := module.GetGroup(ctx, orgID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := scimGroup.ApplyPatch(patch); err != nil {
+		return nil, err
+	}
+
+	return module.ReplaceGroup(ctx, orgID, id, scimGroup)
+}
+
+func (module *module) DeleteGroup(ctx context.Context, orgID valuer.UUID, id valuer.UUID) error {
+	current, err := module.GetGroup(ctx, orgID, id)
+	if err != nil {
+		return err
+	}
+
+	memberIDs, err := module.memberIDs(ctx, orgID, current.Members)
+	if err != nil {
+		return err
+	}
+
+	if err := module.store.DeleteGroup(ctx, orgID, id); err != nil {
+		return err
+	}
+
+	module.syncRoles(ctx, orgID, memberIDs)
+	return nil
+}
+
+// memberIDs checks that the members are users of the org and returns their ids.
+func (module *module) memberIDs(ctx context.Context, orgID valuer.UUID, members []scimtypes.MultiValued) ([]valuer.UUID, error) {
+	ids := []valuer.UUID{}
+	for _, member := range members {
+		id, err := valuer.NewUUID(member.Value)
+		if err != nil {
+			return nil, errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "member %q is not a user", member.Value)
+		}
+
+		if _, err := module.user.GetUserByID(ctx, orgID.StringValue(), id.StringValue()); err != nil {
+			if errors.Ast(err, errors.TypeNotFound) {
+				return nil, errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "member %q is not a user", member.Value)
+			}
+			return nil, err
+		}
+
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids, nil
+}
+
+// syncRoles updates the roles of the users to the ones mapped from their groups, failures are
+// logged and don't fail the change of the groups.
+func (module *module) syncRoles(ctx context.Context, orgID valuer.UUID, userIDs []valuer.UUID) {
+	memberships, err := module.listMemberships(ctx, orgID)
+	if err != nil {
+		module.settings.Logger().ErrorContext(ctx, "failed to list the groups of the org", "error", err)
+		return
+	}
+
+	for _, id := range userIDs {
+		user, err := module.user.GetUserByID(ctx, orgID.StringValue(), id.StringValue())
+		if err != nil {
+			module.settings.Logger().ErrorContext(ctx, "failed to get scim user", "id", id.StringValue(), "error", err)
+			continue
+		}
+
+		groups := []string{}
+		for _, group := range memberships.groupsOf(user.ID) {
+			groups = append(groups, group.DisplayName)
+		}
+
+		role, err := module.mappedRole(ctx, orgID, user.Email, groups)
+		if err != nil {
+			module.settings.Logger().ErrorContext(ctx, "failed to resolve the role of scim user", "email", user.Email, "error", err)
+			continue
+		}
+
+		if role == "" || role.String() == user.Role {
+			continue
+		}
+
+		if err := module.updateUser(ctx, &user.User, user.DisplayName, role); err != nil {
+			module.settings.Logger().WarnContext(ctx, "failed to update the role of scim user from its groups", "email", user.Email, "role", role, "groups", groups, "error", err)
+		}
+	}
+}
+
+// mappedRole resolves the role of the user from the role mapping of the domain of its email, it is
+// empty when the domain has no role mapping.
+func (module *module) mappedRole(ctx context.Context, orgID valuer.UUID, email string, groups []string) (types.Role, error) {
+	domain, err := module.user.GetAuthDomainByEmail(ctx, email)
+	if err != nil {
+		if errors.Ast(err, errors.TypeNotFound) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	if domain.RoleMapping == nil || domain.OrgID != orgID.StringValue() {
+		return "", nil
+	}
+
+	role, _ := domain.RoleMapping.Resolve(groups)
+	return role, nil
+}
+
+func (module *module) updateUser(ctx context.Context, user *types.User, displayName string, role types.Role) error {
+	previous := user.Role
+	if role.String() != previous && previous == types.RoleAdmin.String() {
+		admins, err := module.user.GetUsersByRoleInOrg(ctx, user.OrgID, types.RoleAdmin)
+		if err != nil {
+			return err
+		}
+
+		if len(admins) == 1 {
+			return errors.New(errors.TypeForbidden, errors.CodeForbidden, "cannot demote the last admin")
+		}
+	}
+
+	user.DisplayName = displayName
+	user.Role = role.String()
+	user.UpdatedAt = time.Now()
+	if _, err := module.user.UpdateUser(ctx, user.OrgID, user.ID.StringValue(), user); err != nil {
+		return err
+	}
+
+	if previous != user.Role {
+		module.settings.Logger().InfoContext(ctx, "updated role of scim user", "email", user.Email, "from", previous, "to", user.Role)
+	}
+
+	return nil
+}
+
+type memberships struct {
+	groups  []*scimtypes.StorableGroup
+	users   map[valuer.UUID]*types.User
+	members []*scimtypes.StorableGroupMember
+}
+
+func (module *module) listMemberships(ctx context.Context, orgID valuer.UUID) (*memberships, error) {
+	groups, err := module.store.ListGroups(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := module.store.ListMembers(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := module.user.ListUsers(ctx, orgID.StringValue())
+	if err != nil {
+		return nil, err
+	}
+
+	usersByID := make(map[valuer.UUID]*types.User, len(users))
+	for _, user := range users {
+		usersByID[user.ID] = &user.User
+	}
+
+	return &memberships{groups: groups, users: usersByID, members: members}, nil
+}
+
+func (m *memberships) groupsOf(userID valuer.UUID) []*scimtypes.StorableGroup {
+	groups := []*scimtypes.StorableGroup{}
+	for _, group := range m.groups {
+		if slices.ContainsFunc(m.members, func(member *scimtypes.StorableGroupMember) bool {
+			return member.GroupID == group.ID && member.UserID == userID
+		}) {
+			groups = append(groups, group)
+		}
+	}
+
+	return groups
+}
+
+func (m *memberships) membersOf(groupID valuer.UUID) []*types.User {
+	users := []*types.User{}
+	for _, member := range m.members {
+		if user, ok := m.users[member.UserID]; ok && member.GroupID == groupID {
+			users = append(users, user)
+		}
+	}
+
+	return users
+}
@@ -0,0 +1,110 @@
+package implscim
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/analytics/analyticstest"
+	"github.com/SigNoz/signoz/pkg/emailing/emailingtest"
+	"github.com/SigNoz/signoz/pkg/errors"
+	"github.com/SigNoz/signoz/pkg/factory/factorytest"
+	"github.com/SigNoz/signoz/pkg/modules/audit/implaudit"
+	"github.com/SigNoz/signoz/pkg/modules/user/impluser"
+	"github.com/SigNoz/signoz/pkg/query-service/utils"
+	"github.com/SigNoz/signoz/pkg/types"
+	"github.com/SigNoz/signoz/pkg/types/authtypes"
+	"github.com/SigNoz/signoz/pkg/types/scimtypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModule(t *testing.T) {
+	ctx := context.Background()
+	sqlStore := utils.NewQueryServiceDBForTests(t)
+	require.NoError(t, utils.CreateTestOrg(t, sqlStore))
+	orgID, err := utils.GetTestOrgId(sqlStore)
+	require.NoError(t, err)
+
+	providerSettings := factorytest.NewSettings()
+	user := impluser.NewModule(impluser.NewStore(sqlStore, providerSettings), authtypes.NewJWT("", time.Hour, time.Hour), emailingtest.New(), providerSettings, nil, nil, analyticstest.New(), implaudit.NewModule(implaudit.NewStore(sqlStore), providerSettings))
+	module := NewModule(NewStore(sqlStore), user, providerSettings)
+
+	admin, err := types.NewUser("admin", "admin@example.com", types.RoleAdmin.String(), orgID.StringValue())
+	require.NoError(t, err)
+	require.NoError(t, user.CreateUser(ctx, admin))
+	require.NoError(t, user.CreateDomain(ctx, &types.GettableOrgDomain{
+		StorableOrgDomain: types.StorableOrgDomain{Name: "example.com", OrgID: orgID.StringValue()},
+		RoleMapping: &types.RoleMapping{
+			GroupRoles:  map[string]types.Role{"sre": types.RoleEditor},
+			DefaultRole: types.RoleViewer,
+		},
+	}))
+
+	token, err := module.CreateToken(ctx, orgID, admin.Email, &scimtypes.PostableToken{Name: "okta"})
+	require.NoError(t, err)
+	authenticatedOrgID, err := module.Authenticate(ctx, token.Token)
+	require.NoError(t, err)
+	assert.Equal(t, orgID, authenticatedOrgID)
+	_, err = module.Authenticate(ctx, token.Token+"x")
+	assert.True(t, errors.Ast(err, errors.TypeUnauthenticated))
+
+	jane, err := module.CreateUser(ctx, orgID, &scimtypes.User{UserName: "jane@example.com", Name: &scimtypes.Name{GivenName: "Jane", FamilyName: "Doe"}})
+	require.NoError(t, err)
+	assert.Equal(t, "Jane Doe", jane.DisplayName)
+	assert.Equal(t, []scimtypes.MultiValued{{Value: types.RoleViewer.String(), Primary: true}}, jane.Roles)
+
+	_, err = module.CreateUser(ctx, orgID, &scimtypes.User{UserName: "jane@example.com"})
+	assert.True(t, errors.Ast(err, errors.TypeAlreadyExists))
+
+	// joining a mapped group promotes the user
+	sre, err := module.CreateGroup(ctx, orgID, &scimtypes.Group{DisplayName: "sre", Members: []scimtypes.MultiValued{{Value: jane.ID}}})
+	require.NoError(t, err)
+	assert.Equal(t, []scimtypes.MultiValued{{Value: jane.ID, Display: "jane@example.com"}}, sre.Members)
+
+	filter, err := scimtypes.ParseFilter(`userName eq "JANE@example.com"`)
+	require.NoError(t, err)
+	users, err := module.ListUsers(ctx, orgID, &scimtypes.ListQuery{Filter: filter, StartIndex: 1, Count: 10})
+	require.NoError(t, err)
+	require.Equal(t, 1, users.TotalResults)
+	listed := users.Resources.([]*scimtypes.User)[0]
+	assert.Equal(t, types.RoleEditor.String(), listed.Roles[0].Value)
+	assert.Equal(t, []scimtypes.MultiValued{{Value: sre.ID, Display: "sre"}}, listed.Groups)
+
+	// leaving it falls back to the default role of the mapping
+	janeID := valuer.MustNewUUID(jane.ID)
+	_, err = module.PatchGroup(ctx, orgID, valuer.MustNewUUID(sre.ID), patch(t, `[{"op": "remove", "path": "members[value eq \"`+jane.ID+`\"]"}]`))
+	require.NoError(t, err)
+	jane, err = module.GetUser(ctx, orgID, janeID)
+	require.NoError(t, err)
+	assert.Equal(t, types.RoleViewer.String(), jane.Roles[0].Value)
+	assert.Empty(t, jane.Groups)
+
+	// the last admin can't be demoted nor deprovisioned
+	_, err = module.PatchUser(ctx, orgID, admin.ID, patch(t, `[{"op": "replace", "path": "roles", "value": [{"value": "VIEWER"}]}]`))
+	assert.True(t, errors.Ast(err, errors.TypeForbidden))
+	assert.True(t, errors.Ast(module.DeleteUser(ctx, orgID, admin.ID), errors.TypeForbidden))
+
+	_, err = module.PatchUser(ctx, orgID, janeID, patch(t, `[{"op": "replace", "path": "userName", "value": "janet@example.com"}]`))
+	assert.True(t, errors.Asc(err, scimtypes.ErrCodeMutability))
+
+	// deactivated users are deleted
+	deactivated, err := module.PatchUser(ctx, orgID, janeID, patch(t, `[{"op": "replace", "path": "active", "value": false}]`))
+	require.NoError(t, err)
+	assert.False(t, *deactivated.Active)
+	_, err = module.GetUser(ctx, orgID, janeID)
+	assert.True(t, errors.Ast(err, errors.TypeNotFound))
+
+	require.NoError(t, module.DeleteGroup(ctx, orgID, valuer.MustNewUUID(sre.ID)))
+	groups, err := module.ListGroups(ctx, orgID, &scimtypes.ListQuery{Filter: scimtypes.Filter{}, StartIndex: 1, Count: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 0, groups.TotalResults)
+}
+
+func patch(t *testing.T, operations string) *scimtypes.PatchRequest {
+	patch := new(scimtypes.PatchRequest)
+	require.NoError(t, json.Unmarshal([]byte(`{"Operations": `+operations+`}`), patch))
+	return patch
+}
@@ -0,0 +1,251 @@
+package implscim
+
+import (
+	"context"
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/errors"
+	"github.com/SigNoz/signoz/pkg/sqlstore"
+	"github.com/SigNoz/signoz/pkg/types/scimtypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+)
+
+type store struct {
+	sqlstore sqlstore.SQLStore
+}
+
+func NewStore(sqlstore sqlstore.SQLStore) scimtypes.Store {
+	return &store{sqlstore: sqlstore}
+}
+
+func (store *store) CreateToken(ctx context.Context, token *scimtypes.StorableToken) error {
+	_, err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewInsert().
+		Model(token).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (store *store) ListTokens(ctx context.Context, orgID valuer.UUID) ([]*scimtypes.StorableToken, error) {
+	tokens := make([]*scimtypes.StorableToken, 0)
+	err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewSelect().
+		Model(&tokens).
+		Where("org_id = ?", orgID).
+		Order("created_at ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+func (store *store) DeleteToken(ctx context.Context, orgID valuer.UUID, id valuer.UUID) error {
+	result, err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewDelete().
+		Model(new(scimtypes.StorableToken)).
+		Where("org_id = ?", orgID).
+		Where("id = ?", id).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return errors.Newf(errors.TypeNotFound, scimtypes.ErrCodeTokenNotFound, "token with id: %s does not exist in org: %s", id.StringValue(), orgID.StringValue())
+	}
+
+	return nil
+}
+
+func (store *store) GetTokenByHash(ctx context.Context, hash string) (*scimtypes.StorableToken, error) {
+	token := new(scimtypes.StorableToken)
+	err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewSelect().
+		Model(token).
+		Where("token_hash = ?", hash).
+		Scan(ctx)
+	if err != nil {
+		return nil, store.sqlstore.WrapNotFoundErrf(err, scimtypes.ErrCodeTokenNotFound, "token does not exist")
+	}
+
+	return token, nil
+}
+
+func (store *store) UpdateTokenLastUsed(ctx context.Context, id valuer.UUID, lastUsed time.Time) error {
+	_, err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewUpdate().
+		Model(new(scimtypes.StorableToken)).
+		Set("last_used = ?", lastUsed).
+		Where("id = ?", id).
+		Exec(ctx)
+	return err
+}
+
+func (store *store) CreateGroup(ctx context.Context, group *scimtypes.StorableGroup, members []valuer.UUID) error {
+	return store.sqlstore.RunInTxCtx(ctx, nil, func(ctx context.Context) error {
+		_, err := store.
+			sqlstore.
+			BunDBCtx(ctx).
+			NewInsert().
+			Model(group).
+			Exec(ctx)
+		if err != nil {
+			return store.sqlstore.WrapAlreadyExistsErrf(err, scimtypes.ErrCodeGroupAlreadyExists, "group with name: %s already exists in org: %s", group.DisplayName, group.OrgID.StringValue())
+		}
+
+		return store.insertMembers(ctx, group, members)
+	})
+}
+
+func (store *store) GetGroup(ctx context.Context, orgID valuer.UUID, id valuer.UUID) (*scimtypes.StorableGroup, error) {
+	group := new(scimtypes.StorableGroup)
+	err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewSelect().
+		Model(group).
+		Where("org_id = ?", orgID).
+		Where("id = ?", id).
+		Scan(ctx)
+	if err != nil {
+		return nil, store.sqlstore.WrapNotFoundErrf(err, scimtypes.ErrCodeGroupNotFound, "group with id: %s does not exist in org: %s", id.StringValue(), orgID.StringValue())
+	}
+
+	return group, nil
+}
+
+func (store *store) ListGroups(ctx context.Context, orgID valuer.UUID) ([]*scimtypes.StorableGroup, error) {
+	groups := make([]*scimtypes.StorableGroup, 0)
+	err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewSelect().
+		Model(&groups).
+		Where("org_id = ?", orgID).
+		Order("created_at ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+func (store *store) UpdateGroup(ctx context.Context, group *scimtypes.StorableGroup, members []valuer.UUID) error {
+	return store.sqlstore.RunInTxCtx(ctx, nil, func(ctx context.Context) error {
+		_, err := store.
+			sqlstore.
+			BunDBCtx(ctx).
+			NewUpdate().
+			Model(group).
+			Where("org_id = ?", group.OrgID).
+			Where("id = ?", group.ID).
+			Exec(ctx)
+		if err != nil {
+			return store.sqlstore.WrapAlreadyExistsErrf(err, scimtypes.ErrCodeGroupAlreadyExists, "group with name: %s already exists in org: %s", group.DisplayName, group.OrgID.StringValue())
+		}
+
+		_, err = store.
+			sqlstore.
+			BunDBCtx(ctx).
+			NewDelete().
+			Model(new(scimtypes.StorableGroupMember)).
+			Where("org_id = ?", group.OrgID).
+			Where("group_id = ?", group.ID).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		return store.insertMembers(ctx, group, members)
+	})
+}
+
+func (store *store) DeleteGroup(ctx context.Context, orgID valuer.UUID, id valuer.UUID) error {
+	return store.sqlstore.RunInTxCtx(ctx, nil, func(ctx context.Context) error {
+		_, err := store.
+			sqlstore.
+			BunDBCtx(ctx).
+			NewDelete().
+			Model(new(scimtypes.StorableGroupMember)).
+			Where("org_id = ?", orgID).
+			Where("group_id = ?", id).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		_, err = store.
+			sqlstore.
+			BunDBCtx(ctx).
+			NewDelete().
+			Model(new(scimtypes.StorableGroup)).
+			Where("org_id = ?", orgID).
+			Where("id = ?", id).
+			Exec(ctx)
+		return err
+	})
+}
+
+func (store *store) ListMembers(ctx context.Context, orgID valuer.UUID) ([]*scimtypes.StorableGroupMember, error) {
+	members := make([]*scimtypes.StorableGroupMember, 0)
+	err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewSelect().
+		Model(&members).
+		Where("org_id = ?", orgID).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+func (store *store) DeleteMemberships(ctx context.Context, orgID valuer.UUID, userID valuer.UUID) error {
+	_, err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewDelete().
+		Model(new(scimtypes.StorableGroupMember)).
+		Where("org_id = ?", orgID).
+		Where("user_id = ?", userID).
+		Exec(ctx)
+	return err
+}
+
+func (store *store) insertMembers(ctx context.Context, group *scimtypes.StorableGroup, userIDs []valuer.UUID) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	members := make([]*scimtypes.StorableGroupMember, len(userIDs))
+	for i, userID := range userIDs {
+		members[i] = &scimtypes.StorableGroupMember{OrgID: group.OrgID, GroupID: group.ID, UserID: userID}
+	}
+
+	_, err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewInsert().
+		Model(&members).
+		Exec(ctx)
+	return err
+}
@@ -0,0 +1,88 @@
+package scim
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/SigNoz/signoz/pkg/types/scimtypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+)
+
+type Module interface {
+	// CreateToken creates a provisioning token for the org, the token is only returned once
+	CreateToken(ctx context.Context, orgID valuer.UUID, createdBy string, token *scimtypes.PostableToken) (*scimtypes.GettableToken, error)
+
+	// ListTokens lists the provisioning tokens of the org
+	ListTokens(ctx context.Context, orgID valuer.UUID) ([]*scimtypes.GettableToken, error)
+
+	// DeleteToken revokes the provisioning token
+	DeleteToken(ctx context.Context, orgID valuer.UUID, id valuer.UUID) error
+
+	// Authenticate returns the org of the provisioning token
+	Authenticate(ctx context.Context, token string) (valuer.UUID, error)
+
+	ListUsers(ctx context.Context, orgID valuer.UUID, query *scimtypes.ListQuery) (*scimtypes.ListResponse, error)
+
+	GetUser(ctx context.Context, orgID valuer.UUID, id valuer.UUID) (*scimtypes.User, error)
+
+	// CreateUser creates the user with the role of its roles or of its groups, VIEWER by default
+	CreateUser(ctx context.Context, orgID valuer.UUID, user *scimtypes.User) (*scimtypes.User, error)
+
+	// ReplaceUser updates the display name and the role of the user, inactive users are deleted
+	ReplaceUser(ctx context.Context, orgID valuer.UUID, id valuer.UUID, user *scimtypes.User) (*scimtypes.User, error)
+
+	// PatchUser applies the patch and updates the user like ReplaceUser, it returns nil when the user is deleted
+	PatchUser(ctx context.Context, orgID valuer.UUID, id valuer.UUID, patch *scimtypes.PatchRequest) (*scimtypes.User, error)
+
+	DeleteUser(ctx context.Context, orgID valuer.UUID, id valuer.UUID) error
+
+	ListGroups(ctx context.Context, orgID valuer.UUID, query *scimtypes.ListQuery) (*scimtypes.ListResponse, error)
+
+	GetGroup(ctx context.Context, orgID valuer.UUID, id valuer.UUID) (*scimtypes.Group, error)
+
+	// CreateGroup creates the group, the roles of its members are resolved from the role mapping of their domain
+	CreateGroup(ctx context.Context, orgID valuer.UUID, group *scimtypes.Group) (*scimtypes.Group, error)
+
+	ReplaceGroup(ctx context.Context, orgID valuer.UUID, id valuer.UUID, group *scimtypes.Group) (*scimtypes.Group, error)
+
+	PatchGroup(ctx context.Context, orgID valuer.UUID, id valuer.UUID, patch *scimtypes.PatchRequest) (*scimtypes.Group, error)
+
+	DeleteGroup(ctx context.Context, orgID valuer.UUID, id valuer.UUID) error
+}
+
+type Handler interface {
+	CreateToken(http.ResponseWriter, *http.Request)
+
+	ListTokens(http.ResponseWriter, *http.Request)
+
+	DeleteToken(http.ResponseWriter, *http.Request)
+
+	// Authenticate authenticates the requests of the directory with a provisioning token
+	Authenticate(next http.HandlerFunc) http.HandlerFunc
+
+	GetServiceProviderConfig(http.ResponseWriter, *http.Request)
+
+	ListUsers(http.ResponseWriter, *http.Request)
+
+	GetUser(http.ResponseWriter, *http.Request)
+
+	CreateUser(http.ResponseWriter, *http.Request)
+
+	ReplaceUser(http.ResponseWriter, *http.Request)
+
+	PatchUser(http.ResponseWriter, *http.Request)
+
+	DeleteUser(http.ResponseWriter, *http.Request)
+
+	ListGroups(http.ResponseWriter, *http.Request)
+
+	GetGroup(http.ResponseWriter, *http.Request)
+
+	CreateGroup(http.ResponseWriter, *http.Request)
+
+	ReplaceGroup(http.ResponseWriter, *http.Request)
+
+	PatchGroup(http.ResponseWriter, *http.Request)
+
+	DeleteGroup(http.ResponseWriter, *http.Request)
+}
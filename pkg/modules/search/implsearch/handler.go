@@ -0,0 +1,109 @@
+package implsearch
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/http/render"
+	"github.com/SigNoz/signoz/pkg/modules/search"
+	"github.com/SigNoz/signoz/pkg/types/authtypes"
+	"github.com/SigNoz/signoz/pkg/types/searchtypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+	"github.com/gorilla/mux"
+)
+
+type handler struct {
+	module search.Module
+}
+
+func NewHandler(module search.Module) search.Handler {
+	return &handler{module: module}
+}
+
+func (handler *handler) Search(rw http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	claims, err := authtypes.ClaimsFromContext(ctx)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	params, err := searchtypes.NewParams(r)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	items, err := handler.module.Search(ctx, valuer.MustNewUUID(claims.OrgID), valuer.MustNewUUID(claims.UserID), params)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	render.Success(rw, http.StatusOK, items)
+}
+
+func (handler *handler) Favorite(rw http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	claims, err := authtypes.ClaimsFromContext(ctx)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	searchType, id, err := parseItem(r)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	if err := handler.module.Favorite(ctx, valuer.MustNewUUID(claims.OrgID), valuer.MustNewUUID(claims.UserID), searchType, id.StringValue()); err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	render.Success(rw, http.StatusNoContent, nil)
+}
+
+func (handler *handler) Unfavorite(rw http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	claims, err := authtypes.ClaimsFromContext(ctx)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	searchType, id, err := parseItem(r)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	if err := handler.module.Unfavorite(ctx, valuer.MustNewUUID(claims.OrgID), valuer.MustNewUUID(claims.UserID), searchType, id.StringValue()); err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	render.Success(rw, http.StatusNoContent, nil)
+}
+
+func parseItem(r *http.Request) (searchtypes.Type, valuer.UUID, error) {
+	searchType, err := searchtypes.NewType(mux.Vars(r)["type"])
+	if err != nil {
+		return searchtypes.Type{}, valuer.UUID{}, err
+	}
+
+	id, err := valuer.NewUUID(mux.Vars(r)["id"])
+	if err != nil {
+		return searchtypes.Type{}, valuer.UUID{}, err
+	}
+
+	return searchType, id, nil
+}
@@ -0,0 +1,185 @@
+package implsearch
+
+import (
+	"context"
+	"slices"
+
+	"github.com/SigNoz/signoz/pkg/errors"
+	"github.com/SigNoz/signoz/pkg/modules/dashboard"
+	"github.com/SigNoz/signoz/pkg/modules/preference"
+	"github.com/SigNoz/signoz/pkg/modules/savedview"
+	"github.com/SigNoz/signoz/pkg/modules/search"
+	"github.com/SigNoz/signoz/pkg/types/authtypes"
+	"github.com/SigNoz/signoz/pkg/types/dashboardtypes"
+	"github.com/SigNoz/signoz/pkg/types/preferencetypes"
+	"github.com/SigNoz/signoz/pkg/types/ruletypes"
+	"github.com/SigNoz/signoz/pkg/types/savedviewtypes"
+	"github.com/SigNoz/signoz/pkg/types/searchtypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+)
+
+type module struct {
+	dashboard        dashboard.Module
+	savedView        savedview.Module
+	ruleStore        ruletypes.RuleStore
+	preference       preference.Module
+	permissionGetter authtypes.PermissionGetter
+}
+
+func NewModule(dashboard dashboard.Module, savedView savedview.Module, ruleStore ruletypes.RuleStore, preference preference.Module, permissionGetter authtypes.PermissionGetter) search.Module {
+	return &module{dashboard: dashboard, savedView: savedView, ruleStore: ruleStore, preference: preference, permissionGetter: permissionGetter}
+}
+
+// readPermissions are the permissions needed to see the items of each type.
+var readPermissions = map[searchtypes.Type]authtypes.Permission{
+	searchtypes.TypeDashboard: authtypes.PermissionDashboardsRead,
+	searchtypes.TypeView:      authtypes.PermissionSavedViewsRead,
+	searchtypes.TypeRule:      authtypes.PermissionAlertsRead,
+}
+
+func (module *module) Search(ctx context.Context, orgID valuer.UUID, userID valuer.UUID, params *searchtypes.Params) (*searchtypes.GettableItems, error) {
+	items, err := module.listItems(ctx, orgID, params.Types)
+	if err != nil {
+		return nil, err
+	}
+
+	favorites, err := module.getFavorites(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range items {
+		item.Favorite = slices.Contains(favorites, searchtypes.NewFavorite(item.Type, item.ID))
+	}
+
+	return searchtypes.Search(items, params), nil
+}
+
+func (module *module) Favorite(ctx context.Context, orgID valuer.UUID, userID valuer.UUID, searchType searchtypes.Type, id string) error {
+	items, err := module.listItems(ctx, orgID, []searchtypes.Type{searchType})
+	if err != nil {
+		return err
+	}
+
+	if !slices.ContainsFunc(items, func(item *searchtypes.Item) bool { return item.ID == id }) {
+		return errors.Newf(errors.TypeNotFound, errors.CodeNotFound, "%s with id %s doesn't exist", searchType.StringValue(), id)
+	}
+
+	favorites, err := module.getFavorites(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	favorite := searchtypes.NewFavorite(searchType, id)
+	if slices.Contains(favorites, favorite) {
+		return nil
+	}
+
+	if len(favorites) >= searchtypes.MaxFavorites {
+		return errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "at most %d items can be starred", searchtypes.MaxFavorites)
+	}
+
+	return module.preference.UpdateByUser(ctx, userID, preferencetypes.NameFavorites, append(favorites, favorite))
+}
+
+func (module *module) Unfavorite(ctx context.Context, _ valuer.UUID, userID valuer.UUID, searchType searchtypes.Type, id string) error {
+	favorites, err := module.getFavorites(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	favorite := searchtypes.NewFavorite(searchType, id)
+	if !slices.Contains(favorites, favorite) {
+		return nil
+	}
+
+	return module.preference.UpdateByUser(ctx, userID, preferencetypes.NameFavorites, slices.DeleteFunc(favorites, func(other string) bool { return other == favorite }))
+}
+
+// listItems lists the items of the types visible to the user of the context, every type when no type is given. The
+// types the user is not allowed to read are left out.
+func (module *module) listItems(ctx context.Context, orgID valuer.UUID, searchTypes []searchtypes.Type) ([]*searchtypes.Item, error) {
+	claims, err := authtypes.ClaimsFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	permissions, err := module.permissionGetter.GetPermissions(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	includes := func(searchType searchtypes.Type) bool {
+		if len(searchTypes) != 0 && !slices.Contains(searchTypes, searchType) {
+			return false
+		}
+
+		permission := readPermissions[searchType]
+		return claims.HasPermission(permission) == nil || slices.Contains(permissions, permission)
+	}
+
+	items := make([]*searchtypes.Item, 0)
+	if includes(searchtypes.TypeDashboard) {
+		dashboards, err := module.dashboard.List(ctx, orgID)
+		if err != nil {
+			return nil, err
+		}
+
+		gettableFolders, err := module.dashboard.ListFolders(ctx, orgID)
+		if err != nil {
+			return nil, err
+		}
+
+		folders := make([]*dashboardtypes.Folder, 0, len(gettableFolders))
+		for _, folder := range gettableFolders {
+			folders = append(folders, folder.Folder)
+		}
+
+		for _, dashboard := range dashboards {
+			items = append(items, searchtypes.NewDashboardItem(dashboard, dashboardtypes.NewFolderPath(folders, dashboard.FolderID)))
+		}
+	}
+
+	if includes(searchtypes.TypeView) {
+		views, err := module.savedView.List(ctx, orgID, &savedviewtypes.ListParams{})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, view := range views {
+			items = append(items, searchtypes.NewViewItem(view.SavedView))
+		}
+	}
+
+	if includes(searchtypes.TypeRule) {
+		rules, err := module.ruleStore.GetStoredRules(ctx, orgID.StringValue())
+		if err != nil {
+			return nil, err
+		}
+
+		for _, rule := range rules {
+			item, err := searchtypes.NewRuleItem(rule)
+			if err != nil {
+				return nil, err
+			}
+
+			items = append(items, item)
+		}
+	}
+
+	return items, nil
+}
+
+// getFavorites gets the favorites of the user, none when the user has never starred an item.
+func (module *module) getFavorites(ctx context.Context, userID valuer.UUID) ([]string, error) {
+	favorites, err := module.preference.GetByUser(ctx, userID, preferencetypes.NameFavorites)
+	if err != nil {
+		if errors.Ast(err, errors.TypeNotFound) {
+			return []string{}, nil
+		}
+
+		return nil, err
+	}
+
+	return favorites.Value.Strings(), nil
+}
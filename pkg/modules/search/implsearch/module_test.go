@@ -0,0 +1,142 @@
+package implsearch
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/analytics/analyticstest"
+	"github.com/SigNoz/signoz/pkg/errors"
+	"github.com/SigNoz/signoz/pkg/factory/factorytest"
+	"github.com/SigNoz/signoz/pkg/modules/audit/implaudit"
+	"github.com/SigNoz/signoz/pkg/modules/dashboard/impldashboard"
+	"github.com/SigNoz/signoz/pkg/modules/preference/implpreference"
+	"github.com/SigNoz/signoz/pkg/modules/savedview/implsavedview"
+	"github.com/SigNoz/signoz/pkg/query-service/utils"
+	"github.com/SigNoz/signoz/pkg/ruler/rulestore/sqlrulestore"
+	"github.com/SigNoz/signoz/pkg/types"
+	"github.com/SigNoz/signoz/pkg/types/authtypes"
+	"github.com/SigNoz/signoz/pkg/types/dashboardtypes"
+	"github.com/SigNoz/signoz/pkg/types/preferencetypes"
+	qbtypes "github.com/SigNoz/signoz/pkg/types/querybuildertypes/querybuildertypesv5"
+	"github.com/SigNoz/signoz/pkg/types/ruletypes"
+	"github.com/SigNoz/signoz/pkg/types/savedviewtypes"
+	"github.com/SigNoz/signoz/pkg/types/searchtypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type permissionGetter map[string][]authtypes.Permission
+
+func (getter permissionGetter) GetPermissions(_ context.Context, claims authtypes.Claims) ([]authtypes.Permission, error) {
+	return getter[claims.UserID], nil
+}
+
+func TestModuleSearch(t *testing.T) {
+	sqlStore := utils.NewQueryServiceDBForTests(t)
+	require.NoError(t, utils.CreateTestOrg(t, sqlStore))
+	orgID, err := utils.GetTestOrgId(sqlStore)
+	require.NoError(t, err)
+
+	// the favorites are kept in the preferences of a stored user
+	user, err := types.NewUser("jane", "jane@example.com", types.RoleEditor.String(), orgID.StringValue())
+	require.NoError(t, err)
+	_, err = sqlStore.BunDB().NewInsert().Model(user).Exec(context.Background())
+	require.NoError(t, err)
+	userID := user.ID
+	ctx := authtypes.NewContextWithClaims(context.Background(), authtypes.Claims{UserID: userID.StringValue(), Email: "jane@example.com", OrgID: orgID.StringValue(), Role: types.RoleEditor})
+
+	providerSettings := factorytest.NewSettings()
+	audit := implaudit.NewModule(implaudit.NewStore(sqlStore), providerSettings)
+	dashboard := impldashboard.NewModule(sqlStore, providerSettings, analyticstest.New(), audit)
+	ruleStore := sqlrulestore.NewRuleStore(sqlStore)
+	preference := implpreference.NewModule(implpreference.NewStore(sqlStore), preferencetypes.NewAvailablePreference())
+	savedView := implsavedview.NewModule(implsavedview.NewStore(sqlStore), preference, providerSettings, audit)
+	readerID := valuer.GenerateUUID().StringValue()
+	module := NewModule(dashboard, savedView, ruleStore, preference, permissionGetter{readerID: {authtypes.PermissionDashboardsRead}})
+
+	folder, err := dashboard.CreateFolder(ctx, orgID, "jane@example.com", &dashboardtypes.PostableFolder{Name: "databases"})
+	require.NoError(t, err)
+	subfolder, err := dashboard.CreateFolder(ctx, orgID, "jane@example.com", &dashboardtypes.PostableFolder{Name: "cache", ParentID: folder.ID.StringValue()})
+	require.NoError(t, err)
+
+	redis, err := dashboard.Create(ctx, orgID, "jane@example.com", userID, dashboardtypes.PostableDashboard{
+		"title": "redis",
+		"tags":  []any{"cache"},
+		"widgets": []any{map[string]any{
+			"id":         "a",
+			"panelTypes": "graph",
+			"query":      map[string]any{"queryType": "promql", "promql": []any{map[string]any{"name": "A", "query": "rate(redis_keyspace_hits[5m])"}}},
+		}},
+	})
+	require.NoError(t, err)
+	_, err = dashboard.Move(ctx, orgID, valuer.MustNewUUID(redis.ID), "jane@example.com", &dashboardtypes.MovableDashboard{FolderID: subfolder.ID.StringValue()})
+	require.NoError(t, err)
+	_, err = dashboard.Create(ctx, orgID, "jane@example.com", userID, dashboardtypes.PostableDashboard{"title": "payments", "description": "redis backed checkout"})
+	require.NoError(t, err)
+
+	view, err := savedView.Create(ctx, orgID, "jane@example.com", &savedviewtypes.PostableSavedView{
+		Name:       "redis errors",
+		SourcePage: "logs",
+		Tags:       []string{"cache"},
+		PanelType:  dashboardtypes.PanelTypeList,
+		CompositeQuery: qbtypes.CompositeQuery{Queries: []qbtypes.QueryEnvelope{
+			{Type: qbtypes.QueryTypeClickHouseSQL, Spec: qbtypes.ClickHouseQuery{Name: "A", Query: "SELECT * FROM signoz_logs.distributed_logs_v2"}},
+		}},
+	})
+	require.NoError(t, err)
+	viewID := view.ID
+
+	now := time.Now()
+	_, err = ruleStore.CreateRule(ctx, &ruletypes.Rule{
+		Identifiable:  types.Identifiable{ID: valuer.GenerateUUID()},
+		TimeAuditable: types.TimeAuditable{CreatedAt: now, UpdatedAt: now},
+		OrgID:         orgID.StringValue(),
+		Data:          `{"alert": "high memory", "tags": ["cache"], "condition": {"compositeQuery": {"queryType": "promql", "promQueries": {"A": {"query": "redis_memory_used_bytes > 1e9"}}}}}`,
+	}, func(context.Context, valuer.UUID) error { return nil })
+	require.NoError(t, err)
+
+	// the title matches rank first, the metrics used by the queries are searched as well
+	items, err := module.Search(ctx, orgID, userID, &searchtypes.Params{Query: "redis", Limit: searchtypes.DefaultLimit})
+	require.NoError(t, err)
+	require.Equal(t, 4, items.Total)
+	assert.Equal(t, redis.ID, items.Items[0].ID)
+	assert.Equal(t, searchtypes.TypeRule, items.Items[3].Type)
+
+	// only the types the user can read are searched
+	readerCtx := authtypes.NewContextWithClaims(context.Background(), authtypes.Claims{UserID: readerID, OrgID: orgID.StringValue()})
+	items, err = module.Search(readerCtx, orgID, userID, &searchtypes.Params{Query: "redis", Limit: searchtypes.DefaultLimit})
+	require.NoError(t, err)
+	require.Equal(t, 2, items.Total)
+	for _, item := range items.Items {
+		assert.Equal(t, searchtypes.TypeDashboard, item.Type)
+	}
+
+	items, err = module.Search(ctx, orgID, userID, &searchtypes.Params{Tags: []string{"cache"}, Types: []searchtypes.Type{searchtypes.TypeView, searchtypes.TypeRule}, Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 2, items.Total)
+	assert.Len(t, items.Items, 1)
+
+	// the folder filter includes the subfolders
+	items, err = module.Search(ctx, orgID, userID, &searchtypes.Params{FolderID: folder.ID.StringValue(), Limit: searchtypes.DefaultLimit})
+	require.NoError(t, err)
+	require.Equal(t, 1, items.Total)
+	assert.Equal(t, subfolder.ID.StringValue(), items.Items[0].FolderID)
+
+	err = module.Favorite(ctx, orgID, userID, searchtypes.TypeView, valuer.GenerateUUID().StringValue())
+	assert.True(t, errors.Ast(err, errors.TypeNotFound))
+	require.NoError(t, module.Favorite(ctx, orgID, userID, searchtypes.TypeView, viewID.StringValue()))
+	require.NoError(t, module.Favorite(ctx, orgID, userID, searchtypes.TypeView, viewID.StringValue()))
+
+	items, err = module.Search(ctx, orgID, userID, &searchtypes.Params{Favorites: true, Limit: searchtypes.DefaultLimit})
+	require.NoError(t, err)
+	require.Equal(t, 1, items.Total)
+	assert.Equal(t, viewID.StringValue(), items.Items[0].ID)
+	assert.True(t, items.Items[0].Favorite)
+
+	require.NoError(t, module.Unfavorite(ctx, orgID, userID, searchtypes.TypeView, viewID.StringValue()))
+	items, err = module.Search(ctx, orgID, userID, &searchtypes.Params{Favorites: true, Limit: searchtypes.DefaultLimit})
+	require.NoError(t, err)
+	assert.Zero(t, items.Total)
+}
@@ -0,0 +1,29 @@
+package search
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/SigNoz/signoz/pkg/types/searchtypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+)
+
+type Module interface {
+	// Search searches the dashboards, saved views and alert rules of the org visible to the user of the context, the
+	// favorites of the user are flagged. The types the user can not read are left out, folders only hold dashboards.
+	Search(ctx context.Context, orgID valuer.UUID, userID valuer.UUID, params *searchtypes.Params) (*searchtypes.GettableItems, error)
+
+	// Favorite stars the dashboard, saved view or alert rule for the user, it is kept in the favorites preference
+	Favorite(ctx context.Context, orgID valuer.UUID, userID valuer.UUID, searchType searchtypes.Type, id string) error
+
+	// Unfavorite unstars the item for the user, items deleted since they were starred can be unstarred
+	Unfavorite(ctx context.Context, orgID valuer.UUID, userID valuer.UUID, searchType searchtypes.Type, id string) error
+}
+
+type Handler interface {
+	Search(http.ResponseWriter, *http.Request)
+
+	Favorite(http.ResponseWriter, *http.Request)
+
+	Unfavorite(http.ResponseWriter, *http.Request)
+}
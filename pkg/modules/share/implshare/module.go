@@ -0,0 +1,364 @@
+package implshare
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/errors"
+	"github.com/SigNoz/signoz/pkg/modules/audit"
+	"github.com/SigNoz/signoz/pkg/modules/dashboard"
+	"github.com/SigNoz/signoz/pkg/modules/savedview"
+	"github.com/SigNoz/signoz/pkg/modules/share"
+	"github.com/SigNoz/signoz/pkg/modules/user"
+	"github.com/SigNoz/signoz/pkg/types/audittypes"
+	"github.com/SigNoz/signoz/pkg/types/authtypes"
+	"github.com/SigNoz/signoz/pkg/types/dashboardtypes"
+	qbtypes "github.com/SigNoz/signoz/pkg/types/querybuildertypes/querybuildertypesv5"
+	"github.com/SigNoz/signoz/pkg/types/savedviewtypes"
+	"github.com/SigNoz/signoz/pkg/types/sharetypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+)
+
+type module struct {
+	store     sharetypes.Store
+	jwt       *authtypes.JWT
+	dashboard dashboard.Module
+	savedView savedview.Module
+	user      user.Module
+	audit     audit.Module
+	throttle  *unlockThrottle
+}
+
+func NewModule(store sharetypes.Store, jwt *authtypes.JWT, dashboard dashboard.Module, savedView savedview.Module, user user.Module, audit audit.Module) share.Module {
+	return &module{store: store, jwt: jwt, dashboard: dashboard, savedView: savedView, user: user, audit: audit, throttle: newUnlockThrottle()}
+}
+
+func (module *module) Create(ctx context.Context, orgID valuer.UUID, createdBy string, postable *sharetypes.PostableShareLink) (*sharetypes.GettableShareLink, error) {
+	if err := postable.Validate(time.Now()); err != nil {
+		return nil, err
+	}
+
+	link, err := sharetypes.NewShareLink(orgID, createdBy, postable)
+	if err != nil {
+		return nil, err
+	}
+
+	// the shared resource must exist and the shared widget must query
+	if link.ResourceType == sharetypes.ResourceTypeDashboard && link.WidgetID != "" {
+		if _, err := module.getWidget(ctx, link, link.WidgetID); err != nil {
+			return nil, err
+		}
+	} else {
+		if _, err := module.getShared(ctx, link); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := module.store.Create(ctx, link); err != nil {
+		return nil, err
+	}
+
+	gettable, err := module.newGettableShareLink(link)
+	if err != nil {
+		return nil, err
+	}
+
+	module.audit.Record(ctx, orgID, audittypes.ActionCreate, audittypes.NewResource(audittypes.ResourceTypeShareLink, link.ID.StringValue()), nil, sharetypes.NewGettableShareLink(link, ""))
+	return gettable, nil
+}
+
+func (module *module) List(ctx context.Context, orgID valuer.UUID) ([]*sharetypes.GettableShareLink, error) {
+	links, err := module.store.List(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	gettables := make([]*sharetypes.GettableShareLink, 0, len(links))
+	for _, link := range links {
+		// the tokens of the links are only listed to the users who can view what they share
+		if _, err := module.getShared(ctx, link); err != nil {
+			if errors.Ast(err, errors.TypeNotFound) {
+				continue
+			}
+
+			return nil, err
+		}
+
+		gettable, err := module.newGettableShareLink(link)
+		if err != nil {
+			return nil, err
+		}
+
+		gettables = append(gettables, gettable)
+	}
+
+	return gettables, nil
+}
+
+func (module *module) Revoke(ctx context.Context, orgID valuer.UUID, id valuer.UUID) error {
+	link, err := module.store.Get(ctx, orgID, id)
+	if err != nil {
+		return err
+	}
+
+	if link.RevokedAt != nil {
+		return nil
+	}
+
+	before := sharetypes.NewGettableShareLink(link, "")
+	link.Revoke()
+	if err := module.store.Update(ctx, link); err != nil {
+		return err
+	}
+
+	module.audit.Record(ctx, orgID, audittypes.ActionUpdate, audittypes.NewResource(audittypes.ResourceTypeShareLink, id.StringValue()), before, sharetypes.NewGettableShareLink(link, ""))
+	return nil
+}
+
+func (module *module) Authenticate(ctx context.Context, token string) (*sharetypes.ShareLink, error) {
+	link, claims, err := module.getLink(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := link.CheckActive(time.Now(), claims.Unlocked); err != nil {
+		return nil, err
+	}
+
+	return link, nil
+}
+
+func (module *module) Unlock(ctx context.Context, token string, password string) (*sharetypes.GettableToken, error) {
+	link, _, err := module.getLink(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := link.CheckActive(time.Now(), true); err != nil {
+		return nil, err
+	}
+
+	// the passwords are throttled for every client of the link, the public route is open to guesses
+	key := link.ID.StringValue() + "/" + audittypes.OriginFromContext(ctx).ClientAddress
+	if !module.throttle.allowed(key, time.Now()) {
+		return nil, errors.New(errors.TypeForbidden, sharetypes.ErrCodeUnlockThrottled, "too many invalid passwords, try again later")
+	}
+
+	if err := link.CheckPassword(password); err != nil {
+		if errors.Asc(err, sharetypes.ErrCodeShareLinkPassword) {
+			module.throttle.fail(key, time.Now())
+		}
+
+		return nil, err
+	}
+	module.throttle.reset(key)
+
+	expiresAt := time.Now().Add(sharetypes.UnlockExpiry)
+	if link.ExpiresAt.Before(expiresAt) {
+		expiresAt = link.ExpiresAt
+	}
+
+	unlocked, _, err := module.jwt.ShareToken(link.OrgID.StringValue(), link.ID.StringValue(), true, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sharetypes.GettableToken{Token: unlocked, ExpiresAt: expiresAt}, nil
+}
+
+func (module *module) GetShared(ctx context.Context, link *sharetypes.ShareLink) (*sharetypes.GettableSharedResource, error) {
+	ctx, err := module.creatorContext(ctx, link)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := module.getShared(ctx, link)
+	if err != nil {
+		return nil, err
+	}
+
+	start, end := link.TimeRange.Resolve(time.Now())
+	return &sharetypes.GettableSharedResource{
+		ResourceType: link.ResourceType,
+		WidgetID:     link.WidgetID,
+		Start:        start,
+		End:          end,
+		ExpiresAt:    link.ExpiresAt,
+		Data:         data,
+	}, nil
+}
+
+func (module *module) NewQueryRangeRequest(ctx context.Context, link *sharetypes.ShareLink, widgetID string) (*qbtypes.QueryRangeRequest, error) {
+	if err := link.CheckWidget(widgetID); err != nil {
+		return nil, err
+	}
+
+	ctx, err := module.creatorContext(ctx, link)
+	if err != nil {
+		return nil, err
+	}
+
+	widget, err := module.getWidget(ctx, link, widgetID)
+	if err != nil {
+		return nil, err
+	}
+
+	compositeQuery, err := widget.Query.CompositeQuery()
+	if err != nil {
+		return nil, err
+	}
+
+	start, end := link.TimeRange.Resolve(time.Now())
+	req := &qbtypes.QueryRangeRequest{
+		SchemaVersion:  "v5",
+		Start:          start,
+		End:            end,
+		RequestType:    widget.PanelType.RequestType(),
+		CompositeQuery: compositeQuery,
+		Variables:      link.Variables,
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// creatorContext attaches the claims of the creator of the link to the context, the link shares what its creator can
+// still view. The links of the users no longer in the org are not found.
+func (module *module) creatorContext(ctx context.Context, link *sharetypes.ShareLink) (context.Context, error) {
+	ctx, err := module.user.NewContextWithClaimsOf(ctx, link.OrgID.StringValue(), link.CreatedBy)
+	if err != nil {
+		if errors.Ast(err, errors.TypeNotFound) {
+			return nil, errors.Newf(errors.TypeNotFound, sharetypes.ErrCodeShareLinkNotFound, "share link with id: %s does not exist", link.ID.StringValue())
+		}
+
+		return nil, err
+	}
+
+	return ctx, nil
+}
+
+// getLink gets the link of the token, whether the link can still be used is left to the caller.
+func (module *module) getLink(ctx context.Context, token string) (*sharetypes.ShareLink, authtypes.ShareClaims, error) {
+	claims, err := module.jwt.ShareClaims(token)
+	if err != nil {
+		return nil, authtypes.ShareClaims{}, err
+	}
+
+	orgID, err := valuer.NewUUID(claims.OrgID)
+	if err != nil {
+		return nil, authtypes.ShareClaims{}, errors.Wrapf(err, errors.TypeUnauthenticated, errors.CodeUnauthenticated, "invalid share token")
+	}
+
+	id, err := valuer.NewUUID(claims.LinkID)
+	if err != nil {
+		return nil, authtypes.ShareClaims{}, errors.Wrapf(err, errors.TypeUnauthenticated, errors.CodeUnauthenticated, "invalid share token")
+	}
+
+	link, err := module.store.Get(ctx, orgID, id)
+	if err != nil {
+		return nil, authtypes.ShareClaims{}, err
+	}
+
+	return link, claims, nil
+}
+
+// getShared gets the data of the resource shared by the link, the dashboard is restricted to the shared widget and
+// the saved view is stripped of its authors.
+func (module *module) getShared(ctx context.Context, link *sharetypes.ShareLink) (any, error) {
+	id, err := valuer.NewUUID(link.ResourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if link.ResourceType == sharetypes.ResourceTypeSavedView {
+		view, err := module.savedView.Get(ctx, link.OrgID, id)
+		if err != nil {
+			return nil, err
+		}
+
+		view.CreatedBy, view.UpdatedBy = "", ""
+		return view.SavedView, nil
+	}
+
+	dashboard, err := module.dashboard.Get(ctx, link.OrgID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	data := dashboard.Data
+	if link.WidgetID == "" {
+		return data, nil
+	}
+
+	widgets, _ := data["widgets"].([]any)
+	data["widgets"] = slices.DeleteFunc(widgets, func(item any) bool {
+		widget, _ := item.(map[string]any)
+		return widget["id"] != link.WidgetID
+	})
+
+	layout, _ := data["layout"].([]any)
+	data["layout"] = slices.DeleteFunc(layout, func(item any) bool {
+		layoutItem, _ := item.(map[string]any)
+		return layoutItem["i"] != link.WidgetID
+	})
+
+	return data, nil
+}
+
+// getWidget gets the widget of the resource shared by the link, saved views are queried as a single widget.
+func (module *module) getWidget(ctx context.Context, link *sharetypes.ShareLink, widgetID string) (*dashboardtypes.Widget, error) {
+	id, err := valuer.NewUUID(link.ResourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if link.ResourceType == sharetypes.ResourceTypeSavedView {
+		view, err := module.savedView.Get(ctx, link.OrgID, id)
+		if err != nil {
+			return nil, err
+		}
+
+		return savedviewtypes.NewWidget(view.SavedView)
+	}
+
+	dashboard, err := module.dashboard.Get(ctx, link.OrgID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	spec, err := dashboardtypes.NewSpec(dashboard.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, widget := range spec.Widgets {
+		if widget == nil || widget.ID != widgetID {
+			continue
+		}
+
+		if widget.Query == nil {
+			return nil, errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "widget: %s has no queries", widgetID)
+		}
+
+		return widget, nil
+	}
+
+	return nil, errors.Newf(errors.TypeNotFound, errors.CodeNotFound, "widget: %s does not exist in dashboard: %s", widgetID, link.ResourceID)
+}
+
+func (module *module) newGettableShareLink(link *sharetypes.ShareLink) (*sharetypes.GettableShareLink, error) {
+	if link.CheckActive(time.Now(), true) != nil {
+		return sharetypes.NewGettableShareLink(link, ""), nil
+	}
+
+	token, _, err := module.jwt.ShareToken(link.OrgID.StringValue(), link.ID.StringValue(), false, link.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return sharetypes.NewGettableShareLink(link, token), nil
+}
@@ -0,0 +1,300 @@
+package implshare
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/analytics/analyticstest"
+	"github.com/SigNoz/signoz/pkg/emailing/emailingtest"
+	"github.com/SigNoz/signoz/pkg/errors"
+	"github.com/SigNoz/signoz/pkg/factory/factorytest"
+	"github.com/SigNoz/signoz/pkg/modules/audit/implaudit"
+	"github.com/SigNoz/signoz/pkg/modules/dashboard/impldashboard"
+	"github.com/SigNoz/signoz/pkg/modules/preference/implpreference"
+	"github.com/SigNoz/signoz/pkg/modules/savedview/implsavedview"
+	"github.com/SigNoz/signoz/pkg/modules/user/impluser"
+	"github.com/SigNoz/signoz/pkg/query-service/utils"
+	"github.com/SigNoz/signoz/pkg/types"
+	"github.com/SigNoz/signoz/pkg/types/audittypes"
+	"github.com/SigNoz/signoz/pkg/types/authtypes"
+	"github.com/SigNoz/signoz/pkg/types/dashboardtypes"
+	"github.com/SigNoz/signoz/pkg/types/preferencetypes"
+	qbtypes "github.com/SigNoz/signoz/pkg/types/querybuildertypes/querybuildertypesv5"
+	"github.com/SigNoz/signoz/pkg/types/savedviewtypes"
+	"github.com/SigNoz/signoz/pkg/types/sharetypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const dashboardData = `{
+	"version": "v4",
+	"title": "redis",
+	"layout": [{"i": "a", "x": 0, "y": 0, "w": 6, "h": 3}, {"i": "b", "x": 6, "y": 0, "w": 6, "h": 3}],
+	"widgets": [{
+		"id": "a",
+		"title": "hits",
+		"panelTypes": "graph",
+		"query": {
+			"queryType": "builder",
+			"builder": {
+				"queryData": [{
+					"queryName": "A",
+					"dataSource": "metrics",
+					"disabled": false,
+					"expression": "A",
+					"aggregateOperator": "sum_rate",
+					"aggregateAttribute": {"key": "redis_keyspace_hits"},
+					"filters": {"items": [{"key": {"key": "host_name"}, "op": "in", "value": ["{{.host_name}}"]}], "op": "AND"},
+					"stepInterval": 60
+				}],
+				"queryFormulas": []
+			}
+		}
+	}, {
+		"id": "b",
+		"title": "keys",
+		"panelTypes": "value",
+		"query": {"queryType": "clickhouse_sql", "clickhouse_sql": [{"name": "A", "query": "SELECT count() FROM signoz_logs.distributed_logs_v2", "disabled": false}]}
+	}]
+}`
+
+func newTestModule(t *testing.T) (context.Context, valuer.UUID, *module, valuer.UUID, valuer.UUID) {
+	sqlStore := utils.NewQueryServiceDBForTests(t)
+	require.NoError(t, utils.CreateTestOrg(t, sqlStore))
+	orgID, err := utils.GetTestOrgId(sqlStore)
+	require.NoError(t, err)
+
+	providerSettings := factorytest.NewSettings()
+	audit := implaudit.NewModule(implaudit.NewStore(sqlStore), providerSettings)
+
+	// the links share what their creator can view
+	user := impluser.NewModule(impluser.NewStore(sqlStore, providerSettings), authtypes.NewJWT("", time.Hour, time.Hour), emailingtest.New(), providerSettings, nil, nil, analyticstest.New(), audit)
+	editor, err := types.NewUser("editor", "editor@example.com", types.RoleEditor.String(), orgID.StringValue())
+	require.NoError(t, err)
+	require.NoError(t, user.CreateUser(context.Background(), editor))
+
+	userID := editor.ID
+	ctx := authtypes.NewContextWithClaims(context.Background(), authtypes.Claims{UserID: userID.StringValue(), Email: "editor@example.com", OrgID: orgID.StringValue(), Role: types.RoleEditor})
+	dashboard := impldashboard.NewModule(sqlStore, providerSettings, analyticstest.New(), audit)
+	preference := implpreference.NewModule(implpreference.NewStore(sqlStore), preferencetypes.NewAvailablePreference())
+	savedView := implsavedview.NewModule(implsavedview.NewStore(sqlStore), preference, providerSettings, audit)
+	jwt := authtypes.NewJWT("secret", time.Hour, time.Hour)
+
+	data := dashboardtypes.PostableDashboard{}
+	require.NoError(t, json.Unmarshal([]byte(dashboardData), &data))
+	created, err := dashboard.Create(ctx, orgID, "editor@example.com", userID, data)
+	require.NoError(t, err)
+
+	view, err := savedView.Create(ctx, orgID, "editor@example.com", &savedviewtypes.PostableSavedView{
+		Name:       "errors",
+		SourcePage: "logs",
+		PanelType:  dashboardtypes.PanelTypeList,
+		CompositeQuery: qbtypes.CompositeQuery{Queries: []qbtypes.QueryEnvelope{
+			{Type: qbtypes.QueryTypeClickHouseSQL, Spec: qbtypes.ClickHouseQuery{Name: "A", Query: "SELECT * FROM signoz_logs.distributed_logs_v2"}},
+		}},
+	})
+	require.NoError(t, err)
+
+	return ctx, orgID, NewModule(NewStore(sqlStore), jwt, dashboard, savedView, user, audit).(*module), valuer.MustNewUUID(created.ID), view.ID
+}
+
+func TestModuleSharePanel(t *testing.T) {
+	ctx, orgID, module, dashboardID, _ := newTestModule(t)
+
+	_, err := module.Create(ctx, orgID, "editor@example.com", &sharetypes.PostableShareLink{
+		ResourceType: sharetypes.ResourceTypeDashboard,
+		ResourceID:   dashboardID.StringValue(),
+		WidgetID:     "c",
+		TimeRange:    sharetypes.TimeRange{Relative: "1h"},
+		ExpiresAt:    time.Now().Add(time.Hour),
+	})
+	assert.True(t, errors.Ast(err, errors.TypeNotFound))
+
+	link, err := module.Create(ctx, orgID, "editor@example.com", &sharetypes.PostableShareLink{
+		ResourceType: sharetypes.ResourceTypeDashboard,
+		ResourceID:   dashboardID.StringValue(),
+		WidgetID:     "a",
+		TimeRange:    sharetypes.TimeRange{Relative: "1h"},
+		Variables:    map[string]any{"host_name": "redis-0"},
+		ExpiresAt:    time.Now().Add(time.Hour),
+		Password:     "correct horse",
+	})
+	require.NoError(t, err)
+	assert.True(t, link.Protected)
+	require.NotEmpty(t, link.Token)
+
+	// the token of a protected link is useless until the password is verified
+	_, err = module.Authenticate(ctx, link.Token)
+	assert.True(t, errors.Asc(err, sharetypes.ErrCodeShareLinkLocked))
+	_, err = module.Unlock(ctx, link.Token, "battery staple")
+	assert.True(t, errors.Asc(err, sharetypes.ErrCodeShareLinkPassword))
+
+	// the clients guessing passwords are refused, even the right one, other clients are not
+	guessing := audittypes.NewContextWithOrigin(ctx, audittypes.Origin{ClientAddress: "192.0.2.10"})
+	for i := 0; i < maxUnlockFailedAttempts; i++ {
+		_, err = module.Unlock(guessing, link.Token, "battery staple")
+		assert.True(t, errors.Asc(err, sharetypes.ErrCodeShareLinkPassword))
+	}
+	_, err = module.Unlock(guessing, link.Token, "correct horse")
+	assert.True(t, errors.Asc(err, sharetypes.ErrCodeUnlockThrottled))
+
+	unlocked, err := module.Unlock(ctx, link.Token, "correct horse")
+	require.NoError(t, err)
+	assert.False(t, unlocked.ExpiresAt.After(link.ExpiresAt))
+
+	authenticated, err := module.Authenticate(ctx, unlocked.Token)
+	require.NoError(t, err)
+	assert.Equal(t, link.ID, authenticated.ID)
+
+	shared, err := module.GetShared(ctx, authenticated)
+	require.NoError(t, err)
+	data := shared.Data.(dashboardtypes.StorableDashboardData)
+	require.Len(t, data["widgets"], 1)
+	require.Len(t, data["layout"], 1)
+	assert.Equal(t, "a", data["widgets"].([]any)[0].(map[string]any)["id"])
+	assert.Equal(t, uint64(time.Hour.Milliseconds()), shared.End-shared.Start)
+
+	req, err := module.NewQueryRangeRequest(ctx, authenticated, "a")
+	require.NoError(t, err)
+	assert.Equal(t, qbtypes.RequestTypeTimeSeries, req.RequestType)
+	assert.Equal(t, map[string]any{"host_name": "redis-0"}, req.Variables)
+	require.Len(t, req.CompositeQuery.Queries, 1)
+	assert.Equal(t, qbtypes.QueryTypeBuilder, req.CompositeQuery.Queries[0].Type)
+
+	// only the shared panel can be queried
+	_, err = module.NewQueryRangeRequest(ctx, authenticated, "b")
+	assert.True(t, errors.Asc(err, sharetypes.ErrCodeWidgetNotShared))
+
+	require.NoError(t, module.Revoke(ctx, orgID, link.ID))
+	_, err = module.Authenticate(ctx, unlocked.Token)
+	assert.True(t, errors.Asc(err, sharetypes.ErrCodeShareLinkRevoked))
+
+	links, err := module.List(ctx, orgID)
+	require.NoError(t, err)
+	require.Len(t, links, 1)
+	assert.NotNil(t, links[0].RevokedAt)
+	assert.Empty(t, links[0].Token)
+}
+
+func TestModuleShareDashboardAndSavedView(t *testing.T) {
+	ctx, orgID, module, dashboardID, viewID := newTestModule(t)
+
+	link, err := module.Create(ctx, orgID, "editor@example.com", &sharetypes.PostableShareLink{
+		ResourceType: sharetypes.ResourceTypeDashboard,
+		ResourceID:   dashboardID.StringValue(),
+		TimeRange:    sharetypes.TimeRange{Start: 1_700_000_000_000, End: 1_700_003_600_000},
+		ExpiresAt:    time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+	assert.False(t, link.Protected)
+
+	authenticated, err := module.Authenticate(ctx, link.Token)
+	require.NoError(t, err)
+
+	req, err := module.NewQueryRangeRequest(ctx, authenticated, "b")
+	require.NoError(t, err)
+	assert.Equal(t, qbtypes.RequestTypeScalar, req.RequestType)
+	assert.Equal(t, uint64(1_700_000_000_000), req.Start)
+	assert.Equal(t, uint64(1_700_003_600_000), req.End)
+
+	link, err = module.Create(ctx, orgID, "editor@example.com", &sharetypes.PostableShareLink{
+		ResourceType: sharetypes.ResourceTypeSavedView,
+		ResourceID:   viewID.StringValue(),
+		TimeRange:    sharetypes.TimeRange{Relative: "15m"},
+		ExpiresAt:    time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	authenticated, err = module.Authenticate(ctx, link.Token)
+	require.NoError(t, err)
+
+	shared, err := module.GetShared(ctx, authenticated)
+	require.NoError(t, err)
+	view := shared.Data.(*savedviewtypes.SavedView)
+	assert.Equal(t, "errors", view.Name)
+	assert.Empty(t, view.CreatedBy)
+
+	req, err = module.NewQueryRangeRequest(ctx, authenticated, "")
+	require.NoError(t, err)
+	assert.Equal(t, qbtypes.RequestTypeRaw, req.RequestType)
+	require.Len(t, req.CompositeQuery.Queries, 1)
+	assert.Equal(t, qbtypes.QueryTypeClickHouseSQL, req.CompositeQuery.Queries[0].Type)
+}
+
+func TestModuleShareRestrictedDashboard(t *testing.T) {
+	ctx, orgID, module, dashboardID, _ := newTestModule(t)
+	john := authtypes.NewContextWithClaims(context.Background(), authtypes.Claims{UserID: valuer.GenerateUUID().StringValue(), Email: "john@example.com", OrgID: orgID.StringValue(), Role: types.RoleEditor})
+
+	link, err := module.Create(ctx, orgID, "editor@example.com", &sharetypes.PostableShareLink{
+		ResourceType: sharetypes.ResourceTypeDashboard,
+		ResourceID:   dashboardID.StringValue(),
+		TimeRange:    sharetypes.TimeRange{Relative: "1h"},
+		ExpiresAt:    time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	links, err := module.List(john, orgID)
+	require.NoError(t, err)
+	require.Len(t, links, 1)
+
+	// the tokens of the links to the dashboards restricted to others are not listed
+	claims, err := authtypes.ClaimsFromContext(ctx)
+	require.NoError(t, err)
+	_, err = module.dashboard.SetGrants(ctx, orgID, dashboardID, &dashboardtypes.PostableGrants{Grants: []*dashboardtypes.PostableGrant{
+		{SubjectType: dashboardtypes.SubjectTypeUser, SubjectID: valuer.MustNewUUID(claims.UserID), Permission: dashboardtypes.PermissionViewer},
+	}})
+	require.NoError(t, err)
+
+	links, err = module.List(john, orgID)
+	require.NoError(t, err)
+	assert.Empty(t, links)
+
+	// the link keeps sharing what its creator can view
+	authenticated, err := module.Authenticate(context.Background(), link.Token)
+	require.NoError(t, err)
+	_, err = module.GetShared(context.Background(), authenticated)
+	require.NoError(t, err)
+}
+
+func TestModuleSharePrivateSavedView(t *testing.T) {
+	ctx, orgID, module, _, _ := newTestModule(t)
+	john := authtypes.NewContextWithClaims(context.Background(), authtypes.Claims{UserID: valuer.GenerateUUID().StringValue(), Email: "john@example.com", OrgID: orgID.StringValue(), Role: types.RoleEditor})
+
+	view, err := module.savedView.Create(ctx, orgID, "editor@example.com", &savedviewtypes.PostableSavedView{
+		Name:       "my errors",
+		SourcePage: "logs",
+		PanelType:  dashboardtypes.PanelTypeList,
+		Visibility: savedviewtypes.VisibilityPrivate,
+		CompositeQuery: qbtypes.CompositeQuery{Queries: []qbtypes.QueryEnvelope{
+			{Type: qbtypes.QueryTypeClickHouseSQL, Spec: qbtypes.ClickHouseQuery{Name: "A", Query: "SELECT * FROM signoz_logs.distributed_logs_v2"}},
+		}},
+	})
+	require.NoError(t, err)
+
+	link, err := module.Create(ctx, orgID, "editor@example.com", &sharetypes.PostableShareLink{
+		ResourceType: sharetypes.ResourceTypeSavedView,
+		ResourceID:   view.ID.StringValue(),
+		TimeRange:    sharetypes.TimeRange{Relative: "1h"},
+		ExpiresAt:    time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	// the tokens of the links to the private views of others are not listed
+	links, err := module.List(ctx, orgID)
+	require.NoError(t, err)
+	require.Len(t, links, 1)
+
+	links, err = module.List(john, orgID)
+	require.NoError(t, err)
+	assert.Empty(t, links)
+
+	// the link keeps sharing what its creator can view
+	authenticated, err := module.Authenticate(context.Background(), link.Token)
+	require.NoError(t, err)
+	shared, err := module.GetShared(context.Background(), authenticated)
+	require.NoError(t, err)
+	assert.Equal(t, "my errors", shared.Data.(*savedviewtypes.SavedView).Name)
+}
@@ -0,0 +1,81 @@
+package implshare
+
+import (
+	"context"
+
+	"github.com/SigNoz/signoz/pkg/sqlstore"
+	"github.com/SigNoz/signoz/pkg/types/sharetypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+)
+
+type store struct {
+	sqlstore sqlstore.SQLStore
+}
+
+func NewStore(sqlstore sqlstore.SQLStore) sharetypes.Store {
+	return &store{sqlstore: sqlstore}
+}
+
+func (store *store) Create(ctx context.Context, link *sharetypes.ShareLink) error {
+	_, err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewInsert().
+		Model(link).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (store *store) Get(ctx context.Context, orgID valuer.UUID, id valuer.UUID) (*sharetypes.ShareLink, error) {
+	link := new(sharetypes.ShareLink)
+	err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewSelect().
+		Model(link).
+		Where("org_id = ?", orgID).
+		Where("id = ?", id).
+		Scan(ctx)
+	if err != nil {
+		return nil, store.sqlstore.WrapNotFoundErrf(err, sharetypes.ErrCodeShareLinkNotFound, "share link with id: %s does not exist in org: %s", id.StringValue(), orgID.StringValue())
+	}
+
+	return link, nil
+}
+
+func (store *store) List(ctx context.Context, orgID valuer.UUID) ([]*sharetypes.ShareLink, error) {
+	links := make([]*sharetypes.ShareLink, 0)
+	err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewSelect().
+		Model(&links).
+		Where("org_id = ?", orgID).
+		Order("created_at DESC").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return links, nil
+}
+
+func (store *store) Update(ctx context.Context, link *sharetypes.ShareLink) error {
+	_, err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewUpdate().
+		Model(link).
+		Where("org_id = ?", link.OrgID).
+		Where("id = ?", link.ID).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
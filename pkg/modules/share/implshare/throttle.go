@@ -0,0 +1,68 @@
+package implshare
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// maxUnlockFailedAttempts is how many invalid passwords a client can send to unlock a link in a window.
+	maxUnlockFailedAttempts int = 10
+	unlockFailureWindow         = 15 * time.Minute
+)
+
+// unlockThrottle counts the invalid passwords sent to unlock a link from each client, the clients sending too many are
+// refused until the window of their first failure is over.
+type unlockThrottle struct {
+	mtx      sync.Mutex
+	failures map[string]*unlockFailures
+}
+
+type unlockFailures struct {
+	count int
+	since time.Time
+}
+
+func newUnlockThrottle() *unlockThrottle {
+	return &unlockThrottle{failures: map[string]*unlockFailures{}}
+}
+
+// allowed reports whether the client can still try passwords for the link.
+func (throttle *unlockThrottle) allowed(key string, now time.Time) bool {
+	throttle.mtx.Lock()
+	defer throttle.mtx.Unlock()
+
+	failures, ok := throttle.failures[key]
+	if !ok || now.Sub(failures.since) >= unlockFailureWindow {
+		return true
+	}
+
+	return failures.count < maxUnlockFailedAttempts
+}
+
+func (throttle *unlockThrottle) fail(key string, now time.Time) {
+	throttle.mtx.Lock()
+	defer throttle.mtx.Unlock()
+
+	// the windows that are over are dropped so that the failures of clients that gave up do not pile up
+	for k, failures := range throttle.failures {
+		if now.Sub(failures.since) >= unlockFailureWindow {
+			delete(throttle.failures, k)
+		}
+	}
+
+	failures, ok := throttle.failures[key]
+	if !ok {
+		failures = &unlockFailures{since: now}
+		throttle.failures[key] = failures
+	}
+
+	failures.count++
+}
+
+func (throttle *unlockThrottle) reset(key string) {
+	throttle.mtx.Lock()
+	defer throttle.mtx.Unlock()
+
+	delete(throttle.failures, key)
+}
@@ -0,0 +1,49 @@
+package share
+
+import (
+	"context"
+	"net/http"
+
+	qbtypes "github.com/SigNoz/signoz/pkg/types/querybuildertypes/querybuildertypesv5"
+	"github.com/SigNoz/signoz/pkg/types/sharetypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+)
+
+type Module interface {
+	// Create creates a link sharing a dashboard, a panel of a dashboard or a saved view of the org
+	Create(ctx context.Context, orgID valuer.UUID, createdBy string, link *sharetypes.PostableShareLink) (*sharetypes.GettableShareLink, error)
+
+	// List lists the links of the org to the resources the user of the context can view, the tokens of the active
+	// links are included
+	List(ctx context.Context, orgID valuer.UUID) ([]*sharetypes.GettableShareLink, error)
+
+	// Revoke revokes the link, its tokens are rejected from then on
+	Revoke(ctx context.Context, orgID valuer.UUID, id valuer.UUID) error
+
+	// Unlock verifies the password of the link of the token and issues a token unlocking it, the clients sending too
+	// many invalid passwords for the link are refused for a while
+	Unlock(ctx context.Context, token string, password string) (*sharetypes.GettableToken, error)
+
+	// GetShared gets the resource shared by the link as its creator can view it
+	GetShared(ctx context.Context, link *sharetypes.ShareLink) (*sharetypes.GettableSharedResource, error)
+
+	// NewQueryRangeRequest builds the query of a widget shared by the link from the stored queries of the widget, over
+	// the time range and with the variables of the link
+	NewQueryRangeRequest(ctx context.Context, link *sharetypes.ShareLink, widgetID string) (*qbtypes.QueryRangeRequest, error)
+
+	sharetypes.Authenticator
+}
+
+type Handler interface {
+	Create(http.ResponseWriter, *http.Request)
+
+	List(http.ResponseWriter, *http.Request)
+
+	Revoke(http.ResponseWriter, *http.Request)
+
+	// Unlock exchanges the token of a protected link and its password for an unlocked token
+	Unlock(http.ResponseWriter, *http.Request)
+
+	// GetShared gets the resource shared by the link as its creator can view it of the request
+	GetShared(http.ResponseWriter, *http.Request)
+}
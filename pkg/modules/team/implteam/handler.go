@@ -0,0 +1,375 @@
+package implteam
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/errors"
+	"github.com/SigNoz/signoz/pkg/http/render"
+	"github.com/SigNoz/signoz/pkg/modules/dashboard"
+	"github.com/SigNoz/signoz/pkg/modules/savedview"
+	"github.com/SigNoz/signoz/pkg/modules/team"
+	"github.com/SigNoz/signoz/pkg/types/authtypes"
+	"github.com/SigNoz/signoz/pkg/types/dashboardtypes"
+	"github.com/SigNoz/signoz/pkg/types/teamtypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+	"github.com/gorilla/mux"
+)
+
+type handler struct {
+	module    team.Module
+	dashboard dashboard.Module
+	savedView savedview.Module
+}
+
+func NewHandler(module team.Module, dashboard dashboard.Module, savedView savedview.Module) team.Handler {
+	return &handler{module: module, dashboard: dashboard, savedView: savedView}
+}
+
+func (handler *handler) Create(rw http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	claims, err := authtypes.ClaimsFromContext(ctx)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	req := new(teamtypes.PostableTeam)
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		render.Error(rw, errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "failed to decode request body"))
+		return
+	}
+
+	team, err := handler.module.Create(ctx, valuer.MustNewUUID(claims.OrgID), req)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	render.Success(rw, http.StatusCreated, team)
+}
+
+func (handler *handler) Get(rw http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	claims, err := authtypes.ClaimsFromContext(ctx)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	id, err := valuer.NewUUID(mux.Vars(r)["id"])
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	team, err := handler.module.Get(ctx, valuer.MustNewUUID(claims.OrgID), id)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	render.Success(rw, http.StatusOK, team)
+}
+
+func (handler *handler) List(rw http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	claims, err := authtypes.ClaimsFromContext(ctx)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	teams, err := handler.module.List(ctx, valuer.MustNewUUID(claims.OrgID))
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	render.Success(rw, http.StatusOK, teams)
+}
+
+func (handler *handler) Update(rw http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	claims, id, err := handler.maintainer(ctx, r)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	req := new(teamtypes.UpdatableTeam)
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		render.Error(rw, errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "failed to decode request body"))
+		return
+	}
+
+	team, err := handler.module.Update(ctx, valuer.MustNewUUID(claims.OrgID), id, req)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	render.Success(rw, http.StatusOK, team)
+}
+
+func (handler *handler) Delete(rw http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	claims, err := authtypes.ClaimsFromContext(ctx)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	id, err := valuer.NewUUID(mux.Vars(r)["id"])
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	if err := handler.module.Delete(ctx, valuer.MustNewUUID(claims.OrgID), id); err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	render.Success(rw, http.StatusNoContent, nil)
+}
+
+func (handler *handler) ListMembers(rw http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	claims, err := authtypes.ClaimsFromContext(ctx)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	id, err := valuer.NewUUID(mux.Vars(r)["id"])
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	members, err := handler.module.ListMembers(ctx, valuer.MustNewUUID(claims.OrgID), id)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	render.Success(rw, http.StatusOK, members)
+}
+
+func (handler *handler) AddMember(rw http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	claims, id, err := handler.maintainer(ctx, r)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	req := new(teamtypes.PostableMember)
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		render.Error(rw, errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "failed to decode request body"))
+		return
+	}
+
+	if err := handler.module.AddMember(ctx, valuer.MustNewUUID(claims.OrgID), id, req); err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	render.Success(rw, http.StatusNoContent, nil)
+}
+
+func (handler *handler) UpdateMember(rw http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	claims, id, err := handler.maintainer(ctx, r)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	userID, err := valuer.NewUUID(mux.Vars(r)["userId"])
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	req := new(teamtypes.UpdatableMember)
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		render.Error(rw, errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "failed to decode request body"))
+		return
+	}
+
+	if err := handler.module.UpdateMember(ctx, valuer.MustNewUUID(claims.OrgID), id, userID, req); err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	render.Success(rw, http.StatusNoContent, nil)
+}
+
+func (handler *handler) RemoveMember(rw http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	claims, id, err := handler.maintainer(ctx, r)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	userID, err := valuer.NewUUID(mux.Vars(r)["userId"])
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	if err := handler.module.RemoveMember(ctx, valuer.MustNewUUID(claims.OrgID), id, userID); err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	render.Success(rw, http.StatusNoContent, nil)
+}
+
+func (handler *handler) SetOwnership(rw http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	claims, id, err := handler.maintainer(ctx, r)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	req := new(teamtypes.PostableOwnership)
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		render.Error(rw, errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "failed to decode request body"))
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	// the resource has to exist in the org
+	resourceID, err := valuer.NewUUID(req.ResourceID)
+	if err != nil {
+		render.Error(rw, errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "resourceId is not a valid uuid"))
+		return
+	}
+
+	if err := handler.canOwn(ctx, claims, req.ResourceType, resourceID); err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	if err := handler.module.SetOwnership(ctx, valuer.MustNewUUID(claims.OrgID), id, req); err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	render.Success(rw, http.StatusNoContent, nil)
+}
+
+func (handler *handler) DeleteOwnership(rw http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	claims, id, err := handler.maintainer(ctx, r)
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	resourceType, err := teamtypes.NewResourceType(mux.Vars(r)["resourceType"])
+	if err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	if err := handler.module.DeleteOwnership(ctx, valuer.MustNewUUID(claims.OrgID), id, resourceType, mux.Vars(r)["resourceId"]); err != nil {
+		render.Error(rw, err)
+		return
+	}
+
+	render.Success(rw, http.StatusNoContent, nil)
+}
+
+// maintainer gets the claims of the request and the id of the team it is made for, the claims have to be allowed to
+// manage the team.
+// canOwn checks that the resource exists in the org and that the claims are allowed to hand it to a team, as an
+// editor of the resource or with the teams:manage permission.
+func (handler *handler) canOwn(ctx context.Context, claims authtypes.Claims, resourceType teamtypes.ResourceType, resourceID valuer.UUID) error {
+	orgID := valuer.MustNewUUID(claims.OrgID)
+	switch resourceType {
+	case teamtypes.ResourceTypeDashboard:
+		grants, err := handler.dashboard.GetGrants(ctx, orgID, resourceID)
+		if err != nil {
+			return err
+		}
+
+		if grants.Permission.Includes(dashboardtypes.PermissionOwner) {
+			return nil
+		}
+
+		if grants.Permission.Includes(dashboardtypes.PermissionEditor) {
+			if err := handler.module.HasPermission(ctx, claims, authtypes.PermissionDashboardsWrite); err == nil {
+				return nil
+			}
+		}
+	case teamtypes.ResourceTypeSavedView:
+		if _, err := handler.savedView.Get(ctx, orgID, resourceID); err != nil {
+			return err
+		}
+
+		if err := handler.module.HasPermission(ctx, claims, authtypes.PermissionSavedViewsWrite); err == nil {
+			return nil
+		}
+	}
+
+	if err := handler.module.HasPermission(ctx, claims, authtypes.PermissionTeamsManage); err != nil {
+		if errors.Ast(err, errors.TypeForbidden) {
+			return errors.Newf(errors.TypeForbidden, errors.CodeForbidden, "only editors of the %s can hand it to a team", resourceType.StringValue())
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (handler *handler) maintainer(ctx context.Context, r *http.Request) (authtypes.Claims, valuer.UUID, error) {
+	claims, err := authtypes.ClaimsFromContext(ctx)
+	if err != nil {
+		return authtypes.Claims{}, valuer.UUID{}, err
+	}
+
+	id, err := valuer.NewUUID(mux.Vars(r)["id"])
+	if err != nil {
+		return authtypes.Claims{}, valuer.UUID{}, err
+	}
+
+	if err := handler.module.CanMaintain(ctx, claims, id); err != nil {
+		return authtypes.Claims{}, valuer.UUID{}, err
+	}
+
+	return claims, id, nil
+}
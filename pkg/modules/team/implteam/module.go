@@ -0,0 +1,348 @@
+package implteam
+
+import (
+	"context"
+	"slices"
+
+	"github.com/SigNoz/signoz/pkg/alertmanager"
+	"github.com/SigNoz/signoz/pkg/errors"
+	"github.com/SigNoz/signoz/pkg/modules/audit"
+	"github.com/SigNoz/signoz/pkg/modules/team"
+	"github.com/SigNoz/signoz/pkg/modules/user"
+	"github.com/SigNoz/signoz/pkg/types"
+	"github.com/SigNoz/signoz/pkg/types/alertmanagertypes"
+	"github.com/SigNoz/signoz/pkg/types/audittypes"
+	"github.com/SigNoz/signoz/pkg/types/authtypes"
+	"github.com/SigNoz/signoz/pkg/types/teamtypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+)
+
+type module struct {
+	store            teamtypes.Store
+	alertmanager     alertmanager.Alertmanager
+	user             user.Module
+	permissionGetter authtypes.PermissionGetter
+	audit            audit.Module
+}
+
+func NewModule(store teamtypes.Store, alertmanager alertmanager.Alertmanager, user user.Module, permissionGetter authtypes.PermissionGetter, audit audit.Module) team.Module {
+	return &module{store: store, alertmanager: alertmanager, user: user, permissionGetter: permissionGetter, audit: audit}
+}
+
+func (module *module) Create(ctx context.Context, orgID valuer.UUID, postable *teamtypes.PostableTeam) (*teamtypes.GettableTeam, error) {
+	if err := postable.Validate(); err != nil {
+		return nil, err
+	}
+
+	// routes left behind by a deleted team of the same name are replaced
+	config, err := module.getConfigWithChannels(ctx, orgID, postable.Name, postable.Channels)
+	if err != nil {
+		return nil, err
+	}
+
+	storable := teamtypes.NewTeam(orgID, postable.Name, postable.DisplayName)
+	err = module.store.RunInTx(ctx, func(ctx context.Context) error {
+		if err := module.store.Create(ctx, storable); err != nil {
+			return err
+		}
+
+		return module.alertmanager.SetConfig(ctx, config)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	gettable := teamtypes.NewGettableTeam(storable, postable.Channels)
+	module.audit.Record(ctx, orgID, audittypes.ActionCreate, audittypes.NewResource(audittypes.ResourceTypeTeam, storable.ID.StringValue()), nil, gettable)
+	return gettable, nil
+}
+
+func (module *module) Get(ctx context.Context, orgID valuer.UUID, id valuer.UUID) (*teamtypes.GettableTeam, error) {
+	storable, err := module.store.Get(ctx, orgID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := module.alertmanager.GetConfig(ctx, orgID.StringValue())
+	if err != nil {
+		return nil, err
+	}
+
+	return teamtypes.NewGettableTeam(storable, config.ReceiverNamesFromTeam(storable.Name)), nil
+}
+
+func (module *module) List(ctx context.Context, orgID valuer.UUID) ([]*teamtypes.GettableTeam, error) {
+	storables, err := module.store.List(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := module.alertmanager.GetConfig(ctx, orgID.StringValue())
+	if err != nil {
+		return nil, err
+	}
+
+	gettables := make([]*teamtypes.GettableTeam, 0, len(storables))
+	for _, storable := range storables {
+		gettables = append(gettables, teamtypes.NewGettableTeam(storable, config.ReceiverNamesFromTeam(storable.Name)))
+	}
+
+	return gettables, nil
+}
+
+func (module *module) Update(ctx context.Context, orgID valuer.UUID, id valuer.UUID, updatable *teamtypes.UpdatableTeam) (*teamtypes.GettableTeam, error) {
+	if err := updatable.Validate(); err != nil {
+		return nil, err
+	}
+
+	before, err := module.Get(ctx, orgID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := module.getConfigWithChannels(ctx, orgID, before.Name, updatable.Channels)
+	if err != nil {
+		return nil, err
+	}
+
+	storable := *before.Team
+	storable.Update(updatable.DisplayName)
+	err = module.store.RunInTx(ctx, func(ctx context.Context) error {
+		if err := module.store.Update(ctx, &storable); err != nil {
+			return err
+		}
+
+		// the alertmanager config is only written when the channels change
+		if slices.Equal(before.Channels, updatable.Channels) {
+			return nil
+		}
+
+		return module.alertmanager.SetConfig(ctx, config)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	gettable := teamtypes.NewGettableTeam(&storable, updatable.Channels)
+	module.audit.Record(ctx, orgID, audittypes.ActionUpdate, audittypes.NewResource(audittypes.ResourceTypeTeam, id.StringValue()), before, gettable)
+	return gettable, nil
+}
+
+func (module *module) Delete(ctx context.Context, orgID valuer.UUID, id valuer.UUID) error {
+	before, err := module.Get(ctx, orgID, id)
+	if err != nil {
+		return err
+	}
+
+	config, err := module.getConfigWithChannels(ctx, orgID, before.Name, nil)
+	if err != nil {
+		return err
+	}
+
+	err = module.store.RunInTx(ctx, func(ctx context.Context) error {
+		if err := module.store.Delete(ctx, orgID, id); err != nil {
+			return err
+		}
+
+		return module.alertmanager.SetConfig(ctx, config)
+	})
+	if err != nil {
+		return err
+	}
+
+	module.audit.Record(ctx, orgID, audittypes.ActionDelete, audittypes.NewResource(audittypes.ResourceTypeTeam, id.StringValue()), before, nil)
+	return nil
+}
+
+func (module *module) ListMembers(ctx context.Context, orgID valuer.UUID, id valuer.UUID) ([]*teamtypes.GettableMember, error) {
+	if _, err := module.store.Get(ctx, orgID, id); err != nil {
+		return nil, err
+	}
+
+	members, err := module.store.ListMembers(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := module.user.ListUsers(ctx, orgID.StringValue())
+	if err != nil {
+		return nil, err
+	}
+
+	gettables := make([]*teamtypes.GettableMember, 0, len(members))
+	for _, member := range members {
+		index := slices.IndexFunc(users, func(user *types.GettableUser) bool { return user.ID == member.UserID })
+		if index == -1 {
+			continue
+		}
+
+		gettables = append(gettables, teamtypes.NewGettableMember(member, users[index]))
+	}
+
+	return gettables, nil
+}
+
+func (module *module) AddMember(ctx context.Context, orgID valuer.UUID, id valuer.UUID, postable *teamtypes.PostableMember) error {
+	if err := postable.Validate(); err != nil {
+		return err
+	}
+
+	if _, err := module.store.Get(ctx, orgID, id); err != nil {
+		return err
+	}
+
+	// only the users of the org can join its teams
+	if _, err := module.user.GetUserByID(ctx, orgID.StringValue(), postable.UserID.StringValue()); err != nil {
+		return err
+	}
+
+	if err := module.store.CreateMember(ctx, teamtypes.NewMember(id, postable.UserID, postable.Role)); err != nil {
+		return err
+	}
+
+	module.audit.Record(ctx, orgID, audittypes.ActionUpdate, audittypes.NewResource(audittypes.ResourceTypeTeam, id.StringValue()), nil, map[string]any{"members": map[string]any{postable.UserID.StringValue(): postable.Role}})
+	return nil
+}
+
+func (module *module) UpdateMember(ctx context.Context, orgID valuer.UUID, id valuer.UUID, userID valuer.UUID, updatable *teamtypes.UpdatableMember) error {
+	if err := updatable.Validate(); err != nil {
+		return err
+	}
+
+	if _, err := module.store.Get(ctx, orgID, id); err != nil {
+		return err
+	}
+
+	member, err := module.store.GetMember(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+
+	before := member.Role
+	member.Update(updatable.Role)
+	if err := module.store.UpdateMember(ctx, member); err != nil {
+		return err
+	}
+
+	module.audit.Record(ctx, orgID, audittypes.ActionUpdate, audittypes.NewResource(audittypes.ResourceTypeTeam, id.StringValue()), map[string]any{"members": map[string]any{userID.StringValue(): before}}, map[string]any{"members": map[string]any{userID.StringValue(): member.Role}})
+	return nil
+}
+
+func (module *module) RemoveMember(ctx context.Context, orgID valuer.UUID, id valuer.UUID, userID valuer.UUID) error {
+	if _, err := module.store.Get(ctx, orgID, id); err != nil {
+		return err
+	}
+
+	member, err := module.store.GetMember(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := module.store.DeleteMember(ctx, id, userID); err != nil {
+		return err
+	}
+
+	module.audit.Record(ctx, orgID, audittypes.ActionUpdate, audittypes.NewResource(audittypes.ResourceTypeTeam, id.StringValue()), map[string]any{"members": map[string]any{userID.StringValue(): member.Role}}, nil)
+	return nil
+}
+
+func (module *module) SetOwnership(ctx context.Context, orgID valuer.UUID, id valuer.UUID, postable *teamtypes.PostableOwnership) error {
+	if err := postable.Validate(); err != nil {
+		return err
+	}
+
+	if _, err := module.store.Get(ctx, orgID, id); err != nil {
+		return err
+	}
+
+	if err := module.store.SetOwnership(ctx, teamtypes.NewOwnership(orgID, id, postable.ResourceType, postable.ResourceID)); err != nil {
+		return err
+	}
+
+	module.audit.Record(ctx, orgID, audittypes.ActionUpdate, audittypes.NewResource(audittypes.ResourceTypeTeam, id.StringValue()), nil, map[string]any{"owns": map[string]any{postable.ResourceType.StringValue(): postable.ResourceID}})
+	return nil
+}
+
+func (module *module) DeleteOwnership(ctx context.Context, orgID valuer.UUID, id valuer.UUID, resourceType teamtypes.ResourceType, resourceID string) error {
+	if _, err := module.store.Get(ctx, orgID, id); err != nil {
+		return err
+	}
+
+	if err := module.store.DeleteOwnership(ctx, id, resourceType, resourceID); err != nil {
+		return err
+	}
+
+	module.audit.Record(ctx, orgID, audittypes.ActionUpdate, audittypes.NewResource(audittypes.ResourceTypeTeam, id.StringValue()), map[string]any{"owns": map[string]any{resourceType.StringValue(): resourceID}}, nil)
+	return nil
+}
+
+func (module *module) ListOwnedResourceIDs(ctx context.Context, orgID valuer.UUID, id valuer.UUID, resourceType teamtypes.ResourceType) ([]string, error) {
+	if _, err := module.store.Get(ctx, orgID, id); err != nil {
+		return nil, err
+	}
+
+	return module.store.ListOwnedResourceIDs(ctx, orgID, id, resourceType)
+}
+
+func (module *module) CanMaintain(ctx context.Context, claims authtypes.Claims, id valuer.UUID) error {
+	deniedErr := module.HasPermission(ctx, claims, authtypes.PermissionTeamsManage)
+	if deniedErr == nil {
+		return nil
+	}
+
+	if !errors.Ast(deniedErr, errors.TypeForbidden) {
+		return deniedErr
+	}
+
+	userID, err := valuer.NewUUID(claims.UserID)
+	if err != nil {
+		return deniedErr
+	}
+
+	member, err := module.store.GetMember(ctx, id, userID)
+	if err != nil {
+		if errors.Ast(err, errors.TypeNotFound) {
+			return deniedErr
+		}
+		return err
+	}
+
+	if member.Role != teamtypes.RoleMaintainer {
+		return deniedErr
+	}
+
+	return nil
+}
+
+// getConfigWithChannels gets the alertmanager config of the org with the alerts of the team routed to the channels, the
+// channels have to exist in the org. The config is read ahead of the transaction it is set in.
+func (module *module) getConfigWithChannels(ctx context.Context, orgID valuer.UUID, name string, channels []string) (*alertmanagertypes.Config, error) {
+	config, err := module.alertmanager.GetConfig(ctx, orgID.StringValue())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := config.SetTeamRoutes(name, channels); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+func (module *module) HasPermission(ctx context.Context, claims authtypes.Claims, permission authtypes.Permission) error {
+	deniedErr := claims.HasPermission(permission)
+	if deniedErr == nil {
+		return nil
+	}
+
+	permissions, err := module.permissionGetter.GetPermissions(ctx, claims)
+	if err != nil {
+		return err
+	}
+
+	if slices.Contains(permissions, permission) {
+		return nil
+	}
+
+	return deniedErr
+}
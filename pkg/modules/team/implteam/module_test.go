@@ -0,0 +1,139 @@
+package implteam
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/alertmanager"
+	"github.com/SigNoz/signoz/pkg/alertmanager/alertmanagerserver"
+	"github.com/SigNoz/signoz/pkg/alertmanager/signozalertmanager"
+	"github.com/SigNoz/signoz/pkg/analytics/analyticstest"
+	"github.com/SigNoz/signoz/pkg/emailing/emailingtest"
+	"github.com/SigNoz/signoz/pkg/errors"
+	"github.com/SigNoz/signoz/pkg/factory/factorytest"
+	"github.com/SigNoz/signoz/pkg/modules/audit/implaudit"
+	"github.com/SigNoz/signoz/pkg/modules/organization/implorganization"
+	"github.com/SigNoz/signoz/pkg/modules/role/implrole"
+	"github.com/SigNoz/signoz/pkg/modules/user/impluser"
+	"github.com/SigNoz/signoz/pkg/query-service/utils"
+	"github.com/SigNoz/signoz/pkg/sharder"
+	"github.com/SigNoz/signoz/pkg/sharder/noopsharder"
+	"github.com/SigNoz/signoz/pkg/types"
+	"github.com/SigNoz/signoz/pkg/types/alertmanagertypes"
+	"github.com/SigNoz/signoz/pkg/types/authtypes"
+	"github.com/SigNoz/signoz/pkg/types/teamtypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+	"github.com/prometheus/alertmanager/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModule(t *testing.T) {
+	ctx := context.Background()
+	sqlStore := utils.NewQueryServiceDBForTests(t)
+	require.NoError(t, utils.CreateTestOrg(t, sqlStore))
+	orgID, err := utils.GetTestOrgId(sqlStore)
+	require.NoError(t, err)
+
+	providerSettings := factorytest.NewSettings()
+	noop, err := noopsharder.New(ctx, providerSettings, sharder.Config{})
+	require.NoError(t, err)
+	orgGetter := implorganization.NewGetter(implorganization.NewStore(sqlStore), noop)
+	am, err := signozalertmanager.New(ctx, providerSettings, alertmanager.Config{Provider: "signoz", Signoz: alertmanager.Signoz{PollInterval: 10 * time.Second, Config: alertmanagerserver.NewConfig()}}, sqlStore, orgGetter)
+	require.NoError(t, err)
+	require.NoError(t, am.SetDefaultConfig(ctx, orgID.StringValue()))
+	_, err = am.CreateChannel(ctx, orgID.StringValue(), alertmanagertypes.Receiver{
+		Name:         "payments-slack",
+		SlackConfigs: []*config.SlackConfig{{Channel: "#payments", APIURL: &config.SecretURL{URL: &url.URL{Scheme: "https", Host: "slack.com", Path: "/api/test"}}}},
+	})
+	require.NoError(t, err)
+
+	audit := implaudit.NewModule(implaudit.NewStore(sqlStore), providerSettings)
+	user := impluser.NewModule(impluser.NewStore(sqlStore, providerSettings), authtypes.NewJWT("", time.Hour, time.Hour), emailingtest.New(), providerSettings, nil, nil, analyticstest.New(), audit)
+	module := NewModule(NewStore(sqlStore), am, user, implrole.NewModule(implrole.NewStore(sqlStore), user), audit)
+
+	jane, err := types.NewUser("jane", "jane@example.com", types.RoleViewer.String(), orgID.StringValue())
+	require.NoError(t, err)
+	require.NoError(t, user.CreateUser(ctx, jane))
+	john, err := types.NewUser("john", "john@example.com", types.RoleEditor.String(), orgID.StringValue())
+	require.NoError(t, err)
+	require.NoError(t, user.CreateUser(ctx, john))
+
+	_, err = module.Create(ctx, orgID, &teamtypes.PostableTeam{Name: "Payments"})
+	assert.True(t, errors.Ast(err, errors.TypeInvalidInput))
+	_, err = module.Create(ctx, orgID, &teamtypes.PostableTeam{Name: "payments", Channels: []string{"does-not-exist"}})
+	assert.True(t, errors.Ast(err, errors.TypeNotFound))
+
+	payments, err := module.Create(ctx, orgID, &teamtypes.PostableTeam{Name: "payments", Channels: []string{"payments-slack"}})
+	require.NoError(t, err)
+	assert.Equal(t, "payments", payments.DisplayName)
+	_, err = module.Create(ctx, orgID, &teamtypes.PostableTeam{Name: "payments"})
+	assert.True(t, errors.Ast(err, errors.TypeAlreadyExists))
+
+	// the alerts labelled with the team are routed to its channels
+	amConfig, err := am.GetConfig(ctx, orgID.StringValue())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"payments-slack"}, amConfig.ReceiverNamesFromTeam("payments"))
+
+	require.NoError(t, module.AddMember(ctx, orgID, payments.ID, &teamtypes.PostableMember{UserID: jane.ID, Role: teamtypes.RoleMaintainer}))
+	require.NoError(t, module.AddMember(ctx, orgID, payments.ID, &teamtypes.PostableMember{UserID: john.ID}))
+	err = module.AddMember(ctx, orgID, payments.ID, &teamtypes.PostableMember{UserID: john.ID})
+	assert.True(t, errors.Ast(err, errors.TypeAlreadyExists))
+	err = module.AddMember(ctx, orgID, payments.ID, &teamtypes.PostableMember{UserID: valuer.GenerateUUID()})
+	assert.True(t, errors.Ast(err, errors.TypeNotFound))
+
+	members, err := module.ListMembers(ctx, orgID, payments.ID)
+	require.NoError(t, err)
+	require.Len(t, members, 2)
+	assert.Equal(t, "jane@example.com", members[0].Email)
+	assert.Equal(t, teamtypes.RoleMember, members[1].Role)
+
+	// maintainers manage the team whatever their role in the org
+	assert.NoError(t, module.CanMaintain(ctx, authtypes.Claims{UserID: jane.ID.StringValue(), OrgID: orgID.StringValue(), Role: types.RoleViewer}, payments.ID))
+	assert.True(t, errors.Ast(module.CanMaintain(ctx, authtypes.Claims{UserID: john.ID.StringValue(), OrgID: orgID.StringValue(), Role: types.RoleEditor}, payments.ID), errors.TypeForbidden))
+	assert.NoError(t, module.CanMaintain(ctx, authtypes.Claims{UserID: valuer.GenerateUUID().StringValue(), OrgID: orgID.StringValue(), Role: types.RoleAdmin}, payments.ID))
+
+	// maintainers of a team do not hold the permissions of the resources it owns
+	assert.True(t, errors.Ast(module.HasPermission(ctx, authtypes.Claims{UserID: jane.ID.StringValue(), OrgID: orgID.StringValue(), Role: types.RoleViewer}, authtypes.PermissionDashboardsWrite), errors.TypeForbidden))
+	assert.NoError(t, module.HasPermission(ctx, authtypes.Claims{UserID: john.ID.StringValue(), OrgID: orgID.StringValue(), Role: types.RoleEditor}, authtypes.PermissionDashboardsWrite))
+
+	updated, err := module.Update(ctx, orgID, payments.ID, &teamtypes.UpdatableTeam{DisplayName: "Payments"})
+	require.NoError(t, err)
+	assert.Equal(t, "Payments", updated.DisplayName)
+	assert.Empty(t, updated.Channels)
+	amConfig, err = am.GetConfig(ctx, orgID.StringValue())
+	require.NoError(t, err)
+	assert.Empty(t, amConfig.ReceiverNamesFromTeam("payments"))
+
+	// a resource is owned by one team at most
+	search, err := module.Create(ctx, orgID, &teamtypes.PostableTeam{Name: "search", DisplayName: "Search"})
+	require.NoError(t, err)
+	dashboardID := valuer.GenerateUUID().StringValue()
+	require.NoError(t, module.SetOwnership(ctx, orgID, payments.ID, &teamtypes.PostableOwnership{ResourceType: teamtypes.ResourceTypeDashboard, ResourceID: dashboardID}))
+	owned, err := module.ListOwnedResourceIDs(ctx, orgID, payments.ID, teamtypes.ResourceTypeDashboard)
+	require.NoError(t, err)
+	assert.Equal(t, []string{dashboardID}, owned)
+
+	require.NoError(t, module.SetOwnership(ctx, orgID, search.ID, &teamtypes.PostableOwnership{ResourceType: teamtypes.ResourceTypeDashboard, ResourceID: dashboardID}))
+	owned, err = module.ListOwnedResourceIDs(ctx, orgID, payments.ID, teamtypes.ResourceTypeDashboard)
+	require.NoError(t, err)
+	assert.Empty(t, owned)
+	err = module.DeleteOwnership(ctx, orgID, payments.ID, teamtypes.ResourceTypeDashboard, dashboardID)
+	assert.True(t, errors.Ast(err, errors.TypeNotFound))
+
+	// removing a user from the org removes them from its teams
+	require.NoError(t, user.DeleteUser(ctx, orgID.StringValue(), john.ID.StringValue()))
+	members, err = module.ListMembers(ctx, orgID, payments.ID)
+	require.NoError(t, err)
+	assert.Len(t, members, 1)
+
+	require.NoError(t, module.Delete(ctx, orgID, payments.ID))
+	_, err = module.Get(ctx, orgID, payments.ID)
+	assert.True(t, errors.Ast(err, errors.TypeNotFound))
+	teams, err := module.List(ctx, orgID)
+	require.NoError(t, err)
+	require.Len(t, teams, 1)
+	assert.Equal(t, "search", teams[0].Name)
+}
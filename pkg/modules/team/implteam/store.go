@@ -0,0 +1,264 @@
+package implteam
+
+import (
+	"context"
+
+	"github.com/SigNoz/signoz/pkg/errors"
+	"github.com/SigNoz/signoz/pkg/sqlstore"
+	"github.com/SigNoz/signoz/pkg/types/teamtypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+)
+
+type store struct {
+	sqlstore sqlstore.SQLStore
+}
+
+func NewStore(sqlstore sqlstore.SQLStore) teamtypes.Store {
+	return &store{sqlstore: sqlstore}
+}
+
+func (store *store) Create(ctx context.Context, team *teamtypes.Team) error {
+	_, err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewInsert().
+		Model(team).
+		Exec(ctx)
+	if err != nil {
+		return store.sqlstore.WrapAlreadyExistsErrf(err, teamtypes.ErrCodeTeamAlreadyExists, "team with name: %s already exists in org: %s", team.Name, team.OrgID.StringValue())
+	}
+
+	return nil
+}
+
+func (store *store) Get(ctx context.Context, orgID valuer.UUID, id valuer.UUID) (*teamtypes.Team, error) {
+	team := new(teamtypes.Team)
+	err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewSelect().
+		Model(team).
+		Where("org_id = ?", orgID).
+		Where("id = ?", id).
+		Scan(ctx)
+	if err != nil {
+		return nil, store.sqlstore.WrapNotFoundErrf(err, teamtypes.ErrCodeTeamNotFound, "team with id: %s does not exist in org: %s", id.StringValue(), orgID.StringValue())
+	}
+
+	return team, nil
+}
+
+func (store *store) List(ctx context.Context, orgID valuer.UUID) ([]*teamtypes.Team, error) {
+	teams := make([]*teamtypes.Team, 0)
+	err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewSelect().
+		Model(&teams).
+		Where("org_id = ?", orgID).
+		Order("name ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return teams, nil
+}
+
+func (store *store) Update(ctx context.Context, team *teamtypes.Team) error {
+	_, err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewUpdate().
+		Model(team).
+		Where("org_id = ?", team.OrgID).
+		Where("id = ?", team.ID).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (store *store) Delete(ctx context.Context, orgID valuer.UUID, id valuer.UUID) error {
+	return store.sqlstore.RunInTxCtx(ctx, nil, func(ctx context.Context) error {
+		_, err := store.
+			sqlstore.
+			BunDBCtx(ctx).
+			NewDelete().
+			Model(new(teamtypes.Ownership)).
+			Where("org_id = ?", orgID).
+			Where("team_id = ?", id).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		_, err = store.
+			sqlstore.
+			BunDBCtx(ctx).
+			NewDelete().
+			Model(new(teamtypes.Member)).
+			Where("team_id = ?", id).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		_, err = store.
+			sqlstore.
+			BunDBCtx(ctx).
+			NewDelete().
+			Model(new(teamtypes.Team)).
+			Where("org_id = ?", orgID).
+			Where("id = ?", id).
+			Exec(ctx)
+		return err
+	})
+}
+
+func (store *store) CreateMember(ctx context.Context, member *teamtypes.Member) error {
+	_, err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewInsert().
+		Model(member).
+		Exec(ctx)
+	if err != nil {
+		return store.sqlstore.WrapAlreadyExistsErrf(err, teamtypes.ErrCodeTeamMemberAlreadyExists, "user: %s is already a member of team: %s", member.UserID.StringValue(), member.TeamID.StringValue())
+	}
+
+	return nil
+}
+
+func (store *store) GetMember(ctx context.Context, teamID valuer.UUID, userID valuer.UUID) (*teamtypes.Member, error) {
+	member := new(teamtypes.Member)
+	err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewSelect().
+		Model(member).
+		Where("team_id = ?", teamID).
+		Where("user_id = ?", userID).
+		Scan(ctx)
+	if err != nil {
+		return nil, store.sqlstore.WrapNotFoundErrf(err, teamtypes.ErrCodeTeamMemberNotFound, "user: %s is not a member of team: %s", userID.StringValue(), teamID.StringValue())
+	}
+
+	return member, nil
+}
+
+func (store *store) ListMembers(ctx context.Context, teamID valuer.UUID) ([]*teamtypes.Member, error) {
+	members := make([]*teamtypes.Member, 0)
+	err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewSelect().
+		Model(&members).
+		Where("team_id = ?", teamID).
+		Order("created_at ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+func (store *store) UpdateMember(ctx context.Context, member *teamtypes.Member) error {
+	_, err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewUpdate().
+		Model(member).
+		Where("team_id = ?", member.TeamID).
+		Where("user_id = ?", member.UserID).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (store *store) DeleteMember(ctx context.Context, teamID valuer.UUID, userID valuer.UUID) error {
+	result, err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewDelete().
+		Model(new(teamtypes.Member)).
+		Where("team_id = ?", teamID).
+		Where("user_id = ?", userID).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return errors.Newf(errors.TypeNotFound, teamtypes.ErrCodeTeamMemberNotFound, "user: %s is not a member of team: %s", userID.StringValue(), teamID.StringValue())
+	}
+
+	return nil
+}
+
+func (store *store) SetOwnership(ctx context.Context, ownership *teamtypes.Ownership) error {
+	_, err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewInsert().
+		Model(ownership).
+		On("CONFLICT (org_id, resource_type, resource_id) DO UPDATE").
+		Set("team_id = EXCLUDED.team_id").
+		Set("updated_at = EXCLUDED.updated_at").
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (store *store) DeleteOwnership(ctx context.Context, teamID valuer.UUID, resourceType teamtypes.ResourceType, resourceID string) error {
+	result, err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewDelete().
+		Model(new(teamtypes.Ownership)).
+		Where("team_id = ?", teamID).
+		Where("resource_type = ?", resourceType).
+		Where("resource_id = ?", resourceID).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return errors.Newf(errors.TypeNotFound, teamtypes.ErrCodeTeamOwnershipNotFound, "%s: %s is not owned by team: %s", resourceType.StringValue(), resourceID, teamID.StringValue())
+	}
+
+	return nil
+}
+
+func (store *store) ListOwnedResourceIDs(ctx context.Context, orgID valuer.UUID, teamID valuer.UUID, resourceType teamtypes.ResourceType) ([]string, error) {
+	resourceIDs := make([]string, 0)
+	err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewSelect().
+		Model(new(teamtypes.Ownership)).
+		Column("resource_id").
+		Where("org_id = ?", orgID).
+		Where("team_id = ?", teamID).
+		Where("resource_type = ?", resourceType).
+		Scan(ctx, &resourceIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return resourceIDs, nil
+}
+
+func (store *store) RunInTx(ctx context.Context, cb func(ctx context.Context) error) error {
+	return store.sqlstore.RunInTxCtx(ctx, nil, cb)
+}
@@ -0,0 +1,80 @@
+package team
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/SigNoz/signoz/pkg/types/authtypes"
+	"github.com/SigNoz/signoz/pkg/types/teamtypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+)
+
+type Module interface {
+	// Create creates a team in the org and routes the alerts of its rules to its channels
+	Create(ctx context.Context, orgID valuer.UUID, team *teamtypes.PostableTeam) (*teamtypes.GettableTeam, error)
+
+	// Get gets the team along with its channels
+	Get(ctx context.Context, orgID valuer.UUID, id valuer.UUID) (*teamtypes.GettableTeam, error)
+
+	// List lists the teams of the org
+	List(ctx context.Context, orgID valuer.UUID) ([]*teamtypes.GettableTeam, error)
+
+	// Update updates the display name of the team and replaces its channels
+	Update(ctx context.Context, orgID valuer.UUID, id valuer.UUID, team *teamtypes.UpdatableTeam) (*teamtypes.GettableTeam, error)
+
+	// Delete deletes the team, its members and ownerships, and stops routing alerts to its channels
+	Delete(ctx context.Context, orgID valuer.UUID, id valuer.UUID) error
+
+	// ListMembers lists the members of the team
+	ListMembers(ctx context.Context, orgID valuer.UUID, id valuer.UUID) ([]*teamtypes.GettableMember, error)
+
+	// AddMember adds a user of the org to the team
+	AddMember(ctx context.Context, orgID valuer.UUID, id valuer.UUID, member *teamtypes.PostableMember) error
+
+	// UpdateMember changes the role of a member of the team
+	UpdateMember(ctx context.Context, orgID valuer.UUID, id valuer.UUID, userID valuer.UUID, member *teamtypes.UpdatableMember) error
+
+	// RemoveMember removes a member from the team
+	RemoveMember(ctx context.Context, orgID valuer.UUID, id valuer.UUID, userID valuer.UUID) error
+
+	// SetOwnership makes the team the owner of a resource of the org
+	SetOwnership(ctx context.Context, orgID valuer.UUID, id valuer.UUID, ownership *teamtypes.PostableOwnership) error
+
+	// DeleteOwnership takes a resource away from the team owning it
+	DeleteOwnership(ctx context.Context, orgID valuer.UUID, id valuer.UUID, resourceType teamtypes.ResourceType, resourceID string) error
+
+	// ListOwnedResourceIDs lists the ids of the resources of a type owned by the team
+	ListOwnedResourceIDs(ctx context.Context, orgID valuer.UUID, id valuer.UUID, resourceType teamtypes.ResourceType) ([]string, error)
+
+	// CanMaintain checks that the claims are allowed to manage the team, with the teams:manage permission or as a
+	// maintainer of the team
+	CanMaintain(ctx context.Context, claims authtypes.Claims, id valuer.UUID) error
+
+	// HasPermission checks that the claims hold the permission, through their role or the roles assigned to them
+	HasPermission(ctx context.Context, claims authtypes.Claims, permission authtypes.Permission) error
+}
+
+type Handler interface {
+	Create(http.ResponseWriter, *http.Request)
+
+	Get(http.ResponseWriter, *http.Request)
+
+	List(http.ResponseWriter, *http.Request)
+
+	Update(http.ResponseWriter, *http.Request)
+
+	Delete(http.ResponseWriter, *http.Request)
+
+	ListMembers(http.ResponseWriter, *http.Request)
+
+	AddMember(http.ResponseWriter, *http.Request)
+
+	UpdateMember(http.ResponseWriter, *http.Request)
+
+	RemoveMember(http.ResponseWriter, *http.Request)
+
+	// SetOwnership assigns a dashboard or a saved view to the team
+	SetOwnership(http.ResponseWriter, *http.Request)
+
+	DeleteOwnership(http.ResponseWriter, *http.Request)
+}
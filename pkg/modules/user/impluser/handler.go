@@ -53,6 +53,43 @@ func (h *handler) AcceptInvite(w http.ResponseWriter, r *http.Request) {
 		IsUser: false,
 	}
 
+	// people who already have an account join the org with it, they keep signing in with their current credentials
+	existingUsers, err := h.module.GetUsersByEmail(ctx, invite.Email)
+	if err != nil {
+		render.Error(w, err)
+		return
+	}
+
+	if len(existingUsers) > 0 {
+		role, err := types.NewRole(invite.Role)
+		if err != nil {
+			render.Error(w, err)
+			return
+		}
+
+		// emails are unique across orgs, the invite is accepted as is when the user already is a member of the org
+		_, err = h.module.GetUserByID(ctx, invite.OrgID, existingUsers[0].ID.StringValue())
+		if err != nil && !errors.Ast(err, errors.TypeNotFound) {
+			render.Error(w, err)
+			return
+		}
+
+		if err != nil {
+			if err := h.module.AddToOrg(ctx, invite.OrgID, existingUsers[0].ID.StringValue(), role); err != nil {
+				render.Error(w, err)
+				return
+			}
+		}
+
+		if err := h.module.DeleteInvite(ctx, invite.OrgID, invite.ID); err != nil {
+			render.Error(w, err)
+			return
+		}
+
+		render.Success(w, http.StatusOK, &types.GettableLoginPrecheck{IsUser: true})
+		return
+	}
+
 	if invite.Name == "" && req.DisplayName != "" {
 		invite.Name = req.DisplayName
 	}
@@ -119,6 +156,11 @@ func (h *handler) CreateInvite(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := claims.CanGrant(req.Role); err != nil {
+		render.Error(rw, err)
+		return
+	}
+
 	invites, err := h.module.CreateBulkInvite(ctx, claims.OrgID, claims.UserID, &types.PostableBulkInviteRequest{
 		Invites: []types.PostableInvite{req},
 	})
@@ -152,6 +194,13 @@ func (h *handler) CreateBulkInvite(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	for _, invite := range req.Invites {
+		if err := claims.CanGrant(invite.Role); err != nil {
+			render.Error(rw, err)
+			return
+		}
+	}
+
 	_, err = h.module.CreateBulkInvite(ctx, claims.OrgID, claims.UserID, &req)
 	if err != nil {
 		render.Error(rw, err)
@@ -466,20 +515,41 @@ func (h *handler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if req.RefreshToken == "" {
-		_, err := h.module.CanUsePassword(ctx, req.Email)
+	// refresh tokens are only issued once the second factor is verified
+	if req.RefreshToken != "" {
+		user, jwt, err := h.module.RotateSession(ctx, req.RefreshToken)
 		if err != nil {
 			render.Error(w, err)
 			return
 		}
+
+		render.Success(w, http.StatusOK, &types.GettableLoginResponse{GettableUserJwt: jwt, UserID: user.ID.String()})
+		return
+	}
+
+	_, err := h.module.CanUsePassword(ctx, req.Email)
+	if err != nil {
+		render.Error(w, err)
+		return
 	}
 
-	user, err := h.module.GetAuthenticatedUser(ctx, req.OrgID, req.Email, req.Password, req.RefreshToken)
+	user, err := h.module.GetAuthenticatedUser(ctx, req.OrgID, req.Email, req.Password)
 	if err != nil {
 		render.Error(w, err)
 		return
 	}
 
+	challenge, err := h.module.PrepareMFAChallenge(ctx, user)
+	if err != nil {
+		render.Error(w, err)
+		return
+	}
+
+	if challenge != nil {
+		render.Success(w, http.StatusOK, &types.GettableLoginResponse{UserID: user.ID.String(), MFA: challenge})
+		return
+	}
+
 	jwt, err := h.module.GetJWTForUser(ctx, user)
 	if err != nil {
 		render.Error(w, err)
@@ -494,6 +564,315 @@ func (h *handler) Login(w http.ResponseWriter, r *http.Request) {
 	render.Success(w, http.StatusOK, gettableLoginResponse)
 }
 
+func (h *handler) VerifyMFAChallenge(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	req := new(types.PostableMFAChallenge)
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		render.Error(w, errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "failed to decode mfa challenge"))
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		render.Error(w, err)
+		return
+	}
+
+	user, err := h.module.VerifyMFAChallenge(ctx, req)
+	if err != nil {
+		render.Error(w, err)
+		return
+	}
+
+	jwt, err := h.module.GetJWTForUser(ctx, user)
+	if err != nil {
+		render.Error(w, err)
+		return
+	}
+
+	render.Success(w, http.StatusOK, &types.GettableLoginResponse{GettableUserJwt: jwt, UserID: user.ID.String()})
+}
+
+func (h *handler) EnrollTOTPWithChallenge(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	req := new(types.PostableMFAToken)
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		render.Error(w, errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "failed to decode mfa token"))
+		return
+	}
+
+	enrollment, err := h.module.EnrollTOTPWithChallenge(ctx, req.Token)
+	if err != nil {
+		render.Error(w, err)
+		return
+	}
+
+	render.Success(w, http.StatusCreated, enrollment)
+}
+
+func (h *handler) GetMFAStatus(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	claims, err := authtypes.ClaimsFromContext(ctx)
+	if err != nil {
+		render.Error(w, err)
+		return
+	}
+
+	status, err := h.module.GetMFAStatus(ctx, claims.OrgID, claims.UserID)
+	if err != nil {
+		render.Error(w, err)
+		return
+	}
+
+	render.Success(w, http.StatusOK, status)
+}
+
+func (h *handler) EnrollTOTP(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	claims, err := authtypes.ClaimsFromContext(ctx)
+	if err != nil {
+		render.Error(w, err)
+		return
+	}
+
+	enrollment, err := h.module.EnrollTOTP(ctx, claims.OrgID, claims.UserID)
+	if err != nil {
+		render.Error(w, err)
+		return
+	}
+
+	render.Success(w, http.StatusCreated, enrollment)
+}
+
+func (h *handler) ConfirmTOTP(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	claims, err := authtypes.ClaimsFromContext(ctx)
+	if err != nil {
+		render.Error(w, err)
+		return
+	}
+
+	req := new(types.PostableTOTPCode)
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		render.Error(w, errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "failed to decode code"))
+		return
+	}
+
+	if err := h.module.ConfirmTOTP(ctx, claims.OrgID, claims.UserID, req.Code); err != nil {
+		render.Error(w, err)
+		return
+	}
+
+	render.Success(w, http.StatusNoContent, nil)
+}
+
+func (h *handler) DisableTOTP(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	claims, err := authtypes.ClaimsFromContext(ctx)
+	if err != nil {
+		render.Error(w, err)
+		return
+	}
+
+	req := new(types.PostableTOTPCode)
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		render.Error(w, errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "failed to decode code"))
+		return
+	}
+
+	if err := h.module.DisableTOTP(ctx, claims.OrgID, claims.UserID, req.Code); err != nil {
+		render.Error(w, err)
+		return
+	}
+
+	render.Success(w, http.StatusNoContent, nil)
+}
+
+func (h *handler) RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	claims, err := authtypes.ClaimsFromContext(ctx)
+	if err != nil {
+		render.Error(w, err)
+		return
+	}
+
+	req := new(types.PostableTOTPCode)
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		render.Error(w, errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "failed to decode code"))
+		return
+	}
+
+	recoveryCodes, err := h.module.RegenerateRecoveryCodes(ctx, claims.OrgID, claims.UserID, req.Code)
+	if err != nil {
+		render.Error(w, err)
+		return
+	}
+
+	render.Success(w, http.StatusOK, &types.GettableRecoveryCodes{RecoveryCodes: recoveryCodes})
+}
+
+func (h *handler) ResetMFA(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	claims, err := authtypes.ClaimsFromContext(ctx)
+	if err != nil {
+		render.Error(w, err)
+		return
+	}
+
+	user, err := h.module.GetUserByID(ctx, claims.OrgID, mux.Vars(r)["id"])
+	if err != nil {
+		render.Error(w, err)
+		return
+	}
+
+	// resetting the factor of a user gives a way into their account, it needs at least their role
+	if err := claims.CanGrant(types.Role(user.Role)); err != nil {
+		render.Error(w, err)
+		return
+	}
+
+	if err := h.module.ResetMFA(ctx, claims.OrgID, user.ID.StringValue()); err != nil {
+		render.Error(w, err)
+		return
+	}
+
+	render.Success(w, http.StatusNoContent, nil)
+}
+
+func (h *handler) ListMyOrgs(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	claims, err := authtypes.ClaimsFromContext(ctx)
+	if err != nil {
+		render.Error(w, err)
+		return
+	}
+
+	orgs, err := h.module.ListOrgs(ctx, claims.UserID)
+	if err != nil {
+		render.Error(w, err)
+		return
+	}
+
+	for _, org := range orgs {
+		org.Current = org.OrgID == claims.OrgID
+	}
+
+	render.Success(w, http.StatusOK, orgs)
+}
+
+func (h *handler) SwitchOrg(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	claims, err := authtypes.ClaimsFromContext(ctx)
+	if err != nil {
+		render.Error(w, err)
+		return
+	}
+
+	req := new(types.PostableSwitchOrg)
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		render.Error(w, errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "failed to decode org"))
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		render.Error(w, err)
+		return
+	}
+
+	user, jwt, err := h.module.SwitchOrg(ctx, claims.UserID, req.OrgID)
+	if err != nil {
+		render.Error(w, err)
+		return
+	}
+
+	render.Success(w, http.StatusOK, &types.GettableLoginResponse{GettableUserJwt: jwt, UserID: user.ID.String()})
+}
+
+func (h *handler) ListMySessions(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	claims, err := authtypes.ClaimsFromContext(ctx)
+	if err != nil {
+		render.Error(w, err)
+		return
+	}
+
+	sessions, err := h.module.ListSessions(ctx, claims.OrgID, claims.UserID)
+	if err != nil {
+		render.Error(w, err)
+		return
+	}
+
+	gettableSessions := make([]*types.GettableSession, len(sessions))
+	for i, session := range sessions {
+		gettableSessions[i] = types.NewGettableSession(session, claims.SessionID)
+	}
+
+	render.Success(w, http.StatusOK, gettableSessions)
+}
+
+func (h *handler) RevokeMySession(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	claims, err := authtypes.ClaimsFromContext(ctx)
+	if err != nil {
+		render.Error(w, err)
+		return
+	}
+
+	id, err := valuer.NewUUID(mux.Vars(r)["id"])
+	if err != nil {
+		render.Error(w, errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "id is not a valid uuid"))
+		return
+	}
+
+	if err := h.module.RevokeSession(ctx, claims.OrgID, claims.UserID, id.StringValue()); err != nil {
+		render.Error(w, err)
+		return
+	}
+
+	render.Success(w, http.StatusNoContent, nil)
+}
+
+func (h *handler) RevokeSessions(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	claims, err := authtypes.ClaimsFromContext(ctx)
+	if err != nil {
+		render.Error(w, err)
+		return
+	}
+
+	if err := h.module.RevokeSessions(ctx, claims.OrgID, mux.Vars(r)["id"]); err != nil {
+		render.Error(w, err)
+		return
+	}
+
+	render.Success(w, http.StatusNoContent, nil)
+}
+
 func (h *handler) GetCurrentUserFromJWT(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
 	defer cancel()
@@ -542,8 +921,14 @@ func (h *handler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := claims.CanGrant(req.Role); err != nil {
+		render.Error(w, err)
+		return
+	}
+
 	apiKey, err := types.NewStorableAPIKey(
 		req.Name,
+		orgID,
 		userID,
 		req.Role,
 		req.ExpiresInDays,
@@ -553,6 +938,14 @@ func (h *handler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	apiKey.Scopes = req.Scopes
+	apiKey.RuleLabels = req.RuleLabels
+	apiKey.AllowedIPs = req.AllowedIPs
+	if err := apiKey.ValidateRestrictions(); err != nil {
+		render.Error(w, err)
+		return
+	}
+
 	err = h.module.CreateAPIKey(ctx, apiKey)
 	if err != nil {
 		render.Error(w, err)
@@ -634,6 +1027,11 @@ func (h *handler) UpdateAPIKey(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := req.ValidateRestrictions(); err != nil {
+		render.Error(w, err)
+		return
+	}
+
 	idStr := mux.Vars(r)["id"]
 	id, err := valuer.NewUUID(idStr)
 	if err != nil {
@@ -660,6 +1058,14 @@ func (h *handler) UpdateAPIKey(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// neither the key being updated nor its new role may grant more than the caller holds
+	for _, role := range []types.Role{existingAPIKey.Role, req.Role} {
+		if err := claims.CanGrant(role); err != nil {
+			render.Error(w, err)
+			return
+		}
+	}
+
 	err = h.module.UpdateAPIKey(ctx, id, &req, userID)
 	if err != nil {
 		render.Error(w, err)
@@ -736,7 +1142,7 @@ func (h *handler) CreateDomain(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := req.ValidNew(); err != nil {
-		render.Error(rw, err)
+		render.Error(rw, errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "invalid request"))
 		return
 	}
 
@@ -814,6 +1220,12 @@ func (h *handler) UpdateDomain(rw http.ResponseWriter, r *http.Request) {
 	req.ID = domainId
 	if err := req.Valid(nil); err != nil {
 		render.Error(rw, errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "invalid request"))
+		return
+	}
+
+	if err := req.ValidSsoConfig(); err != nil {
+		render.Error(rw, errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "invalid sso config"))
+		return
 	}
 
 	err = h.module.UpdateDomain(ctx, &req)
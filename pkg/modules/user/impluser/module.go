@@ -12,38 +12,50 @@ import (
 	"github.com/SigNoz/signoz/pkg/emailing"
 	"github.com/SigNoz/signoz/pkg/errors"
 	"github.com/SigNoz/signoz/pkg/factory"
+	"github.com/SigNoz/signoz/pkg/modules/audit"
 	"github.com/SigNoz/signoz/pkg/modules/organization"
+	"github.com/SigNoz/signoz/pkg/modules/preference"
 	"github.com/SigNoz/signoz/pkg/modules/user"
 	"github.com/SigNoz/signoz/pkg/query-service/constants"
 	"github.com/SigNoz/signoz/pkg/query-service/model"
 	"github.com/SigNoz/signoz/pkg/query-service/telemetry"
 	"github.com/SigNoz/signoz/pkg/types"
 	"github.com/SigNoz/signoz/pkg/types/analyticstypes"
+	"github.com/SigNoz/signoz/pkg/types/audittypes"
 	"github.com/SigNoz/signoz/pkg/types/authtypes"
 	"github.com/SigNoz/signoz/pkg/types/emailtypes"
+	"github.com/SigNoz/signoz/pkg/types/preferencetypes"
+	"github.com/SigNoz/signoz/pkg/types/ssotypes"
 	"github.com/SigNoz/signoz/pkg/valuer"
 	"github.com/google/uuid"
 )
 
+// time given to the user to enter their second factor after their password
+const mfaTokenExpiry = 5 * time.Minute
+
 type Module struct {
-	store     types.UserStore
-	jwt       *authtypes.JWT
-	emailing  emailing.Emailing
-	settings  factory.ScopedProviderSettings
-	orgSetter organization.Setter
-	analytics analytics.Analytics
+	store      types.UserStore
+	jwt        *authtypes.JWT
+	emailing   emailing.Emailing
+	settings   factory.ScopedProviderSettings
+	orgSetter  organization.Setter
+	preference preference.Module
+	analytics  analytics.Analytics
+	audit      audit.Module
 }
 
 // This module is a WIP, don't take inspiration from this.
-func NewModule(store types.UserStore, jwt *authtypes.JWT, emailing emailing.Emailing, providerSettings factory.ProviderSettings, orgSetter organization.Setter, analytics analytics.Analytics) user.Module {
+func NewModule(store types.UserStore, jwt *authtypes.JWT, emailing emailing.Emailing, providerSettings factory.ProviderSettings, orgSetter organization.Setter, preference preference.Module, analytics analytics.Analytics, audit audit.Module) user.Module {
 	settings := factory.NewScopedProviderSettings(providerSettings, "github.com/SigNoz/signoz/pkg/modules/user/impluser")
 	return &Module{
-		store:     store,
-		jwt:       jwt,
-		emailing:  emailing,
-		settings:  settings,
-		orgSetter: orgSetter,
-		analytics: analytics,
+		store:      store,
+		jwt:        jwt,
+		emailing:   emailing,
+		settings:   settings,
+		orgSetter:  orgSetter,
+		preference: preference,
+		analytics:  analytics,
+		audit:      audit,
 	}
 }
 
@@ -135,6 +147,8 @@ func (m *Module) CreateUserWithPassword(ctx context.Context, user *types.User, p
 		return nil, err
 	}
 
+	m.recordUser(ctx, user.OrgID, audittypes.ActionCreate, user.ID.StringValue(), nil, user)
+
 	m.analytics.Send(ctx,
 		analyticstypes.Identify{
 			UserId: user.ID.String(),
@@ -173,6 +187,8 @@ func (m *Module) CreateUser(ctx context.Context, user *types.User) error {
 		return err
 	}
 
+	m.recordUser(ctx, user.OrgID, audittypes.ActionCreate, user.ID.StringValue(), nil, user)
+
 	m.analytics.Send(ctx,
 		analyticstypes.Identify{
 			UserId: user.ID.String(),
@@ -214,6 +230,20 @@ func (m *Module) GetUserByEmailInOrg(ctx context.Context, orgID string, email st
 	return m.store.GetUserByEmailInOrg(ctx, orgID, email)
 }
 
+func (m *Module) NewContextWithClaimsOf(ctx context.Context, orgID string, email string) (context.Context, error) {
+	user, err := m.store.GetUserByEmailInOrg(ctx, orgID, email)
+	if err != nil {
+		return nil, err
+	}
+
+	return authtypes.NewContextWithClaims(ctx, authtypes.Claims{
+		UserID: user.ID.StringValue(),
+		Email:  user.Email,
+		Role:   types.Role(user.Role),
+		OrgID:  orgID,
+	}), nil
+}
+
 func (m *Module) GetUsersByEmail(ctx context.Context, email string) ([]*types.GettableUser, error) {
 	return m.store.GetUsersByEmail(ctx, email)
 }
@@ -227,7 +257,31 @@ func (m *Module) ListUsers(ctx context.Context, orgID string) ([]*types.Gettable
 }
 
 func (m *Module) UpdateUser(ctx context.Context, orgID string, id string, user *types.User) (*types.User, error) {
-	return m.store.UpdateUser(ctx, orgID, id, user)
+	existingUser, err := m.store.GetUserByID(ctx, orgID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	updatedUser, err := m.store.UpdateUser(ctx, orgID, id, user)
+	if err != nil {
+		return nil, err
+	}
+
+	// only the updatable columns are written, the rest of the user is left as is
+	after := existingUser.User
+	after.DisplayName = updatedUser.DisplayName
+	after.Role = updatedUser.Role
+	after.UpdatedAt = updatedUser.UpdatedAt
+	m.recordUser(ctx, orgID, audittypes.ActionUpdate, id, existingUser.User, after)
+
+	// the tokens of the user carry the previous role until they are refreshed
+	if updatedUser.Role != "" && types.Role(updatedUser.Role).IsLowerThan(types.Role(existingUser.Role)) {
+		if err := m.store.RevokeSessions(ctx, id, "", types.SessionRevocationReasonRoleDowngrade); err != nil {
+			return nil, err
+		}
+	}
+
+	return updatedUser, nil
 }
 
 func (m *Module) DeleteUser(ctx context.Context, orgID string, id string) error {
@@ -250,7 +304,12 @@ func (m *Module) DeleteUser(ctx context.Context, orgID string, id string) error
 		return errors.New(errors.TypeForbidden, errors.CodeForbidden, "cannot delete the last admin")
 	}
 
-	return m.store.DeleteUser(ctx, orgID, user.ID.StringValue())
+	if err := m.store.DeleteUser(ctx, orgID, user.ID.StringValue()); err != nil {
+		return err
+	}
+
+	m.recordUser(ctx, orgID, audittypes.ActionDelete, user.ID.StringValue(), user.User, nil)
+	return nil
 }
 
 func (m *Module) CreateResetPasswordToken(ctx context.Context, userID string) (*types.ResetPasswordRequest, error) {
@@ -319,7 +378,11 @@ func (m *Module) UpdatePasswordAndDeleteResetPasswordEntry(ctx context.Context,
 		return err
 	}
 
-	return m.store.UpdatePasswordAndDeleteResetPasswordEntry(ctx, existingPassword.UserID, hashedPassword)
+	if err := m.store.UpdatePasswordAndDeleteResetPasswordEntry(ctx, existingPassword.UserID, hashedPassword); err != nil {
+		return err
+	}
+
+	return m.store.RevokeSessions(ctx, existingPassword.UserID, "", types.SessionRevocationReasonPasswordChange)
 }
 
 func (m *Module) UpdatePassword(ctx context.Context, userID string, password string) error {
@@ -327,26 +390,23 @@ func (m *Module) UpdatePassword(ctx context.Context, userID string, password str
 	if err != nil {
 		return err
 	}
-	return m.store.UpdatePassword(ctx, userID, hashedPassword)
-}
 
-func (m *Module) GetAuthenticatedUser(ctx context.Context, orgID, email, password, refreshToken string) (*types.User, error) {
-	if refreshToken != "" {
-		// parse the refresh token
-		claims, err := m.jwt.Claims(refreshToken)
-		if err != nil {
-			return nil, err
-		}
+	if err := m.store.UpdatePassword(ctx, userID, hashedPassword); err != nil {
+		return err
+	}
 
-		user, err := m.store.GetUserByID(ctx, claims.OrgID, claims.UserID)
-		if err != nil {
-			return nil, err
-		}
-		return &user.User, nil
+	// users changing their own password stay signed in on the session they change it from
+	var currentSessionID string
+	if claims, err := authtypes.ClaimsFromContext(ctx); err == nil && claims.UserID == userID {
+		currentSessionID = claims.SessionID
 	}
 
-	var dbUser *types.User
-	// when the orgID is not provided we login if the user exists in just one org
+	return m.store.RevokeSessions(ctx, userID, currentSessionID, types.SessionRevocationReasonPasswordChange)
+}
+
+// GetAuthenticatedUser verifies the password of the user and returns them in the org they log in to, the org can be
+// left out for users who are a member of a single org.
+func (m *Module) GetAuthenticatedUser(ctx context.Context, orgID, email, password string) (*types.User, error) {
 	users, err := m.store.GetUsersByEmail(ctx, email)
 	if err != nil {
 		return nil, err
@@ -354,10 +414,32 @@ func (m *Module) GetAuthenticatedUser(ctx context.Context, orgID, email, passwor
 
 	if len(users) == 0 {
 		return nil, errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "user with email: %s does not exist", email)
-	} else if len(users) == 1 {
-		dbUser = &users[0].User
+	}
+
+	var dbUser *types.User
+	if orgID != "" {
+		for _, user := range users {
+			member, err := m.store.GetUserByID(ctx, orgID, user.ID.StringValue())
+			if err != nil {
+				if errors.Ast(err, errors.TypeNotFound) {
+					continue
+				}
+				return nil, err
+			}
+
+			dbUser = &member.User
+			break
+		}
+
+		if dbUser == nil {
+			return nil, errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "user with email: %s does not exist in org: %s", email, orgID)
+		}
 	} else {
-		return nil, errors.New(errors.TypeInvalidInput, errors.CodeInvalidInput, "please provide an orgID")
+		if len(users) > 1 {
+			return nil, errors.New(errors.TypeInvalidInput, errors.CodeInvalidInput, "please provide an orgID")
+		}
+
+		dbUser = &users[0].User
 	}
 
 	existingPassword, err := m.store.GetPasswordByUserID(ctx, dbUser.ID.StringValue())
@@ -386,14 +468,23 @@ func (m *Module) LoginPrecheck(ctx context.Context, orgID, email, sourceUrl stri
 		resp.IsUser = false
 	}
 
-	if len(users) > 1 {
-		resp.SelectOrg = true
-		resp.Orgs = make([]string, len(users))
-		for i, user := range users {
-			resp.Orgs[i] = user.OrgID
+	orgs := []string{}
+	for _, user := range users {
+		memberships, err := m.store.ListMemberships(ctx, user.ID.StringValue())
+		if err != nil {
+			return nil, err
+		}
+
+		for _, membership := range memberships {
+			orgs = append(orgs, membership.OrgID)
 		}
 	}
 
+	if len(orgs) > 1 {
+		resp.SelectOrg = true
+		resp.Orgs = orgs
+	}
+
 	// TODO(Nitya): in multitenancy this should use orgId as well.
 	orgDomain, err := m.GetAuthDomainByEmail(ctx, email)
 	if err != nil && !errors.Ast(err, errors.TypeNotFound) {
@@ -432,18 +523,205 @@ func (m *Module) LoginPrecheck(ctx context.Context, orgID, email, sourceUrl stri
 	return resp, nil
 }
 
+// AddToOrg makes an existing user a member of another org.
+func (m *Module) AddToOrg(ctx context.Context, orgID string, userID string, role types.Role) error {
+	membership := types.NewOrgMembership(userID, orgID, role)
+	if err := m.store.CreateMembership(ctx, membership); err != nil {
+		return err
+	}
+
+	m.recordUser(ctx, orgID, audittypes.ActionCreate, userID, nil, membership)
+	return nil
+}
+
+func (m *Module) ListOrgs(ctx context.Context, userID string) ([]*types.GettableOrgMembership, error) {
+	memberships, err := m.store.ListMemberships(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	orgs := make([]*types.GettableOrgMembership, 0, len(memberships))
+	for _, membership := range memberships {
+		user, err := m.store.GetUserByID(ctx, membership.OrgID, userID)
+		if err != nil {
+			return nil, err
+		}
+
+		orgs = append(orgs, &types.GettableOrgMembership{OrgID: membership.OrgID, Organization: user.Organization, Role: membership.Role})
+	}
+
+	return orgs, nil
+}
+
+// SwitchOrg ends the current session of the user and starts one in another of their orgs.
+func (m *Module) SwitchOrg(ctx context.Context, userID string, orgID string) (*types.User, types.GettableUserJwt, error) {
+	user, err := m.store.GetUserByID(ctx, orgID, userID)
+	if err != nil {
+		if errors.Ast(err, errors.TypeNotFound) {
+			return nil, types.GettableUserJwt{}, errors.Newf(errors.TypeForbidden, types.ErrMembershipNotFound, "user is not a member of org: %s", orgID)
+		}
+		return nil, types.GettableUserJwt{}, err
+	}
+
+	// the second factor is verified on login, users without one can't enter an org requiring it
+	status, err := m.GetMFAStatus(ctx, orgID, userID)
+	if err != nil {
+		return nil, types.GettableUserJwt{}, err
+	}
+
+	if status.Required && !status.Enabled {
+		return nil, types.GettableUserJwt{}, errors.New(errors.TypeForbidden, types.ErrMFARequired, "the org requires multi-factor authentication, enable it before switching to the org")
+	}
+
+	if claims, err := authtypes.ClaimsFromContext(ctx); err == nil && claims.SessionID != "" {
+		session, err := m.store.GetSession(ctx, claims.SessionID)
+		if err != nil {
+			return nil, types.GettableUserJwt{}, err
+		}
+
+		session.Revoke(types.SessionRevocationReasonOrgSwitch)
+		if err := m.store.RevokeSession(ctx, session); err != nil {
+			return nil, types.GettableUserJwt{}, err
+		}
+	}
+
+	jwt, err := m.GetJWTForUser(ctx, &user.User)
+	if err != nil {
+		return nil, types.GettableUserJwt{}, err
+	}
+
+	return &user.User, jwt, nil
+}
+
+// GetJWTForUser starts a new session for the user and issues its tokens.
 func (m *Module) GetJWTForUser(ctx context.Context, user *types.User) (types.GettableUserJwt, error) {
+	origin := audittypes.OriginFromContext(ctx)
+	session := types.NewStorableSession(user.OrgID, user.ID.StringValue(), origin.ClientAddress, origin.UserAgent, m.jwt.JwtRefresh)
+	if err := m.store.CreateSession(ctx, session); err != nil {
+		return types.GettableUserJwt{}, err
+	}
+
+	return m.getJWTForSession(user, session)
+}
+
+func (m *Module) RotateSession(ctx context.Context, refreshToken string) (*types.User, types.GettableUserJwt, error) {
+	claims, err := m.jwt.Claims(refreshToken)
+	if err != nil {
+		return nil, types.GettableUserJwt{}, err
+	}
+
+	session, err := m.getSession(ctx, claims)
+	if err != nil {
+		return nil, types.GettableUserJwt{}, err
+	}
+
+	// the token has already been exchanged, either the client or an attacker holds a stolen copy of it
+	if claims.ID != session.RefreshTokenID {
+		m.settings.Logger().WarnContext(ctx, "refresh token reused, revoking the session", "session_id", session.ID, "user_id", session.UserID)
+		session.Revoke(types.SessionRevocationReasonRefreshTokenReuse)
+		if err := m.store.RevokeSession(ctx, session); err != nil {
+			return nil, types.GettableUserJwt{}, err
+		}
+
+		return nil, types.GettableUserJwt{}, errors.New(errors.TypeUnauthenticated, types.ErrSessionRevoked, "refresh token has already been used")
+	}
+
+	// the role of the user is read again so that the new tokens carry its changes
+	user, err := m.store.GetUserByID(ctx, session.OrgID, session.UserID)
+	if err != nil {
+		return nil, types.GettableUserJwt{}, err
+	}
+
+	origin := audittypes.OriginFromContext(ctx)
+	session.Rotate(origin.ClientAddress, origin.UserAgent, m.jwt.JwtRefresh)
+	if err := m.store.RotateSession(ctx, session, claims.ID); err != nil {
+		return nil, types.GettableUserJwt{}, err
+	}
+
+	jwt, err := m.getJWTForSession(&user.User, session)
+	if err != nil {
+		return nil, types.GettableUserJwt{}, err
+	}
+
+	return &user.User, jwt, nil
+}
+
+// ValidateSession rejects the tokens of revoked or expired sessions along with the ones issued without a session.
+func (m *Module) ValidateSession(ctx context.Context, claims authtypes.Claims) error {
+	// api keys are checked on their own
+	if claims.APIKeyID != "" {
+		return nil
+	}
+
+	_, err := m.getSession(ctx, claims)
+	return err
+}
+
+func (m *Module) ListSessions(ctx context.Context, orgID string, userID string) ([]*types.StorableSession, error) {
+	return m.store.ListSessions(ctx, orgID, userID)
+}
+
+func (m *Module) RevokeSession(ctx context.Context, orgID string, userID string, id string) error {
+	session, err := m.store.GetSession(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// sessions of other users are reported as missing
+	if session.OrgID != orgID || session.UserID != userID {
+		return errors.Newf(errors.TypeNotFound, types.ErrSessionNotFound, "session with id: %s does not exist", id)
+	}
+
+	session.Revoke(types.SessionRevocationReasonLogout)
+	return m.store.RevokeSession(ctx, session)
+}
+
+func (m *Module) RevokeSessions(ctx context.Context, orgID string, userID string) error {
+	user, err := m.store.GetUserByID(ctx, orgID, userID)
+	if err != nil {
+		return err
+	}
+
+	return m.store.RevokeSessions(ctx, user.ID.StringValue(), "", types.SessionRevocationReasonForced)
+}
+
+func (m *Module) getSession(ctx context.Context, claims authtypes.Claims) (*types.StorableSession, error) {
+	if claims.SessionID == "" {
+		return nil, errors.New(errors.TypeUnauthenticated, errors.CodeUnauthenticated, "token was not issued for a session, please login again")
+	}
+
+	session, err := m.store.GetSession(ctx, claims.SessionID)
+	if err != nil {
+		if errors.Ast(err, errors.TypeNotFound) {
+			return nil, errors.New(errors.TypeUnauthenticated, types.ErrSessionNotFound, "session does not exist, please login again")
+		}
+
+		return nil, err
+	}
+
+	if session.UserID != claims.UserID || session.OrgID != claims.OrgID {
+		return nil, errors.New(errors.TypeUnauthenticated, errors.CodeUnauthenticated, "token was not issued for this session")
+	}
+
+	if err := session.Validate(); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+func (m *Module) getJWTForSession(user *types.User, session *types.StorableSession) (types.GettableUserJwt, error) {
 	role, err := types.NewRole(user.Role)
 	if err != nil {
 		return types.GettableUserJwt{}, err
 	}
 
-	accessJwt, accessClaims, err := m.jwt.AccessToken(user.OrgID, user.ID.String(), user.Email, role)
+	accessJwt, accessClaims, err := m.jwt.AccessToken(user.OrgID, user.ID.String(), user.Email, role, session.ID.StringValue())
 	if err != nil {
 		return types.GettableUserJwt{}, err
 	}
 
-	refreshJwt, refreshClaims, err := m.jwt.RefreshToken(user.OrgID, user.ID.String(), user.Email, role)
+	refreshJwt, refreshClaims, err := m.jwt.RefreshToken(user.OrgID, user.ID.String(), user.Email, role, session.ID.StringValue(), session.RefreshTokenID)
 	if err != nil {
 		return types.GettableUserJwt{}, err
 	}
@@ -456,26 +734,19 @@ func (m *Module) GetJWTForUser(ctx context.Context, user *types.User) (types.Get
 	}, nil
 }
 
-func (m *Module) CreateUserForSAMLRequest(ctx context.Context, email string) (*types.User, error) {
-	// get auth domain from email domain
-	_, err := m.GetAuthDomainByEmail(ctx, email)
-	if err != nil && !errors.Ast(err, errors.TypeNotFound) {
-		return nil, err
-	}
-
+// CreateUserForSSORequest provisions the user signing in with the sso of the domain for the first time.
+func (m *Module) CreateUserForSSORequest(ctx context.Context, domain *types.GettableOrgDomain, identity *ssotypes.SSOIdentity, role types.Role) (*types.User, error) {
 	// get name from email
-	parts := strings.Split(email, "@")
+	parts := strings.Split(identity.Email, "@")
 	if len(parts) < 2 {
 		return nil, errors.New(errors.TypeInvalidInput, errors.CodeInvalidInput, "invalid email format")
 	}
 	name := parts[0]
-
-	defaultOrgID, err := m.store.GetDefaultOrgID(ctx)
-	if err != nil {
-		return nil, err
+	if identity.Username != "" {
+		name = identity.Username
 	}
 
-	user, err := types.NewUser(name, email, types.RoleViewer.String(), defaultOrgID)
+	user, err := types.NewUser(name, identity.Email, role.String(), domain.OrgID)
 	if err != nil {
 		return nil, err
 	}
@@ -486,11 +757,23 @@ func (m *Module) CreateUserForSAMLRequest(ctx context.Context, email string) (*t
 	}
 
 	return user, nil
-
 }
 
-func (m *Module) PrepareSsoRedirect(ctx context.Context, redirectUri, email string, jwt *authtypes.JWT) (string, error) {
-	users, err := m.GetUsersByEmail(ctx, email)
+func (m *Module) PrepareSsoRedirect(ctx context.Context, redirectUri string, domain *types.GettableOrgDomain, identity *ssotypes.SSOIdentity, jwt *authtypes.JWT) (string, error) {
+	// the role is resolved before the user is looked up so that denied users are not provisioned
+	var role types.Role
+	var groups []string
+	if domain.RoleMapping != nil {
+		var matched bool
+		groups = identity.Attributes[domain.RoleMapping.GetGroupsAttribute()]
+		role, matched = domain.RoleMapping.Resolve(groups)
+		if !matched && domain.RoleMapping.DenyUnmatched {
+			m.settings.Logger().WarnContext(ctx, "denied sso login, none of the groups of the user are mapped to a role", "email", identity.Email, "domain", domain.Name, "groups", groups)
+			return "", errors.Newf(errors.TypeForbidden, errors.CodeForbidden, "none of the groups of %s are mapped to a role", identity.Email)
+		}
+	}
+
+	users, err := m.GetUsersByEmail(ctx, identity.Email)
 	if err != nil {
 		m.settings.Logger().ErrorContext(ctx, "failed to get user with email received from auth provider", "error", err)
 		return "", err
@@ -498,14 +781,32 @@ func (m *Module) PrepareSsoRedirect(ctx context.Context, redirectUri, email stri
 	user := &types.User{}
 
 	if len(users) == 0 {
-		newUser, err := m.CreateUserForSAMLRequest(ctx, email)
+		if role == "" {
+			role = types.RoleViewer
+		}
+
+		newUser, err := m.CreateUserForSSORequest(ctx, domain, identity, role)
 		user = newUser
 		if err != nil {
 			m.settings.Logger().ErrorContext(ctx, "failed to create user with email received from auth provider", "error", err)
 			return "", err
 		}
+		m.settings.Logger().InfoContext(ctx, "provisioned sso user", "email", user.Email, "role", user.Role, "domain", domain.Name, "groups", groups)
 	} else {
-		user = &users[0].User
+		// the user may be at home in another org, the token is issued for their membership in the org of the domain
+		member, err := m.ssoMembership(ctx, domain, &users[0].User, role)
+		if err != nil {
+			m.settings.Logger().ErrorContext(ctx, "failed to get membership of sso user in the org of the domain", "error", err)
+			return "", err
+		}
+
+		user = member
+		if role != "" && user.Role != role.String() {
+			if err := m.syncSsoUserRole(ctx, domain, user, role, groups); err != nil {
+				m.settings.Logger().ErrorContext(ctx, "failed to update role of sso user", "error", err)
+				return "", err
+			}
+		}
 	}
 
 	tokenStore, err := m.GetJWTForUser(ctx, user)
@@ -521,6 +822,60 @@ func (m *Module) PrepareSsoRedirect(ctx context.Context, redirectUri, email stri
 		tokenStore.RefreshJwt), nil
 }
 
+// ssoMembership returns the user as a member of the org of the domain, users signing in for the first time to the org
+// are made members of it with the mapped role.
+func (m *Module) ssoMembership(ctx context.Context, domain *types.GettableOrgDomain, user *types.User, role types.Role) (*types.User, error) {
+	member, err := m.store.GetUserByID(ctx, domain.OrgID, user.ID.StringValue())
+	if err == nil {
+		return &member.User, nil
+	}
+
+	if !errors.Ast(err, errors.TypeNotFound) {
+		return nil, err
+	}
+
+	if role == "" {
+		role = types.RoleViewer
+	}
+
+	if err := m.AddToOrg(ctx, domain.OrgID, user.ID.StringValue(), role); err != nil {
+		return nil, err
+	}
+	m.settings.Logger().InfoContext(ctx, "added sso user to the org of the domain", "email", user.Email, "role", role, "domain", domain.Name)
+
+	member, err = m.store.GetUserByID(ctx, domain.OrgID, user.ID.StringValue())
+	if err != nil {
+		return nil, err
+	}
+
+	return &member.User, nil
+}
+
+// syncSsoUserRole updates the role of the user to the one mapped from the groups of the identity provider.
+func (m *Module) syncSsoUserRole(ctx context.Context, domain *types.GettableOrgDomain, user *types.User, role types.Role, groups []string) error {
+	// demoting the last admin would leave nobody able to fix the role mapping of the domain
+	if user.Role == types.RoleAdmin.String() {
+		admins, err := m.GetUsersByRoleInOrg(ctx, user.OrgID, types.RoleAdmin)
+		if err != nil {
+			return err
+		}
+
+		if len(admins) == 1 {
+			m.settings.Logger().WarnContext(ctx, "kept the role of the last admin of the org instead of the one mapped from the groups of the user", "email", user.Email, "domain", domain.Name, "role", role, "groups", groups)
+			return nil
+		}
+	}
+
+	previous := user.Role
+	user.Role = role.String()
+	if _, err := m.UpdateUser(ctx, user.OrgID, user.ID.StringValue(), user); err != nil {
+		return err
+	}
+
+	m.settings.Logger().InfoContext(ctx, "updated role of sso user from the groups of the identity provider", "email", user.Email, "domain", domain.Name, "from", previous, "to", user.Role, "groups", groups)
+	return nil
+}
+
 func (m *Module) CanUsePassword(ctx context.Context, email string) (bool, error) {
 	domain, err := m.GetAuthDomainByEmail(ctx, email)
 	if err != nil && !errors.Ast(err, errors.TypeNotFound) {
@@ -570,12 +925,346 @@ func (m *Module) GetAuthDomainByEmail(ctx context.Context, email string) (*types
 	return gettableDomain, nil
 }
 
+func (m *Module) PrepareMFAChallenge(ctx context.Context, user *types.User) (*types.GettableMFAChallenge, error) {
+	factor, err := m.getFactorTOTP(ctx, user.ID.StringValue())
+	if err != nil && !errors.Ast(err, errors.TypeNotFound) {
+		return nil, err
+	}
+
+	enabled := factor != nil && factor.Enabled
+	required, err := m.isMFARequired(ctx, user.OrgID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !enabled && !required {
+		return nil, nil
+	}
+
+	token, claims, err := m.jwt.MFAToken(user.OrgID, user.ID.StringValue(), mfaTokenExpiry)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.GettableMFAChallenge{
+		Token:              token,
+		TokenExpiry:        claims.ExpiresAt.Unix(),
+		EnrollmentRequired: !enabled,
+	}, nil
+}
+
+func (m *Module) VerifyMFAChallenge(ctx context.Context, challenge *types.PostableMFAChallenge) (*types.User, error) {
+	claims, err := m.jwt.MFAClaims(challenge.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := m.store.GetUserByID(ctx, claims.OrgID, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	factor, err := m.getFactorTOTP(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if challenge.RecoveryCode != "" {
+		if !factor.Enabled || !factor.UseRecoveryCode(challenge.RecoveryCode) {
+			return nil, errors.New(errors.TypeInvalidInput, types.ErrInvalidTOTPCode, "invalid recovery code")
+		}
+
+		// a recovery code proves the user holds the factor, unlock it
+		factor.FailedAttempts = 0
+		if err := m.updateFactorTOTP(ctx, factor); err != nil {
+			return nil, err
+		}
+
+		m.settings.Logger().InfoContext(ctx, "user logged in with a recovery code", "email", user.Email, "remaining", factor.CountRecoveryCodes())
+		return &user.User, nil
+	}
+
+	if err := m.verifyTOTP(ctx, factor, challenge.Code); err != nil {
+		return nil, err
+	}
+
+	// the first code of an enrollment started at login enables the factor
+	factor.Enabled = true
+	if err := m.updateFactorTOTP(ctx, factor); err != nil {
+		return nil, err
+	}
+
+	return &user.User, nil
+}
+
+func (m *Module) EnrollTOTPWithChallenge(ctx context.Context, token string) (*types.GettableTOTPEnrollment, error) {
+	claims, err := m.jwt.MFAClaims(token)
+	if err != nil {
+		return nil, err
+	}
+
+	required, err := m.isMFARequired(ctx, claims.OrgID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !required {
+		return nil, errors.New(errors.TypeForbidden, errors.CodeForbidden, "mfa is not required by the organization, enroll after logging in")
+	}
+
+	return m.EnrollTOTP(ctx, claims.OrgID, claims.UserID)
+}
+
+func (m *Module) GetMFAStatus(ctx context.Context, orgID string, userID string) (*types.GettableMFAStatus, error) {
+	required, err := m.isMFARequired(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	factor, err := m.getFactorTOTP(ctx, userID)
+	if err != nil {
+		if errors.Ast(err, errors.TypeNotFound) {
+			return &types.GettableMFAStatus{Required: required}, nil
+		}
+		return nil, err
+	}
+
+	if !factor.Enabled {
+		return &types.GettableMFAStatus{Required: required}, nil
+	}
+
+	return &types.GettableMFAStatus{
+		Enabled:           true,
+		Required:          required,
+		RecoveryCodesLeft: factor.CountRecoveryCodes(),
+	}, nil
+}
+
+func (m *Module) EnrollTOTP(ctx context.Context, orgID string, userID string) (*types.GettableTOTPEnrollment, error) {
+	user, err := m.store.GetUserByID(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := m.getFactorTOTP(ctx, userID)
+	if err != nil && !errors.Ast(err, errors.TypeNotFound) {
+		return nil, err
+	}
+
+	if existing != nil && existing.Enabled {
+		return nil, errors.New(errors.TypeAlreadyExists, types.ErrTOTPAlreadyExists, "totp is already enabled, disable it before enrolling again")
+	}
+
+	factor, recoveryCodes, err := types.NewFactorTOTP(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.createFactorTOTP(ctx, factor); err != nil {
+		return nil, err
+	}
+
+	return &types.GettableTOTPEnrollment{
+		Secret:        factor.Secret,
+		URI:           factor.URI(user.Email),
+		RecoveryCodes: recoveryCodes,
+	}, nil
+}
+
+func (m *Module) ConfirmTOTP(ctx context.Context, orgID string, userID string, code string) error {
+	factor, err := m.getFactorTOTP(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if factor.Enabled {
+		return errors.New(errors.TypeAlreadyExists, types.ErrTOTPAlreadyExists, "totp is already enabled")
+	}
+
+	if err := m.verifyTOTP(ctx, factor, code); err != nil {
+		return err
+	}
+
+	factor.Enabled = true
+	if err := m.updateFactorTOTP(ctx, factor); err != nil {
+		return err
+	}
+
+	m.settings.Logger().InfoContext(ctx, "user enabled totp", "user_id", userID, "org_id", orgID)
+	return nil
+}
+
+func (m *Module) DisableTOTP(ctx context.Context, orgID string, userID string, code string) error {
+	required, err := m.isMFARequired(ctx, orgID)
+	if err != nil {
+		return err
+	}
+
+	if required {
+		return errors.New(errors.TypeForbidden, types.ErrMFARequired, "mfa is required by the organization")
+	}
+
+	factor, err := m.getEnabledFactorTOTP(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := m.verifyTOTP(ctx, factor, code); err != nil {
+		return err
+	}
+
+	if err := m.store.DeleteFactorTOTP(ctx, userID); err != nil {
+		return err
+	}
+
+	m.settings.Logger().InfoContext(ctx, "user disabled totp", "user_id", userID, "org_id", orgID)
+	return nil
+}
+
+func (m *Module) RegenerateRecoveryCodes(ctx context.Context, orgID string, userID string, code string) ([]string, error) {
+	factor, err := m.getEnabledFactorTOTP(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.verifyTOTP(ctx, factor, code); err != nil {
+		return nil, err
+	}
+
+	recoveryCodes, err := factor.RegenerateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.updateFactorTOTP(ctx, factor); err != nil {
+		return nil, err
+	}
+
+	return recoveryCodes, nil
+}
+
+func (m *Module) ResetMFA(ctx context.Context, orgID string, userID string) error {
+	user, err := m.store.GetUserByID(ctx, orgID, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := m.store.DeleteFactorTOTP(ctx, user.ID.StringValue()); err != nil {
+		return err
+	}
+
+	m.settings.Logger().InfoContext(ctx, "reset mfa of user", "email", user.Email, "org_id", orgID)
+	return nil
+}
+
+func (m *Module) isMFARequired(ctx context.Context, orgID string) (bool, error) {
+	id, err := valuer.NewUUID(orgID)
+	if err != nil {
+		return false, err
+	}
+
+	requireMFA, err := m.preference.GetByOrg(ctx, id, preferencetypes.NameRequireMFA)
+	if err != nil {
+		return false, err
+	}
+
+	return requireMFA.Value.Bool(), nil
+}
+
+// getFactorTOTP gets the factor of the user with its secret opened.
+func (m *Module) getFactorTOTP(ctx context.Context, userID string) (*types.FactorTOTP, error) {
+	factor, err := m.store.GetFactorTOTPByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := factor.OpenSecret(types.NewTOTPSecretKey(m.jwt.JwtSecret)); err != nil {
+		return nil, err
+	}
+
+	return factor, nil
+}
+
+// createFactorTOTP stores the factor with its secret sealed, the factor itself keeps the secret in clear.
+func (m *Module) createFactorTOTP(ctx context.Context, factor *types.FactorTOTP) error {
+	sealed := *factor
+	if err := sealed.SealSecret(types.NewTOTPSecretKey(m.jwt.JwtSecret)); err != nil {
+		return err
+	}
+
+	return m.store.CreateFactorTOTP(ctx, &sealed)
+}
+
+func (m *Module) updateFactorTOTP(ctx context.Context, factor *types.FactorTOTP) error {
+	sealed := *factor
+	if err := sealed.SealSecret(types.NewTOTPSecretKey(m.jwt.JwtSecret)); err != nil {
+		return err
+	}
+
+	return m.store.UpdateFactorTOTP(ctx, &sealed)
+}
+
+func (m *Module) getEnabledFactorTOTP(ctx context.Context, userID string) (*types.FactorTOTP, error) {
+	factor, err := m.getFactorTOTP(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !factor.Enabled {
+		return nil, errors.New(errors.TypeNotFound, types.ErrTOTPNotFound, "totp is not enabled")
+	}
+
+	return factor, nil
+}
+
+// verifyTOTP verifies the code, the caller has to save the factor on success so that the code can't be replayed.
+// Repeated failures lock the factor until a recovery code is used or an admin resets it.
+func (m *Module) verifyTOTP(ctx context.Context, factor *types.FactorTOTP, code string) error {
+	if factor.Locked() {
+		return errors.New(errors.TypeForbidden, types.ErrTOTPLocked, "too many invalid codes, use a recovery code or ask an admin to reset mfa")
+	}
+
+	// the attempt is counted before the code is checked so that concurrent requests can't try more codes than allowed
+	attempts, err := m.store.IncrementFactorTOTPAttempts(ctx, factor.ID)
+	if err != nil {
+		return err
+	}
+
+	factor.FailedAttempts = attempts - 1
+	if factor.Locked() {
+		return errors.New(errors.TypeForbidden, types.ErrTOTPLocked, "too many invalid codes, use a recovery code or ask an admin to reset mfa")
+	}
+
+	if !factor.Verify(code, time.Now()) {
+		return errors.New(errors.TypeInvalidInput, types.ErrInvalidTOTPCode, "invalid code")
+	}
+
+	factor.FailedAttempts = 0
+	return nil
+}
+
 func (m *Module) CreateAPIKey(ctx context.Context, apiKey *types.StorableAPIKey) error {
-	return m.store.CreateAPIKey(ctx, apiKey)
+	// api keys act in the org they are created in, their user has to be a member of it
+	if _, err := m.store.GetUserByID(ctx, apiKey.OrgID.StringValue(), apiKey.UserID.StringValue()); err != nil {
+		return err
+	}
+
+	if err := m.store.CreateAPIKey(ctx, apiKey); err != nil {
+		return err
+	}
+
+	m.recordAPIKey(ctx, audittypes.ActionCreate, apiKey.ID, nil, apiKey)
+	return nil
 }
 
 func (m *Module) UpdateAPIKey(ctx context.Context, id valuer.UUID, apiKey *types.StorableAPIKey, updaterID valuer.UUID) error {
-	return m.store.UpdateAPIKey(ctx, id, apiKey, updaterID)
+	before := m.getAPIKeyForAudit(ctx, id)
+	if err := m.store.UpdateAPIKey(ctx, id, apiKey, updaterID); err != nil {
+		return err
+	}
+
+	m.recordAPIKey(ctx, audittypes.ActionUpdate, id, before, apiKey)
+	return nil
 }
 
 func (m *Module) ListAPIKeys(ctx context.Context, orgID valuer.UUID) ([]*types.StorableAPIKeyUser, error) {
@@ -587,7 +1276,13 @@ func (m *Module) GetAPIKey(ctx context.Context, orgID, id valuer.UUID) (*types.S
 }
 
 func (m *Module) RevokeAPIKey(ctx context.Context, id, removedByUserID valuer.UUID) error {
-	return m.store.RevokeAPIKey(ctx, id, removedByUserID)
+	before := m.getAPIKeyForAudit(ctx, id)
+	if err := m.store.RevokeAPIKey(ctx, id, removedByUserID); err != nil {
+		return err
+	}
+
+	m.recordAPIKey(ctx, audittypes.ActionDelete, id, before, nil)
+	return nil
 }
 
 func (m *Module) GetDomainFromSsoResponse(ctx context.Context, url *url.URL) (*types.GettableOrgDomain, error) {
@@ -651,3 +1346,46 @@ func (m *Module) Collect(ctx context.Context, orgID valuer.UUID) (map[string]any
 
 	return map[string]any{"user.count": count}, nil
 }
+
+func (m *Module) recordUser(ctx context.Context, orgID string, action audittypes.Action, id string, before any, after any) {
+	orgUUID, err := valuer.NewUUID(orgID)
+	if err != nil {
+		return
+	}
+
+	m.audit.Record(ctx, orgUUID, action, audittypes.NewResource(audittypes.ResourceTypeUser, id), before, after)
+}
+
+// recordAPIKey records changes to api keys in the org of the actor, api keys are only managed by signed in users.
+func (m *Module) recordAPIKey(ctx context.Context, action audittypes.Action, id valuer.UUID, before any, after any) {
+	claims, err := authtypes.ClaimsFromContext(ctx)
+	if err != nil {
+		return
+	}
+
+	orgID, err := valuer.NewUUID(claims.OrgID)
+	if err != nil {
+		return
+	}
+
+	m.audit.Record(ctx, orgID, action, audittypes.NewResource(audittypes.ResourceTypeAPIKey, id.StringValue()), before, after)
+}
+
+func (m *Module) getAPIKeyForAudit(ctx context.Context, id valuer.UUID) any {
+	claims, err := authtypes.ClaimsFromContext(ctx)
+	if err != nil {
+		return nil
+	}
+
+	orgID, err := valuer.NewUUID(claims.OrgID)
+	if err != nil {
+		return nil
+	}
+
+	apiKey, err := m.store.GetAPIKey(ctx, orgID, id)
+	if err != nil {
+		return nil
+	}
+
+	return apiKey
+}
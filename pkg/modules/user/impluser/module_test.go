@@ -0,0 +1,372 @@
+package impluser
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/analytics/analyticstest"
+	"github.com/SigNoz/signoz/pkg/emailing/emailingtest"
+	"github.com/SigNoz/signoz/pkg/errors"
+	"github.com/SigNoz/signoz/pkg/factory/factorytest"
+	"github.com/SigNoz/signoz/pkg/modules/audit/implaudit"
+	"github.com/SigNoz/signoz/pkg/modules/preference/implpreference"
+	"github.com/SigNoz/signoz/pkg/query-service/utils"
+	"github.com/SigNoz/signoz/pkg/types"
+	"github.com/SigNoz/signoz/pkg/types/authtypes"
+	"github.com/SigNoz/signoz/pkg/types/preferencetypes"
+	"github.com/SigNoz/signoz/pkg/types/ssotypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModuleMFA(t *testing.T) {
+	ctx := context.Background()
+	sqlStore := utils.NewQueryServiceDBForTests(t)
+	require.NoError(t, utils.CreateTestOrg(t, sqlStore))
+	orgID, err := utils.GetTestOrgId(sqlStore)
+	require.NoError(t, err)
+
+	providerSettings := factorytest.NewSettings()
+	preference := implpreference.NewModule(implpreference.NewStore(sqlStore), preferencetypes.NewAvailablePreference())
+	module := NewModule(NewStore(sqlStore, providerSettings), authtypes.NewJWT("secret", time.Hour, time.Hour), emailingtest.New(), providerSettings, nil, preference, analyticstest.New(), implaudit.NewModule(implaudit.NewStore(sqlStore), providerSettings))
+
+	jane, err := types.NewUser("jane", "jane@example.com", types.RoleAdmin.String(), orgID.StringValue())
+	require.NoError(t, err)
+	require.NoError(t, module.CreateUser(ctx, jane))
+
+	// without a factor nor a policy the password is enough
+	challenge, err := module.PrepareMFAChallenge(ctx, jane)
+	require.NoError(t, err)
+	assert.Nil(t, challenge)
+
+	enrollment, err := module.EnrollTOTP(ctx, orgID.StringValue(), jane.ID.StringValue())
+	require.NoError(t, err)
+	assert.Len(t, enrollment.RecoveryCodes, 10)
+	factor := &types.FactorTOTP{Secret: enrollment.Secret}
+
+	// the secret is not stored in clear
+	stored := new(types.FactorTOTP)
+	require.NoError(t, sqlStore.BunDB().NewSelect().Model(stored).Where("user_id = ?", jane.ID.StringValue()).Scan(ctx))
+	assert.NotContains(t, stored.Secret, enrollment.Secret)
+
+	// the factor is pending until confirmed
+	challenge, err = module.PrepareMFAChallenge(ctx, jane)
+	require.NoError(t, err)
+	assert.Nil(t, challenge)
+
+	code, err := factor.Code(time.Now())
+	require.NoError(t, err)
+	require.NoError(t, module.ConfirmTOTP(ctx, orgID.StringValue(), jane.ID.StringValue(), code))
+
+	challenge, err = module.PrepareMFAChallenge(ctx, jane)
+	require.NoError(t, err)
+	require.NotNil(t, challenge)
+	assert.False(t, challenge.EnrollmentRequired)
+
+	// the code used to confirm can't be replayed
+	_, err = module.VerifyMFAChallenge(ctx, &types.PostableMFAChallenge{Token: challenge.Token, Code: code})
+	assert.True(t, errors.Asc(err, types.ErrInvalidTOTPCode))
+
+	code, err = factor.Code(time.Now().Add(30 * time.Second))
+	require.NoError(t, err)
+	user, err := module.VerifyMFAChallenge(ctx, &types.PostableMFAChallenge{Token: challenge.Token, Code: code})
+	require.NoError(t, err)
+	assert.Equal(t, jane.ID, user.ID)
+
+	// repeated invalid codes lock the factor until a recovery code is used
+	for range 10 {
+		_, err = module.VerifyMFAChallenge(ctx, &types.PostableMFAChallenge{Token: challenge.Token, Code: "invalid"})
+		assert.True(t, errors.Asc(err, types.ErrInvalidTOTPCode))
+	}
+	_, err = module.VerifyMFAChallenge(ctx, &types.PostableMFAChallenge{Token: challenge.Token, Code: "invalid"})
+	assert.True(t, errors.Asc(err, types.ErrTOTPLocked))
+
+	_, err = module.VerifyMFAChallenge(ctx, &types.PostableMFAChallenge{Token: challenge.Token, RecoveryCode: enrollment.RecoveryCodes[0]})
+	require.NoError(t, err)
+	_, err = module.VerifyMFAChallenge(ctx, &types.PostableMFAChallenge{Token: challenge.Token, RecoveryCode: enrollment.RecoveryCodes[0]})
+	assert.True(t, errors.Asc(err, types.ErrInvalidTOTPCode))
+
+	status, err := module.GetMFAStatus(ctx, orgID.StringValue(), jane.ID.StringValue())
+	require.NoError(t, err)
+	assert.Equal(t, &types.GettableMFAStatus{Enabled: true, Required: false, RecoveryCodesLeft: 9}, status)
+
+	// failures are counted by the store, concurrent attempts reading the same factor still lock it
+	stale, err := module.(*Module).getEnabledFactorTOTP(ctx, jane.ID.StringValue())
+	require.NoError(t, err)
+	for range 10 {
+		attempt := *stale
+		assert.True(t, errors.Asc(module.(*Module).verifyTOTP(ctx, &attempt, "invalid"), types.ErrInvalidTOTPCode))
+	}
+	attempt := *stale
+	assert.True(t, errors.Asc(module.(*Module).verifyTOTP(ctx, &attempt, "invalid"), types.ErrTOTPLocked))
+
+	// the challenge token is not an access token
+	_, err = authtypes.NewJWT("secret", time.Hour, time.Hour).Claims(challenge.Token)
+	assert.True(t, errors.Ast(err, errors.TypeUnauthenticated))
+
+	// once required, the factor can't be disabled and users without one enroll at login
+	require.NoError(t, preference.UpdateByOrg(ctx, orgID, preferencetypes.NameRequireMFA, true))
+	assert.True(t, errors.Asc(module.DisableTOTP(ctx, orgID.StringValue(), jane.ID.StringValue(), "000000"), types.ErrMFARequired))
+
+	john, err := types.NewUser("john", "john@example.com", types.RoleViewer.String(), orgID.StringValue())
+	require.NoError(t, err)
+	require.NoError(t, module.CreateUser(ctx, john))
+
+	challenge, err = module.PrepareMFAChallenge(ctx, john)
+	require.NoError(t, err)
+	require.NotNil(t, challenge)
+	assert.True(t, challenge.EnrollmentRequired)
+
+	enrollment, err = module.EnrollTOTPWithChallenge(ctx, challenge.Token)
+	require.NoError(t, err)
+	code, err = (&types.FactorTOTP{Secret: enrollment.Secret}).Code(time.Now())
+	require.NoError(t, err)
+	_, err = module.VerifyMFAChallenge(ctx, &types.PostableMFAChallenge{Token: challenge.Token, Code: code})
+	require.NoError(t, err)
+
+	status, err = module.GetMFAStatus(ctx, orgID.StringValue(), john.ID.StringValue())
+	require.NoError(t, err)
+	assert.True(t, status.Enabled)
+
+	require.NoError(t, module.ResetMFA(ctx, orgID.StringValue(), john.ID.StringValue()))
+	status, err = module.GetMFAStatus(ctx, orgID.StringValue(), john.ID.StringValue())
+	require.NoError(t, err)
+	assert.Equal(t, &types.GettableMFAStatus{Enabled: false, Required: true}, status)
+}
+
+func TestModuleSessions(t *testing.T) {
+	ctx := context.Background()
+	sqlStore := utils.NewQueryServiceDBForTests(t)
+	require.NoError(t, utils.CreateTestOrg(t, sqlStore))
+	orgID, err := utils.GetTestOrgId(sqlStore)
+	require.NoError(t, err)
+
+	providerSettings := factorytest.NewSettings()
+	preference := implpreference.NewModule(implpreference.NewStore(sqlStore), preferencetypes.NewAvailablePreference())
+	jwt := authtypes.NewJWT("secret", time.Hour, time.Hour)
+	module := NewModule(NewStore(sqlStore, providerSettings), jwt, emailingtest.New(), providerSettings, nil, preference, analyticstest.New(), implaudit.NewModule(implaudit.NewStore(sqlStore), providerSettings))
+
+	jane, err := types.NewUser("jane", "jane@example.com", types.RoleAdmin.String(), orgID.StringValue())
+	require.NoError(t, err)
+	password, err := types.NewFactorPassword("password123Z$")
+	require.NoError(t, err)
+	_, err = module.CreateUserWithPassword(ctx, jane, password)
+	require.NoError(t, err)
+
+	validate := func(token string) error {
+		claims, err := jwt.Claims(token)
+		require.NoError(t, err)
+		return module.ValidateSession(ctx, claims)
+	}
+
+	laptop, err := module.GetJWTForUser(ctx, jane)
+	require.NoError(t, err)
+	phone, err := module.GetJWTForUser(ctx, jane)
+	require.NoError(t, err)
+	require.NoError(t, validate(laptop.AccessJwt))
+
+	sessions, err := module.ListSessions(ctx, orgID.StringValue(), jane.ID.StringValue())
+	require.NoError(t, err)
+	assert.Len(t, sessions, 2)
+
+	// refresh tokens are rotated and can only be exchanged once
+	_, rotated, err := module.RotateSession(ctx, laptop.RefreshJwt)
+	require.NoError(t, err)
+	require.NoError(t, validate(rotated.AccessJwt))
+
+	_, _, err = module.RotateSession(ctx, laptop.RefreshJwt)
+	assert.True(t, errors.Asc(err, types.ErrSessionRevoked))
+	// the reuse revokes the whole session
+	assert.True(t, errors.Asc(validate(rotated.AccessJwt), types.ErrSessionRevoked))
+	_, _, err = module.RotateSession(ctx, rotated.RefreshJwt)
+	assert.True(t, errors.Asc(err, types.ErrSessionRevoked))
+	require.NoError(t, validate(phone.AccessJwt))
+
+	// users changing their password keep the session they change it from
+	current, err := module.GetJWTForUser(ctx, jane)
+	require.NoError(t, err)
+	currentClaims, err := jwt.Claims(current.AccessJwt)
+	require.NoError(t, err)
+	require.NoError(t, module.UpdatePassword(authtypes.NewContextWithClaims(ctx, currentClaims), jane.ID.StringValue(), "password456Z$"))
+	require.NoError(t, validate(current.AccessJwt))
+	assert.True(t, errors.Asc(validate(phone.AccessJwt), types.ErrSessionRevoked))
+
+	sessions, err = module.ListSessions(ctx, orgID.StringValue(), jane.ID.StringValue())
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+
+	// sessions of other users can't be revoked
+	assert.True(t, errors.Ast(module.RevokeSession(ctx, orgID.StringValue(), "other", sessions[0].ID.StringValue()), errors.TypeNotFound))
+	require.NoError(t, module.RevokeSession(ctx, orgID.StringValue(), jane.ID.StringValue(), sessions[0].ID.StringValue()))
+	assert.True(t, errors.Asc(validate(current.AccessJwt), types.ErrSessionRevoked))
+
+	// a role downgrade signs the user out of all their sessions
+	john, err := types.NewUser("john", "john@example.com", types.RoleAdmin.String(), orgID.StringValue())
+	require.NoError(t, err)
+	require.NoError(t, module.CreateUser(ctx, john))
+	johnJWT, err := module.GetJWTForUser(ctx, john)
+	require.NoError(t, err)
+
+	_, err = module.UpdateUser(ctx, orgID.StringValue(), john.ID.StringValue(), &types.User{DisplayName: "john", Role: types.RoleViewer.String()})
+	require.NoError(t, err)
+	assert.True(t, errors.Asc(validate(johnJWT.AccessJwt), types.ErrSessionRevoked))
+
+	// tokens issued without a session are rejected
+	legacy, _, err := jwt.AccessToken(orgID.StringValue(), jane.ID.StringValue(), jane.Email, types.RoleAdmin, "")
+	require.NoError(t, err)
+	assert.True(t, errors.Ast(validate(legacy), errors.TypeUnauthenticated))
+}
+
+func TestModuleOrgMembership(t *testing.T) {
+	ctx := context.Background()
+	sqlStore := utils.NewQueryServiceDBForTests(t)
+	require.NoError(t, utils.CreateTestOrg(t, sqlStore))
+	homeOrgID, err := utils.GetTestOrgId(sqlStore)
+	require.NoError(t, err)
+	customerOrg := types.NewOrganization("customer")
+	_, err = sqlStore.BunDB().NewInsert().Model(customerOrg).Exec(ctx)
+	require.NoError(t, err)
+
+	providerSettings := factorytest.NewSettings()
+	preference := implpreference.NewModule(implpreference.NewStore(sqlStore), preferencetypes.NewAvailablePreference())
+	jwt := authtypes.NewJWT("secret", time.Hour, time.Hour)
+	module := NewModule(NewStore(sqlStore, providerSettings), jwt, emailingtest.New(), providerSettings, nil, preference, analyticstest.New(), implaudit.NewModule(implaudit.NewStore(sqlStore), providerSettings))
+
+	jane, err := types.NewUser("jane", "jane@example.com", types.RoleAdmin.String(), homeOrgID.StringValue())
+	require.NoError(t, err)
+	password, err := types.NewFactorPassword("password123Z$")
+	require.NoError(t, err)
+	_, err = module.CreateUserWithPassword(ctx, jane, password)
+	require.NoError(t, err)
+
+	require.NoError(t, module.AddToOrg(ctx, customerOrg.ID.StringValue(), jane.ID.StringValue(), types.RoleViewer))
+	assert.True(t, errors.Ast(module.AddToOrg(ctx, customerOrg.ID.StringValue(), jane.ID.StringValue(), types.RoleViewer), errors.TypeAlreadyExists))
+
+	// the user has the role of their membership in each org
+	member, err := module.GetUserByID(ctx, customerOrg.ID.StringValue(), jane.ID.StringValue())
+	require.NoError(t, err)
+	assert.Equal(t, types.RoleViewer.String(), member.Role)
+	assert.Equal(t, customerOrg.ID.StringValue(), member.OrgID)
+	users, err := module.ListUsers(ctx, customerOrg.ID.StringValue())
+	require.NoError(t, err)
+	assert.Len(t, users, 1)
+
+	orgs, err := module.ListOrgs(ctx, jane.ID.StringValue())
+	require.NoError(t, err)
+	assert.Len(t, orgs, 2)
+
+	precheck, err := module.LoginPrecheck(ctx, "", jane.Email, "")
+	require.NoError(t, err)
+	assert.True(t, precheck.SelectOrg)
+	assert.ElementsMatch(t, []string{homeOrgID.StringValue(), customerOrg.ID.StringValue()}, precheck.Orgs)
+
+	// the login is scoped to the selected org
+	user, err := module.GetAuthenticatedUser(ctx, customerOrg.ID.StringValue(), jane.Email, "password123Z$")
+	require.NoError(t, err)
+	assert.Equal(t, customerOrg.ID.StringValue(), user.OrgID)
+	user, err = module.GetAuthenticatedUser(ctx, "", jane.Email, "password123Z$")
+	require.NoError(t, err)
+	assert.Equal(t, homeOrgID.StringValue(), user.OrgID)
+
+	home, err := module.GetJWTForUser(ctx, user)
+	require.NoError(t, err)
+	homeClaims, err := jwt.Claims(home.AccessJwt)
+	require.NoError(t, err)
+
+	_, switched, err := module.SwitchOrg(authtypes.NewContextWithClaims(ctx, homeClaims), jane.ID.StringValue(), customerOrg.ID.StringValue())
+	require.NoError(t, err)
+	switchedClaims, err := jwt.Claims(switched.AccessJwt)
+	require.NoError(t, err)
+	assert.Equal(t, customerOrg.ID.StringValue(), switchedClaims.OrgID)
+	assert.Equal(t, types.RoleViewer, switchedClaims.Role)
+	require.NoError(t, module.ValidateSession(ctx, switchedClaims))
+	assert.True(t, errors.Asc(module.ValidateSession(ctx, homeClaims), types.ErrSessionRevoked))
+
+	_, _, err = module.SwitchOrg(ctx, jane.ID.StringValue(), valuer.GenerateUUID().StringValue())
+	assert.True(t, errors.Ast(err, errors.TypeForbidden))
+
+	// removing the user from their home org moves them to their other org
+	john, err := types.NewUser("john", "john@example.com", types.RoleAdmin.String(), homeOrgID.StringValue())
+	require.NoError(t, err)
+	require.NoError(t, module.CreateUser(ctx, john))
+
+	// api keys are bound to the org they are created in, their user has to be a member of it
+	homeKey, err := types.NewStorableAPIKey("home", homeOrgID, jane.ID, types.RoleAdmin, 0)
+	require.NoError(t, err)
+	require.NoError(t, module.CreateAPIKey(ctx, homeKey))
+	customerKey, err := types.NewStorableAPIKey("customer", customerOrg.ID, jane.ID, types.RoleViewer, 0)
+	require.NoError(t, err)
+	require.NoError(t, module.CreateAPIKey(ctx, customerKey))
+	johnKey, err := types.NewStorableAPIKey("john", customerOrg.ID, john.ID, types.RoleViewer, 0)
+	require.NoError(t, err)
+	assert.True(t, errors.Ast(module.CreateAPIKey(ctx, johnKey), errors.TypeNotFound))
+
+	apiKeys, err := module.ListAPIKeys(ctx, customerOrg.ID)
+	require.NoError(t, err)
+	require.Len(t, apiKeys, 1)
+	assert.Equal(t, customerKey.ID, apiKeys[0].ID)
+	_, err = module.GetAPIKey(ctx, customerOrg.ID, homeKey.ID)
+	assert.True(t, errors.Ast(err, errors.TypeNotFound))
+
+	require.NoError(t, module.DeleteUser(ctx, homeOrgID.StringValue(), jane.ID.StringValue()))
+
+	// the api keys of the org left are revoked
+	_, err = module.GetAPIKey(ctx, homeOrgID, homeKey.ID)
+	assert.True(t, errors.Ast(err, errors.TypeNotFound))
+	_, err = module.GetAPIKey(ctx, customerOrg.ID, customerKey.ID)
+	require.NoError(t, err)
+
+	_, err = module.GetUserByID(ctx, homeOrgID.StringValue(), jane.ID.StringValue())
+	assert.True(t, errors.Ast(err, errors.TypeNotFound))
+	// the sessions in the other orgs of the user are kept
+	require.NoError(t, module.ValidateSession(ctx, switchedClaims))
+	user, err = module.GetAuthenticatedUser(ctx, "", jane.Email, "password123Z$")
+	require.NoError(t, err)
+	assert.Equal(t, customerOrg.ID.StringValue(), user.OrgID)
+	assert.Equal(t, types.RoleViewer.String(), user.Role)
+}
+
+func TestModulePrepareSsoRedirectInOtherOrg(t *testing.T) {
+	ctx := context.Background()
+	sqlStore := utils.NewQueryServiceDBForTests(t)
+	require.NoError(t, utils.CreateTestOrg(t, sqlStore))
+	homeOrgID, err := utils.GetTestOrgId(sqlStore)
+	require.NoError(t, err)
+	customerOrg := types.NewOrganization("customer")
+	_, err = sqlStore.BunDB().NewInsert().Model(customerOrg).Exec(ctx)
+	require.NoError(t, err)
+
+	providerSettings := factorytest.NewSettings()
+	jwt := authtypes.NewJWT("secret", time.Hour, time.Hour)
+	module := NewModule(NewStore(sqlStore, providerSettings), jwt, emailingtest.New(), providerSettings, nil, nil, analyticstest.New(), implaudit.NewModule(implaudit.NewStore(sqlStore), providerSettings))
+
+	jane, err := types.NewUser("jane", "jane@example.com", types.RoleAdmin.String(), homeOrgID.StringValue())
+	require.NoError(t, err)
+	require.NoError(t, module.CreateUser(ctx, jane))
+
+	domain := &types.GettableOrgDomain{
+		StorableOrgDomain: types.StorableOrgDomain{OrgID: customerOrg.ID.StringValue(), Name: "example.com"},
+		RoleMapping:       &types.RoleMapping{GroupRoles: map[string]types.Role{"sre": types.RoleEditor}},
+	}
+	identity := &ssotypes.SSOIdentity{Email: jane.Email, Attributes: map[string][]string{"groups": {"sre"}}}
+
+	// the user signs in to the org of the domain and not to their home org
+	for i := 0; i < 2; i++ {
+		redirect, err := module.PrepareSsoRedirect(ctx, "https://signoz.example.com/login", domain, identity, jwt)
+		require.NoError(t, err)
+		redirectURL, err := url.Parse(redirect)
+		require.NoError(t, err)
+
+		claims, err := jwt.Claims(redirectURL.Query().Get("jwt"))
+		require.NoError(t, err)
+		assert.Equal(t, customerOrg.ID.StringValue(), claims.OrgID)
+		assert.Equal(t, types.RoleEditor, claims.Role)
+	}
+
+	home, err := module.GetUserByID(ctx, homeOrgID.StringValue(), jane.ID.StringValue())
+	require.NoError(t, err)
+	assert.Equal(t, types.RoleAdmin.String(), home.Role)
+}
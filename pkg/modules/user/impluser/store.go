@@ -5,7 +5,6 @@ import (
 	"database/sql"
 	"encoding/json"
 	"net/url"
-	"sort"
 	"strings"
 	"time"
 
@@ -13,6 +12,7 @@ import (
 	"github.com/SigNoz/signoz/pkg/factory"
 	"github.com/SigNoz/signoz/pkg/sqlstore"
 	"github.com/SigNoz/signoz/pkg/types"
+	"github.com/SigNoz/signoz/pkg/types/teamtypes"
 	"github.com/SigNoz/signoz/pkg/valuer"
 	"github.com/google/uuid"
 	"github.com/uptrace/bun"
@@ -126,10 +126,8 @@ func (store *store) CreateUserWithPassword(ctx context.Context, user *types.User
 		_ = tx.Rollback()
 	}()
 
-	if _, err := tx.NewInsert().
-		Model(user).
-		Exec(ctx); err != nil {
-		return nil, store.sqlstore.WrapAlreadyExistsErrf(err, types.ErrUserAlreadyExists, "user with email: %s already exists in org: %s", user.Email, user.OrgID)
+	if err := store.createUser(ctx, tx, user); err != nil {
+		return nil, err
 	}
 
 	password.UserID = user.ID.StringValue()
@@ -148,12 +146,41 @@ func (store *store) CreateUserWithPassword(ctx context.Context, user *types.User
 }
 
 func (store *store) CreateUser(ctx context.Context, user *types.User) error {
-	_, err := store.sqlstore.BunDB().NewInsert().
-		Model(user).
-		Exec(ctx)
+	tx, err := store.sqlstore.BunDB().BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrapf(err, errors.TypeInternal, errors.CodeInternal, "failed to start transaction")
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if err := store.createUser(ctx, tx, user); err != nil {
+		return err
+	}
+
+	err = tx.Commit()
 	if err != nil {
+		return errors.Wrapf(err, errors.TypeInternal, errors.CodeInternal, "failed to commit transaction")
+	}
+
+	return nil
+}
+
+// createUser creates the user along with the membership of their home org.
+func (store *store) createUser(ctx context.Context, tx bun.Tx, user *types.User) error {
+	if _, err := tx.NewInsert().
+		Model(user).
+		Exec(ctx); err != nil {
 		return store.sqlstore.WrapAlreadyExistsErrf(err, types.ErrUserAlreadyExists, "user with email: %s already exists in org: %s", user.Email, user.OrgID)
 	}
+
+	if _, err := tx.NewInsert().
+		Model(types.NewOrgMembership(user.ID.StringValue(), user.OrgID, types.Role(user.Role))).
+		Exec(ctx); err != nil {
+		return store.sqlstore.WrapAlreadyExistsErrf(err, types.ErrMembershipAlreadyExists, "user with email: %s is already a member of org: %s", user.Email, user.OrgID)
+	}
+
 	return nil
 }
 
@@ -184,10 +211,9 @@ func (store *store) getOrgNameByID(ctx context.Context, orgID string) (string, e
 
 func (store *store) GetUserByID(ctx context.Context, orgID string, id string) (*types.GettableUser, error) {
 	user := new(types.User)
-	err := store.sqlstore.BunDB().NewSelect().
-		Model(user).
-		Where("org_id = ?", orgID).
-		Where("id = ?", id).
+	err := store.
+		selectMembers(ctx, user, orgID).
+		Where("?TableAlias.id = ?", id).
 		Scan(ctx)
 	if err != nil {
 		return nil, store.sqlstore.WrapNotFoundErrf(err, types.ErrUserNotFound, "user with id: %s does not exist in org: %s", id, orgID)
@@ -202,12 +228,42 @@ func (store *store) GetUserByID(ctx context.Context, orgID string, id string) (*
 	return &types.GettableUser{User: *user, Organization: orgName}, nil
 }
 
-func (store *store) GetUserByEmailInOrg(ctx context.Context, orgID string, email string) (*types.GettableUser, error) {
+func (store *store) GetUser(ctx context.Context, id string) (*types.User, error) {
 	user := new(types.User)
-	err := store.sqlstore.BunDB().NewSelect().
+	err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewSelect().
 		Model(user).
-		Where("org_id = ?", orgID).
-		Where("email = ?", email).
+		Where("id = ?", id).
+		Scan(ctx)
+	if err != nil {
+		return nil, store.sqlstore.WrapNotFoundErrf(err, types.ErrUserNotFound, "user with id: %s does not exist", id)
+	}
+
+	return user, nil
+}
+
+// selectMembers selects the users with a membership in the org, the org and role of the selected users are the ones
+// of their membership.
+func (store *store) selectMembers(ctx context.Context, model any, orgID string) *bun.SelectQuery {
+	return store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewSelect().
+		Model(model).
+		ColumnExpr("?TableAlias.id, ?TableAlias.display_name, ?TableAlias.email, ?TableAlias.created_at, ?TableAlias.updated_at").
+		ColumnExpr("org_membership.org_id, org_membership.role").
+		Join("JOIN org_membership ON org_membership.user_id = ?TableAlias.id").
+		Where("org_membership.org_id = ?", orgID)
+}
+
+func (store *store) GetUserByEmailInOrg(ctx context.Context, orgID string, email string) (*types.GettableUser, error) {
+	user := new(types.User)
+	err := store.
+		selectMembers(ctx, user, orgID).
+		Where("?TableAlias.email = ?", email).
+		Limit(1).
 		Scan(ctx)
 	if err != nil {
 		return nil, store.sqlstore.WrapNotFoundErrf(err, types.ErrUserNotFound, "user with email: %s does not exist in org: %s", email, orgID)
@@ -246,10 +302,9 @@ func (store *store) GetUsersByEmail(ctx context.Context, email string) ([]*types
 
 func (store *store) GetUsersByRoleInOrg(ctx context.Context, orgID string, role types.Role) ([]*types.GettableUser, error) {
 	users := new([]*types.User)
-	err := store.sqlstore.BunDB().NewSelect().
-		Model(users).
-		Where("org_id = ?", orgID).
-		Where("role = ?", role).
+	err := store.
+		selectMembers(ctx, users, orgID).
+		Where("org_membership.role = ?", role).
 		Scan(ctx)
 	if err != nil {
 		return nil, store.sqlstore.WrapNotFoundErrf(err, types.ErrUserNotFound, "user with role: %s does not exist in org: %s", role, orgID)
@@ -267,16 +322,53 @@ func (store *store) GetUsersByRoleInOrg(ctx context.Context, orgID string, role
 	return usersWithOrg, nil
 }
 
+// UpdateUser updates the display name of the user and their role in the org, the role stored on the user is kept in sync
+// with the one of their home org.
 func (store *store) UpdateUser(ctx context.Context, orgID string, id string, user *types.User) (*types.User, error) {
 	user.UpdatedAt = time.Now()
-	_, err := store.sqlstore.BunDB().NewUpdate().
-		Model(user).
-		Column("display_name").
-		Column("role").
-		Column("updated_at").
-		Where("id = ?", id).
-		Where("org_id = ?", orgID).
-		Exec(ctx)
+	err := store.sqlstore.RunInTxCtx(ctx, nil, func(ctx context.Context) error {
+		result, err := store.
+			sqlstore.
+			BunDBCtx(ctx).
+			NewUpdate().
+			Model(new(types.OrgMembership)).
+			Set("role = ?", user.Role).
+			Set("updated_at = ?", user.UpdatedAt).
+			Where("user_id = ?", id).
+			Where("org_id = ?", orgID).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+			return errors.Newf(errors.TypeNotFound, types.ErrUserNotFound, "user with id: %s does not exist in org: %s", id, orgID)
+		}
+
+		_, err = store.
+			sqlstore.
+			BunDBCtx(ctx).
+			NewUpdate().
+			Model(user).
+			Column("display_name").
+			Column("updated_at").
+			Where("id = ?", id).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		_, err = store.
+			sqlstore.
+			BunDBCtx(ctx).
+			NewUpdate().
+			Model(user).
+			Column("role").
+			Where("id = ?", id).
+			Where("org_id = ?", orgID).
+			Exec(ctx)
+		return err
+	})
 	if err != nil {
 		return nil, store.sqlstore.WrapNotFoundErrf(err, types.ErrUserNotFound, "user with id: %s does not exist in org: %s", id, orgID)
 	}
@@ -285,9 +377,8 @@ func (store *store) UpdateUser(ctx context.Context, orgID string, id string, use
 
 func (store *store) ListUsers(ctx context.Context, orgID string) ([]*types.GettableUser, error) {
 	users := []*types.User{}
-	err := store.sqlstore.BunDB().NewSelect().
-		Model(&users).
-		Where("org_id = ?", orgID).
+	err := store.
+		selectMembers(ctx, &users, orgID).
 		Scan(ctx)
 	if err != nil {
 		return nil, store.sqlstore.WrapNotFoundErrf(err, types.ErrUserNotFound, "users with org id: %s does not exist", orgID)
@@ -305,6 +396,8 @@ func (store *store) ListUsers(ctx context.Context, orgID string) ([]*types.Getta
 	return usersWithOrg, nil
 }
 
+// DeleteUser removes the user from the org, the user is only deleted along with their factors once they are not a
+// member of any org anymore.
 func (store *store) DeleteUser(ctx context.Context, orgID string, id string) error {
 
 	tx, err := store.sqlstore.BunDB().BeginTx(ctx, nil)
@@ -316,6 +409,47 @@ func (store *store) DeleteUser(ctx context.Context, orgID string, id string) err
 		_ = tx.Rollback()
 	}()
 
+	_, err = tx.NewDelete().
+		Model(new(types.OrgMembership)).
+		Where("user_id = ?", id).
+		Where("org_id = ?", orgID).
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrapf(err, errors.TypeInternal, errors.CodeInternal, "failed to delete membership")
+	}
+
+	// delete the team memberships in the org
+	_, err = tx.NewDelete().
+		Model(new(teamtypes.Member)).
+		Where("user_id = ?", id).
+		Where("team_id IN (?)", tx.NewSelect().Model(new(teamtypes.Team)).Column("id").Where("org_id = ?", orgID)).
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrapf(err, errors.TypeInternal, errors.CodeInternal, "failed to delete team memberships")
+	}
+
+	memberships := []*types.OrgMembership{}
+	err = tx.NewSelect().
+		Model(&memberships).
+		Where("user_id = ?", id).
+		Order("created_at ASC").
+		Scan(ctx)
+	if err != nil {
+		return errors.Wrapf(err, errors.TypeInternal, errors.CodeInternal, "failed to list memberships")
+	}
+
+	if len(memberships) > 0 {
+		if err := store.leaveOrg(ctx, tx, orgID, id, memberships[0]); err != nil {
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return errors.Wrapf(err, errors.TypeInternal, errors.CodeInternal, "failed to commit transaction")
+		}
+
+		return nil
+	}
+
 	// get the password id
 
 	var password types.FactorPassword
@@ -345,6 +479,24 @@ func (store *store) DeleteUser(ctx context.Context, orgID string, id string) err
 		return errors.Wrapf(err, errors.TypeInternal, errors.CodeInternal, "failed to delete factor password")
 	}
 
+	// delete factor totp
+	_, err = tx.NewDelete().
+		Model(new(types.FactorTOTP)).
+		Where("user_id = ?", id).
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrapf(err, errors.TypeInternal, errors.CodeInternal, "failed to delete factor totp")
+	}
+
+	// delete sessions
+	_, err = tx.NewDelete().
+		Model(new(types.StorableSession)).
+		Where("user_id = ?", id).
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrapf(err, errors.TypeInternal, errors.CodeInternal, "failed to delete sessions")
+	}
+
 	// delete api keys
 	_, err = tx.NewDelete().
 		Model(&types.StorableAPIKey{}).
@@ -357,7 +509,6 @@ func (store *store) DeleteUser(ctx context.Context, orgID string, id string) err
 	// delete user
 	_, err = tx.NewDelete().
 		Model(new(types.User)).
-		Where("org_id = ?", orgID).
 		Where("id = ?", id).
 		Exec(ctx)
 	if err != nil {
@@ -372,6 +523,44 @@ func (store *store) DeleteUser(ctx context.Context, orgID string, id string) err
 	return nil
 }
 
+// leaveOrg ends the sessions and revokes the api keys of a user removed from an org they are not the last member of,
+// the next of their memberships becomes their home org if they are removed from it.
+func (store *store) leaveOrg(ctx context.Context, tx bun.Tx, orgID string, id string, next *types.OrgMembership) error {
+	_, err := tx.NewDelete().
+		Model(new(types.StorableSession)).
+		Where("user_id = ?", id).
+		Where("org_id = ?", orgID).
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrapf(err, errors.TypeInternal, errors.CodeInternal, "failed to delete sessions")
+	}
+
+	_, err = tx.NewUpdate().
+		Model(new(types.StorableAPIKey)).
+		Set("revoked = ?", true).
+		Set("updated_at = ?", time.Now()).
+		Where("user_id = ?", id).
+		Where("org_id = ?", orgID).
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrapf(err, errors.TypeInternal, errors.CodeInternal, "failed to revoke API keys")
+	}
+
+	_, err = tx.NewUpdate().
+		Model(new(types.User)).
+		Set("org_id = ?", next.OrgID).
+		Set("role = ?", next.Role).
+		Set("updated_at = ?", time.Now()).
+		Where("id = ?", id).
+		Where("org_id = ?", orgID).
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrapf(err, errors.TypeInternal, errors.CodeInternal, "failed to update home org")
+	}
+
+	return nil
+}
+
 func (store *store) CreateResetPasswordToken(ctx context.Context, resetPasswordRequest *types.ResetPasswordRequest) error {
 	_, err := store.sqlstore.BunDB().NewInsert().
 		Model(resetPasswordRequest).
@@ -525,7 +714,7 @@ func (store *store) UpdateAPIKey(ctx context.Context, id valuer.UUID, apiKey *ty
 	apiKey.UpdatedAt = time.Now()
 	_, err := store.sqlstore.BunDB().NewUpdate().
 		Model(apiKey).
-		Column("role", "name", "updated_at", "updated_by").
+		Column("role", "name", "scopes", "rule_labels", "allowed_ips", "updated_at", "updated_by").
 		Where("id = ?", id).
 		Where("revoked = false").
 		Exec(ctx)
@@ -536,36 +725,23 @@ func (store *store) UpdateAPIKey(ctx context.Context, id valuer.UUID, apiKey *ty
 }
 
 func (store *store) ListAPIKeys(ctx context.Context, orgID valuer.UUID) ([]*types.StorableAPIKeyUser, error) {
-	orgUserAPIKeys := new(types.OrgUserAPIKey)
-
-	if err := store.sqlstore.BunDB().NewSelect().
-		Model(orgUserAPIKeys).
-		Relation("Users").
-		Relation("Users.APIKeys", func(q *bun.SelectQuery) *bun.SelectQuery {
-			return q.Where("revoked = false")
-		},
-		).
-		Relation("Users.APIKeys.CreatedByUser").
-		Relation("Users.APIKeys.UpdatedByUser").
-		Where("id = ?", orgID).
-		Scan(ctx); err != nil {
+	apiKeys := []*types.StorableAPIKeyUser{}
+	err := store.
+		sqlstore.
+		BunDB().
+		NewSelect().
+		Model(&apiKeys).
+		Relation("CreatedByUser").
+		Relation("UpdatedByUser").
+		Where("?TableAlias.org_id = ?", orgID).
+		Where("?TableAlias.revoked = false").
+		OrderExpr("?TableAlias.updated_at DESC").
+		Scan(ctx)
+	if err != nil {
 		return nil, errors.Wrapf(err, errors.TypeInternal, errors.CodeInternal, "failed to fetch API keys")
 	}
 
-	// Flatten the API keys from all users
-	var allAPIKeys []*types.StorableAPIKeyUser
-	for _, user := range orgUserAPIKeys.Users {
-		if user.APIKeys != nil {
-			allAPIKeys = append(allAPIKeys, user.APIKeys...)
-		}
-	}
-
-	// sort the API keys by updated_at
-	sort.Slice(allAPIKeys, func(i, j int) bool {
-		return allAPIKeys[i].UpdatedAt.After(allAPIKeys[j].UpdatedAt)
-	})
-
-	return allAPIKeys, nil
+	return apiKeys, nil
 }
 
 func (store *store) RevokeAPIKey(ctx context.Context, id, revokedByUserID valuer.UUID) error {
@@ -584,33 +760,23 @@ func (store *store) RevokeAPIKey(ctx context.Context, id, revokedByUserID valuer
 }
 
 func (store *store) GetAPIKey(ctx context.Context, orgID, id valuer.UUID) (*types.StorableAPIKeyUser, error) {
-	apiKey := new(types.OrgUserAPIKey)
-	if err := store.sqlstore.BunDB().NewSelect().
+	apiKey := new(types.StorableAPIKeyUser)
+	err := store.
+		sqlstore.
+		BunDB().
+		NewSelect().
 		Model(apiKey).
-		Relation("Users").
-		Relation("Users.APIKeys", func(q *bun.SelectQuery) *bun.SelectQuery {
-			return q.Where("revoked = false").Where("storable_api_key.id = ?", id).
-				OrderExpr("storable_api_key.updated_at DESC").Limit(1)
-		},
-		).
-		Relation("Users.APIKeys.CreatedByUser").
-		Relation("Users.APIKeys.UpdatedByUser").
-		Scan(ctx); err != nil {
+		Relation("CreatedByUser").
+		Relation("UpdatedByUser").
+		Where("?TableAlias.id = ?", id).
+		Where("?TableAlias.org_id = ?", orgID).
+		Where("?TableAlias.revoked = false").
+		Scan(ctx)
+	if err != nil {
 		return nil, store.sqlstore.WrapNotFoundErrf(err, types.ErrAPIKeyNotFound, "API key with id: %s does not exist", id)
 	}
 
-	// flatten the API keys
-	flattenedAPIKeys := []*types.StorableAPIKeyUser{}
-	for _, user := range apiKey.Users {
-		if user.APIKeys != nil {
-			flattenedAPIKeys = append(flattenedAPIKeys, user.APIKeys...)
-		}
-	}
-	if len(flattenedAPIKeys) == 0 {
-		return nil, store.sqlstore.WrapNotFoundErrf(errors.New(errors.TypeNotFound, errors.CodeNotFound, "API key with id: %s does not exist"), types.ErrAPIKeyNotFound, "API key with id: %s does not exist", id)
-	}
-
-	return flattenedAPIKeys[0], nil
+	return apiKey, nil
 }
 
 // GetDomainFromSsoResponse uses relay state received from IdP to fetch
@@ -810,14 +976,252 @@ func (store *store) DeleteDomain(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
-func (store *store) CountByOrgID(ctx context.Context, orgID valuer.UUID) (int64, error) {
-	user := new(types.User)
+// CreateFactorTOTP replaces the pending factor of the user if any, enabled factors have to be deleted first.
+func (store *store) CreateFactorTOTP(ctx context.Context, factor *types.FactorTOTP) error {
+	return store.sqlstore.RunInTxCtx(ctx, nil, func(ctx context.Context) error {
+		_, err := store.
+			sqlstore.
+			BunDBCtx(ctx).
+			NewDelete().
+			Model(new(types.FactorTOTP)).
+			Where("user_id = ?", factor.UserID).
+			Where("enabled = ?", false).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
 
+		_, err = store.
+			sqlstore.
+			BunDBCtx(ctx).
+			NewInsert().
+			Model(factor).
+			Exec(ctx)
+		if err != nil {
+			return store.sqlstore.WrapAlreadyExistsErrf(err, types.ErrTOTPAlreadyExists, "totp with user id: %s already exists", factor.UserID)
+		}
+
+		return nil
+	})
+}
+
+func (store *store) GetFactorTOTPByUserID(ctx context.Context, userID string) (*types.FactorTOTP, error) {
+	factor := new(types.FactorTOTP)
+	err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewSelect().
+		Model(factor).
+		Where("user_id = ?", userID).
+		Scan(ctx)
+	if err != nil {
+		return nil, store.sqlstore.WrapNotFoundErrf(err, types.ErrTOTPNotFound, "totp with user id: %s does not exist", userID)
+	}
+
+	return factor, nil
+}
+
+func (store *store) UpdateFactorTOTP(ctx context.Context, factor *types.FactorTOTP) error {
+	factor.UpdatedAt = time.Now()
+	_, err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewUpdate().
+		Model(factor).
+		WherePK().
+		Exec(ctx)
+	return err
+}
+
+func (store *store) IncrementFactorTOTPAttempts(ctx context.Context, id valuer.UUID) (int, error) {
+	var failedAttempts int
+	err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewUpdate().
+		Model(new(types.FactorTOTP)).
+		Set("failed_attempts = failed_attempts + 1").
+		Set("updated_at = ?", time.Now()).
+		Where("id = ?", id).
+		Returning("failed_attempts").
+		Scan(ctx, &failedAttempts)
+	if err != nil {
+		return 0, store.sqlstore.WrapNotFoundErrf(err, types.ErrTOTPNotFound, "totp with id: %s does not exist", id)
+	}
+
+	return failedAttempts, nil
+}
+
+func (store *store) DeleteFactorTOTP(ctx context.Context, userID string) error {
+	result, err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewDelete().
+		Model(new(types.FactorTOTP)).
+		Where("user_id = ?", userID).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return errors.Newf(errors.TypeNotFound, types.ErrTOTPNotFound, "totp with user id: %s does not exist", userID)
+	}
+
+	return nil
+}
+
+func (store *store) CreateSession(ctx context.Context, session *types.StorableSession) error {
+	_, err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewInsert().
+		Model(session).
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrapf(err, errors.TypeInternal, errors.CodeInternal, "failed to create session")
+	}
+
+	return nil
+}
+
+func (store *store) GetSession(ctx context.Context, id string) (*types.StorableSession, error) {
+	session := new(types.StorableSession)
+	err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewSelect().
+		Model(session).
+		Where("id = ?", id).
+		Scan(ctx)
+	if err != nil {
+		return nil, store.sqlstore.WrapNotFoundErrf(err, types.ErrSessionNotFound, "session with id: %s does not exist", id)
+	}
+
+	return session, nil
+}
+
+func (store *store) ListSessions(ctx context.Context, orgID string, userID string) ([]*types.StorableSession, error) {
+	sessions := []*types.StorableSession{}
+	err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewSelect().
+		Model(&sessions).
+		Where("org_id = ?", orgID).
+		Where("user_id = ?", userID).
+		Where("revoked_at IS NULL").
+		Where("expires_at > ?", time.Now()).
+		Order("updated_at DESC").
+		Scan(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.TypeInternal, errors.CodeInternal, "failed to list sessions")
+	}
+
+	return sessions, nil
+}
+
+// RotateSession stores the session with its new refresh token as long as the token being exchanged is still the
+// current one, concurrent exchanges of the same token are detected as reuse.
+func (store *store) RotateSession(ctx context.Context, session *types.StorableSession, refreshTokenID string) error {
+	result, err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewUpdate().
+		Model(session).
+		Column("refresh_token_id", "client_address", "user_agent", "expires_at", "updated_at").
+		WherePK().
+		Where("refresh_token_id = ?", refreshTokenID).
+		Where("revoked_at IS NULL").
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrapf(err, errors.TypeInternal, errors.CodeInternal, "failed to rotate session")
+	}
+
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return errors.New(errors.TypeUnauthenticated, types.ErrSessionRevoked, "refresh token has already been used")
+	}
+
+	return nil
+}
+
+func (store *store) RevokeSession(ctx context.Context, session *types.StorableSession) error {
+	_, err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewUpdate().
+		Model(session).
+		Column("revoked_at", "revoked_reason", "updated_at").
+		WherePK().
+		Exec(ctx)
+	if err != nil {
+		return errors.Wrapf(err, errors.TypeInternal, errors.CodeInternal, "failed to revoke session")
+	}
+
+	return nil
+}
+
+// RevokeSessions revokes the active sessions of the user but the one with the excluded id if any.
+func (store *store) RevokeSessions(ctx context.Context, userID string, excludedID string, reason types.SessionRevocationReason) error {
+	query := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewUpdate().
+		Model(new(types.StorableSession)).
+		Set("revoked_at = ?", time.Now()).
+		Set("revoked_reason = ?", reason).
+		Set("updated_at = ?", time.Now()).
+		Where("user_id = ?", userID).
+		Where("revoked_at IS NULL")
+
+	if excludedID != "" {
+		query = query.Where("id != ?", excludedID)
+	}
+
+	if _, err := query.Exec(ctx); err != nil {
+		return errors.Wrapf(err, errors.TypeInternal, errors.CodeInternal, "failed to revoke sessions")
+	}
+
+	return nil
+}
+
+func (store *store) CreateMembership(ctx context.Context, membership *types.OrgMembership) error {
+	_, err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewInsert().
+		Model(membership).
+		Exec(ctx)
+	if err != nil {
+		return store.sqlstore.WrapAlreadyExistsErrf(err, types.ErrMembershipAlreadyExists, "user with id: %s is already a member of org: %s", membership.UserID, membership.OrgID)
+	}
+
+	return nil
+}
+
+func (store *store) ListMemberships(ctx context.Context, userID string) ([]*types.OrgMembership, error) {
+	memberships := []*types.OrgMembership{}
+	err := store.
+		sqlstore.
+		BunDBCtx(ctx).
+		NewSelect().
+		Model(&memberships).
+		Where("user_id = ?", userID).
+		Order("created_at ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.TypeInternal, errors.CodeInternal, "failed to list memberships")
+	}
+
+	return memberships, nil
+}
+
+func (store *store) CountByOrgID(ctx context.Context, orgID valuer.UUID) (int64, error) {
 	count, err := store.
 		sqlstore.
 		BunDB().
 		NewSelect().
-		Model(user).
+		Model(new(types.OrgMembership)).
 		Where("org_id = ?", orgID).
 		Count(ctx)
 	if err != nil {
@@ -8,6 +8,7 @@ import (
 	"github.com/SigNoz/signoz/pkg/statsreporter"
 	"github.com/SigNoz/signoz/pkg/types"
 	"github.com/SigNoz/signoz/pkg/types/authtypes"
+	"github.com/SigNoz/signoz/pkg/types/ssotypes"
 	"github.com/SigNoz/signoz/pkg/valuer"
 	"github.com/google/uuid"
 )
@@ -27,18 +28,54 @@ type Module interface {
 	GetUsersByEmail(ctx context.Context, email string) ([]*types.GettableUser, error) // public function
 	GetUserByEmailInOrg(ctx context.Context, orgID string, email string) (*types.GettableUser, error)
 	GetUsersByRoleInOrg(ctx context.Context, orgID string, role types.Role) ([]*types.GettableUser, error)
+	// NewContextWithClaimsOf attaches the claims of the member of the org with the email to the context, the work done
+	// in the background on behalf of a user is allowed what the user is allowed
+	NewContextWithClaimsOf(ctx context.Context, orgID string, email string) (context.Context, error)
 	ListUsers(ctx context.Context, orgID string) ([]*types.GettableUser, error)
 	UpdateUser(ctx context.Context, orgID string, id string, user *types.User) (*types.User, error)
+	// DeleteUser removes the user from the org, users who are not a member of any other org are deleted
 	DeleteUser(ctx context.Context, orgID string, id string) error
 
+	// org membership
+	// AddToOrg makes an existing user a member of another org
+	AddToOrg(ctx context.Context, orgID string, userID string, role types.Role) error
+	ListOrgs(ctx context.Context, userID string) ([]*types.GettableOrgMembership, error)
+	// SwitchOrg ends the current session of the user and starts one in another of their orgs
+	SwitchOrg(ctx context.Context, userID string, orgID string) (*types.User, types.GettableUserJwt, error)
+
 	// login
-	GetAuthenticatedUser(ctx context.Context, orgID, email, password, refreshToken string) (*types.User, error)
+	GetAuthenticatedUser(ctx context.Context, orgID, email, password string) (*types.User, error)
+	// GetJWTForUser starts a new session for the user and issues its tokens
 	GetJWTForUser(ctx context.Context, user *types.User) (types.GettableUserJwt, error)
-	CreateUserForSAMLRequest(ctx context.Context, email string) (*types.User, error)
+	CreateUserForSSORequest(ctx context.Context, domain *types.GettableOrgDomain, identity *ssotypes.SSOIdentity, role types.Role) (*types.User, error)
 	LoginPrecheck(ctx context.Context, orgID, email, sourceUrl string) (*types.GettableLoginPrecheck, error)
 
+	// mfa
+	// PrepareMFAChallenge returns the challenge to complete after the password of the user, nil when the password is enough
+	PrepareMFAChallenge(ctx context.Context, user *types.User) (*types.GettableMFAChallenge, error)
+	// VerifyMFAChallenge verifies the second factor of the challenge and returns the user to issue the tokens for
+	VerifyMFAChallenge(ctx context.Context, challenge *types.PostableMFAChallenge) (*types.User, error)
+	// EnrollTOTPWithChallenge enrolls a user logging in to an org requiring mfa, the enrollment completes with the challenge
+	EnrollTOTPWithChallenge(ctx context.Context, token string) (*types.GettableTOTPEnrollment, error)
+	GetMFAStatus(ctx context.Context, orgID string, userID string) (*types.GettableMFAStatus, error)
+	EnrollTOTP(ctx context.Context, orgID string, userID string) (*types.GettableTOTPEnrollment, error)
+	ConfirmTOTP(ctx context.Context, orgID string, userID string, code string) error
+	DisableTOTP(ctx context.Context, orgID string, userID string, code string) error
+	RegenerateRecoveryCodes(ctx context.Context, orgID string, userID string, code string) ([]string, error)
+	// ResetMFA removes the factor of a user who lost it
+	ResetMFA(ctx context.Context, orgID string, userID string) error
+
+	// session
+	// RotateSession exchanges the refresh token of a session for new tokens, a refresh token can only be exchanged once
+	RotateSession(ctx context.Context, refreshToken string) (*types.User, types.GettableUserJwt, error)
+	ListSessions(ctx context.Context, orgID string, userID string) ([]*types.StorableSession, error)
+	RevokeSession(ctx context.Context, orgID string, userID string, id string) error
+	// RevokeSessions signs the user out of all their sessions
+	RevokeSessions(ctx context.Context, orgID string, userID string) error
+	authtypes.SessionValidator
+
 	// sso
-	PrepareSsoRedirect(ctx context.Context, redirectUri, email string, jwt *authtypes.JWT) (string, error)
+	PrepareSsoRedirect(ctx context.Context, redirectUri string, domain *types.GettableOrgDomain, identity *ssotypes.SSOIdentity, jwt *authtypes.JWT) (string, error)
 	CanUsePassword(ctx context.Context, email string) (bool, error)
 
 	// password
@@ -84,10 +121,27 @@ type Handler interface {
 	ListUsers(http.ResponseWriter, *http.Request)
 	UpdateUser(http.ResponseWriter, *http.Request)
 	DeleteUser(http.ResponseWriter, *http.Request)
+	ListMyOrgs(http.ResponseWriter, *http.Request)
+	SwitchOrg(http.ResponseWriter, *http.Request)
 
 	// Login
 	LoginPrecheck(http.ResponseWriter, *http.Request)
 	Login(http.ResponseWriter, *http.Request)
+	VerifyMFAChallenge(http.ResponseWriter, *http.Request)
+	EnrollTOTPWithChallenge(http.ResponseWriter, *http.Request)
+
+	// MFA
+	GetMFAStatus(http.ResponseWriter, *http.Request)
+	EnrollTOTP(http.ResponseWriter, *http.Request)
+	ConfirmTOTP(http.ResponseWriter, *http.Request)
+	DisableTOTP(http.ResponseWriter, *http.Request)
+	RegenerateRecoveryCodes(http.ResponseWriter, *http.Request)
+	ResetMFA(http.ResponseWriter, *http.Request)
+
+	// Sessions
+	ListMySessions(http.ResponseWriter, *http.Request)
+	RevokeMySession(http.ResponseWriter, *http.Request)
+	RevokeSessions(http.ResponseWriter, *http.Request)
 
 	// Reset Password
 	GetResetPasswordToken(http.ResponseWriter, *http.Request)
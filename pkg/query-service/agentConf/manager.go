@@ -8,11 +8,13 @@ import (
 	"sync"
 	"sync/atomic"
 
+	"github.com/SigNoz/signoz/pkg/modules/audit"
 	"github.com/SigNoz/signoz/pkg/query-service/app/opamp"
 	filterprocessor "github.com/SigNoz/signoz/pkg/query-service/app/opamp/otelconfig/filterprocessor"
 	tsp "github.com/SigNoz/signoz/pkg/query-service/app/opamp/otelconfig/tailsampler"
 	"github.com/SigNoz/signoz/pkg/query-service/model"
 	"github.com/SigNoz/signoz/pkg/sqlstore"
+	"github.com/SigNoz/signoz/pkg/types/audittypes"
 	"github.com/SigNoz/signoz/pkg/types/opamptypes"
 	"github.com/SigNoz/signoz/pkg/valuer"
 	"github.com/google/uuid"
@@ -38,10 +40,18 @@ type Manager struct {
 	agentFeatures         []AgentFeature
 	configSubscribers     map[string]func()
 	configSubscribersLock sync.Mutex
+
+	// lock to serialize updates to staged rollouts of config versions
+	rolloutLock sync.Mutex
+	// versions of the features last deployed successfully to each agent, keyed by org and agent id
+	deployedVersions map[string]map[opamptypes.ElementType]int
+
+	audit audit.Module
 }
 
 type ManagerOptions struct {
 	Store sqlstore.SQLStore
+	Audit audit.Module
 
 	// When acting as opamp.AgentConfigProvider, agent conf recommendations are
 	// applied to the base conf in the order the features have been specified here.
@@ -65,6 +75,8 @@ func Initiate(options *ManagerOptions) (*Manager, error) {
 		Repo:              Repo{options.Store},
 		agentFeatures:     options.AgentFeatures,
 		configSubscribers: map[string]func(){},
+		deployedVersions:  map[string]map[opamptypes.ElementType]int{},
+		audit:             options.Audit,
 	}
 
 	return m, nil
@@ -92,54 +104,34 @@ func (m *Manager) notifyConfigUpdateSubscribers() {
 }
 
 // Implements opamp.AgentConfigProvider
-func (m *Manager) RecommendAgentConfig(orgId valuer.UUID, currentConfYaml []byte) (
+func (m *Manager) RecommendAgentConfig(orgId valuer.UUID, agentId string, agentLabels map[string]string, currentConfYaml []byte) (
 	recommendedConfYaml []byte,
 	// Opaque id of the recommended config, used for reporting deployment status updates
 	configId string,
 	err error,
 ) {
-	recommendation := currentConfYaml
-	settingVersionsUsed := []string{}
-
-	for _, feature := range m.agentFeatures {
-		featureType := opamptypes.NewElementType(string(feature.AgentFeatureType()))
-		latestConfig, apiErr := GetLatestVersion(context.Background(), orgId, featureType)
-		if apiErr != nil && apiErr.Type() != model.ErrorNotFound {
-			return nil, "", errors.Wrap(apiErr.ToError(), "failed to get latest agent config version")
-		}
-
-		updatedConf, serializedSettingsUsed, apiErr := feature.RecommendAgentConfig(orgId, recommendation, latestConfig)
-		if apiErr != nil {
-			return nil, "", errors.Wrap(apiErr.ToError(), fmt.Sprintf(
-				"failed to generate agent config recommendation for %s", featureType,
-			))
-		}
-		recommendation = updatedConf
-
-		// It is possible for a feature to recommend collector config
-		// before any user created config versions exist.
-		//
-		// For example, log pipeline config for installed integrations will
-		// have to be recommended even if the user hasn't created any pipelines yet
-		configVersion := -1
-		if latestConfig != nil {
-			configVersion = latestConfig.Version
-		}
-		configId := fmt.Sprintf("%s:%d", featureType, configVersion)
+	recommendation, featureConfigsUsed, err := m.recommendFeatureConfigs(
+		context.Background(), orgId, agentId, agentLabels, currentConfYaml, nil,
+	)
+	if err != nil {
+		return nil, "", err
+	}
 
+	settingVersionsUsed := []string{}
+	for _, used := range featureConfigsUsed {
+		configId := fmt.Sprintf("%s:%d", used.elementType, used.version)
 		settingVersionsUsed = append(settingVersionsUsed, configId)
 
 		_ = m.updateDeployStatus(
 			context.Background(),
 			orgId,
-			featureType,
-			configVersion,
+			used.elementType,
+			used.version,
 			opamptypes.DeployInitiated.StringValue(),
 			"Deployment has started",
 			configId,
-			serializedSettingsUsed,
+			used.serializedSettings,
 		)
-
 	}
 
 	if len(settingVersionsUsed) > 0 {
@@ -155,6 +147,80 @@ func (m *Manager) RecommendAgentConfig(orgId valuer.UUID, currentConfYaml []byte
 	return recommendation, configId, nil
 }
 
+// settings of an agent feature that went into a config recommendation
+type featureConfigUsed struct {
+	elementType        opamptypes.ElementType
+	version            int
+	serializedSettings string
+}
+
+// recommendFeatureConfigs applies the config recommendations of all agent features
+// to `currentConfYaml`. Features use the config version resolved for the agent
+// unless a version has been pinned for the feature in `pinnedVersions`.
+func (m *Manager) recommendFeatureConfigs(
+	ctx context.Context,
+	orgId valuer.UUID,
+	agentId string,
+	agentLabels map[string]string,
+	currentConfYaml []byte,
+	pinnedVersions map[opamptypes.ElementType]int,
+) ([]byte, []featureConfigUsed, error) {
+	recommendation := currentConfYaml
+	featureConfigsUsed := []featureConfigUsed{}
+
+	for _, feature := range m.agentFeatures {
+		featureType := opamptypes.NewElementType(string(feature.AgentFeatureType()))
+
+		var configVersion *opamptypes.AgentConfigVersion
+		if pinnedVersion, ok := pinnedVersions[featureType]; ok {
+			pinnedConfig, apiErr := m.GetConfigVersion(ctx, orgId, featureType, pinnedVersion)
+			if apiErr != nil {
+				return nil, nil, errors.Wrap(apiErr.ToError(), fmt.Sprintf(
+					"failed to get version %d of %s config", pinnedVersion, featureType,
+				))
+			}
+			configVersion = pinnedConfig
+		} else {
+			latestConfig, apiErr := m.GetLatestVersion(ctx, orgId, featureType)
+			if apiErr != nil && apiErr.Type() != model.ErrorNotFound {
+				return nil, nil, errors.Wrap(apiErr.ToError(), "failed to get latest agent config version")
+			}
+
+			// staged rollouts may hold back the latest version from this agent
+			configVersion, apiErr = m.resolveConfigVersion(ctx, orgId, latestConfig, agentId, agentLabels)
+			if apiErr != nil {
+				return nil, nil, errors.Wrap(apiErr.ToError(), "failed to resolve agent config version for agent")
+			}
+		}
+
+		updatedConf, serializedSettingsUsed, apiErr := feature.RecommendAgentConfig(orgId, recommendation, configVersion)
+		if apiErr != nil {
+			return nil, nil, errors.Wrap(apiErr.ToError(), fmt.Sprintf(
+				"failed to generate agent config recommendation for %s", featureType,
+			))
+		}
+		recommendation = updatedConf
+
+		// It is possible for a feature to recommend collector config
+		// before any user created config versions exist.
+		//
+		// For example, log pipeline config for installed integrations will
+		// have to be recommended even if the user hasn't created any pipelines yet
+		version := -1
+		if configVersion != nil {
+			version = configVersion.Version
+		}
+
+		featureConfigsUsed = append(featureConfigsUsed, featureConfigUsed{
+			elementType:        featureType,
+			version:            version,
+			serializedSettings: serializedSettingsUsed,
+		})
+	}
+
+	return recommendation, featureConfigsUsed, nil
+}
+
 // Implements opamp.AgentConfigProvider
 func (m *Manager) ReportConfigDeploymentStatus(
 	orgId valuer.UUID,
@@ -174,6 +240,8 @@ func (m *Manager) ReportConfigDeploymentStatus(
 			context.Background(), orgId, featureConfId, newStatus, message,
 		)
 	}
+
+	m.recordRolloutResults(orgId, agentId, configId, err)
 }
 
 func GetLatestVersion(
@@ -194,11 +262,21 @@ func GetConfigHistory(
 	return m.GetConfigHistory(ctx, orgId, typ, limit)
 }
 
-// StartNewVersion launches a new config version for given set of elements
+// StartNewVersion launches a new config version for given set of elements.
+// If a rollout policy is specified, the new version is deployed in stages
+// instead of being recommended to all the agents at once.
 func StartNewVersion(
-	ctx context.Context, orgId valuer.UUID, userId valuer.UUID, eleType opamptypes.ElementType, elementIds []string,
+	ctx context.Context, orgId valuer.UUID, userId valuer.UUID, eleType opamptypes.ElementType, elementIds []string, rolloutPolicy *opamptypes.PostableRolloutPolicy,
 ) (*opamptypes.AgentConfigVersion, *model.ApiError) {
 
+	if rolloutPolicy != nil {
+		if err := m.validateRolloutPolicy(ctx, orgId, rolloutPolicy); err != nil {
+			return nil, err
+		}
+	}
+
+	before := m.getAuditableConfigVersion(ctx, orgId, eleType)
+
 	// create a new version
 	cfg := opamptypes.NewAgentConfigVersion(orgId, userId, eleType)
 
@@ -208,11 +286,48 @@ func StartNewVersion(
 		return nil, err
 	}
 
+	if rolloutPolicy != nil {
+		rollout := opamptypes.NewAgentConfigRollout(orgId, eleType, cfg.Version, cfg.Version-1, *rolloutPolicy)
+		if err := m.insertRollout(ctx, rollout); err != nil {
+			return nil, err
+		}
+	}
+
 	m.notifyConfigUpdateSubscribers()
 
+	if m.audit != nil {
+		after := &auditableConfigVersion{Version: cfg.Version, ElementIDs: elementIds}
+		m.audit.Record(ctx, orgId, audittypes.ActionUpdate, audittypes.NewResource(audittypes.ResourceTypeAgentConfig, eleType.StringValue()), before, after)
+	}
+
 	return cfg, nil
 }
 
+// auditableConfigVersion is the state of an element type recorded in the audit log, the elements themselves are
+// audited by their own features.
+type auditableConfigVersion struct {
+	Version    int      `json:"version"`
+	ElementIDs []string `json:"elementIds"`
+}
+
+func (m *Manager) getAuditableConfigVersion(ctx context.Context, orgId valuer.UUID, eleType opamptypes.ElementType) *auditableConfigVersion {
+	if m.audit == nil {
+		return nil
+	}
+
+	latest, err := m.GetLatestVersion(ctx, orgId, eleType)
+	if err != nil {
+		return nil
+	}
+
+	elementIds, err := m.getElementIdsForVersion(ctx, latest.ID)
+	if err != nil {
+		return nil
+	}
+
+	return &auditableConfigVersion{Version: latest.Version, ElementIDs: elementIds}
+}
+
 func NotifyConfigUpdate(ctx context.Context) {
 	m.notifyConfigUpdateSubscribers()
 }
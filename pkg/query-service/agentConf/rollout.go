@@ -0,0 +1,471 @@
+package agentConf
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/SigNoz/signoz/pkg/query-service/model"
+	"github.com/SigNoz/signoz/pkg/types/opamptypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// max number of rollouts walked back while resolving the config version for an agent
+const maxRolloutsToResolve = 100
+
+func (r *Repo) insertAgentGroup(ctx context.Context, group *opamptypes.AgentGroup) *model.ApiError {
+	_, err := r.store.BunDB().NewInsert().Model(group).Exec(ctx)
+	if err != nil {
+		return model.InternalError(errors.Wrap(err, "failed to insert agent group"))
+	}
+
+	return nil
+}
+
+func (r *Repo) getAgentGroup(ctx context.Context, orgId valuer.UUID, id string) (*opamptypes.AgentGroup, *model.ApiError) {
+	group := new(opamptypes.AgentGroup)
+	err := r.store.BunDB().NewSelect().
+		Model(group).
+		Where("org_id = ?", orgId).
+		Where("id = ?", id).
+		Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, model.NotFoundError(fmt.Errorf("agent group %s does not exist", id))
+		}
+		return nil, model.InternalError(err)
+	}
+
+	return group, nil
+}
+
+func (r *Repo) listAgentGroups(ctx context.Context, orgId valuer.UUID) ([]opamptypes.AgentGroup, *model.ApiError) {
+	groups := []opamptypes.AgentGroup{}
+	err := r.store.BunDB().NewSelect().
+		Model(&groups).
+		Where("org_id = ?", orgId).
+		OrderExpr("name ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, model.InternalError(err)
+	}
+
+	return groups, nil
+}
+
+func (r *Repo) deleteAgentGroup(ctx context.Context, orgId valuer.UUID, id string) *model.ApiError {
+	_, err := r.store.BunDB().NewDelete().
+		Model(new(opamptypes.AgentGroup)).
+		Where("org_id = ?", orgId).
+		Where("id = ?", id).
+		Exec(ctx)
+	if err != nil {
+		return model.InternalError(err)
+	}
+
+	return nil
+}
+
+func (r *Repo) insertRollout(ctx context.Context, rollout *opamptypes.AgentConfigRollout) *model.ApiError {
+	_, err := r.store.BunDB().NewInsert().Model(rollout).Exec(ctx)
+	if err != nil {
+		return model.InternalError(errors.Wrap(err, "failed to insert agent config rollout"))
+	}
+
+	return nil
+}
+
+func (r *Repo) updateRollout(ctx context.Context, rollout *opamptypes.AgentConfigRollout) *model.ApiError {
+	_, err := r.store.BunDB().NewUpdate().
+		Model(rollout).
+		Column("stage", "succeeded", "failed", "message", "updated_at").
+		Where("id = ?", rollout.ID).
+		Where("org_id = ?", rollout.OrgID).
+		Exec(ctx)
+	if err != nil {
+		return model.InternalError(errors.Wrap(err, "failed to update agent config rollout"))
+	}
+
+	return nil
+}
+
+func (r *Repo) getRollout(ctx context.Context, orgId valuer.UUID, id string) (*opamptypes.AgentConfigRollout, *model.ApiError) {
+	rollout := new(opamptypes.AgentConfigRollout)
+	err := r.store.BunDB().NewSelect().
+		Model(rollout).
+		Where("org_id = ?", orgId).
+		Where("id = ?", id).
+		Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, model.NotFoundError(fmt.Errorf("rollout %s does not exist", id))
+		}
+		return nil, model.InternalError(err)
+	}
+
+	return rollout, nil
+}
+
+func (r *Repo) getRolloutForVersion(
+	ctx context.Context, orgId valuer.UUID, typ opamptypes.ElementType, version int,
+) (*opamptypes.AgentConfigRollout, *model.ApiError) {
+	rollout := new(opamptypes.AgentConfigRollout)
+	err := r.store.BunDB().NewSelect().
+		Model(rollout).
+		Where("org_id = ?", orgId).
+		Where("element_type = ?", typ).
+		Where("version = ?", version).
+		Scan(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, model.NotFoundError(err)
+		}
+		return nil, model.InternalError(err)
+	}
+
+	return rollout, nil
+}
+
+// listRollouts returns the latest rollouts for versions upto maxVersion, newest first.
+func (r *Repo) listRollouts(
+	ctx context.Context, orgId valuer.UUID, typ opamptypes.ElementType, maxVersion int, limit int,
+) ([]opamptypes.AgentConfigRollout, *model.ApiError) {
+	rollouts := []opamptypes.AgentConfigRollout{}
+	err := r.store.BunDB().NewSelect().
+		Model(&rollouts).
+		Where("org_id = ?", orgId).
+		Where("element_type = ?", typ).
+		Where("version <= ?", maxVersion).
+		OrderExpr("version DESC").
+		Limit(limit).
+		Scan(ctx)
+	if err != nil {
+		return nil, model.InternalError(err)
+	}
+
+	return rollouts, nil
+}
+
+func (r *Repo) listActiveRolloutsForGroup(
+	ctx context.Context, orgId valuer.UUID, groupId string,
+) ([]opamptypes.AgentConfigRollout, *model.ApiError) {
+	rollouts := []opamptypes.AgentConfigRollout{}
+	err := r.store.BunDB().NewSelect().
+		Model(&rollouts).
+		Where("org_id = ?", orgId).
+		Where("group_id = ?", groupId).
+		Where("stage != ?", opamptypes.RolloutStageRolledBack).
+		Scan(ctx)
+	if err != nil {
+		return nil, model.InternalError(err)
+	}
+
+	return rollouts, nil
+}
+
+// resolveConfigVersion picks the config version to be recommended to an agent.
+//
+// Without staged rollouts, the latest version is recommended to every agent.
+// A version with a rollout is only recommended to agents targeted by the rollout
+// in its current stage, other agents keep getting the version that was current
+// before it - which is resolved the same way.
+func (m *Manager) resolveConfigVersion(
+	ctx context.Context,
+	orgId valuer.UUID,
+	latest *opamptypes.AgentConfigVersion,
+	agentId string,
+	agentLabels map[string]string,
+) (*opamptypes.AgentConfigVersion, *model.ApiError) {
+	if latest == nil {
+		return nil, nil
+	}
+
+	rollouts, apiErr := m.listRollouts(ctx, orgId, latest.ElementType, latest.Version, maxRolloutsToResolve)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	rolloutsByVersion := map[int]opamptypes.AgentConfigRollout{}
+	for _, rollout := range rollouts {
+		rolloutsByVersion[rollout.Version] = rollout
+	}
+
+	groups := map[string]*opamptypes.AgentGroup{}
+	version := latest.Version
+	for version > 0 {
+		rollout, ok := rolloutsByVersion[version]
+		if !ok {
+			break
+		}
+
+		targeted, apiErr := m.isTargetedByRollout(ctx, &rollout, groups, agentId, agentLabels)
+		if apiErr != nil {
+			return nil, apiErr
+		}
+		if targeted {
+			break
+		}
+
+		version = rollout.PreviousVersion
+	}
+
+	if version <= 0 {
+		// none of the versions have been rolled out to this agent yet.
+		return nil, nil
+	}
+
+	if version == latest.Version {
+		return latest, nil
+	}
+
+	return m.GetConfigVersion(ctx, orgId, latest.ElementType, version)
+}
+
+func (m *Manager) isTargetedByRollout(
+	ctx context.Context,
+	rollout *opamptypes.AgentConfigRollout,
+	groups map[string]*opamptypes.AgentGroup,
+	agentId string,
+	agentLabels map[string]string,
+) (bool, *model.ApiError) {
+	if !rollout.IsActive() {
+		return false, nil
+	}
+
+	if rollout.GroupID != "" {
+		group, ok := groups[rollout.GroupID]
+		if !ok {
+			var apiErr *model.ApiError
+			group, apiErr = m.getAgentGroup(ctx, rollout.OrgID, rollout.GroupID)
+			if apiErr != nil && apiErr.Type() != model.ErrorNotFound {
+				return false, apiErr
+			}
+			groups[rollout.GroupID] = group
+		}
+
+		if group == nil || !group.Matches(agentLabels) {
+			return false, nil
+		}
+	}
+
+	if rollout.Stage == opamptypes.RolloutStageCanary && !rollout.IsCanary(agentId) {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// recordRolloutResults counts the deployment outcome reported by an agent towards the rollouts
+// of the config versions it deployed. The config versions of all the features are deployed at
+// once, the outcome only counts towards the features whose version changed since the last
+// successful deployment to the agent so that a failing feature doesn't roll back the others.
+func (m *Manager) recordRolloutResults(orgId valuer.UUID, agentId string, configId string, err error) {
+	versions := parseConfigId(orgId, configId)
+
+	m.rolloutLock.Lock()
+	defer m.rolloutLock.Unlock()
+
+	key := orgId.StringValue() + agentId
+	deployed, ok := m.deployedVersions[key]
+	for elementType, version := range versions {
+		if ok && deployed[elementType] == version {
+			continue
+		}
+
+		m.recordRolloutResult(orgId, agentId, elementType, version, err)
+	}
+
+	if err == nil {
+		m.deployedVersions[key] = versions
+	}
+}
+
+// recordRolloutResult counts the deployment outcome reported by an agent towards
+// the rollout of the config version, promoting or rolling back the rollout if needed.
+func (m *Manager) recordRolloutResult(orgId valuer.UUID, agentId string, elementType opamptypes.ElementType, version int, err error) {
+	ctx := context.Background()
+	rollout, apiErr := m.getRolloutForVersion(ctx, orgId, elementType, version)
+	if apiErr != nil {
+		if apiErr.Type() != model.ErrorNotFound {
+			zap.L().Error("failed to get rollout for config version", zap.String("elementType", elementType.StringValue()), zap.Int("version", version), zap.Error(apiErr))
+		}
+		return
+	}
+
+	stageChanged := rollout.RecordResult(agentId, err)
+	if apiErr := m.updateRollout(ctx, rollout); apiErr != nil {
+		zap.L().Error("failed to record deployment result for rollout", zap.String("rolloutId", rollout.ID.StringValue()), zap.Error(apiErr))
+		return
+	}
+
+	if stageChanged {
+		zap.L().Info(
+			"agent config rollout moved to a new stage",
+			zap.String("rolloutId", rollout.ID.StringValue()),
+			zap.String("stage", rollout.Stage.StringValue()),
+			zap.String("message", rollout.Message),
+		)
+		// status reports are received while the reporting agent is locked, recommend
+		// configs to agents asynchronously to avoid deadlocking on the same agent.
+		go m.notifyConfigUpdateSubscribers()
+	}
+}
+
+// parseConfigId parses the versions of the features deployed with a config id reported by an
+// agent, the org id followed by the `<element_type>:<version>` ids of the features joined with
+// commas generated by RecommendAgentConfig.
+func parseConfigId(orgId valuer.UUID, configId string) map[opamptypes.ElementType]int {
+	versions := map[opamptypes.ElementType]int{}
+	for _, featureConfId := range strings.Split(strings.TrimPrefix(configId, orgId.StringValue()), ",") {
+		elementType, version, ok := parseFeatureConfigId(featureConfId)
+		if ok {
+			versions[elementType] = version
+		}
+	}
+
+	return versions
+}
+
+// parses config ids of the form `<element_type>:<version>` generated by RecommendAgentConfig
+func parseFeatureConfigId(featureConfId string) (opamptypes.ElementType, int, bool) {
+	idx := strings.LastIndex(featureConfId, ":")
+	if idx < 0 {
+		return opamptypes.ElementType{}, 0, false
+	}
+
+	elementType := opamptypes.NewElementType(featureConfId[:idx])
+	if elementType.StringValue() == "" {
+		return opamptypes.ElementType{}, 0, false
+	}
+
+	version, err := strconv.Atoi(featureConfId[idx+1:])
+	if err != nil || version <= 0 {
+		return opamptypes.ElementType{}, 0, false
+	}
+
+	return elementType, version, true
+}
+
+// validateRolloutPolicy ensures a rollout policy can be applied before a new config version is created.
+func (m *Manager) validateRolloutPolicy(
+	ctx context.Context, orgId valuer.UUID, policy *opamptypes.PostableRolloutPolicy,
+) *model.ApiError {
+	if err := policy.Validate(); err != nil {
+		return model.BadRequest(err)
+	}
+
+	if policy.GroupID != "" {
+		if _, apiErr := m.getAgentGroup(ctx, orgId, policy.GroupID); apiErr != nil {
+			return apiErr
+		}
+	}
+
+	return nil
+}
+
+func ListAgentGroups(ctx context.Context, orgId valuer.UUID) ([]opamptypes.AgentGroup, *model.ApiError) {
+	return m.listAgentGroups(ctx, orgId)
+}
+
+func CreateAgentGroup(
+	ctx context.Context, orgId valuer.UUID, userId valuer.UUID, postable opamptypes.PostableAgentGroup,
+) (*opamptypes.AgentGroup, *model.ApiError) {
+	group, err := opamptypes.NewAgentGroup(orgId, userId, postable)
+	if err != nil {
+		return nil, model.BadRequest(err)
+	}
+
+	groups, apiErr := m.listAgentGroups(ctx, orgId)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	for _, existing := range groups {
+		if existing.Name == group.Name {
+			return nil, &model.ApiError{Typ: model.ErrorConflict, Err: fmt.Errorf("agent group %s already exists", group.Name)}
+		}
+	}
+
+	if apiErr := m.insertAgentGroup(ctx, group); apiErr != nil {
+		return nil, apiErr
+	}
+
+	return group, nil
+}
+
+// DeleteAgentGroup deletes an agent group unless the latest config version of a
+// feature is still being rolled out to it.
+func DeleteAgentGroup(ctx context.Context, orgId valuer.UUID, id string) *model.ApiError {
+	if _, apiErr := m.getAgentGroup(ctx, orgId, id); apiErr != nil {
+		return apiErr
+	}
+
+	rollouts, apiErr := m.listActiveRolloutsForGroup(ctx, orgId, id)
+	if apiErr != nil {
+		return apiErr
+	}
+
+	for _, rollout := range rollouts {
+		latest, apiErr := m.GetLatestVersion(ctx, orgId, rollout.ElementType)
+		if apiErr != nil && apiErr.Type() != model.ErrorNotFound {
+			return apiErr
+		}
+		if latest != nil && latest.Version == rollout.Version {
+			return &model.ApiError{Typ: model.ErrorConflict, Err: fmt.Errorf(
+				"agent group is targeted by the latest %s config version %d", rollout.ElementType.StringValue(), rollout.Version,
+			)}
+		}
+	}
+
+	return m.deleteAgentGroup(ctx, orgId, id)
+}
+
+func ListRollouts(
+	ctx context.Context, orgId valuer.UUID, typ opamptypes.ElementType, limit int,
+) ([]opamptypes.AgentConfigRollout, *model.ApiError) {
+	latest, apiErr := m.GetLatestVersion(ctx, orgId, typ)
+	if apiErr != nil {
+		if apiErr.Type() == model.ErrorNotFound {
+			return []opamptypes.AgentConfigRollout{}, nil
+		}
+		return nil, apiErr
+	}
+
+	return m.listRollouts(ctx, orgId, typ, latest.Version, limit)
+}
+
+// PromoteRollout recommends a config version in canary stage to all the targeted agents.
+func PromoteRollout(ctx context.Context, orgId valuer.UUID, id string) (*opamptypes.AgentConfigRollout, *model.ApiError) {
+	return m.transitionRollout(ctx, orgId, id, (*opamptypes.AgentConfigRollout).Promote)
+}
+
+// RollBackRollout moves the agents targeted by a rollout back to the previous config version.
+func RollBackRollout(ctx context.Context, orgId valuer.UUID, id string) (*opamptypes.AgentConfigRollout, *model.ApiError) {
+	return m.transitionRollout(ctx, orgId, id, (*opamptypes.AgentConfigRollout).RollBack)
+}
+
+func (m *Manager) transitionRollout(
+	ctx context.Context, orgId valuer.UUID, id string, transition func(*opamptypes.AgentConfigRollout) error,
+) (*opamptypes.AgentConfigRollout, *model.ApiError) {
+	m.rolloutLock.Lock()
+	defer m.rolloutLock.Unlock()
+
+	rollout, apiErr := m.getRollout(ctx, orgId, id)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	if err := transition(rollout); err != nil {
+		return nil, model.BadRequest(err)
+	}
+
+	if apiErr := m.updateRollout(ctx, rollout); apiErr != nil {
+		return nil, apiErr
+	}
+
+	m.notifyConfigUpdateSubscribers()
+	return rollout, nil
+}
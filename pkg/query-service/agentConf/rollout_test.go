@@ -0,0 +1,105 @@
+package agentConf
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/SigNoz/signoz/pkg/query-service/utils"
+	"github.com/SigNoz/signoz/pkg/types/opamptypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveConfigVersion(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	sqlStore := utils.NewQueryServiceDBForTests(t)
+	require.NoError(utils.CreateTestOrg(t, sqlStore))
+	orgId, err := utils.GetTestOrgId(sqlStore)
+	require.NoError(err)
+
+	m := &Manager{Repo: Repo{sqlStore}}
+	userId := valuer.GenerateUUID()
+	typ := opamptypes.ElementTypeLogPipelines
+
+	versions := []*opamptypes.AgentConfigVersion{}
+	for v := 1; v <= 2; v++ {
+		version := opamptypes.NewAgentConfigVersion(orgId, userId, typ)
+		version.IncrementVersion(v - 1)
+		_, err := sqlStore.BunDB().NewInsert().Model(version).Exec(ctx)
+		require.NoError(err)
+		versions = append(versions, version)
+	}
+
+	group, err := opamptypes.NewAgentGroup(orgId, userId, opamptypes.PostableAgentGroup{Name: "prod", Selector: map[string]string{"env": "prod"}})
+	require.NoError(err)
+	require.Nil(m.insertAgentGroup(ctx, group))
+
+	prod := map[string]string{"env": "prod"}
+	dev := map[string]string{"env": "dev"}
+
+	resolve := func(latest *opamptypes.AgentConfigVersion, labels map[string]string) int {
+		version, apiErr := m.resolveConfigVersion(ctx, orgId, latest, "agent", labels)
+		require.Nil(apiErr)
+		if version == nil {
+			return 0
+		}
+		return version.Version
+	}
+
+	// without rollouts the latest version is recommended to every agent
+	assert.Equal(t, 2, resolve(versions[1], dev))
+	assert.Equal(t, 0, resolve(nil, dev))
+
+	// a version rolled out to a group is only recommended to the agents of the group
+	rollout := opamptypes.NewAgentConfigRollout(orgId, typ, 2, 1, opamptypes.PostableRolloutPolicy{GroupID: group.ID.StringValue()})
+	require.Nil(m.insertRollout(ctx, rollout))
+	assert.Equal(t, 2, resolve(versions[1], prod))
+	assert.Equal(t, 1, resolve(versions[1], dev))
+
+	// agents are back on the previous version once the rollout is rolled back
+	require.NoError(rollout.RollBack())
+	require.Nil(m.updateRollout(ctx, rollout))
+	assert.Equal(t, 1, resolve(versions[1], prod))
+
+	// none of the versions are recommended when the first one isn't rolled out to the agent
+	first := opamptypes.NewAgentConfigRollout(orgId, typ, 1, 0, opamptypes.PostableRolloutPolicy{GroupID: group.ID.StringValue()})
+	require.Nil(m.insertRollout(ctx, first))
+	assert.Equal(t, 0, resolve(versions[1], dev))
+	assert.Equal(t, 1, resolve(versions[1], prod))
+}
+
+func TestRecordRolloutResults(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	sqlStore := utils.NewQueryServiceDBForTests(t)
+	require.NoError(utils.CreateTestOrg(t, sqlStore))
+	orgId, err := utils.GetTestOrgId(sqlStore)
+	require.NoError(err)
+
+	m := &Manager{Repo: Repo{sqlStore}, configSubscribers: map[string]func(){}, deployedVersions: map[string]map[opamptypes.ElementType]int{}}
+
+	pipelines := opamptypes.NewAgentConfigRollout(orgId, opamptypes.ElementTypeLogPipelines, 2, 1, opamptypes.PostableRolloutPolicy{CanaryPercent: 50, MinCanarySuccesses: 5})
+	require.Nil(m.insertRollout(ctx, pipelines))
+	metrics := opamptypes.NewAgentConfigRollout(orgId, opamptypes.ElementTypeDerivedMetrics, 1, 0, opamptypes.PostableRolloutPolicy{CanaryPercent: 50, MinCanarySuccesses: 5})
+	require.Nil(m.insertRollout(ctx, metrics))
+
+	m.ReportConfigDeploymentStatus(orgId, "agent", orgId.StringValue()+"log_pipelines:1,derived_metrics:1", nil)
+
+	// the failure of a deployment only counts against the features whose version changed
+	m.ReportConfigDeploymentStatus(orgId, "agent", orgId.StringValue()+"log_pipelines:2,derived_metrics:1", errors.New("invalid pipeline"))
+
+	rollout, apiErr := m.getRolloutForVersion(ctx, orgId, opamptypes.ElementTypeLogPipelines, 2)
+	require.Nil(apiErr)
+	assert.Equal(t, opamptypes.RolloutStageRolledBack, rollout.Stage)
+
+	rollout, apiErr = m.getRolloutForVersion(ctx, orgId, opamptypes.ElementTypeDerivedMetrics, 1)
+	require.Nil(apiErr)
+	assert.Equal(t, opamptypes.RolloutStageCanary, rollout.Stage)
+	assert.Equal(t, 1, rollout.Succeeded)
+	assert.Zero(t, rollout.Failed)
+}
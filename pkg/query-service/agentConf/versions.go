@@ -0,0 +1,123 @@
+package agentConf
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/SigNoz/signoz/pkg/query-service/model"
+	"github.com/SigNoz/signoz/pkg/types/opamptypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+	"github.com/pkg/errors"
+)
+
+func (r *Repo) getElementIdsForVersion(ctx context.Context, versionId valuer.UUID) ([]string, *model.ApiError) {
+	elementIds := []string{}
+	err := r.store.BunDB().NewSelect().
+		Model(new(opamptypes.AgentConfigElement)).
+		Column("element_id").
+		Where("version_id = ?", versionId).
+		Scan(ctx, &elementIds)
+	if err != nil {
+		return nil, model.InternalError(errors.Wrap(err, "failed to get elements of config version"))
+	}
+
+	return elementIds, nil
+}
+
+func (r *Repo) updateConfig(
+	ctx context.Context, orgId valuer.UUID, typ opamptypes.ElementType, version int, config string,
+) *model.ApiError {
+	_, err := r.store.BunDB().NewUpdate().
+		Model(new(opamptypes.AgentConfigVersion)).
+		Set("config = ?", config).
+		Where("version = ?", version).
+		Where("element_type = ?", typ).
+		Where("org_id = ?", orgId).
+		Exec(ctx)
+	if err != nil {
+		return model.InternalError(errors.Wrap(err, "failed to update config of config version"))
+	}
+
+	return nil
+}
+
+// DiffConfigVersions compares the settings deployed to agents for two versions of a feature.
+func DiffConfigVersions(
+	ctx context.Context, orgId valuer.UUID, typ opamptypes.ElementType, fromVersion int, toVersion int,
+) (*opamptypes.ConfigVersionDiff, *model.ApiError) {
+	from, apiErr := m.GetConfigVersion(ctx, orgId, typ, fromVersion)
+	if apiErr != nil {
+		return nil, model.WrapApiError(apiErr, fmt.Sprintf("failed to get version %d", fromVersion))
+	}
+
+	to, apiErr := m.GetConfigVersion(ctx, orgId, typ, toVersion)
+	if apiErr != nil {
+		return nil, model.WrapApiError(apiErr, fmt.Sprintf("failed to get version %d", toVersion))
+	}
+
+	settings, err := opamptypes.DiffSettings(from.Config, to.Config)
+	if err != nil {
+		return nil, model.InternalError(err)
+	}
+
+	return &opamptypes.ConfigVersionDiff{
+		ElementType: typ,
+		FromVersion: fromVersion,
+		ToVersion:   toVersion,
+		Settings:    settings,
+	}, nil
+}
+
+// RollbackToVersion creates a new config version with the same elements as an older version
+// and deploys it. The version history is preserved, the rollback being a version of its own.
+func RollbackToVersion(
+	ctx context.Context, orgId valuer.UUID, userId valuer.UUID, typ opamptypes.ElementType, version int,
+) (*opamptypes.AgentConfigVersion, *model.ApiError) {
+	configVersion, apiErr := m.GetConfigVersion(ctx, orgId, typ, version)
+	if apiErr != nil {
+		return nil, model.WrapApiError(apiErr, fmt.Sprintf("failed to get version %d to roll back to", version))
+	}
+
+	elementIds, apiErr := m.getElementIdsForVersion(ctx, configVersion.ID)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	newVersion, apiErr := StartNewVersion(ctx, orgId, userId, typ, elementIds, nil)
+	if apiErr != nil {
+		return nil, model.WrapApiError(apiErr, "failed to start a new version for the rollback")
+	}
+
+	// log pipelines are recommended to agents through the AgentFeature implementation,
+	// processor configs of ingestion rules have to be pushed to the agents explicitly.
+	if typ == opamptypes.ElementTypeSamplingRules || typ == opamptypes.ElementTypeDropRules {
+		if apiErr := m.updateConfig(ctx, orgId, typ, newVersion.Version, configVersion.Config); apiErr != nil {
+			return nil, apiErr
+		}
+
+		if apiErr := Redeploy(ctx, orgId, typ, newVersion.Version); apiErr != nil {
+			return nil, model.WrapApiError(apiErr, "failed to deploy the rolled back version")
+		}
+	}
+
+	return m.GetConfigVersion(ctx, orgId, typ, newVersion.Version)
+}
+
+// DryRunAgentConfig renders the collector config that would be recommended to an agent
+// without sending it to the agent or recording a deployment. Versions of features can be
+// pinned in `pinnedVersions` to preview a specific version instead of the one resolved for the agent.
+func DryRunAgentConfig(
+	ctx context.Context,
+	orgId valuer.UUID,
+	agentId string,
+	agentLabels map[string]string,
+	currentConfYaml []byte,
+	pinnedVersions map[opamptypes.ElementType]int,
+) ([]byte, *model.ApiError) {
+	recommendation, _, err := m.recommendFeatureConfigs(ctx, orgId, agentId, agentLabels, currentConfYaml, pinnedVersions)
+	if err != nil {
+		return nil, model.BadRequest(err)
+	}
+
+	return recommendation, nil
+}
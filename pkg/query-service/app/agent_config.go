@@ -0,0 +1,301 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/SigNoz/signoz/pkg/http/middleware"
+	"github.com/SigNoz/signoz/pkg/http/render"
+	"github.com/SigNoz/signoz/pkg/query-service/agentConf"
+	opAmpModel "github.com/SigNoz/signoz/pkg/query-service/app/opamp/model"
+	"github.com/SigNoz/signoz/pkg/query-service/model"
+	"github.com/SigNoz/signoz/pkg/types/authtypes"
+	"github.com/SigNoz/signoz/pkg/types/opamptypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+	"github.com/gorilla/mux"
+)
+
+// RegisterAgentConfigRoutes adds routes for managing agent groups, staged config rollouts and config versions
+func (aH *APIHandler) RegisterAgentConfigRoutes(router *mux.Router, am *middleware.AuthZ) {
+	subRouter := router.PathPrefix("/api/v1/agents").Subrouter()
+	subRouter.HandleFunc("/groups", am.PermissionAccess(authtypes.PermissionPipelinesRead, aH.listAgentGroups)).Methods(http.MethodGet)
+	subRouter.HandleFunc("/groups", am.PermissionAccess(authtypes.PermissionAgentsManage, aH.createAgentGroup)).Methods(http.MethodPost)
+	subRouter.HandleFunc("/groups/{id}", am.PermissionAccess(authtypes.PermissionAgentsManage, aH.deleteAgentGroup)).Methods(http.MethodDelete)
+
+	subRouter.HandleFunc("/rollouts", am.PermissionAccess(authtypes.PermissionPipelinesRead, aH.listAgentConfigRollouts)).Methods(http.MethodGet)
+	subRouter.HandleFunc("/rollouts/{id}/promote", am.PermissionAccess(authtypes.PermissionPipelinesDeploy, aH.promoteAgentConfigRollout)).Methods(http.MethodPost)
+	subRouter.HandleFunc("/rollouts/{id}/rollback", am.PermissionAccess(authtypes.PermissionPipelinesDeploy, aH.rollBackAgentConfigRollout)).Methods(http.MethodPost)
+
+	subRouter.HandleFunc("/configs/{elementType}/diff", am.PermissionAccess(authtypes.PermissionPipelinesRead, aH.diffAgentConfigVersions)).Methods(http.MethodGet)
+	subRouter.HandleFunc("/configs/{elementType}/versions/{version}/rollback", am.PermissionAccess(authtypes.PermissionPipelinesDeploy, aH.rollbackAgentConfigVersion)).Methods(http.MethodPost)
+	subRouter.HandleFunc("/{agentId}/config/dry_run", am.PermissionAccess(authtypes.PermissionPipelinesDeploy, aH.dryRunAgentConfig)).Methods(http.MethodPost)
+}
+
+func (aH *APIHandler) listAgentGroups(w http.ResponseWriter, r *http.Request) {
+	claims, errv2 := authtypes.ClaimsFromContext(r.Context())
+	if errv2 != nil {
+		render.Error(w, errv2)
+		return
+	}
+
+	groups, apiErr := agentConf.ListAgentGroups(r.Context(), valuer.MustNewUUID(claims.OrgID))
+	if apiErr != nil {
+		RespondError(w, apiErr, nil)
+		return
+	}
+
+	aH.Respond(w, groups)
+}
+
+func (aH *APIHandler) createAgentGroup(w http.ResponseWriter, r *http.Request) {
+	claims, errv2 := authtypes.ClaimsFromContext(r.Context())
+	if errv2 != nil {
+		render.Error(w, errv2)
+		return
+	}
+
+	userID, errv2 := valuer.NewUUID(claims.UserID)
+	if errv2 != nil {
+		render.Error(w, errv2)
+		return
+	}
+
+	var req opamptypes.PostableAgentGroup
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondError(w, model.BadRequest(err), nil)
+		return
+	}
+
+	group, apiErr := agentConf.CreateAgentGroup(r.Context(), valuer.MustNewUUID(claims.OrgID), userID, req)
+	if apiErr != nil {
+		RespondError(w, apiErr, nil)
+		return
+	}
+
+	aH.Respond(w, group)
+}
+
+func (aH *APIHandler) deleteAgentGroup(w http.ResponseWriter, r *http.Request) {
+	claims, errv2 := authtypes.ClaimsFromContext(r.Context())
+	if errv2 != nil {
+		render.Error(w, errv2)
+		return
+	}
+
+	apiErr := agentConf.DeleteAgentGroup(r.Context(), valuer.MustNewUUID(claims.OrgID), mux.Vars(r)["id"])
+	if apiErr != nil {
+		RespondError(w, apiErr, nil)
+		return
+	}
+
+	aH.Respond(w, nil)
+}
+
+func (aH *APIHandler) listAgentConfigRollouts(w http.ResponseWriter, r *http.Request) {
+	claims, errv2 := authtypes.ClaimsFromContext(r.Context())
+	if errv2 != nil {
+		render.Error(w, errv2)
+		return
+	}
+
+	elementType := opamptypes.NewElementType(r.URL.Query().Get("elementType"))
+	if elementType.StringValue() == "" {
+		RespondError(w, model.BadRequest(fmt.Errorf("invalid elementType: %s", r.URL.Query().Get("elementType"))), nil)
+		return
+	}
+
+	limit := 10
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed <= 0 {
+			RespondError(w, model.BadRequest(fmt.Errorf("invalid limit: %s", limitStr)), nil)
+			return
+		}
+		limit = parsed
+	}
+
+	rollouts, apiErr := agentConf.ListRollouts(r.Context(), valuer.MustNewUUID(claims.OrgID), elementType, limit)
+	if apiErr != nil {
+		RespondError(w, apiErr, nil)
+		return
+	}
+
+	aH.Respond(w, rollouts)
+}
+
+func (aH *APIHandler) promoteAgentConfigRollout(w http.ResponseWriter, r *http.Request) {
+	claims, errv2 := authtypes.ClaimsFromContext(r.Context())
+	if errv2 != nil {
+		render.Error(w, errv2)
+		return
+	}
+
+	rollout, apiErr := agentConf.PromoteRollout(r.Context(), valuer.MustNewUUID(claims.OrgID), mux.Vars(r)["id"])
+	if apiErr != nil {
+		RespondError(w, apiErr, nil)
+		return
+	}
+
+	aH.Respond(w, rollout)
+}
+
+func (aH *APIHandler) rollBackAgentConfigRollout(w http.ResponseWriter, r *http.Request) {
+	claims, errv2 := authtypes.ClaimsFromContext(r.Context())
+	if errv2 != nil {
+		render.Error(w, errv2)
+		return
+	}
+
+	rollout, apiErr := agentConf.RollBackRollout(r.Context(), valuer.MustNewUUID(claims.OrgID), mux.Vars(r)["id"])
+	if apiErr != nil {
+		RespondError(w, apiErr, nil)
+		return
+	}
+
+	aH.Respond(w, rollout)
+}
+
+func parseElementType(r *http.Request) (opamptypes.ElementType, *model.ApiError) {
+	elementType := opamptypes.NewElementType(mux.Vars(r)["elementType"])
+	if elementType.StringValue() == "" {
+		return elementType, model.BadRequest(fmt.Errorf("invalid element type: %s", mux.Vars(r)["elementType"]))
+	}
+
+	return elementType, nil
+}
+
+func (aH *APIHandler) diffAgentConfigVersions(w http.ResponseWriter, r *http.Request) {
+	claims, errv2 := authtypes.ClaimsFromContext(r.Context())
+	if errv2 != nil {
+		render.Error(w, errv2)
+		return
+	}
+	orgID := valuer.MustNewUUID(claims.OrgID)
+
+	elementType, apiErr := parseElementType(r)
+	if apiErr != nil {
+		RespondError(w, apiErr, nil)
+		return
+	}
+
+	fromVersion, err := strconv.Atoi(r.URL.Query().Get("from"))
+	if err != nil || fromVersion <= 0 {
+		RespondError(w, model.BadRequestStr("from must be a valid version number"), nil)
+		return
+	}
+
+	toVersion, err := strconv.Atoi(r.URL.Query().Get("to"))
+	if err != nil || toVersion <= 0 {
+		RespondError(w, model.BadRequestStr("to must be a valid version number"), nil)
+		return
+	}
+
+	if elementType == opamptypes.ElementTypeLogPipelines {
+		diff, apiErr := aH.LogsParsingPipelineController.DiffPipelineVersions(r.Context(), orgID, fromVersion, toVersion)
+		if apiErr != nil {
+			RespondError(w, apiErr, nil)
+			return
+		}
+
+		aH.Respond(w, diff)
+		return
+	}
+
+	diff, apiErr := agentConf.DiffConfigVersions(r.Context(), orgID, elementType, fromVersion, toVersion)
+	if apiErr != nil {
+		RespondError(w, apiErr, nil)
+		return
+	}
+
+	aH.Respond(w, diff)
+}
+
+func (aH *APIHandler) rollbackAgentConfigVersion(w http.ResponseWriter, r *http.Request) {
+	claims, errv2 := authtypes.ClaimsFromContext(r.Context())
+	if errv2 != nil {
+		render.Error(w, errv2)
+		return
+	}
+
+	userID, errv2 := valuer.NewUUID(claims.UserID)
+	if errv2 != nil {
+		render.Error(w, errv2)
+		return
+	}
+
+	elementType, apiErr := parseElementType(r)
+	if apiErr != nil {
+		RespondError(w, apiErr, nil)
+		return
+	}
+
+	version, apiErr := parseAgentConfigVersion(r)
+	if apiErr != nil {
+		RespondError(w, model.WrapApiError(apiErr, "Failed to parse agent config version"), nil)
+		return
+	}
+
+	configVersion, apiErr := agentConf.RollbackToVersion(r.Context(), valuer.MustNewUUID(claims.OrgID), userID, elementType, version)
+	if apiErr != nil {
+		RespondError(w, apiErr, nil)
+		return
+	}
+
+	aH.Respond(w, configVersion)
+}
+
+type dryRunAgentConfigRequest struct {
+	// Versions pins the config version used for a feature, eg: {"log_pipelines": 3}.
+	// Features that aren't pinned use the version that would be recommended to the agent.
+	Versions map[string]int `json:"versions"`
+}
+
+type dryRunAgentConfigResponse struct {
+	AgentID string `json:"agentId"`
+	Config  string `json:"config"`
+}
+
+func (aH *APIHandler) dryRunAgentConfig(w http.ResponseWriter, r *http.Request) {
+	claims, errv2 := authtypes.ClaimsFromContext(r.Context())
+	if errv2 != nil {
+		render.Error(w, errv2)
+		return
+	}
+	orgID := valuer.MustNewUUID(claims.OrgID)
+
+	req := dryRunAgentConfigRequest{}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			RespondError(w, model.BadRequest(err), nil)
+			return
+		}
+	}
+
+	pinnedVersions := map[opamptypes.ElementType]int{}
+	for name, version := range req.Versions {
+		elementType := opamptypes.NewElementType(name)
+		if elementType.StringValue() == "" {
+			RespondError(w, model.BadRequest(fmt.Errorf("invalid element type: %s", name)), nil)
+			return
+		}
+		pinnedVersions[elementType] = version
+	}
+
+	agentID := mux.Vars(r)["agentId"]
+	agent := opAmpModel.AllAgents.FindAgent(agentID)
+	if agent == nil || agent.OrgID != orgID {
+		RespondError(w, model.NotFoundError(fmt.Errorf("agent %s is not connected", agentID)), nil)
+		return
+	}
+
+	currentConfig, labels := agent.EffectiveConfigAndLabels()
+	config, apiErr := agentConf.DryRunAgentConfig(r.Context(), orgID, agentID, labels, []byte(currentConfig), pinnedVersions)
+	if apiErr != nil {
+		RespondError(w, apiErr, nil)
+		return
+	}
+
+	aH.Respond(w, dryRunAgentConfigResponse{AgentID: agentID, Config: string(config)})
+}
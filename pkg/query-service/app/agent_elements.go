@@ -0,0 +1,168 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/SigNoz/signoz/pkg/http/middleware"
+	"github.com/SigNoz/signoz/pkg/http/render"
+	"github.com/SigNoz/signoz/pkg/query-service/app/agentelement"
+	"github.com/SigNoz/signoz/pkg/query-service/model"
+	"github.com/SigNoz/signoz/pkg/types/authtypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+	"github.com/gorilla/mux"
+)
+
+// RegisterDerivedMetricsRoutes adds routes for managing metrics derived from logs
+func (aH *APIHandler) RegisterDerivedMetricsRoutes(router *mux.Router, am *middleware.AuthZ) {
+	registerAgentElementRoutes(aH, router.PathPrefix("/api/v1/derived_metrics").Subrouter(), am, aH.DerivedMetricsController)
+}
+
+// RegisterSpanMetricsRoutes adds routes for managing generators of RED metrics from spans
+func (aH *APIHandler) RegisterSpanMetricsRoutes(router *mux.Router, am *middleware.AuthZ) {
+	registerAgentElementRoutes(aH, router.PathPrefix("/api/v1/span_metrics/generators").Subrouter(), am, aH.SpanMetricsController)
+}
+
+// agentElementHandler serves the definitions of a kind of agent config elements.
+type agentElementHandler[S any, PS agentelement.Storable[S], G any, P any] struct {
+	aH         *APIHandler
+	controller *agentelement.Controller[S, PS, G, P]
+}
+
+func registerAgentElementRoutes[S any, PS agentelement.Storable[S], G any, P any](
+	aH *APIHandler, subRouter *mux.Router, am *middleware.AuthZ, controller *agentelement.Controller[S, PS, G, P],
+) {
+	handler := &agentElementHandler[S, PS, G, P]{aH: aH, controller: controller}
+	subRouter.HandleFunc("", am.PermissionAccess(authtypes.PermissionPipelinesRead, handler.list)).Methods(http.MethodGet)
+	subRouter.HandleFunc("", am.PermissionAccess(authtypes.PermissionPipelinesDeploy, handler.create)).Methods(http.MethodPost)
+	subRouter.HandleFunc("/{id}", am.PermissionAccess(authtypes.PermissionPipelinesRead, handler.get)).Methods(http.MethodGet)
+	subRouter.HandleFunc("/{id}", am.PermissionAccess(authtypes.PermissionPipelinesDeploy, handler.update)).Methods(http.MethodPut)
+	subRouter.HandleFunc("/{id}", am.PermissionAccess(authtypes.PermissionPipelinesDeploy, handler.delete)).Methods(http.MethodDelete)
+}
+
+func (h *agentElementHandler[S, PS, G, P]) list(w http.ResponseWriter, r *http.Request) {
+	claims, errv2 := authtypes.ClaimsFromContext(r.Context())
+	if errv2 != nil {
+		render.Error(w, errv2)
+		return
+	}
+
+	gettables, apiErr := h.controller.List(r.Context(), valuer.MustNewUUID(claims.OrgID))
+	if apiErr != nil {
+		RespondError(w, apiErr, nil)
+		return
+	}
+
+	h.aH.Respond(w, gettables)
+}
+
+func (h *agentElementHandler[S, PS, G, P]) get(w http.ResponseWriter, r *http.Request) {
+	claims, errv2 := authtypes.ClaimsFromContext(r.Context())
+	if errv2 != nil {
+		render.Error(w, errv2)
+		return
+	}
+
+	id, errv2 := valuer.NewUUID(mux.Vars(r)["id"])
+	if errv2 != nil {
+		RespondError(w, model.BadRequest(errv2), nil)
+		return
+	}
+
+	gettable, apiErr := h.controller.Get(r.Context(), valuer.MustNewUUID(claims.OrgID), id)
+	if apiErr != nil {
+		RespondError(w, apiErr, nil)
+		return
+	}
+
+	h.aH.Respond(w, gettable)
+}
+
+func (h *agentElementHandler[S, PS, G, P]) create(w http.ResponseWriter, r *http.Request) {
+	claims, errv2 := authtypes.ClaimsFromContext(r.Context())
+	if errv2 != nil {
+		render.Error(w, errv2)
+		return
+	}
+
+	userID, errv2 := valuer.NewUUID(claims.UserID)
+	if errv2 != nil {
+		render.Error(w, errv2)
+		return
+	}
+
+	req := new(P)
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		RespondError(w, model.BadRequest(err), nil)
+		return
+	}
+
+	gettable, apiErr := h.controller.Create(r.Context(), valuer.MustNewUUID(claims.OrgID), userID, claims.Email, req)
+	if apiErr != nil {
+		RespondError(w, apiErr, nil)
+		return
+	}
+
+	h.aH.Respond(w, gettable)
+}
+
+func (h *agentElementHandler[S, PS, G, P]) update(w http.ResponseWriter, r *http.Request) {
+	claims, errv2 := authtypes.ClaimsFromContext(r.Context())
+	if errv2 != nil {
+		render.Error(w, errv2)
+		return
+	}
+
+	userID, errv2 := valuer.NewUUID(claims.UserID)
+	if errv2 != nil {
+		render.Error(w, errv2)
+		return
+	}
+
+	id, errv2 := valuer.NewUUID(mux.Vars(r)["id"])
+	if errv2 != nil {
+		RespondError(w, model.BadRequest(errv2), nil)
+		return
+	}
+
+	req := new(P)
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		RespondError(w, model.BadRequest(err), nil)
+		return
+	}
+
+	gettable, apiErr := h.controller.Update(r.Context(), valuer.MustNewUUID(claims.OrgID), userID, claims.Email, id, req)
+	if apiErr != nil {
+		RespondError(w, apiErr, nil)
+		return
+	}
+
+	h.aH.Respond(w, gettable)
+}
+
+func (h *agentElementHandler[S, PS, G, P]) delete(w http.ResponseWriter, r *http.Request) {
+	claims, errv2 := authtypes.ClaimsFromContext(r.Context())
+	if errv2 != nil {
+		render.Error(w, errv2)
+		return
+	}
+
+	userID, errv2 := valuer.NewUUID(claims.UserID)
+	if errv2 != nil {
+		render.Error(w, errv2)
+		return
+	}
+
+	id, errv2 := valuer.NewUUID(mux.Vars(r)["id"])
+	if errv2 != nil {
+		RespondError(w, model.BadRequest(errv2), nil)
+		return
+	}
+
+	if apiErr := h.controller.Delete(r.Context(), valuer.MustNewUUID(claims.OrgID), userID, id); apiErr != nil {
+		RespondError(w, apiErr, nil)
+		return
+	}
+
+	h.aH.Respond(w, nil)
+}
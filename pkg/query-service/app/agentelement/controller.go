@@ -0,0 +1,264 @@
+// Package agentelement manages definitions stored by users and deployed to agents as the
+// elements of agent config versions, eg: derived metrics and span metrics generators.
+package agentelement
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+
+	errorsV2 "github.com/SigNoz/signoz/pkg/errors"
+	"github.com/SigNoz/signoz/pkg/query-service/agentConf"
+	"github.com/SigNoz/signoz/pkg/query-service/model"
+	"github.com/SigNoz/signoz/pkg/sqlstore"
+	"github.com/SigNoz/signoz/pkg/types/opamptypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+	"github.com/pkg/errors"
+)
+
+// Storable is the stored form of a revision of a definition.
+type Storable[S any] interface {
+	*S
+	ElementID() valuer.UUID
+	ElementDefinitionID() valuer.UUID
+	ElementName() string
+}
+
+// Kind describes a kind of definitions and how they are deployed to agents.
+type Kind[S any, PS Storable[S]] struct {
+	// name of a definition used in messages, eg: derived metric
+	Noun                 string
+	ElementType          opamptypes.ElementType
+	FeatureType          agentConf.AgentFeatureType
+	ErrCodeAlreadyExists errorsV2.Code
+	ErrCodeNotFound      errorsV2.Code
+}
+
+// Definition converts between the stored, gettable and postable forms of a kind of definitions.
+type Definition[S any, PS Storable[S], G any, P any] interface {
+	NewStorable(orgID valuer.UUID, createdBy string, postable *P) (PS, error)
+	NewRevision(storable PS, updatedBy string, postable *P) (PS, error)
+	NewGettable(storable PS) (*G, error)
+
+	// Validate checks the definition and that it can be translated to collector config
+	// so that invalid definitions are rejected instead of failing the deployment.
+	Validate(postable *P) error
+
+	// GenerateCollectorConfig adds the components deploying `gettables` to the collector config.
+	GenerateCollectorConfig(config []byte, gettables []G) ([]byte, *model.ApiError)
+}
+
+// Controller manages the definitions of a kind and their deployment to agents.
+//
+// Every change to the definitions stores new revisions and starts a new agent config version
+// whose elements are the current revisions of the org. The stored revisions are never updated
+// or deleted, the earlier config versions keep deploying the definitions they were created with.
+type Controller[S any, PS Storable[S], G any, P any] struct {
+	Repo[S, PS]
+	definition Definition[S, PS, G, P]
+}
+
+func NewController[S any, PS Storable[S], G any, P any](sqlStore sqlstore.SQLStore, kind *Kind[S, PS], definition Definition[S, PS, G, P]) *Controller[S, PS, G, P] {
+	return &Controller[S, PS, G, P]{
+		Repo:       Repo[S, PS]{sqlStore: sqlStore, kind: kind},
+		definition: definition,
+	}
+}
+
+func (c *Controller[S, PS, G, P]) List(ctx context.Context, orgID valuer.UUID) ([]G, *model.ApiError) {
+	storables, apiErr := c.list(ctx, orgID)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	return c.toGettable(storables)
+}
+
+func (c *Controller[S, PS, G, P]) Get(ctx context.Context, orgID valuer.UUID, id valuer.UUID) (*G, *model.ApiError) {
+	storable, apiErr := c.get(ctx, orgID, id)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	gettable, err := c.definition.NewGettable(storable)
+	if err != nil {
+		return nil, model.InternalError(err)
+	}
+
+	return gettable, nil
+}
+
+func (c *Controller[S, PS, G, P]) Create(
+	ctx context.Context, orgID valuer.UUID, userID valuer.UUID, email string, postable *P,
+) (*G, *model.ApiError) {
+	if err := c.definition.Validate(postable); err != nil {
+		return nil, model.BadRequest(err)
+	}
+
+	current, apiErr := c.list(ctx, orgID)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	storable, err := c.definition.NewStorable(orgID, email, postable)
+	if err != nil {
+		return nil, model.InternalError(err)
+	}
+
+	if apiErr := c.checkNameIsUnique(current, storable); apiErr != nil {
+		return nil, apiErr
+	}
+
+	if apiErr := c.insert(ctx, storable); apiErr != nil {
+		return nil, apiErr
+	}
+
+	if apiErr := c.startNewVersion(ctx, orgID, userID, append(current, *storable)); apiErr != nil {
+		return nil, apiErr
+	}
+
+	return c.Get(ctx, orgID, storable.ElementDefinitionID())
+}
+
+func (c *Controller[S, PS, G, P]) Update(
+	ctx context.Context, orgID valuer.UUID, userID valuer.UUID, email string, id valuer.UUID, postable *P,
+) (*G, *model.ApiError) {
+	if err := c.definition.Validate(postable); err != nil {
+		return nil, model.BadRequest(err)
+	}
+
+	current, apiErr := c.list(ctx, orgID)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	idx, apiErr := c.indexOf(current, id)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	revision, err := c.definition.NewRevision(&current[idx], email, postable)
+	if err != nil {
+		return nil, model.InternalError(err)
+	}
+
+	if apiErr := c.checkNameIsUnique(current, revision); apiErr != nil {
+		return nil, apiErr
+	}
+
+	if apiErr := c.insert(ctx, revision); apiErr != nil {
+		return nil, apiErr
+	}
+
+	current[idx] = *revision
+	if apiErr := c.startNewVersion(ctx, orgID, userID, current); apiErr != nil {
+		return nil, apiErr
+	}
+
+	return c.Get(ctx, orgID, id)
+}
+
+func (c *Controller[S, PS, G, P]) Delete(ctx context.Context, orgID valuer.UUID, userID valuer.UUID, id valuer.UUID) *model.ApiError {
+	current, apiErr := c.list(ctx, orgID)
+	if apiErr != nil {
+		return apiErr
+	}
+
+	idx, apiErr := c.indexOf(current, id)
+	if apiErr != nil {
+		return apiErr
+	}
+
+	return c.startNewVersion(ctx, orgID, userID, slices.Delete(current, idx, idx+1))
+}
+
+// startNewVersion starts a config version whose elements are `storables`
+func (c *Controller[S, PS, G, P]) startNewVersion(ctx context.Context, orgID valuer.UUID, userID valuer.UUID, storables []S) *model.ApiError {
+	elements := make([]string, len(storables))
+	for i := range storables {
+		elements[i] = PS(&storables[i]).ElementID().StringValue()
+	}
+
+	if _, apiErr := agentConf.StartNewVersion(ctx, orgID, userID, c.kind.ElementType, elements, nil); apiErr != nil {
+		return model.WrapApiError(apiErr, "failed to start new version")
+	}
+
+	return nil
+}
+
+func (c *Controller[S, PS, G, P]) indexOf(current []S, id valuer.UUID) (int, *model.ApiError) {
+	idx := slices.IndexFunc(current, func(storable S) bool {
+		return PS(&storable).ElementDefinitionID() == id
+	})
+	if idx < 0 {
+		return 0, model.NotFoundError(errorsV2.Newf(errorsV2.TypeNotFound, c.kind.ErrCodeNotFound, "%s %s does not exist", c.kind.Noun, id.StringValue()))
+	}
+
+	return idx, nil
+}
+
+// checkNameIsUnique reports the names used by another current definition as bad requests.
+func (c *Controller[S, PS, G, P]) checkNameIsUnique(current []S, storable PS) *model.ApiError {
+	for i := range current {
+		other := PS(&current[i])
+		if other.ElementDefinitionID() != storable.ElementDefinitionID() && other.ElementName() == storable.ElementName() {
+			return model.BadRequest(errorsV2.Newf(errorsV2.TypeAlreadyExists, c.kind.ErrCodeAlreadyExists, "%s %s already exists", c.kind.Noun, storable.ElementName()))
+		}
+	}
+
+	return nil
+}
+
+// Implements agentConf.AgentFeature interface.
+func (c *Controller[S, PS, G, P]) AgentFeatureType() agentConf.AgentFeatureType {
+	return c.kind.FeatureType
+}
+
+// Implements agentConf.AgentFeature interface.
+func (c *Controller[S, PS, G, P]) RecommendAgentConfig(
+	orgId valuer.UUID,
+	currentConfYaml []byte,
+	configVersion *opamptypes.AgentConfigVersion,
+) (
+	recommendedConfYaml []byte,
+	serializedSettingsUsed string,
+	apiErr *model.ApiError,
+) {
+	gettables := []G{}
+	if configVersion != nil {
+		storables, apiErr := c.listByVersion(context.Background(), orgId, configVersion.Version)
+		if apiErr != nil {
+			return nil, "", apiErr
+		}
+
+		gettables, apiErr = c.toGettable(storables)
+		if apiErr != nil {
+			return nil, "", apiErr
+		}
+	}
+
+	updatedConf, apiErr := c.definition.GenerateCollectorConfig(currentConfYaml, gettables)
+	if apiErr != nil {
+		return nil, "", model.WrapApiError(apiErr, "could not generate collector config for "+c.kind.Noun+"s")
+	}
+
+	rawGettables, err := json.Marshal(gettables)
+	if err != nil {
+		return nil, "", model.BadRequest(errors.Wrapf(err, "could not serialize %ss to JSON", c.kind.Noun))
+	}
+
+	return updatedConf, string(rawGettables), nil
+}
+
+func (c *Controller[S, PS, G, P]) toGettable(storables []S) ([]G, *model.ApiError) {
+	gettables := make([]G, len(storables))
+	for i := range storables {
+		gettable, err := c.definition.NewGettable(&storables[i])
+		if err != nil {
+			return nil, model.InternalError(err)
+		}
+		gettables[i] = *gettable
+	}
+
+	return gettables, nil
+}
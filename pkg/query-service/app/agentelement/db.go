@@ -0,0 +1,92 @@
+package agentelement
+
+import (
+	"context"
+
+	errorsV2 "github.com/SigNoz/signoz/pkg/errors"
+	"github.com/SigNoz/signoz/pkg/query-service/model"
+	"github.com/SigNoz/signoz/pkg/sqlstore"
+	"github.com/SigNoz/signoz/pkg/valuer"
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+)
+
+// Repo handles DML ops on the stored definitions of a kind of agent config elements
+type Repo[S any, PS Storable[S]] struct {
+	sqlStore sqlstore.SQLStore
+	kind     *Kind[S, PS]
+}
+
+func (r *Repo[S, PS]) insert(ctx context.Context, storable PS) *model.ApiError {
+	_, err := r.sqlStore.BunDB().NewInsert().
+		Model(storable).
+		Exec(ctx)
+	if err != nil {
+		return model.InternalError(errors.Wrapf(err, "failed to save %s", r.kind.Noun))
+	}
+
+	return nil
+}
+
+// get returns the current revision of a definition
+func (r *Repo[S, PS]) get(ctx context.Context, orgID valuer.UUID, id valuer.UUID) (PS, *model.ApiError) {
+	storable := PS(new(S))
+	err := r.selectElements(storable, orgID).
+		Where("v.version = (?)", r.selectLatestVersion(orgID)).
+		Where("?TableAlias.definition_id = ?", id).
+		Scan(ctx)
+	if err != nil {
+		err = r.sqlStore.WrapNotFoundErrf(err, r.kind.ErrCodeNotFound, "%s %s does not exist", r.kind.Noun, id.StringValue())
+		if errorsV2.Ast(err, errorsV2.TypeNotFound) {
+			return nil, model.NotFoundError(err)
+		}
+		return nil, model.InternalError(errors.Wrapf(err, "failed to get %s", r.kind.Noun))
+	}
+
+	return storable, nil
+}
+
+// list returns the current revisions of the definitions, the elements of the latest config version
+func (r *Repo[S, PS]) list(ctx context.Context, orgID valuer.UUID) ([]S, *model.ApiError) {
+	storables := []S{}
+	err := r.selectElements(&storables, orgID).
+		Where("v.version = (?)", r.selectLatestVersion(orgID)).
+		OrderExpr("?TableAlias.name ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, model.InternalError(errors.Wrapf(err, "failed to list %ss", r.kind.Noun))
+	}
+
+	return storables, nil
+}
+
+// listByVersion returns the stored revisions that are elements of a config version
+func (r *Repo[S, PS]) listByVersion(ctx context.Context, orgID valuer.UUID, version int) ([]S, *model.ApiError) {
+	storables := []S{}
+	err := r.selectElements(&storables, orgID).
+		Where("v.version = ?", version).
+		OrderExpr("?TableAlias.name ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, model.InternalError(errors.Wrapf(err, "failed to get %ss of config version", r.kind.Noun))
+	}
+
+	return storables, nil
+}
+
+func (r *Repo[S, PS]) selectElements(model interface{}, orgID valuer.UUID) *bun.SelectQuery {
+	return r.sqlStore.BunDB().NewSelect().
+		Model(model).
+		Join("JOIN agent_config_element e ON ?TableAlias.id = e.element_id").
+		Join("JOIN agent_config_version v ON v.id = e.version_id").
+		Where("e.element_type = ?", r.kind.ElementType.StringValue()).
+		Where("v.org_id = ?", orgID)
+}
+
+func (r *Repo[S, PS]) selectLatestVersion(orgID valuer.UUID) *bun.SelectQuery {
+	return r.sqlStore.BunDB().NewSelect().
+		Table("agent_config_version").
+		ColumnExpr("MAX(version)").
+		Where("org_id = ?", orgID).
+		Where("element_type = ?", r.kind.ElementType.StringValue())
+}
@@ -2823,6 +2823,15 @@ func (r *ClickHouseReader) GetLogs(ctx context.Context, params *model.LogsFilter
 	return &response, nil
 }
 
+func (r *ClickHouseReader) GetLogsV2(ctx context.Context, query string) ([]model.SignozLogV2, *model.ApiError) {
+	response := []model.SignozLogV2{}
+	err := r.db.Select(ctx, &response, query)
+	if err != nil {
+		return nil, &model.ApiError{Err: err, Typ: model.ErrorInternal}
+	}
+	return response, nil
+}
+
 func (r *ClickHouseReader) TailLogs(ctx context.Context, client *model.LogsTailClient) {
 
 	fields, apiErr := r.GetLogFields(ctx)
@@ -0,0 +1,243 @@
+package derivedmetrics
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+
+	"github.com/SigNoz/signoz/pkg/query-service/model"
+	"github.com/SigNoz/signoz/pkg/querybuilder"
+	"github.com/SigNoz/signoz/pkg/types/derivedmetrictypes"
+	"github.com/SigNoz/signoz/pkg/types/telemetrytypes"
+	"github.com/pkg/errors"
+)
+
+const (
+	countConnectorName = "count/signoz_derived_metrics"
+	sumConnectorName   = "sum/signoz_derived_metrics"
+)
+
+var derivedMetricsConnectorNames = []string{countConnectorName, sumConnectorName}
+
+type connectorMetricAttribute struct {
+	Key string `yaml:"key"`
+}
+
+// config of a single metric of the count and sum connectors
+type connectorMetric struct {
+	Description     string                     `yaml:"description,omitempty"`
+	SourceAttribute string                     `yaml:"source_attribute,omitempty"`
+	Conditions      []string                   `yaml:"conditions,omitempty"`
+	Attributes      []connectorMetricAttribute `yaml:"attributes,omitempty"`
+}
+
+// connectorConfigs returns the count and sum connector configs computing `metrics`, keyed by connector name.
+// Connectors without any metrics are left out since they emit a default metric when none are configured.
+func connectorConfigs(metrics []derivedmetrictypes.GettableDerivedMetric) (map[string]interface{}, error) {
+	metricsByConnector := map[string]map[string]connectorMetric{}
+	for _, metric := range metrics {
+		if !metric.Enabled {
+			continue
+		}
+
+		connectorName, metricConf, err := connectorMetricFor(metric)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not generate collector config for derived metric %s", metric.Name)
+		}
+
+		if _, ok := metricsByConnector[connectorName]; !ok {
+			metricsByConnector[connectorName] = map[string]connectorMetric{}
+		}
+		metricsByConnector[connectorName][metric.Name] = metricConf
+	}
+
+	configs := map[string]interface{}{}
+	for connectorName, connectorMetrics := range metricsByConnector {
+		// round trip through yaml to get the generic representation used for the rest of the collector config
+		serialized, err := yaml.Marshal(map[string]interface{}{"logs": connectorMetrics})
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not marshal config of %s", connectorName)
+		}
+
+		// Escape any `$`s as `$$$` so that they don't end up being treated as env vars when loading collector config.
+		var config map[string]interface{}
+		if err := yaml.Unmarshal([]byte(strings.ReplaceAll(string(serialized), "$", "$$$")), &config); err != nil {
+			return nil, errors.Wrapf(err, "could not unmarshal dollar escaped config of %s", connectorName)
+		}
+
+		configs[connectorName] = config
+	}
+
+	return configs, nil
+}
+
+func connectorMetricFor(metric derivedmetrictypes.GettableDerivedMetric) (string, connectorMetric, error) {
+	if len(metric.Query.Aggregations) != 1 {
+		return "", connectorMetric{}, fmt.Errorf("expected exactly one aggregation, got %d", len(metric.Query.Aggregations))
+	}
+
+	aggregation, err := derivedmetrictypes.ParseAggregation(metric.Query.Aggregations[0].Expression)
+	if err != nil {
+		return "", connectorMetric{}, err
+	}
+
+	expression := ""
+	if metric.Query.Filter != nil {
+		expression = metric.Query.Filter.Expression
+	}
+	condition, err := querybuilder.PrepareOTTLCondition(expression, querybuilder.OTTLConditionOpts{
+		FieldContext:  telemetrytypes.FieldContextLog,
+		Fields:        querybuilder.OTTLLogFields,
+		FullTextField: querybuilder.OTTLLogFields["body"],
+	})
+	if err != nil {
+		return "", connectorMetric{}, err
+	}
+
+	config := connectorMetric{
+		Description: metric.Description,
+		Conditions:  []string{condition},
+	}
+
+	// the connectors look dimension keys up in the attributes of the log and then of its resource
+	for _, key := range metric.Query.GroupBy {
+		if err := validateConnectorKey(key.TelemetryFieldKey, true); err != nil {
+			return "", connectorMetric{}, errors.Wrap(err, "invalid group by key")
+		}
+		config.Attributes = append(config.Attributes, connectorMetricAttribute{Key: key.Name})
+	}
+
+	if aggregation.Function == derivedmetrictypes.AggregationFunctionCount {
+		return countConnectorName, config, nil
+	}
+
+	// the sum connector reads the values to add up from log attributes only
+	if err := validateConnectorKey(*aggregation.Key, false); err != nil {
+		return "", connectorMetric{}, errors.Wrap(err, "invalid key to sum up")
+	}
+	config.SourceAttribute = aggregation.Key.Name
+
+	return sumConnectorName, config, nil
+}
+
+func validateConnectorKey(key telemetrytypes.TelemetryFieldKey, allowResource bool) error {
+	switch key.FieldContext {
+	case telemetrytypes.FieldContextAttribute, telemetrytypes.FieldContextUnspecified:
+	case telemetrytypes.FieldContextResource:
+		if !allowResource {
+			return fmt.Errorf("resource attribute `%s` can not be used here", key.Name)
+		}
+	default:
+		return fmt.Errorf("key `%s` of context `%s` is not supported, only attributes can be used", key.Name, key.FieldContext.StringValue())
+	}
+
+	if _, ok := querybuilder.OTTLLogFields[key.Name]; ok && key.FieldContext == telemetrytypes.FieldContextUnspecified {
+		return fmt.Errorf("log field `%s` is not supported, only attributes can be used", key.Name)
+	}
+
+	return nil
+}
+
+// GenerateCollectorConfigWithDerivedMetrics adds the connectors computing `metrics` to the collector config.
+// The connectors are exporters of the logs pipeline and receivers of the metrics pipeline so the derived
+// metrics get written to the metrics tables like any other metric, the config is left unchanged when it
+// doesn't have both pipelines.
+func GenerateCollectorConfigWithDerivedMetrics(
+	config []byte,
+	metrics []derivedmetrictypes.GettableDerivedMetric,
+) ([]byte, *model.ApiError) {
+	var collectorConf map[string]interface{}
+	if err := yaml.Unmarshal(config, &collectorConf); err != nil {
+		return nil, model.BadRequest(err)
+	}
+	if collectorConf == nil {
+		collectorConf = map[string]interface{}{}
+	}
+
+	connectors, err := connectorConfigs(metrics)
+	if err != nil {
+		return nil, model.BadRequest(err)
+	}
+
+	agentConnectors, _ := collectorConf["connectors"].(map[string]interface{})
+	if agentConnectors == nil {
+		agentConnectors = map[string]interface{}{}
+	}
+	for _, name := range derivedMetricsConnectorNames {
+		delete(agentConnectors, name)
+	}
+	for name, connectorConf := range connectors {
+		agentConnectors[name] = connectorConf
+	}
+	if len(agentConnectors) > 0 {
+		collectorConf["connectors"] = agentConnectors
+	} else {
+		delete(collectorConf, "connectors")
+	}
+
+	connectorNames := []string{}
+	for _, name := range derivedMetricsConnectorNames {
+		if _, ok := connectors[name]; ok {
+			connectorNames = append(connectorNames, name)
+		}
+	}
+
+	service, _ := collectorConf["service"].(map[string]interface{})
+	pipelines, _ := service["pipelines"].(map[string]interface{})
+	if len(connectorNames) > 0 && (pipelines["logs"] == nil || pipelines["metrics"] == nil) {
+		// the rest of the recommended config must still reach the agent
+		zap.L().Warn("skipping derived metrics, the collector config has no logs or metrics pipeline")
+		return config, nil
+	}
+
+	if err := updatePipelineComponents(pipelines, "logs", "exporters", connectorNames); err != nil {
+		return nil, model.BadRequest(err)
+	}
+	if err := updatePipelineComponents(pipelines, "metrics", "receivers", connectorNames); err != nil {
+		return nil, model.BadRequest(err)
+	}
+
+	updatedConf, err := yaml.Marshal(collectorConf)
+	if err != nil {
+		return nil, model.InternalError(errors.Wrap(err, "could not marshal updated collector config"))
+	}
+
+	return updatedConf, nil
+}
+
+// updatePipelineComponents replaces the derived metrics connectors among the `components`
+// (receivers or exporters) of a collector pipeline with `connectorNames`.
+func updatePipelineComponents(
+	pipelines map[string]interface{}, pipelineName string, components string, connectorNames []string,
+) error {
+	pipeline, ok := pipelines[pipelineName].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	if pipeline[components] == nil && len(connectorNames) == 0 {
+		return nil
+	}
+
+	current, ok := pipeline[components].([]interface{})
+	if !ok && pipeline[components] != nil {
+		return fmt.Errorf("unexpected %s in %s pipeline: %v", components, pipelineName, pipeline[components])
+	}
+
+	updated := []interface{}{}
+	for _, component := range current {
+		if name, ok := component.(string); ok && slices.Contains(derivedMetricsConnectorNames, name) {
+			continue
+		}
+		updated = append(updated, component)
+	}
+	for _, name := range connectorNames {
+		updated = append(updated, name)
+	}
+
+	pipeline[components] = updated
+	return nil
+}
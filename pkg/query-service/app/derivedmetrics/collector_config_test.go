@@ -0,0 +1,130 @@
+package derivedmetrics
+
+import (
+	"testing"
+
+	"github.com/SigNoz/signoz/pkg/types/derivedmetrictypes"
+	qbtypes "github.com/SigNoz/signoz/pkg/types/querybuildertypes/querybuildertypesv5"
+	"github.com/SigNoz/signoz/pkg/types/telemetrytypes"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+const testCollectorConf = `
+receivers:
+  otlp: {}
+exporters:
+  clickhouselogsexporter: {}
+  clickhousemetricswrite: {}
+service:
+  pipelines:
+    logs:
+      receivers: [otlp]
+      exporters: [clickhouselogsexporter]
+    metrics:
+      receivers: [otlp]
+      exporters: [clickhousemetricswrite]
+`
+
+func testDerivedMetric(name string, aggregation string, filter string, groupBy ...string) derivedmetrictypes.GettableDerivedMetric {
+	query := qbtypes.QueryBuilderQuery[qbtypes.LogAggregation]{
+		Signal:       telemetrytypes.SignalLogs,
+		Aggregations: []qbtypes.LogAggregation{{Expression: aggregation}},
+		Filter:       &qbtypes.Filter{Expression: filter},
+	}
+	for _, key := range groupBy {
+		query.GroupBy = append(query.GroupBy, qbtypes.GroupByKey{
+			TelemetryFieldKey: telemetrytypes.GetFieldKeyFromKeyText(key),
+		})
+	}
+
+	return derivedmetrictypes.GettableDerivedMetric{Name: name, Query: query, Enabled: true}
+}
+
+func TestGenerateCollectorConfigWithDerivedMetrics(t *testing.T) {
+	require := require.New(t)
+
+	metrics := []derivedmetrictypes.GettableDerivedMetric{
+		testDerivedMetric("checkout_errors", "count()", "severity_text = 'ERROR'", "service.name"),
+		testDerivedMetric("bytes_sent", "sum(attribute.bytes)", "attribute.price > 100"),
+	}
+	disabled := testDerivedMetric("disabled_metric", "count()", "")
+	disabled.Enabled = false
+	metrics = append(metrics, disabled)
+
+	updated, apiErr := GenerateCollectorConfigWithDerivedMetrics([]byte(testCollectorConf), metrics)
+	require.Nil(apiErr)
+
+	var conf map[string]interface{}
+	require.NoError(yaml.Unmarshal(updated, &conf))
+
+	connectors := conf["connectors"].(map[string]interface{})
+	require.Len(connectors, 2)
+
+	countMetrics := connectors[countConnectorName].(map[string]interface{})["logs"].(map[string]interface{})
+	require.Len(countMetrics, 1)
+	checkoutErrors := countMetrics["checkout_errors"].(map[string]interface{})
+	require.Equal([]interface{}{`severity_text == "ERROR"`}, checkoutErrors["conditions"])
+	require.Equal([]interface{}{map[string]interface{}{"key": "service.name"}}, checkoutErrors["attributes"])
+
+	sumMetrics := connectors[sumConnectorName].(map[string]interface{})["logs"].(map[string]interface{})
+	bytesSent := sumMetrics["bytes_sent"].(map[string]interface{})
+	require.Equal("bytes", bytesSent["source_attribute"])
+	require.Equal([]interface{}{`attributes["price"] > 100`}, bytesSent["conditions"])
+
+	pipelines := conf["service"].(map[string]interface{})["pipelines"].(map[string]interface{})
+	require.Equal(
+		[]interface{}{"clickhouselogsexporter", countConnectorName, sumConnectorName},
+		pipelines["logs"].(map[string]interface{})["exporters"],
+	)
+	require.Equal(
+		[]interface{}{"otlp", countConnectorName, sumConnectorName},
+		pipelines["metrics"].(map[string]interface{})["receivers"],
+	)
+
+	// removing all derived metrics must clean up the connectors
+	cleaned, apiErr := GenerateCollectorConfigWithDerivedMetrics(updated, nil)
+	require.Nil(apiErr)
+
+	var expected, actual map[string]interface{}
+	require.NoError(yaml.Unmarshal([]byte(testCollectorConf), &expected))
+	require.NoError(yaml.Unmarshal(cleaned, &actual))
+	require.Equal(expected, actual)
+}
+
+func TestGenerateCollectorConfigWithDerivedMetricsMissingPipeline(t *testing.T) {
+	conf := []byte("service:\n  pipelines:\n    logs:\n      exporters: [clickhouselogsexporter]\n")
+
+	// the rest of the config is still recommended to the agent
+	updated, apiErr := GenerateCollectorConfigWithDerivedMetrics(conf, []derivedmetrictypes.GettableDerivedMetric{testDerivedMetric("requests", "count()", "")})
+	require.Nil(t, apiErr)
+	require.Equal(t, conf, updated)
+}
+
+func TestGenerateCollectorConfigWithDerivedMetricsErrors(t *testing.T) {
+	testCases := []struct {
+		name   string
+		conf   string
+		metric derivedmetrictypes.GettableDerivedMetric
+	}{
+		{
+			name:   "sum of a resource attribute",
+			conf:   testCollectorConf,
+			metric: testDerivedMetric("requests", "sum(resource.bytes)", ""),
+		},
+		{
+			name:   "group by a log field",
+			conf:   testCollectorConf,
+			metric: testDerivedMetric("requests", "count()", "", "severity_text"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, apiErr := GenerateCollectorConfigWithDerivedMetrics(
+				[]byte(tc.conf), []derivedmetrictypes.GettableDerivedMetric{tc.metric},
+			)
+			require.NotNil(t, apiErr)
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package derivedmetrics
+
+import (
+	"github.com/SigNoz/signoz/pkg/query-service/agentConf"
+	"github.com/SigNoz/signoz/pkg/query-service/app/agentelement"
+	"github.com/SigNoz/signoz/pkg/query-service/model"
+	"github.com/SigNoz/signoz/pkg/sqlstore"
+	"github.com/SigNoz/signoz/pkg/types/derivedmetrictypes"
+	"github.com/SigNoz/signoz/pkg/types/opamptypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+	"github.com/pkg/errors"
+)
+
+const DerivedMetricsFeatureType agentConf.AgentFeatureType = "derived_metrics"
+
+// Controller manages derived metric definitions and their deployment to agents,
+// the collectors compute the enabled ones continuously from the logs they receive.
+type Controller = agentelement.Controller[
+	derivedmetrictypes.StorableDerivedMetric,
+	*derivedmetrictypes.StorableDerivedMetric,
+	derivedmetrictypes.GettableDerivedMetric,
+	derivedmetrictypes.PostableDerivedMetric,
+]
+
+func NewController(sqlStore sqlstore.SQLStore) *Controller {
+	return agentelement.NewController(sqlStore, &agentelement.Kind[derivedmetrictypes.StorableDerivedMetric, *derivedmetrictypes.StorableDerivedMetric]{
+		Noun:                 "derived metric",
+		ElementType:          opamptypes.ElementTypeDerivedMetrics,
+		FeatureType:          DerivedMetricsFeatureType,
+		ErrCodeAlreadyExists: derivedmetrictypes.ErrCodeDerivedMetricAlreadyExists,
+		ErrCodeNotFound:      derivedmetrictypes.ErrCodeDerivedMetricNotFound,
+	}, definition{})
+}
+
+// Implements agentelement.Definition interface.
+type definition struct{}
+
+func (definition) NewStorable(orgID valuer.UUID, createdBy string, postable *derivedmetrictypes.PostableDerivedMetric) (*derivedmetrictypes.StorableDerivedMetric, error) {
+	return derivedmetrictypes.NewStorableDerivedMetric(orgID, createdBy, postable)
+}
+
+func (definition) NewRevision(storable *derivedmetrictypes.StorableDerivedMetric, updatedBy string, postable *derivedmetrictypes.PostableDerivedMetric) (*derivedmetrictypes.StorableDerivedMetric, error) {
+	return storable.NewRevision(updatedBy, postable)
+}
+
+func (definition) NewGettable(storable *derivedmetrictypes.StorableDerivedMetric) (*derivedmetrictypes.GettableDerivedMetric, error) {
+	return derivedmetrictypes.NewGettableDerivedMetric(storable)
+}
+
+func (definition) Validate(postable *derivedmetrictypes.PostableDerivedMetric) error {
+	if err := postable.Validate(); err != nil {
+		return err
+	}
+
+	if _, _, err := connectorMetricFor(derivedmetrictypes.GettableDerivedMetric{
+		Name:    postable.Name,
+		Query:   postable.Query,
+		Enabled: true,
+	}); err != nil {
+		return errors.Wrap(err, "derived metric can not be computed by the collectors")
+	}
+
+	return nil
+}
+
+func (definition) GenerateCollectorConfig(config []byte, metrics []derivedmetrictypes.GettableDerivedMetric) ([]byte, *model.ApiError) {
+	return GenerateCollectorConfigWithDerivedMetrics(config, metrics)
+}
@@ -0,0 +1,92 @@
+package derivedmetrics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SigNoz/signoz/pkg/query-service/agentConf"
+	"github.com/SigNoz/signoz/pkg/query-service/model"
+	"github.com/SigNoz/signoz/pkg/query-service/utils"
+	"github.com/SigNoz/signoz/pkg/types/derivedmetrictypes"
+	"github.com/SigNoz/signoz/pkg/types/opamptypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestController(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	sqlStore := utils.NewQueryServiceDBForTests(t)
+	require.NoError(utils.CreateTestOrg(t, sqlStore))
+	orgID, err := utils.GetTestOrgId(sqlStore)
+	require.NoError(err)
+	userID := valuer.GenerateUUID()
+
+	controller := NewController(sqlStore)
+	_, err = agentConf.Initiate(&agentConf.ManagerOptions{Store: sqlStore, AgentFeatures: []agentConf.AgentFeature{controller}})
+	require.NoError(err)
+
+	postable := func(name string) *derivedmetrictypes.PostableDerivedMetric {
+		metric := testDerivedMetric(name, "count()", "severity_text = 'ERROR'")
+		return &derivedmetrictypes.PostableDerivedMetric{Name: metric.Name, Query: metric.Query, Enabled: true}
+	}
+
+	created, apiErr := controller.Create(ctx, orgID, userID, "editor@example.com", postable("checkout_errors"))
+	require.Nil(apiErr)
+	require.Equal("checkout_errors", created.Name)
+
+	// names are unique in an org
+	_, apiErr = controller.Create(ctx, orgID, userID, "editor@example.com", postable("checkout_errors"))
+	require.NotNil(apiErr)
+	require.Equal(model.ErrorBadData, apiErr.Type())
+
+	_, apiErr = controller.Get(ctx, orgID, valuer.GenerateUUID())
+	require.NotNil(apiErr)
+	require.Equal(model.ErrorNotFound, apiErr.Type())
+
+	_, apiErr = controller.Create(ctx, orgID, userID, "editor@example.com", postable("cart_errors"))
+	require.Nil(apiErr)
+	require.Nil(controller.Delete(ctx, orgID, userID, created.ID))
+
+	metrics, apiErr := controller.List(ctx, orgID)
+	require.Nil(apiErr)
+	require.Len(metrics, 1)
+
+	// the config of the latest version only has the metrics left
+	version, apiErr := agentConf.GetLatestVersion(ctx, orgID, opamptypes.ElementTypeDerivedMetrics)
+	require.Nil(apiErr)
+	require.Equal(3, version.Version)
+
+	config, _, apiErr := controller.RecommendAgentConfig(orgID, []byte(testCollectorConf), version)
+	require.Nil(apiErr)
+
+	var conf map[string]interface{}
+	require.NoError(yaml.Unmarshal(config, &conf))
+	logs := conf["connectors"].(map[string]interface{})[countConnectorName].(map[string]interface{})["logs"].(map[string]interface{})
+	require.Contains(logs, "cart_errors")
+	require.NotContains(logs, "checkout_errors")
+
+	// updates store a new revision, the earlier versions keep the definitions they were created with
+	update := postable("cart_errors")
+	update.Description = "errors of the cart"
+	updated, apiErr := controller.Update(ctx, orgID, userID, "editor@example.com", metrics[0].ID, update)
+	require.Nil(apiErr)
+	require.Equal(metrics[0].ID, updated.ID)
+	require.Equal("errors of the cart", updated.Description)
+
+	earlier, apiErr := agentConf.GetConfigVersion(ctx, orgID, opamptypes.ElementTypeDerivedMetrics, 2)
+	require.Nil(apiErr)
+	_, settings, apiErr := controller.RecommendAgentConfig(orgID, []byte(testCollectorConf), earlier)
+	require.Nil(apiErr)
+	require.Contains(settings, "checkout_errors")
+	require.NotContains(settings, "errors of the cart")
+
+	// the name of a deleted metric can be used again
+	_, apiErr = controller.Create(ctx, orgID, userID, "editor@example.com", postable("checkout_errors"))
+	require.Nil(apiErr)
+	_, apiErr = controller.Update(ctx, orgID, userID, "editor@example.com", metrics[0].ID, postable("checkout_errors"))
+	require.NotNil(apiErr)
+	require.Equal(model.ErrorBadData, apiErr.Type())
+}
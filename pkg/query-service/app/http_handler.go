@@ -60,17 +60,23 @@ import (
 	v3 "github.com/SigNoz/signoz/pkg/query-service/model/v3"
 	"github.com/SigNoz/signoz/pkg/query-service/postprocess"
 	"github.com/SigNoz/signoz/pkg/types"
+	"github.com/SigNoz/signoz/pkg/types/audittypes"
 	"github.com/SigNoz/signoz/pkg/types/authtypes"
 	"github.com/SigNoz/signoz/pkg/types/dashboardtypes"
 	"github.com/SigNoz/signoz/pkg/types/licensetypes"
 	"github.com/SigNoz/signoz/pkg/types/opamptypes"
 	"github.com/SigNoz/signoz/pkg/types/pipelinetypes"
+	"github.com/SigNoz/signoz/pkg/types/provisioningtypes"
 	ruletypes "github.com/SigNoz/signoz/pkg/types/ruletypes"
+	"github.com/SigNoz/signoz/pkg/types/sharetypes"
+	"github.com/SigNoz/signoz/pkg/types/teamtypes"
 
 	"go.uber.org/zap"
 
+	"github.com/SigNoz/signoz/pkg/query-service/app/derivedmetrics"
 	"github.com/SigNoz/signoz/pkg/query-service/app/integrations/messagingQueues/kafka"
 	"github.com/SigNoz/signoz/pkg/query-service/app/logparsingpipeline"
+	"github.com/SigNoz/signoz/pkg/query-service/app/spanmetrics"
 	"github.com/SigNoz/signoz/pkg/query-service/interfaces"
 	"github.com/SigNoz/signoz/pkg/query-service/model"
 	"github.com/SigNoz/signoz/pkg/query-service/rules"
@@ -115,6 +121,10 @@ type APIHandler struct {
 
 	LogsParsingPipelineController *logparsingpipeline.LogParsingPipelineController
 
+	DerivedMetricsController *derivedmetrics.Controller
+
+	SpanMetricsController *spanmetrics.Controller
+
 	// SetupCompleted indicates if SigNoz is ready for general use.
 	// at the moment, we mark the app ready when the first user
 	// is registers.
@@ -171,6 +181,12 @@ type APIHandlerOpts struct {
 	// Log parsing pipelines
 	LogsParsingPipelineController *logparsingpipeline.LogParsingPipelineController
 
+	// Metrics derived from logs
+	DerivedMetricsController *derivedmetrics.Controller
+
+	// RED metrics generated from spans
+	SpanMetricsController *spanmetrics.Controller
+
 	// cache
 	Cache cache.Cache
 
@@ -231,6 +247,8 @@ func NewAPIHandler(opts APIHandlerOpts) (*APIHandler, error) {
 		IntegrationsController:        opts.IntegrationsController,
 		CloudIntegrationsController:   opts.CloudIntegrationsController,
 		LogsParsingPipelineController: opts.LogsParsingPipelineController,
+		DerivedMetricsController:      opts.DerivedMetricsController,
+		SpanMetricsController:         opts.SpanMetricsController,
 		querier:                       querier,
 		querierV2:                     querierv2,
 		hostsRepo:                     hostsRepo,
@@ -379,111 +397,111 @@ func writeHttpResponse(w http.ResponseWriter, data interface{}) {
 
 func (aH *APIHandler) RegisterQueryRangeV3Routes(router *mux.Router, am *middleware.AuthZ) {
 	subRouter := router.PathPrefix("/api/v3").Subrouter()
-	subRouter.HandleFunc("/autocomplete/aggregate_attributes", am.ViewAccess(
+	subRouter.HandleFunc("/autocomplete/aggregate_attributes", am.PermissionAccess(authtypes.PermissionTelemetryRead,
 		withCacheControl(AutoCompleteCacheControlAge, aH.autocompleteAggregateAttributes))).Methods(http.MethodGet)
-	subRouter.HandleFunc("/autocomplete/attribute_keys", am.ViewAccess(
+	subRouter.HandleFunc("/autocomplete/attribute_keys", am.PermissionAccess(authtypes.PermissionTelemetryRead,
 		withCacheControl(AutoCompleteCacheControlAge, aH.autoCompleteAttributeKeys))).Methods(http.MethodGet)
-	subRouter.HandleFunc("/autocomplete/attribute_values", am.ViewAccess(
+	subRouter.HandleFunc("/autocomplete/attribute_values", am.PermissionAccess(authtypes.PermissionTelemetryRead,
 		withCacheControl(AutoCompleteCacheControlAge, aH.autoCompleteAttributeValues))).Methods(http.MethodGet)
 
 	// autocomplete with filters using new endpoints
 	// Note: eventually all autocomplete APIs should be migrated to new endpoint with appropriate filters, deprecating the older ones
 
-	subRouter.HandleFunc("/auto_complete/attribute_values", am.ViewAccess(aH.autoCompleteAttributeValuesPost)).Methods(http.MethodPost)
+	subRouter.HandleFunc("/auto_complete/attribute_values", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.autoCompleteAttributeValuesPost)).Methods(http.MethodPost)
 
-	subRouter.HandleFunc("/query_range", am.ViewAccess(aH.QueryRangeV3)).Methods(http.MethodPost)
-	subRouter.HandleFunc("/query_range/format", am.ViewAccess(aH.QueryRangeV3Format)).Methods(http.MethodPost)
+	subRouter.HandleFunc("/query_range", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.QueryRangeV3)).Methods(http.MethodPost)
+	subRouter.HandleFunc("/query_range/format", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.QueryRangeV3Format)).Methods(http.MethodPost)
 
-	subRouter.HandleFunc("/filter_suggestions", am.ViewAccess(aH.getQueryBuilderSuggestions)).Methods(http.MethodGet)
+	subRouter.HandleFunc("/filter_suggestions", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getQueryBuilderSuggestions)).Methods(http.MethodGet)
 
 	// TODO(Raj): Remove this handler after /ws based path has been completely rolled out.
-	subRouter.HandleFunc("/query_progress", am.ViewAccess(aH.GetQueryProgressUpdates)).Methods(http.MethodGet)
+	subRouter.HandleFunc("/query_progress", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.GetQueryProgressUpdates)).Methods(http.MethodGet)
 
 	// live logs
-	subRouter.HandleFunc("/logs/livetail", am.ViewAccess(aH.liveTailLogs)).Methods(http.MethodGet)
+	subRouter.HandleFunc("/logs/livetail", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.liveTailLogs)).Methods(http.MethodGet)
 }
 
 func (aH *APIHandler) RegisterFieldsRoutes(router *mux.Router, am *middleware.AuthZ) {
 	subRouter := router.PathPrefix("/api/v1").Subrouter()
 
-	subRouter.HandleFunc("/fields/keys", am.ViewAccess(aH.FieldsAPI.GetFieldsKeys)).Methods(http.MethodGet)
-	subRouter.HandleFunc("/fields/values", am.ViewAccess(aH.FieldsAPI.GetFieldsValues)).Methods(http.MethodGet)
+	subRouter.HandleFunc("/fields/keys", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.FieldsAPI.GetFieldsKeys)).Methods(http.MethodGet)
+	subRouter.HandleFunc("/fields/values", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.FieldsAPI.GetFieldsValues)).Methods(http.MethodGet)
 }
 
 func (aH *APIHandler) RegisterInfraMetricsRoutes(router *mux.Router, am *middleware.AuthZ) {
 	hostsSubRouter := router.PathPrefix("/api/v1/hosts").Subrouter()
-	hostsSubRouter.HandleFunc("/attribute_keys", am.ViewAccess(aH.getHostAttributeKeys)).Methods(http.MethodGet)
-	hostsSubRouter.HandleFunc("/attribute_values", am.ViewAccess(aH.getHostAttributeValues)).Methods(http.MethodGet)
-	hostsSubRouter.HandleFunc("/list", am.ViewAccess(aH.getHostList)).Methods(http.MethodPost)
+	hostsSubRouter.HandleFunc("/attribute_keys", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getHostAttributeKeys)).Methods(http.MethodGet)
+	hostsSubRouter.HandleFunc("/attribute_values", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getHostAttributeValues)).Methods(http.MethodGet)
+	hostsSubRouter.HandleFunc("/list", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getHostList)).Methods(http.MethodPost)
 
 	processesSubRouter := router.PathPrefix("/api/v1/processes").Subrouter()
-	processesSubRouter.HandleFunc("/attribute_keys", am.ViewAccess(aH.getProcessAttributeKeys)).Methods(http.MethodGet)
-	processesSubRouter.HandleFunc("/attribute_values", am.ViewAccess(aH.getProcessAttributeValues)).Methods(http.MethodGet)
-	processesSubRouter.HandleFunc("/list", am.ViewAccess(aH.getProcessList)).Methods(http.MethodPost)
+	processesSubRouter.HandleFunc("/attribute_keys", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getProcessAttributeKeys)).Methods(http.MethodGet)
+	processesSubRouter.HandleFunc("/attribute_values", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getProcessAttributeValues)).Methods(http.MethodGet)
+	processesSubRouter.HandleFunc("/list", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getProcessList)).Methods(http.MethodPost)
 
 	podsSubRouter := router.PathPrefix("/api/v1/pods").Subrouter()
-	podsSubRouter.HandleFunc("/attribute_keys", am.ViewAccess(aH.getPodAttributeKeys)).Methods(http.MethodGet)
-	podsSubRouter.HandleFunc("/attribute_values", am.ViewAccess(aH.getPodAttributeValues)).Methods(http.MethodGet)
-	podsSubRouter.HandleFunc("/list", am.ViewAccess(aH.getPodList)).Methods(http.MethodPost)
+	podsSubRouter.HandleFunc("/attribute_keys", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getPodAttributeKeys)).Methods(http.MethodGet)
+	podsSubRouter.HandleFunc("/attribute_values", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getPodAttributeValues)).Methods(http.MethodGet)
+	podsSubRouter.HandleFunc("/list", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getPodList)).Methods(http.MethodPost)
 
 	pvcsSubRouter := router.PathPrefix("/api/v1/pvcs").Subrouter()
-	pvcsSubRouter.HandleFunc("/attribute_keys", am.ViewAccess(aH.getPvcAttributeKeys)).Methods(http.MethodGet)
-	pvcsSubRouter.HandleFunc("/attribute_values", am.ViewAccess(aH.getPvcAttributeValues)).Methods(http.MethodGet)
-	pvcsSubRouter.HandleFunc("/list", am.ViewAccess(aH.getPvcList)).Methods(http.MethodPost)
+	pvcsSubRouter.HandleFunc("/attribute_keys", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getPvcAttributeKeys)).Methods(http.MethodGet)
+	pvcsSubRouter.HandleFunc("/attribute_values", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getPvcAttributeValues)).Methods(http.MethodGet)
+	pvcsSubRouter.HandleFunc("/list", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getPvcList)).Methods(http.MethodPost)
 
 	nodesSubRouter := router.PathPrefix("/api/v1/nodes").Subrouter()
-	nodesSubRouter.HandleFunc("/attribute_keys", am.ViewAccess(aH.getNodeAttributeKeys)).Methods(http.MethodGet)
-	nodesSubRouter.HandleFunc("/attribute_values", am.ViewAccess(aH.getNodeAttributeValues)).Methods(http.MethodGet)
-	nodesSubRouter.HandleFunc("/list", am.ViewAccess(aH.getNodeList)).Methods(http.MethodPost)
+	nodesSubRouter.HandleFunc("/attribute_keys", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getNodeAttributeKeys)).Methods(http.MethodGet)
+	nodesSubRouter.HandleFunc("/attribute_values", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getNodeAttributeValues)).Methods(http.MethodGet)
+	nodesSubRouter.HandleFunc("/list", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getNodeList)).Methods(http.MethodPost)
 
 	namespacesSubRouter := router.PathPrefix("/api/v1/namespaces").Subrouter()
-	namespacesSubRouter.HandleFunc("/attribute_keys", am.ViewAccess(aH.getNamespaceAttributeKeys)).Methods(http.MethodGet)
-	namespacesSubRouter.HandleFunc("/attribute_values", am.ViewAccess(aH.getNamespaceAttributeValues)).Methods(http.MethodGet)
-	namespacesSubRouter.HandleFunc("/list", am.ViewAccess(aH.getNamespaceList)).Methods(http.MethodPost)
+	namespacesSubRouter.HandleFunc("/attribute_keys", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getNamespaceAttributeKeys)).Methods(http.MethodGet)
+	namespacesSubRouter.HandleFunc("/attribute_values", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getNamespaceAttributeValues)).Methods(http.MethodGet)
+	namespacesSubRouter.HandleFunc("/list", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getNamespaceList)).Methods(http.MethodPost)
 
 	clustersSubRouter := router.PathPrefix("/api/v1/clusters").Subrouter()
-	clustersSubRouter.HandleFunc("/attribute_keys", am.ViewAccess(aH.getClusterAttributeKeys)).Methods(http.MethodGet)
-	clustersSubRouter.HandleFunc("/attribute_values", am.ViewAccess(aH.getClusterAttributeValues)).Methods(http.MethodGet)
-	clustersSubRouter.HandleFunc("/list", am.ViewAccess(aH.getClusterList)).Methods(http.MethodPost)
+	clustersSubRouter.HandleFunc("/attribute_keys", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getClusterAttributeKeys)).Methods(http.MethodGet)
+	clustersSubRouter.HandleFunc("/attribute_values", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getClusterAttributeValues)).Methods(http.MethodGet)
+	clustersSubRouter.HandleFunc("/list", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getClusterList)).Methods(http.MethodPost)
 
 	deploymentsSubRouter := router.PathPrefix("/api/v1/deployments").Subrouter()
-	deploymentsSubRouter.HandleFunc("/attribute_keys", am.ViewAccess(aH.getDeploymentAttributeKeys)).Methods(http.MethodGet)
-	deploymentsSubRouter.HandleFunc("/attribute_values", am.ViewAccess(aH.getDeploymentAttributeValues)).Methods(http.MethodGet)
-	deploymentsSubRouter.HandleFunc("/list", am.ViewAccess(aH.getDeploymentList)).Methods(http.MethodPost)
+	deploymentsSubRouter.HandleFunc("/attribute_keys", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getDeploymentAttributeKeys)).Methods(http.MethodGet)
+	deploymentsSubRouter.HandleFunc("/attribute_values", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getDeploymentAttributeValues)).Methods(http.MethodGet)
+	deploymentsSubRouter.HandleFunc("/list", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getDeploymentList)).Methods(http.MethodPost)
 
 	daemonsetsSubRouter := router.PathPrefix("/api/v1/daemonsets").Subrouter()
-	daemonsetsSubRouter.HandleFunc("/attribute_keys", am.ViewAccess(aH.getDaemonSetAttributeKeys)).Methods(http.MethodGet)
-	daemonsetsSubRouter.HandleFunc("/attribute_values", am.ViewAccess(aH.getDaemonSetAttributeValues)).Methods(http.MethodGet)
-	daemonsetsSubRouter.HandleFunc("/list", am.ViewAccess(aH.getDaemonSetList)).Methods(http.MethodPost)
+	daemonsetsSubRouter.HandleFunc("/attribute_keys", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getDaemonSetAttributeKeys)).Methods(http.MethodGet)
+	daemonsetsSubRouter.HandleFunc("/attribute_values", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getDaemonSetAttributeValues)).Methods(http.MethodGet)
+	daemonsetsSubRouter.HandleFunc("/list", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getDaemonSetList)).Methods(http.MethodPost)
 
 	statefulsetsSubRouter := router.PathPrefix("/api/v1/statefulsets").Subrouter()
-	statefulsetsSubRouter.HandleFunc("/attribute_keys", am.ViewAccess(aH.getStatefulSetAttributeKeys)).Methods(http.MethodGet)
-	statefulsetsSubRouter.HandleFunc("/attribute_values", am.ViewAccess(aH.getStatefulSetAttributeValues)).Methods(http.MethodGet)
-	statefulsetsSubRouter.HandleFunc("/list", am.ViewAccess(aH.getStatefulSetList)).Methods(http.MethodPost)
+	statefulsetsSubRouter.HandleFunc("/attribute_keys", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getStatefulSetAttributeKeys)).Methods(http.MethodGet)
+	statefulsetsSubRouter.HandleFunc("/attribute_values", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getStatefulSetAttributeValues)).Methods(http.MethodGet)
+	statefulsetsSubRouter.HandleFunc("/list", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getStatefulSetList)).Methods(http.MethodPost)
 
 	jobsSubRouter := router.PathPrefix("/api/v1/jobs").Subrouter()
-	jobsSubRouter.HandleFunc("/attribute_keys", am.ViewAccess(aH.getJobAttributeKeys)).Methods(http.MethodGet)
-	jobsSubRouter.HandleFunc("/attribute_values", am.ViewAccess(aH.getJobAttributeValues)).Methods(http.MethodGet)
-	jobsSubRouter.HandleFunc("/list", am.ViewAccess(aH.getJobList)).Methods(http.MethodPost)
+	jobsSubRouter.HandleFunc("/attribute_keys", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getJobAttributeKeys)).Methods(http.MethodGet)
+	jobsSubRouter.HandleFunc("/attribute_values", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getJobAttributeValues)).Methods(http.MethodGet)
+	jobsSubRouter.HandleFunc("/list", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getJobList)).Methods(http.MethodPost)
 
 	infraOnboardingSubRouter := router.PathPrefix("/api/v1/infra_onboarding").Subrouter()
-	infraOnboardingSubRouter.HandleFunc("/k8s/status", am.ViewAccess(aH.getK8sInfraOnboardingStatus)).Methods(http.MethodGet)
+	infraOnboardingSubRouter.HandleFunc("/k8s/status", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getK8sInfraOnboardingStatus)).Methods(http.MethodGet)
 }
 
 func (aH *APIHandler) RegisterWebSocketPaths(router *mux.Router, am *middleware.AuthZ) {
 	subRouter := router.PathPrefix("/ws").Subrouter()
-	subRouter.HandleFunc("/query_progress", am.ViewAccess(aH.GetQueryProgressUpdates)).Methods(http.MethodGet)
+	subRouter.HandleFunc("/query_progress", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.GetQueryProgressUpdates)).Methods(http.MethodGet)
 }
 
 func (aH *APIHandler) RegisterQueryRangeV4Routes(router *mux.Router, am *middleware.AuthZ) {
 	subRouter := router.PathPrefix("/api/v4").Subrouter()
-	subRouter.HandleFunc("/query_range", am.ViewAccess(aH.QueryRangeV4)).Methods(http.MethodPost)
-	subRouter.HandleFunc("/metric/metric_metadata", am.ViewAccess(aH.getMetricMetadata)).Methods(http.MethodGet)
+	subRouter.HandleFunc("/query_range", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.QueryRangeV4)).Methods(http.MethodPost)
+	subRouter.HandleFunc("/metric/metric_metadata", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getMetricMetadata)).Methods(http.MethodGet)
 }
 
 func (aH *APIHandler) RegisterQueryRangeV5Routes(router *mux.Router, am *middleware.AuthZ) {
 	subRouter := router.PathPrefix("/api/v5").Subrouter()
-	subRouter.HandleFunc("/query_range", am.ViewAccess(aH.QuerierAPI.QueryRange)).Methods(http.MethodPost)
+	subRouter.HandleFunc("/query_range", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.QuerierAPI.QueryRange)).Methods(http.MethodPost)
 }
 
 // todo(remove): Implemented at render package (github.com/SigNoz/signoz/pkg/http/render) with the new error structure
@@ -498,127 +516,246 @@ func (aH *APIHandler) RegisterPrivateRoutes(router *mux.Router) {
 
 // RegisterRoutes registers routes for this handler on the given router
 func (aH *APIHandler) RegisterRoutes(router *mux.Router, am *middleware.AuthZ) {
-	router.HandleFunc("/api/v1/query_range", am.ViewAccess(aH.queryRangeMetrics)).Methods(http.MethodGet)
-	router.HandleFunc("/api/v1/query", am.ViewAccess(aH.queryMetrics)).Methods(http.MethodGet)
-	router.HandleFunc("/api/v1/channels", am.ViewAccess(aH.AlertmanagerAPI.ListChannels)).Methods(http.MethodGet)
-	router.HandleFunc("/api/v1/channels/{id}", am.ViewAccess(aH.AlertmanagerAPI.GetChannelByID)).Methods(http.MethodGet)
-	router.HandleFunc("/api/v1/channels/{id}", am.AdminAccess(aH.AlertmanagerAPI.UpdateChannelByID)).Methods(http.MethodPut)
-	router.HandleFunc("/api/v1/channels/{id}", am.AdminAccess(aH.AlertmanagerAPI.DeleteChannelByID)).Methods(http.MethodDelete)
-	router.HandleFunc("/api/v1/channels", am.EditAccess(aH.AlertmanagerAPI.CreateChannel)).Methods(http.MethodPost)
-	router.HandleFunc("/api/v1/testChannel", am.EditAccess(aH.AlertmanagerAPI.TestReceiver)).Methods(http.MethodPost)
-
-	router.HandleFunc("/api/v1/alerts", am.ViewAccess(aH.AlertmanagerAPI.GetAlerts)).Methods(http.MethodGet)
-
-	router.HandleFunc("/api/v1/rules", am.ViewAccess(aH.listRules)).Methods(http.MethodGet)
-	router.HandleFunc("/api/v1/rules/{id}", am.ViewAccess(aH.getRule)).Methods(http.MethodGet)
-	router.HandleFunc("/api/v1/rules", am.EditAccess(aH.createRule)).Methods(http.MethodPost)
-	router.HandleFunc("/api/v1/rules/{id}", am.EditAccess(aH.editRule)).Methods(http.MethodPut)
-	router.HandleFunc("/api/v1/rules/{id}", am.EditAccess(aH.deleteRule)).Methods(http.MethodDelete)
-	router.HandleFunc("/api/v1/rules/{id}", am.EditAccess(aH.patchRule)).Methods(http.MethodPatch)
-	router.HandleFunc("/api/v1/testRule", am.EditAccess(aH.testRule)).Methods(http.MethodPost)
-	router.HandleFunc("/api/v1/rules/{id}/history/stats", am.ViewAccess(aH.getRuleStats)).Methods(http.MethodPost)
-	router.HandleFunc("/api/v1/rules/{id}/history/timeline", am.ViewAccess(aH.getRuleStateHistory)).Methods(http.MethodPost)
-	router.HandleFunc("/api/v1/rules/{id}/history/top_contributors", am.ViewAccess(aH.getRuleStateHistoryTopContributors)).Methods(http.MethodPost)
-	router.HandleFunc("/api/v1/rules/{id}/history/overall_status", am.ViewAccess(aH.getOverallStateTransitions)).Methods(http.MethodPost)
-
-	router.HandleFunc("/api/v1/downtime_schedules", am.ViewAccess(aH.listDowntimeSchedules)).Methods(http.MethodGet)
-	router.HandleFunc("/api/v1/downtime_schedules/{id}", am.ViewAccess(aH.getDowntimeSchedule)).Methods(http.MethodGet)
-	router.HandleFunc("/api/v1/downtime_schedules", am.EditAccess(aH.createDowntimeSchedule)).Methods(http.MethodPost)
-	router.HandleFunc("/api/v1/downtime_schedules/{id}", am.EditAccess(aH.editDowntimeSchedule)).Methods(http.MethodPut)
-	router.HandleFunc("/api/v1/downtime_schedules/{id}", am.EditAccess(aH.deleteDowntimeSchedule)).Methods(http.MethodDelete)
-
-	router.HandleFunc("/api/v1/dashboards", am.ViewAccess(aH.List)).Methods(http.MethodGet)
-	router.HandleFunc("/api/v1/dashboards", am.EditAccess(aH.Signoz.Handlers.Dashboard.Create)).Methods(http.MethodPost)
-	router.HandleFunc("/api/v1/dashboards/{id}", am.ViewAccess(aH.Get)).Methods(http.MethodGet)
-	router.HandleFunc("/api/v1/dashboards/{id}", am.EditAccess(aH.Signoz.Handlers.Dashboard.Update)).Methods(http.MethodPut)
-	router.HandleFunc("/api/v1/dashboards/{id}", am.EditAccess(aH.Signoz.Handlers.Dashboard.Delete)).Methods(http.MethodDelete)
-	router.HandleFunc("/api/v1/dashboards/{id}/lock", am.EditAccess(aH.Signoz.Handlers.Dashboard.LockUnlock)).Methods(http.MethodPut)
-	router.HandleFunc("/api/v2/variables/query", am.ViewAccess(aH.queryDashboardVarsV2)).Methods(http.MethodPost)
-
-	router.HandleFunc("/api/v1/explorer/views", am.ViewAccess(aH.Signoz.Handlers.SavedView.List)).Methods(http.MethodGet)
-	router.HandleFunc("/api/v1/explorer/views", am.EditAccess(aH.Signoz.Handlers.SavedView.Create)).Methods(http.MethodPost)
-	router.HandleFunc("/api/v1/explorer/views/{viewId}", am.ViewAccess(aH.Signoz.Handlers.SavedView.Get)).Methods(http.MethodGet)
-	router.HandleFunc("/api/v1/explorer/views/{viewId}", am.EditAccess(aH.Signoz.Handlers.SavedView.Update)).Methods(http.MethodPut)
-	router.HandleFunc("/api/v1/explorer/views/{viewId}", am.EditAccess(aH.Signoz.Handlers.SavedView.Delete)).Methods(http.MethodDelete)
+	router.HandleFunc("/api/v1/query_range", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.queryRangeMetrics)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/query", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.queryMetrics)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/channels", am.PermissionAccess(authtypes.PermissionChannelsRead, aH.AlertmanagerAPI.ListChannels)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/channels/{id}", am.PermissionAccess(authtypes.PermissionChannelsRead, aH.AlertmanagerAPI.GetChannelByID)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/channels/{id}", am.PermissionAccess(authtypes.PermissionChannelsManage, aH.unmanaged(provisioningtypes.KindChannel, "id", aH.AlertmanagerAPI.UpdateChannelByID))).Methods(http.MethodPut)
+	router.HandleFunc("/api/v1/channels/{id}", am.PermissionAccess(authtypes.PermissionChannelsManage, aH.unmanaged(provisioningtypes.KindChannel, "id", aH.AlertmanagerAPI.DeleteChannelByID))).Methods(http.MethodDelete)
+	router.HandleFunc("/api/v1/channels", am.PermissionAccess(authtypes.PermissionChannelsWrite, aH.AlertmanagerAPI.CreateChannel)).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/testChannel", am.PermissionAccess(authtypes.PermissionChannelsWrite, aH.AlertmanagerAPI.TestReceiver)).Methods(http.MethodPost)
+
+	router.HandleFunc("/api/v1/alerts", am.PermissionAccess(authtypes.PermissionAlertsRead, aH.AlertmanagerAPI.GetAlerts)).Methods(http.MethodGet)
+
+	router.HandleFunc("/api/v1/rules", am.PermissionAccess(authtypes.PermissionAlertsRead, aH.listRules)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/rules/{id}", am.PermissionAccess(authtypes.PermissionAlertsRead, aH.getRule)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/rules", am.PermissionAccess(authtypes.PermissionAlertsWrite, aH.createRule)).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/rules/{id}", am.PermissionAccess(authtypes.PermissionAlertsWrite, aH.unmanaged(provisioningtypes.KindRule, "id", aH.editRule))).Methods(http.MethodPut)
+	router.HandleFunc("/api/v1/rules/{id}", am.PermissionAccess(authtypes.PermissionAlertsDelete, aH.unmanaged(provisioningtypes.KindRule, "id", aH.deleteRule))).Methods(http.MethodDelete)
+	router.HandleFunc("/api/v1/rules/{id}", am.PermissionAccess(authtypes.PermissionAlertsWrite, aH.unmanaged(provisioningtypes.KindRule, "id", aH.patchRule))).Methods(http.MethodPatch)
+	router.HandleFunc("/api/v1/testRule", am.PermissionAccess(authtypes.PermissionAlertsWrite, aH.testRule)).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/rules/{id}/history/stats", am.PermissionAccess(authtypes.PermissionAlertsRead, aH.getRuleStats)).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/rules/{id}/history/timeline", am.PermissionAccess(authtypes.PermissionAlertsRead, aH.getRuleStateHistory)).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/rules/{id}/history/top_contributors", am.PermissionAccess(authtypes.PermissionAlertsRead, aH.getRuleStateHistoryTopContributors)).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/rules/{id}/history/overall_status", am.PermissionAccess(authtypes.PermissionAlertsRead, aH.getOverallStateTransitions)).Methods(http.MethodPost)
+
+	router.HandleFunc("/api/v1/downtime_schedules", am.PermissionAccess(authtypes.PermissionAlertsRead, aH.listDowntimeSchedules)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/downtime_schedules/{id}", am.PermissionAccess(authtypes.PermissionAlertsRead, aH.getDowntimeSchedule)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/downtime_schedules", am.PermissionAccess(authtypes.PermissionAlertsSilence, aH.createDowntimeSchedule)).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/downtime_schedules/{id}", am.PermissionAccess(authtypes.PermissionAlertsSilence, aH.editDowntimeSchedule)).Methods(http.MethodPut)
+	router.HandleFunc("/api/v1/downtime_schedules/{id}", am.PermissionAccess(authtypes.PermissionAlertsSilence, aH.deleteDowntimeSchedule)).Methods(http.MethodDelete)
+
+	router.HandleFunc("/api/v1/dashboards", am.PermissionAccess(authtypes.PermissionDashboardsRead, aH.List)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/dashboards", am.PermissionAccess(authtypes.PermissionDashboardsWrite, aH.Signoz.Handlers.Dashboard.Create)).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/dashboards/schema", am.PermissionAccess(authtypes.PermissionDashboardsRead, aH.Signoz.Handlers.Dashboard.Schema)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/dashboards/import/grafana", am.PermissionAccess(authtypes.PermissionDashboardsWrite, aH.Signoz.Handlers.Dashboard.ImportGrafana)).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/dashboards/schema/upgrade", am.PermissionAccess(authtypes.PermissionDashboardsRead, aH.Signoz.Handlers.Dashboard.Upgrade)).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/dashboards/{id}", am.PermissionAccess(authtypes.PermissionDashboardsRead, aH.Get)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/dashboards/{id}", am.PermissionAccess(authtypes.PermissionDashboardsWrite, aH.unmanaged(provisioningtypes.KindDashboard, "id", aH.Signoz.Handlers.Dashboard.Update))).Methods(http.MethodPut)
+	router.HandleFunc("/api/v1/dashboards/{id}", am.PermissionAccess(authtypes.PermissionDashboardsDelete, aH.unmanaged(provisioningtypes.KindDashboard, "id", aH.Signoz.Handlers.Dashboard.Delete))).Methods(http.MethodDelete)
+	router.HandleFunc("/api/v1/dashboards/{id}/lock", am.PermissionAccess(authtypes.PermissionDashboardsWrite, aH.unmanaged(provisioningtypes.KindDashboard, "id", aH.Signoz.Handlers.Dashboard.LockUnlock))).Methods(http.MethodPut)
+	router.HandleFunc("/api/v1/dashboards/{id}/revisions", am.PermissionAccess(authtypes.PermissionDashboardsRead, aH.Signoz.Handlers.Dashboard.ListRevisions)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/dashboards/{id}/revisions/diff", am.PermissionAccess(authtypes.PermissionDashboardsRead, aH.Signoz.Handlers.Dashboard.DiffRevisions)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/dashboards/{id}/revisions/{version:[0-9]+}", am.PermissionAccess(authtypes.PermissionDashboardsRead, aH.Signoz.Handlers.Dashboard.GetRevision)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/dashboards/{id}/revisions/{version:[0-9]+}/restore", am.PermissionAccess(authtypes.PermissionDashboardsWrite, aH.unmanaged(provisioningtypes.KindDashboard, "id", aH.Signoz.Handlers.Dashboard.RestoreRevision))).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/dashboards/{id}/grants", am.PermissionAccess(authtypes.PermissionDashboardsRead, aH.Signoz.Handlers.Dashboard.GetGrants)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/dashboards/{id}/grants", am.PermissionAccess(authtypes.PermissionDashboardsWrite, aH.Signoz.Handlers.Dashboard.SetGrants)).Methods(http.MethodPut)
+	router.HandleFunc("/api/v1/dashboards/{id}/folder", am.PermissionAccess(authtypes.PermissionDashboardsWrite, aH.Signoz.Handlers.Dashboard.Move)).Methods(http.MethodPut)
+
+	// Dashboard folders, the dashboards of a folder inherit its grants
+	router.HandleFunc("/api/v1/dashboard_folders", am.PermissionAccess(authtypes.PermissionDashboardsRead, aH.Signoz.Handlers.Dashboard.ListFolders)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/dashboard_folders", am.PermissionAccess(authtypes.PermissionDashboardsWrite, aH.Signoz.Handlers.Dashboard.CreateFolder)).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/dashboard_folders/{id}", am.PermissionAccess(authtypes.PermissionDashboardsRead, aH.Signoz.Handlers.Dashboard.GetFolder)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/dashboard_folders/{id}", am.PermissionAccess(authtypes.PermissionDashboardsWrite, aH.Signoz.Handlers.Dashboard.UpdateFolder)).Methods(http.MethodPut)
+	router.HandleFunc("/api/v1/dashboard_folders/{id}", am.PermissionAccess(authtypes.PermissionDashboardsDelete, aH.Signoz.Handlers.Dashboard.DeleteFolder)).Methods(http.MethodDelete)
+	router.HandleFunc("/api/v1/dashboard_folders/{id}/grants", am.PermissionAccess(authtypes.PermissionDashboardsRead, aH.Signoz.Handlers.Dashboard.GetFolderGrants)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/dashboard_folders/{id}/grants", am.PermissionAccess(authtypes.PermissionDashboardsWrite, aH.Signoz.Handlers.Dashboard.SetFolderGrants)).Methods(http.MethodPut)
+	router.HandleFunc("/api/v2/variables/query", am.PermissionAccess(authtypes.PermissionDashboardsRead, aH.queryDashboardVarsV2)).Methods(http.MethodPost)
+
+	router.HandleFunc("/api/v1/explorer/views", am.PermissionAccess(authtypes.PermissionSavedViewsRead, aH.Signoz.Handlers.SavedView.List)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/explorer/views", am.PermissionAccess(authtypes.PermissionSavedViewsWrite, aH.Signoz.Handlers.SavedView.Create)).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/explorer/views/{viewId}", am.PermissionAccess(authtypes.PermissionSavedViewsRead, aH.Signoz.Handlers.SavedView.Get)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/explorer/views/{viewId}", am.PermissionAccess(authtypes.PermissionSavedViewsWrite, aH.unmanaged(provisioningtypes.KindSavedView, "viewId", aH.Signoz.Handlers.SavedView.Update))).Methods(http.MethodPut)
+	router.HandleFunc("/api/v1/explorer/views/{viewId}", am.PermissionAccess(authtypes.PermissionSavedViewsWrite, aH.unmanaged(provisioningtypes.KindSavedView, "viewId", aH.Signoz.Handlers.SavedView.Delete))).Methods(http.MethodDelete)
+	router.HandleFunc("/api/v1/explorer/views/{viewId}/usage", am.PermissionAccess(authtypes.PermissionSavedViewsRead, aH.Signoz.Handlers.SavedView.RecordUsage)).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/explorer/views/{viewId}/default", am.PermissionAccess(authtypes.PermissionSavedViewsRead, aH.Signoz.Handlers.SavedView.SetDefault)).Methods(http.MethodPut)
+	router.HandleFunc("/api/v1/explorer/views/{viewId}/default", am.PermissionAccess(authtypes.PermissionSavedViewsRead, aH.Signoz.Handlers.SavedView.UnsetDefault)).Methods(http.MethodDelete)
 
 	router.HandleFunc("/api/v1/feedback", am.OpenAccess(aH.submitFeedback)).Methods(http.MethodPost)
 	router.HandleFunc("/api/v1/event", am.ViewAccess(aH.registerEvent)).Methods(http.MethodPost)
 
-	router.HandleFunc("/api/v1/services", am.ViewAccess(aH.getServices)).Methods(http.MethodPost)
-	router.HandleFunc("/api/v1/services/list", am.ViewAccess(aH.getServicesList)).Methods(http.MethodGet)
-	router.HandleFunc("/api/v1/service/top_operations", am.ViewAccess(aH.getTopOperations)).Methods(http.MethodPost)
-	router.HandleFunc("/api/v1/service/top_level_operations", am.ViewAccess(aH.getServicesTopLevelOps)).Methods(http.MethodPost)
-	router.HandleFunc("/api/v1/service/entry_point_operations", am.ViewAccess(aH.getEntryPointOps)).Methods(http.MethodPost)
-	router.HandleFunc("/api/v1/traces/{traceId}", am.ViewAccess(aH.SearchTraces)).Methods(http.MethodGet)
-	router.HandleFunc("/api/v1/usage", am.ViewAccess(aH.getUsage)).Methods(http.MethodGet)
-	router.HandleFunc("/api/v1/dependency_graph", am.ViewAccess(aH.dependencyGraph)).Methods(http.MethodPost)
-	router.HandleFunc("/api/v1/settings/ttl", am.AdminAccess(aH.setTTL)).Methods(http.MethodPost)
-	router.HandleFunc("/api/v1/settings/ttl", am.ViewAccess(aH.getTTL)).Methods(http.MethodGet)
-	router.HandleFunc("/api/v1/settings/apdex", am.AdminAccess(aH.Signoz.Handlers.Apdex.Set)).Methods(http.MethodPost)
-	router.HandleFunc("/api/v1/settings/apdex", am.ViewAccess(aH.Signoz.Handlers.Apdex.Get)).Methods(http.MethodGet)
-
-	router.HandleFunc("/api/v2/traces/fields", am.ViewAccess(aH.traceFields)).Methods(http.MethodGet)
-	router.HandleFunc("/api/v2/traces/fields", am.EditAccess(aH.updateTraceField)).Methods(http.MethodPost)
-	router.HandleFunc("/api/v2/traces/flamegraph/{traceId}", am.ViewAccess(aH.GetFlamegraphSpansForTrace)).Methods(http.MethodPost)
-	router.HandleFunc("/api/v2/traces/waterfall/{traceId}", am.ViewAccess(aH.GetWaterfallSpansForTraceWithMetadata)).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/services", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getServices)).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/services/list", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getServicesList)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/service/top_operations", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getTopOperations)).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/service/top_level_operations", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getServicesTopLevelOps)).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/service/entry_point_operations", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getEntryPointOps)).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/traces/{traceId}", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.SearchTraces)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/usage", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getUsage)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/dependency_graph", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.dependencyGraph)).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/settings/ttl", am.PermissionAccess(authtypes.PermissionOrgManage, aH.setTTL)).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/settings/ttl", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getTTL)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/settings/apdex", am.PermissionAccess(authtypes.PermissionOrgManage, aH.Signoz.Handlers.Apdex.Set)).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/settings/apdex", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.Signoz.Handlers.Apdex.Get)).Methods(http.MethodGet)
+
+	router.HandleFunc("/api/v2/traces/fields", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.traceFields)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v2/traces/fields", am.PermissionAccess(authtypes.PermissionTelemetryWrite, aH.updateTraceField)).Methods(http.MethodPost)
+	router.HandleFunc("/api/v2/traces/flamegraph/{traceId}", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.GetFlamegraphSpansForTrace)).Methods(http.MethodPost)
+	router.HandleFunc("/api/v2/traces/waterfall/{traceId}", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.GetWaterfallSpansForTraceWithMetadata)).Methods(http.MethodPost)
 
 	router.HandleFunc("/api/v1/version", am.OpenAccess(aH.getVersion)).Methods(http.MethodGet)
 	router.HandleFunc("/api/v1/features", am.ViewAccess(aH.getFeatureFlags)).Methods(http.MethodGet)
 	router.HandleFunc("/api/v1/health", am.OpenAccess(aH.getHealth)).Methods(http.MethodGet)
 
-	router.HandleFunc("/api/v1/listErrors", am.ViewAccess(aH.listErrors)).Methods(http.MethodPost)
-	router.HandleFunc("/api/v1/countErrors", am.ViewAccess(aH.countErrors)).Methods(http.MethodPost)
-	router.HandleFunc("/api/v1/errorFromErrorID", am.ViewAccess(aH.getErrorFromErrorID)).Methods(http.MethodGet)
-	router.HandleFunc("/api/v1/errorFromGroupID", am.ViewAccess(aH.getErrorFromGroupID)).Methods(http.MethodGet)
-	router.HandleFunc("/api/v1/nextPrevErrorIDs", am.ViewAccess(aH.getNextPrevErrorIDs)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/listErrors", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.listErrors)).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/countErrors", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.countErrors)).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/errorFromErrorID", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getErrorFromErrorID)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/errorFromGroupID", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getErrorFromGroupID)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/nextPrevErrorIDs", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getNextPrevErrorIDs)).Methods(http.MethodGet)
 
-	router.HandleFunc("/api/v1/disks", am.ViewAccess(aH.getDisks)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/disks", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getDisks)).Methods(http.MethodGet)
 
 	router.HandleFunc("/api/v1/user/preferences", am.ViewAccess(aH.Signoz.Handlers.Preference.ListByUser)).Methods(http.MethodGet)
 	router.HandleFunc("/api/v1/user/preferences/{name}", am.ViewAccess(aH.Signoz.Handlers.Preference.GetByUser)).Methods(http.MethodGet)
 	router.HandleFunc("/api/v1/user/preferences/{name}", am.ViewAccess(aH.Signoz.Handlers.Preference.UpdateByUser)).Methods(http.MethodPut)
-	router.HandleFunc("/api/v1/org/preferences", am.AdminAccess(aH.Signoz.Handlers.Preference.ListByOrg)).Methods(http.MethodGet)
-	router.HandleFunc("/api/v1/org/preferences/{name}", am.AdminAccess(aH.Signoz.Handlers.Preference.GetByOrg)).Methods(http.MethodGet)
-	router.HandleFunc("/api/v1/org/preferences/{name}", am.AdminAccess(aH.Signoz.Handlers.Preference.UpdateByOrg)).Methods(http.MethodPut)
+	router.HandleFunc("/api/v1/org/preferences", am.PermissionAccess(authtypes.PermissionOrgManage, aH.Signoz.Handlers.Preference.ListByOrg)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/org/preferences/{name}", am.PermissionAccess(authtypes.PermissionOrgManage, aH.Signoz.Handlers.Preference.GetByOrg)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/org/preferences/{name}", am.PermissionAccess(authtypes.PermissionOrgManage, aH.Signoz.Handlers.Preference.UpdateByOrg)).Methods(http.MethodPut)
+
+	// Audit
+	router.HandleFunc("/api/v1/audit/events", am.PermissionAccess(authtypes.PermissionAuditRead, aH.Signoz.Handlers.Audit.List)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/audit/events/export", am.PermissionAccess(authtypes.PermissionAuditRead, aH.Signoz.Handlers.Audit.Export)).Methods(http.MethodGet)
+
+	// Teams, maintainers of a team manage it along with the holders of the teams:manage permission
+	router.HandleFunc("/api/v1/teams", am.ViewAccess(aH.Signoz.Handlers.Team.List)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/teams", am.PermissionAccess(authtypes.PermissionTeamsManage, aH.Signoz.Handlers.Team.Create)).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/teams/{id}", am.ViewAccess(aH.Signoz.Handlers.Team.Get)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/teams/{id}", am.ViewAccess(aH.Signoz.Handlers.Team.Update)).Methods(http.MethodPut)
+	router.HandleFunc("/api/v1/teams/{id}", am.PermissionAccess(authtypes.PermissionTeamsManage, aH.Signoz.Handlers.Team.Delete)).Methods(http.MethodDelete)
+	router.HandleFunc("/api/v1/teams/{id}/members", am.ViewAccess(aH.Signoz.Handlers.Team.ListMembers)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/teams/{id}/members", am.ViewAccess(aH.Signoz.Handlers.Team.AddMember)).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/teams/{id}/members/{userId}", am.ViewAccess(aH.Signoz.Handlers.Team.UpdateMember)).Methods(http.MethodPut)
+	router.HandleFunc("/api/v1/teams/{id}/members/{userId}", am.ViewAccess(aH.Signoz.Handlers.Team.RemoveMember)).Methods(http.MethodDelete)
+	router.HandleFunc("/api/v1/teams/{id}/ownerships", am.ViewAccess(aH.Signoz.Handlers.Team.SetOwnership)).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/teams/{id}/ownerships/{resourceType}/{resourceId}", am.ViewAccess(aH.Signoz.Handlers.Team.DeleteOwnership)).Methods(http.MethodDelete)
+
+	// Provisioning, the resources managed by bundles are only changed by applying bundles
+	router.HandleFunc("/api/v1/provisioning/apply", am.PermissionAccess(authtypes.PermissionProvisionApply, aH.applyBundle)).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/provisioning/managed", am.PermissionAccess(authtypes.PermissionProvisionApply, aH.Signoz.Handlers.Provisioning.ListManaged)).Methods(http.MethodGet)
+
+	// Share links, the public routes are authenticated with the token of a link instead of a user
+	share := middleware.NewShare(aH.Signoz.Modules.Share, []string{sharetypes.HeaderToken})
+	router.HandleFunc("/api/v1/share_links", am.PermissionAccess(authtypes.PermissionShareLinksManage, aH.Signoz.Handlers.Share.Create)).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/share_links", am.PermissionAccess(authtypes.PermissionShareLinksManage, aH.Signoz.Handlers.Share.List)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/share_links/{id}", am.PermissionAccess(authtypes.PermissionShareLinksManage, aH.Signoz.Handlers.Share.Revoke)).Methods(http.MethodDelete)
+	router.HandleFunc("/api/v1/public/share", share.Access(aH.Signoz.Handlers.Share.GetShared)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/public/share/unlock", am.OpenAccess(aH.Signoz.Handlers.Share.Unlock)).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/public/share/query_range", share.Access(aH.sharedQueryRange)).Methods(http.MethodPost)
+
+	// Reports, sending a report queries its panels and emails it right away
+	router.HandleFunc("/api/v1/reports", am.PermissionAccess(authtypes.PermissionReportsManage, aH.Signoz.Handlers.Report.Create)).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/reports", am.PermissionAccess(authtypes.PermissionReportsManage, aH.Signoz.Handlers.Report.List)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/reports/{id}", am.PermissionAccess(authtypes.PermissionReportsManage, aH.Signoz.Handlers.Report.Get)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/reports/{id}", am.PermissionAccess(authtypes.PermissionReportsManage, aH.Signoz.Handlers.Report.Update)).Methods(http.MethodPut)
+	router.HandleFunc("/api/v1/reports/{id}", am.PermissionAccess(authtypes.PermissionReportsManage, aH.Signoz.Handlers.Report.Delete)).Methods(http.MethodDelete)
+	router.HandleFunc("/api/v1/reports/{id}/deliveries", am.PermissionAccess(authtypes.PermissionReportsManage, aH.Signoz.Handlers.Report.ListDeliveries)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/reports/{id}/send", am.PermissionAccess(authtypes.PermissionReportsManage, aH.Signoz.Handlers.Report.Send)).Methods(http.MethodPost)
+
+	// Search across dashboards, saved views and alert rules, favorites are kept per user
+	router.HandleFunc("/api/v1/search", am.ViewAccess(aH.Signoz.Handlers.Search.Search)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/favorites/{type}/{id}", am.ViewAccess(aH.Signoz.Handlers.Search.Favorite)).Methods(http.MethodPut)
+	router.HandleFunc("/api/v1/favorites/{type}/{id}", am.ViewAccess(aH.Signoz.Handlers.Search.Unfavorite)).Methods(http.MethodDelete)
+
+	// Annotations overlaid on the charts, deploys are created by CI pipelines with api keys
+	router.HandleFunc("/api/v1/annotations", am.PermissionAccess(authtypes.PermissionDashboardsRead, aH.Signoz.Handlers.Annotation.List)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/annotations", am.PermissionAccess(authtypes.PermissionDashboardsWrite, aH.Signoz.Handlers.Annotation.Create)).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/annotations/deploys", am.PermissionAccess(authtypes.PermissionDashboardsWrite, aH.Signoz.Handlers.Annotation.CreateDeploy)).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/annotations/{id}", am.PermissionAccess(authtypes.PermissionDashboardsRead, aH.Signoz.Handlers.Annotation.Get)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/annotations/{id}", am.PermissionAccess(authtypes.PermissionDashboardsWrite, aH.Signoz.Handlers.Annotation.Update)).Methods(http.MethodPut)
+	router.HandleFunc("/api/v1/annotations/{id}", am.PermissionAccess(authtypes.PermissionDashboardsWrite, aH.Signoz.Handlers.Annotation.Delete)).Methods(http.MethodDelete)
 
 	// Quick Filters
-	router.HandleFunc("/api/v1/orgs/me/filters", am.ViewAccess(aH.Signoz.Handlers.QuickFilter.GetQuickFilters)).Methods(http.MethodGet)
-	router.HandleFunc("/api/v1/orgs/me/filters/{signal}", am.ViewAccess(aH.Signoz.Handlers.QuickFilter.GetSignalFilters)).Methods(http.MethodGet)
-	router.HandleFunc("/api/v1/orgs/me/filters", am.AdminAccess(aH.Signoz.Handlers.QuickFilter.UpdateQuickFilters)).Methods(http.MethodPut)
+	router.HandleFunc("/api/v1/orgs/me/filters", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.Signoz.Handlers.QuickFilter.GetQuickFilters)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/orgs/me/filters/{signal}", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.Signoz.Handlers.QuickFilter.GetSignalFilters)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/orgs/me/filters", am.PermissionAccess(authtypes.PermissionOrgManage, aH.Signoz.Handlers.QuickFilter.UpdateQuickFilters)).Methods(http.MethodPut)
 
 	// === Authentication APIs ===
-	router.HandleFunc("/api/v1/invite", am.AdminAccess(aH.Signoz.Handlers.User.CreateInvite)).Methods(http.MethodPost)
-	router.HandleFunc("/api/v1/invite/bulk", am.AdminAccess(aH.Signoz.Handlers.User.CreateBulkInvite)).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/invite", am.PermissionAccess(authtypes.PermissionUsersManage, aH.Signoz.Handlers.User.CreateInvite)).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/invite/bulk", am.PermissionAccess(authtypes.PermissionUsersManage, aH.Signoz.Handlers.User.CreateBulkInvite)).Methods(http.MethodPost)
 	router.HandleFunc("/api/v1/invite/{token}", am.OpenAccess(aH.Signoz.Handlers.User.GetInvite)).Methods(http.MethodGet)
-	router.HandleFunc("/api/v1/invite/{id}", am.AdminAccess(aH.Signoz.Handlers.User.DeleteInvite)).Methods(http.MethodDelete)
-	router.HandleFunc("/api/v1/invite", am.AdminAccess(aH.Signoz.Handlers.User.ListInvite)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/invite/{id}", am.PermissionAccess(authtypes.PermissionUsersManage, aH.Signoz.Handlers.User.DeleteInvite)).Methods(http.MethodDelete)
+	router.HandleFunc("/api/v1/invite", am.PermissionAccess(authtypes.PermissionUsersManage, aH.Signoz.Handlers.User.ListInvite)).Methods(http.MethodGet)
 	router.HandleFunc("/api/v1/invite/accept", am.OpenAccess(aH.Signoz.Handlers.User.AcceptInvite)).Methods(http.MethodPost)
 
 	router.HandleFunc("/api/v1/register", am.OpenAccess(aH.registerUser)).Methods(http.MethodPost)
 	router.HandleFunc("/api/v1/login", am.OpenAccess(aH.Signoz.Handlers.User.Login)).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/login/mfa", am.OpenAccess(aH.Signoz.Handlers.User.VerifyMFAChallenge)).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/login/mfa/totp", am.OpenAccess(aH.Signoz.Handlers.User.EnrollTOTPWithChallenge)).Methods(http.MethodPost)
 	router.HandleFunc("/api/v1/loginPrecheck", am.OpenAccess(aH.Signoz.Handlers.User.LoginPrecheck)).Methods(http.MethodGet)
-	router.HandleFunc("/api/v1/complete/google", am.OpenAccess(aH.receiveGoogleAuth)).Methods(http.MethodGet)
-
-	router.HandleFunc("/api/v1/domains", am.AdminAccess(aH.Signoz.Handlers.User.ListDomains)).Methods(http.MethodGet)
-	router.HandleFunc("/api/v1/domains", am.AdminAccess(aH.Signoz.Handlers.User.CreateDomain)).Methods(http.MethodPost)
-	router.HandleFunc("/api/v1/domains/{id}", am.AdminAccess(aH.Signoz.Handlers.User.UpdateDomain)).Methods(http.MethodPut)
-	router.HandleFunc("/api/v1/domains/{id}", am.AdminAccess(aH.Signoz.Handlers.User.DeleteDomain)).Methods(http.MethodDelete)
-
-	router.HandleFunc("/api/v1/pats", am.AdminAccess(aH.Signoz.Handlers.User.CreateAPIKey)).Methods(http.MethodPost)
-	router.HandleFunc("/api/v1/pats", am.AdminAccess(aH.Signoz.Handlers.User.ListAPIKeys)).Methods(http.MethodGet)
-	router.HandleFunc("/api/v1/pats/{id}", am.AdminAccess(aH.Signoz.Handlers.User.UpdateAPIKey)).Methods(http.MethodPut)
-	router.HandleFunc("/api/v1/pats/{id}", am.AdminAccess(aH.Signoz.Handlers.User.RevokeAPIKey)).Methods(http.MethodDelete)
-
-	router.HandleFunc("/api/v1/user", am.AdminAccess(aH.Signoz.Handlers.User.ListUsers)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/complete/google", am.OpenAccess(aH.receiveOAuth)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/complete/oidc", am.OpenAccess(aH.receiveOAuth)).Methods(http.MethodGet)
+
+	router.HandleFunc("/api/v1/domains", am.PermissionAccess(authtypes.PermissionOrgManage, aH.Signoz.Handlers.User.ListDomains)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/domains", am.PermissionAccess(authtypes.PermissionOrgManage, aH.Signoz.Handlers.User.CreateDomain)).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/domains/{id}", am.PermissionAccess(authtypes.PermissionOrgManage, aH.Signoz.Handlers.User.UpdateDomain)).Methods(http.MethodPut)
+	router.HandleFunc("/api/v1/domains/{id}", am.PermissionAccess(authtypes.PermissionOrgManage, aH.Signoz.Handlers.User.DeleteDomain)).Methods(http.MethodDelete)
+
+	router.HandleFunc("/api/v1/pats", am.PermissionAccess(authtypes.PermissionAPIKeysManage, aH.Signoz.Handlers.User.CreateAPIKey)).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/pats", am.PermissionAccess(authtypes.PermissionAPIKeysManage, aH.Signoz.Handlers.User.ListAPIKeys)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/pats/{id}", am.PermissionAccess(authtypes.PermissionAPIKeysManage, aH.Signoz.Handlers.User.UpdateAPIKey)).Methods(http.MethodPut)
+	router.HandleFunc("/api/v1/pats/{id}", am.PermissionAccess(authtypes.PermissionAPIKeysManage, aH.Signoz.Handlers.User.RevokeAPIKey)).Methods(http.MethodDelete)
+
+	router.HandleFunc("/api/v1/roles", am.PermissionAccess(authtypes.PermissionRolesManage, aH.Signoz.Handlers.Role.List)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/roles", am.PermissionAccess(authtypes.PermissionRolesManage, aH.Signoz.Handlers.Role.Create)).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/roles/{id}", am.PermissionAccess(authtypes.PermissionRolesManage, aH.Signoz.Handlers.Role.Get)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/roles/{id}", am.PermissionAccess(authtypes.PermissionRolesManage, aH.Signoz.Handlers.Role.Update)).Methods(http.MethodPut)
+	router.HandleFunc("/api/v1/roles/{id}", am.PermissionAccess(authtypes.PermissionRolesManage, aH.Signoz.Handlers.Role.Delete)).Methods(http.MethodDelete)
+	router.HandleFunc("/api/v1/roles/{id}/subjects", am.PermissionAccess(authtypes.PermissionRolesManage, aH.Signoz.Handlers.Role.Assign)).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/roles/{id}/subjects/{subjectType}/{subjectId}", am.PermissionAccess(authtypes.PermissionRolesManage, aH.Signoz.Handlers.Role.Unassign)).Methods(http.MethodDelete)
+	router.HandleFunc("/api/v1/permissions", am.ViewAccess(aH.Signoz.Handlers.Role.ListPermissions)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/permissions/me", am.ViewAccess(aH.Signoz.Handlers.Role.GetMyPermissions)).Methods(http.MethodGet)
+
+	// provisioning tokens can create admins, so only admins can manage them and not holders of users:manage
+	router.HandleFunc("/api/v1/scim/tokens", am.AdminAccess(aH.Signoz.Handlers.SCIM.ListTokens)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/scim/tokens", am.AdminAccess(aH.Signoz.Handlers.SCIM.CreateToken)).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/scim/tokens/{id}", am.AdminAccess(aH.Signoz.Handlers.SCIM.DeleteToken)).Methods(http.MethodDelete)
+
+	// the SCIM endpoints are called by directories, authenticated with a provisioning token
+	scim := aH.Signoz.Handlers.SCIM
+	router.HandleFunc("/api/v1/scim/v2/ServiceProviderConfig", scim.Authenticate(scim.GetServiceProviderConfig)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/scim/v2/Users", scim.Authenticate(scim.ListUsers)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/scim/v2/Users", scim.Authenticate(scim.CreateUser)).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/scim/v2/Users/{id}", scim.Authenticate(scim.GetUser)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/scim/v2/Users/{id}", scim.Authenticate(scim.ReplaceUser)).Methods(http.MethodPut)
+	router.HandleFunc("/api/v1/scim/v2/Users/{id}", scim.Authenticate(scim.PatchUser)).Methods(http.MethodPatch)
+	router.HandleFunc("/api/v1/scim/v2/Users/{id}", scim.Authenticate(scim.DeleteUser)).Methods(http.MethodDelete)
+	router.HandleFunc("/api/v1/scim/v2/Groups", scim.Authenticate(scim.ListGroups)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/scim/v2/Groups", scim.Authenticate(scim.CreateGroup)).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/scim/v2/Groups/{id}", scim.Authenticate(scim.GetGroup)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/scim/v2/Groups/{id}", scim.Authenticate(scim.ReplaceGroup)).Methods(http.MethodPut)
+	router.HandleFunc("/api/v1/scim/v2/Groups/{id}", scim.Authenticate(scim.PatchGroup)).Methods(http.MethodPatch)
+	router.HandleFunc("/api/v1/scim/v2/Groups/{id}", scim.Authenticate(scim.DeleteGroup)).Methods(http.MethodDelete)
+
+	router.HandleFunc("/api/v1/user", am.PermissionAccess(authtypes.PermissionUsersManage, aH.Signoz.Handlers.User.ListUsers)).Methods(http.MethodGet)
 	router.HandleFunc("/api/v1/user/me", am.OpenAccess(aH.Signoz.Handlers.User.GetCurrentUserFromJWT)).Methods(http.MethodGet)
 	router.HandleFunc("/api/v1/user/{id}", am.SelfAccess(aH.Signoz.Handlers.User.GetUser)).Methods(http.MethodGet)
 	router.HandleFunc("/api/v1/user/{id}", am.SelfAccess(aH.Signoz.Handlers.User.UpdateUser)).Methods(http.MethodPut)
-	router.HandleFunc("/api/v1/user/{id}", am.AdminAccess(aH.Signoz.Handlers.User.DeleteUser)).Methods(http.MethodDelete)
-
-	router.HandleFunc("/api/v2/orgs/me", am.AdminAccess(aH.Signoz.Handlers.Organization.Get)).Methods(http.MethodGet)
-	router.HandleFunc("/api/v2/orgs/me", am.AdminAccess(aH.Signoz.Handlers.Organization.Update)).Methods(http.MethodPut)
-
-	router.HandleFunc("/api/v1/getResetPasswordToken/{id}", am.AdminAccess(aH.Signoz.Handlers.User.GetResetPasswordToken)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/user/{id}", am.PermissionAccess(authtypes.PermissionUsersManage, aH.Signoz.Handlers.User.DeleteUser)).Methods(http.MethodDelete)
+	router.HandleFunc("/api/v1/user/{id}/mfa", am.PermissionAccess(authtypes.PermissionUsersManage, aH.Signoz.Handlers.User.ResetMFA)).Methods(http.MethodDelete)
+	router.HandleFunc("/api/v1/user/me/mfa", am.ViewAccess(aH.Signoz.Handlers.User.GetMFAStatus)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/user/me/mfa/totp", am.ViewAccess(aH.Signoz.Handlers.User.EnrollTOTP)).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/user/me/mfa/totp/confirm", am.ViewAccess(aH.Signoz.Handlers.User.ConfirmTOTP)).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/user/me/mfa/totp/disable", am.ViewAccess(aH.Signoz.Handlers.User.DisableTOTP)).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/user/me/mfa/recovery_codes", am.ViewAccess(aH.Signoz.Handlers.User.RegenerateRecoveryCodes)).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/user/me/orgs", am.ViewAccess(aH.Signoz.Handlers.User.ListMyOrgs)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/user/me/orgs/switch", am.ViewAccess(aH.Signoz.Handlers.User.SwitchOrg)).Methods(http.MethodPost)
+	router.HandleFunc("/api/v1/user/me/sessions", am.ViewAccess(aH.Signoz.Handlers.User.ListMySessions)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/user/me/sessions/{id}", am.ViewAccess(aH.Signoz.Handlers.User.RevokeMySession)).Methods(http.MethodDelete)
+	router.HandleFunc("/api/v1/user/{id}/sessions", am.PermissionAccess(authtypes.PermissionUsersManage, aH.Signoz.Handlers.User.RevokeSessions)).Methods(http.MethodDelete)
+
+	router.HandleFunc("/api/v2/orgs/me", am.PermissionAccess(authtypes.PermissionOrgManage, aH.Signoz.Handlers.Organization.Get)).Methods(http.MethodGet)
+	router.HandleFunc("/api/v2/orgs/me", am.PermissionAccess(authtypes.PermissionOrgManage, aH.Signoz.Handlers.Organization.Update)).Methods(http.MethodPut)
+
+	router.HandleFunc("/api/v1/getResetPasswordToken/{id}", am.PermissionAccess(authtypes.PermissionUsersManage, aH.Signoz.Handlers.User.GetResetPasswordToken)).Methods(http.MethodGet)
 	router.HandleFunc("/api/v1/resetPassword", am.OpenAccess(aH.Signoz.Handlers.User.ResetPassword)).Methods(http.MethodPost)
 	router.HandleFunc("/api/v1/changePassword/{id}", am.SelfAccess(aH.Signoz.Handlers.User.ChangePassword)).Methods(http.MethodPost)
 
@@ -632,28 +769,28 @@ func (aH *APIHandler) RegisterRoutes(router *mux.Router, am *middleware.AuthZ) {
 
 func (ah *APIHandler) MetricExplorerRoutes(router *mux.Router, am *middleware.AuthZ) {
 	router.HandleFunc("/api/v1/metrics/filters/keys",
-		am.ViewAccess(ah.FilterKeysSuggestion)).
+		am.PermissionAccess(authtypes.PermissionTelemetryRead, ah.FilterKeysSuggestion)).
 		Methods(http.MethodGet)
 	router.HandleFunc("/api/v1/metrics/filters/values",
-		am.ViewAccess(ah.FilterValuesSuggestion)).
+		am.PermissionAccess(authtypes.PermissionTelemetryRead, ah.FilterValuesSuggestion)).
 		Methods(http.MethodPost)
 	router.HandleFunc("/api/v1/metrics/{metric_name}/metadata",
-		am.ViewAccess(ah.GetMetricsDetails)).
+		am.PermissionAccess(authtypes.PermissionTelemetryRead, ah.GetMetricsDetails)).
 		Methods(http.MethodGet)
 	router.HandleFunc("/api/v1/metrics",
-		am.ViewAccess(ah.ListMetrics)).
+		am.PermissionAccess(authtypes.PermissionTelemetryRead, ah.ListMetrics)).
 		Methods(http.MethodPost)
 	router.HandleFunc("/api/v1/metrics/treemap",
-		am.ViewAccess(ah.GetTreeMap)).
+		am.PermissionAccess(authtypes.PermissionTelemetryRead, ah.GetTreeMap)).
 		Methods(http.MethodPost)
 	router.HandleFunc("/api/v1/metrics/related",
-		am.ViewAccess(ah.GetRelatedMetrics)).
+		am.PermissionAccess(authtypes.PermissionTelemetryRead, ah.GetRelatedMetrics)).
 		Methods(http.MethodPost)
 	router.HandleFunc("/api/v1/metrics/inspect",
-		am.ViewAccess(ah.GetInspectMetricsData)).
+		am.PermissionAccess(authtypes.PermissionTelemetryRead, ah.GetInspectMetricsData)).
 		Methods(http.MethodPost)
 	router.HandleFunc("/api/v1/metrics/{metric_name}/metadata",
-		am.ViewAccess(ah.UpdateMetricsMetadata)).
+		am.PermissionAccess(authtypes.PermissionTelemetryWrite, ah.UpdateMetricsMetadata)).
 		Methods(http.MethodPost)
 }
 
@@ -1112,11 +1249,125 @@ func (aH *APIHandler) listRules(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// rules are owned by the team of their team label
+	if teamID := r.URL.Query().Get("teamId"); teamID != "" {
+		team, err := aH.getTeam(r.Context(), teamID)
+		if err != nil {
+			render.Error(w, err)
+			return
+		}
+
+		rules.Rules = slices.DeleteFunc(rules.Rules, func(rule *ruletypes.GettableRule) bool {
+			return rule.Labels[teamtypes.RuleLabel] != team.Name
+		})
+	}
+
 	// todo(amol): need to add sorter
 
 	aH.Respond(w, rules)
 }
 
+// getTeam gets the team of the org of the claims the listings are filtered by.
+func (aH *APIHandler) getTeam(ctx context.Context, id string) (*teamtypes.GettableTeam, error) {
+	claims, err := authtypes.ClaimsFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	teamID, err := valuer.NewUUID(id)
+	if err != nil {
+		return nil, errorsV2.Wrapf(err, errorsV2.TypeInvalidInput, errorsV2.CodeInvalidInput, "failed to parse team id")
+	}
+
+	return aH.Signoz.Modules.Team.Get(ctx, valuer.MustNewUUID(claims.OrgID), teamID)
+}
+
+// applyBundle reconciles the channels, rules, dashboards and saved views of the org with the bundle of the request,
+// `?dryRun=true` renders the plan without making the changes.
+func (aH *APIHandler) applyBundle(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 2*time.Minute)
+	defer cancel()
+
+	claims, err := authtypes.ClaimsFromContext(ctx)
+	if err != nil {
+		render.Error(w, err)
+		return
+	}
+
+	bundle := new(provisioningtypes.Bundle)
+	if err := json.NewDecoder(r.Body).Decode(bundle); err != nil {
+		render.Error(w, errorsV2.Wrapf(err, errorsV2.TypeInvalidInput, errorsV2.CodeInvalidInput, "failed to decode request body"))
+		return
+	}
+
+	dryRun := false
+	if value := r.URL.Query().Get("dryRun"); value != "" {
+		dryRun, err = strconv.ParseBool(value)
+		if err != nil {
+			render.Error(w, errorsV2.Wrapf(err, errorsV2.TypeInvalidInput, errorsV2.CodeInvalidInput, "dryRun must be a boolean"))
+			return
+		}
+	}
+
+	plan, err := aH.Signoz.Modules.Provisioning.Apply(ctx, valuer.MustNewUUID(claims.OrgID), bundle, dryRun, aH.ruleManager)
+	if err != nil {
+		render.Error(w, err)
+		return
+	}
+
+	render.Success(w, http.StatusOK, plan)
+}
+
+// sharedQueryRange queries a widget shared by the link of the request, the queries are read from the shared resource
+// so the token of a link can't run any other query.
+func (aH *APIHandler) sharedQueryRange(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	link, err := sharetypes.ShareLinkFromContext(ctx)
+	if err != nil {
+		render.Error(w, err)
+		return
+	}
+
+	sharedQuery := new(sharetypes.PostableSharedQuery)
+	if err := json.NewDecoder(r.Body).Decode(sharedQuery); err != nil {
+		render.Error(w, errorsV2.Wrapf(err, errorsV2.TypeInvalidInput, errorsV2.CodeInvalidInput, "failed to decode request body"))
+		return
+	}
+
+	queryRangeRequest, err := aH.Signoz.Modules.Share.NewQueryRangeRequest(ctx, link, sharedQuery.WidgetID)
+	if err != nil {
+		render.Error(w, err)
+		return
+	}
+
+	queryRangeResponse, err := aH.Signoz.Querier.QueryRange(ctx, link.OrgID, queryRangeRequest)
+	if err != nil {
+		render.Error(w, err)
+		return
+	}
+
+	render.Success(w, http.StatusOK, queryRangeResponse)
+}
+
+// unmanaged rejects the changes to the resources managed by bundles, the id of the resource is the path variable.
+func (aH *APIHandler) unmanaged(kind provisioningtypes.Kind, variable string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, err := authtypes.ClaimsFromContext(r.Context())
+		if err != nil {
+			render.Error(w, err)
+			return
+		}
+
+		if err := aH.Signoz.Modules.Provisioning.CheckUnmanaged(r.Context(), valuer.MustNewUUID(claims.OrgID), kind, mux.Vars(r)[variable]); err != nil {
+			render.Error(w, err)
+			return
+		}
+
+		next(w, r)
+	}
+}
+
 func prepareQuery(r *http.Request) (string, error) {
 	var postData *model.DashboardVars
 
@@ -1286,6 +1537,24 @@ func (aH *APIHandler) List(rw http.ResponseWriter, r *http.Request) {
 		dashboards = append(dashboards, cloudIntegrationDashboards...)
 	}
 
+	if teamID := r.URL.Query().Get("teamId"); teamID != "" {
+		team, err := aH.getTeam(ctx, teamID)
+		if err != nil {
+			render.Error(rw, err)
+			return
+		}
+
+		ownedIDs, err := aH.Signoz.Modules.Team.ListOwnedResourceIDs(ctx, orgID, team.ID, teamtypes.ResourceTypeDashboard)
+		if err != nil {
+			render.Error(rw, err)
+			return
+		}
+
+		dashboards = slices.DeleteFunc(dashboards, func(dashboard *dashboardtypes.Dashboard) bool {
+			return !slices.Contains(ownedIDs, dashboard.ID)
+		})
+	}
+
 	gettableDashboards, err := dashboardtypes.NewGettableDashboardsFromDashboards(dashboards)
 	if err != nil {
 		render.Error(rw, err)
@@ -1927,6 +2196,10 @@ func (aH *APIHandler) setTTL(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if orgID, err := valuer.NewUUID(claims.OrgID); err == nil {
+		aH.Signoz.Modules.Audit.Record(ctx, orgID, audittypes.ActionUpdate, audittypes.NewResource(audittypes.ResourceTypeTTL, ttlParams.Type), nil, ttlParams)
+	}
+
 	aH.WriteJSON(w, r, result)
 
 }
@@ -2044,25 +2317,26 @@ func handleSsoError(w http.ResponseWriter, r *http.Request, redirectURL string)
 	http.Redirect(w, r, fmt.Sprintf("%s?ssoerror=%s", redirectURL, string(dst)), http.StatusSeeOther)
 }
 
-// receiveGoogleAuth completes google OAuth response and forwards a request
-// to front-end to sign user in
-func (aH *APIHandler) receiveGoogleAuth(w http.ResponseWriter, r *http.Request) {
+// receiveOAuth completes the OAuth response of google or of a generic OpenID Connect
+// provider, picked from the sso config of the domain in the relay state, and forwards
+// a request to front-end to sign user in
+func (aH *APIHandler) receiveOAuth(w http.ResponseWriter, r *http.Request) {
 	redirectUri := constants.GetDefaultSiteURL()
 	ctx := context.Background()
 
 	q := r.URL.Query()
 	if errType := q.Get("error"); errType != "" {
-		zap.L().Error("[receiveGoogleAuth] failed to login with google auth", zap.String("error", errType), zap.String("error_description", q.Get("error_description")))
+		zap.L().Error("[receiveOAuth] failed to login with sso provider", zap.String("error", errType), zap.String("error_description", q.Get("error_description")))
 		http.Redirect(w, r, fmt.Sprintf("%s?ssoerror=%s", redirectUri, "failed to login through SSO"), http.StatusMovedPermanently)
 		return
 	}
 
 	relayState := q.Get("state")
-	zap.L().Debug("[receiveGoogleAuth] relay state received", zap.String("state", relayState))
+	zap.L().Debug("[receiveOAuth] relay state received", zap.String("state", relayState))
 
 	parsedState, err := url.Parse(relayState)
 	if err != nil || relayState == "" {
-		zap.L().Error("[receiveGoogleAuth] failed to process response - invalid response from IDP", zap.Error(err), zap.Any("request", r))
+		zap.L().Error("[receiveOAuth] failed to process response - invalid response from IDP", zap.Error(err), zap.Any("request", r))
 		handleSsoError(w, r, redirectUri)
 		return
 	}
@@ -2078,25 +2352,25 @@ func (aH *APIHandler) receiveGoogleAuth(w http.ResponseWriter, r *http.Request)
 	}
 
 	// now that we have domain, use domain to fetch sso settings.
-	// prepare google callback handler using parsedState -
+	// prepare callback handler using parsedState -
 	// which contains redirect URL (front-end endpoint)
-	callbackHandler, err := domain.PrepareGoogleOAuthProvider(parsedState)
+	callbackHandler, err := domain.PrepareOAuthCallbackProvider(parsedState)
 	if err != nil {
-		zap.L().Error("[receiveGoogleAuth] failed to prepare google oauth provider", zap.String("domain", domain.String()), zap.Error(err))
+		zap.L().Error("[receiveOAuth] failed to prepare oauth provider", zap.String("domain", domain.String()), zap.Error(err))
 		handleSsoError(w, r, redirectUri)
 		return
 	}
 
 	identity, err := callbackHandler.HandleCallback(r)
 	if err != nil {
-		zap.L().Error("[receiveGoogleAuth] failed to process HandleCallback", zap.String("domain", domain.String()), zap.Error(err))
+		zap.L().Error("[receiveOAuth] failed to process HandleCallback", zap.String("domain", domain.String()), zap.Error(err))
 		handleSsoError(w, r, redirectUri)
 		return
 	}
 
-	nextPage, err := aH.Signoz.Modules.User.PrepareSsoRedirect(ctx, redirectUri, identity.Email, aH.JWT)
+	nextPage, err := aH.Signoz.Modules.User.PrepareSsoRedirect(ctx, redirectUri, domain, identity, aH.JWT)
 	if err != nil {
-		zap.L().Error("[receiveGoogleAuth] failed to generate redirect URI after successful login ", zap.String("domain", domain.String()), zap.Error(err))
+		zap.L().Error("[receiveOAuth] failed to generate redirect URI after successful login ", zap.String("domain", domain.String()), zap.Error(err))
 		handleSsoError(w, r, redirectUri)
 		return
 	}
@@ -2143,7 +2417,7 @@ func (aH *APIHandler) RegisterMessagingQueuesRoutes(router *mux.Router, am *midd
 	messagingQueuesRouter := router.PathPrefix("/api/v1/messaging-queues").Subrouter()
 
 	// Queue Overview route
-	messagingQueuesRouter.HandleFunc("/queue-overview", am.ViewAccess(aH.getQueueOverview)).Methods(http.MethodPost)
+	messagingQueuesRouter.HandleFunc("/queue-overview", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getQueueOverview)).Methods(http.MethodPost)
 
 	// -------------------------------------------------
 	// Kafka-specific routes
@@ -2151,31 +2425,31 @@ func (aH *APIHandler) RegisterMessagingQueuesRoutes(router *mux.Router, am *midd
 
 	onboardingRouter := kafkaRouter.PathPrefix("/onboarding").Subrouter()
 
-	onboardingRouter.HandleFunc("/producers", am.ViewAccess(aH.onboardProducers)).Methods(http.MethodPost)
-	onboardingRouter.HandleFunc("/consumers", am.ViewAccess(aH.onboardConsumers)).Methods(http.MethodPost)
-	onboardingRouter.HandleFunc("/kafka", am.ViewAccess(aH.onboardKafka)).Methods(http.MethodPost)
+	onboardingRouter.HandleFunc("/producers", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.onboardProducers)).Methods(http.MethodPost)
+	onboardingRouter.HandleFunc("/consumers", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.onboardConsumers)).Methods(http.MethodPost)
+	onboardingRouter.HandleFunc("/kafka", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.onboardKafka)).Methods(http.MethodPost)
 
 	partitionLatency := kafkaRouter.PathPrefix("/partition-latency").Subrouter()
 
-	partitionLatency.HandleFunc("/overview", am.ViewAccess(aH.getPartitionOverviewLatencyData)).Methods(http.MethodPost)
-	partitionLatency.HandleFunc("/consumer", am.ViewAccess(aH.getConsumerPartitionLatencyData)).Methods(http.MethodPost)
+	partitionLatency.HandleFunc("/overview", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getPartitionOverviewLatencyData)).Methods(http.MethodPost)
+	partitionLatency.HandleFunc("/consumer", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getConsumerPartitionLatencyData)).Methods(http.MethodPost)
 
 	consumerLagRouter := kafkaRouter.PathPrefix("/consumer-lag").Subrouter()
 
-	consumerLagRouter.HandleFunc("/producer-details", am.ViewAccess(aH.getProducerData)).Methods(http.MethodPost)
-	consumerLagRouter.HandleFunc("/consumer-details", am.ViewAccess(aH.getConsumerData)).Methods(http.MethodPost)
-	consumerLagRouter.HandleFunc("/network-latency", am.ViewAccess(aH.getNetworkData)).Methods(http.MethodPost)
+	consumerLagRouter.HandleFunc("/producer-details", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getProducerData)).Methods(http.MethodPost)
+	consumerLagRouter.HandleFunc("/consumer-details", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getConsumerData)).Methods(http.MethodPost)
+	consumerLagRouter.HandleFunc("/network-latency", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getNetworkData)).Methods(http.MethodPost)
 
 	topicThroughput := kafkaRouter.PathPrefix("/topic-throughput").Subrouter()
 
-	topicThroughput.HandleFunc("/producer", am.ViewAccess(aH.getProducerThroughputOverview)).Methods(http.MethodPost)
-	topicThroughput.HandleFunc("/producer-details", am.ViewAccess(aH.getProducerThroughputDetails)).Methods(http.MethodPost)
-	topicThroughput.HandleFunc("/consumer", am.ViewAccess(aH.getConsumerThroughputOverview)).Methods(http.MethodPost)
-	topicThroughput.HandleFunc("/consumer-details", am.ViewAccess(aH.getConsumerThroughputDetails)).Methods(http.MethodPost)
+	topicThroughput.HandleFunc("/producer", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getProducerThroughputOverview)).Methods(http.MethodPost)
+	topicThroughput.HandleFunc("/producer-details", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getProducerThroughputDetails)).Methods(http.MethodPost)
+	topicThroughput.HandleFunc("/consumer", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getConsumerThroughputOverview)).Methods(http.MethodPost)
+	topicThroughput.HandleFunc("/consumer-details", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getConsumerThroughputDetails)).Methods(http.MethodPost)
 
 	spanEvaluation := kafkaRouter.PathPrefix("/span").Subrouter()
 
-	spanEvaluation.HandleFunc("/evaluation", am.ViewAccess(aH.getProducerConsumerEval)).Methods(http.MethodPost)
+	spanEvaluation.HandleFunc("/evaluation", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getProducerConsumerEval)).Methods(http.MethodPost)
 }
 
 // RegisterThirdPartyApiRoutes adds third-party-api integration routes
@@ -2187,8 +2461,8 @@ func (aH *APIHandler) RegisterThirdPartyApiRoutes(router *mux.Router, am *middle
 	// Domain Overview route
 	overviewRouter := thirdPartyApiRouter.PathPrefix("/overview").Subrouter()
 
-	overviewRouter.HandleFunc("/list", am.ViewAccess(aH.getDomainList)).Methods(http.MethodPost)
-	overviewRouter.HandleFunc("/domain", am.ViewAccess(aH.getDomainInfo)).Methods(http.MethodPost)
+	overviewRouter.HandleFunc("/list", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getDomainList)).Methods(http.MethodPost)
+	overviewRouter.HandleFunc("/domain", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getDomainInfo)).Methods(http.MethodPost)
 }
 
 // not using md5 hashing as the plain string would work
@@ -3175,24 +3449,24 @@ func (aH *APIHandler) RegisterIntegrationRoutes(router *mux.Router, am *middlewa
 	subRouter := router.PathPrefix("/api/v1/integrations").Subrouter()
 
 	subRouter.HandleFunc(
-		"/install", am.ViewAccess(aH.InstallIntegration),
+		"/install", am.PermissionAccess(authtypes.PermissionIntegrationsManage, aH.InstallIntegration),
 	).Methods(http.MethodPost)
 
 	subRouter.HandleFunc(
-		"/uninstall", am.ViewAccess(aH.UninstallIntegration),
+		"/uninstall", am.PermissionAccess(authtypes.PermissionIntegrationsManage, aH.UninstallIntegration),
 	).Methods(http.MethodPost)
 
 	// Used for polling for status in v0
 	subRouter.HandleFunc(
-		"/{integrationId}/connection_status", am.ViewAccess(aH.GetIntegrationConnectionStatus),
+		"/{integrationId}/connection_status", am.PermissionAccess(authtypes.PermissionIntegrationsRead, aH.GetIntegrationConnectionStatus),
 	).Methods(http.MethodGet)
 
 	subRouter.HandleFunc(
-		"/{integrationId}", am.ViewAccess(aH.GetIntegration),
+		"/{integrationId}", am.PermissionAccess(authtypes.PermissionIntegrationsRead, aH.GetIntegration),
 	).Methods(http.MethodGet)
 
 	subRouter.HandleFunc(
-		"", am.ViewAccess(aH.ListIntegrations),
+		"", am.PermissionAccess(authtypes.PermissionIntegrationsRead, aH.ListIntegrations),
 	).Methods(http.MethodGet)
 }
 
@@ -3507,39 +3781,39 @@ func (aH *APIHandler) RegisterCloudIntegrationsRoutes(router *mux.Router, am *mi
 	subRouter := router.PathPrefix("/api/v1/cloud-integrations").Subrouter()
 
 	subRouter.HandleFunc(
-		"/{cloudProvider}/accounts/generate-connection-url", am.EditAccess(aH.CloudIntegrationsGenerateConnectionUrl),
+		"/{cloudProvider}/accounts/generate-connection-url", am.PermissionAccess(authtypes.PermissionIntegrationsManage, aH.CloudIntegrationsGenerateConnectionUrl),
 	).Methods(http.MethodPost)
 
 	subRouter.HandleFunc(
-		"/{cloudProvider}/accounts", am.ViewAccess(aH.CloudIntegrationsListConnectedAccounts),
+		"/{cloudProvider}/accounts", am.PermissionAccess(authtypes.PermissionIntegrationsRead, aH.CloudIntegrationsListConnectedAccounts),
 	).Methods(http.MethodGet)
 
 	subRouter.HandleFunc(
-		"/{cloudProvider}/accounts/{accountId}/status", am.ViewAccess(aH.CloudIntegrationsGetAccountStatus),
+		"/{cloudProvider}/accounts/{accountId}/status", am.PermissionAccess(authtypes.PermissionIntegrationsRead, aH.CloudIntegrationsGetAccountStatus),
 	).Methods(http.MethodGet)
 
 	subRouter.HandleFunc(
-		"/{cloudProvider}/accounts/{accountId}/config", am.EditAccess(aH.CloudIntegrationsUpdateAccountConfig),
+		"/{cloudProvider}/accounts/{accountId}/config", am.PermissionAccess(authtypes.PermissionIntegrationsManage, aH.CloudIntegrationsUpdateAccountConfig),
 	).Methods(http.MethodPost)
 
 	subRouter.HandleFunc(
-		"/{cloudProvider}/accounts/{accountId}/disconnect", am.EditAccess(aH.CloudIntegrationsDisconnectAccount),
+		"/{cloudProvider}/accounts/{accountId}/disconnect", am.PermissionAccess(authtypes.PermissionIntegrationsManage, aH.CloudIntegrationsDisconnectAccount),
 	).Methods(http.MethodPost)
 
 	subRouter.HandleFunc(
-		"/{cloudProvider}/agent-check-in", am.ViewAccess(aH.CloudIntegrationsAgentCheckIn),
+		"/{cloudProvider}/agent-check-in", am.PermissionAccess(authtypes.PermissionIntegrationsRead, aH.CloudIntegrationsAgentCheckIn),
 	).Methods(http.MethodPost)
 
 	subRouter.HandleFunc(
-		"/{cloudProvider}/services", am.ViewAccess(aH.CloudIntegrationsListServices),
+		"/{cloudProvider}/services", am.PermissionAccess(authtypes.PermissionIntegrationsRead, aH.CloudIntegrationsListServices),
 	).Methods(http.MethodGet)
 
 	subRouter.HandleFunc(
-		"/{cloudProvider}/services/{serviceId}", am.ViewAccess(aH.CloudIntegrationsGetServiceDetails),
+		"/{cloudProvider}/services/{serviceId}", am.PermissionAccess(authtypes.PermissionIntegrationsRead, aH.CloudIntegrationsGetServiceDetails),
 	).Methods(http.MethodGet)
 
 	subRouter.HandleFunc(
-		"/{cloudProvider}/services/{serviceId}/config", am.EditAccess(aH.CloudIntegrationsUpdateServiceConfig),
+		"/{cloudProvider}/services/{serviceId}/config", am.PermissionAccess(authtypes.PermissionIntegrationsManage, aH.CloudIntegrationsUpdateServiceConfig),
 	).Methods(http.MethodPost)
 
 }
@@ -4023,16 +4297,16 @@ func (aH *APIHandler) CloudIntegrationsUpdateServiceConfig(
 // logs
 func (aH *APIHandler) RegisterLogsRoutes(router *mux.Router, am *middleware.AuthZ) {
 	subRouter := router.PathPrefix("/api/v1/logs").Subrouter()
-	subRouter.HandleFunc("", am.ViewAccess(aH.getLogs)).Methods(http.MethodGet)
-	subRouter.HandleFunc("/tail", am.ViewAccess(aH.tailLogs)).Methods(http.MethodGet)
-	subRouter.HandleFunc("/fields", am.ViewAccess(aH.logFields)).Methods(http.MethodGet)
-	subRouter.HandleFunc("/fields", am.EditAccess(aH.logFieldUpdate)).Methods(http.MethodPost)
-	subRouter.HandleFunc("/aggregate", am.ViewAccess(aH.logAggregate)).Methods(http.MethodGet)
+	subRouter.HandleFunc("", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.getLogs)).Methods(http.MethodGet)
+	subRouter.HandleFunc("/tail", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.tailLogs)).Methods(http.MethodGet)
+	subRouter.HandleFunc("/fields", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.logFields)).Methods(http.MethodGet)
+	subRouter.HandleFunc("/fields", am.PermissionAccess(authtypes.PermissionTelemetryWrite, aH.logFieldUpdate)).Methods(http.MethodPost)
+	subRouter.HandleFunc("/aggregate", am.PermissionAccess(authtypes.PermissionTelemetryRead, aH.logAggregate)).Methods(http.MethodGet)
 
 	// log pipelines
-	subRouter.HandleFunc("/pipelines/preview", am.ViewAccess(aH.PreviewLogsPipelinesHandler)).Methods(http.MethodPost)
-	subRouter.HandleFunc("/pipelines/{version}", am.ViewAccess(aH.ListLogsPipelinesHandler)).Methods(http.MethodGet)
-	subRouter.HandleFunc("/pipelines", am.EditAccess(aH.CreateLogsPipeline)).Methods(http.MethodPost)
+	subRouter.HandleFunc("/pipelines/preview", am.PermissionAccess(authtypes.PermissionPipelinesRead, aH.PreviewLogsPipelinesHandler)).Methods(http.MethodPost)
+	subRouter.HandleFunc("/pipelines/{version}", am.PermissionAccess(authtypes.PermissionPipelinesRead, aH.ListLogsPipelinesHandler)).Methods(http.MethodGet)
+	subRouter.HandleFunc("/pipelines", am.PermissionAccess(authtypes.PermissionPipelinesDeploy, aH.CreateLogsPipeline)).Methods(http.MethodPost)
 }
 
 func (aH *APIHandler) logFields(w http.ResponseWriter, r *http.Request) {
@@ -4170,6 +4444,27 @@ func (aH *APIHandler) PreviewLogsPipelinesHandler(w http.ResponseWriter, r *http
 		return
 	}
 
+	if req.RecentLogs != nil {
+		filter, err := req.RecentLogs.Filter(req.Pipelines)
+		if err != nil {
+			RespondError(w, model.BadRequest(err), nil)
+			return
+		}
+
+		query, err := logparsingpipeline.RecentLogsQuery(filter, req.RecentLogs.Limit, time.Now())
+		if err != nil {
+			RespondError(w, model.BadRequest(err), nil)
+			return
+		}
+
+		recentLogs, apiErr := aH.reader.GetLogsV2(r.Context(), query)
+		if apiErr != nil {
+			RespondError(w, model.WrapApiError(apiErr, "failed to get recent logs for preview"), nil)
+			return
+		}
+		req.Logs = logparsingpipeline.SignozLogsFromV2(recentLogs)
+	}
+
 	resultLogs, apiErr := aH.LogsParsingPipelineController.PreviewLogsPipelines(
 		r.Context(), &req,
 	)
@@ -4296,6 +4591,7 @@ func (aH *APIHandler) CreateLogsPipeline(w http.ResponseWriter, r *http.Request)
 	createPipeline := func(
 		ctx context.Context,
 		postable []pipelinetypes.PostablePipeline,
+		rolloutPolicy *opamptypes.PostableRolloutPolicy,
 	) (*logparsingpipeline.PipelinesResponse, *model.ApiError) {
 		if len(postable) == 0 {
 			zap.L().Warn("found no pipelines in the http request, this will delete all the pipelines")
@@ -4306,10 +4602,10 @@ func (aH *APIHandler) CreateLogsPipeline(w http.ResponseWriter, r *http.Request)
 			return nil, validationErr
 		}
 
-		return aH.LogsParsingPipelineController.ApplyPipelines(ctx, orgID, userID, postable)
+		return aH.LogsParsingPipelineController.ApplyPipelines(ctx, orgID, userID, postable, rolloutPolicy)
 	}
 
-	res, err := createPipeline(r.Context(), req.Pipelines)
+	res, err := createPipeline(r.Context(), req.Pipelines, req.Rollout)
 	if err != nil {
 		RespondError(w, err, nil)
 		return
@@ -5214,22 +5510,22 @@ func (aH *APIHandler) RegisterTraceFunnelsRoutes(router *mux.Router, am *middlew
 
 	// API endpoints
 	traceFunnelsRouter.HandleFunc("/new",
-		am.EditAccess(aH.Signoz.Handlers.TraceFunnel.New)).
+		am.PermissionAccess(authtypes.PermissionFunnelsWrite, aH.Signoz.Handlers.TraceFunnel.New)).
 		Methods(http.MethodPost)
 	traceFunnelsRouter.HandleFunc("/list",
-		am.ViewAccess(aH.Signoz.Handlers.TraceFunnel.List)).
+		am.PermissionAccess(authtypes.PermissionFunnelsRead, aH.Signoz.Handlers.TraceFunnel.List)).
 		Methods(http.MethodGet)
 	traceFunnelsRouter.HandleFunc("/steps/update",
-		am.EditAccess(aH.Signoz.Handlers.TraceFunnel.UpdateSteps)).
+		am.PermissionAccess(authtypes.PermissionFunnelsWrite, aH.Signoz.Handlers.TraceFunnel.UpdateSteps)).
 		Methods(http.MethodPut)
 
 	traceFunnelsRouter.HandleFunc("/{funnel_id}",
-		am.ViewAccess(aH.Signoz.Handlers.TraceFunnel.Get)).
+		am.PermissionAccess(authtypes.PermissionFunnelsRead, aH.Signoz.Handlers.TraceFunnel.Get)).
 		Methods(http.MethodGet)
 	traceFunnelsRouter.HandleFunc("/{funnel_id}",
-		am.EditAccess(aH.Signoz.Handlers.TraceFunnel.Delete)).
+		am.PermissionAccess(authtypes.PermissionFunnelsWrite, aH.Signoz.Handlers.TraceFunnel.Delete)).
 		Methods(http.MethodDelete)
 	traceFunnelsRouter.HandleFunc("/{funnel_id}",
-		am.EditAccess(aH.Signoz.Handlers.TraceFunnel.UpdateFunnel)).
+		am.PermissionAccess(authtypes.PermissionFunnelsWrite, aH.Signoz.Handlers.TraceFunnel.UpdateFunnel)).
 		Methods(http.MethodPut)
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/SigNoz/signoz/pkg/query-service/agentConf"
 	"github.com/SigNoz/signoz/pkg/query-service/constants"
 	"github.com/SigNoz/signoz/pkg/query-service/model"
+	v3 "github.com/SigNoz/signoz/pkg/query-service/model/v3"
 	"github.com/SigNoz/signoz/pkg/query-service/utils"
 	"github.com/SigNoz/signoz/pkg/sqlstore"
 	"github.com/SigNoz/signoz/pkg/types"
@@ -47,12 +48,14 @@ type PipelinesResponse struct {
 	History   []opamptypes.AgentConfigVersion  `json:"history"`
 }
 
-// ApplyPipelines stores new or changed pipelines and initiates a new config update
+// ApplyPipelines stores new or changed pipelines and initiates a new config update,
+// staged as per the rollout policy if one is specified
 func (ic *LogParsingPipelineController) ApplyPipelines(
 	ctx context.Context,
 	orgID valuer.UUID,
 	userID valuer.UUID,
 	postable []pipelinetypes.PostablePipeline,
+	rolloutPolicy *opamptypes.PostableRolloutPolicy,
 ) (*PipelinesResponse, *model.ApiError) {
 	var pipelines []pipelinetypes.GettablePipeline
 
@@ -82,7 +85,7 @@ func (ic *LogParsingPipelineController) ApplyPipelines(
 		elements[i] = p.ID.StringValue()
 	}
 
-	cfg, err := agentConf.StartNewVersion(ctx, orgID, userID, opamptypes.ElementTypeLogPipelines, elements)
+	cfg, err := agentConf.StartNewVersion(ctx, orgID, userID, opamptypes.ElementTypeLogPipelines, elements, rolloutPolicy)
 	if err != nil || cfg == nil {
 		return nil, model.InternalError(fmt.Errorf("failed to start new version: %w", err))
 	}
@@ -218,20 +221,98 @@ func (ic *LogParsingPipelineController) GetPipelinesByVersion(
 	}, nil
 }
 
+// PipelinesDiffResponse captures the changes made to pipelines between two config versions
+type PipelinesDiffResponse struct {
+	FromVersion int                          `json:"fromVersion"`
+	ToVersion   int                          `json:"toVersion"`
+	Pipelines   []pipelinetypes.PipelineDiff `json:"pipelines"`
+}
+
+// DiffPipelineVersions returns the pipelines and operators added, removed or changed between two versions
+func (ic *LogParsingPipelineController) DiffPipelineVersions(
+	ctx context.Context, orgID valuer.UUID, fromVersion int, toVersion int,
+) (*PipelinesDiffResponse, *model.ApiError) {
+	from, apiErr := ic.GetPipelinesByVersion(ctx, orgID, fromVersion)
+	if apiErr != nil {
+		return nil, model.WrapApiError(apiErr, fmt.Sprintf("failed to get pipelines for version %d", fromVersion))
+	}
+
+	to, apiErr := ic.GetPipelinesByVersion(ctx, orgID, toVersion)
+	if apiErr != nil {
+		return nil, model.WrapApiError(apiErr, fmt.Sprintf("failed to get pipelines for version %d", toVersion))
+	}
+
+	return &PipelinesDiffResponse{
+		FromVersion: fromVersion,
+		ToVersion:   toVersion,
+		Pipelines:   pipelinetypes.DiffPipelines(from.Pipelines, to.Pipelines),
+	}, nil
+}
+
 type PipelinesPreviewRequest struct {
 	Pipelines []pipelinetypes.GettablePipeline `json:"pipelines"`
 	Logs      []model.SignozLog                `json:"logs"`
+
+	// RecentLogs optionally previews the pipelines against recent logs instead of the provided ones.
+	RecentLogs *RecentLogsSource `json:"recentLogs,omitempty"`
+
+	// Trace includes the state of every log after each operator in the response.
+	Trace bool `json:"trace,omitempty"`
+}
+
+type RecentLogsSource struct {
+	// Alias of the pipeline whose filter is used for selecting logs, defaults to the first pipeline.
+	PipelineAlias string `json:"pipelineAlias,omitempty"`
+	Limit         int    `json:"limit"`
+}
+
+// Filter returns the filter to be used for selecting recent logs for previewing `pipelines`.
+func (s *RecentLogsSource) Filter(pipelines []pipelinetypes.GettablePipeline) (*v3.FilterSet, error) {
+	if len(pipelines) == 0 {
+		return nil, fmt.Errorf("at least one pipeline is needed for selecting recent logs")
+	}
+
+	if s.PipelineAlias == "" {
+		return pipelines[0].Filter, nil
+	}
+
+	for _, pipeline := range pipelines {
+		if pipeline.Alias == s.PipelineAlias {
+			return pipeline.Filter, nil
+		}
+	}
+
+	return nil, fmt.Errorf("pipeline with alias %s not found in the preview request", s.PipelineAlias)
 }
 
 type PipelinesPreviewResponse struct {
-	OutputLogs    []model.SignozLog `json:"logs"`
-	CollectorLogs []string          `json:"collectorLogs"`
+	OutputLogs    []model.SignozLog    `json:"logs"`
+	CollectorLogs []string             `json:"collectorLogs"`
+	InputLogs     []model.SignozLog    `json:"inputLogs,omitempty"`
+	Traces        []LogProcessingTrace `json:"traces,omitempty"`
 }
 
 func (ic *LogParsingPipelineController) PreviewLogsPipelines(
 	ctx context.Context,
 	request *PipelinesPreviewRequest,
 ) (*PipelinesPreviewResponse, *model.ApiError) {
+	var traces []LogProcessingTrace
+	if request.Trace {
+		var apiErr *model.ApiError
+		traces, apiErr = TracePipelinesProcessing(ctx, request.Pipelines, request.Logs)
+		if apiErr != nil {
+			return nil, apiErr
+		}
+	}
+
+	var inputLogs []model.SignozLog
+	if request.RecentLogs != nil {
+		inputLogs = make([]model.SignozLog, len(request.Logs))
+		for i, log := range request.Logs {
+			inputLogs[i] = cloneSignozLog(log)
+		}
+	}
+
 	result, collectorLogs, err := SimulatePipelinesProcessing(
 		ctx, request.Pipelines, request.Logs,
 	)
@@ -243,6 +324,8 @@ func (ic *LogParsingPipelineController) PreviewLogsPipelines(
 	return &PipelinesPreviewResponse{
 		OutputLogs:    result,
 		CollectorLogs: collectorLogs,
+		InputLogs:     inputLogs,
+		Traces:        traces,
 	}, nil
 }
 
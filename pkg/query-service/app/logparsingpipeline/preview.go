@@ -158,6 +158,9 @@ func SignozLogsToPLogs(logs []model.SignozLog) []plog.Logs {
 		for k, v := range log.Attributes_string {
 			slAttribs.PutStr(k, v)
 		}
+		for k, v := range log.Attributes_bool {
+			slAttribs.PutBool(k, v)
+		}
 		slAttribs.PutStr(SignozLogIdAttr, log.ID)
 
 		result = append(result, pl)
@@ -0,0 +1,355 @@
+package logparsingpipeline
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+	"time"
+
+	logsV4 "github.com/SigNoz/signoz/pkg/query-service/app/logs/v4"
+	"github.com/SigNoz/signoz/pkg/query-service/model"
+	v3 "github.com/SigNoz/signoz/pkg/query-service/model/v3"
+	"github.com/SigNoz/signoz/pkg/types/pipelinetypes"
+	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// attributes used for recording which parts of a pipeline were reached by a log while tracing.
+	tracePipelineMatchedAttr = "__signoz_preview_pipeline_matched__"
+	traceIfMatchedAttr       = "__signoz_preview_if_matched__"
+	traceProcessedAttr       = "__signoz_preview_processed__"
+
+	// number of collector simulations run in parallel while tracing pipelines processing.
+	traceSimulationsConcurrency = 8
+
+	// logs older than this are not considered while fetching recent logs for previews.
+	recentLogsLookback = 24 * time.Hour
+	maxRecentLogsLimit = 100
+)
+
+// LogProcessingTrace captures the state of a log after every operator of the pipelines it was processed by.
+type LogProcessingTrace struct {
+	Input     model.SignozLog `json:"input"`
+	Pipelines []PipelineTrace `json:"pipelines"`
+}
+
+type PipelineTrace struct {
+	Alias         string          `json:"alias"`
+	Name          string          `json:"name"`
+	FilterMatched bool            `json:"filterMatched"`
+	Operators     []OperatorTrace `json:"operators"`
+}
+
+type OperatorTrace struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+
+	// condition generated for the operator, the operator is skipped for logs not matching it.
+	If        string `json:"if,omitempty"`
+	IfMatched bool   `json:"ifMatched"`
+
+	// set if the operator failed to process the log, the log is passed on to the next operator unchanged.
+	Errored bool     `json:"errored"`
+	Errors  []string `json:"errors,omitempty"`
+
+	Output model.SignozLog `json:"output"`
+}
+
+// TracePipelinesProcessing simulates processing of logs through pipelines one operator at a time,
+// recording the state of every log after each operator along with the conditions it matched and
+// the operators that failed to process it.
+func TracePipelinesProcessing(
+	ctx context.Context,
+	pipelines []pipelinetypes.GettablePipeline,
+	logs []model.SignozLog,
+) ([]LogProcessingTrace, *model.ApiError) {
+	traces := make([]LogProcessingTrace, len(logs))
+	for i, log := range logs {
+		traces[i] = LogProcessingTrace{Input: cloneSignozLog(log), Pipelines: []PipelineTrace{}}
+	}
+
+	// Simulations are run for every prefix of the pipelines ending with an operator,
+	// the output of each of them being the state of logs right after that operator.
+	type traceStep struct {
+		pipelineIdx int
+		operatorIdx int
+		prefix      []pipelinetypes.GettablePipeline
+	}
+
+	enabledPipelines := []pipelinetypes.GettablePipeline{}
+	steps := []traceStep{}
+	for _, pipeline := range pipelines {
+		if !pipeline.Enabled {
+			continue
+		}
+
+		operators := enabledOperators(pipeline.Config)
+		if len(operators) == 0 {
+			continue
+		}
+
+		pipelineTrace := PipelineTrace{Alias: pipeline.Alias, Name: pipeline.Name, Operators: []OperatorTrace{}}
+		for operatorIdx, operator := range operators {
+			ifCondition, err := operatorIfCondition(operator)
+			if err != nil {
+				return nil, model.BadRequest(err)
+			}
+
+			pipelineTrace.Operators = append(pipelineTrace.Operators, OperatorTrace{
+				ID: operator.ID, Name: operator.Name, Type: operator.Type, If: ifCondition,
+			})
+
+			steps = append(steps, traceStep{
+				pipelineIdx: len(enabledPipelines),
+				operatorIdx: operatorIdx,
+				prefix:      tracePipelinesPrefix(enabledPipelines, pipeline, operators, operatorIdx, ifCondition),
+			})
+		}
+
+		for i := range traces {
+			traces[i].Pipelines = append(traces[i].Pipelines, clonePipelineTrace(pipelineTrace))
+		}
+		enabledPipelines = append(enabledPipelines, pipeline)
+	}
+
+	stepOutputs := make([]map[int]model.SignozLog, len(steps))
+	stepCollectorLogs := make([][]string, len(steps))
+
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(traceSimulationsConcurrency)
+	for stepIdx, step := range steps {
+		group.Go(func() error {
+			outputs, collectorLogs, apiErr := simulateTraceStep(groupCtx, step.prefix, logs)
+			if apiErr != nil {
+				return apiErr.ToError()
+			}
+
+			stepOutputs[stepIdx] = outputs
+			stepCollectorLogs[stepIdx] = collectorLogs
+			return nil
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return nil, model.BadRequest(errors.Wrap(err, "could not trace pipelines processing"))
+	}
+
+	for logIdx := range traces {
+		for stepIdx, step := range steps {
+			operatorTrace := &traces[logIdx].Pipelines[step.pipelineIdx].Operators[step.operatorIdx]
+
+			output, ok := stepOutputs[stepIdx][logIdx]
+			if !ok {
+				return nil, model.InternalError(fmt.Errorf("log %d missing in the output of pipelines simulation", logIdx))
+			}
+
+			_, pipelineMatched := output.Attributes_string[tracePipelineMatchedAttr]
+			_, ifMatched := output.Attributes_string[traceIfMatchedAttr]
+			_, processed := output.Attributes_string[traceProcessedAttr]
+			for _, attr := range []string{tracePipelineMatchedAttr, traceIfMatchedAttr, traceProcessedAttr} {
+				delete(output.Attributes_string, attr)
+			}
+
+			if step.operatorIdx == 0 {
+				traces[logIdx].Pipelines[step.pipelineIdx].FilterMatched = pipelineMatched
+			}
+			operatorTrace.IfMatched = ifMatched
+			operatorTrace.Output = output
+
+			if pipelineMatched && !processed {
+				// The log has been left as it was before the failing operator,
+				// it would have been passed on unchanged to the next operator.
+				operatorTrace.Errored = true
+				operatorTrace.Errors = collectorLogsForOperator(stepCollectorLogs[stepIdx], enabledPipelines[step.pipelineIdx], operatorTrace.ID)
+			}
+		}
+	}
+
+	return traces, nil
+}
+
+// simulateTraceStep processes logs through the pipelines and returns the output logs keyed by their index in `logs`.
+func simulateTraceStep(
+	ctx context.Context,
+	pipelines []pipelinetypes.GettablePipeline,
+	logs []model.SignozLog,
+) (map[int]model.SignozLog, []string, *model.ApiError) {
+	// log ids are used for matching output logs with the input.
+	inputLogs := make([]model.SignozLog, len(logs))
+	for i, log := range logs {
+		inputLogs[i] = cloneSignozLog(log)
+		inputLogs[i].ID = strconv.Itoa(i)
+	}
+
+	outputLogs, collectorLogs, apiErr := SimulatePipelinesProcessing(ctx, pipelines, inputLogs)
+	if apiErr != nil {
+		return nil, collectorLogs, apiErr
+	}
+
+	outputs := map[int]model.SignozLog{}
+	for _, output := range outputLogs {
+		logIdx, err := strconv.Atoi(output.ID)
+		if err != nil || logIdx < 0 || logIdx >= len(logs) {
+			continue
+		}
+
+		output.ID = logs[logIdx].ID
+		outputs[logIdx] = output
+	}
+
+	return outputs, collectorLogs, nil
+}
+
+// tracePipelinesPrefix returns the pipelines to be simulated for getting the state of logs right after `operators[operatorIdx]`.
+// Marker operators record whether the pipeline filter and the condition of the traced operator matched a log.
+// The traced operator stops processing of logs it fails to process so that failures can be told apart
+// by the absence of the marker following it.
+func tracePipelinesPrefix(
+	previousPipelines []pipelinetypes.GettablePipeline,
+	pipeline pipelinetypes.GettablePipeline,
+	operators []pipelinetypes.PipelineOperator,
+	operatorIdx int,
+	ifCondition string,
+) []pipelinetypes.GettablePipeline {
+	config := []pipelinetypes.PipelineOperator{
+		{
+			ID:      "signoz-preview-pipeline-matched",
+			Type:    "add",
+			Field:   "attributes." + tracePipelineMatchedAttr,
+			Value:   "true",
+			Enabled: true,
+		},
+	}
+	config = append(config, operators[:operatorIdx]...)
+
+	traced := operators[operatorIdx]
+	traced.Output = ""
+	traced.OnError = "drop"
+	config = append(config,
+		pipelinetypes.PipelineOperator{
+			ID:      "signoz-preview-if-matched",
+			Type:    "add",
+			Field:   "attributes." + traceIfMatchedAttr,
+			Value:   "true",
+			If:      ifCondition,
+			Enabled: true,
+		},
+		traced,
+		pipelinetypes.PipelineOperator{
+			ID:      "signoz-preview-processed",
+			Type:    "add",
+			Field:   "attributes." + traceProcessedAttr,
+			Value:   "true",
+			Enabled: true,
+		},
+	)
+
+	pipeline.Config = config
+
+	prefix := append([]pipelinetypes.GettablePipeline{}, previousPipelines...)
+	return append(prefix, pipeline)
+}
+
+func enabledOperators(operators []pipelinetypes.PipelineOperator) []pipelinetypes.PipelineOperator {
+	enabled := []pipelinetypes.PipelineOperator{}
+	for _, operator := range operators {
+		if operator.Enabled {
+			enabled = append(enabled, operator)
+		}
+	}
+	return enabled
+}
+
+// operatorIfCondition returns the condition generated for an operator in the collector config.
+func operatorIfCondition(operator pipelinetypes.PipelineOperator) (string, error) {
+	operator.Output = ""
+	generated, err := getOperators([]pipelinetypes.PipelineOperator{operator})
+	if err != nil {
+		return "", err
+	}
+	if len(generated) == 0 {
+		return "", nil
+	}
+	return generated[0].If, nil
+}
+
+// collectorLogsForOperator returns the collector logs emitted by an operator of a pipeline.
+func collectorLogsForOperator(collectorLogs []string, pipeline pipelinetypes.GettablePipeline, operatorID string) []string {
+	processorRef := fmt.Sprintf(`"name": "%s"`, CollectorConfProcessorName(pipeline))
+	operatorRef := fmt.Sprintf(`"operator_id": "%s"`, operatorID)
+
+	operatorLogs := []string{}
+	for _, log := range collectorLogs {
+		if strings.Contains(log, processorRef) && strings.Contains(log, operatorRef) {
+			operatorLogs = append(operatorLogs, log)
+		}
+	}
+	return operatorLogs
+}
+
+func clonePipelineTrace(trace PipelineTrace) PipelineTrace {
+	trace.Operators = append([]OperatorTrace{}, trace.Operators...)
+	return trace
+}
+
+func cloneSignozLog(log model.SignozLog) model.SignozLog {
+	log.Resources_string = maps.Clone(log.Resources_string)
+	log.Attributes_string = maps.Clone(log.Attributes_string)
+	log.Attributes_int64 = maps.Clone(log.Attributes_int64)
+	log.Attributes_float64 = maps.Clone(log.Attributes_float64)
+	log.Attributes_bool = maps.Clone(log.Attributes_bool)
+	return log
+}
+
+// RecentLogsQuery builds the query for fetching the most recent logs matching the filter of a pipeline.
+func RecentLogsQuery(filter *v3.FilterSet, limit int, now time.Time) (string, error) {
+	if limit <= 0 || limit > maxRecentLogsLimit {
+		return "", fmt.Errorf("limit must be between 1 and %d", maxRecentLogsLimit)
+	}
+
+	if filter == nil {
+		filter = &v3.FilterSet{Operator: "AND", Items: []v3.FilterItem{}}
+	}
+
+	return logsV4.PrepareLogsQuery(
+		now.Add(-recentLogsLookback).UnixMilli(),
+		now.UnixMilli(),
+		v3.QueryTypeBuilder,
+		v3.PanelTypeList,
+		&v3.BuilderQuery{
+			QueryName:         "A",
+			DataSource:        v3.DataSourceLogs,
+			AggregateOperator: v3.AggregateOperatorNoOp,
+			Filters:           filter,
+			Limit:             uint64(limit),
+			Expression:        "A",
+		},
+		v3.QBOptions{},
+	)
+}
+
+// SignozLogsFromV2 converts logs read from the v2 logs table to the representation used for simulations.
+func SignozLogsFromV2(logs []model.SignozLogV2) []model.SignozLog {
+	result := []model.SignozLog{}
+	for _, log := range logs {
+		result = append(result, model.SignozLog{
+			Timestamp:          log.Timestamp,
+			ID:                 log.ID,
+			TraceID:            log.TraceID,
+			SpanID:             log.SpanID,
+			TraceFlags:         log.TraceFlags,
+			SeverityText:       log.SeverityText,
+			SeverityNumber:     log.SeverityNumber,
+			Body:               log.Body,
+			Resources_string:   maps.Clone(log.Resources_string),
+			Attributes_string:  maps.Clone(log.Attributes_string),
+			Attributes_int64:   map[string]int64{},
+			Attributes_float64: maps.Clone(log.Attributes_number),
+			Attributes_bool:    maps.Clone(log.Attributes_bool),
+		})
+	}
+	return result
+}
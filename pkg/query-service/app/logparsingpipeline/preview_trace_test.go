@@ -0,0 +1,146 @@
+package logparsingpipeline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/query-service/model"
+	v3 "github.com/SigNoz/signoz/pkg/query-service/model/v3"
+	"github.com/SigNoz/signoz/pkg/types/pipelinetypes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracePipelinesProcessing(t *testing.T) {
+	require := require.New(t)
+
+	testPipelines := []pipelinetypes.GettablePipeline{
+		{
+			StoreablePipeline: pipelinetypes.StoreablePipeline{
+				OrderID: 1,
+				Name:    "pipeline1",
+				Alias:   "pipeline1",
+				Enabled: true,
+			},
+			Filter: &v3.FilterSet{
+				Operator: "AND",
+				Items: []v3.FilterItem{
+					{
+						Key: v3.AttributeKey{
+							Key:      "method",
+							DataType: v3.AttributeKeyDataTypeString,
+							Type:     v3.AttributeKeyTypeTag,
+						},
+						Operator: "=",
+						Value:    "GET",
+					},
+				},
+			},
+			Config: []pipelinetypes.PipelineOperator{
+				{
+					OrderId:   1,
+					ID:        "json",
+					Type:      "json_parser",
+					Enabled:   true,
+					Name:      "test json parser",
+					ParseFrom: "body",
+					ParseTo:   "attributes",
+				},
+				{
+					OrderId: 2,
+					ID:      "move",
+					Type:    "move",
+					Enabled: true,
+					Name:    "test move",
+					From:    "attributes.log_level",
+					To:      "attributes.level",
+				},
+				{
+					OrderId: 3,
+					ID:      "add",
+					Type:    "add",
+					Enabled: true,
+					Name:    "test add",
+					Field:   "attributes.processed",
+					Value:   "true",
+				},
+			},
+		},
+	}
+
+	testLogs := []model.SignozLog{
+		makeTestSignozLog(`{"log_level": "INFO"}`, map[string]interface{}{"method": "GET"}),
+		makeTestSignozLog(`{"log_level": }`, map[string]interface{}{"method": "GET"}),
+		makeTestSignozLog(`{"log_level": "INFO"}`, map[string]interface{}{"method": "POST"}),
+	}
+	testLogs[0].ID = "log-1"
+
+	traces, apiErr := TracePipelinesProcessing(context.Background(), testPipelines, testLogs)
+	require.Nil(apiErr)
+	require.Equal(3, len(traces))
+
+	parsed := traces[0].Pipelines[0]
+	require.True(parsed.FilterMatched)
+	require.Equal(3, len(parsed.Operators))
+	require.Contains(parsed.Operators[0].If, "body != nil")
+	require.True(parsed.Operators[0].IfMatched)
+	require.False(parsed.Operators[0].Errored)
+	require.Equal("INFO", parsed.Operators[0].Output.Attributes_string["log_level"])
+	require.Equal("log-1", parsed.Operators[0].Output.ID)
+	require.True(parsed.Operators[1].IfMatched)
+	require.Equal("INFO", parsed.Operators[1].Output.Attributes_string["level"])
+	require.NotContains(parsed.Operators[1].Output.Attributes_string, "log_level")
+	require.Equal("true", parsed.Operators[2].Output.Attributes_string["processed"])
+	require.NotContains(parsed.Operators[2].Output.Attributes_string, traceIfMatchedAttr)
+	require.NotContains(parsed.Operators[2].Output.Attributes_string, tracePipelineMatchedAttr)
+
+	failed := traces[1].Pipelines[0]
+	require.True(failed.FilterMatched)
+	require.True(failed.Operators[0].Errored)
+	require.NotEmpty(failed.Operators[0].Errors)
+	require.Equal(`{"log_level": }`, failed.Operators[0].Output.Body)
+	require.False(failed.Operators[1].IfMatched)
+	require.False(failed.Operators[1].Errored)
+	require.Equal("true", failed.Operators[2].Output.Attributes_string["processed"])
+
+	filtered := traces[2].Pipelines[0]
+	require.False(filtered.FilterMatched)
+	for _, operator := range filtered.Operators {
+		require.False(operator.IfMatched)
+		require.False(operator.Errored)
+	}
+	require.NotContains(filtered.Operators[2].Output.Attributes_string, "processed")
+
+	// the input logs must be left untouched
+	require.Equal(1, len(testLogs[0].Attributes_string))
+}
+
+func TestRecentLogsQuery(t *testing.T) {
+	require := require.New(t)
+
+	filter := &v3.FilterSet{
+		Operator: "AND",
+		Items: []v3.FilterItem{
+			{
+				Key: v3.AttributeKey{
+					Key:      "method",
+					DataType: v3.AttributeKeyDataTypeString,
+					Type:     v3.AttributeKeyTypeTag,
+				},
+				Operator: "=",
+				Value:    "GET",
+			},
+		},
+	}
+
+	query, err := RecentLogsQuery(filter, 10, time.Now())
+	require.NoError(err)
+	require.Contains(query, "attributes_string['method'] = 'GET'")
+	require.Contains(query, "order by timestamp DESC,id DESC LIMIT 10")
+
+	_, err = RecentLogsQuery(filter, 0, time.Now())
+	require.Error(err)
+
+	_, err = RecentLogsQuery(filter, maxRecentLogsLimit+1, time.Now())
+	require.Error(err)
+}
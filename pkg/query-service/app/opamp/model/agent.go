@@ -112,6 +112,39 @@ func ExtractLbFlag(agentDescr *protobufs.AgentDescription) bool {
 	return false
 }
 
+// Labels returns the string attributes reported by the agent in its description.
+// Identifying attributes take precedence over non identifying ones with the same key.
+// The caller is expected to hold the agent lock.
+func (agent *Agent) Labels() map[string]string {
+	labels := map[string]string{}
+	if agent.Status == nil || agent.Status.AgentDescription == nil {
+		return labels
+	}
+
+	attributes := append([]*protobufs.KeyValue{}, agent.Status.AgentDescription.NonIdentifyingAttributes...)
+	attributes = append(attributes, agent.Status.AgentDescription.IdentifyingAttributes...)
+	for _, kv := range attributes {
+		if kv == nil || kv.Value == nil {
+			continue
+		}
+		anyvalue, ok := kv.Value.Value.(*protobufs.AnyValue_StringValue)
+		if !ok {
+			continue
+		}
+		labels[kv.Key] = anyvalue.StringValue
+	}
+
+	return labels
+}
+
+// EffectiveConfigAndLabels returns the effective config and labels of the agent for callers outside the opamp server.
+func (agent *Agent) EffectiveConfigAndLabels() (string, map[string]string) {
+	agent.mux.RLock()
+	defer agent.mux.RUnlock()
+
+	return agent.Config, agent.Labels()
+}
+
 func (agent *Agent) updateAgentDescription(newStatus *protobufs.AgentToServer) (agentDescrChanged bool) {
 	prevStatus := agent.Status
 
@@ -294,7 +327,7 @@ func (agent *Agent) processStatusUpdate(
 }
 
 func (agent *Agent) updateRemoteConfig(configProvider AgentConfigProvider) bool {
-	recommendedConfig, confId, err := configProvider.RecommendAgentConfig(agent.OrgID, []byte(agent.Config))
+	recommendedConfig, confId, err := configProvider.RecommendAgentConfig(agent.OrgID, agent.AgentID, agent.Labels(), []byte(agent.Config))
 	if err != nil {
 		zap.L().Error("could not generate config recommendation for agent", zap.String("agentID", agent.AgentID), zap.Error(err))
 		return false
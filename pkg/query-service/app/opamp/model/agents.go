@@ -119,45 +119,55 @@ func (agents *Agents) RecommendLatestConfigToAll(
 	provider AgentConfigProvider,
 ) error {
 	for _, agent := range agents.GetAllAgents() {
-		newConfig, confId, err := provider.RecommendAgentConfig(
-			agent.OrgID,
-			[]byte(agent.Config),
-		)
-		if err != nil {
-			return errors.Wrap(err, fmt.Sprintf(
-				"could not generate conf recommendation for %v", agent.AgentID,
-			))
+		if err := agent.recommendLatestConfig(provider); err != nil {
+			return err
 		}
+	}
+	return nil
+}
 
-		// Recommendation is same as current config
-		if string(newConfig) == agent.Config {
-			zap.L().Info(
-				"Recommended config same as current effective config for agent", zap.String("agentID", agent.AgentID),
-			)
-			return nil
-		}
+func (agent *Agent) recommendLatestConfig(provider AgentConfigProvider) error {
+	agent.mux.Lock()
+	defer agent.mux.Unlock()
+
+	newConfig, confId, err := provider.RecommendAgentConfig(
+		agent.OrgID,
+		agent.AgentID,
+		agent.Labels(),
+		[]byte(agent.Config),
+	)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf(
+			"could not generate conf recommendation for %v", agent.AgentID,
+		))
+	}
+
+	// Recommendation is same as current config
+	if string(newConfig) == agent.Config {
+		zap.L().Info(
+			"Recommended config same as current effective config for agent", zap.String("agentID", agent.AgentID),
+		)
+		return nil
+	}
 
-		newRemoteConfig := &protobufs.AgentRemoteConfig{
-			Config: &protobufs.AgentConfigMap{
-				ConfigMap: map[string]*protobufs.AgentConfigFile{
-					CollectorConfigFilename: {
-						Body:        newConfig,
-						ContentType: "application/x-yaml",
-					},
+	newRemoteConfig := &protobufs.AgentRemoteConfig{
+		Config: &protobufs.AgentConfigMap{
+			ConfigMap: map[string]*protobufs.AgentConfigFile{
+				CollectorConfigFilename: {
+					Body:        newConfig,
+					ContentType: "application/x-yaml",
 				},
 			},
-			ConfigHash: []byte(confId),
-		}
+		},
+		ConfigHash: []byte(confId),
+	}
 
-		agent.mux.Lock()
-		defer agent.mux.Unlock()
-		agent.remoteConfig = newRemoteConfig
+	agent.remoteConfig = newRemoteConfig
 
-		agent.SendToAgent(&protobufs.ServerToAgent{
-			RemoteConfig: newRemoteConfig,
-		})
+	agent.SendToAgent(&protobufs.ServerToAgent{
+		RemoteConfig: newRemoteConfig,
+	})
 
-		ListenToConfigUpdate(agent.OrgID, agent.AgentID, confId, provider.ReportConfigDeploymentStatus)
-	}
+	ListenToConfigUpdate(agent.OrgID, agent.AgentID, confId, provider.ReportConfigDeploymentStatus)
 	return nil
 }
@@ -6,7 +6,9 @@ import "github.com/SigNoz/signoz/pkg/valuer"
 type AgentConfigProvider interface {
 	// Generate recommended config for an agent based on its `currentConfYaml`
 	// and current state of user facing settings for agent based features.
-	RecommendAgentConfig(orgId valuer.UUID, currentConfYaml []byte) (
+	// `agentLabels` are the attributes reported by the agent in its description
+	// and are used for targeting config versions to groups of agents.
+	RecommendAgentConfig(orgId valuer.UUID, agentId string, agentLabels map[string]string, currentConfYaml []byte) (
 		recommendedConfYaml []byte,
 		// Opaque id of the recommended config, used for reporting deployment status updates
 		configId string,
@@ -11,7 +11,7 @@ import (
 var coordinator *Coordinator
 
 func init() {
-	subscribers := make(map[string][]OnChangeCallback, 0)
+	subscribers := make(map[string][]subscriber, 0)
 	coordinator = &Coordinator{
 		subscribers: subscribers,
 	}
@@ -19,12 +19,17 @@ func init() {
 
 type OnChangeCallback func(orgId valuer.UUID, agentId string, hash string, err error)
 
+type subscriber struct {
+	agentId  string
+	callback OnChangeCallback
+}
+
 // responsible for managing subscribers on config change
 type Coordinator struct {
 	mutex sync.Mutex
 
 	// hash wise list of subscribers
-	subscribers map[string][]OnChangeCallback
+	subscribers map[string][]subscriber
 }
 
 func getSubscriberKey(orgId valuer.UUID, hash string) string {
@@ -43,21 +48,36 @@ func onConfigFailure(orgId valuer.UUID, agentId string, hash string, errorMessag
 
 // OnSuccess listens to config changes and notifies subscribers
 func notifySubscribers(orgId valuer.UUID, agentId string, key string, err error) {
-	// this method currently does not handle multi-agent scenario.
-	// as soon as a message is delivered, we release all the subscribers
-	// for a given key
+	// the same config hash is usually recommended to many agents. subscribers
+	// are released only for the agent that reported the status, so that the
+	// deployment outcome of every agent reaches the subscribers.
+	coordinator.mutex.Lock()
 	subs, ok := coordinator.subscribers[key]
 	if !ok {
+		coordinator.mutex.Unlock()
 		return
 	}
 
+	notify := []OnChangeCallback{}
+	remaining := []subscriber{}
 	for _, s := range subs {
-		s(orgId, agentId, key, err)
+		if s.agentId == agentId {
+			notify = append(notify, s.callback)
+		} else {
+			remaining = append(remaining, s)
+		}
 	}
 
-	// delete all subscribers for this key, assume future
-	// notifies will be disabled. the first response is processed
-	delete(coordinator.subscribers, key)
+	if len(remaining) == 0 {
+		delete(coordinator.subscribers, key)
+	} else {
+		coordinator.subscribers[key] = remaining
+	}
+	coordinator.mutex.Unlock()
+
+	for _, callback := range notify {
+		callback(orgId, agentId, key, err)
+	}
 }
 
 // callers subscribe to this function to listen on config change requests
@@ -66,10 +86,5 @@ func ListenToConfigUpdate(orgId valuer.UUID, agentId string, hash string, ss OnC
 	defer coordinator.mutex.Unlock()
 
 	key := getSubscriberKey(orgId, hash)
-	if subs, ok := coordinator.subscribers[key]; ok {
-		subs = append(subs, ss)
-		coordinator.subscribers[key] = subs
-	} else {
-		coordinator.subscribers[key] = []OnChangeCallback{ss}
-	}
+	coordinator.subscribers[key] = append(coordinator.subscribers[key], subscriber{agentId: agentId, callback: ss})
 }
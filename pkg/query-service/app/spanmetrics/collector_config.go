@@ -0,0 +1,247 @@
+package spanmetrics
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/SigNoz/signoz/pkg/query-service/model"
+	"github.com/SigNoz/signoz/pkg/querybuilder"
+	"github.com/SigNoz/signoz/pkg/types/spanmetrictypes"
+	"github.com/SigNoz/signoz/pkg/types/telemetrytypes"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// components generated for span metrics generators are identified by these prefixes
+const (
+	connectorPrefix = "spanmetrics/signoz_"
+	processorPrefix = "filter/signoz_spanmetrics_"
+	pipelinePrefix  = "traces/signoz_spanmetrics_"
+)
+
+type connectorDimension struct {
+	Name    string  `yaml:"name"`
+	Default *string `yaml:"default,omitempty"`
+}
+
+type connectorExplicitHistogram struct {
+	Buckets []string `yaml:"buckets"`
+}
+
+type connectorHistogram struct {
+	Explicit *connectorExplicitHistogram `yaml:"explicit,omitempty"`
+}
+
+// config of the spanmetrics connector
+type connectorConfig struct {
+	Namespace                   string               `yaml:"namespace"`
+	Histogram                   *connectorHistogram  `yaml:"histogram,omitempty"`
+	Dimensions                  []connectorDimension `yaml:"dimensions,omitempty"`
+	AggregationCardinalityLimit int                  `yaml:"aggregation_cardinality_limit"`
+}
+
+// config of the filter processor dropping the spans not matching the filter of a generator
+type filterProcessorConfig struct {
+	ErrorMode string `yaml:"error_mode"`
+	Traces    struct {
+		Span []string `yaml:"span"`
+	} `yaml:"traces"`
+}
+
+// generatorComponents are the collector components generating the metrics of a generator,
+// a traces pipeline of its own receiving the spans of the default traces pipeline, optionally
+// filtering them, and exporting them to a spanmetrics connector feeding the metrics pipeline.
+type generatorComponents struct {
+	connectorName string
+	connector     connectorConfig
+	processorName string
+	processor     *filterProcessorConfig
+	pipelineName  string
+}
+
+func componentsFor(generator spanmetrictypes.GettableGenerator) (*generatorComponents, error) {
+	id := generator.ID.StringValue()
+	components := &generatorComponents{
+		connectorName: connectorPrefix + id,
+		pipelineName:  pipelinePrefix + id,
+		connector: connectorConfig{
+			Namespace:                   generator.Name,
+			AggregationCardinalityLimit: generator.CardinalityLimit,
+		},
+	}
+
+	if len(generator.HistogramBuckets) > 0 {
+		explicit := &connectorExplicitHistogram{}
+		for _, bucket := range generator.HistogramBuckets {
+			explicit.Buckets = append(explicit.Buckets, strconv.FormatFloat(bucket, 'f', -1, 64)+"ms")
+		}
+		components.connector.Histogram = &connectorHistogram{Explicit: explicit}
+	}
+
+	for _, dimension := range generator.Dimensions {
+		components.connector.Dimensions = append(components.connector.Dimensions, connectorDimension{
+			Name:    dimension.Name,
+			Default: dimension.Default,
+		})
+	}
+
+	if generator.Filter != nil && strings.TrimSpace(generator.Filter.Expression) != "" {
+		condition, err := querybuilder.PrepareOTTLCondition(generator.Filter.Expression, querybuilder.OTTLConditionOpts{
+			FieldContext: telemetrytypes.FieldContextSpan,
+			Fields:       querybuilder.OTTLSpanFields,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		// the filter processor drops the spans matching its conditions
+		components.processorName = processorPrefix + id
+		components.processor = &filterProcessorConfig{ErrorMode: "ignore"}
+		components.processor.Traces.Span = []string{fmt.Sprintf("not (%s)", condition)}
+	}
+
+	return components, nil
+}
+
+// GenerateCollectorConfigWithSpanMetrics adds the components computing the metrics of `generators`
+// to the collector config, replacing the ones of previously deployed generators. The config is left
+// unchanged if it has no traces or metrics pipeline to connect the generators to.
+func GenerateCollectorConfigWithSpanMetrics(
+	config []byte,
+	generators []spanmetrictypes.GettableGenerator,
+) ([]byte, *model.ApiError) {
+	var collectorConf map[string]interface{}
+	if err := yaml.Unmarshal(config, &collectorConf); err != nil {
+		return nil, model.BadRequest(err)
+	}
+	if collectorConf == nil {
+		collectorConf = map[string]interface{}{}
+	}
+
+	service, _ := collectorConf["service"].(map[string]interface{})
+	pipelines, _ := service["pipelines"].(map[string]interface{})
+
+	// remove the components of previously deployed generators
+	connectors := removeGeneratedComponents(collectorConf, "connectors", connectorPrefix)
+	processors := removeGeneratedComponents(collectorConf, "processors", processorPrefix)
+	for name := range pipelines {
+		if strings.HasPrefix(name, pipelinePrefix) {
+			delete(pipelines, name)
+		}
+	}
+	metricsPipeline, _ := pipelines["metrics"].(map[string]interface{})
+	metricsReceivers := []interface{}{}
+	if metricsPipeline != nil {
+		current, _ := metricsPipeline["receivers"].([]interface{})
+		for _, receiver := range current {
+			if name, ok := receiver.(string); ok && strings.HasPrefix(name, connectorPrefix) {
+				continue
+			}
+			metricsReceivers = append(metricsReceivers, receiver)
+		}
+	}
+
+	enabled := []spanmetrictypes.GettableGenerator{}
+	for _, generator := range generators {
+		if generator.Enabled {
+			enabled = append(enabled, generator)
+		}
+	}
+
+	if len(enabled) > 0 {
+		tracesPipeline, _ := pipelines["traces"].(map[string]interface{})
+		if tracesPipeline == nil || metricsPipeline == nil {
+			// the rest of the recommended config must still reach the agent
+			zap.L().Warn("skipping span metrics, the collector config has no traces or metrics pipeline")
+			return config, nil
+		}
+
+		for _, generator := range enabled {
+			components, err := componentsFor(generator)
+			if err != nil {
+				return nil, model.BadRequest(errors.Wrapf(err, "could not generate collector config for span metrics generator %s", generator.Name))
+			}
+
+			connectorConf, err := toGenericConfig(components.connector)
+			if err != nil {
+				return nil, model.InternalError(err)
+			}
+			connectors[components.connectorName] = connectorConf
+
+			pipeline := map[string]interface{}{
+				"receivers": tracesPipeline["receivers"],
+				"exporters": []interface{}{components.connectorName},
+			}
+			if components.processor != nil {
+				processorConf, err := toGenericConfig(components.processor)
+				if err != nil {
+					return nil, model.InternalError(err)
+				}
+				processors[components.processorName] = processorConf
+				pipeline["processors"] = []interface{}{components.processorName}
+			}
+			pipelines[components.pipelineName] = pipeline
+
+			metricsReceivers = append(metricsReceivers, components.connectorName)
+		}
+	}
+
+	if metricsPipeline != nil && (metricsPipeline["receivers"] != nil || len(metricsReceivers) > 0) {
+		metricsPipeline["receivers"] = metricsReceivers
+	}
+	setComponents(collectorConf, "connectors", connectors)
+	setComponents(collectorConf, "processors", processors)
+
+	updatedConf, err := yaml.Marshal(collectorConf)
+	if err != nil {
+		return nil, model.InternalError(errors.Wrap(err, "could not marshal updated collector config"))
+	}
+
+	return updatedConf, nil
+}
+
+func removeGeneratedComponents(collectorConf map[string]interface{}, kind string, prefix string) map[string]interface{} {
+	components, _ := collectorConf[kind].(map[string]interface{})
+	if components == nil {
+		return map[string]interface{}{}
+	}
+
+	for name := range components {
+		if strings.HasPrefix(name, prefix) {
+			delete(components, name)
+		}
+	}
+
+	return components
+}
+
+func setComponents(collectorConf map[string]interface{}, kind string, components map[string]interface{}) {
+	if len(components) > 0 {
+		collectorConf[kind] = components
+		return
+	}
+
+	if existing, ok := collectorConf[kind].(map[string]interface{}); ok && len(existing) == 0 {
+		delete(collectorConf, kind)
+	}
+}
+
+// toGenericConfig round trips a config through yaml to get the generic representation used for the
+// rest of the collector config. Any `$`s are escaped as `$$$` so that they don't end up being treated
+// as env vars when loading collector config.
+func toGenericConfig(config any) (map[string]interface{}, error) {
+	serialized, err := yaml.Marshal(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not marshal component config")
+	}
+
+	var generic map[string]interface{}
+	if err := yaml.Unmarshal([]byte(strings.ReplaceAll(string(serialized), "$", "$$$")), &generic); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal dollar escaped component config")
+	}
+
+	return generic, nil
+}
@@ -0,0 +1,148 @@
+package spanmetrics
+
+import (
+	"testing"
+
+	"github.com/SigNoz/signoz/pkg/types"
+	qbtypes "github.com/SigNoz/signoz/pkg/types/querybuildertypes/querybuildertypesv5"
+	"github.com/SigNoz/signoz/pkg/types/spanmetrictypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+const testCollectorConf = `
+receivers:
+  otlp: {}
+  jaeger: {}
+processors:
+  batch: {}
+exporters:
+  clickhousetraces: {}
+  clickhousemetricswrite: {}
+service:
+  pipelines:
+    traces:
+      receivers: [otlp, jaeger]
+      processors: [batch]
+      exporters: [clickhousetraces]
+    metrics:
+      receivers: [otlp]
+      processors: [batch]
+      exporters: [clickhousemetricswrite]
+`
+
+func TestGenerateCollectorConfigWithSpanMetrics(t *testing.T) {
+	require := require.New(t)
+
+	defaultTenant := "unknown"
+	filtered := spanmetrictypes.GettableGenerator{
+		Identifiable: types.Identifiable{ID: valuer.GenerateUUID()},
+		Name:         "tenant_red",
+		Enabled:      true,
+		GeneratorConfig: spanmetrictypes.GeneratorConfig{
+			Filter:           &qbtypes.Filter{Expression: "kind = 2 AND resource.deployment.environment = 'prod'"},
+			Dimensions:       []spanmetrictypes.Dimension{{Name: "http.route"}, {Name: "tenant.id", Default: &defaultTenant}},
+			HistogramBuckets: []float64{2, 4.5, 100},
+			CardinalityLimit: 5000,
+		},
+	}
+	unfiltered := spanmetrictypes.GettableGenerator{
+		Identifiable: types.Identifiable{ID: valuer.GenerateUUID()},
+		Name:         "cluster_red",
+		Enabled:      true,
+		GeneratorConfig: spanmetrictypes.GeneratorConfig{
+			Dimensions:       []spanmetrictypes.Dimension{{Name: "k8s.cluster.name"}},
+			CardinalityLimit: 1000,
+		},
+	}
+	disabled := spanmetrictypes.GettableGenerator{
+		Identifiable: types.Identifiable{ID: valuer.GenerateUUID()},
+		Name:         "disabled",
+	}
+
+	updated, apiErr := GenerateCollectorConfigWithSpanMetrics(
+		[]byte(testCollectorConf), []spanmetrictypes.GettableGenerator{filtered, unfiltered, disabled},
+	)
+	require.Nil(apiErr)
+
+	var conf map[string]interface{}
+	require.NoError(yaml.Unmarshal(updated, &conf))
+
+	filteredConnector := connectorPrefix + filtered.ID.StringValue()
+	unfilteredConnector := connectorPrefix + unfiltered.ID.StringValue()
+
+	connectors := conf["connectors"].(map[string]interface{})
+	require.Len(connectors, 2)
+	connector := connectors[filteredConnector].(map[string]interface{})
+	require.Equal("tenant_red", connector["namespace"])
+	require.Equal(5000, connector["aggregation_cardinality_limit"])
+	require.Equal(
+		map[string]interface{}{"explicit": map[string]interface{}{"buckets": []interface{}{"2ms", "4.5ms", "100ms"}}},
+		connector["histogram"],
+	)
+	require.Equal([]interface{}{
+		map[string]interface{}{"name": "http.route"},
+		map[string]interface{}{"name": "tenant.id", "default": "unknown"},
+	}, connector["dimensions"])
+
+	processors := conf["processors"].(map[string]interface{})
+	require.Len(processors, 2)
+	filterProcessor := processors[processorPrefix+filtered.ID.StringValue()].(map[string]interface{})
+	require.Equal(
+		[]interface{}{`not (kind == 2 and resource.attributes["deployment.environment"] == "prod")`},
+		filterProcessor["traces"].(map[string]interface{})["span"],
+	)
+
+	pipelines := conf["service"].(map[string]interface{})["pipelines"].(map[string]interface{})
+	require.Equal(map[string]interface{}{
+		"receivers":  []interface{}{"otlp", "jaeger"},
+		"processors": []interface{}{processorPrefix + filtered.ID.StringValue()},
+		"exporters":  []interface{}{filteredConnector},
+	}, pipelines[pipelinePrefix+filtered.ID.StringValue()])
+	require.Equal(map[string]interface{}{
+		"receivers": []interface{}{"otlp", "jaeger"},
+		"exporters": []interface{}{unfilteredConnector},
+	}, pipelines[pipelinePrefix+unfiltered.ID.StringValue()])
+	require.Equal(
+		[]interface{}{"otlp", filteredConnector, unfilteredConnector},
+		pipelines["metrics"].(map[string]interface{})["receivers"],
+	)
+
+	// removing all generators must clean up the generated components
+	cleaned, apiErr := GenerateCollectorConfigWithSpanMetrics(updated, nil)
+	require.Nil(apiErr)
+
+	var expected, actual map[string]interface{}
+	require.NoError(yaml.Unmarshal([]byte(testCollectorConf), &expected))
+	require.NoError(yaml.Unmarshal(cleaned, &actual))
+	require.Equal(expected, actual)
+}
+
+func TestGenerateCollectorConfigWithSpanMetricsMissingPipeline(t *testing.T) {
+	conf := []byte("service:\n  pipelines:\n    metrics:\n      receivers: [otlp]\n")
+	generator := spanmetrictypes.GettableGenerator{
+		Identifiable: types.Identifiable{ID: valuer.GenerateUUID()},
+		Name:         "red",
+		Enabled:      true,
+	}
+
+	// the rest of the config is still recommended to the agent
+	updated, apiErr := GenerateCollectorConfigWithSpanMetrics(conf, []spanmetrictypes.GettableGenerator{generator})
+	require.Nil(t, apiErr)
+	require.Equal(t, conf, updated)
+}
+
+func TestGenerateCollectorConfigWithSpanMetricsErrors(t *testing.T) {
+	generator := spanmetrictypes.GettableGenerator{
+		Identifiable: types.Identifiable{ID: valuer.GenerateUUID()},
+		Name:         "red",
+		Enabled:      true,
+	}
+
+	generator.Filter = &qbtypes.Filter{Expression: "has(attribute.tags, 'a')"}
+	_, apiErr := GenerateCollectorConfigWithSpanMetrics(
+		[]byte(testCollectorConf), []spanmetrictypes.GettableGenerator{generator},
+	)
+	require.NotNil(t, apiErr)
+}
@@ -0,0 +1,68 @@
+package spanmetrics
+
+import (
+	"github.com/SigNoz/signoz/pkg/query-service/agentConf"
+	"github.com/SigNoz/signoz/pkg/query-service/app/agentelement"
+	"github.com/SigNoz/signoz/pkg/query-service/model"
+	"github.com/SigNoz/signoz/pkg/sqlstore"
+	"github.com/SigNoz/signoz/pkg/types/opamptypes"
+	"github.com/SigNoz/signoz/pkg/types/spanmetrictypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+	"github.com/pkg/errors"
+)
+
+const SpanMetricsFeatureType agentConf.AgentFeatureType = "span_metrics"
+
+// Controller manages span metrics generators and their deployment to agents,
+// the collectors compute RED metrics for the enabled ones with spanmetrics connectors.
+type Controller = agentelement.Controller[
+	spanmetrictypes.StorableGenerator,
+	*spanmetrictypes.StorableGenerator,
+	spanmetrictypes.GettableGenerator,
+	spanmetrictypes.PostableGenerator,
+]
+
+func NewController(sqlStore sqlstore.SQLStore) *Controller {
+	return agentelement.NewController(sqlStore, &agentelement.Kind[spanmetrictypes.StorableGenerator, *spanmetrictypes.StorableGenerator]{
+		Noun:                 "span metrics generator",
+		ElementType:          opamptypes.ElementTypeSpanMetrics,
+		FeatureType:          SpanMetricsFeatureType,
+		ErrCodeAlreadyExists: spanmetrictypes.ErrCodeGeneratorAlreadyExists,
+		ErrCodeNotFound:      spanmetrictypes.ErrCodeGeneratorNotFound,
+	}, definition{})
+}
+
+// Implements agentelement.Definition interface.
+type definition struct{}
+
+func (definition) NewStorable(orgID valuer.UUID, createdBy string, postable *spanmetrictypes.PostableGenerator) (*spanmetrictypes.StorableGenerator, error) {
+	return spanmetrictypes.NewStorableGenerator(orgID, createdBy, postable)
+}
+
+func (definition) NewRevision(storable *spanmetrictypes.StorableGenerator, updatedBy string, postable *spanmetrictypes.PostableGenerator) (*spanmetrictypes.StorableGenerator, error) {
+	return storable.NewRevision(updatedBy, postable)
+}
+
+func (definition) NewGettable(storable *spanmetrictypes.StorableGenerator) (*spanmetrictypes.GettableGenerator, error) {
+	return spanmetrictypes.NewGettableGenerator(storable)
+}
+
+func (definition) Validate(postable *spanmetrictypes.PostableGenerator) error {
+	if err := postable.Validate(); err != nil {
+		return err
+	}
+
+	if _, err := componentsFor(spanmetrictypes.GettableGenerator{
+		GeneratorConfig: postable.GeneratorConfig,
+		Name:            postable.Name,
+		Enabled:         true,
+	}); err != nil {
+		return errors.Wrap(err, "span metrics can not be generated by the collectors")
+	}
+
+	return nil
+}
+
+func (definition) GenerateCollectorConfig(config []byte, generators []spanmetrictypes.GettableGenerator) ([]byte, *model.ApiError) {
+	return GenerateCollectorConfigWithSpanMetrics(config, generators)
+}
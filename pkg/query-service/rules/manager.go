@@ -18,6 +18,7 @@ import (
 
 	"github.com/SigNoz/signoz/pkg/alertmanager"
 	"github.com/SigNoz/signoz/pkg/cache"
+	"github.com/SigNoz/signoz/pkg/modules/audit"
 	"github.com/SigNoz/signoz/pkg/modules/organization"
 	"github.com/SigNoz/signoz/pkg/prometheus"
 	"github.com/SigNoz/signoz/pkg/query-service/interfaces"
@@ -27,6 +28,7 @@ import (
 	"github.com/SigNoz/signoz/pkg/telemetrystore"
 	"github.com/SigNoz/signoz/pkg/types"
 	"github.com/SigNoz/signoz/pkg/types/alertmanagertypes"
+	"github.com/SigNoz/signoz/pkg/types/audittypes"
 	"github.com/SigNoz/signoz/pkg/types/authtypes"
 	ruletypes "github.com/SigNoz/signoz/pkg/types/ruletypes"
 	"github.com/SigNoz/signoz/pkg/valuer"
@@ -96,6 +98,7 @@ type ManagerOptions struct {
 	Alertmanager        alertmanager.Alertmanager
 	SQLStore            sqlstore.SQLStore
 	OrgGetter           organization.Getter
+	Audit               audit.Module
 }
 
 // The Manager manages recording and alerting rules.
@@ -118,6 +121,7 @@ type Manager struct {
 	alertmanager alertmanager.Alertmanager
 	sqlstore     sqlstore.SQLStore
 	orgGetter    organization.Getter
+	audit        audit.Module
 }
 
 func defaultOptions(o *ManagerOptions) *ManagerOptions {
@@ -213,6 +217,7 @@ func NewManager(o *ManagerOptions) (*Manager, error) {
 		alertmanager:        o.Alertmanager,
 		sqlstore:            o.SQLStore,
 		orgGetter:           o.OrgGetter,
+		audit:               o.Audit,
 	}
 
 	return m, nil
@@ -339,11 +344,12 @@ func (m *Manager) EditRule(ctx context.Context, ruleStr string, id valuer.UUID)
 		return err
 	}
 
+	before := existingRule.Data
 	existingRule.UpdatedAt = time.Now()
 	existingRule.UpdatedBy = claims.Email
 	existingRule.Data = ruleStr
 
-	return m.ruleStore.EditRule(ctx, existingRule, func(ctx context.Context) error {
+	err = m.ruleStore.EditRule(ctx, existingRule, func(ctx context.Context) error {
 		cfg, err := m.alertmanager.GetConfig(ctx, claims.OrgID)
 		if err != nil {
 			return err
@@ -380,6 +386,12 @@ func (m *Manager) EditRule(ctx context.Context, ruleStr string, id valuer.UUID)
 
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	m.recordRule(ctx, orgID, audittypes.ActionUpdate, id, before, ruleStr)
+	return nil
 }
 
 func (m *Manager) editTask(_ context.Context, orgID valuer.UUID, rule *ruletypes.PostableRule, taskName string) error {
@@ -448,12 +460,17 @@ func (m *Manager) DeleteRule(ctx context.Context, idStr string) error {
 		return err
 	}
 
-	_, err = m.ruleStore.GetStoredRule(ctx, id)
+	orgID, err := valuer.NewUUID(claims.OrgID)
+	if err != nil {
+		return err
+	}
+
+	existingRule, err := m.ruleStore.GetStoredRule(ctx, id)
 	if err != nil {
 		return err
 	}
 
-	return m.ruleStore.DeleteRule(ctx, id, func(ctx context.Context) error {
+	err = m.ruleStore.DeleteRule(ctx, id, func(ctx context.Context) error {
 		cfg, err := m.alertmanager.GetConfig(ctx, claims.OrgID)
 		if err != nil {
 			return err
@@ -474,6 +491,12 @@ func (m *Manager) DeleteRule(ctx context.Context, idStr string) error {
 
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	m.recordRule(ctx, orgID, audittypes.ActionDelete, id, existingRule.Data, "")
+	return nil
 }
 
 func (m *Manager) deleteTask(taskName string) {
@@ -568,6 +591,7 @@ func (m *Manager) CreateRule(ctx context.Context, ruleStr string) (*ruletypes.Ge
 		return nil, err
 	}
 
+	m.recordRule(ctx, orgID, audittypes.ActionCreate, id, "", ruleStr)
 	return &ruletypes.GettableRule{
 		Id:           id.StringValue(),
 		PostableRule: *parsedRule,
@@ -915,6 +939,7 @@ func (m *Manager) PatchRule(ctx context.Context, ruleStr string, id valuer.UUID)
 		return nil, err
 	}
 
+	before := storedJSON.Data
 	now := time.Now()
 	storedJSON.Data = string(patchedRuleBytes)
 	storedJSON.UpdatedBy = claims.Email
@@ -928,6 +953,8 @@ func (m *Manager) PatchRule(ctx context.Context, ruleStr string, id valuer.UUID)
 		return nil, err
 	}
 
+	m.recordRule(ctx, orgID, audittypes.ActionUpdate, id, before, storedJSON.Data)
+
 	// prepare http response
 	response := ruletypes.GettableRule{
 		Id:           id.StringValue(),
@@ -1048,3 +1075,20 @@ func (m *Manager) GetAlertDetailsForMetricNames(ctx context.Context, metricNames
 
 	return result, nil
 }
+
+// recordRule records a change to the rule, the rule definitions are json documents which are recorded as is.
+func (m *Manager) recordRule(ctx context.Context, orgID valuer.UUID, action audittypes.Action, id valuer.UUID, before string, after string) {
+	if m.audit == nil {
+		return
+	}
+
+	var beforeRule, afterRule any
+	if before != "" {
+		beforeRule = json.RawMessage(before)
+	}
+	if after != "" {
+		afterRule = json.RawMessage(after)
+	}
+
+	m.audit.Record(ctx, orgID, action, audittypes.NewResource(audittypes.ResourceTypeRule, id.StringValue()), beforeRule, afterRule)
+}
@@ -2,45 +2,36 @@ package telemetry
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"slices"
+	"regexp"
 	"strings"
 
 	"github.com/SigNoz/signoz/pkg/query-service/model"
-	v3 "github.com/SigNoz/signoz/pkg/query-service/model/v3"
 	"github.com/SigNoz/signoz/pkg/sqlstore"
 	"github.com/SigNoz/signoz/pkg/types"
+	qbtypes "github.com/SigNoz/signoz/pkg/types/querybuildertypes/querybuildertypesv5"
+	"github.com/SigNoz/signoz/pkg/types/savedviewtypes"
 	"go.uber.org/zap"
 )
 
-func GetViews(ctx context.Context, sqlstore sqlstore.SQLStore, orgID string) ([]*v3.SavedView, error) {
-	var views []types.SavedView
+// containsRegex matches the conditions of a filter expression using contains or like, the key of the condition is
+// captured.
+var containsRegex = regexp.MustCompile(`(?i)([\w.\[\]]+)\s+(?:not\s+)?(?:contains|like|ilike)\b`)
+
+func GetViews(ctx context.Context, sqlstore sqlstore.SQLStore, orgID string) ([]*savedviewtypes.SavedView, error) {
+	var views []*savedviewtypes.StorableSavedView
 	err := sqlstore.BunDB().NewSelect().Model(&views).Where("org_id = ?", orgID).Scan(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("error in getting saved views: %s", err.Error())
 	}
 
-	var savedViews []*v3.SavedView
+	var savedViews []*savedviewtypes.SavedView
 	for _, view := range views {
-		var compositeQuery v3.CompositeQuery
-		err = json.Unmarshal([]byte(view.Data), &compositeQuery)
+		savedView, err := savedviewtypes.NewSavedViewFromStorable(view)
 		if err != nil {
 			return nil, fmt.Errorf("error in unmarshalling explorer query data: %s", err.Error())
 		}
-		savedViews = append(savedViews, &v3.SavedView{
-			ID:             view.ID,
-			Name:           view.Name,
-			Category:       view.Category,
-			CreatedAt:      view.CreatedAt,
-			CreatedBy:      view.CreatedBy,
-			UpdatedAt:      view.UpdatedAt,
-			UpdatedBy:      view.UpdatedBy,
-			Tags:           strings.Split(view.Tags, ","),
-			SourcePage:     view.SourcePage,
-			CompositeQuery: &compositeQuery,
-			ExtraData:      view.ExtraData,
-		})
+		savedViews = append(savedViews, savedView)
 	}
 	return savedViews, nil
 }
@@ -70,14 +61,15 @@ func GetSavedViewsInfo(ctx context.Context, sqlstore sqlstore.SQLStore) (*model.
 		} else if view.SourcePage == "logs" {
 			savedViewsInfo.LogsSavedViews += 1
 
-			for _, query := range view.CompositeQuery.BuilderQueries {
-				if query.Filters != nil {
-					for _, item := range query.Filters.Items {
-						if slices.Contains([]string{"contains", "ncontains", "like", "nlike"}, string(item.Operator)) {
-							if item.Key.Key != "body" {
-								savedViewsInfo.LogsSavedViewWithContainsOp += 1
-							}
-						}
+			for _, envelope := range view.CompositeQuery.Queries {
+				query, ok := envelope.Spec.(qbtypes.QueryBuilderQuery[qbtypes.LogAggregation])
+				if !ok || query.Filter == nil {
+					continue
+				}
+
+				for _, match := range containsRegex.FindAllStringSubmatch(query.Filter.Expression, -1) {
+					if strings.ToLower(match[1]) != "body" {
+						savedViewsInfo.LogsSavedViewWithContainsOp += 1
 					}
 				}
 			}
@@ -72,6 +72,27 @@ func NewTestSqliteDB(t *testing.T) (sqlStore sqlstore.SQLStore, testDBFilePath s
 			sqlmigration.NewAddKeyOrganizationFactory(sqlStore),
 			sqlmigration.NewUpdateDashboardFactory(sqlStore),
 			sqlmigration.NewUpdateAgentsFactory(sqlStore),
+			sqlmigration.NewAddAgentRolloutsFactory(sqlStore),
+			sqlmigration.NewAddDerivedMetricsFactory(sqlStore),
+			sqlmigration.NewAddSpanMetricsGeneratorsFactory(sqlStore),
+			sqlmigration.NewAddCustomRolesFactory(sqlStore),
+			sqlmigration.NewAddSCIMFactory(sqlStore),
+			sqlmigration.NewAddFactorTOTPFactory(sqlStore),
+			sqlmigration.NewAddAuditFactory(sqlStore),
+			sqlmigration.NewUpdateAPIKeyRestrictionsFactory(sqlStore),
+			sqlmigration.NewAddSessionFactory(sqlStore),
+			sqlmigration.NewAddOrgMembershipFactory(sqlStore),
+			sqlmigration.NewAddTeamFactory(sqlStore),
+			sqlmigration.NewAddDashboardRevisionFactory(sqlStore),
+			sqlmigration.NewAddManagedResourceFactory(sqlStore),
+			sqlmigration.NewAddShareLinkFactory(sqlStore),
+			sqlmigration.NewAddReportFactory(sqlStore),
+			sqlmigration.NewAddDashboardAccessFactory(sqlStore),
+			sqlmigration.NewAddDashboardFolderParentFactory(sqlStore),
+			sqlmigration.NewUpdateSavedViewsFactory(sqlStore),
+			sqlmigration.NewAddAnnotationFactory(sqlStore),
+			sqlmigration.NewUpdateAgentElementsFactory(sqlStore),
+			sqlmigration.NewAddAPIKeyOrgFactory(sqlStore),
 		),
 	)
 	if err != nil {
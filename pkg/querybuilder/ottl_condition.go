@@ -0,0 +1,331 @@
+package querybuilder
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/SigNoz/signoz/pkg/errors"
+	grammar "github.com/SigNoz/signoz/pkg/parser/grammar"
+	"github.com/SigNoz/signoz/pkg/types/telemetrytypes"
+	"github.com/antlr4-go/antlr/v4"
+)
+
+var (
+	// top level fields of a log record in the OTTL log context, keyed by their name in the query builder
+	OTTLLogFields = map[string]string{
+		"body":            "body",
+		"severity_text":   "severity_text",
+		"severity_number": "severity_number",
+		"trace_id":        "trace_id.string",
+		"span_id":         "span_id.string",
+	}
+
+	// top level fields of a span in the OTTL span context, keyed by their name in the query builder
+	OTTLSpanFields = map[string]string{
+		"name":           "name",
+		"kind":           "kind",
+		"duration_nano":  "(end_time_unix_nano - start_time_unix_nano)",
+		"status_code":    "status.code",
+		"status_message": "status.message",
+		"trace_id":       "trace_id.string",
+		"span_id":        "span_id.string",
+		"parent_span_id": "parent_span_id.string",
+	}
+)
+
+type OTTLConditionOpts struct {
+	// field context of the top level fields of the signal, FieldContextLog or FieldContextSpan
+	FieldContext telemetrytypes.FieldContext
+	// OTTL paths of the top level fields of the signal
+	Fields map[string]string
+	// field searched by full text search, if any
+	FullTextField string
+}
+
+// ottlConditionBuilder translates parsed filter expressions to OTTL conditions
+type ottlConditionBuilder struct {
+	opts OTTLConditionOpts
+}
+
+// PrepareOTTLCondition translates a filter expression to an OTTL condition, as used by
+// collector components like the filter processor and the count, sum and spanmetrics connectors.
+//
+// Keys without a field context are looked up in both the attributes and the resource
+// attributes, like the query builder does.
+func PrepareOTTLCondition(expression string, opts OTTLConditionOpts) (string, error) {
+	if strings.TrimSpace(expression) == "" {
+		return "true", nil
+	}
+
+	lexer := grammar.NewFilterQueryLexer(antlr.NewInputStream(expression))
+	lexerErrorListener := NewErrorListener()
+	lexer.RemoveErrorListeners()
+	lexer.AddErrorListener(lexerErrorListener)
+
+	parser := grammar.NewFilterQueryParser(antlr.NewCommonTokenStream(lexer, 0))
+	parserErrorListener := NewErrorListener()
+	parser.RemoveErrorListeners()
+	parser.AddErrorListener(parserErrorListener)
+
+	tree := parser.Query()
+	syntaxErrors := append(lexerErrorListener.SyntaxErrors, parserErrorListener.SyntaxErrors...)
+	if len(syntaxErrors) > 0 {
+		return "", errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "invalid filter expression %q: %s", expression, syntaxErrors[0].Error())
+	}
+
+	builder := &ottlConditionBuilder{opts: opts}
+	return builder.orExpression(tree.Expression().OrExpression())
+}
+
+func (b *ottlConditionBuilder) orExpression(ctx grammar.IOrExpressionContext) (string, error) {
+	conditions := []string{}
+	for _, expr := range ctx.AllAndExpression() {
+		condition, err := b.andExpression(expr)
+		if err != nil {
+			return "", err
+		}
+		conditions = append(conditions, condition)
+	}
+
+	return strings.Join(conditions, " or "), nil
+}
+
+func (b *ottlConditionBuilder) andExpression(ctx grammar.IAndExpressionContext) (string, error) {
+	conditions := []string{}
+	for _, expr := range ctx.AllUnaryExpression() {
+		condition, err := b.unaryExpression(expr)
+		if err != nil {
+			return "", err
+		}
+		conditions = append(conditions, condition)
+	}
+
+	return strings.Join(conditions, " and "), nil
+}
+
+func (b *ottlConditionBuilder) unaryExpression(ctx grammar.IUnaryExpressionContext) (string, error) {
+	condition, err := b.primary(ctx.Primary())
+	if err != nil {
+		return "", err
+	}
+
+	if ctx.NOT() != nil {
+		return fmt.Sprintf("not (%s)", condition), nil
+	}
+
+	return condition, nil
+}
+
+func (b *ottlConditionBuilder) primary(ctx grammar.IPrimaryContext) (string, error) {
+	switch {
+	case ctx.OrExpression() != nil:
+		condition, err := b.orExpression(ctx.OrExpression())
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("(%s)", condition), nil
+	case ctx.Comparison() != nil:
+		return b.comparison(ctx.Comparison())
+	case ctx.FunctionCall() != nil:
+		return "", errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "function `%s` is not supported in OTTL conditions", ctx.FunctionCall().GetText())
+	case ctx.FullText() != nil:
+		text := ctx.FullText().GetText()
+		if ctx.FullText().QUOTED_TEXT() != nil {
+			text = trimQuotes(text)
+		}
+		return b.fullText(text)
+	case ctx.Key() != nil:
+		return b.fullText(ctx.Key().GetText())
+	case ctx.Value() != nil:
+		return b.fullText(ottlValueText(ctx.Value()))
+	}
+
+	return "", errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "unsupported filter expression `%s`", ctx.GetText())
+}
+
+func (b *ottlConditionBuilder) fullText(text string) (string, error) {
+	if b.opts.FullTextField == "" {
+		return "", errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "full text search is not supported")
+	}
+
+	return ottlIsMatch(b.opts.FullTextField, text), nil
+}
+
+func (b *ottlConditionBuilder) comparison(ctx grammar.IComparisonContext) (string, error) {
+	keyText := ctx.Key().GetText()
+	paths, err := b.paths(telemetrytypes.GetFieldKeyFromKeyText(keyText))
+	if err != nil {
+		return "", err
+	}
+
+	values := []string{}
+	for _, value := range ctx.AllValue() {
+		values = append(values, ottlValue(value))
+	}
+
+	// for negative operators a key without a field context must not match
+	// in any of the places it is looked up in
+	negative := ctx.NOT() != nil || ctx.NotInClause() != nil || ctx.NOT_EQUALS() != nil ||
+		ctx.NEQ() != nil || ctx.NOT_LIKE() != nil || ctx.NOT_ILIKE() != nil
+	negatedMatch := ctx.NOT_LIKE() != nil || ctx.NOT_ILIKE() != nil ||
+		(ctx.NOT() != nil && (ctx.REGEXP() != nil || ctx.CONTAINS() != nil))
+
+	conditions := []string{}
+	for _, path := range paths {
+		var condition string
+		switch {
+		case ctx.EXISTS() != nil:
+			condition = fmt.Sprintf("%s != nil", path)
+			if ctx.NOT() != nil {
+				condition = fmt.Sprintf("%s == nil", path)
+			}
+		case ctx.InClause() != nil || ctx.NotInClause() != nil:
+			var valueList grammar.IValueListContext
+			if ctx.InClause() != nil {
+				valueList = ctx.InClause().ValueList()
+			} else {
+				valueList = ctx.NotInClause().ValueList()
+			}
+			if valueList == nil {
+				return "", errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "IN of `%s` expects a list of values", keyText)
+			}
+			operator, join := "==", " or "
+			if ctx.NotInClause() != nil {
+				operator, join = "!=", " and "
+			}
+			inConditions := []string{}
+			for _, value := range valueList.AllValue() {
+				inConditions = append(inConditions, fmt.Sprintf("%s %s %s", path, operator, ottlValue(value)))
+			}
+			condition = fmt.Sprintf("(%s)", strings.Join(inConditions, join))
+		case ctx.BETWEEN() != nil:
+			if len(values) != 2 {
+				return "", errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "BETWEEN of `%s` expects two values", keyText)
+			}
+			condition = fmt.Sprintf("(%s >= %s and %s <= %s)", path, values[0], path, values[1])
+			if ctx.NOT() != nil {
+				condition = fmt.Sprintf("(%s < %s or %s > %s)", path, values[0], path, values[1])
+			}
+		case len(values) == 0:
+			return "", errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "missing value for `%s`", keyText)
+		case ctx.EQUALS() != nil:
+			condition = fmt.Sprintf("%s == %s", path, values[0])
+		case ctx.NOT_EQUALS() != nil || ctx.NEQ() != nil:
+			condition = fmt.Sprintf("%s != %s", path, values[0])
+		case ctx.LT() != nil:
+			condition = fmt.Sprintf("%s < %s", path, values[0])
+		case ctx.LE() != nil:
+			condition = fmt.Sprintf("%s <= %s", path, values[0])
+		case ctx.GT() != nil:
+			condition = fmt.Sprintf("%s > %s", path, values[0])
+		case ctx.GE() != nil:
+			condition = fmt.Sprintf("%s >= %s", path, values[0])
+		case ctx.LIKE() != nil, ctx.NOT_LIKE() != nil:
+			condition = ottlIsMatch(path, likeToRegex(ottlValueText(ctx.Value(0)), false))
+		case ctx.ILIKE() != nil, ctx.NOT_ILIKE() != nil:
+			condition = ottlIsMatch(path, likeToRegex(ottlValueText(ctx.Value(0)), true))
+		case ctx.REGEXP() != nil:
+			condition = ottlIsMatch(path, ottlValueText(ctx.Value(0)))
+		case ctx.CONTAINS() != nil:
+			condition = ottlIsMatch(path, "(?i)"+regexp.QuoteMeta(ottlValueText(ctx.Value(0))))
+		default:
+			return "", errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "unsupported comparison `%s`", ctx.GetText())
+		}
+
+		if negatedMatch {
+			condition = fmt.Sprintf("not %s", condition)
+		}
+
+		conditions = append(conditions, condition)
+	}
+
+	if len(conditions) == 1 {
+		return conditions[0], nil
+	}
+
+	if negative {
+		return fmt.Sprintf("(%s)", strings.Join(conditions, " and ")), nil
+	}
+
+	return fmt.Sprintf("(%s)", strings.Join(conditions, " or ")), nil
+}
+
+// paths returns the OTTL paths a query builder key is looked up in.
+func (b *ottlConditionBuilder) paths(key telemetrytypes.TelemetryFieldKey) ([]string, error) {
+	attribute := fmt.Sprintf("attributes[%s]", ottlString(key.Name))
+	resourceAttribute := fmt.Sprintf("resource.attributes[%s]", ottlString(key.Name))
+
+	switch key.FieldContext {
+	case telemetrytypes.FieldContextAttribute:
+		return []string{attribute}, nil
+	case telemetrytypes.FieldContextResource:
+		return []string{resourceAttribute}, nil
+	case b.opts.FieldContext:
+		if path, ok := b.opts.Fields[key.Name]; ok {
+			return []string{path}, nil
+		}
+		return nil, errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "unknown %s field `%s`", key.FieldContext.StringValue(), key.Name)
+	case telemetrytypes.FieldContextUnspecified:
+		if path, ok := b.opts.Fields[key.Name]; ok {
+			return []string{path}, nil
+		}
+		if strings.HasPrefix(key.Name, "body.") {
+			return nil, errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "JSON body search on `%s` is not supported in OTTL conditions", key.Name)
+		}
+		return []string{attribute, resourceAttribute}, nil
+	}
+
+	return nil, errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "keys of context `%s` are not supported in OTTL conditions", key.FieldContext.StringValue())
+}
+
+func ottlValue(ctx grammar.IValueContext) string {
+	switch {
+	case ctx.NUMBER() != nil:
+		return ctx.NUMBER().GetText()
+	case ctx.BOOL() != nil:
+		return strings.ToLower(ctx.BOOL().GetText())
+	}
+
+	return ottlString(ottlValueText(ctx))
+}
+
+func ottlValueText(ctx grammar.IValueContext) string {
+	if ctx.QUOTED_TEXT() != nil {
+		return trimQuotes(ctx.QUOTED_TEXT().GetText())
+	}
+
+	return ctx.GetText()
+}
+
+func ottlIsMatch(path string, pattern string) string {
+	return fmt.Sprintf("IsMatch(%s, %s)", path, ottlString(pattern))
+}
+
+func ottlString(value string) string {
+	escaped := strings.ReplaceAll(value, `\`, `\\`)
+	escaped = strings.ReplaceAll(escaped, `"`, `\"`)
+	return fmt.Sprintf(`"%s"`, escaped)
+}
+
+// likeToRegex converts a SQL LIKE pattern to an anchored regular expression.
+func likeToRegex(pattern string, caseInsensitive bool) string {
+	regex := strings.Builder{}
+	if caseInsensitive {
+		regex.WriteString("(?i)")
+	}
+	regex.WriteString("^")
+	for _, char := range pattern {
+		switch char {
+		case '%':
+			regex.WriteString(".*")
+		case '_':
+			regex.WriteString(".")
+		default:
+			regex.WriteString(regexp.QuoteMeta(string(char)))
+		}
+	}
+	regex.WriteString("$")
+
+	return regex.String()
+}
@@ -0,0 +1,112 @@
+package querybuilder
+
+import (
+	"testing"
+
+	"github.com/SigNoz/signoz/pkg/types/telemetrytypes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrepareOTTLConditionForLogs(t *testing.T) {
+	testCases := []struct {
+		name       string
+		expression string
+		expected   string
+		wantErr    bool
+	}{
+		{
+			name:       "empty filter",
+			expression: "",
+			expected:   "true",
+		},
+		{
+			name:       "attribute equals",
+			expression: "attribute.http.method = 'GET'",
+			expected:   `attributes["http.method"] == "GET"`,
+		},
+		{
+			name:       "key without context is looked up in attributes and resource",
+			expression: "service.name = 'api'",
+			expected:   `(attributes["service.name"] == "api" or resource.attributes["service.name"] == "api")`,
+		},
+		{
+			name:       "negative operator on key without context",
+			expression: "service.name != 'api'",
+			expected:   `(attributes["service.name"] != "api" and resource.attributes["service.name"] != "api")`,
+		},
+		{
+			name:       "log fields, numbers and boolean logic",
+			expression: "severity_text = 'ERROR' AND (severity_number >= 17 OR NOT resource.env = 'dev')",
+			expected:   `severity_text == "ERROR" and (severity_number >= 17 or not (resource.attributes["env"] == "dev"))`,
+		},
+		{
+			name:       "in and exists",
+			expression: "attribute.status IN (500, 503) AND attribute.user_id EXISTS",
+			expected:   `(attributes["status"] == 500 or attributes["status"] == 503) and attributes["user_id"] != nil`,
+		},
+		{
+			name:       "like and regexp",
+			expression: "attribute.path LIKE '/api/%' AND attribute.path NOT REGEXP 'health'",
+			expected:   `IsMatch(attributes["path"], "^/api/.*$") and not IsMatch(attributes["path"], "health")`,
+		},
+		{
+			name:       "between",
+			expression: "attribute.duration BETWEEN 100 AND 200",
+			expected:   `(attributes["duration"] >= 100 and attributes["duration"] <= 200)`,
+		},
+		{
+			name:       "full text search on body",
+			expression: "'connection refused'",
+			expected:   `IsMatch(body, "connection refused")`,
+		},
+		{
+			name:       "quotes are escaped",
+			expression: `attribute.message = 'say "hi"'`,
+			expected:   `attributes["message"] == "say \"hi\""`,
+		},
+		{
+			name:       "functions are not supported",
+			expression: "has(body.tags, 'a')",
+			wantErr:    true,
+		},
+		{
+			name:       "syntax error",
+			expression: "attribute.status = ",
+			wantErr:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			condition, err := PrepareOTTLCondition(tc.expression, OTTLConditionOpts{
+				FieldContext:  telemetrytypes.FieldContextLog,
+				Fields:        OTTLLogFields,
+				FullTextField: OTTLLogFields["body"],
+			})
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, condition)
+		})
+	}
+}
+
+func TestPrepareOTTLConditionForSpans(t *testing.T) {
+	opts := OTTLConditionOpts{
+		FieldContext: telemetrytypes.FieldContextSpan,
+		Fields:       OTTLSpanFields,
+	}
+
+	condition, err := PrepareOTTLCondition("span.name = 'GET /orders' AND duration_nano > 1000000 AND resource.deployment.environment = 'prod'", opts)
+	require.NoError(t, err)
+	require.Equal(t, `name == "GET /orders" and (end_time_unix_nano - start_time_unix_nano) > 1000000 and resource.attributes["deployment.environment"] == "prod"`, condition)
+
+	_, err = PrepareOTTLCondition("'full text'", opts)
+	require.Error(t, err)
+
+	_, err = PrepareOTTLCondition("span.unknown_field = 1", opts)
+	require.Error(t, err)
+}
@@ -1,18 +1,36 @@
 package signoz
 
 import (
+	"github.com/SigNoz/signoz/pkg/modules/annotation"
+	"github.com/SigNoz/signoz/pkg/modules/annotation/implannotation"
 	"github.com/SigNoz/signoz/pkg/modules/apdex"
 	"github.com/SigNoz/signoz/pkg/modules/apdex/implapdex"
+	"github.com/SigNoz/signoz/pkg/modules/audit"
+	"github.com/SigNoz/signoz/pkg/modules/audit/implaudit"
 	"github.com/SigNoz/signoz/pkg/modules/dashboard"
 	"github.com/SigNoz/signoz/pkg/modules/dashboard/impldashboard"
 	"github.com/SigNoz/signoz/pkg/modules/organization"
 	"github.com/SigNoz/signoz/pkg/modules/organization/implorganization"
 	"github.com/SigNoz/signoz/pkg/modules/preference"
 	"github.com/SigNoz/signoz/pkg/modules/preference/implpreference"
+	"github.com/SigNoz/signoz/pkg/modules/provisioning"
+	"github.com/SigNoz/signoz/pkg/modules/provisioning/implprovisioning"
 	"github.com/SigNoz/signoz/pkg/modules/quickfilter"
 	"github.com/SigNoz/signoz/pkg/modules/quickfilter/implquickfilter"
+	"github.com/SigNoz/signoz/pkg/modules/report"
+	"github.com/SigNoz/signoz/pkg/modules/report/implreport"
+	"github.com/SigNoz/signoz/pkg/modules/role"
+	"github.com/SigNoz/signoz/pkg/modules/role/implrole"
 	"github.com/SigNoz/signoz/pkg/modules/savedview"
 	"github.com/SigNoz/signoz/pkg/modules/savedview/implsavedview"
+	"github.com/SigNoz/signoz/pkg/modules/scim"
+	"github.com/SigNoz/signoz/pkg/modules/scim/implscim"
+	"github.com/SigNoz/signoz/pkg/modules/search"
+	"github.com/SigNoz/signoz/pkg/modules/search/implsearch"
+	"github.com/SigNoz/signoz/pkg/modules/share"
+	"github.com/SigNoz/signoz/pkg/modules/share/implshare"
+	"github.com/SigNoz/signoz/pkg/modules/team"
+	"github.com/SigNoz/signoz/pkg/modules/team/implteam"
 	"github.com/SigNoz/signoz/pkg/modules/tracefunnel"
 	"github.com/SigNoz/signoz/pkg/modules/tracefunnel/impltracefunnel"
 	"github.com/SigNoz/signoz/pkg/modules/user"
@@ -28,6 +46,15 @@ type Handlers struct {
 	Dashboard    dashboard.Handler
 	QuickFilter  quickfilter.Handler
 	TraceFunnel  tracefunnel.Handler
+	Role         role.Handler
+	SCIM         scim.Handler
+	Audit        audit.Handler
+	Team         team.Handler
+	Provisioning provisioning.Handler
+	Share        share.Handler
+	Report       report.Handler
+	Search       search.Handler
+	Annotation   annotation.Handler
 }
 
 func NewHandlers(modules Modules) Handlers {
@@ -35,10 +62,19 @@ func NewHandlers(modules Modules) Handlers {
 		Organization: implorganization.NewHandler(modules.OrgGetter, modules.OrgSetter),
 		Preference:   implpreference.NewHandler(modules.Preference),
 		User:         impluser.NewHandler(modules.User),
-		SavedView:    implsavedview.NewHandler(modules.SavedView),
+		SavedView:    implsavedview.NewHandler(modules.SavedView, modules.Team),
 		Apdex:        implapdex.NewHandler(modules.Apdex),
 		Dashboard:    impldashboard.NewHandler(modules.Dashboard),
 		QuickFilter:  implquickfilter.NewHandler(modules.QuickFilter),
 		TraceFunnel:  impltracefunnel.NewHandler(modules.TraceFunnel),
+		Role:         implrole.NewHandler(modules.Role),
+		SCIM:         implscim.NewHandler(modules.SCIM),
+		Audit:        implaudit.NewHandler(modules.Audit),
+		Team:         implteam.NewHandler(modules.Team, modules.Dashboard, modules.SavedView),
+		Provisioning: implprovisioning.NewHandler(modules.Provisioning),
+		Share:        implshare.NewHandler(modules.Share),
+		Report:       implreport.NewHandler(modules.Report),
+		Search:       implsearch.NewHandler(modules.Search),
+		Annotation:   implannotation.NewHandler(modules.Annotation),
 	}
 }
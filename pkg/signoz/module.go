@@ -5,37 +5,67 @@ import (
 	"github.com/SigNoz/signoz/pkg/analytics"
 	"github.com/SigNoz/signoz/pkg/emailing"
 	"github.com/SigNoz/signoz/pkg/factory"
+	"github.com/SigNoz/signoz/pkg/modules/annotation"
+	"github.com/SigNoz/signoz/pkg/modules/annotation/implannotation"
 	"github.com/SigNoz/signoz/pkg/modules/apdex"
 	"github.com/SigNoz/signoz/pkg/modules/apdex/implapdex"
+	"github.com/SigNoz/signoz/pkg/modules/audit"
+	"github.com/SigNoz/signoz/pkg/modules/audit/implaudit"
 	"github.com/SigNoz/signoz/pkg/modules/dashboard"
 	"github.com/SigNoz/signoz/pkg/modules/dashboard/impldashboard"
 	"github.com/SigNoz/signoz/pkg/modules/organization"
 	"github.com/SigNoz/signoz/pkg/modules/organization/implorganization"
 	"github.com/SigNoz/signoz/pkg/modules/preference"
 	"github.com/SigNoz/signoz/pkg/modules/preference/implpreference"
+	"github.com/SigNoz/signoz/pkg/modules/provisioning"
+	"github.com/SigNoz/signoz/pkg/modules/provisioning/implprovisioning"
 	"github.com/SigNoz/signoz/pkg/modules/quickfilter"
 	"github.com/SigNoz/signoz/pkg/modules/quickfilter/implquickfilter"
+	"github.com/SigNoz/signoz/pkg/modules/report"
+	"github.com/SigNoz/signoz/pkg/modules/report/implreport"
+	"github.com/SigNoz/signoz/pkg/modules/role"
+	"github.com/SigNoz/signoz/pkg/modules/role/implrole"
 	"github.com/SigNoz/signoz/pkg/modules/savedview"
 	"github.com/SigNoz/signoz/pkg/modules/savedview/implsavedview"
+	"github.com/SigNoz/signoz/pkg/modules/scim"
+	"github.com/SigNoz/signoz/pkg/modules/scim/implscim"
+	"github.com/SigNoz/signoz/pkg/modules/search"
+	"github.com/SigNoz/signoz/pkg/modules/search/implsearch"
+	"github.com/SigNoz/signoz/pkg/modules/share"
+	"github.com/SigNoz/signoz/pkg/modules/share/implshare"
+	"github.com/SigNoz/signoz/pkg/modules/team"
+	"github.com/SigNoz/signoz/pkg/modules/team/implteam"
 	"github.com/SigNoz/signoz/pkg/modules/tracefunnel"
 	"github.com/SigNoz/signoz/pkg/modules/tracefunnel/impltracefunnel"
 	"github.com/SigNoz/signoz/pkg/modules/user"
 	"github.com/SigNoz/signoz/pkg/modules/user/impluser"
+	"github.com/SigNoz/signoz/pkg/querier"
+	"github.com/SigNoz/signoz/pkg/ruler/rulestore/sqlrulestore"
 	"github.com/SigNoz/signoz/pkg/sqlstore"
+	"github.com/SigNoz/signoz/pkg/telemetrystore"
 	"github.com/SigNoz/signoz/pkg/types/authtypes"
 	"github.com/SigNoz/signoz/pkg/types/preferencetypes"
 )
 
 type Modules struct {
-	OrgGetter   organization.Getter
-	OrgSetter   organization.Setter
-	Preference  preference.Module
-	User        user.Module
-	SavedView   savedview.Module
-	Apdex       apdex.Module
-	Dashboard   dashboard.Module
-	QuickFilter quickfilter.Module
-	TraceFunnel tracefunnel.Module
+	OrgGetter    organization.Getter
+	OrgSetter    organization.Setter
+	Preference   preference.Module
+	User         user.Module
+	SavedView    savedview.Module
+	Apdex        apdex.Module
+	Dashboard    dashboard.Module
+	QuickFilter  quickfilter.Module
+	TraceFunnel  tracefunnel.Module
+	Role         role.Module
+	SCIM         scim.Module
+	Audit        audit.Module
+	Team         team.Module
+	Provisioning provisioning.Module
+	Share        share.Module
+	Report       report.Module
+	Search       search.Module
+	Annotation   annotation.Module
 }
 
 func NewModules(
@@ -46,19 +76,36 @@ func NewModules(
 	orgGetter organization.Getter,
 	alertmanager alertmanager.Alertmanager,
 	analytics analytics.Analytics,
+	querier querier.Querier,
+	telemetryStore telemetrystore.TelemetryStore,
 ) Modules {
+	audit := implaudit.NewModule(implaudit.NewStore(sqlstore), providerSettings)
 	quickfilter := implquickfilter.NewModule(implquickfilter.NewStore(sqlstore))
 	orgSetter := implorganization.NewSetter(implorganization.NewStore(sqlstore), alertmanager, quickfilter)
-	user := impluser.NewModule(impluser.NewStore(sqlstore, providerSettings), jwt, emailing, providerSettings, orgSetter, analytics)
+	preference := implpreference.NewModule(implpreference.NewStore(sqlstore), preferencetypes.NewAvailablePreference())
+	user := impluser.NewModule(impluser.NewStore(sqlstore, providerSettings), jwt, emailing, providerSettings, orgSetter, preference, analytics, audit)
+	role := implrole.NewModule(implrole.NewStore(sqlstore), user)
+	savedView := implsavedview.NewModule(implsavedview.NewStore(sqlstore), preference, providerSettings, audit)
+	dashboard := impldashboard.NewModule(sqlstore, providerSettings, analytics, audit)
+	ruleStore := sqlrulestore.NewRuleStore(sqlstore)
 	return Modules{
-		OrgGetter:   orgGetter,
-		OrgSetter:   orgSetter,
-		Preference:  implpreference.NewModule(implpreference.NewStore(sqlstore), preferencetypes.NewAvailablePreference()),
-		SavedView:   implsavedview.NewModule(sqlstore),
-		Apdex:       implapdex.NewModule(sqlstore),
-		Dashboard:   impldashboard.NewModule(sqlstore, providerSettings, analytics),
-		User:        user,
-		QuickFilter: quickfilter,
-		TraceFunnel: impltracefunnel.NewModule(impltracefunnel.NewStore(sqlstore)),
+		OrgGetter:    orgGetter,
+		OrgSetter:    orgSetter,
+		Preference:   preference,
+		SavedView:    savedView,
+		Apdex:        implapdex.NewModule(sqlstore),
+		Dashboard:    dashboard,
+		User:         user,
+		QuickFilter:  quickfilter,
+		TraceFunnel:  impltracefunnel.NewModule(impltracefunnel.NewStore(sqlstore)),
+		Role:         role,
+		SCIM:         implscim.NewModule(implscim.NewStore(sqlstore), user, providerSettings),
+		Audit:        audit,
+		Team:         implteam.NewModule(implteam.NewStore(sqlstore), alertmanager, user, role, audit),
+		Provisioning: implprovisioning.NewModule(implprovisioning.NewStore(sqlstore), dashboard, savedView, alertmanager, audit),
+		Share:        implshare.NewModule(implshare.NewStore(sqlstore), jwt, dashboard, savedView, user, audit),
+		Report:       implreport.NewModule(implreport.NewStore(sqlstore), dashboard, querier, emailing, orgGetter, user, providerSettings, audit),
+		Search:       implsearch.NewModule(dashboard, savedView, ruleStore, preference, role),
+		Annotation:   implannotation.NewModule(implannotation.NewStore(sqlstore), telemetryStore, ruleStore, dashboard, audit),
 	}
 }
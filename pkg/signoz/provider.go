@@ -111,6 +111,27 @@ func NewSQLMigrationProviderFactories(sqlstore sqlstore.SQLStore) factory.NamedM
 		sqlmigration.NewDropFeatureSetFactory(),
 		sqlmigration.NewDropDeprecatedTablesFactory(),
 		sqlmigration.NewUpdateAgentsFactory(sqlstore),
+		sqlmigration.NewAddAgentRolloutsFactory(sqlstore),
+		sqlmigration.NewAddDerivedMetricsFactory(sqlstore),
+		sqlmigration.NewAddSpanMetricsGeneratorsFactory(sqlstore),
+		sqlmigration.NewAddCustomRolesFactory(sqlstore),
+		sqlmigration.NewAddSCIMFactory(sqlstore),
+		sqlmigration.NewAddFactorTOTPFactory(sqlstore),
+		sqlmigration.NewAddAuditFactory(sqlstore),
+		sqlmigration.NewUpdateAPIKeyRestrictionsFactory(sqlstore),
+		sqlmigration.NewAddSessionFactory(sqlstore),
+		sqlmigration.NewAddOrgMembershipFactory(sqlstore),
+		sqlmigration.NewAddTeamFactory(sqlstore),
+		sqlmigration.NewAddDashboardRevisionFactory(sqlstore),
+		sqlmigration.NewAddManagedResourceFactory(sqlstore),
+		sqlmigration.NewAddShareLinkFactory(sqlstore),
+		sqlmigration.NewAddReportFactory(sqlstore),
+		sqlmigration.NewAddDashboardAccessFactory(sqlstore),
+		sqlmigration.NewAddDashboardFolderParentFactory(sqlstore),
+		sqlmigration.NewUpdateSavedViewsFactory(sqlstore),
+		sqlmigration.NewAddAnnotationFactory(sqlstore),
+		sqlmigration.NewUpdateAgentElementsFactory(sqlstore),
+		sqlmigration.NewAddAPIKeyOrgFactory(sqlstore),
 	)
 }
 
@@ -247,7 +247,7 @@ func New(
 	}
 
 	// Initialize all modules
-	modules := NewModules(sqlstore, jwt, emailing, providerSettings, orgGetter, alertmanager, analytics)
+	modules := NewModules(sqlstore, jwt, emailing, providerSettings, orgGetter, alertmanager, analytics, querier, telemetrystore)
 
 	// Initialize all handlers for the modules
 	handlers := NewHandlers(modules)
@@ -281,6 +281,7 @@ func New(
 		factory.NewNamedService(factory.MustNewName("alertmanager"), alertmanager),
 		factory.NewNamedService(factory.MustNewName("licensing"), licensing),
 		factory.NewNamedService(factory.MustNewName("statsreporter"), statsReporter),
+		factory.NewNamedService(factory.MustNewName("report"), modules.Report),
 	)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,80 @@
+package client
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+)
+
+// The length of the lines of base64 encoded parts per section 6.8 of RFC 2045
+// https://www.ietf.org/rfc/rfc2045.txt
+const base64LineLength = 76
+
+// Attachment is a file attached to a message. Inline attachments are referenced from the HTML body with cid:<name>.
+type Attachment struct {
+	Name        string
+	ContentType string
+	Data        []byte
+	Inline      bool
+}
+
+// wrapAttachments wraps the body of the given content type in a multipart body of the given media type, followed by
+// the attachments. The body is returned as is when there are no attachments.
+func wrapAttachments(mediaType string, contentType string, body []byte, attachments []Attachment) (string, []byte, error) {
+	if len(attachments) == 0 {
+		return contentType, body, nil
+	}
+
+	buffer := &bytes.Buffer{}
+	writer := multipart.NewWriter(buffer)
+
+	w, err := writer.CreatePart(textproto.MIMEHeader{"Content-Type": {contentType}})
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create part for body: %w", err)
+	}
+
+	if _, err := w.Write(body); err != nil {
+		return "", nil, fmt.Errorf("failed to write body part: %w", err)
+	}
+
+	for _, attachment := range attachments {
+		attachmentContentType := mime.FormatMediaType(attachment.ContentType, map[string]string{"name": attachment.Name})
+		if attachmentContentType == "" {
+			attachmentContentType = mime.FormatMediaType("application/octet-stream", map[string]string{"name": attachment.Name})
+		}
+
+		disposition := "attachment"
+		header := textproto.MIMEHeader{
+			"Content-Type":              {attachmentContentType},
+			"Content-Transfer-Encoding": {"base64"},
+		}
+		if attachment.Inline {
+			disposition = "inline"
+			header.Set("Content-ID", "<"+attachment.Name+">")
+		}
+		header.Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": attachment.Name}))
+
+		w, err := writer.CreatePart(header)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to create part for attachment %q: %w", attachment.Name, err)
+		}
+
+		encoded := base64.StdEncoding.EncodeToString(attachment.Data)
+		for len(encoded) > 0 {
+			line := encoded[:min(base64LineLength, len(encoded))]
+			encoded = encoded[len(line):]
+			if _, err := fmt.Fprintf(w, "%s\r\n", line); err != nil {
+				return "", nil, fmt.Errorf("failed to write attachment %q: %w", attachment.Name, err)
+			}
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return "", nil, fmt.Errorf("failed to close multipartWriter: %w", err)
+	}
+
+	return mediaType + "; boundary=" + writer.Boundary(), buffer.Bytes(), nil
+}
@@ -0,0 +1,58 @@
+package client
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrapAttachments(t *testing.T) {
+	contentType, body, err := wrapAttachments("multipart/mixed", "text/html", []byte("<p>hello</p>"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "text/html", contentType)
+	assert.Equal(t, "<p>hello</p>", string(body))
+
+	data := bytes.Repeat([]byte{0, 1, 2, 3}, 100)
+	contentType, body, err = wrapAttachments("multipart/related", "text/html", []byte("<img src=\"cid:chart.png\">"), []Attachment{
+		{Name: "chart.png", ContentType: "image/png", Data: data, Inline: true},
+		{Name: "data.csv", ContentType: "text/csv", Data: []byte("a,b\n1,2\n")},
+	})
+	require.NoError(t, err)
+
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/related", mediaType)
+
+	reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+
+	part, err := reader.NextPart()
+	require.NoError(t, err)
+	assert.Equal(t, "text/html", part.Header.Get("Content-Type"))
+
+	part, err = reader.NextPart()
+	require.NoError(t, err)
+	assert.Equal(t, "<chart.png>", part.Header.Get("Content-ID"))
+	assert.Equal(t, "inline; filename=chart.png", part.Header.Get("Content-Disposition"))
+	encoded, err := io.ReadAll(part)
+	require.NoError(t, err)
+	for _, line := range bytes.Split(bytes.TrimSpace(encoded), []byte("\r\n")) {
+		assert.LessOrEqual(t, len(line), base64LineLength)
+	}
+	decoded, err := io.ReadAll(base64.NewDecoder(base64.StdEncoding, bytes.NewReader(encoded)))
+	require.NoError(t, err)
+	assert.Equal(t, data, decoded)
+
+	part, err = reader.NextPart()
+	require.NoError(t, err)
+	assert.Empty(t, part.Header.Get("Content-ID"))
+	assert.Equal(t, "attachment; filename=data.csv", part.Header.Get("Content-Disposition"))
+
+	_, err = reader.NextPart()
+	assert.ErrorIs(t, err, io.EOF)
+}
@@ -84,7 +84,7 @@ func New(address string, logger *slog.Logger, opts ...Option) (*Client, error) {
 	}, nil
 }
 
-func (c *Client) Do(ctx context.Context, tos []*mail.Address, subject string, contentType ContentType, body []byte) error {
+func (c *Client) Do(ctx context.Context, tos []*mail.Address, subject string, contentType ContentType, body []byte, attachments ...Attachment) error {
 	var (
 		smtpClient *smtp.Client
 		conn       net.Conn
@@ -194,13 +194,6 @@ func (c *Client) Do(ctx context.Context, tos []*mail.Address, subject string, co
 	fmt.Fprintf(buffer, "To: %s\r\n", strings.Join(tosAsStrings, ","))
 	fmt.Fprintf(buffer, "Subject: %s\r\n", subject)
 	fmt.Fprintf(buffer, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
-	fmt.Fprintf(buffer, "Content-Type: multipart/alternative;  boundary=%s\r\n", multipartWriter.Boundary())
-	fmt.Fprintf(buffer, "MIME-Version: 1.0\r\n\r\n")
-
-	_, err = message.Write(buffer.Bytes())
-	if err != nil {
-		return fmt.Errorf("failed to write headers: %w", err)
-	}
 
 	// Text template
 	if contentType == ContentTypeText {
@@ -251,7 +244,37 @@ func (c *Client) Do(ctx context.Context, tos []*mail.Address, subject string, co
 		return fmt.Errorf("failed to close multipartWriter: %w", err)
 	}
 
-	_, err = message.Write(multipartBuffer.Bytes())
+	// The alternatives are wrapped with the inline attachments they reference, which are in turn wrapped with the
+	// other attachments.
+	var inlines, others []Attachment
+	for _, attachment := range attachments {
+		if attachment.Inline {
+			inlines = append(inlines, attachment)
+		} else {
+			others = append(others, attachment)
+		}
+	}
+
+	bodyContentType := fmt.Sprintf("multipart/alternative;  boundary=%s", multipartWriter.Boundary())
+	bodyContentType, content, err := wrapAttachments("multipart/related", bodyContentType, multipartBuffer.Bytes(), inlines)
+	if err != nil {
+		return err
+	}
+
+	bodyContentType, content, err = wrapAttachments("multipart/mixed", bodyContentType, content, others)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(buffer, "Content-Type: %s\r\n", bodyContentType)
+	fmt.Fprintf(buffer, "MIME-Version: 1.0\r\n\r\n")
+
+	_, err = message.Write(buffer.Bytes())
+	if err != nil {
+		return fmt.Errorf("failed to write headers: %w", err)
+	}
+
+	_, err = message.Write(content)
 	if err != nil {
 		return fmt.Errorf("failed to write body buffer: %w", err)
 	}
@@ -0,0 +1,98 @@
+package sqlmigration
+
+import (
+	"context"
+
+	"github.com/SigNoz/signoz/pkg/factory"
+	"github.com/SigNoz/signoz/pkg/sqlstore"
+	"github.com/SigNoz/signoz/pkg/types"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/migrate"
+)
+
+type addAgentRollouts struct {
+	store sqlstore.SQLStore
+}
+
+type agentGroup42 struct {
+	bun.BaseModel `bun:"table:agent_group"`
+
+	types.Identifiable
+	types.TimeAuditable
+	types.UserAuditable
+	OrgID    string `bun:"org_id,type:text,notnull,unique:org_id_name"`
+	Name     string `bun:"name,type:text,notnull,unique:org_id_name"`
+	Selector string `bun:"selector,type:text,notnull"`
+}
+
+type agentConfigRollout42 struct {
+	bun.BaseModel `bun:"table:agent_config_rollout"`
+
+	types.Identifiable
+	types.TimeAuditable
+	OrgID              string  `bun:"org_id,type:text,notnull,unique:rollout_element_version_org_idx"`
+	ElementType        string  `bun:"element_type,type:text,notnull,unique:rollout_element_version_org_idx"`
+	Version            int     `bun:"version,notnull,unique:rollout_element_version_org_idx"`
+	PreviousVersion    int     `bun:"previous_version,notnull"`
+	GroupID            string  `bun:"group_id,type:text"`
+	Stage              string  `bun:"stage,type:text,notnull"`
+	CanaryPercent      int     `bun:"canary_percent,notnull"`
+	MinCanarySuccesses int     `bun:"min_canary_successes,notnull"`
+	FailureThreshold   float64 `bun:"failure_threshold,notnull"`
+	Succeeded          int     `bun:"succeeded,notnull"`
+	Failed             int     `bun:"failed,notnull"`
+	Message            string  `bun:"message,type:text"`
+}
+
+func NewAddAgentRolloutsFactory(store sqlstore.SQLStore) factory.ProviderFactory[SQLMigration, Config] {
+	return factory.NewProviderFactory(factory.MustNewName("add_agent_rollouts"), func(ctx context.Context, ps factory.ProviderSettings, c Config) (SQLMigration, error) {
+		return &addAgentRollouts{store: store}, nil
+	})
+}
+
+func (migration *addAgentRollouts) Register(migrations *migrate.Migrations) error {
+	if err := migrations.Register(migration.Up, migration.Down); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (migration *addAgentRollouts) Up(ctx context.Context, db *bun.DB) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	_, err = tx.NewCreateTable().
+		Model(new(agentGroup42)).
+		IfNotExists().
+		ForeignKey(`("org_id") REFERENCES "organizations" ("id") ON DELETE CASCADE`).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.NewCreateTable().
+		Model(new(agentConfigRollout42)).
+		IfNotExists().
+		ForeignKey(`("org_id") REFERENCES "organizations" ("id") ON DELETE CASCADE`).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (migration *addAgentRollouts) Down(ctx context.Context, db *bun.DB) error {
+	return nil
+}
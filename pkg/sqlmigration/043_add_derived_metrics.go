@@ -0,0 +1,60 @@
+package sqlmigration
+
+import (
+	"context"
+
+	"github.com/SigNoz/signoz/pkg/factory"
+	"github.com/SigNoz/signoz/pkg/sqlstore"
+	"github.com/SigNoz/signoz/pkg/types"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/migrate"
+)
+
+type addDerivedMetrics struct {
+	store sqlstore.SQLStore
+}
+
+type derivedMetric43 struct {
+	bun.BaseModel `bun:"table:derived_metric"`
+
+	types.Identifiable
+	types.TimeAuditable
+	types.UserAuditable
+	OrgID       string `bun:"org_id,type:text,notnull,unique:org_id_name"`
+	Name        string `bun:"name,type:text,notnull,unique:org_id_name"`
+	Description string `bun:"description,type:text"`
+	Unit        string `bun:"unit,type:text"`
+	Query       string `bun:"query,type:text,notnull"`
+	Enabled     bool   `bun:"enabled,notnull"`
+}
+
+func NewAddDerivedMetricsFactory(store sqlstore.SQLStore) factory.ProviderFactory[SQLMigration, Config] {
+	return factory.NewProviderFactory(factory.MustNewName("add_derived_metrics"), func(ctx context.Context, ps factory.ProviderSettings, c Config) (SQLMigration, error) {
+		return &addDerivedMetrics{store: store}, nil
+	})
+}
+
+func (migration *addDerivedMetrics) Register(migrations *migrate.Migrations) error {
+	if err := migrations.Register(migration.Up, migration.Down); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (migration *addDerivedMetrics) Up(ctx context.Context, db *bun.DB) error {
+	_, err := db.NewCreateTable().
+		Model(new(derivedMetric43)).
+		IfNotExists().
+		ForeignKey(`("org_id") REFERENCES "organizations" ("id") ON DELETE CASCADE`).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (migration *addDerivedMetrics) Down(ctx context.Context, db *bun.DB) error {
+	return nil
+}
@@ -0,0 +1,59 @@
+package sqlmigration
+
+import (
+	"context"
+
+	"github.com/SigNoz/signoz/pkg/factory"
+	"github.com/SigNoz/signoz/pkg/sqlstore"
+	"github.com/SigNoz/signoz/pkg/types"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/migrate"
+)
+
+type addSpanMetricsGenerators struct {
+	store sqlstore.SQLStore
+}
+
+type spanMetricsGenerator44 struct {
+	bun.BaseModel `bun:"table:span_metrics_generator"`
+
+	types.Identifiable
+	types.TimeAuditable
+	types.UserAuditable
+	OrgID       string `bun:"org_id,type:text,notnull,unique:org_id_name"`
+	Name        string `bun:"name,type:text,notnull,unique:org_id_name"`
+	Description string `bun:"description,type:text"`
+	Config      string `bun:"config,type:text,notnull"`
+	Enabled     bool   `bun:"enabled,notnull"`
+}
+
+func NewAddSpanMetricsGeneratorsFactory(store sqlstore.SQLStore) factory.ProviderFactory[SQLMigration, Config] {
+	return factory.NewProviderFactory(factory.MustNewName("add_span_metrics_generators"), func(ctx context.Context, ps factory.ProviderSettings, c Config) (SQLMigration, error) {
+		return &addSpanMetricsGenerators{store: store}, nil
+	})
+}
+
+func (migration *addSpanMetricsGenerators) Register(migrations *migrate.Migrations) error {
+	if err := migrations.Register(migration.Up, migration.Down); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (migration *addSpanMetricsGenerators) Up(ctx context.Context, db *bun.DB) error {
+	_, err := db.NewCreateTable().
+		Model(new(spanMetricsGenerator44)).
+		IfNotExists().
+		ForeignKey(`("org_id") REFERENCES "organizations" ("id") ON DELETE CASCADE`).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (migration *addSpanMetricsGenerators) Down(ctx context.Context, db *bun.DB) error {
+	return nil
+}
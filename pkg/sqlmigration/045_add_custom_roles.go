@@ -0,0 +1,92 @@
+package sqlmigration
+
+import (
+	"context"
+
+	"github.com/SigNoz/signoz/pkg/factory"
+	"github.com/SigNoz/signoz/pkg/sqlstore"
+	"github.com/SigNoz/signoz/pkg/types"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/migrate"
+)
+
+type addCustomRoles struct {
+	store sqlstore.SQLStore
+}
+
+type customRole45 struct {
+	bun.BaseModel `bun:"table:custom_role"`
+
+	types.Identifiable
+	types.TimeAuditable
+	types.UserAuditable
+	OrgID       string `bun:"org_id,type:text,notnull,unique:org_id_name"`
+	Name        string `bun:"name,type:text,notnull,unique:org_id_name"`
+	Description string `bun:"description,type:text"`
+	Permissions string `bun:"permissions,type:text,notnull"`
+}
+
+type customRoleAssignment45 struct {
+	bun.BaseModel `bun:"table:custom_role_assignment"`
+
+	types.Identifiable
+	types.TimeAuditable
+	OrgID       string `bun:"org_id,type:text,notnull"`
+	RoleID      string `bun:"role_id,type:text,notnull,unique:role_id_subject"`
+	SubjectType string `bun:"subject_type,type:text,notnull,unique:role_id_subject"`
+	SubjectID   string `bun:"subject_id,type:text,notnull,unique:role_id_subject"`
+}
+
+func NewAddCustomRolesFactory(store sqlstore.SQLStore) factory.ProviderFactory[SQLMigration, Config] {
+	return factory.NewProviderFactory(factory.MustNewName("add_custom_roles"), func(ctx context.Context, ps factory.ProviderSettings, c Config) (SQLMigration, error) {
+		return &addCustomRoles{store: store}, nil
+	})
+}
+
+func (migration *addCustomRoles) Register(migrations *migrate.Migrations) error {
+	if err := migrations.Register(migration.Up, migration.Down); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (migration *addCustomRoles) Up(ctx context.Context, db *bun.DB) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	_, err = tx.NewCreateTable().
+		Model(new(customRole45)).
+		IfNotExists().
+		ForeignKey(`("org_id") REFERENCES "organizations" ("id") ON DELETE CASCADE`).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.NewCreateTable().
+		Model(new(customRoleAssignment45)).
+		IfNotExists().
+		ForeignKey(`("org_id") REFERENCES "organizations" ("id") ON DELETE CASCADE`).
+		ForeignKey(`("role_id") REFERENCES "custom_role" ("id") ON DELETE CASCADE`).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (migration *addCustomRoles) Down(ctx context.Context, db *bun.DB) error {
+	return nil
+}
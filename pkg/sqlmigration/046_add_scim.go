@@ -0,0 +1,110 @@
+package sqlmigration
+
+import (
+	"context"
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/factory"
+	"github.com/SigNoz/signoz/pkg/sqlstore"
+	"github.com/SigNoz/signoz/pkg/types"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/migrate"
+)
+
+type addSCIM struct {
+	store sqlstore.SQLStore
+}
+
+type scimToken46 struct {
+	bun.BaseModel `bun:"table:scim_token"`
+
+	types.Identifiable
+	types.TimeAuditable
+	types.UserAuditable
+	OrgID     string    `bun:"org_id,type:text,notnull"`
+	Name      string    `bun:"name,type:text,notnull"`
+	TokenHash string    `bun:"token_hash,type:text,notnull,unique"`
+	LastUsed  time.Time `bun:"last_used,type:timestamptz,nullzero"`
+}
+
+type scimGroup46 struct {
+	bun.BaseModel `bun:"table:scim_group"`
+
+	types.Identifiable
+	types.TimeAuditable
+	OrgID       string `bun:"org_id,type:text,notnull,unique:org_id_display_name"`
+	DisplayName string `bun:"display_name,type:text,notnull,unique:org_id_display_name"`
+	ExternalID  string `bun:"external_id,type:text"`
+}
+
+type scimGroupMember46 struct {
+	bun.BaseModel `bun:"table:scim_group_member"`
+
+	OrgID   string `bun:"org_id,type:text,notnull"`
+	GroupID string `bun:"group_id,type:text,notnull,unique:group_id_user_id"`
+	UserID  string `bun:"user_id,type:text,notnull,unique:group_id_user_id"`
+}
+
+func NewAddSCIMFactory(store sqlstore.SQLStore) factory.ProviderFactory[SQLMigration, Config] {
+	return factory.NewProviderFactory(factory.MustNewName("add_scim"), func(ctx context.Context, ps factory.ProviderSettings, c Config) (SQLMigration, error) {
+		return &addSCIM{store: store}, nil
+	})
+}
+
+func (migration *addSCIM) Register(migrations *migrate.Migrations) error {
+	if err := migrations.Register(migration.Up, migration.Down); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (migration *addSCIM) Up(ctx context.Context, db *bun.DB) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	_, err = tx.NewCreateTable().
+		Model(new(scimToken46)).
+		IfNotExists().
+		ForeignKey(`("org_id") REFERENCES "organizations" ("id") ON DELETE CASCADE`).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.NewCreateTable().
+		Model(new(scimGroup46)).
+		IfNotExists().
+		ForeignKey(`("org_id") REFERENCES "organizations" ("id") ON DELETE CASCADE`).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.NewCreateTable().
+		Model(new(scimGroupMember46)).
+		IfNotExists().
+		ForeignKey(`("org_id") REFERENCES "organizations" ("id") ON DELETE CASCADE`).
+		ForeignKey(`("group_id") REFERENCES "scim_group" ("id") ON DELETE CASCADE`).
+		ForeignKey(`("user_id") REFERENCES "users" ("id") ON DELETE CASCADE`).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (migration *addSCIM) Down(ctx context.Context, db *bun.DB) error {
+	return nil
+}
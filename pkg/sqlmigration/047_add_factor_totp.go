@@ -0,0 +1,59 @@
+package sqlmigration
+
+import (
+	"context"
+
+	"github.com/SigNoz/signoz/pkg/factory"
+	"github.com/SigNoz/signoz/pkg/sqlstore"
+	"github.com/SigNoz/signoz/pkg/types"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/migrate"
+)
+
+type addFactorTOTP struct {
+	store sqlstore.SQLStore
+}
+
+type factorTOTP47 struct {
+	bun.BaseModel `bun:"table:factor_totp"`
+
+	types.Identifiable
+	types.TimeAuditable
+	Secret         string `bun:"secret,type:text,notnull"`
+	Enabled        bool   `bun:"enabled,type:boolean,notnull"`
+	LastUsedStep   int64  `bun:"last_used_step,notnull,default:0"`
+	FailedAttempts int    `bun:"failed_attempts,notnull,default:0"`
+	RecoveryCodes  string `bun:"recovery_codes,type:text,notnull"`
+	UserID         string `bun:"user_id,type:text,notnull,unique"`
+}
+
+func NewAddFactorTOTPFactory(store sqlstore.SQLStore) factory.ProviderFactory[SQLMigration, Config] {
+	return factory.NewProviderFactory(factory.MustNewName("add_factor_totp"), func(ctx context.Context, ps factory.ProviderSettings, c Config) (SQLMigration, error) {
+		return &addFactorTOTP{store: store}, nil
+	})
+}
+
+func (migration *addFactorTOTP) Register(migrations *migrate.Migrations) error {
+	if err := migrations.Register(migration.Up, migration.Down); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (migration *addFactorTOTP) Up(ctx context.Context, db *bun.DB) error {
+	_, err := db.NewCreateTable().
+		Model(new(factorTOTP47)).
+		IfNotExists().
+		ForeignKey(`("user_id") REFERENCES "users" ("id") ON DELETE CASCADE`).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (migration *addFactorTOTP) Down(ctx context.Context, db *bun.DB) error {
+	return nil
+}
@@ -0,0 +1,88 @@
+package sqlmigration
+
+import (
+	"context"
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/factory"
+	"github.com/SigNoz/signoz/pkg/sqlstore"
+	"github.com/SigNoz/signoz/pkg/types"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/migrate"
+)
+
+type addAudit struct {
+	store sqlstore.SQLStore
+}
+
+type auditEvent48 struct {
+	bun.BaseModel `bun:"table:audit_event"`
+
+	types.Identifiable
+	OrgID         string    `bun:"org_id,type:text,notnull"`
+	Timestamp     time.Time `bun:"timestamp,notnull"`
+	ActorType     string    `bun:"actor_type,type:text,notnull"`
+	ActorID       string    `bun:"actor_id,type:text"`
+	ActorEmail    string    `bun:"actor_email,type:text"`
+	Action        string    `bun:"action,type:text,notnull"`
+	ResourceType  string    `bun:"resource_type,type:text,notnull"`
+	ResourceID    string    `bun:"resource_id,type:text,notnull"`
+	Before        string    `bun:"before,type:text"`
+	After         string    `bun:"after,type:text"`
+	ClientAddress string    `bun:"client_address,type:text"`
+	UserAgent     string    `bun:"user_agent,type:text"`
+}
+
+func NewAddAuditFactory(store sqlstore.SQLStore) factory.ProviderFactory[SQLMigration, Config] {
+	return factory.NewProviderFactory(factory.MustNewName("add_audit"), func(ctx context.Context, ps factory.ProviderSettings, c Config) (SQLMigration, error) {
+		return &addAudit{store: store}, nil
+	})
+}
+
+func (migration *addAudit) Register(migrations *migrate.Migrations) error {
+	if err := migrations.Register(migration.Up, migration.Down); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (migration *addAudit) Up(ctx context.Context, db *bun.DB) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	_, err = tx.NewCreateTable().
+		Model(new(auditEvent48)).
+		IfNotExists().
+		ForeignKey(`("org_id") REFERENCES "organizations" ("id") ON DELETE CASCADE`).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.NewCreateIndex().
+		Table("audit_event").
+		Column("org_id", "timestamp").
+		Index("idx_audit_event_org_id_timestamp").
+		IfNotExists().
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (migration *addAudit) Down(ctx context.Context, db *bun.DB) error {
+	return nil
+}
@@ -0,0 +1,66 @@
+package sqlmigration
+
+import (
+	"context"
+
+	"github.com/SigNoz/signoz/pkg/factory"
+	"github.com/SigNoz/signoz/pkg/sqlstore"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/migrate"
+)
+
+type updateAPIKeyRestrictions struct {
+	store sqlstore.SQLStore
+}
+
+func NewUpdateAPIKeyRestrictionsFactory(store sqlstore.SQLStore) factory.ProviderFactory[SQLMigration, Config] {
+	return factory.NewProviderFactory(factory.MustNewName("update_api_key_restrictions"), func(ctx context.Context, ps factory.ProviderSettings, c Config) (SQLMigration, error) {
+		return &updateAPIKeyRestrictions{store: store}, nil
+	})
+}
+
+func (migration *updateAPIKeyRestrictions) Register(migrations *migrate.Migrations) error {
+	if err := migrations.Register(migration.Up, migration.Down); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (migration *updateAPIKeyRestrictions) Up(ctx context.Context, db *bun.DB) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	columns := []struct {
+		name string
+		expr string
+	}{
+		{name: "usage_count", expr: "BIGINT NOT NULL DEFAULT 0"},
+		{name: "last_used_ip", expr: "TEXT"},
+		{name: "scopes", expr: "TEXT"},
+		{name: "rule_labels", expr: "TEXT"},
+		{name: "allowed_ips", expr: "TEXT"},
+	}
+
+	for _, column := range columns {
+		if err := migration.store.Dialect().AddColumn(ctx, tx, "factor_api_key", column.name, column.expr); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (migration *updateAPIKeyRestrictions) Down(ctx context.Context, db *bun.DB) error {
+	return nil
+}
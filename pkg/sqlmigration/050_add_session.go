@@ -0,0 +1,85 @@
+package sqlmigration
+
+import (
+	"context"
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/factory"
+	"github.com/SigNoz/signoz/pkg/sqlstore"
+	"github.com/SigNoz/signoz/pkg/types"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/migrate"
+)
+
+type addSession struct {
+	store sqlstore.SQLStore
+}
+
+type session50 struct {
+	bun.BaseModel `bun:"table:session"`
+
+	types.Identifiable
+	types.TimeAuditable
+	OrgID          string    `bun:"org_id,type:text,notnull"`
+	UserID         string    `bun:"user_id,type:text,notnull"`
+	RefreshTokenID string    `bun:"refresh_token_id,type:text,notnull"`
+	ClientAddress  string    `bun:"client_address,type:text"`
+	UserAgent      string    `bun:"user_agent,type:text"`
+	ExpiresAt      time.Time `bun:"expires_at,notnull"`
+	RevokedAt      time.Time `bun:"revoked_at,nullzero"`
+	RevokedReason  string    `bun:"revoked_reason,type:text"`
+}
+
+func NewAddSessionFactory(store sqlstore.SQLStore) factory.ProviderFactory[SQLMigration, Config] {
+	return factory.NewProviderFactory(factory.MustNewName("add_session"), func(ctx context.Context, ps factory.ProviderSettings, c Config) (SQLMigration, error) {
+		return &addSession{store: store}, nil
+	})
+}
+
+func (migration *addSession) Register(migrations *migrate.Migrations) error {
+	if err := migrations.Register(migration.Up, migration.Down); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (migration *addSession) Up(ctx context.Context, db *bun.DB) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	_, err = tx.NewCreateTable().
+		Model(new(session50)).
+		IfNotExists().
+		ForeignKey(`("user_id") REFERENCES "users" ("id") ON DELETE CASCADE`).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.NewCreateIndex().
+		Table("session").
+		Column("user_id").
+		Index("idx_session_user_id").
+		IfNotExists().
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (migration *addSession) Down(ctx context.Context, db *bun.DB) error {
+	return nil
+}
@@ -0,0 +1,106 @@
+package sqlmigration
+
+import (
+	"context"
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/factory"
+	"github.com/SigNoz/signoz/pkg/sqlstore"
+	"github.com/SigNoz/signoz/pkg/types"
+	"github.com/SigNoz/signoz/pkg/valuer"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/migrate"
+)
+
+type addOrgMembership struct {
+	store sqlstore.SQLStore
+}
+
+type orgMembership51 struct {
+	bun.BaseModel `bun:"table:org_membership"`
+
+	types.Identifiable
+	types.TimeAuditable
+	UserID string `bun:"user_id,type:text,notnull,unique:user_org"`
+	OrgID  string `bun:"org_id,type:text,notnull,unique:user_org"`
+	Role   string `bun:"role,type:text,notnull"`
+}
+
+type user51 struct {
+	bun.BaseModel `bun:"table:users"`
+
+	ID    string `bun:"id"`
+	OrgID string `bun:"org_id"`
+	Role  string `bun:"role"`
+}
+
+func NewAddOrgMembershipFactory(store sqlstore.SQLStore) factory.ProviderFactory[SQLMigration, Config] {
+	return factory.NewProviderFactory(factory.MustNewName("add_org_membership"), func(ctx context.Context, ps factory.ProviderSettings, c Config) (SQLMigration, error) {
+		return &addOrgMembership{store: store}, nil
+	})
+}
+
+func (migration *addOrgMembership) Register(migrations *migrate.Migrations) error {
+	if err := migrations.Register(migration.Up, migration.Down); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (migration *addOrgMembership) Up(ctx context.Context, db *bun.DB) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	_, err = tx.NewCreateTable().
+		Model(new(orgMembership51)).
+		IfNotExists().
+		ForeignKey(`("user_id") REFERENCES "users" ("id") ON DELETE CASCADE`).
+		ForeignKey(`("org_id") REFERENCES "organizations" ("id") ON DELETE CASCADE`).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	// every existing user is a member of their org
+	users := []*user51{}
+	if err := tx.NewSelect().Model(&users).Scan(ctx); err != nil {
+		return err
+	}
+
+	memberships := []*orgMembership51{}
+	for _, user := range users {
+		memberships = append(memberships, &orgMembership51{
+			Identifiable: types.Identifiable{ID: valuer.GenerateUUID()},
+			TimeAuditable: types.TimeAuditable{
+				CreatedAt: time.Now(),
+				UpdatedAt: time.Now(),
+			},
+			UserID: user.ID,
+			OrgID:  user.OrgID,
+			Role:   user.Role,
+		})
+	}
+
+	if len(memberships) > 0 {
+		if _, err := tx.NewInsert().Model(&memberships).On("CONFLICT (user_id, org_id) DO NOTHING").Exec(ctx); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (migration *addOrgMembership) Down(ctx context.Context, db *bun.DB) error {
+	return nil
+}
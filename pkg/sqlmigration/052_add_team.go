@@ -0,0 +1,129 @@
+package sqlmigration
+
+import (
+	"context"
+
+	"github.com/SigNoz/signoz/pkg/factory"
+	"github.com/SigNoz/signoz/pkg/sqlstore"
+	"github.com/SigNoz/signoz/pkg/types"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/migrate"
+)
+
+type addTeam struct {
+	store sqlstore.SQLStore
+}
+
+type team52 struct {
+	bun.BaseModel `bun:"table:team"`
+
+	types.Identifiable
+	types.TimeAuditable
+	OrgID       string `bun:"org_id,type:text,notnull,unique:org_id_name"`
+	Name        string `bun:"name,type:text,notnull,unique:org_id_name"`
+	DisplayName string `bun:"display_name,type:text,notnull"`
+}
+
+type teamMember52 struct {
+	bun.BaseModel `bun:"table:team_member"`
+
+	types.Identifiable
+	types.TimeAuditable
+	TeamID string `bun:"team_id,type:text,notnull,unique:team_id_user_id"`
+	UserID string `bun:"user_id,type:text,notnull,unique:team_id_user_id"`
+	Role   string `bun:"role,type:text,notnull"`
+}
+
+type teamOwnership52 struct {
+	bun.BaseModel `bun:"table:team_ownership"`
+
+	types.Identifiable
+	types.TimeAuditable
+	OrgID        string `bun:"org_id,type:text,notnull,unique:org_id_resource_type_resource_id"`
+	TeamID       string `bun:"team_id,type:text,notnull"`
+	ResourceType string `bun:"resource_type,type:text,notnull,unique:org_id_resource_type_resource_id"`
+	ResourceID   string `bun:"resource_id,type:text,notnull,unique:org_id_resource_type_resource_id"`
+}
+
+func NewAddTeamFactory(store sqlstore.SQLStore) factory.ProviderFactory[SQLMigration, Config] {
+	return factory.NewProviderFactory(factory.MustNewName("add_team"), func(ctx context.Context, ps factory.ProviderSettings, c Config) (SQLMigration, error) {
+		return &addTeam{store: store}, nil
+	})
+}
+
+func (migration *addTeam) Register(migrations *migrate.Migrations) error {
+	if err := migrations.Register(migration.Up, migration.Down); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (migration *addTeam) Up(ctx context.Context, db *bun.DB) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	_, err = tx.NewCreateTable().
+		Model(new(team52)).
+		IfNotExists().
+		ForeignKey(`("org_id") REFERENCES "organizations" ("id") ON DELETE CASCADE`).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.NewCreateTable().
+		Model(new(teamMember52)).
+		IfNotExists().
+		ForeignKey(`("team_id") REFERENCES "team" ("id") ON DELETE CASCADE`).
+		ForeignKey(`("user_id") REFERENCES "users" ("id") ON DELETE CASCADE`).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.NewCreateTable().
+		Model(new(teamOwnership52)).
+		IfNotExists().
+		ForeignKey(`("team_id") REFERENCES "team" ("id") ON DELETE CASCADE`).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.NewCreateIndex().
+		Table("team_member").
+		Column("user_id").
+		Index("idx_team_member_user_id").
+		IfNotExists().
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.NewCreateIndex().
+		Table("team_ownership").
+		Column("team_id", "resource_type").
+		Index("idx_team_ownership_team_id_resource_type").
+		IfNotExists().
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (migration *addTeam) Down(ctx context.Context, db *bun.DB) error {
+	return nil
+}
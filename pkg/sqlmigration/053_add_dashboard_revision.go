@@ -0,0 +1,127 @@
+package sqlmigration
+
+import (
+	"context"
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/factory"
+	"github.com/SigNoz/signoz/pkg/sqlstore"
+	"github.com/SigNoz/signoz/pkg/types"
+	"github.com/SigNoz/signoz/pkg/valuer"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/migrate"
+)
+
+type addDashboardRevision struct {
+	store sqlstore.SQLStore
+}
+
+type dashboard53 struct {
+	bun.BaseModel `bun:"table:dashboard"`
+
+	ID        string    `bun:"id"`
+	OrgID     string    `bun:"org_id"`
+	Data      string    `bun:"data"`
+	UpdatedAt time.Time `bun:"updated_at"`
+	UpdatedBy string    `bun:"updated_by"`
+}
+
+type dashboardRevision53 struct {
+	bun.BaseModel `bun:"table:dashboard_revision"`
+
+	types.Identifiable
+	OrgID        string    `bun:"org_id,type:text,notnull"`
+	DashboardID  string    `bun:"dashboard_id,type:text,notnull,unique:dashboard_id_version"`
+	Version      int       `bun:"version,notnull,unique:dashboard_id_version"`
+	Data         string    `bun:"data,type:text,notnull"`
+	Message      string    `bun:"message,type:text"`
+	RestoredFrom int       `bun:"restored_from,notnull,default:0"`
+	CreatedAt    time.Time `bun:"created_at,notnull"`
+	CreatedBy    string    `bun:"created_by,type:text"`
+}
+
+func NewAddDashboardRevisionFactory(store sqlstore.SQLStore) factory.ProviderFactory[SQLMigration, Config] {
+	return factory.NewProviderFactory(factory.MustNewName("add_dashboard_revision"), func(ctx context.Context, ps factory.ProviderSettings, c Config) (SQLMigration, error) {
+		return &addDashboardRevision{store: store}, nil
+	})
+}
+
+func (migration *addDashboardRevision) Register(migrations *migrate.Migrations) error {
+	if err := migrations.Register(migration.Up, migration.Down); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (migration *addDashboardRevision) Up(ctx context.Context, db *bun.DB) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	_, err = tx.NewCreateTable().
+		Model(new(dashboardRevision53)).
+		IfNotExists().
+		ForeignKey(`("dashboard_id") REFERENCES "dashboard" ("id") ON DELETE CASCADE`).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.NewCreateIndex().
+		Table("dashboard_revision").
+		Column("org_id", "dashboard_id").
+		Index("idx_dashboard_revision_org_id_dashboard_id").
+		IfNotExists().
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	// the current data of the existing dashboards becomes their first revision
+	dashboards := make([]*dashboard53, 0)
+	err = tx.NewSelect().
+		Model(&dashboards).
+		Scan(ctx)
+	if err != nil {
+		return err
+	}
+
+	revisions := make([]*dashboardRevision53, 0, len(dashboards))
+	for _, dashboard := range dashboards {
+		revisions = append(revisions, &dashboardRevision53{
+			Identifiable: types.Identifiable{ID: valuer.GenerateUUID()},
+			OrgID:        dashboard.OrgID,
+			DashboardID:  dashboard.ID,
+			Version:      1,
+			Data:         dashboard.Data,
+			CreatedAt:    dashboard.UpdatedAt,
+			CreatedBy:    dashboard.UpdatedBy,
+		})
+	}
+
+	if len(revisions) > 0 {
+		_, err = tx.NewInsert().
+			Model(&revisions).
+			On("CONFLICT (dashboard_id, version) DO NOTHING").
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (migration *addDashboardRevision) Down(ctx context.Context, db *bun.DB) error {
+	return nil
+}
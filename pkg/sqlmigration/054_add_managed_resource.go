@@ -0,0 +1,81 @@
+package sqlmigration
+
+import (
+	"context"
+
+	"github.com/SigNoz/signoz/pkg/factory"
+	"github.com/SigNoz/signoz/pkg/sqlstore"
+	"github.com/SigNoz/signoz/pkg/types"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/migrate"
+)
+
+type addManagedResource struct {
+	store sqlstore.SQLStore
+}
+
+type managedResource54 struct {
+	bun.BaseModel `bun:"table:managed_resource"`
+
+	types.Identifiable
+	types.TimeAuditable
+	OrgID      string `bun:"org_id,type:text,notnull,unique:org_id_kind_external_id"`
+	Kind       string `bun:"kind,type:text,notnull,unique:org_id_kind_external_id"`
+	ExternalID string `bun:"external_id,type:text,notnull,unique:org_id_kind_external_id"`
+	ResourceID string `bun:"resource_id,type:text,notnull"`
+	Checksum   string `bun:"checksum,type:text,notnull"`
+}
+
+func NewAddManagedResourceFactory(store sqlstore.SQLStore) factory.ProviderFactory[SQLMigration, Config] {
+	return factory.NewProviderFactory(factory.MustNewName("add_managed_resource"), func(ctx context.Context, ps factory.ProviderSettings, c Config) (SQLMigration, error) {
+		return &addManagedResource{store: store}, nil
+	})
+}
+
+func (migration *addManagedResource) Register(migrations *migrate.Migrations) error {
+	if err := migrations.Register(migration.Up, migration.Down); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (migration *addManagedResource) Up(ctx context.Context, db *bun.DB) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	_, err = tx.NewCreateTable().
+		Model(new(managedResource54)).
+		IfNotExists().
+		ForeignKey(`("org_id") REFERENCES "organizations" ("id") ON DELETE CASCADE`).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.NewCreateIndex().
+		Table("managed_resource").
+		Column("org_id", "kind", "resource_id").
+		Index("idx_managed_resource_org_id_kind_resource_id").
+		IfNotExists().
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (migration *addManagedResource) Down(ctx context.Context, db *bun.DB) error {
+	return nil
+}
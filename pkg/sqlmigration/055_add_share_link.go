@@ -0,0 +1,87 @@
+package sqlmigration
+
+import (
+	"context"
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/factory"
+	"github.com/SigNoz/signoz/pkg/sqlstore"
+	"github.com/SigNoz/signoz/pkg/types"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/migrate"
+)
+
+type addShareLink struct {
+	store sqlstore.SQLStore
+}
+
+type shareLink55 struct {
+	bun.BaseModel `bun:"table:share_link"`
+
+	types.Identifiable
+	types.TimeAuditable
+	OrgID        string     `bun:"org_id,type:text,notnull"`
+	ResourceType string     `bun:"resource_type,type:text,notnull"`
+	ResourceID   string     `bun:"resource_id,type:text,notnull"`
+	WidgetID     string     `bun:"widget_id,type:text"`
+	TimeRange    string     `bun:"time_range,type:text,notnull"`
+	Variables    string     `bun:"variables,type:text"`
+	PasswordHash string     `bun:"password_hash,type:text"`
+	ExpiresAt    time.Time  `bun:"expires_at,notnull"`
+	RevokedAt    *time.Time `bun:"revoked_at"`
+	CreatedBy    string     `bun:"created_by,type:text,notnull"`
+}
+
+func NewAddShareLinkFactory(store sqlstore.SQLStore) factory.ProviderFactory[SQLMigration, Config] {
+	return factory.NewProviderFactory(factory.MustNewName("add_share_link"), func(ctx context.Context, ps factory.ProviderSettings, c Config) (SQLMigration, error) {
+		return &addShareLink{store: store}, nil
+	})
+}
+
+func (migration *addShareLink) Register(migrations *migrate.Migrations) error {
+	if err := migrations.Register(migration.Up, migration.Down); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (migration *addShareLink) Up(ctx context.Context, db *bun.DB) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	_, err = tx.NewCreateTable().
+		Model(new(shareLink55)).
+		IfNotExists().
+		ForeignKey(`("org_id") REFERENCES "organizations" ("id") ON DELETE CASCADE`).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.NewCreateIndex().
+		Table("share_link").
+		Column("org_id", "resource_type", "resource_id").
+		Index("idx_share_link_org_id_resource_type_resource_id").
+		IfNotExists().
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (migration *addShareLink) Down(ctx context.Context, db *bun.DB) error {
+	return nil
+}
@@ -0,0 +1,125 @@
+package sqlmigration
+
+import (
+	"context"
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/factory"
+	"github.com/SigNoz/signoz/pkg/sqlstore"
+	"github.com/SigNoz/signoz/pkg/types"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/migrate"
+)
+
+type addReport struct {
+	store sqlstore.SQLStore
+}
+
+type report56 struct {
+	bun.BaseModel `bun:"table:report"`
+
+	types.Identifiable
+	types.TimeAuditable
+	types.UserAuditable
+	OrgID       string    `bun:"org_id,type:text,notnull"`
+	Name        string    `bun:"name,type:text,notnull"`
+	DashboardID string    `bun:"dashboard_id,type:text"`
+	WidgetIDs   string    `bun:"widget_ids,type:text"`
+	Panels      string    `bun:"panels,type:text"`
+	Variables   string    `bun:"variables,type:text"`
+	Cron        string    `bun:"cron,type:text,notnull"`
+	Timezone    string    `bun:"timezone,type:text,notnull"`
+	TimeRange   string    `bun:"time_range,type:text,notnull"`
+	Recipients  string    `bun:"recipients,type:text,notnull"`
+	AttachCSV   bool      `bun:"attach_csv,notnull,default:false"`
+	Enabled     bool      `bun:"enabled,notnull,default:true"`
+	NextRunAt   time.Time `bun:"next_run_at,notnull"`
+}
+
+type reportDelivery56 struct {
+	bun.BaseModel `bun:"table:report_delivery"`
+
+	types.Identifiable
+	OrgID      string    `bun:"org_id,type:text,notnull"`
+	ReportID   string    `bun:"report_id,type:text,notnull"`
+	Trigger    string    `bun:"trigger,type:text,notnull"`
+	Status     string    `bun:"status,type:text,notnull"`
+	Recipients string    `bun:"recipients,type:text,notnull"`
+	Error      string    `bun:"error,type:text"`
+	StartedAt  time.Time `bun:"started_at,notnull"`
+	FinishedAt time.Time `bun:"finished_at,notnull"`
+}
+
+func NewAddReportFactory(store sqlstore.SQLStore) factory.ProviderFactory[SQLMigration, Config] {
+	return factory.NewProviderFactory(factory.MustNewName("add_report"), func(ctx context.Context, ps factory.ProviderSettings, c Config) (SQLMigration, error) {
+		return &addReport{store: store}, nil
+	})
+}
+
+func (migration *addReport) Register(migrations *migrate.Migrations) error {
+	if err := migrations.Register(migration.Up, migration.Down); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (migration *addReport) Up(ctx context.Context, db *bun.DB) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	_, err = tx.NewCreateTable().
+		Model(new(report56)).
+		IfNotExists().
+		ForeignKey(`("org_id") REFERENCES "organizations" ("id") ON DELETE CASCADE`).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.NewCreateIndex().
+		Table("report").
+		Column("enabled", "next_run_at").
+		Index("idx_report_enabled_next_run_at").
+		IfNotExists().
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.NewCreateTable().
+		Model(new(reportDelivery56)).
+		IfNotExists().
+		ForeignKey(`("org_id") REFERENCES "organizations" ("id") ON DELETE CASCADE`).
+		ForeignKey(`("report_id") REFERENCES "report" ("id") ON DELETE CASCADE`).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.NewCreateIndex().
+		Table("report_delivery").
+		Column("report_id", "started_at").
+		Index("idx_report_delivery_report_id_started_at").
+		IfNotExists().
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (migration *addReport) Down(ctx context.Context, db *bun.DB) error {
+	return nil
+}
@@ -0,0 +1,105 @@
+package sqlmigration
+
+import (
+	"context"
+
+	"github.com/SigNoz/signoz/pkg/factory"
+	"github.com/SigNoz/signoz/pkg/sqlstore"
+	"github.com/SigNoz/signoz/pkg/types"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/migrate"
+)
+
+type addDashboardAccess struct {
+	store sqlstore.SQLStore
+}
+
+type dashboardFolder57 struct {
+	bun.BaseModel `bun:"table:dashboard_folder"`
+
+	types.Identifiable
+	types.TimeAuditable
+	types.UserAuditable
+	OrgID string `bun:"org_id,type:text,notnull,unique:org_id_name"`
+	Name  string `bun:"name,type:text,notnull,unique:org_id_name"`
+}
+
+type dashboardGrant57 struct {
+	bun.BaseModel `bun:"table:dashboard_grant"`
+
+	types.Identifiable
+	types.TimeAuditable
+	OrgID        string `bun:"org_id,type:text,notnull"`
+	ResourceType string `bun:"resource_type,type:text,notnull,unique:resource_type_resource_id_subject_type_subject_id"`
+	ResourceID   string `bun:"resource_id,type:text,notnull,unique:resource_type_resource_id_subject_type_subject_id"`
+	SubjectType  string `bun:"subject_type,type:text,notnull,unique:resource_type_resource_id_subject_type_subject_id"`
+	SubjectID    string `bun:"subject_id,type:text,notnull,unique:resource_type_resource_id_subject_type_subject_id"`
+	Permission   string `bun:"permission,type:text,notnull"`
+}
+
+func NewAddDashboardAccessFactory(store sqlstore.SQLStore) factory.ProviderFactory[SQLMigration, Config] {
+	return factory.NewProviderFactory(factory.MustNewName("add_dashboard_access"), func(ctx context.Context, ps factory.ProviderSettings, c Config) (SQLMigration, error) {
+		return &addDashboardAccess{store: store}, nil
+	})
+}
+
+func (migration *addDashboardAccess) Register(migrations *migrate.Migrations) error {
+	if err := migrations.Register(migration.Up, migration.Down); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (migration *addDashboardAccess) Up(ctx context.Context, db *bun.DB) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	_, err = tx.NewCreateTable().
+		Model(new(dashboardFolder57)).
+		IfNotExists().
+		ForeignKey(`("org_id") REFERENCES "organizations" ("id") ON DELETE CASCADE`).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.NewCreateTable().
+		Model(new(dashboardGrant57)).
+		IfNotExists().
+		ForeignKey(`("org_id") REFERENCES "organizations" ("id") ON DELETE CASCADE`).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.NewCreateIndex().
+		Table("dashboard_grant").
+		Column("org_id").
+		Index("idx_dashboard_grant_org_id").
+		IfNotExists().
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := migration.store.Dialect().AddColumn(ctx, tx, "dashboard", "folder_id", "TEXT"); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (migration *addDashboardAccess) Down(ctx context.Context, db *bun.DB) error {
+	return nil
+}
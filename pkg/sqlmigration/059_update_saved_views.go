@@ -0,0 +1,94 @@
+package sqlmigration
+
+import (
+	"context"
+
+	"github.com/SigNoz/signoz/pkg/factory"
+	"github.com/SigNoz/signoz/pkg/sqlstore"
+	"github.com/SigNoz/signoz/pkg/types/savedviewtypes"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/migrate"
+)
+
+type updateSavedViews struct {
+	store sqlstore.SQLStore
+}
+
+type savedView59 struct {
+	bun.BaseModel `bun:"table:saved_views"`
+
+	ID   string `bun:"id,pk,type:text"`
+	Data string `bun:"data,type:text,notnull"`
+}
+
+func NewUpdateSavedViewsFactory(store sqlstore.SQLStore) factory.ProviderFactory[SQLMigration, Config] {
+	return factory.NewProviderFactory(factory.MustNewName("update_saved_views"), func(ctx context.Context, ps factory.ProviderSettings, c Config) (SQLMigration, error) {
+		return &updateSavedViews{store: store}, nil
+	})
+}
+
+func (migration *updateSavedViews) Register(migrations *migrate.Migrations) error {
+	if err := migrations.Register(migration.Up, migration.Down); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (migration *updateSavedViews) Up(ctx context.Context, db *bun.DB) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	columns := []struct {
+		name string
+		expr string
+	}{
+		{name: "visibility", expr: "TEXT NOT NULL DEFAULT 'org'"},
+		{name: "team_id", expr: "TEXT"},
+		{name: "usage_count", expr: "BIGINT NOT NULL DEFAULT 0"},
+		{name: "last_used_at", expr: "TIMESTAMP"},
+	}
+
+	for _, column := range columns {
+		if err := migration.store.Dialect().AddColumn(ctx, tx, "saved_views", column.name, column.expr); err != nil {
+			return err
+		}
+	}
+
+	views := make([]*savedView59, 0)
+	if err := tx.NewSelect().Model(&views).Scan(ctx); err != nil {
+		return err
+	}
+
+	for _, view := range views {
+		data, err := savedviewtypes.UpgradeData(view.Data)
+		if err != nil {
+			// the views whose queries can't be upgraded are left as is, they are upgraded again when read
+			continue
+		}
+
+		if data == view.Data {
+			continue
+		}
+
+		if _, err := tx.NewUpdate().Model(view).Set("data = ?", data).WherePK().Exec(ctx); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (migration *updateSavedViews) Down(ctx context.Context, db *bun.DB) error {
+	return nil
+}
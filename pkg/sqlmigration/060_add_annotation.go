@@ -0,0 +1,87 @@
+package sqlmigration
+
+import (
+	"context"
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/factory"
+	"github.com/SigNoz/signoz/pkg/sqlstore"
+	"github.com/SigNoz/signoz/pkg/types"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/migrate"
+)
+
+type addAnnotation struct {
+	store sqlstore.SQLStore
+}
+
+type annotation60 struct {
+	bun.BaseModel `bun:"table:annotation"`
+
+	types.Identifiable
+	types.TimeAuditable
+	OrgID       string     `bun:"org_id,type:text,notnull"`
+	DashboardID string     `bun:"dashboard_id,type:text"`
+	StartTime   time.Time  `bun:"start_time,notnull"`
+	EndTime     *time.Time `bun:"end_time"`
+	Text        string     `bun:"text,type:text,notnull"`
+	Tags        string     `bun:"tags,type:text"`
+	Service     string     `bun:"service,type:text"`
+	Environment string     `bun:"environment,type:text"`
+	Source      string     `bun:"source,type:text,notnull"`
+	CreatedBy   string     `bun:"created_by,type:text"`
+}
+
+func NewAddAnnotationFactory(store sqlstore.SQLStore) factory.ProviderFactory[SQLMigration, Config] {
+	return factory.NewProviderFactory(factory.MustNewName("add_annotation"), func(ctx context.Context, ps factory.ProviderSettings, c Config) (SQLMigration, error) {
+		return &addAnnotation{store: store}, nil
+	})
+}
+
+func (migration *addAnnotation) Register(migrations *migrate.Migrations) error {
+	if err := migrations.Register(migration.Up, migration.Down); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (migration *addAnnotation) Up(ctx context.Context, db *bun.DB) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	_, err = tx.NewCreateTable().
+		Model(new(annotation60)).
+		IfNotExists().
+		ForeignKey(`("org_id") REFERENCES "organizations" ("id") ON DELETE CASCADE`).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.NewCreateIndex().
+		Table("annotation").
+		Column("org_id", "start_time").
+		Index("idx_annotation_org_id_start_time").
+		IfNotExists().
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (migration *addAnnotation) Down(ctx context.Context, db *bun.DB) error {
+	return nil
+}
@@ -0,0 +1,178 @@
+package sqlmigration
+
+import (
+	"context"
+
+	"github.com/SigNoz/signoz/pkg/factory"
+	"github.com/SigNoz/signoz/pkg/sqlstore"
+	"github.com/SigNoz/signoz/pkg/types"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/migrate"
+)
+
+type updateAgentElements struct {
+	store sqlstore.SQLStore
+}
+
+type existingDerivedMetric61 struct {
+	bun.BaseModel `bun:"table:derived_metric"`
+
+	types.Identifiable
+	types.TimeAuditable
+	types.UserAuditable
+	OrgID       string `bun:"org_id,type:text,notnull,unique:org_id_name"`
+	Name        string `bun:"name,type:text,notnull,unique:org_id_name"`
+	Description string `bun:"description,type:text"`
+	Query       string `bun:"query,type:text,notnull"`
+	Enabled     bool   `bun:"enabled,notnull"`
+}
+
+// revisions of the same metric share the definition id, the names are unique among
+// the current revisions only
+type newDerivedMetric61 struct {
+	bun.BaseModel `bun:"table:derived_metric_revision"`
+
+	types.Identifiable
+	types.TimeAuditable
+	types.UserAuditable
+	DefinitionID string `bun:"definition_id,type:text,notnull"`
+	OrgID        string `bun:"org_id,type:text,notnull"`
+	Name         string `bun:"name,type:text,notnull"`
+	Description  string `bun:"description,type:text"`
+	Query        string `bun:"query,type:text,notnull"`
+	Enabled      bool   `bun:"enabled,notnull"`
+}
+
+type existingSpanMetricsGenerator61 struct {
+	bun.BaseModel `bun:"table:span_metrics_generator"`
+
+	types.Identifiable
+	types.TimeAuditable
+	types.UserAuditable
+	OrgID       string `bun:"org_id,type:text,notnull,unique:org_id_name"`
+	Name        string `bun:"name,type:text,notnull,unique:org_id_name"`
+	Description string `bun:"description,type:text"`
+	Config      string `bun:"config,type:text,notnull"`
+	Enabled     bool   `bun:"enabled,notnull"`
+}
+
+type newSpanMetricsGenerator61 struct {
+	bun.BaseModel `bun:"table:span_metrics_generator_revision"`
+
+	types.Identifiable
+	types.TimeAuditable
+	types.UserAuditable
+	DefinitionID string `bun:"definition_id,type:text,notnull"`
+	OrgID        string `bun:"org_id,type:text,notnull"`
+	Name         string `bun:"name,type:text,notnull"`
+	Description  string `bun:"description,type:text"`
+	Config       string `bun:"config,type:text,notnull"`
+	Enabled      bool   `bun:"enabled,notnull"`
+}
+
+func NewUpdateAgentElementsFactory(store sqlstore.SQLStore) factory.ProviderFactory[SQLMigration, Config] {
+	return factory.NewProviderFactory(factory.MustNewName("update_agent_elements"), func(ctx context.Context, ps factory.ProviderSettings, c Config) (SQLMigration, error) {
+		return &updateAgentElements{store: store}, nil
+	})
+}
+
+func (migration *updateAgentElements) Register(migrations *migrate.Migrations) error {
+	if err := migrations.Register(migration.Up, migration.Down); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (migration *updateAgentElements) Up(ctx context.Context, db *bun.DB) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	// the existing rows become the first revisions, their ids are kept as they are the
+	// elements of the existing agent config versions
+	err = migration.
+		store.
+		Dialect().
+		RenameTableAndModifyModel(ctx, tx, new(existingDerivedMetric61), new(newDerivedMetric61), []string{OrgReference}, func(ctx context.Context) error {
+			existingMetrics := make([]*existingDerivedMetric61, 0)
+			if err := tx.NewSelect().Model(&existingMetrics).Scan(ctx); err != nil {
+				return err
+			}
+
+			if len(existingMetrics) == 0 {
+				return nil
+			}
+
+			newMetrics := make([]*newDerivedMetric61, len(existingMetrics))
+			for i, metric := range existingMetrics {
+				newMetrics[i] = &newDerivedMetric61{
+					Identifiable:  metric.Identifiable,
+					TimeAuditable: metric.TimeAuditable,
+					UserAuditable: metric.UserAuditable,
+					DefinitionID:  metric.ID.StringValue(),
+					OrgID:         metric.OrgID,
+					Name:          metric.Name,
+					Description:   metric.Description,
+					Query:         metric.Query,
+					Enabled:       metric.Enabled,
+				}
+			}
+
+			_, err := tx.NewInsert().Model(&newMetrics).Exec(ctx)
+			return err
+		})
+	if err != nil {
+		return err
+	}
+
+	err = migration.
+		store.
+		Dialect().
+		RenameTableAndModifyModel(ctx, tx, new(existingSpanMetricsGenerator61), new(newSpanMetricsGenerator61), []string{OrgReference}, func(ctx context.Context) error {
+			existingGenerators := make([]*existingSpanMetricsGenerator61, 0)
+			if err := tx.NewSelect().Model(&existingGenerators).Scan(ctx); err != nil {
+				return err
+			}
+
+			if len(existingGenerators) == 0 {
+				return nil
+			}
+
+			newGenerators := make([]*newSpanMetricsGenerator61, len(existingGenerators))
+			for i, generator := range existingGenerators {
+				newGenerators[i] = &newSpanMetricsGenerator61{
+					Identifiable:  generator.Identifiable,
+					TimeAuditable: generator.TimeAuditable,
+					UserAuditable: generator.UserAuditable,
+					DefinitionID:  generator.ID.StringValue(),
+					OrgID:         generator.OrgID,
+					Name:          generator.Name,
+					Description:   generator.Description,
+					Config:        generator.Config,
+					Enabled:       generator.Enabled,
+				}
+			}
+
+			_, err := tx.NewInsert().Model(&newGenerators).Exec(ctx)
+			return err
+		})
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (migration *updateAgentElements) Down(ctx context.Context, db *bun.DB) error {
+	return nil
+}
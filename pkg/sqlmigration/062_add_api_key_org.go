@@ -0,0 +1,64 @@
+package sqlmigration
+
+import (
+	"context"
+
+	"github.com/SigNoz/signoz/pkg/factory"
+	"github.com/SigNoz/signoz/pkg/sqlstore"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/migrate"
+)
+
+type addAPIKeyOrg struct {
+	store sqlstore.SQLStore
+}
+
+func NewAddAPIKeyOrgFactory(store sqlstore.SQLStore) factory.ProviderFactory[SQLMigration, Config] {
+	return factory.NewProviderFactory(factory.MustNewName("add_api_key_org"), func(ctx context.Context, ps factory.ProviderSettings, c Config) (SQLMigration, error) {
+		return &addAPIKeyOrg{store: store}, nil
+	})
+}
+
+func (migration *addAPIKeyOrg) Register(migrations *migrate.Migrations) error {
+	if err := migrations.Register(migration.Up, migration.Down); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (migration *addAPIKeyOrg) Up(ctx context.Context, db *bun.DB) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if err := migration.store.Dialect().AddColumn(ctx, tx, "factor_api_key", "org_id", "TEXT"); err != nil {
+		return err
+	}
+
+	// the existing keys keep acting in the home org of their user
+	_, err = tx.
+		NewUpdate().
+		Table("factor_api_key").
+		Set("org_id = (SELECT users.org_id FROM users WHERE users.id = factor_api_key.user_id)").
+		Where("org_id IS NULL").
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (migration *addAPIKeyOrg) Down(ctx context.Context, db *bun.DB) error {
+	return nil
+}
@@ -261,13 +261,10 @@ func (c *Config) DeleteReceiver(name string) error {
 		return errors.New(errors.TypeInvalidInput, ErrCodeAlertmanagerConfigInvalid, "delete receiver requires the receiver name")
 	}
 
-	routes := c.alertmanagerConfig.Route.Routes
-	for i, r := range routes {
-		if r.Receiver == name {
-			c.alertmanagerConfig.Route.Routes = append(routes[:i], routes[i+1:]...)
-			break
-		}
-	}
+	// the receiver can be routed to for any number of teams
+	c.alertmanagerConfig.Route.Routes = slices.DeleteFunc(c.alertmanagerConfig.Route.Routes, func(route *config.Route) bool {
+		return route.Receiver == name
+	})
 
 	for i, existingReceiver := range c.alertmanagerConfig.Receivers {
 		if existingReceiver.Name == name {
@@ -289,6 +286,10 @@ func (c *Config) CreateRuleIDMatcher(ruleID string, receiverNames []string) erro
 	}
 
 	for _, route := range c.alertmanagerConfig.Route.Routes {
+		if isTeamRoute(route) {
+			continue
+		}
+
 		if slices.Contains(receiverNames, route.Receiver) {
 			if err := addRuleIDToRoute(route, ruleID); err != nil {
 				return err
@@ -330,6 +331,10 @@ func (c *Config) ReceiverNamesFromRuleID(ruleID string) []string {
 	receiverNames := make([]string, 0)
 	routes := c.alertmanagerConfig.Route.Routes
 	for _, route := range routes {
+		if isTeamRoute(route) {
+			continue
+		}
+
 		if ok := matcherContainsRuleID(route.Matchers, ruleID); ok {
 			receiverNames = append(receiverNames, route.Receiver)
 		}
@@ -338,6 +343,58 @@ func (c *Config) ReceiverNamesFromRuleID(ruleID string) []string {
 	return receiverNames
 }
 
+// SetTeamRoutes routes the alerts labelled with the team to the receivers, replacing the receivers the team was routed
+// to before. The alerts keep going to the receivers of their rule as well.
+func (c *Config) SetTeamRoutes(team string, receiverNames []string) error {
+	if team == "" {
+		return errors.New(errors.TypeInvalidInput, ErrCodeAlertmanagerConfigInvalid, "set team routes requires the team name")
+	}
+
+	if c.alertmanagerConfig.Route == nil {
+		return errors.New(errors.TypeInvalidInput, ErrCodeAlertmanagerConfigInvalid, "route is nil")
+	}
+
+	for _, name := range receiverNames {
+		if _, err := c.GetReceiver(name); err != nil {
+			return err
+		}
+	}
+
+	c.alertmanagerConfig.Route.Routes = slices.DeleteFunc(c.alertmanagerConfig.Route.Routes, func(route *config.Route) bool {
+		return teamOfRoute(route) == team
+	})
+
+	for _, name := range receiverNames {
+		route, err := NewRouteFromTeam(team, name)
+		if err != nil {
+			return err
+		}
+
+		c.alertmanagerConfig.Route.Routes = append(c.alertmanagerConfig.Route.Routes, route)
+	}
+
+	c.storeableConfig.Config = string(newRawFromConfig(c.alertmanagerConfig))
+	c.storeableConfig.Hash = fmt.Sprintf("%x", newConfigHash(c.storeableConfig.Config))
+	c.storeableConfig.UpdatedAt = time.Now()
+
+	return nil
+}
+
+func (c *Config) DeleteTeamRoutes(team string) error {
+	return c.SetTeamRoutes(team, nil)
+}
+
+func (c *Config) ReceiverNamesFromTeam(team string) []string {
+	receiverNames := make([]string, 0)
+	for _, route := range c.alertmanagerConfig.Route.Routes {
+		if teamOfRoute(route) == team {
+			receiverNames = append(receiverNames, route.Receiver)
+		}
+	}
+
+	return receiverNames
+}
+
 type storeOptions struct {
 	Cb func(context.Context) error
 }
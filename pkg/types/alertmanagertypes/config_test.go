@@ -227,6 +227,39 @@ func TestDeleteRuleIDMatcher(t *testing.T) {
 	}
 }
 
+func TestSetTeamRoutes(t *testing.T) {
+	cfg, err := NewDefaultConfig(
+		GlobalConfig{SMTPSmarthost: config.HostPort{Host: "localhost", Port: "25"}, SMTPFrom: "test@example.com"},
+		RouteConfig{GroupInterval: 1 * time.Minute, GroupWait: 1 * time.Minute, RepeatInterval: 1 * time.Minute},
+		"1",
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, cfg.CreateReceiver(config.Receiver{Name: "slack-receiver", SlackConfigs: []*config.SlackConfig{{Channel: "#alerts", APIURL: &config.SecretURL{URL: &url.URL{Scheme: "https", Host: "slack.com", Path: "/api/test"}}}}}))
+	require.NoError(t, cfg.CreateReceiver(config.Receiver{Name: "email-receiver", EmailConfigs: []*config.EmailConfig{{To: "test@example.com"}}}))
+
+	require.NoError(t, cfg.SetTeamRoutes("payments", []string{"slack-receiver", "email-receiver"}))
+	require.NoError(t, cfg.SetTeamRoutes("search", []string{"email-receiver"}))
+	assert.Error(t, cfg.SetTeamRoutes("search", []string{"does-not-exist"}))
+	assert.Error(t, cfg.SetTeamRoutes("", []string{"email-receiver"}))
+
+	// the routes of the teams are not matched on rule ids
+	require.NoError(t, cfg.CreateRuleIDMatcher("test-rule", []string{"slack-receiver"}))
+	assert.Equal(t, []string{"slack-receiver"}, cfg.ReceiverNamesFromRuleID("test-rule"))
+	assert.Equal(t, []string{"slack-receiver", "email-receiver"}, cfg.ReceiverNamesFromTeam("payments"))
+
+	require.NoError(t, cfg.SetTeamRoutes("payments", []string{"slack-receiver"}))
+	require.NoError(t, cfg.DeleteReceiver("slack-receiver"))
+	require.NoError(t, cfg.DeleteTeamRoutes("search"))
+
+	routes, err := json.Marshal(cfg.alertmanagerConfig.Route.Routes)
+	require.NoError(t, err)
+	var actualRoutes []map[string]any
+	require.NoError(t, json.Unmarshal(routes, &actualRoutes))
+	assert.ElementsMatch(t, []map[string]any{{"receiver": "email-receiver", "continue": true, "matchers": []any{"ruleId=~\"-1\""}}}, actualRoutes)
+	assert.Empty(t, cfg.ReceiverNamesFromTeam("payments"))
+}
+
 func TestSetRouteConfigWithNilRoute(t *testing.T) {
 	cfg := NewConfig(&config.Config{}, "1")
 	err := cfg.SetRouteConfig(RouteConfig{GroupByStr: []string{"alertname"}, GroupInterval: 1 * time.Minute, GroupWait: 1 * time.Minute, RepeatInterval: 1 * time.Minute})
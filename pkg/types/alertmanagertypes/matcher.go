@@ -11,6 +11,8 @@ import (
 const (
 	RuleIDMatcherName     string = "ruleId"
 	ruleIDMatcherValueSep string = "|"
+	// TeamMatcherName is the label of the alerts routed to the channels of the team owning their rule.
+	TeamMatcherName string = "team"
 )
 
 var (
@@ -85,3 +87,16 @@ func matcherContainsRuleID(matchers config.Matchers, ruleID string) bool {
 
 	return false
 }
+
+// teamOfRoute returns the team the route was created for, routes of teams match on the team label only.
+func teamOfRoute(route *config.Route) string {
+	if len(route.Matchers) != 1 || route.Matchers[0].Name != TeamMatcherName || route.Matchers[0].Type != labels.MatchEqual {
+		return ""
+	}
+
+	return route.Matchers[0].Value
+}
+
+func isTeamRoute(route *config.Route) bool {
+	return teamOfRoute(route) != ""
+}
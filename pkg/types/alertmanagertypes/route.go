@@ -2,6 +2,7 @@ package alertmanagertypes
 
 import (
 	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/pkg/labels"
 	"github.com/prometheus/common/model"
 )
 
@@ -36,3 +37,17 @@ func NewRouteFromReceiver(receiver Receiver) (*config.Route, error) {
 
 	return route, nil
 }
+
+func NewRouteFromTeam(team string, receiverName string) (*config.Route, error) {
+	matcher, err := labels.NewMatcher(labels.MatchEqual, TeamMatcherName, team)
+	if err != nil {
+		return nil, err
+	}
+
+	route := &config.Route{Receiver: receiverName, Continue: true, Matchers: config.Matchers{matcher}}
+	if err := route.UnmarshalYAML(func(i interface{}) error { return nil }); err != nil {
+		return nil, err
+	}
+
+	return route, nil
+}
@@ -0,0 +1,419 @@
+package annotationtypes
+
+import (
+	"cmp"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/errors"
+	"github.com/SigNoz/signoz/pkg/types"
+	"github.com/SigNoz/signoz/pkg/valuer"
+	"github.com/uptrace/bun"
+)
+
+var (
+	ErrCodeAnnotationNotFound = errors.MustNewCode("annotation_not_found")
+)
+
+const (
+	// DefaultTimeRange is the time range the annotations are listed for when none is given, up to now.
+	DefaultTimeRange = 24 * time.Hour
+	// MaxTimeRange is the longest time range the annotations can be listed for.
+	MaxTimeRange = 31 * 24 * time.Hour
+	// MaxDerived is the number of annotations derived from the telemetry for a time range.
+	MaxDerived = 1000
+
+	maxTextLength = 1024
+	maxTags       = 20
+)
+
+// Source is where an annotation comes from, only the annotations created by users and by deploys are stored, the
+// others are derived from the telemetry when the annotations are listed.
+type Source struct{ valuer.String }
+
+var (
+	SourceUser   = Source{valuer.NewString("user")}
+	SourceDeploy = Source{valuer.NewString("deploy")}
+	// SourceAlert annotations are the state transitions of the alert rules.
+	SourceAlert = Source{valuer.NewString("alert")}
+	// SourceVersion annotations are the new values of service.version seen in the resources of the spans.
+	SourceVersion = Source{valuer.NewString("version")}
+)
+
+var derivedSources = []Source{SourceAlert, SourceVersion}
+
+func NewDerivedSource(source string) (Source, error) {
+	for _, s := range derivedSources {
+		if s.StringValue() == source {
+			return s, nil
+		}
+	}
+
+	return Source{}, errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "invalid derived source: %s, must be one of alert, version", source)
+}
+
+// Annotation marks a time or a time range of the charts with a text, e.g. a deploy or an incident.
+type Annotation struct {
+	bun.BaseModel `bun:"table:annotation"`
+
+	types.Identifiable
+	types.TimeAuditable
+	OrgID valuer.UUID `bun:"org_id,type:text,notnull" json:"-"`
+	// the dashboard the annotation is shown on, every dashboard when empty
+	DashboardID string    `bun:"dashboard_id,type:text" json:"dashboardId,omitempty"`
+	StartTime   time.Time `bun:"start_time,notnull" json:"startTime"`
+	// the end of the range annotated, nil for the annotations of a single time
+	EndTime *time.Time `bun:"end_time" json:"endTime,omitempty"`
+	Text    string     `bun:"text,type:text,notnull" json:"text"`
+	Tags    []string   `bun:"tags,type:text" json:"tags"`
+	// the service and the environment the annotation is about, the annotations without them are about everything
+	Service     string `bun:"service,type:text" json:"service,omitempty"`
+	Environment string `bun:"environment,type:text" json:"environment,omitempty"`
+	Source      Source `bun:"source,type:text,notnull" json:"source"`
+	CreatedBy   string `bun:"created_by,type:text" json:"createdBy,omitempty"`
+}
+
+type PostableAnnotation struct {
+	DashboardID string     `json:"dashboardId"`
+	StartTime   time.Time  `json:"startTime"`
+	EndTime     *time.Time `json:"endTime"`
+	Text        string     `json:"text"`
+	Tags        []string   `json:"tags"`
+	Service     string     `json:"service"`
+	Environment string     `json:"environment"`
+}
+
+type UpdatableAnnotation = PostableAnnotation
+
+// PostableDeploy is the deploy of a version of a service, sent by CI pipelines once deployed.
+type PostableDeploy struct {
+	Service     string `json:"service"`
+	Environment string `json:"environment"`
+	Version     string `json:"version"`
+	// the time of the deploy, now when nil
+	Timestamp *time.Time `json:"timestamp"`
+	// Deployed <service> <version> when empty
+	Text string   `json:"text"`
+	Tags []string `json:"tags"`
+}
+
+// ListParams filters the annotations, the annotations overlapping the time range are listed.
+type ListParams struct {
+	Start time.Time
+	End   time.Time
+	// the annotations of the dashboard and the ones of every dashboard, every annotation when empty
+	DashboardID string
+	// the annotations of the service and the ones without a service
+	Service string
+	// the annotations of the environment and the ones without an environment
+	Environment string
+	// the annotations must have all the tags
+	Tags []string
+	// the sources of the annotations derived from the telemetry to list along the stored ones
+	Derived []Source
+}
+
+// ServiceVersion is a version of a service first seen in the resources of the spans at a time.
+type ServiceVersion struct {
+	Service     string    `ch:"service"`
+	Environment string    `ch:"environment"`
+	Version     string    `ch:"version"`
+	FirstSeen   time.Time `ch:"first_seen"`
+}
+
+// AlertTransition is a change of the overall state of an alert rule.
+type AlertTransition struct {
+	RuleID      string `ch:"rule_id"`
+	RuleName    string `ch:"rule_name"`
+	State       string `ch:"overall_state"`
+	UnixMilli   int64  `ch:"unix_milli"`
+	Service     string `ch:"service"`
+	Environment string `ch:"environment"`
+}
+
+func NewAnnotation(orgID valuer.UUID, createdBy string, source Source, postable *PostableAnnotation) (*Annotation, error) {
+	if err := postable.Validate(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return &Annotation{
+		Identifiable: types.Identifiable{
+			ID: valuer.GenerateUUID(),
+		},
+		TimeAuditable: types.TimeAuditable{
+			CreatedAt: now,
+			UpdatedAt: now,
+		},
+		OrgID:       orgID,
+		DashboardID: postable.DashboardID,
+		StartTime:   postable.StartTime,
+		EndTime:     postable.EndTime,
+		Text:        postable.Text,
+		Tags:        postable.Tags,
+		Service:     postable.Service,
+		Environment: postable.Environment,
+		Source:      source,
+		CreatedBy:   createdBy,
+	}, nil
+}
+
+// NewPostableAnnotationFromDeploy returns the annotation of the deploy, tagged with deploy and with its version.
+func NewPostableAnnotationFromDeploy(deploy *PostableDeploy) (*PostableAnnotation, error) {
+	if err := deploy.Validate(); err != nil {
+		return nil, err
+	}
+
+	timestamp := time.Now()
+	if deploy.Timestamp != nil {
+		timestamp = *deploy.Timestamp
+	}
+
+	text := deploy.Text
+	if text == "" {
+		text = "Deployed " + deploy.Service + " " + deploy.Version
+	}
+
+	return &PostableAnnotation{
+		StartTime:   timestamp,
+		Text:        text,
+		Tags:        append([]string{"deploy", "version:" + deploy.Version}, deploy.Tags...),
+		Service:     deploy.Service,
+		Environment: deploy.Environment,
+	}, nil
+}
+
+// NewVersionAnnotations returns the annotations of the changes of versions of the services since the start. The
+// versions first seen before the start were already running and are only the versions changed from, the first version
+// of a service and environment was deployed before any span was seen and isn't a change either.
+func NewVersionAnnotations(orgID valuer.UUID, versions []ServiceVersion, start time.Time) []*Annotation {
+	versions = slices.SortedStableFunc(slices.Values(versions), func(a, b ServiceVersion) int {
+		return a.FirstSeen.Compare(b.FirstSeen)
+	})
+
+	previous := make(map[string]string)
+	annotations := make([]*Annotation, 0)
+	for _, version := range versions {
+		key := version.Service + "\x00" + version.Environment
+		from, ok := previous[key]
+		previous[key] = version.Version
+		if !ok || version.FirstSeen.Before(start) {
+			continue
+		}
+
+		annotations = append(annotations, &Annotation{
+			OrgID:       orgID,
+			StartTime:   version.FirstSeen,
+			Text:        version.Service + " changed from " + from + " to " + version.Version,
+			Tags:        []string{"version:" + version.Version},
+			Service:     version.Service,
+			Environment: version.Environment,
+			Source:      SourceVersion,
+		})
+	}
+
+	return annotations
+}
+
+// NewAlertAnnotations returns the annotations of the transitions of the alert rules.
+func NewAlertAnnotations(orgID valuer.UUID, transitions []AlertTransition) []*Annotation {
+	annotations := make([]*Annotation, 0, len(transitions))
+	for _, transition := range transitions {
+		annotations = append(annotations, &Annotation{
+			OrgID:       orgID,
+			StartTime:   time.UnixMilli(transition.UnixMilli),
+			Text:        transition.RuleName + " is " + transition.State,
+			Tags:        []string{"alert", transition.State},
+			Service:     transition.Service,
+			Environment: transition.Environment,
+			Source:      SourceAlert,
+		})
+	}
+
+	return annotations
+}
+
+func NewListParams(req *http.Request) (*ListParams, error) {
+	query := req.URL.Query()
+	params := &ListParams{
+		End:         time.Now(),
+		DashboardID: query.Get("dashboardId"),
+		Service:     query.Get("service"),
+		Environment: query.Get("environment"),
+	}
+
+	if end := query.Get("end"); end != "" {
+		millis, err := strconv.ParseInt(end, 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "end must be epoch milliseconds")
+		}
+
+		params.End = time.UnixMilli(millis)
+	}
+
+	params.Start = params.End.Add(-DefaultTimeRange)
+	if start := query.Get("start"); start != "" {
+		millis, err := strconv.ParseInt(start, 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "start must be epoch milliseconds")
+		}
+
+		params.Start = time.UnixMilli(millis)
+	}
+
+	for _, tag := range query["tag"] {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			params.Tags = append(params.Tags, tag)
+		}
+	}
+
+	for _, value := range query["derived"] {
+		source, err := NewDerivedSource(value)
+		if err != nil {
+			return nil, err
+		}
+
+		params.Derived = append(params.Derived, source)
+	}
+
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	return params, nil
+}
+
+func (annotation *Annotation) Update(updatable *UpdatableAnnotation) error {
+	if err := updatable.Validate(); err != nil {
+		return err
+	}
+
+	annotation.DashboardID = updatable.DashboardID
+	annotation.StartTime = updatable.StartTime
+	annotation.EndTime = updatable.EndTime
+	annotation.Text = updatable.Text
+	annotation.Tags = updatable.Tags
+	annotation.Service = updatable.Service
+	annotation.Environment = updatable.Environment
+	annotation.UpdatedAt = time.Now()
+	return nil
+}
+
+// end returns the end of the annotation, its start for the annotations of a single time.
+func (annotation *Annotation) end() time.Time {
+	if annotation.EndTime == nil {
+		return annotation.StartTime
+	}
+
+	return *annotation.EndTime
+}
+
+func (p *PostableAnnotation) Validate() error {
+	p.Text = strings.TrimSpace(p.Text)
+	if p.Text == "" {
+		return errors.New(errors.TypeInvalidInput, errors.CodeInvalidInput, "text is required")
+	}
+
+	if len(p.Text) > maxTextLength {
+		return errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "text must be at most %d characters", maxTextLength)
+	}
+
+	if p.StartTime.IsZero() {
+		return errors.New(errors.TypeInvalidInput, errors.CodeInvalidInput, "startTime is required")
+	}
+
+	if p.EndTime != nil && p.EndTime.Before(p.StartTime) {
+		return errors.New(errors.TypeInvalidInput, errors.CodeInvalidInput, "endTime must be after startTime")
+	}
+
+	if p.DashboardID != "" {
+		if _, err := valuer.NewUUID(p.DashboardID); err != nil {
+			return errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "dashboardId is not a valid uuid")
+		}
+	}
+
+	tags := make([]string, 0, len(p.Tags))
+	for _, tag := range p.Tags {
+		if tag = strings.TrimSpace(tag); tag != "" && !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+
+	if len(tags) > maxTags {
+		return errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "at most %d tags are allowed", maxTags)
+	}
+	p.Tags = tags
+
+	p.Service = strings.TrimSpace(p.Service)
+	p.Environment = strings.TrimSpace(p.Environment)
+	return nil
+}
+
+func (d *PostableDeploy) Validate() error {
+	d.Service = strings.TrimSpace(d.Service)
+	if d.Service == "" {
+		return errors.New(errors.TypeInvalidInput, errors.CodeInvalidInput, "service is required")
+	}
+
+	d.Version = strings.TrimSpace(d.Version)
+	if d.Version == "" {
+		return errors.New(errors.TypeInvalidInput, errors.CodeInvalidInput, "version is required")
+	}
+
+	return nil
+}
+
+func (p *ListParams) Validate() error {
+	if !p.Start.Before(p.End) {
+		return errors.New(errors.TypeInvalidInput, errors.CodeInvalidInput, "start must be before end")
+	}
+
+	if p.End.Sub(p.Start) > MaxTimeRange {
+		return errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "the time range must be at most %s", MaxTimeRange)
+	}
+
+	if p.DashboardID != "" {
+		if _, err := valuer.NewUUID(p.DashboardID); err != nil {
+			return errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "dashboardId is not a valid uuid")
+		}
+	}
+
+	return nil
+}
+
+// Matches checks that the annotation overlaps the time range and is in the scope of the params.
+func (p *ListParams) Matches(annotation *Annotation) bool {
+	if annotation.StartTime.After(p.End) || annotation.end().Before(p.Start) {
+		return false
+	}
+
+	if annotation.DashboardID != "" && p.DashboardID != "" && annotation.DashboardID != p.DashboardID {
+		return false
+	}
+
+	if annotation.Service != "" && p.Service != "" && annotation.Service != p.Service {
+		return false
+	}
+
+	if annotation.Environment != "" && p.Environment != "" && annotation.Environment != p.Environment {
+		return false
+	}
+
+	for _, tag := range p.Tags {
+		if !slices.Contains(annotation.Tags, tag) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Sort sorts the annotations by their start, the earliest first.
+func Sort(annotations []*Annotation) {
+	slices.SortStableFunc(annotations, func(a, b *Annotation) int {
+		return cmp.Compare(a.StartTime.UnixNano(), b.StartTime.UnixNano())
+	})
+}
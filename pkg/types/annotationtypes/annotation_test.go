@@ -0,0 +1,101 @@
+package annotationtypes
+
+import (
+	"testing"
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/valuer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostableAnnotationValidate(t *testing.T) {
+	now := time.Now()
+	newPostable := func() *PostableAnnotation {
+		return &PostableAnnotation{
+			StartTime: now,
+			Text:      " incident ",
+			Tags:      []string{"sev1", " ", "sev1", " db "},
+		}
+	}
+
+	postable := newPostable()
+	require.NoError(t, postable.Validate())
+	assert.Equal(t, "incident", postable.Text)
+	assert.Equal(t, []string{"sev1", "db"}, postable.Tags)
+
+	for _, tc := range []struct {
+		name   string
+		modify func(*PostableAnnotation)
+	}{
+		{name: "NoText", modify: func(p *PostableAnnotation) { p.Text = " " }},
+		{name: "NoStartTime", modify: func(p *PostableAnnotation) { p.StartTime = time.Time{} }},
+		{name: "EndBeforeStart", modify: func(p *PostableAnnotation) {
+			end := now.Add(-time.Minute)
+			p.EndTime = &end
+		}},
+		{name: "InvalidDashboardID", modify: func(p *PostableAnnotation) { p.DashboardID = "redis" }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			postable := newPostable()
+			tc.modify(postable)
+			assert.Error(t, postable.Validate())
+		})
+	}
+}
+
+func TestNewPostableAnnotationFromDeploy(t *testing.T) {
+	postable, err := NewPostableAnnotationFromDeploy(&PostableDeploy{Service: "checkout", Environment: "prod", Version: "1.4.2", Tags: []string{"ci"}})
+	require.NoError(t, err)
+	assert.Equal(t, "Deployed checkout 1.4.2", postable.Text)
+	assert.Equal(t, []string{"deploy", "version:1.4.2", "ci"}, postable.Tags)
+	assert.Equal(t, "checkout", postable.Service)
+	assert.False(t, postable.StartTime.IsZero())
+
+	_, err = NewPostableAnnotationFromDeploy(&PostableDeploy{Service: "checkout"})
+	assert.Error(t, err)
+}
+
+func TestListParamsMatches(t *testing.T) {
+	now := time.Now()
+	dashboardID := valuer.GenerateUUID().StringValue()
+	params := &ListParams{Start: now.Add(-time.Hour), End: now, DashboardID: dashboardID, Service: "checkout", Tags: []string{"deploy"}}
+
+	end := now.Add(-30 * time.Minute)
+	for _, tc := range []struct {
+		name       string
+		annotation *Annotation
+		matches    bool
+	}{
+		{name: "Unscoped", annotation: &Annotation{StartTime: now.Add(-time.Minute), Tags: []string{"deploy"}}, matches: true},
+		{name: "Scoped", annotation: &Annotation{StartTime: now.Add(-time.Minute), DashboardID: dashboardID, Service: "checkout", Tags: []string{"deploy", "ci"}}, matches: true},
+		{name: "RangeOverlapping", annotation: &Annotation{StartTime: now.Add(-2 * time.Hour), EndTime: &end, Tags: []string{"deploy"}}, matches: true},
+		{name: "Before", annotation: &Annotation{StartTime: now.Add(-2 * time.Hour), Tags: []string{"deploy"}}, matches: false},
+		{name: "OtherDashboard", annotation: &Annotation{StartTime: now.Add(-time.Minute), DashboardID: valuer.GenerateUUID().StringValue(), Tags: []string{"deploy"}}, matches: false},
+		{name: "OtherService", annotation: &Annotation{StartTime: now.Add(-time.Minute), Service: "cart", Tags: []string{"deploy"}}, matches: false},
+		{name: "MissingTag", annotation: &Annotation{StartTime: now.Add(-time.Minute)}, matches: false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.matches, params.Matches(tc.annotation))
+		})
+	}
+}
+
+func TestNewVersionAnnotations(t *testing.T) {
+	now := time.Now()
+	annotations := NewVersionAnnotations(valuer.GenerateUUID(), []ServiceVersion{
+		{Service: "checkout", Environment: "prod", Version: "1.1", FirstSeen: now.Add(-time.Hour)},
+		{Service: "checkout", Environment: "prod", Version: "1.0", FirstSeen: now.Add(-2 * time.Hour)},
+		{Service: "checkout", Environment: "staging", Version: "1.2", FirstSeen: now.Add(-3 * time.Hour)},
+		{Service: "cart", Environment: "prod", Version: "2.0", FirstSeen: now.Add(-time.Hour)},
+		{Service: "cart", Environment: "prod", Version: "1.9", FirstSeen: now.Add(-5 * time.Hour)},
+		{Service: "cart", Environment: "prod", Version: "1.8", FirstSeen: now.Add(-6 * time.Hour)},
+	}, now.Add(-4*time.Hour))
+
+	// the versions seen before the time range were already running, the first versions seen were deployed before
+	require.Len(t, annotations, 2)
+	assert.Equal(t, "cart changed from 1.9 to 2.0", annotations[1].Text)
+	assert.Equal(t, "checkout changed from 1.0 to 1.1", annotations[0].Text)
+	assert.Equal(t, "prod", annotations[0].Environment)
+	assert.Equal(t, SourceVersion, annotations[0].Source)
+}
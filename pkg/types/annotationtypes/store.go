@@ -0,0 +1,18 @@
+package annotationtypes
+
+import (
+	"context"
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/valuer"
+)
+
+type Store interface {
+	Create(context.Context, *Annotation) error
+	Get(context.Context, valuer.UUID, valuer.UUID) (*Annotation, error)
+	Update(context.Context, *Annotation) error
+	Delete(context.Context, valuer.UUID, valuer.UUID) error
+
+	// List lists the annotations of the org overlapping the time range.
+	List(context.Context, valuer.UUID, time.Time, time.Time) ([]*Annotation, error)
+}
@@ -0,0 +1,361 @@
+package audittypes
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/errors"
+	"github.com/SigNoz/signoz/pkg/types"
+	"github.com/SigNoz/signoz/pkg/types/authtypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+	"github.com/uptrace/bun"
+)
+
+const (
+	DefaultLimit int = 50
+	MaxLimit     int = 1000
+)
+
+// ActorType is the kind of principal a change is made by.
+type ActorType struct{ valuer.String }
+
+var (
+	ActorTypeUser   = ActorType{valuer.NewString("user")}
+	ActorTypeAPIKey = ActorType{valuer.NewString("api_key")}
+	// changes made without a user, e.g. by the identity provider or background jobs
+	ActorTypeSystem = ActorType{valuer.NewString("system")}
+)
+
+type Action struct{ valuer.String }
+
+var (
+	ActionCreate = Action{valuer.NewString("create")}
+	ActionUpdate = Action{valuer.NewString("update")}
+	ActionDelete = Action{valuer.NewString("delete")}
+)
+
+type ResourceType struct{ valuer.String }
+
+var (
+	ResourceTypeDashboard       = ResourceType{valuer.NewString("dashboard")}
+	ResourceTypeSavedView       = ResourceType{valuer.NewString("saved_view")}
+	ResourceTypeRule            = ResourceType{valuer.NewString("rule")}
+	ResourceTypeChannel         = ResourceType{valuer.NewString("channel")}
+	ResourceTypeAgentConfig     = ResourceType{valuer.NewString("agent_config")}
+	ResourceTypeAPIKey          = ResourceType{valuer.NewString("api_key")}
+	ResourceTypeUser            = ResourceType{valuer.NewString("user")}
+	ResourceTypeTTL             = ResourceType{valuer.NewString("ttl")}
+	ResourceTypeTeam            = ResourceType{valuer.NewString("team")}
+	ResourceTypeShareLink       = ResourceType{valuer.NewString("share_link")}
+	ResourceTypeReport          = ResourceType{valuer.NewString("report")}
+	ResourceTypeDashboardFolder = ResourceType{valuer.NewString("dashboard_folder")}
+	ResourceTypeAnnotation      = ResourceType{valuer.NewString("annotation")}
+)
+
+// Resource identifies what a change is made to.
+type Resource struct {
+	Type ResourceType
+	ID   string
+}
+
+func NewResource(resourceType ResourceType, id string) Resource {
+	return Resource{Type: resourceType, ID: id}
+}
+
+// Origin is where the request making a change comes from.
+type Origin struct {
+	ClientAddress string
+	UserAgent     string
+}
+
+type originKey struct{}
+
+// NewContextWithOrigin attaches the origin of the request to the context.
+func NewContextWithOrigin(ctx context.Context, origin Origin) context.Context {
+	return context.WithValue(ctx, originKey{}, origin)
+}
+
+func OriginFromContext(ctx context.Context) Origin {
+	origin, _ := ctx.Value(originKey{}).(Origin)
+	return origin
+}
+
+// StorableEvent is an entry of the audit log, entries are never updated nor deleted.
+type StorableEvent struct {
+	bun.BaseModel `bun:"table:audit_event"`
+
+	types.Identifiable
+	OrgID         valuer.UUID  `bun:"org_id,type:text,notnull"`
+	Timestamp     time.Time    `bun:"timestamp,notnull"`
+	ActorType     ActorType    `bun:"actor_type,type:text,notnull"`
+	ActorID       string       `bun:"actor_id,type:text"`
+	ActorEmail    string       `bun:"actor_email,type:text"`
+	Action        Action       `bun:"action,type:text,notnull"`
+	ResourceType  ResourceType `bun:"resource_type,type:text,notnull"`
+	ResourceID    string       `bun:"resource_id,type:text,notnull"`
+	Before        string       `bun:"before,type:text"`
+	After         string       `bun:"after,type:text"`
+	ClientAddress string       `bun:"client_address,type:text"`
+	UserAgent     string       `bun:"user_agent,type:text"`
+}
+
+type GettableEvent struct {
+	ID            valuer.UUID     `json:"id"`
+	Timestamp     time.Time       `json:"timestamp"`
+	ActorType     ActorType       `json:"actorType"`
+	ActorID       string          `json:"actorId"`
+	ActorEmail    string          `json:"actorEmail"`
+	Action        Action          `json:"action"`
+	ResourceType  ResourceType    `json:"resourceType"`
+	ResourceID    string          `json:"resourceId"`
+	Before        json.RawMessage `json:"before"`
+	After         json.RawMessage `json:"after"`
+	Changes       []*Change       `json:"changes"`
+	ClientAddress string          `json:"clientAddress"`
+	UserAgent     string          `json:"userAgent"`
+}
+
+type GettableEvents struct {
+	Events []*GettableEvent `json:"events"`
+	Total  int              `json:"total"`
+}
+
+// ListParams filters the events of an org, the zero value matches all of them.
+type ListParams struct {
+	ActorID      string
+	Action       string
+	ResourceType string
+	ResourceID   string
+	Start        time.Time
+	End          time.Time
+	Limit        int
+	Offset       int
+}
+
+// NewEvent creates the event of the change made by the actor of the context, the states before and after the change are
+// stored with their sensitive fields redacted.
+func NewEvent(ctx context.Context, orgID valuer.UUID, action Action, resource Resource, before any, after any) (*StorableEvent, error) {
+	beforeJSON, err := newSnapshot(before)
+	if err != nil {
+		return nil, err
+	}
+
+	afterJSON, err := newSnapshot(after)
+	if err != nil {
+		return nil, err
+	}
+
+	origin := OriginFromContext(ctx)
+	event := &StorableEvent{
+		Identifiable:  types.Identifiable{ID: valuer.GenerateUUID()},
+		OrgID:         orgID,
+		Timestamp:     time.Now(),
+		ActorType:     ActorTypeSystem,
+		Action:        action,
+		ResourceType:  resource.Type,
+		ResourceID:    resource.ID,
+		Before:        beforeJSON,
+		After:         afterJSON,
+		ClientAddress: origin.ClientAddress,
+		UserAgent:     origin.UserAgent,
+	}
+
+	if claims, err := authtypes.ClaimsFromContext(ctx); err == nil {
+		event.ActorType = ActorTypeUser
+		event.ActorID = claims.UserID
+		event.ActorEmail = claims.Email
+		if claims.APIKeyID != "" {
+			event.ActorType = ActorTypeAPIKey
+			event.ActorID = claims.APIKeyID
+		}
+	}
+
+	return event, nil
+}
+
+// NewSnapshot captures the state of a resource before it is changed in place.
+func NewSnapshot(resource any) json.RawMessage {
+	snapshot, err := json.Marshal(resource)
+	if err != nil {
+		return nil
+	}
+
+	return snapshot
+}
+
+func NewGettableEvent(event *StorableEvent) *GettableEvent {
+	return &GettableEvent{
+		ID:            event.ID,
+		Timestamp:     event.Timestamp,
+		ActorType:     event.ActorType,
+		ActorID:       event.ActorID,
+		ActorEmail:    event.ActorEmail,
+		Action:        event.Action,
+		ResourceType:  event.ResourceType,
+		ResourceID:    event.ResourceID,
+		Before:        rawMessage(event.Before),
+		After:         rawMessage(event.After),
+		Changes:       NewChanges(event.Before, event.After),
+		ClientAddress: event.ClientAddress,
+		UserAgent:     event.UserAgent,
+	}
+}
+
+func NewListParams(req *http.Request) (*ListParams, error) {
+	query := req.URL.Query()
+	params := &ListParams{
+		ActorID:      query.Get("actorId"),
+		Action:       query.Get("action"),
+		ResourceType: query.Get("resourceType"),
+		ResourceID:   query.Get("resourceId"),
+		Limit:        DefaultLimit,
+	}
+
+	var err error
+	if params.Start, err = parseTime(query.Get("start")); err != nil {
+		return nil, err
+	}
+
+	if params.End, err = parseTime(query.Get("end")); err != nil {
+		return nil, err
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		if params.Limit, err = strconv.Atoi(limit); err != nil || params.Limit <= 0 || params.Limit > MaxLimit {
+			return nil, errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "limit must be between 1 and %d", MaxLimit)
+		}
+	}
+
+	if offset := query.Get("offset"); offset != "" {
+		if params.Offset, err = strconv.Atoi(offset); err != nil || params.Offset < 0 {
+			return nil, errors.New(errors.TypeInvalidInput, errors.CodeInvalidInput, "offset must be a positive integer")
+		}
+	}
+
+	return params, nil
+}
+
+type Store interface {
+	Create(context.Context, *StorableEvent) error
+	List(context.Context, valuer.UUID, *ListParams) ([]*StorableEvent, int, error)
+}
+
+// parseTime parses a unix timestamp in milliseconds.
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	millis, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "invalid timestamp: %s, must be in milliseconds", value)
+	}
+
+	return time.UnixMilli(millis), nil
+}
+
+func rawMessage(value string) json.RawMessage {
+	if value == "" {
+		return nil
+	}
+
+	return json.RawMessage(value)
+}
+
+// sensitiveKeys are the fields redacted from the snapshots, they are compared case insensitively without separators.
+var sensitiveKeys = map[string]struct{}{
+	"password":     {},
+	"token":        {},
+	"secret":       {},
+	"apikey":       {},
+	"accesstoken":  {},
+	"refreshtoken": {},
+	"privatekey":   {},
+	"clientsecret": {},
+	"credentials":  {},
+	// secrets of the alertmanager receivers
+	"apiurl":       {},
+	"webhookurl":   {},
+	"routingkey":   {},
+	"servicekey":   {},
+	"authpassword": {},
+	"authsecret":   {},
+	"bottoken":     {},
+}
+
+const redacted string = "<redacted>"
+
+func newSnapshot(resource any) (string, error) {
+	if resource == nil {
+		return "", nil
+	}
+
+	data, err := json.Marshal(resource)
+	if err != nil {
+		return "", errors.Wrapf(err, errors.TypeInternal, errors.CodeInternal, "failed to marshal resource")
+	}
+
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return "", errors.Wrapf(err, errors.TypeInternal, errors.CodeInternal, "failed to unmarshal resource")
+	}
+
+	if value == nil {
+		return "", nil
+	}
+
+	data, err = json.Marshal(redact(value))
+	if err != nil {
+		return "", errors.Wrapf(err, errors.TypeInternal, errors.CodeInternal, "failed to marshal resource")
+	}
+
+	return string(data), nil
+}
+
+func redact(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, child := range v {
+			normalized := strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(key))
+			if _, ok := sensitiveKeys[normalized]; ok && child != nil && child != "" {
+				v[key] = redacted
+				continue
+			}
+			v[key] = redact(child)
+		}
+	case []any:
+		for i, child := range v {
+			v[i] = redact(child)
+		}
+	}
+
+	return value
+}
+
+// CSVHeader is the header of the events exported as csv.
+var CSVHeader = []string{"id", "timestamp", "actor_type", "actor_id", "actor_email", "action", "resource_type", "resource_id", "changes", "client_address", "user_agent"}
+
+func (event *GettableEvent) CSVRecord() ([]string, error) {
+	changes, err := json.Marshal(event.Changes)
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.TypeInternal, errors.CodeInternal, "failed to marshal changes")
+	}
+
+	return []string{
+		event.ID.StringValue(),
+		event.Timestamp.UTC().Format(time.RFC3339Nano),
+		event.ActorType.StringValue(),
+		event.ActorID,
+		event.ActorEmail,
+		event.Action.StringValue(),
+		event.ResourceType.StringValue(),
+		event.ResourceID,
+		string(changes),
+		event.ClientAddress,
+		event.UserAgent,
+	}, nil
+}
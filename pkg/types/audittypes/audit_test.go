@@ -0,0 +1,155 @@
+package audittypes
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/SigNoz/signoz/pkg/types/authtypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEvent(t *testing.T) {
+	orgID := valuer.GenerateUUID()
+	resource := NewResource(ResourceTypeChannel, "channel")
+	before := map[string]any{"name": "slack", "slack_configs": []any{map[string]any{"api_url": "https://hooks.slack.com/secret", "channel": "#alerts"}}}
+	after := map[string]any{"name": "slack", "slack_configs": []any{map[string]any{"api_url": "https://hooks.slack.com/secret", "channel": "#incidents"}}}
+
+	testCases := []struct {
+		name       string
+		ctx        context.Context
+		actorType  ActorType
+		actorID    string
+		actorEmail string
+	}{
+		{
+			name:      "System",
+			ctx:       context.Background(),
+			actorType: ActorTypeSystem,
+		},
+		{
+			name:       "User",
+			ctx:        authtypes.NewContextWithClaims(context.Background(), authtypes.Claims{UserID: "user", Email: "jane@example.com"}),
+			actorType:  ActorTypeUser,
+			actorID:    "user",
+			actorEmail: "jane@example.com",
+		},
+		{
+			name:       "APIKey",
+			ctx:        authtypes.NewContextWithClaims(context.Background(), authtypes.Claims{UserID: "user", Email: "jane@example.com", APIKeyID: "key"}),
+			actorType:  ActorTypeAPIKey,
+			actorID:    "key",
+			actorEmail: "jane@example.com",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctx := NewContextWithOrigin(testCase.ctx, Origin{ClientAddress: "10.0.0.1", UserAgent: "curl"})
+			event, err := NewEvent(ctx, orgID, ActionUpdate, resource, before, after)
+			require.NoError(t, err)
+
+			assert.Equal(t, testCase.actorType, event.ActorType)
+			assert.Equal(t, testCase.actorID, event.ActorID)
+			assert.Equal(t, testCase.actorEmail, event.ActorEmail)
+			assert.Equal(t, "10.0.0.1", event.ClientAddress)
+			assert.Equal(t, "curl", event.UserAgent)
+			assert.NotContains(t, event.Before, "hooks.slack.com")
+			assert.NotContains(t, event.After, "hooks.slack.com")
+			assert.Equal(t, []*Change{{Path: "slack_configs[0].channel", Before: "#alerts", After: "#incidents"}}, NewChanges(event.Before, event.After))
+		})
+	}
+}
+
+func TestNewChanges(t *testing.T) {
+	testCases := []struct {
+		name     string
+		before   string
+		after    string
+		expected []*Change
+	}{
+		{
+			name:     "Create",
+			before:   "",
+			after:    `{"title":"hosts","tags":["infra"]}`,
+			expected: []*Change{{Path: "tags[0]", After: "infra"}, {Path: "title", After: "hosts"}},
+		},
+		{
+			name:     "Delete",
+			before:   `{"title":"hosts"}`,
+			after:    "",
+			expected: []*Change{{Path: "title", Before: "hosts"}},
+		},
+		{
+			name:     "Nested",
+			before:   `{"widgets":[{"id":"a","query":{"step":60}}],"locked":false}`,
+			after:    `{"widgets":[{"id":"a","query":{"step":120}},{"id":"b"}],"locked":false}`,
+			expected: []*Change{{Path: "widgets[0].query.step", Before: float64(60), After: float64(120)}, {Path: "widgets[1].id", After: "b"}},
+		},
+		{
+			name:     "EmptyList",
+			before:   `{"tags":["infra"]}`,
+			after:    `{"tags":[]}`,
+			expected: []*Change{{Path: "tags", After: []any{}}, {Path: "tags[0]", Before: "infra"}},
+		},
+		{
+			name:     "Unchanged",
+			before:   `{"title":"hosts"}`,
+			after:    `{"title":"hosts"}`,
+			expected: []*Change{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, NewChanges(testCase.before, testCase.after))
+		})
+	}
+}
+
+func TestNewListParams(t *testing.T) {
+	testCases := []struct {
+		name     string
+		query    string
+		expected *ListParams
+		pass     bool
+	}{
+		{
+			name:     "Default",
+			query:    "",
+			expected: &ListParams{Limit: DefaultLimit},
+			pass:     true,
+		},
+		{
+			name:     "Filters",
+			query:    "actorId=user&action=update&resourceType=dashboard&resourceId=id&limit=10&offset=20",
+			expected: &ListParams{ActorID: "user", Action: "update", ResourceType: "dashboard", ResourceID: "id", Limit: 10, Offset: 20},
+			pass:     true,
+		},
+		{
+			name:  "LimitTooLarge",
+			query: "limit=1001",
+			pass:  false,
+		},
+		{
+			name:  "InvalidStart",
+			query: "start=yesterday",
+			pass:  false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			params, err := NewListParams(httptest.NewRequest("GET", "/api/v1/audit/events?"+testCase.query, nil))
+			if !testCase.pass {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, params)
+		})
+	}
+}
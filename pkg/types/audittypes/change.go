@@ -0,0 +1,90 @@
+package audittypes
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strconv"
+)
+
+// Change is a field which differs between the states before and after a change, nested fields are addressed with
+// dots and list items with their index, e.g. `widgets[0].title`.
+type Change struct {
+	Path   string `json:"path"`
+	Before any    `json:"before"`
+	After  any    `json:"after"`
+}
+
+// NewChanges diffs the snapshots of a resource, fields only present in one of them are reported with a nil value
+// for the other one.
+func NewChanges(before string, after string) []*Change {
+	beforeFields := flatten(before)
+	afterFields := flatten(after)
+
+	paths := make([]string, 0, len(beforeFields)+len(afterFields))
+	for path := range beforeFields {
+		paths = append(paths, path)
+	}
+	for path := range afterFields {
+		if _, ok := beforeFields[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	slices.Sort(paths)
+
+	changes := make([]*Change, 0)
+	for _, path := range paths {
+		beforeValue, afterValue := beforeFields[path], afterFields[path]
+		if reflect.DeepEqual(beforeValue, afterValue) {
+			continue
+		}
+
+		changes = append(changes, &Change{Path: path, Before: beforeValue, After: afterValue})
+	}
+
+	return changes
+}
+
+func flatten(snapshot string) map[string]any {
+	fields := map[string]any{}
+	if snapshot == "" {
+		return fields
+	}
+
+	var value any
+	if err := json.Unmarshal([]byte(snapshot), &value); err != nil {
+		return fields
+	}
+
+	flattenInto(fields, "", value)
+	return fields
+}
+
+func flattenInto(fields map[string]any, prefix string, value any) {
+	switch v := value.(type) {
+	case map[string]any:
+		if len(v) == 0 && prefix != "" {
+			fields[prefix] = v
+			return
+		}
+
+		for key, child := range v {
+			path := key
+			if prefix != "" {
+				path = prefix + "." + key
+			}
+			flattenInto(fields, path, child)
+		}
+	case []any:
+		if len(v) == 0 {
+			fields[prefix] = v
+			return
+		}
+
+		for i, child := range v {
+			flattenInto(fields, prefix+"["+strconv.Itoa(i)+"]", child)
+		}
+	default:
+		fields[prefix] = v
+	}
+}
@@ -1,6 +1,7 @@
 package authtypes
 
 import (
+	"context"
 	"log/slog"
 	"slices"
 
@@ -9,7 +10,18 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
-var _ jwt.ClaimsValidator = (*Claims)(nil)
+var (
+	_ jwt.ClaimsValidator = (*Claims)(nil)
+	_ jwt.ClaimsValidator = (*MFAClaims)(nil)
+	_ jwt.ClaimsValidator = (*ShareClaims)(nil)
+)
+
+const (
+	// audience of the tokens issued between the password and the second factor of a login
+	mfaAudience string = "mfa"
+	// audience of the tokens of the share links, they only grant read access to the shared resource
+	shareAudience string = "share"
+)
 
 type Claims struct {
 	jwt.RegisteredClaims
@@ -17,9 +29,26 @@ type Claims struct {
 	Email  string     `json:"email"`
 	Role   types.Role `json:"role"`
 	OrgID  string     `json:"orgId"`
+	// set when authenticated with an api key, the custom roles of the key apply instead of the ones of the user
+	APIKeyID string `json:"apiKeyId,omitempty"`
+	// server side session the token was issued for, see SessionValidator
+	SessionID string `json:"sid,omitempty"`
+}
+
+// SessionValidator checks that the session a token was issued for has not been revoked.
+type SessionValidator interface {
+	ValidateSession(ctx context.Context, claims Claims) error
 }
 
 func (c *Claims) Validate() error {
+	if slices.Contains(c.Audience, mfaAudience) {
+		return errors.New(errors.TypeUnauthenticated, errors.CodeUnauthenticated, "mfa tokens can't be used for authentication")
+	}
+
+	if slices.Contains(c.Audience, shareAudience) {
+		return errors.New(errors.TypeUnauthenticated, errors.CodeUnauthenticated, "share tokens can't be used for authentication")
+	}
+
 	if c.UserID == "" {
 		return errors.New(errors.TypeUnauthenticated, errors.CodeUnauthenticated, "id is required")
 	}
@@ -37,6 +66,29 @@ func (c *Claims) Validate() error {
 	return nil
 }
 
+// MFAClaims are the claims of a login waiting for the second factor of the user.
+type MFAClaims struct {
+	jwt.RegisteredClaims
+	UserID string `json:"id"`
+	OrgID  string `json:"orgId"`
+}
+
+func (c *MFAClaims) Validate() error {
+	if !slices.Contains(c.Audience, mfaAudience) {
+		return errors.New(errors.TypeUnauthenticated, errors.CodeUnauthenticated, "not an mfa token")
+	}
+
+	if c.UserID == "" {
+		return errors.New(errors.TypeUnauthenticated, errors.CodeUnauthenticated, "id is required")
+	}
+
+	if c.OrgID == "" {
+		return errors.New(errors.TypeUnauthenticated, errors.CodeUnauthenticated, "orgId is required")
+	}
+
+	return nil
+}
+
 func (c *Claims) LogValue() slog.Value {
 	return slog.GroupValue(
 		slog.String("id", c.UserID),
@@ -71,6 +123,16 @@ func (c *Claims) IsAdmin() error {
 	return errors.New(errors.TypeForbidden, errors.CodeForbidden, "only admins can access this resource")
 }
 
+// CanGrant checks that the claims hold at least `role`, users with the permission to manage users or api keys
+// can not hand out a role above their own.
+func (c *Claims) CanGrant(role types.Role) error {
+	if !c.Role.IsLowerThan(role) {
+		return nil
+	}
+
+	return errors.Newf(errors.TypeForbidden, errors.CodeForbidden, "the %s role can not be granted by a %s", role.String(), c.Role.String())
+}
+
 func (c *Claims) IsSelfAccess(id string) error {
 	if c.UserID == id {
 		return nil
@@ -82,3 +144,29 @@ func (c *Claims) IsSelfAccess(id string) error {
 
 	return errors.New(errors.TypeForbidden, errors.CodeForbidden, "only the user/admin can access their own resource")
 }
+
+// ShareClaims are the claims of the token of a share link, the link itself is looked up on every request so that
+// revoking it takes effect immediately.
+type ShareClaims struct {
+	jwt.RegisteredClaims
+	LinkID string `json:"linkId"`
+	OrgID  string `json:"orgId"`
+	// set once the password of a protected link has been verified
+	Unlocked bool `json:"unlocked,omitempty"`
+}
+
+func (c *ShareClaims) Validate() error {
+	if !slices.Contains(c.Audience, shareAudience) {
+		return errors.New(errors.TypeUnauthenticated, errors.CodeUnauthenticated, "not a share token")
+	}
+
+	if c.LinkID == "" {
+		return errors.New(errors.TypeUnauthenticated, errors.CodeUnauthenticated, "linkId is required")
+	}
+
+	if c.OrgID == "" {
+		return errors.New(errors.TypeUnauthenticated, errors.CodeUnauthenticated, "orgId is required")
+	}
+
+	return nil
+}
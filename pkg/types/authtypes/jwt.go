@@ -56,12 +56,7 @@ func (j *JWT) ContextFromRequest(ctx context.Context, values ...string) (context
 
 func (j *JWT) Claims(jwtStr string) (Claims, error) {
 	claims := Claims{}
-	_, err := jwt.ParseWithClaims(jwtStr, &claims, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.Newf(errors.TypeUnauthenticated, errors.CodeUnauthenticated, "unrecognized signing algorithm: %s", token.Method.Alg())
-		}
-		return []byte(j.JwtSecret), nil
-	})
+	_, err := jwt.ParseWithClaims(jwtStr, &claims, j.keyFunc)
 	if err != nil {
 		return Claims{}, errors.Wrapf(err, errors.TypeUnauthenticated, errors.CodeUnauthenticated, "failed to parse jwt token")
 	}
@@ -69,6 +64,35 @@ func (j *JWT) Claims(jwtStr string) (Claims, error) {
 	return claims, nil
 }
 
+// MFAClaims parses a token created with MFAToken, access and refresh tokens are rejected.
+func (j *JWT) MFAClaims(jwtStr string) (MFAClaims, error) {
+	claims := MFAClaims{}
+	_, err := jwt.ParseWithClaims(jwtStr, &claims, j.keyFunc)
+	if err != nil {
+		return MFAClaims{}, errors.Wrapf(err, errors.TypeUnauthenticated, errors.CodeUnauthenticated, "failed to parse mfa token")
+	}
+
+	return claims, nil
+}
+
+// ShareClaims parses a token created with ShareToken, access, refresh and mfa tokens are rejected.
+func (j *JWT) ShareClaims(jwtStr string) (ShareClaims, error) {
+	claims := ShareClaims{}
+	_, err := jwt.ParseWithClaims(jwtStr, &claims, j.keyFunc)
+	if err != nil {
+		return ShareClaims{}, errors.Wrapf(err, errors.TypeUnauthenticated, errors.CodeUnauthenticated, "failed to parse share token")
+	}
+
+	return claims, nil
+}
+
+func (j *JWT) keyFunc(token *jwt.Token) (interface{}, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		return nil, errors.Newf(errors.TypeUnauthenticated, errors.CodeUnauthenticated, "unrecognized signing algorithm: %s", token.Method.Alg())
+	}
+	return []byte(j.JwtSecret), nil
+}
+
 // NewContextWithClaims attaches individual claims to the context.
 func NewContextWithClaims(ctx context.Context, claims Claims) context.Context {
 	ctx = context.WithValue(ctx, jwtClaimsKey{}, claims)
@@ -76,18 +100,19 @@ func NewContextWithClaims(ctx context.Context, claims Claims) context.Context {
 }
 
 // signToken creates and signs a JWT token with the given claims
-func (j *JWT) signToken(claims Claims) (string, error) {
+func (j *JWT) signToken(claims jwt.Claims) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString([]byte(j.JwtSecret))
 }
 
-// AccessToken creates an access token with the provided claims
-func (j *JWT) AccessToken(orgId, userId, email string, role types.Role) (string, Claims, error) {
+// AccessToken creates an access token for the session with the provided claims
+func (j *JWT) AccessToken(orgId, userId, email string, role types.Role, sessionId string) (string, Claims, error) {
 	claims := Claims{
-		UserID: userId,
-		Role:   role,
-		Email:  email,
-		OrgID:  orgId,
+		UserID:    userId,
+		Role:      role,
+		Email:     email,
+		OrgID:     orgId,
+		SessionID: sessionId,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(j.JwtExpiry)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -102,14 +127,17 @@ func (j *JWT) AccessToken(orgId, userId, email string, role types.Role) (string,
 	return token, claims, nil
 }
 
-// RefreshToken creates a refresh token with the provided claims
-func (j *JWT) RefreshToken(orgId, userId, email string, role types.Role) (string, Claims, error) {
+// RefreshToken creates a refresh token for the session with the provided claims, the id of the token is the one the
+// session expects to be exchanged next
+func (j *JWT) RefreshToken(orgId, userId, email string, role types.Role, sessionId string, tokenId string) (string, Claims, error) {
 	claims := Claims{
-		UserID: userId,
-		Role:   role,
-		Email:  email,
-		OrgID:  orgId,
+		UserID:    userId,
+		Role:      role,
+		Email:     email,
+		OrgID:     orgId,
+		SessionID: sessionId,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenId,
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(j.JwtRefresh)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
@@ -123,6 +151,47 @@ func (j *JWT) RefreshToken(orgId, userId, email string, role types.Role) (string
 	return token, claims, nil
 }
 
+// MFAToken creates a short lived token for a user who has verified their password but not their second factor yet
+func (j *JWT) MFAToken(orgId, userId string, expiry time.Duration) (string, MFAClaims, error) {
+	claims := MFAClaims{
+		UserID: userId,
+		OrgID:  orgId,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{mfaAudience},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiry)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	token, err := j.signToken(claims)
+	if err != nil {
+		return "", MFAClaims{}, errors.Wrapf(err, errors.TypeUnauthenticated, errors.CodeUnauthenticated, "failed to sign token")
+	}
+
+	return token, claims, nil
+}
+
+// ShareToken creates the token of a share link, it expires with the link
+func (j *JWT) ShareToken(orgId, linkId string, unlocked bool, expiresAt time.Time) (string, ShareClaims, error) {
+	claims := ShareClaims{
+		LinkID:   linkId,
+		OrgID:    orgId,
+		Unlocked: unlocked,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{shareAudience},
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	token, err := j.signToken(claims)
+	if err != nil {
+		return "", ShareClaims{}, errors.Wrapf(err, errors.TypeUnauthenticated, errors.CodeUnauthenticated, "failed to sign token")
+	}
+
+	return token, claims, nil
+}
+
 func ClaimsFromContext(ctx context.Context) (Claims, error) {
 	claims, ok := ctx.Value(jwtClaimsKey{}).(Claims)
 	if !ok {
@@ -12,18 +12,27 @@ import (
 
 func TestJwtAccessToken(t *testing.T) {
 	jwtService := NewJWT("secret", time.Minute, time.Hour)
-	token, _, err := jwtService.AccessToken("orgId", "userId", "email@example.com", types.RoleAdmin)
+	token, _, err := jwtService.AccessToken("orgId", "userId", "email@example.com", types.RoleAdmin, "sessionId")
 
 	assert.NoError(t, err)
 	assert.NotEmpty(t, token)
+
+	claims, err := jwtService.Claims(token)
+	assert.NoError(t, err)
+	assert.Equal(t, "sessionId", claims.SessionID)
 }
 
 func TestJwtRefreshToken(t *testing.T) {
 	jwtService := NewJWT("secret", time.Minute, time.Hour)
-	token, _, err := jwtService.RefreshToken("orgId", "userId", "email@example.com", types.RoleAdmin)
+	token, _, err := jwtService.RefreshToken("orgId", "userId", "email@example.com", types.RoleAdmin, "sessionId", "tokenId")
 
 	assert.NoError(t, err)
 	assert.NotEmpty(t, token)
+
+	claims, err := jwtService.Claims(token)
+	assert.NoError(t, err)
+	assert.Equal(t, "sessionId", claims.SessionID)
+	assert.Equal(t, "tokenId", claims.ID)
 }
 
 func TestJwtClaims(t *testing.T) {
@@ -208,3 +217,51 @@ func TestParseBearerAuth(t *testing.T) {
 		})
 	}
 }
+
+func TestJwtMFAToken(t *testing.T) {
+	jwtService := NewJWT("secret", time.Minute, time.Hour)
+
+	mfaToken, _, err := jwtService.MFAToken("orgId", "userId", time.Minute)
+	assert.NoError(t, err)
+
+	claims, err := jwtService.MFAClaims(mfaToken)
+	assert.NoError(t, err)
+	assert.Equal(t, "userId", claims.UserID)
+	assert.Equal(t, "orgId", claims.OrgID)
+
+	// the mfa token can't be used as an access token and vice versa
+	_, err = jwtService.Claims(mfaToken)
+	assert.True(t, errors.Ast(err, errors.TypeUnauthenticated))
+
+	accessToken, _, err := jwtService.AccessToken("orgId", "userId", "email@example.com", types.RoleAdmin, "sessionId")
+	assert.NoError(t, err)
+	_, err = jwtService.MFAClaims(accessToken)
+	assert.True(t, errors.Ast(err, errors.TypeUnauthenticated))
+}
+
+func TestJwtShareToken(t *testing.T) {
+	jwtService := NewJWT("secret", time.Minute, time.Hour)
+
+	shareToken, _, err := jwtService.ShareToken("orgId", "linkId", true, time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+
+	claims, err := jwtService.ShareClaims(shareToken)
+	assert.NoError(t, err)
+	assert.Equal(t, "linkId", claims.LinkID)
+	assert.Equal(t, "orgId", claims.OrgID)
+	assert.True(t, claims.Unlocked)
+
+	// the share token can't be used as an access token and vice versa
+	_, err = jwtService.Claims(shareToken)
+	assert.True(t, errors.Ast(err, errors.TypeUnauthenticated))
+
+	mfaToken, _, err := jwtService.MFAToken("orgId", "userId", time.Minute)
+	assert.NoError(t, err)
+	_, err = jwtService.ShareClaims(mfaToken)
+	assert.True(t, errors.Ast(err, errors.TypeUnauthenticated))
+
+	expiredToken, _, err := jwtService.ShareToken("orgId", "linkId", false, time.Now().Add(-time.Minute))
+	assert.NoError(t, err)
+	_, err = jwtService.ShareClaims(expiredToken)
+	assert.True(t, errors.Ast(err, errors.TypeUnauthenticated))
+}
@@ -0,0 +1,157 @@
+package authtypes
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/SigNoz/signoz/pkg/errors"
+	"github.com/SigNoz/signoz/pkg/types"
+	"github.com/SigNoz/signoz/pkg/valuer"
+)
+
+// Permission allows an action on a kind of resource, it is of the form `<resource>:<action>`.
+type Permission struct{ valuer.String }
+
+var (
+	PermissionDashboardsRead     = Permission{valuer.NewString("dashboards:read")}
+	PermissionDashboardsWrite    = Permission{valuer.NewString("dashboards:write")}
+	PermissionDashboardsDelete   = Permission{valuer.NewString("dashboards:delete")}
+	PermissionAlertsRead         = Permission{valuer.NewString("alerts:read")}
+	PermissionAlertsWrite        = Permission{valuer.NewString("alerts:write")}
+	PermissionAlertsDelete       = Permission{valuer.NewString("alerts:delete")}
+	PermissionAlertsSilence      = Permission{valuer.NewString("alerts:silence")}
+	PermissionChannelsRead       = Permission{valuer.NewString("channels:read")}
+	PermissionChannelsWrite      = Permission{valuer.NewString("channels:write")}
+	PermissionChannelsManage     = Permission{valuer.NewString("channels:manage")}
+	PermissionSavedViewsRead     = Permission{valuer.NewString("savedviews:read")}
+	PermissionSavedViewsWrite    = Permission{valuer.NewString("savedviews:write")}
+	PermissionPipelinesRead      = Permission{valuer.NewString("pipelines:read")}
+	PermissionPipelinesDeploy    = Permission{valuer.NewString("pipelines:deploy")}
+	PermissionAgentsManage       = Permission{valuer.NewString("agents:manage")}
+	PermissionAPIKeysManage      = Permission{valuer.NewString("apikeys:manage")}
+	PermissionUsersManage        = Permission{valuer.NewString("users:manage")}
+	PermissionRolesManage        = Permission{valuer.NewString("roles:manage")}
+	PermissionOrgManage          = Permission{valuer.NewString("org:manage")}
+	PermissionAuditRead          = Permission{valuer.NewString("audit:read")}
+	PermissionTeamsManage        = Permission{valuer.NewString("teams:manage")}
+	PermissionProvisionApply     = Permission{valuer.NewString("provisioning:apply")}
+	PermissionShareLinksManage   = Permission{valuer.NewString("sharelinks:manage")}
+	PermissionReportsManage      = Permission{valuer.NewString("reports:manage")}
+	PermissionTelemetryRead      = Permission{valuer.NewString("telemetry:read")}
+	PermissionTelemetryWrite     = Permission{valuer.NewString("telemetry:write")}
+	PermissionIntegrationsRead   = Permission{valuer.NewString("integrations:read")}
+	PermissionIntegrationsManage = Permission{valuer.NewString("integrations:manage")}
+	PermissionFunnelsRead        = Permission{valuer.NewString("funnels:read")}
+	PermissionFunnelsWrite       = Permission{valuer.NewString("funnels:write")}
+	PermissionIngestionManage    = Permission{valuer.NewString("ingestion:manage")}
+)
+
+// permissionRoles maps every permission to the least privileged built-in role granted it, roles
+// higher up get all the permissions of the roles below them.
+var permissionRoles = map[Permission]types.Role{
+	PermissionDashboardsRead:     types.RoleViewer,
+	PermissionDashboardsWrite:    types.RoleEditor,
+	PermissionDashboardsDelete:   types.RoleEditor,
+	PermissionAlertsRead:         types.RoleViewer,
+	PermissionAlertsWrite:        types.RoleEditor,
+	PermissionAlertsDelete:       types.RoleEditor,
+	PermissionAlertsSilence:      types.RoleEditor,
+	PermissionChannelsRead:       types.RoleViewer,
+	PermissionChannelsWrite:      types.RoleEditor,
+	PermissionChannelsManage:     types.RoleAdmin,
+	PermissionSavedViewsRead:     types.RoleViewer,
+	PermissionSavedViewsWrite:    types.RoleEditor,
+	PermissionPipelinesRead:      types.RoleViewer,
+	PermissionPipelinesDeploy:    types.RoleEditor,
+	PermissionAgentsManage:       types.RoleAdmin,
+	PermissionAPIKeysManage:      types.RoleAdmin,
+	PermissionUsersManage:        types.RoleAdmin,
+	PermissionRolesManage:        types.RoleAdmin,
+	PermissionOrgManage:          types.RoleAdmin,
+	PermissionAuditRead:          types.RoleAdmin,
+	PermissionTeamsManage:        types.RoleAdmin,
+	PermissionProvisionApply:     types.RoleAdmin,
+	PermissionShareLinksManage:   types.RoleEditor,
+	PermissionReportsManage:      types.RoleEditor,
+	PermissionTelemetryRead:      types.RoleViewer,
+	PermissionTelemetryWrite:     types.RoleEditor,
+	PermissionIntegrationsRead:   types.RoleViewer,
+	PermissionIntegrationsManage: types.RoleEditor,
+	PermissionFunnelsRead:        types.RoleViewer,
+	PermissionFunnelsWrite:       types.RoleEditor,
+	PermissionIngestionManage:    types.RoleEditor,
+}
+
+// PermissionGetter gets the permissions granted on top of the built-in role of the claims.
+type PermissionGetter interface {
+	GetPermissions(ctx context.Context, claims Claims) ([]Permission, error)
+}
+
+func NewPermission(permission string) (Permission, error) {
+	p := Permission{valuer.NewString(permission)}
+	if _, ok := permissionRoles[p]; !ok {
+		return Permission{}, errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "invalid permission: %s", permission)
+	}
+
+	return p, nil
+}
+
+func (p *Permission) UnmarshalJSON(data []byte) error {
+	var s valuer.String
+	if err := s.UnmarshalJSON(data); err != nil {
+		return err
+	}
+
+	permission, err := NewPermission(s.StringValue())
+	if err != nil {
+		return err
+	}
+
+	*p = permission
+	return nil
+}
+
+// Permissions returns all the permissions sorted by name.
+func Permissions() []Permission {
+	permissions := make([]Permission, 0, len(permissionRoles))
+	for permission := range permissionRoles {
+		permissions = append(permissions, permission)
+	}
+
+	slices.SortFunc(permissions, func(a, b Permission) int {
+		return strings.Compare(a.StringValue(), b.StringValue())
+	})
+	return permissions
+}
+
+// PermissionsOfRole returns the permissions granted to a built-in role sorted by name.
+func PermissionsOfRole(role types.Role) []Permission {
+	permissions := []Permission{}
+	for _, permission := range Permissions() {
+		if roleHasPermission(role, permission) {
+			permissions = append(permissions, permission)
+		}
+	}
+
+	return permissions
+}
+
+// HasPermission checks whether the built-in role of the claims is granted the permission.
+func (c *Claims) HasPermission(permission Permission) error {
+	if roleHasPermission(c.Role, permission) {
+		return nil
+	}
+
+	return errors.Newf(errors.TypeForbidden, errors.CodeForbidden, "the %s permission is required to access this resource", permission.StringValue())
+}
+
+func roleHasPermission(role types.Role, permission Permission) bool {
+	minimum, ok := permissionRoles[permission]
+	if !ok {
+		return false
+	}
+
+	// unknown roles rank below every role
+	return !role.IsLowerThan(minimum)
+}
@@ -0,0 +1,72 @@
+package authtypes
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/SigNoz/signoz/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClaimsHasPermission(t *testing.T) {
+	testCases := []struct {
+		name       string
+		role       types.Role
+		permission Permission
+		pass       bool
+	}{
+		{name: "ViewerRead", role: types.RoleViewer, permission: PermissionDashboardsRead, pass: true},
+		{name: "ViewerWrite", role: types.RoleViewer, permission: PermissionDashboardsWrite, pass: false},
+		{name: "ViewerSilence", role: types.RoleViewer, permission: PermissionAlertsSilence, pass: false},
+		{name: "EditorWrite", role: types.RoleEditor, permission: PermissionDashboardsWrite, pass: true},
+		{name: "EditorSilence", role: types.RoleEditor, permission: PermissionAlertsSilence, pass: true},
+		{name: "EditorAPIKeys", role: types.RoleEditor, permission: PermissionAPIKeysManage, pass: false},
+		{name: "AdminAPIKeys", role: types.RoleAdmin, permission: PermissionAPIKeysManage, pass: true},
+		{name: "AdminRead", role: types.RoleAdmin, permission: PermissionPipelinesRead, pass: true},
+		{name: "ViewerTelemetryRead", role: types.RoleViewer, permission: PermissionTelemetryRead, pass: true},
+		{name: "ViewerIntegrationsManage", role: types.RoleViewer, permission: PermissionIntegrationsManage, pass: false},
+		{name: "UnknownRole", role: types.Role("OWNER"), permission: PermissionDashboardsRead, pass: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			claims := Claims{Role: tc.role}
+			err := claims.HasPermission(tc.permission)
+			if tc.pass {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestPermissionsOfRole(t *testing.T) {
+	viewer := PermissionsOfRole(types.RoleViewer)
+	editor := PermissionsOfRole(types.RoleEditor)
+	admin := PermissionsOfRole(types.RoleAdmin)
+
+	assert.Subset(t, editor, viewer)
+	assert.Subset(t, admin, editor)
+	assert.Equal(t, Permissions(), admin)
+	assert.NotContains(t, viewer, PermissionDashboardsDelete)
+}
+
+func TestPermissionUnmarshalJSON(t *testing.T) {
+	var permissions []Permission
+	require.NoError(t, json.Unmarshal([]byte(`["alerts:silence", "Dashboards:Read"]`), &permissions))
+	assert.Equal(t, []Permission{PermissionAlertsSilence, PermissionDashboardsRead}, permissions)
+
+	assert.Error(t, json.Unmarshal([]byte(`["dashboards:fly"]`), &permissions))
+}
+
+func TestClaimsCanGrant(t *testing.T) {
+	editor := Claims{Role: types.RoleEditor}
+	assert.NoError(t, editor.CanGrant(types.RoleViewer))
+	assert.NoError(t, editor.CanGrant(types.RoleEditor))
+	assert.Error(t, editor.CanGrant(types.RoleAdmin))
+
+	admin := Claims{Role: types.RoleAdmin}
+	assert.NoError(t, admin.CanGrant(types.RoleAdmin))
+}
@@ -0,0 +1,300 @@
+package dashboardtypes
+
+import (
+	"slices"
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/errors"
+	"github.com/SigNoz/signoz/pkg/types"
+	"github.com/SigNoz/signoz/pkg/types/authtypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+	"github.com/uptrace/bun"
+)
+
+// MaxGrants is the number of grants of a dashboard or a folder.
+const MaxGrants = 100
+
+// Permission is what a user can do with a dashboard or a folder, each permission includes the ones before it.
+type Permission struct{ valuer.String }
+
+var (
+	PermissionViewer = Permission{valuer.NewString("viewer")}
+	PermissionEditor = Permission{valuer.NewString("editor")}
+	// PermissionOwner also allows to lock, move and delete the dashboard and to change its grants.
+	PermissionOwner = Permission{valuer.NewString("owner")}
+)
+
+var permissions = []Permission{PermissionViewer, PermissionEditor, PermissionOwner}
+
+func NewPermission(permission string) (Permission, error) {
+	for _, p := range permissions {
+		if p.StringValue() == permission {
+			return p, nil
+		}
+	}
+
+	return Permission{}, errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "invalid permission: %s, must be one of viewer, editor, owner", permission)
+}
+
+func (p *Permission) UnmarshalJSON(data []byte) error {
+	var value valuer.String
+	if err := value.UnmarshalJSON(data); err != nil {
+		return err
+	}
+
+	permission, err := NewPermission(value.StringValue())
+	if err != nil {
+		return err
+	}
+
+	*p = permission
+	return nil
+}
+
+// Includes checks that the permission allows what the other permission allows, the zero permission includes nothing.
+func (p Permission) Includes(other Permission) bool {
+	return slices.Index(permissions, p) >= slices.Index(permissions, other) && slices.Index(permissions, other) >= 0
+}
+
+type SubjectType struct{ valuer.String }
+
+var (
+	SubjectTypeUser = SubjectType{valuer.NewString("user")}
+	SubjectTypeTeam = SubjectType{valuer.NewString("team")}
+)
+
+func NewSubjectType(subjectType string) (SubjectType, error) {
+	switch subjectType {
+	case SubjectTypeUser.StringValue():
+		return SubjectTypeUser, nil
+	case SubjectTypeTeam.StringValue():
+		return SubjectTypeTeam, nil
+	default:
+		return SubjectType{}, errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "invalid subject type: %s, must be one of user, team", subjectType)
+	}
+}
+
+func (s *SubjectType) UnmarshalJSON(data []byte) error {
+	var value valuer.String
+	if err := value.UnmarshalJSON(data); err != nil {
+		return err
+	}
+
+	subjectType, err := NewSubjectType(value.StringValue())
+	if err != nil {
+		return err
+	}
+
+	*s = subjectType
+	return nil
+}
+
+type ResourceType struct{ valuer.String }
+
+var (
+	ResourceTypeDashboard = ResourceType{valuer.NewString("dashboard")}
+	ResourceTypeFolder    = ResourceType{valuer.NewString("folder")}
+)
+
+// Grant gives a permission on a dashboard or a folder to a user or a team. Dashboards and folders without grants are
+// open to every user of the org according to their role, once granted they are only visible to the users they are
+// granted to.
+type Grant struct {
+	bun.BaseModel `bun:"table:dashboard_grant"`
+
+	types.Identifiable
+	types.TimeAuditable
+	OrgID        valuer.UUID  `bun:"org_id,type:text,notnull" json:"-"`
+	ResourceType ResourceType `bun:"resource_type,type:text,notnull,unique:resource_type_resource_id_subject_type_subject_id" json:"resourceType"`
+	ResourceID   valuer.UUID  `bun:"resource_id,type:text,notnull,unique:resource_type_resource_id_subject_type_subject_id" json:"resourceId"`
+	SubjectType  SubjectType  `bun:"subject_type,type:text,notnull,unique:resource_type_resource_id_subject_type_subject_id" json:"subjectType"`
+	SubjectID    valuer.UUID  `bun:"subject_id,type:text,notnull,unique:resource_type_resource_id_subject_type_subject_id" json:"subjectId"`
+	Permission   Permission   `bun:"permission,type:text,notnull" json:"permission"`
+}
+
+type PostableGrant struct {
+	SubjectType SubjectType `json:"subjectType"`
+	SubjectID   valuer.UUID `json:"subjectId"`
+	Permission  Permission  `json:"permission"`
+}
+
+// PostableGrants replaces the grants of a dashboard or a folder, no grants opens it to the whole org again.
+type PostableGrants struct {
+	Grants []*PostableGrant `json:"grants"`
+}
+
+type GettableGrants struct {
+	Grants []*Grant `json:"grants"`
+	// grants of the folders the dashboard or the folder is in
+	Inherited []*Grant `json:"inherited"`
+	// permission of the user on the dashboard or the folder
+	Permission Permission `json:"permission"`
+}
+
+func NewGrants(orgID valuer.UUID, resourceType ResourceType, resourceID valuer.UUID, postable *PostableGrants) ([]*Grant, error) {
+	if err := postable.Validate(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	grants := make([]*Grant, 0, len(postable.Grants))
+	for _, grant := range postable.Grants {
+		grants = append(grants, &Grant{
+			Identifiable: types.Identifiable{
+				ID: valuer.GenerateUUID(),
+			},
+			TimeAuditable: types.TimeAuditable{
+				CreatedAt: now,
+				UpdatedAt: now,
+			},
+			OrgID:        orgID,
+			ResourceType: resourceType,
+			ResourceID:   resourceID,
+			SubjectType:  grant.SubjectType,
+			SubjectID:    grant.SubjectID,
+			Permission:   grant.Permission,
+		})
+	}
+
+	return grants, nil
+}
+
+func NewGettableGrants(grants []*Grant, inherited []*Grant, permission Permission) *GettableGrants {
+	return &GettableGrants{Grants: grants, Inherited: inherited, Permission: permission}
+}
+
+func (p *PostableGrants) Validate() error {
+	if len(p.Grants) > MaxGrants {
+		return errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "at most %d grants are allowed", MaxGrants)
+	}
+
+	subjects := make(map[string]struct{}, len(p.Grants))
+	for _, grant := range p.Grants {
+		if grant == nil {
+			return errors.New(errors.TypeInvalidInput, errors.CodeInvalidInput, "grant is required")
+		}
+
+		if grant.SubjectType.IsZero() {
+			return errors.New(errors.TypeInvalidInput, errors.CodeInvalidInput, "subjectType is required")
+		}
+
+		if grant.SubjectID.IsZero() {
+			return errors.New(errors.TypeInvalidInput, errors.CodeInvalidInput, "subjectId is required")
+		}
+
+		if grant.Permission.IsZero() {
+			return errors.New(errors.TypeInvalidInput, errors.CodeInvalidInput, "permission is required")
+		}
+
+		subject := grant.SubjectType.StringValue() + ":" + grant.SubjectID.StringValue()
+		if _, ok := subjects[subject]; ok {
+			return errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "%s %s is granted more than once", grant.SubjectType.StringValue(), grant.SubjectID.StringValue())
+		}
+		subjects[subject] = struct{}{}
+	}
+
+	return nil
+}
+
+// Access resolves the permissions of a user on dashboards and folders from their grants.
+type Access struct {
+	claims  *authtypes.Claims
+	teamIDs []valuer.UUID
+}
+
+func NewAccess(claims authtypes.Claims, teamIDs []valuer.UUID) *Access {
+	return &Access{claims: &claims, teamIDs: teamIDs}
+}
+
+// DashboardPermission returns the permission of the user on the dashboard in the folders of the path, see
+// NewFolderPath. The grants are filtered down to the ones of the dashboard and of the folders of the path.
+func (access *Access) DashboardPermission(dashboard *Dashboard, path []*Folder, grants []*Grant) Permission {
+	creators := []string{dashboard.CreatedBy}
+	for _, folder := range path {
+		creators = append(creators, folder.CreatedBy)
+	}
+
+	return access.permission(creators, DashboardGrants(dashboard, path, grants))
+}
+
+// FolderPermission returns the permission of the user on the first folder of the path, see NewFolderPath. The grants
+// are filtered down to the ones of the folders of the path.
+func (access *Access) FolderPermission(path []*Folder, grants []*Grant) Permission {
+	creators := make([]string, 0, len(path))
+	for _, folder := range path {
+		creators = append(creators, folder.CreatedBy)
+	}
+
+	return access.permission(creators, PathGrants(path, grants))
+}
+
+// permission returns the permission of the user on a resource created by one of the creators. Admins and creators own
+// the resource, users of the org are editors of resources without grants and their role tells what they can do.
+func (access *Access) permission(creators []string, grants []*Grant) Permission {
+	if access.claims.Role == types.RoleAdmin || slices.Contains(creators, access.claims.Email) {
+		return PermissionOwner
+	}
+
+	if len(grants) == 0 {
+		return PermissionEditor
+	}
+
+	permission := Permission{}
+	for _, grant := range grants {
+		if !access.isSubject(grant) || permission.Includes(grant.Permission) {
+			continue
+		}
+
+		permission = grant.Permission
+	}
+
+	return permission
+}
+
+func (access *Access) isSubject(grant *Grant) bool {
+	switch grant.SubjectType {
+	case SubjectTypeUser:
+		return grant.SubjectID.StringValue() == access.claims.UserID
+	case SubjectTypeTeam:
+		return slices.Contains(access.teamIDs, grant.SubjectID)
+	default:
+		return false
+	}
+}
+
+// ManagePermission returns the permission needed to move or delete a dashboard or a folder with the grants, every
+// editor can move or delete the ones without grants.
+func ManagePermission(grants []*Grant) Permission {
+	if len(grants) == 0 {
+		return PermissionEditor
+	}
+
+	return PermissionOwner
+}
+
+// DashboardGrants returns the grants of the dashboard and of the folders of the path.
+func DashboardGrants(dashboard *Dashboard, path []*Folder, grants []*Grant) []*Grant {
+	return slices.DeleteFunc(slices.Clone(grants), func(grant *Grant) bool {
+		if grant.ResourceType == ResourceTypeDashboard {
+			return grant.ResourceID.StringValue() != dashboard.ID
+		}
+
+		return !isPathGrant(path, grant)
+	})
+}
+
+// PathGrants returns the grants of the folders of the path.
+func PathGrants(path []*Folder, grants []*Grant) []*Grant {
+	return slices.DeleteFunc(slices.Clone(grants), func(grant *Grant) bool {
+		return !isPathGrant(path, grant)
+	})
+}
+
+// FolderGrants returns the grants of the folder.
+func FolderGrants(folder *Folder, grants []*Grant) []*Grant {
+	return PathGrants([]*Folder{folder}, grants)
+}
+
+func isPathGrant(path []*Folder, grant *Grant) bool {
+	return grant.ResourceType == ResourceTypeFolder && slices.ContainsFunc(path, func(folder *Folder) bool { return folder.ID == grant.ResourceID })
+}
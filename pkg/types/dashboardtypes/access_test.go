@@ -0,0 +1,117 @@
+package dashboardtypes
+
+import (
+	"testing"
+
+	"github.com/SigNoz/signoz/pkg/types"
+	"github.com/SigNoz/signoz/pkg/types/authtypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccessDashboardPermission(t *testing.T) {
+	userID, teamID := valuer.GenerateUUID(), valuer.GenerateUUID()
+	parent := &Folder{Identifiable: types.Identifiable{ID: valuer.GenerateUUID()}, UserAuditable: types.UserAuditable{CreatedBy: "ops@example.com"}}
+	folder := &Folder{Identifiable: types.Identifiable{ID: valuer.GenerateUUID()}, UserAuditable: types.UserAuditable{CreatedBy: "finance@example.com"}, ParentID: parent.ID.StringValue()}
+	path := []*Folder{folder, parent}
+	dashboard := &Dashboard{ID: valuer.GenerateUUID().StringValue(), UserAuditable: types.UserAuditable{CreatedBy: "jane@example.com"}}
+	other := &Dashboard{ID: valuer.GenerateUUID().StringValue(), UserAuditable: types.UserAuditable{CreatedBy: "jane@example.com"}}
+
+	grant := func(resourceType ResourceType, resourceID string, subjectType SubjectType, subjectID valuer.UUID, permission Permission) *Grant {
+		return &Grant{ResourceType: resourceType, ResourceID: valuer.MustNewUUID(resourceID), SubjectType: subjectType, SubjectID: subjectID, Permission: permission}
+	}
+
+	claims := authtypes.Claims{UserID: userID.StringValue(), Email: "john@example.com", Role: types.RoleEditor}
+	access := NewAccess(claims, []valuer.UUID{teamID})
+
+	testCases := []struct {
+		name       string
+		access     *Access
+		path       []*Folder
+		grants     []*Grant
+		permission Permission
+	}{
+		{
+			name:       "Open",
+			access:     access,
+			grants:     []*Grant{grant(ResourceTypeDashboard, other.ID, SubjectTypeUser, valuer.GenerateUUID(), PermissionViewer)},
+			permission: PermissionEditor,
+		},
+		{
+			name:       "Hidden",
+			access:     access,
+			grants:     []*Grant{grant(ResourceTypeDashboard, dashboard.ID, SubjectTypeUser, valuer.GenerateUUID(), PermissionOwner)},
+			permission: Permission{},
+		},
+		{
+			name:   "Highest",
+			access: access,
+			grants: []*Grant{
+				grant(ResourceTypeDashboard, dashboard.ID, SubjectTypeUser, userID, PermissionViewer),
+				grant(ResourceTypeDashboard, dashboard.ID, SubjectTypeTeam, teamID, PermissionEditor),
+			},
+			permission: PermissionEditor,
+		},
+		{
+			name:       "Inherited",
+			access:     access,
+			path:       path,
+			grants:     []*Grant{grant(ResourceTypeFolder, folder.ID.StringValue(), SubjectTypeTeam, teamID, PermissionViewer)},
+			permission: PermissionViewer,
+		},
+		{
+			name:       "HiddenByFolder",
+			access:     access,
+			path:       path,
+			grants:     []*Grant{grant(ResourceTypeFolder, folder.ID.StringValue(), SubjectTypeTeam, valuer.GenerateUUID(), PermissionViewer)},
+			permission: Permission{},
+		},
+		{
+			name:       "InheritedFromParent",
+			access:     access,
+			path:       path,
+			grants:     []*Grant{grant(ResourceTypeFolder, parent.ID.StringValue(), SubjectTypeUser, userID, PermissionEditor)},
+			permission: PermissionEditor,
+		},
+		{
+			name:       "ParentCreator",
+			access:     NewAccess(authtypes.Claims{UserID: valuer.GenerateUUID().StringValue(), Email: "ops@example.com", Role: types.RoleViewer}, nil),
+			path:       path,
+			grants:     []*Grant{grant(ResourceTypeFolder, folder.ID.StringValue(), SubjectTypeUser, userID, PermissionViewer)},
+			permission: PermissionOwner,
+		},
+		{
+			name:       "Creator",
+			access:     NewAccess(authtypes.Claims{UserID: valuer.GenerateUUID().StringValue(), Email: "jane@example.com", Role: types.RoleViewer}, nil),
+			grants:     []*Grant{grant(ResourceTypeDashboard, dashboard.ID, SubjectTypeUser, userID, PermissionViewer)},
+			permission: PermissionOwner,
+		},
+		{
+			name:       "FolderCreator",
+			access:     NewAccess(authtypes.Claims{UserID: valuer.GenerateUUID().StringValue(), Email: "finance@example.com", Role: types.RoleEditor}, nil),
+			path:       path,
+			grants:     []*Grant{grant(ResourceTypeDashboard, dashboard.ID, SubjectTypeUser, userID, PermissionViewer)},
+			permission: PermissionOwner,
+		},
+		{
+			name:       "Admin",
+			access:     NewAccess(authtypes.Claims{UserID: valuer.GenerateUUID().StringValue(), Email: "admin@example.com", Role: types.RoleAdmin}, nil),
+			grants:     []*Grant{grant(ResourceTypeDashboard, dashboard.ID, SubjectTypeUser, userID, PermissionViewer)},
+			permission: PermissionOwner,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.permission, testCase.access.DashboardPermission(dashboard, testCase.path, testCase.grants))
+		})
+	}
+}
+
+func TestPermissionIncludes(t *testing.T) {
+	assert.True(t, PermissionOwner.Includes(PermissionViewer))
+	assert.True(t, PermissionEditor.Includes(PermissionEditor))
+	assert.False(t, PermissionViewer.Includes(PermissionEditor))
+	assert.False(t, Permission{}.Includes(PermissionViewer))
+	assert.False(t, PermissionOwner.Includes(Permission{}))
+}
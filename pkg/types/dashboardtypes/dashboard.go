@@ -7,7 +7,6 @@ import (
 
 	"github.com/SigNoz/signoz/pkg/errors"
 	"github.com/SigNoz/signoz/pkg/types"
-	"github.com/SigNoz/signoz/pkg/types/authtypes"
 	"github.com/SigNoz/signoz/pkg/valuer"
 	"github.com/uptrace/bun"
 )
@@ -21,16 +20,21 @@ type StorableDashboard struct {
 	Data   StorableDashboardData `bun:"data,type:text,notnull"`
 	Locked bool                  `bun:"locked,notnull,default:false"`
 	OrgID  valuer.UUID           `bun:"org_id,notnull"`
+	// empty for the dashboards out of any folder
+	FolderID string `bun:"folder_id,type:text"`
 }
 
 type Dashboard struct {
 	types.TimeAuditable
 	types.UserAuditable
 
-	ID     string                `json:"id"`
-	Data   StorableDashboardData `json:"data"`
-	Locked bool                  `json:"locked"`
-	OrgID  valuer.UUID           `json:"org_id"`
+	ID       string                `json:"id"`
+	Data     StorableDashboardData `json:"data"`
+	Locked   bool                  `json:"locked"`
+	OrgID    valuer.UUID           `json:"org_id"`
+	FolderID string                `json:"folder_id,omitempty"`
+	// violations of the schema tolerated by the last update, see Update
+	Warnings []string `json:"warnings,omitempty"`
 }
 
 type LockUnlockDashboard struct {
@@ -67,13 +71,23 @@ func NewStorableDashboardFromDashboard(dashboard *Dashboard) (*StorableDashboard
 			CreatedBy: dashboard.CreatedBy,
 			UpdatedBy: dashboard.UpdatedBy,
 		},
-		OrgID:  dashboard.OrgID,
-		Data:   dashboard.Data,
-		Locked: dashboard.Locked,
+		OrgID:    dashboard.OrgID,
+		Data:     dashboard.Data,
+		Locked:   dashboard.Locked,
+		FolderID: dashboard.FolderID,
 	}, nil
 }
 
 func NewDashboard(orgID valuer.UUID, createdBy string, storableDashboardData StorableDashboardData) (*Dashboard, error) {
+	data, err := storableDashboardData.Upgrade(MinSchemaVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := data.Validate(); err != nil {
+		return nil, err
+	}
+
 	currentTime := time.Now()
 
 	return &Dashboard{
@@ -87,7 +101,7 @@ func NewDashboard(orgID valuer.UUID, createdBy string, storableDashboardData Sto
 			UpdatedBy: createdBy,
 		},
 		OrgID:  orgID,
-		Data:   storableDashboardData,
+		Data:   data,
 		Locked: false,
 	}, nil
 }
@@ -103,9 +117,10 @@ func NewDashboardFromStorableDashboard(storableDashboard *StorableDashboard) (*D
 			CreatedBy: storableDashboard.CreatedBy,
 			UpdatedBy: storableDashboard.UpdatedBy,
 		},
-		OrgID:  storableDashboard.OrgID,
-		Data:   storableDashboard.Data,
-		Locked: storableDashboard.Locked,
+		OrgID:    storableDashboard.OrgID,
+		Data:     storableDashboard.Data,
+		Locked:   storableDashboard.Locked,
+		FolderID: storableDashboard.FolderID,
 	}, nil
 }
 
@@ -142,6 +157,7 @@ func NewGettableDashboardFromDashboard(dashboard *Dashboard) (*GettableDashboard
 		OrgID:         dashboard.OrgID,
 		Data:          dashboard.Data,
 		Locked:        dashboard.Locked,
+		FolderID:      dashboard.FolderID,
 	}, nil
 }
 
@@ -260,39 +276,88 @@ func (dashboard *Dashboard) CanUpdate(data StorableDashboardData) error {
 }
 
 func (dashboard *Dashboard) Update(updatableDashboard UpdatableDashboard, updatedBy string) error {
-	err := dashboard.CanUpdate(updatableDashboard)
+	data, err := updatableDashboard.Upgrade(MinSchemaVersion)
+	if err != nil {
+		return err
+	}
+
+	if err := dashboard.validateUpdate(data); err != nil {
+		return err
+	}
+
+	err = dashboard.CanUpdate(data)
 	if err != nil {
 		return err
 	}
 	dashboard.UpdatedBy = updatedBy
 	dashboard.UpdatedAt = time.Now()
-	dashboard.Data = updatableDashboard
+	dashboard.Data = data
 	return nil
 }
 
-func (dashboard *Dashboard) CanLockUnlock(ctx context.Context, updatedBy string) error {
-	claims, err := authtypes.ClaimsFromContext(ctx)
-	if err != nil {
+// validateUpdate validates the data the dashboard is updated with. Dashboards stored before the schema was enforced can
+// violate it, the violations of their updates are reported as warnings until the stored data is valid so that they can
+// still be edited and fixed.
+func (dashboard *Dashboard) validateUpdate(data StorableDashboardData) error {
+	err := data.Validate()
+	if err == nil {
+		return nil
+	}
+
+	if dashboard.Data.Validate() == nil {
 		return err
 	}
 
-	if dashboard.CreatedBy != updatedBy && claims.Role != types.RoleAdmin {
-		return errors.Newf(errors.TypeForbidden, errors.CodeForbidden, "you are not authorized to lock/unlock this dashboard")
+	_, _, message, _, _, _ := errors.Unwrapb(err)
+	dashboard.Warnings = append(dashboard.Warnings, message)
+	return nil
+}
+
+// Restore replaces the data of the dashboard with the data of one of its revisions, any number of panels can be
+// deleted by a restore.
+func (dashboard *Dashboard) Restore(revision *StorableRevision, updatedBy string) error {
+	if dashboard.Locked {
+		return errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "cannot restore a locked dashboard, please unlock the dashboard to restore it")
 	}
+
+	dashboard.UpdatedBy = updatedBy
+	dashboard.UpdatedAt = time.Now()
+	dashboard.Data = revision.Data
 	return nil
 }
 
-func (dashboard *Dashboard) LockUnlock(ctx context.Context, lock bool, updatedBy string) error {
-	err := dashboard.CanLockUnlock(ctx, updatedBy)
+// Replace replaces the data of the dashboard whatever its lock and the panels it deletes, it is meant for the
+// dashboards managed by bundles that are replaced as a whole.
+func (dashboard *Dashboard) Replace(updatableDashboard UpdatableDashboard, updatedBy string) error {
+	data, err := updatableDashboard.Upgrade(MinSchemaVersion)
 	if err != nil {
 		return err
 	}
-	dashboard.Locked = lock
+
+	if err := data.Validate(); err != nil {
+		return err
+	}
+
 	dashboard.UpdatedBy = updatedBy
 	dashboard.UpdatedAt = time.Now()
+	dashboard.Data = data
 	return nil
 }
 
+// LockUnlock locks or unlocks the dashboard, only its owners are allowed to.
+func (dashboard *Dashboard) LockUnlock(lock bool, updatedBy string) {
+	dashboard.Locked = lock
+	dashboard.UpdatedBy = updatedBy
+	dashboard.UpdatedAt = time.Now()
+}
+
+// Move moves the dashboard to the folder, an empty folder id moves it out of its folder.
+func (dashboard *Dashboard) Move(folderID string, updatedBy string) {
+	dashboard.FolderID = folderID
+	dashboard.UpdatedBy = updatedBy
+	dashboard.UpdatedAt = time.Now()
+}
+
 func (lockUnlockDashboard *LockUnlockDashboard) UnmarshalJSON(src []byte) error {
 	var lockUnlock struct {
 		Locked *bool `json:"lock"`
@@ -320,5 +385,39 @@ type Store interface {
 
 	Update(context.Context, valuer.UUID, *StorableDashboard) error
 
+	// Delete deletes the dashboard along with its revisions
 	Delete(context.Context, valuer.UUID, valuer.UUID) error
+
+	// CreateRevision stores the revision and prunes the revisions of the dashboard past the retention
+	CreateRevision(context.Context, *StorableRevision, int) error
+
+	GetRevision(context.Context, valuer.UUID, valuer.UUID, int) (*StorableRevision, error)
+
+	// ListRevisions lists the revisions of the dashboard, the latest first
+	ListRevisions(context.Context, valuer.UUID, valuer.UUID) ([]*StorableRevision, error)
+
+	// GetLatestVersion gets the version of the latest revision of the dashboard, zero if it has none
+	GetLatestVersion(context.Context, valuer.UUID, valuer.UUID) (int, error)
+
+	CreateFolder(context.Context, *Folder) error
+
+	GetFolder(context.Context, valuer.UUID, valuer.UUID) (*Folder, error)
+
+	ListFolders(context.Context, valuer.UUID) ([]*Folder, error)
+
+	UpdateFolder(context.Context, *Folder) error
+
+	// DeleteFolder deletes the folder along with its grants, folders with dashboards cannot be deleted
+	DeleteFolder(context.Context, valuer.UUID, valuer.UUID) error
+
+	// ListGrants lists the grants of every dashboard and folder of the org
+	ListGrants(context.Context, valuer.UUID) ([]*Grant, error)
+
+	// SetGrants replaces the grants of the dashboard or the folder
+	SetGrants(context.Context, valuer.UUID, ResourceType, valuer.UUID, []*Grant) error
+
+	// ListTeamIDs lists the ids of the teams of the user
+	ListTeamIDs(context.Context, valuer.UUID) ([]valuer.UUID, error)
+
+	RunInTx(context.Context, func(context.Context) error) error
 }
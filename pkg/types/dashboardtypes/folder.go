@@ -0,0 +1,176 @@
+package dashboardtypes
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/errors"
+	"github.com/SigNoz/signoz/pkg/types"
+	"github.com/SigNoz/signoz/pkg/valuer"
+	"github.com/uptrace/bun"
+)
+
+var (
+	ErrCodeFolderNotFound      = errors.MustNewCode("dashboard_folder_not_found")
+	ErrCodeFolderAlreadyExists = errors.MustNewCode("dashboard_folder_already_exists")
+	ErrCodeFolderNotEmpty      = errors.MustNewCode("dashboard_folder_not_empty")
+)
+
+const (
+	// MaxFolderDepth is the number of levels of nested folders.
+	MaxFolderDepth = 8
+
+	maxFolderNameLength = 128
+)
+
+// Folder groups dashboards and other folders, the dashboards and the folders of a folder inherit its grants. The names
+// of the folders are unique in the org.
+type Folder struct {
+	bun.BaseModel `bun:"table:dashboard_folder"`
+
+	types.Identifiable
+	types.TimeAuditable
+	types.UserAuditable
+	OrgID valuer.UUID `bun:"org_id,type:text,notnull,unique:org_id_name" json:"orgId"`
+	Name  string      `bun:"name,type:text,notnull,unique:org_id_name" json:"name"`
+	// empty for the folders at the root
+	ParentID string `bun:"parent_id,type:text" json:"parentId,omitempty"`
+}
+
+type GettableFolder struct {
+	*Folder
+	// permission of the user on the folder
+	Permission Permission `json:"permission"`
+}
+
+type PostableFolder struct {
+	Name     string `json:"name"`
+	ParentID string `json:"parentId"`
+}
+
+type UpdatableFolder = PostableFolder
+
+// MovableDashboard moves a dashboard to a folder, an empty folder id moves it out of its folder.
+type MovableDashboard struct {
+	FolderID string `json:"folderId"`
+}
+
+func NewFolder(orgID valuer.UUID, createdBy string, postable *PostableFolder) (*Folder, error) {
+	if err := postable.Validate(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return &Folder{
+		Identifiable: types.Identifiable{
+			ID: valuer.GenerateUUID(),
+		},
+		TimeAuditable: types.TimeAuditable{
+			CreatedAt: now,
+			UpdatedAt: now,
+		},
+		UserAuditable: types.UserAuditable{
+			CreatedBy: createdBy,
+			UpdatedBy: createdBy,
+		},
+		OrgID:    orgID,
+		Name:     postable.Name,
+		ParentID: postable.ParentID,
+	}, nil
+}
+
+func NewGettableFolder(folder *Folder, permission Permission) *GettableFolder {
+	return &GettableFolder{Folder: folder, Permission: permission}
+}
+
+func (folder *Folder) Update(updatedBy string, updatable *UpdatableFolder) error {
+	if err := updatable.Validate(); err != nil {
+		return err
+	}
+
+	folder.Name = updatable.Name
+	folder.ParentID = updatable.ParentID
+	folder.UpdatedBy = updatedBy
+	folder.UpdatedAt = time.Now()
+	return nil
+}
+
+func (p *PostableFolder) Validate() error {
+	p.Name = strings.TrimSpace(p.Name)
+	if p.Name == "" {
+		return errors.New(errors.TypeInvalidInput, errors.CodeInvalidInput, "name is required")
+	}
+
+	if len(p.Name) > maxFolderNameLength {
+		return errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "name must be at most %d characters", maxFolderNameLength)
+	}
+
+	if p.ParentID != "" {
+		if _, err := valuer.NewUUID(p.ParentID); err != nil {
+			return errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "parentId is not a valid uuid")
+		}
+	}
+
+	return nil
+}
+
+// NewFolderPath returns the folder of the id followed by its ancestors up to the root, an empty path for an empty id.
+func NewFolderPath(folders []*Folder, id string) []*Folder {
+	foldersByID := make(map[string]*Folder, len(folders))
+	for _, folder := range folders {
+		foldersByID[folder.ID.StringValue()] = folder
+	}
+
+	path := make([]*Folder, 0)
+	for id != "" && len(path) <= MaxFolderDepth {
+		folder, ok := foldersByID[id]
+		if !ok || slices.Contains(path, folder) {
+			break
+		}
+
+		path = append(path, folder)
+		id = folder.ParentID
+	}
+
+	return path
+}
+
+// ValidateParent checks that the folder can be moved to the folders of the parent path without nesting folders past
+// MaxFolderDepth or in themselves. The depth is the number of levels of the folder and of its descendants.
+func (folder *Folder) ValidateParent(parentPath []*Folder, depth int) error {
+	if slices.ContainsFunc(parentPath, func(parent *Folder) bool { return parent.ID == folder.ID }) {
+		return errors.New(errors.TypeInvalidInput, errors.CodeInvalidInput, "a folder cannot be moved in itself")
+	}
+
+	if len(parentPath)+depth > MaxFolderDepth {
+		return errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "folders can be nested %d levels deep at most", MaxFolderDepth)
+	}
+
+	return nil
+}
+
+// Depth returns the number of levels of the folder and of its descendants.
+func (folder *Folder) Depth(folders []*Folder) int {
+	depth := 0
+	for _, other := range folders {
+		path := NewFolderPath(folders, other.ID.StringValue())
+		if index := slices.IndexFunc(path, func(ancestor *Folder) bool { return ancestor.ID == folder.ID }); index >= 0 {
+			depth = max(depth, index+1)
+		}
+	}
+
+	return depth
+}
+
+func (m *MovableDashboard) Validate() error {
+	if m.FolderID == "" {
+		return nil
+	}
+
+	if _, err := valuer.NewUUID(m.FolderID); err != nil {
+		return errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "folderId is not a valid uuid")
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package dashboardtypes
+
+import (
+	"testing"
+
+	"github.com/SigNoz/signoz/pkg/types"
+	"github.com/SigNoz/signoz/pkg/valuer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewFolderPath(t *testing.T) {
+	root := &Folder{Identifiable: types.Identifiable{ID: valuer.GenerateUUID()}}
+	child := &Folder{Identifiable: types.Identifiable{ID: valuer.GenerateUUID()}, ParentID: root.ID.StringValue()}
+	grandchild := &Folder{Identifiable: types.Identifiable{ID: valuer.GenerateUUID()}, ParentID: child.ID.StringValue()}
+	folders := []*Folder{root, child, grandchild}
+
+	assert.Equal(t, []*Folder{grandchild, child, root}, NewFolderPath(folders, grandchild.ID.StringValue()))
+	assert.Equal(t, []*Folder{root}, NewFolderPath(folders, root.ID.StringValue()))
+	assert.Empty(t, NewFolderPath(folders, ""))
+	assert.Empty(t, NewFolderPath(folders, valuer.GenerateUUID().StringValue()))
+
+	assert.Equal(t, 3, root.Depth(folders))
+	assert.Equal(t, 1, grandchild.Depth(folders))
+
+	// a cycle stops at the first folder seen twice
+	root.ParentID = grandchild.ID.StringValue()
+	assert.Equal(t, []*Folder{grandchild, child, root}, NewFolderPath(folders, grandchild.ID.StringValue()))
+}
+
+func TestFolderValidateParent(t *testing.T) {
+	folders := make([]*Folder, 0, MaxFolderDepth)
+	for i := 0; i < MaxFolderDepth; i++ {
+		folder := &Folder{Identifiable: types.Identifiable{ID: valuer.GenerateUUID()}}
+		if i > 0 {
+			folder.ParentID = folders[i-1].ID.StringValue()
+		}
+		folders = append(folders, folder)
+	}
+
+	deepest := NewFolderPath(folders, folders[MaxFolderDepth-1].ID.StringValue())
+	shallow := NewFolderPath(folders, folders[1].ID.StringValue())
+	folder := &Folder{Identifiable: types.Identifiable{ID: valuer.GenerateUUID()}}
+
+	assert.NoError(t, folder.ValidateParent(shallow, 1))
+	assert.Error(t, folder.ValidateParent(deepest, 1))
+	assert.Error(t, folders[0].ValidateParent(shallow, folders[0].Depth(folders)))
+}
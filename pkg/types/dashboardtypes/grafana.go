@@ -0,0 +1,708 @@
+package dashboardtypes
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/SigNoz/signoz/pkg/errors"
+	"github.com/SigNoz/signoz/pkg/valuer"
+)
+
+// the grid of grafana is 24 columns wide with rows of 30px, the grid of signoz is 12 columns wide with rows of 45px
+const (
+	grafanaColumns    = 24
+	grafanaRowHeight  = 30
+	columns           = 12
+	rowHeight         = 45
+	defaultPanelWidth = 6
+)
+
+var grafanaPanelTypes = map[string]PanelType{
+	"graph":                  PanelTypeGraph,
+	"timeseries":             PanelTypeGraph,
+	"stat":                   PanelTypeValue,
+	"singlestat":             PanelTypeValue,
+	"gauge":                  PanelTypeValue,
+	"table":                  PanelTypeTable,
+	"table-old":              PanelTypeTable,
+	"barchart":               PanelTypeBar,
+	"bargauge":               PanelTypeBar,
+	"piechart":               PanelTypePie,
+	"grafana-piechart-panel": PanelTypePie,
+	"histogram":              PanelTypeHistogram,
+	"row":                    PanelTypeRow,
+}
+
+// the named colors of grafana, other colors are css colors understood as they are
+var grafanaColors = map[string]string{
+	"green":  "#73BF69",
+	"red":    "#F2495C",
+	"orange": "#FF9830",
+	"yellow": "#FADE2A",
+	"blue":   "#5794F2",
+	"purple": "#B877D9",
+}
+
+// the global variables of grafana with no equivalent in signoz are replaced with fixed durations
+var grafanaIntervals = map[string]string{
+	"__rate_interval": "5m",
+	"__interval":      "1m",
+	"__range":         "1h",
+}
+
+var (
+	// variables are referenced as $name, ${name}, ${name:format} or [[name]]
+	grafanaVariableRegex = regexp.MustCompile(`\$\{(\w+)(?::\w+)?\}|\[\[(\w+)(?::\w+)?\]\]|\$(\w+)`)
+	// label_values(label) or label_values(metric, label), the metric may have a selector
+	grafanaLabelValuesRegex = regexp.MustCompile(`^\s*label_values\(\s*(?:([a-zA-Z_:][a-zA-Z0-9_:]*)(\{[^}]*\})?\s*,\s*)?([a-zA-Z_][a-zA-Z0-9_]*)\s*\)\s*$`)
+	queryNameRegex          = regexp.MustCompile(`^[A-Z]$`)
+)
+
+// GrafanaDashboard is the json model of a grafana dashboard, the panels of old dashboards are nested in rows.
+type GrafanaDashboard struct {
+	Title       string          `json:"title"`
+	Description string          `json:"description"`
+	Tags        []string        `json:"tags"`
+	Panels      []*GrafanaPanel `json:"panels"`
+	Rows        []*GrafanaRow   `json:"rows"`
+	Templating  struct {
+		List []*GrafanaVariable `json:"list"`
+	} `json:"templating"`
+}
+
+type GrafanaRow struct {
+	Title     string          `json:"title"`
+	ShowTitle bool            `json:"showTitle"`
+	Height    json.RawMessage `json:"height"`
+	Panels    []*GrafanaPanel `json:"panels"`
+}
+
+type GrafanaPanel struct {
+	ID          int                 `json:"id"`
+	Type        string              `json:"type"`
+	Title       string              `json:"title"`
+	Description string              `json:"description"`
+	Datasource  json.RawMessage     `json:"datasource"`
+	GridPos     *GrafanaGridPos     `json:"gridPos"`
+	Span        float64             `json:"span"`
+	Targets     []*GrafanaTarget    `json:"targets"`
+	FieldConfig *GrafanaFieldConfig `json:"fieldConfig"`
+	Format      string              `json:"format"`
+	YAxes       []*GrafanaYAxis     `json:"yaxes"`
+	Thresholds  json.RawMessage     `json:"thresholds"`
+	Repeat      string              `json:"repeat"`
+	Panels      []*GrafanaPanel     `json:"panels"`
+}
+
+type GrafanaGridPos struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+	W int `json:"w"`
+	H int `json:"h"`
+}
+
+type GrafanaTarget struct {
+	RefID        string          `json:"refId"`
+	Datasource   json.RawMessage `json:"datasource"`
+	Expr         string          `json:"expr"`
+	LegendFormat string          `json:"legendFormat"`
+	Hide         bool            `json:"hide"`
+}
+
+type GrafanaFieldConfig struct {
+	Defaults struct {
+		Unit       string `json:"unit"`
+		Thresholds *struct {
+			Mode  string `json:"mode"`
+			Steps []*struct {
+				Color string   `json:"color"`
+				Value *float64 `json:"value"`
+			} `json:"steps"`
+		} `json:"thresholds"`
+	} `json:"defaults"`
+}
+
+type GrafanaYAxis struct {
+	Format string `json:"format"`
+}
+
+// grafanaGraphThreshold is a threshold of the legacy graph panel.
+type grafanaGraphThreshold struct {
+	Value *float64 `json:"value"`
+	Op    string   `json:"op"`
+	Color string   `json:"fillColor"`
+}
+
+type GrafanaVariable struct {
+	Name        string          `json:"name"`
+	Label       string          `json:"label"`
+	Description string          `json:"description"`
+	Type        string          `json:"type"`
+	Query       json.RawMessage `json:"query"`
+	Definition  string          `json:"definition"`
+	Multi       bool            `json:"multi"`
+	IncludeAll  bool            `json:"includeAll"`
+	Sort        int             `json:"sort"`
+}
+
+type GrafanaImportIssue struct {
+	// title of the panel or name of the variable
+	Title  string `json:"title"`
+	Reason string `json:"reason"`
+}
+
+// GrafanaImportReport reports the panels and variables that could not be translated, the skipped ones are left out of
+// the dashboard and the ones with warnings are partially translated.
+type GrafanaImportReport struct {
+	Panels     int                   `json:"panels"`
+	Translated int                   `json:"translated"`
+	Skipped    []*GrafanaImportIssue `json:"skipped"`
+	Warnings   []*GrafanaImportIssue `json:"warnings"`
+}
+
+type GettableGrafanaImport struct {
+	Dashboard *GettableDashboard   `json:"dashboard"`
+	Report    *GrafanaImportReport `json:"report"`
+}
+
+// NewGrafanaDashboard decodes the json model of a grafana dashboard, the model may be wrapped in the `dashboard` key as
+// returned by the api of grafana.
+func NewGrafanaDashboard(raw []byte) (*GrafanaDashboard, error) {
+	var wrapper struct {
+		Dashboard json.RawMessage `json:"dashboard"`
+	}
+	if err := json.Unmarshal(raw, &wrapper); err != nil {
+		return nil, errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "invalid grafana dashboard: %s", err.Error())
+	}
+
+	if len(wrapper.Dashboard) > 0 && wrapper.Dashboard[0] == '{' {
+		raw = wrapper.Dashboard
+	}
+
+	grafana := new(GrafanaDashboard)
+	if err := json.Unmarshal(raw, grafana); err != nil {
+		return nil, errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "invalid grafana dashboard: %s", err.Error())
+	}
+
+	if strings.TrimSpace(grafana.Title) == "" {
+		return nil, errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "invalid grafana dashboard: title is required")
+	}
+
+	return grafana, nil
+}
+
+// Translate translates the dashboard into the data of a signoz dashboard, the panels and variables that cannot be
+// translated are reported instead of failing the translation.
+func (grafana *GrafanaDashboard) Translate() (StorableDashboardData, *GrafanaImportReport) {
+	report := &GrafanaImportReport{Skipped: []*GrafanaImportIssue{}, Warnings: []*GrafanaImportIssue{}}
+
+	variables := map[string]any{}
+	names := map[string]bool{}
+	for i, variable := range grafana.Templating.List {
+		if variable == nil || variable.Name == "" {
+			continue
+		}
+
+		translated, warning, ok := variable.translate(i)
+		if !ok {
+			report.Skipped = append(report.Skipped, &GrafanaImportIssue{Title: variable.Name, Reason: warning})
+			continue
+		}
+
+		if warning != "" {
+			report.Warnings = append(report.Warnings, &GrafanaImportIssue{Title: variable.Name, Reason: warning})
+		}
+
+		variables[translated["id"].(string)] = translated
+		names[variable.Name] = true
+	}
+
+	widgets := []any{}
+	layout := []any{}
+	for _, panel := range grafana.panels() {
+		if panel.Type != "row" {
+			report.Panels++
+		}
+
+		widget, warnings, reason := panel.translate(names)
+		if widget == nil {
+			report.Skipped = append(report.Skipped, &GrafanaImportIssue{Title: panel.Title, Reason: reason})
+			continue
+		}
+
+		for _, warning := range warnings {
+			report.Warnings = append(report.Warnings, &GrafanaImportIssue{Title: panel.Title, Reason: warning})
+		}
+
+		if panel.Type != "row" {
+			report.Translated++
+		}
+
+		widgets = append(widgets, widget)
+		layout = append(layout, panel.layout(widget["id"].(string)))
+	}
+
+	tags := grafana.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	return StorableDashboardData{
+		"version":         string(MinSchemaVersion),
+		"title":           grafana.Title,
+		"description":     grafana.Description,
+		"tags":            tags,
+		"uploadedGrafana": true,
+		"layout":          layout,
+		"widgets":         widgets,
+		"variables":       variables,
+	}, report
+}
+
+// panels flattens the panels of the dashboard, the panels of collapsed rows follow their row and the panels of old
+// dashboards are laid out row by row.
+func (grafana *GrafanaDashboard) panels() []*GrafanaPanel {
+	panels := []*GrafanaPanel{}
+	for _, panel := range grafana.Panels {
+		if panel == nil {
+			continue
+		}
+
+		panels = append(panels, panel)
+		for _, nested := range panel.Panels {
+			if nested != nil {
+				panels = append(panels, nested)
+			}
+		}
+	}
+
+	y := 0
+	for _, row := range grafana.Rows {
+		if row == nil {
+			continue
+		}
+
+		if row.ShowTitle && row.Title != "" {
+			panels = append(panels, &GrafanaPanel{Type: "row", Title: row.Title, GridPos: &GrafanaGridPos{X: 0, Y: y, W: grafanaColumns, H: 1}})
+			y++
+		}
+
+		h := row.height()
+		x := 0
+		for _, panel := range row.Panels {
+			if panel == nil {
+				continue
+			}
+
+			// spans are on a grid of 12 columns
+			w := int(panel.Span * 2)
+			if w <= 0 {
+				w = defaultPanelWidth * 2
+			}
+
+			if x+w > grafanaColumns {
+				x = 0
+				y += h
+			}
+
+			panel.GridPos = &GrafanaGridPos{X: x, Y: y, W: w, H: h}
+			panels = append(panels, panel)
+			x += w
+		}
+
+		y += h
+	}
+
+	return panels
+}
+
+// height is the height of the row in units of the grid, rows of old dashboards are sized in pixels e.g. `250px`.
+func (row *GrafanaRow) height() int {
+	var value any
+	_ = json.Unmarshal(row.Height, &value)
+
+	pixels := 250.0
+	switch height := value.(type) {
+	case float64:
+		pixels = height
+	case string:
+		if parsed, err := strconv.ParseFloat(strings.TrimSuffix(height, "px"), 64); err == nil {
+			pixels = parsed
+		}
+	}
+
+	return max(1, int(math.Round(pixels/grafanaRowHeight)))
+}
+
+func (panel *GrafanaPanel) layout(id string) map[string]any {
+	gridPos := panel.GridPos
+	if gridPos == nil {
+		gridPos = &GrafanaGridPos{W: defaultPanelWidth * 2, H: 8}
+	}
+
+	if panel.Type == "row" {
+		return map[string]any{"i": id, "x": 0, "y": scaleHeight(gridPos.Y), "w": columns, "h": 1, "moved": false, "static": false}
+	}
+
+	// the bottom is scaled along with the top so that panels stacked in grafana don't overlap
+	x := gridPos.X * columns / grafanaColumns
+	w := max(1, int(math.Ceil(float64(gridPos.W*columns)/grafanaColumns)))
+	y := scaleHeight(gridPos.Y)
+	h := max(2, scaleHeight(gridPos.Y+gridPos.H)-y)
+
+	return map[string]any{"i": id, "x": x, "y": y, "w": min(w, columns-x), "h": h, "moved": false, "static": false}
+}
+
+func scaleHeight(height int) int {
+	return int(math.Round(float64(height*grafanaRowHeight) / rowHeight))
+}
+
+// translate translates the panel into a widget, the reason the panel cannot be translated is returned instead of the
+// widget.
+func (panel *GrafanaPanel) translate(variables map[string]bool) (map[string]any, []string, string) {
+	panelType, ok := grafanaPanelTypes[panel.Type]
+	if !ok {
+		return nil, nil, fmt.Sprintf("panels of type %q are not supported", panel.Type)
+	}
+
+	id := valuer.GenerateUUID().StringValue()
+	if panelType == PanelTypeRow {
+		return map[string]any{"id": id, "title": panel.Title, "description": "", "panelTypes": string(PanelTypeRow)}, nil, ""
+	}
+
+	warnings := []string{}
+	if panel.Repeat != "" {
+		warnings = append(warnings, fmt.Sprintf("the panel is repeated for every value of $%s in grafana, it is imported once", panel.Repeat))
+	}
+
+	// the queries without a valid name are named after the first letter no other query of the panel is named after
+	names := map[string]bool{}
+	for _, target := range panel.Targets {
+		if target != nil && queryNameRegex.MatchString(target.RefID) {
+			names[target.RefID] = true
+		}
+	}
+
+	promQL := []any{}
+	for _, target := range panel.Targets {
+		if target == nil {
+			continue
+		}
+
+		datasource := datasourceType(target.Datasource)
+		if datasource == "" {
+			datasource = datasourceType(panel.Datasource)
+		}
+
+		if datasource != "" && datasource != "prometheus" {
+			warnings = append(warnings, fmt.Sprintf("query %s of the %s datasource is not supported", target.RefID, datasource))
+			continue
+		}
+
+		if strings.TrimSpace(target.Expr) == "" {
+			warnings = append(warnings, fmt.Sprintf("query %s is not a promql query", target.RefID))
+			continue
+		}
+
+		name := target.RefID
+		if !queryNameRegex.MatchString(name) {
+			name = freeQueryName(names)
+			if name == "" {
+				warnings = append(warnings, fmt.Sprintf("query %s is left out, the panel has no query name left", target.RefID))
+				continue
+			}
+			names[name] = true
+		}
+
+		query, replaced := translatePromQL(target.Expr, variables)
+		for _, variable := range replaced {
+			warnings = append(warnings, fmt.Sprintf("$%s of query %s is replaced with %s", variable, name, grafanaIntervals[variable]))
+		}
+
+		legend := target.LegendFormat
+		if legend == "__auto" {
+			legend = ""
+		}
+
+		promQL = append(promQL, map[string]any{"name": name, "query": query, "legend": legend, "disabled": target.Hide})
+	}
+
+	if len(promQL) == 0 {
+		return nil, nil, "the panel has no promql query"
+	}
+
+	thresholds, thresholdWarnings := panel.thresholds(panelType)
+	warnings = append(warnings, thresholdWarnings...)
+
+	return map[string]any{
+		"id":             id,
+		"title":          panel.Title,
+		"description":    panel.Description,
+		"panelTypes":     string(panelType),
+		"isStacked":      false,
+		"nullZeroValues": "zero",
+		"opacity":        "1",
+		"fillSpans":      false,
+		"softMin":        nil,
+		"softMax":        nil,
+		"timePreferance": "GLOBAL_TIME",
+		"yAxisUnit":      panel.unit(),
+		"thresholds":     thresholds,
+		"query": map[string]any{
+			"id":        valuer.GenerateUUID().StringValue(),
+			"queryType": string(QueryTypePromQL),
+			"promql":    promQL,
+			"builder": map[string]any{
+				"queryData": []any{
+					map[string]any{
+						"queryName":          "A",
+						"dataSource":         "metrics",
+						"expression":         "A",
+						"disabled":           false,
+						"aggregateOperator":  "noop",
+						"aggregateAttribute": map[string]any{"key": "", "dataType": "", "type": ""},
+						"filters":            map[string]any{"items": []any{}, "op": "AND"},
+						"groupBy":            []any{},
+						"orderBy":            []any{},
+						"legend":             "",
+						"limit":              nil,
+						"stepInterval":       60,
+					},
+				},
+				"queryFormulas": []any{},
+			},
+			"clickhouse_sql": []any{map[string]any{"name": "A", "query": "", "legend": "", "disabled": false}},
+		},
+	}, warnings, ""
+}
+
+// unit is the unit of the panel, signoz and grafana share the ids of units.
+func (panel *GrafanaPanel) unit() string {
+	unit := ""
+	if panel.FieldConfig != nil {
+		unit = panel.FieldConfig.Defaults.Unit
+	}
+
+	if unit == "" && len(panel.YAxes) > 0 && panel.YAxes[0] != nil {
+		unit = panel.YAxes[0].Format
+	}
+
+	if unit == "" {
+		unit = panel.Format
+	}
+
+	if unit == "" {
+		return "none"
+	}
+
+	return unit
+}
+
+func (panel *GrafanaPanel) thresholds(panelType PanelType) ([]any, []string) {
+	thresholds := []any{}
+	warnings := []string{}
+	unit := panel.unit()
+
+	add := func(operator string, value float64, color string) {
+		format := "Text"
+		if panelType == PanelTypeValue {
+			format = "Background"
+		}
+
+		if hex, ok := grafanaColors[color]; ok {
+			color = hex
+		}
+
+		thresholds = append(thresholds, map[string]any{
+			"index":             valuer.GenerateUUID().StringValue(),
+			"keyIndex":          len(thresholds),
+			"thresholdOperator": operator,
+			"thresholdValue":    value,
+			"thresholdUnit":     unit,
+			"thresholdColor":    color,
+			"thresholdFormat":   format,
+			"thresholdLabel":    "",
+			"isEditEnabled":     false,
+			"selectedGraph":     string(panelType),
+		})
+	}
+
+	if panel.FieldConfig != nil && panel.FieldConfig.Defaults.Thresholds != nil {
+		if panel.FieldConfig.Defaults.Thresholds.Mode == "percentage" {
+			return thresholds, append(warnings, "percentage thresholds are not supported")
+		}
+
+		// the base step without a value colors the values below the first threshold
+		for _, step := range panel.FieldConfig.Defaults.Thresholds.Steps {
+			if step != nil && step.Value != nil {
+				add(">=", *step.Value, step.Color)
+			}
+		}
+
+		return thresholds, warnings
+	}
+
+	graphThresholds := []*grafanaGraphThreshold{}
+	if err := json.Unmarshal(panel.Thresholds, &graphThresholds); err == nil {
+		for _, threshold := range graphThresholds {
+			if threshold == nil || threshold.Value == nil {
+				continue
+			}
+
+			operator := ">"
+			if threshold.Op == "lt" {
+				operator = "<"
+			}
+
+			color := threshold.Color
+			if color == "" {
+				color = "red"
+			}
+
+			add(operator, *threshold.Value, color)
+		}
+	}
+
+	return thresholds, warnings
+}
+
+// translatePromQL rewrites the references to the variables of the dashboard as $name, the global variables of grafana
+// are replaced with fixed durations and returned.
+func translatePromQL(expr string, variables map[string]bool) (string, []string) {
+	replaced := []string{}
+	query := grafanaVariableRegex.ReplaceAllStringFunc(expr, func(match string) string {
+		groups := grafanaVariableRegex.FindStringSubmatch(match)
+		name := groups[1] + groups[2] + groups[3]
+
+		if duration, ok := grafanaIntervals[name]; ok {
+			replaced = append(replaced, name)
+			return duration
+		}
+
+		if variables[name] {
+			return "$" + name
+		}
+
+		return match
+	})
+
+	return query, replaced
+}
+
+// freeQueryName returns the first letter that is not a name in use, empty when all are.
+func freeQueryName(names map[string]bool) string {
+	for letter := 'A'; letter <= 'Z'; letter++ {
+		if !names[string(letter)] {
+			return string(letter)
+		}
+	}
+
+	return ""
+}
+
+// translate translates the variable into a signoz variable, the variables that cannot be translated are reported with
+// the reason.
+func (variable *GrafanaVariable) translate(order int) (map[string]any, string, bool) {
+	query := variable.query()
+	translated := map[string]any{
+		"id":            valuer.GenerateUUID().StringValue(),
+		"name":          variable.Name,
+		"description":   variable.Description,
+		"order":         order,
+		"multiSelect":   variable.Multi,
+		"showALLOption": variable.Multi && variable.IncludeAll,
+		"sort":          variable.sort(),
+		"queryValue":    "",
+		"customValue":   "",
+		"textboxValue":  "",
+	}
+
+	switch variable.Type {
+	case "query":
+		matches := grafanaLabelValuesRegex.FindStringSubmatch(query)
+		if matches == nil {
+			translated["type"] = string(VariableTypeTextbox)
+			return translated, fmt.Sprintf("query %q is not a label_values query, the variable is imported as a textbox", query), true
+		}
+
+		label := matches[3]
+		where := fmt.Sprintf("JSONHas(labels, '%s')", label)
+		if matches[1] != "" {
+			where = fmt.Sprintf("metric_name = '%s'", matches[1])
+		}
+
+		translated["type"] = string(VariableTypeQuery)
+		translated["queryValue"] = fmt.Sprintf("SELECT JSONExtractString(labels, '%s') AS %s\nFROM signoz_metrics.distributed_time_series_v4_1day\nWHERE %s\nGROUP BY %s", label, label, where, label)
+
+		// the selector is left out, the variable lists the values of every series of the metric
+		if matches[2] != "" {
+			return translated, fmt.Sprintf("the selector %s of query %q is dropped, the variable lists the values of %s of every series of %s", matches[2], query, label, matches[1]), true
+		}
+	case "custom", "interval":
+		translated["type"] = string(VariableTypeCustom)
+		translated["customValue"] = query
+	case "textbox", "constant":
+		translated["type"] = string(VariableTypeTextbox)
+		translated["textboxValue"] = query
+	default:
+		return nil, fmt.Sprintf("variables of type %q are not supported", variable.Type), false
+	}
+
+	return translated, "", true
+}
+
+// query is the query of the variable, the query of query variables is an object in recent versions of grafana.
+func (variable *GrafanaVariable) query() string {
+	var query any
+	_ = json.Unmarshal(variable.Query, &query)
+
+	switch query := query.(type) {
+	case string:
+		return query
+	case map[string]any:
+		if value, ok := query["query"].(string); ok {
+			return value
+		}
+	}
+
+	return variable.Definition
+}
+
+// sort maps the sorts of grafana, alphabetical, numerical and case-insensitive, on the direction of the sort.
+func (variable *GrafanaVariable) sort() string {
+	switch {
+	case variable.Sort <= 0:
+		return "DISABLED"
+	case variable.Sort%2 == 1:
+		return "ASC"
+	default:
+		return "DESC"
+	}
+}
+
+// datasourceType is the type of the datasource of a panel or query, it is empty when the datasource is referenced by
+// its name or the default one.
+func datasourceType(raw json.RawMessage) string {
+	var datasource any
+	_ = json.Unmarshal(raw, &datasource)
+
+	switch datasource := datasource.(type) {
+	case map[string]any:
+		if datasourceType, ok := datasource["type"].(string); ok && datasourceType != "datasource" {
+			return datasourceType
+		}
+	case string:
+		name := strings.ToLower(datasource)
+		for _, datasourceType := range []string{"loki", "elasticsearch", "influxdb", "mysql", "postgres", "tempo", "jaeger", "cloudwatch"} {
+			if strings.Contains(name, datasourceType) {
+				return datasourceType
+			}
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,164 @@
+package dashboardtypes
+
+import (
+	"testing"
+
+	"github.com/SigNoz/signoz/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const grafanaDashboard = `{
+	"dashboard": {
+		"title": "Node exporter",
+		"tags": ["linux"],
+		"panels": [
+			{"id": 1, "type": "row", "title": "CPU", "gridPos": {"x": 0, "y": 0, "w": 24, "h": 1}, "collapsed": false, "panels": []},
+			{
+				"id": 2, "type": "timeseries", "title": "CPU busy",
+				"datasource": {"type": "prometheus", "uid": "abc"},
+				"gridPos": {"x": 12, "y": 1, "w": 12, "h": 9},
+				"fieldConfig": {"defaults": {"unit": "percent", "thresholds": {"mode": "absolute", "steps": [{"color": "green", "value": null}, {"color": "red", "value": 80}]}}},
+				"targets": [
+					{"refId": "A", "expr": "sum by (instance) (rate(node_cpu_seconds_total{instance=~\"${instance}\", mode!=\"idle\"}[$__rate_interval]))", "legendFormat": "{{instance}}"},
+					{"refId": "B", "datasource": {"type": "loki"}, "expr": "{job=\"node\"}"}
+				]
+			},
+			{"id": 3, "type": "text", "title": "Notes", "gridPos": {"x": 0, "y": 1, "w": 12, "h": 9}},
+			{
+				"id": 4, "type": "row", "title": "Memory", "collapsed": true, "gridPos": {"x": 0, "y": 10, "w": 24, "h": 1},
+				"panels": [
+					{"id": 5, "type": "stat", "title": "Memory used", "datasource": "Prometheus", "gridPos": {"x": 0, "y": 11, "w": 6, "h": 4}, "targets": [{"refId": "A", "expr": "node_memory_Active_bytes{instance=\"$instance\"}"}], "fieldConfig": {"defaults": {"unit": "bytes"}}}
+				]
+			}
+		],
+		"templating": {
+			"list": [
+				{"name": "datasource", "type": "datasource", "query": "prometheus"},
+				{"name": "instance", "type": "query", "query": {"query": "label_values(node_uname_info{job=\"node\"}, instance)", "refId": "A"}, "multi": true, "includeAll": true, "sort": 1},
+				{"name": "mode", "type": "custom", "query": "user,system"},
+				{"name": "release", "type": "query", "query": "query_result(node_uname_info)"}
+			]
+		}
+	},
+	"meta": {}
+}`
+
+func TestGrafanaDashboardTranslate(t *testing.T) {
+	grafana, err := NewGrafanaDashboard([]byte(grafanaDashboard))
+	require.NoError(t, err)
+
+	data, report := grafana.Translate()
+	require.NoError(t, data.Validate())
+	assert.Equal(t, "Node exporter", data["title"])
+	assert.Equal(t, true, data["uploadedGrafana"])
+
+	// the text panel is skipped and so is the datasource variable, the loki query, the query variable and the
+	// dropped selector are reported
+	assert.Equal(t, 3, report.Panels)
+	assert.Equal(t, 2, report.Translated)
+	require.Len(t, report.Skipped, 2)
+	assert.Equal(t, "datasource", report.Skipped[0].Title)
+	assert.Equal(t, "Notes", report.Skipped[1].Title)
+	require.Len(t, report.Warnings, 4)
+	assert.Equal(t, "instance", report.Warnings[0].Title)
+	assert.Contains(t, report.Warnings[0].Reason, `{job="node"}`)
+
+	widgets := data["widgets"].([]any)
+	require.Len(t, widgets, 4)
+	assert.Equal(t, "row", widgets[0].(map[string]any)["panelTypes"])
+	assert.Equal(t, "row", widgets[2].(map[string]any)["panelTypes"])
+
+	cpu := widgets[1].(map[string]any)
+	assert.Equal(t, "graph", cpu["panelTypes"])
+	assert.Equal(t, "percent", cpu["yAxisUnit"])
+	promQL := cpu["query"].(map[string]any)["promql"].([]any)
+	require.Len(t, promQL, 1)
+	assert.Equal(t, `sum by (instance) (rate(node_cpu_seconds_total{instance=~"$instance", mode!="idle"}[5m]))`, promQL[0].(map[string]any)["query"])
+	assert.Equal(t, "{{instance}}", promQL[0].(map[string]any)["legend"])
+	thresholds := cpu["thresholds"].([]any)
+	require.Len(t, thresholds, 1)
+	assert.Equal(t, 80.0, thresholds[0].(map[string]any)["thresholdValue"])
+	assert.Equal(t, "#F2495C", thresholds[0].(map[string]any)["thresholdColor"])
+
+	memory := widgets[3].(map[string]any)
+	assert.Equal(t, "value", memory["panelTypes"])
+	assert.Equal(t, "bytes", memory["yAxisUnit"])
+
+	// the grid of 24 columns is halved
+	layout := data["layout"].([]any)
+	require.Len(t, layout, 4)
+	assert.Equal(t, map[string]any{"i": cpu["id"], "x": 6, "y": 1, "w": 6, "h": 6, "moved": false, "static": false}, layout[1])
+
+	variables := data["variables"].(map[string]any)
+	require.Len(t, variables, 3)
+	byName := map[string]map[string]any{}
+	for _, variable := range variables {
+		byName[variable.(map[string]any)["name"].(string)] = variable.(map[string]any)
+	}
+	assert.Equal(t, "QUERY", byName["instance"]["type"])
+	assert.Contains(t, byName["instance"]["queryValue"], "WHERE metric_name = 'node_uname_info'")
+	assert.Equal(t, true, byName["instance"]["showALLOption"])
+	assert.Equal(t, "ASC", byName["instance"]["sort"])
+	assert.Equal(t, "CUSTOM", byName["mode"]["type"])
+	assert.Equal(t, "user,system", byName["mode"]["customValue"])
+	assert.Equal(t, "TEXTBOX", byName["release"]["type"])
+}
+
+func TestGrafanaDashboardTranslateRows(t *testing.T) {
+	grafana, err := NewGrafanaDashboard([]byte(`{
+		"title": "legacy",
+		"rows": [
+			{"title": "Requests", "showTitle": true, "height": "300px", "panels": [
+				{"type": "graph", "title": "rate", "span": 6, "targets": [{"refId": "A", "expr": "rate(http_requests_total[5m])"}], "yaxes": [{"format": "reqps"}], "thresholds": [{"value": 100, "op": "gt", "fillColor": "red"}]},
+				{"type": "singlestat", "title": "total", "span": 6, "targets": [{"refId": "A", "expr": "sum(http_requests_total)"}], "format": "short"}
+			]}
+		]
+	}`))
+	require.NoError(t, err)
+
+	data, report := grafana.Translate()
+	require.NoError(t, data.Validate())
+	assert.Equal(t, 2, report.Translated)
+	assert.Empty(t, report.Skipped)
+
+	widgets := data["widgets"].([]any)
+	require.Len(t, widgets, 3)
+	assert.Equal(t, "reqps", widgets[1].(map[string]any)["yAxisUnit"])
+	assert.Equal(t, ">", widgets[1].(map[string]any)["thresholds"].([]any)[0].(map[string]any)["thresholdOperator"])
+
+	layout := data["layout"].([]any)
+	assert.Equal(t, 0, layout[1].(map[string]any)["x"])
+	assert.Equal(t, 6, layout[2].(map[string]any)["x"])
+	assert.Equal(t, layout[1].(map[string]any)["y"], layout[2].(map[string]any)["y"])
+}
+
+func TestGrafanaDashboardTranslateQueryNames(t *testing.T) {
+	grafana, err := NewGrafanaDashboard([]byte(`{
+		"title": "names",
+		"panels": [
+			{"type": "timeseries", "title": "requests", "gridPos": {"x": 0, "y": 0, "w": 12, "h": 8}, "targets": [
+				{"refId": "query", "expr": "sum(rate(http_requests_total[5m]))"},
+				{"refId": "A", "expr": "sum(rate(http_errors_total[5m]))"}
+			]}
+		]
+	}`))
+	require.NoError(t, err)
+
+	data, _ := grafana.Translate()
+	require.NoError(t, data.Validate())
+
+	// the unnamed query does not take the name of the query after it
+	promQL := data["widgets"].([]any)[0].(map[string]any)["query"].(map[string]any)["promql"].([]any)
+	require.Len(t, promQL, 2)
+	assert.Equal(t, "B", promQL[0].(map[string]any)["name"])
+	assert.Equal(t, "A", promQL[1].(map[string]any)["name"])
+}
+
+func TestNewGrafanaDashboard(t *testing.T) {
+	_, err := NewGrafanaDashboard([]byte(`{"panels": []}`))
+	assert.True(t, errors.Ast(err, errors.TypeInvalidInput))
+
+	_, err = NewGrafanaDashboard([]byte(`[`))
+	assert.True(t, errors.Ast(err, errors.TypeInvalidInput))
+}
@@ -0,0 +1,100 @@
+package dashboardtypes
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+const jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+var (
+	enumerType       = reflect.TypeFor[interface{ Enum() []string }]()
+	jsonSchemaerType = reflect.TypeFor[interface{ JSONSchema() map[string]any }]()
+	rawMessageType   = reflect.TypeFor[json.RawMessage]()
+)
+
+// NewJSONSchema returns the JSON schema of the data of dashboards of the version for linting them ahead of pushing.
+// The fields the schema doesn't know of are allowed.
+func NewJSONSchema(version SchemaVersion) map[string]any {
+	schema := newJSONSchema(reflect.TypeFor[Spec]())
+	schema["$schema"] = jsonSchemaDraft
+	schema["title"] = "SigNoz dashboard " + string(version)
+
+	properties := schema["properties"].(map[string]any)
+	properties["version"] = map[string]any{"type": "string", "const": string(version)}
+
+	// widgets query through the sections of their query type up to v4 and through the query envelopes from v5
+	query := properties["widgets"].(map[string]any)["items"].(map[string]any)["properties"].(map[string]any)["query"].(map[string]any)
+	queryProperties := query["properties"].(map[string]any)
+	if version == SchemaVersionV5 {
+		delete(queryProperties, "queryType")
+		delete(queryProperties, "builder")
+		delete(queryProperties, "clickhouse_sql")
+		delete(queryProperties, "promql")
+		query["required"] = []string{"queries"}
+	} else {
+		delete(queryProperties, "queries")
+		query["required"] = []string{"queryType"}
+	}
+
+	return schema
+}
+
+func newJSONSchema(t reflect.Type) map[string]any {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t.Implements(jsonSchemaerType) {
+		return reflect.Zero(t).Interface().(interface{ JSONSchema() map[string]any }).JSONSchema()
+	}
+
+	if t.Implements(enumerType) {
+		return map[string]any{"type": "string", "enum": reflect.Zero(t).Interface().(interface{ Enum() []string }).Enum()}
+	}
+
+	if t == rawMessageType {
+		return map[string]any{}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice:
+		return map[string]any{"type": "array", "items": newJSONSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": newJSONSchema(t.Elem())}
+	case reflect.Struct:
+		properties := map[string]any{}
+		required := []string{}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "" || name == "-" {
+				continue
+			}
+
+			properties[name] = newJSONSchema(field.Type)
+			if field.Tag.Get("required") == "true" {
+				required = append(required, name)
+			}
+		}
+
+		schema := map[string]any{"type": "object", "properties": properties}
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+
+		return schema
+	default:
+		// values of any type
+		return map[string]any{}
+	}
+}
@@ -0,0 +1,196 @@
+package dashboardtypes
+
+import (
+	"maps"
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/errors"
+	"github.com/SigNoz/signoz/pkg/types"
+	"github.com/SigNoz/signoz/pkg/types/audittypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+	"github.com/uptrace/bun"
+)
+
+// MaxRevisions is the number of revisions kept for every dashboard, the oldest ones are pruned first.
+const MaxRevisions int = 50
+
+var (
+	ErrCodeRevisionNotFound = errors.MustNewCode("dashboard_revision_not_found")
+)
+
+// StorableRevision is the immutable record of the data of a dashboard after a change, the latest revision holds the
+// current data of the dashboard.
+type StorableRevision struct {
+	bun.BaseModel `bun:"table:dashboard_revision"`
+
+	types.Identifiable
+	OrgID       valuer.UUID           `bun:"org_id,type:text,notnull"`
+	DashboardID valuer.UUID           `bun:"dashboard_id,type:text,notnull,unique:dashboard_id_version"`
+	Version     int                   `bun:"version,notnull,unique:dashboard_id_version"`
+	Data        StorableDashboardData `bun:"data,type:text,notnull"`
+	Message     string                `bun:"message,type:text"`
+	// version the data was restored from, zero for regular updates
+	RestoredFrom int       `bun:"restored_from,notnull,default:0"`
+	CreatedAt    time.Time `bun:"created_at,notnull"`
+	CreatedBy    string    `bun:"created_by,type:text"`
+}
+
+type GettableRevision struct {
+	Version      int                   `json:"version"`
+	Message      string                `json:"message"`
+	RestoredFrom int                   `json:"restoredFrom,omitempty"`
+	CreatedAt    time.Time             `json:"createdAt"`
+	CreatedBy    string                `json:"createdBy"`
+	Data         StorableDashboardData `json:"data,omitempty"`
+}
+
+type WidgetChangeType struct{ valuer.String }
+
+var (
+	WidgetChangeTypeAdded    = WidgetChangeType{valuer.NewString("added")}
+	WidgetChangeTypeRemoved  = WidgetChangeType{valuer.NewString("removed")}
+	WidgetChangeTypeModified = WidgetChangeType{valuer.NewString("modified")}
+)
+
+// WidgetDiff is the change of a widget between two revisions, the position of the widget in the layout is diffed
+// under the `layout` path.
+type WidgetDiff struct {
+	ID      string               `json:"id"`
+	Title   string               `json:"title"`
+	Type    WidgetChangeType     `json:"type"`
+	Changes []*audittypes.Change `json:"changes"`
+}
+
+type RevisionDiff struct {
+	From int `json:"from"`
+	To   int `json:"to"`
+	// changes of the dashboard besides its widgets, e.g. title and variables
+	Changes []*audittypes.Change `json:"changes"`
+	Widgets []*WidgetDiff        `json:"widgets"`
+}
+
+func NewStorableRevision(dashboard *Dashboard, version int, message string, restoredFrom int) (*StorableRevision, error) {
+	dashboardID, err := valuer.NewUUID(dashboard.ID)
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "id is not a valid uuid")
+	}
+
+	return &StorableRevision{
+		Identifiable: types.Identifiable{
+			ID: valuer.GenerateUUID(),
+		},
+		OrgID:        dashboard.OrgID,
+		DashboardID:  dashboardID,
+		Version:      version,
+		Data:         dashboard.Data,
+		Message:      message,
+		RestoredFrom: restoredFrom,
+		CreatedAt:    dashboard.UpdatedAt,
+		CreatedBy:    dashboard.UpdatedBy,
+	}, nil
+}
+
+// NewGettableRevision converts the revision, the data is left out of listings.
+func NewGettableRevision(revision *StorableRevision, withData bool) *GettableRevision {
+	gettable := &GettableRevision{
+		Version:      revision.Version,
+		Message:      revision.Message,
+		RestoredFrom: revision.RestoredFrom,
+		CreatedAt:    revision.CreatedAt,
+		CreatedBy:    revision.CreatedBy,
+	}
+
+	if withData {
+		gettable.Data = revision.Data
+	}
+
+	return gettable
+}
+
+// NewRevisionDiff diffs the data of two revisions of a dashboard, widgets are matched on their ids.
+func NewRevisionDiff(from *StorableRevision, to *StorableRevision) *RevisionDiff {
+	fromWidgets, fromOrder := widgetsByID(from.Data)
+	toWidgets, toOrder := widgetsByID(to.Data)
+
+	widgets := make([]*WidgetDiff, 0)
+	for _, id := range toOrder {
+		after := toWidgets[id]
+		before, ok := fromWidgets[id]
+		if !ok {
+			widgets = append(widgets, &WidgetDiff{ID: id, Title: widgetTitle(after), Type: WidgetChangeTypeAdded, Changes: audittypes.NewChanges("", string(audittypes.NewSnapshot(after)))})
+			continue
+		}
+
+		changes := audittypes.NewChanges(string(audittypes.NewSnapshot(before)), string(audittypes.NewSnapshot(after)))
+		if len(changes) > 0 {
+			widgets = append(widgets, &WidgetDiff{ID: id, Title: widgetTitle(after), Type: WidgetChangeTypeModified, Changes: changes})
+		}
+	}
+
+	for _, id := range fromOrder {
+		before := fromWidgets[id]
+		if _, ok := toWidgets[id]; !ok {
+			widgets = append(widgets, &WidgetDiff{ID: id, Title: widgetTitle(before), Type: WidgetChangeTypeRemoved, Changes: audittypes.NewChanges(string(audittypes.NewSnapshot(before)), "")})
+		}
+	}
+
+	return &RevisionDiff{
+		From:    from.Version,
+		To:      to.Version,
+		Changes: audittypes.NewChanges(string(audittypes.NewSnapshot(withoutWidgets(from.Data))), string(audittypes.NewSnapshot(withoutWidgets(to.Data)))),
+		Widgets: widgets,
+	}
+}
+
+// widgetsByID indexes the widgets of the data along with their item of the layout, it also returns the ids in the
+// order of the widgets.
+func widgetsByID(data StorableDashboardData) (map[string]map[string]any, []string) {
+	layouts := map[string]any{}
+	if items, ok := data["layout"].([]any); ok {
+		for _, item := range items {
+			if layout, ok := item.(map[string]any); ok {
+				if id, ok := layout["i"].(string); ok {
+					layouts[id] = layout
+				}
+			}
+		}
+	}
+
+	widgets := map[string]map[string]any{}
+	order := []string{}
+	if items, ok := data["widgets"].([]any); ok {
+		for _, item := range items {
+			widget, ok := item.(map[string]any)
+			if !ok {
+				continue
+			}
+
+			id, ok := widget["id"].(string)
+			if !ok {
+				continue
+			}
+
+			widget = maps.Clone(widget)
+			if layout, ok := layouts[id]; ok {
+				widget["layout"] = layout
+			}
+
+			widgets[id] = widget
+			order = append(order, id)
+		}
+	}
+
+	return widgets, order
+}
+
+func withoutWidgets(data StorableDashboardData) map[string]any {
+	rest := maps.Clone(map[string]any(data))
+	delete(rest, "widgets")
+	delete(rest, "layout")
+	return rest
+}
+
+func widgetTitle(widget map[string]any) string {
+	title, _ := widget["title"].(string)
+	return title
+}
@@ -0,0 +1,64 @@
+package dashboardtypes
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRevisionDiff(t *testing.T) {
+	revision := func(version int, data string) *StorableRevision {
+		storable := StorableDashboardData{}
+		require.NoError(t, json.Unmarshal([]byte(data), &storable))
+		return &StorableRevision{Version: version, Data: storable}
+	}
+
+	from := revision(1, `{
+		"title": "payments",
+		"layout": [{"i": "a", "x": 0, "y": 0}, {"i": "b", "x": 6, "y": 0}, {"i": "c", "x": 0, "y": 6}],
+		"widgets": [{"id": "a", "title": "latency"}, {"id": "b", "title": "errors"}, {"id": "c", "title": "throughput"}]
+	}`)
+	to := revision(3, `{
+		"title": "payments api",
+		"layout": [{"i": "a", "x": 0, "y": 0}, {"i": "b", "x": 0, "y": 6}, {"i": "d", "x": 6, "y": 6}],
+		"widgets": [{"id": "a", "title": "latency"}, {"id": "b", "title": "errors"}, {"id": "d", "title": "saturation"}]
+	}`)
+
+	diff := NewRevisionDiff(from, to)
+	assert.Equal(t, 1, diff.From)
+	assert.Equal(t, 3, diff.To)
+
+	require.Len(t, diff.Changes, 1)
+	assert.Equal(t, "title", diff.Changes[0].Path)
+
+	testCases := []struct {
+		id    string
+		kind  WidgetChangeType
+		paths []string
+	}{
+		{id: "b", kind: WidgetChangeTypeModified, paths: []string{"layout.x", "layout.y"}},
+		{id: "d", kind: WidgetChangeTypeAdded},
+		{id: "c", kind: WidgetChangeTypeRemoved},
+	}
+
+	require.Len(t, diff.Widgets, len(testCases))
+	for i, tc := range testCases {
+		t.Run(tc.id, func(t *testing.T) {
+			widget := diff.Widgets[i]
+			assert.Equal(t, tc.id, widget.ID)
+			assert.Equal(t, tc.kind, widget.Type)
+			assert.NotEmpty(t, widget.Changes)
+			if tc.paths != nil {
+				paths := []string{}
+				for _, change := range widget.Changes {
+					paths = append(paths, change.Path)
+				}
+				assert.ElementsMatch(t, tc.paths, paths)
+			}
+		})
+	}
+
+	assert.Empty(t, NewRevisionDiff(from, from).Widgets)
+}
@@ -0,0 +1,624 @@
+package dashboardtypes
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/SigNoz/signoz/pkg/errors"
+	qbtypes "github.com/SigNoz/signoz/pkg/types/querybuildertypes/querybuildertypesv5"
+)
+
+var (
+	ErrCodeDashboardInvalid = errors.MustNewCode("dashboard_invalid")
+)
+
+// SchemaVersion is the version of the schema of the data of a dashboard, it is kept in the `version` field of the
+// data. The data without a version is of the oldest schema.
+type SchemaVersion string
+
+const (
+	SchemaVersionV3 SchemaVersion = "v3"
+	// widgets query through the builder, clickhouse and promql sections of the query editor
+	SchemaVersionV4 SchemaVersion = "v4"
+	// widgets query through v5 query envelopes
+	SchemaVersionV5 SchemaVersion = "v5"
+)
+
+var (
+	schemaVersions = []SchemaVersion{SchemaVersionV3, SchemaVersionV4, SchemaVersionV5}
+
+	// LatestSchemaVersion is the version the data of dashboards is upgraded to on request.
+	LatestSchemaVersion = SchemaVersionV5
+
+	// MinSchemaVersion is the version the data of dashboards is upgraded to when they are created or updated.
+	MinSchemaVersion = SchemaVersionV4
+)
+
+func NewSchemaVersion(version string) (SchemaVersion, error) {
+	if version == "" {
+		return SchemaVersionV3, nil
+	}
+
+	if !slices.Contains(schemaVersions, SchemaVersion(version)) {
+		return "", errors.Newf(errors.TypeInvalidInput, ErrCodeDashboardInvalid, "invalid dashboard: unknown version %q, must be one of %s", version, joinEnum(schemaVersions))
+	}
+
+	return SchemaVersion(version), nil
+}
+
+func (version SchemaVersion) Enum() []string {
+	return enumStrings(schemaVersions)
+}
+
+func (version SchemaVersion) before(other SchemaVersion) bool {
+	return slices.Index(schemaVersions, version) < slices.Index(schemaVersions, other)
+}
+
+type PanelType string
+
+const (
+	PanelTypeGraph       PanelType = "graph"
+	PanelTypeValue       PanelType = "value"
+	PanelTypeTable       PanelType = "table"
+	PanelTypeList        PanelType = "list"
+	PanelTypeTrace       PanelType = "trace"
+	PanelTypeBar         PanelType = "bar"
+	PanelTypePie         PanelType = "pie"
+	PanelTypeHistogram   PanelType = "histogram"
+	PanelTypeEmptyWidget PanelType = "EMPTY_WIDGET"
+	// rows group the widgets laid out below them, they don't query
+	PanelTypeRow PanelType = "row"
+)
+
+var panelTypes = []PanelType{PanelTypeGraph, PanelTypeValue, PanelTypeTable, PanelTypeList, PanelTypeTrace, PanelTypeBar, PanelTypePie, PanelTypeHistogram, PanelTypeEmptyWidget, PanelTypeRow}
+
+func (panelType PanelType) Enum() []string {
+	return enumStrings(panelTypes)
+}
+
+// RequestType is the type of the v5 queries of the panel.
+func (panelType PanelType) RequestType() qbtypes.RequestType {
+	switch panelType {
+	case PanelTypeValue, PanelTypeTable, PanelTypePie:
+		return qbtypes.RequestTypeScalar
+	case PanelTypeList, PanelTypeTrace:
+		return qbtypes.RequestTypeRaw
+	case PanelTypeHistogram:
+		return qbtypes.RequestTypeDistribution
+	default:
+		return qbtypes.RequestTypeTimeSeries
+	}
+}
+
+func (panelType PanelType) queries() bool {
+	return panelType != PanelTypeRow && panelType != PanelTypeEmptyWidget
+}
+
+type QueryType string
+
+const (
+	QueryTypeBuilder       QueryType = "builder"
+	QueryTypeClickHouseSQL QueryType = "clickhouse_sql"
+	QueryTypePromQL        QueryType = "promql"
+)
+
+var queryTypes = []QueryType{QueryTypeBuilder, QueryTypeClickHouseSQL, QueryTypePromQL}
+
+func (queryType QueryType) Enum() []string {
+	return enumStrings(queryTypes)
+}
+
+type DataSource string
+
+var dataSources = []DataSource{"metrics", "logs", "traces"}
+
+func (dataSource DataSource) Enum() []string {
+	return enumStrings(dataSources)
+}
+
+type VariableType string
+
+const (
+	VariableTypeQuery   VariableType = "QUERY"
+	VariableTypeTextbox VariableType = "TEXTBOX"
+	VariableTypeCustom  VariableType = "CUSTOM"
+)
+
+var variableTypes = []VariableType{VariableTypeQuery, VariableTypeTextbox, VariableTypeCustom}
+
+func (variableType VariableType) Enum() []string {
+	return enumStrings(variableTypes)
+}
+
+type VariableSort string
+
+var variableSorts = []VariableSort{"DISABLED", "ASC", "DESC"}
+
+func (sort VariableSort) Enum() []string {
+	return enumStrings(variableSorts)
+}
+
+// Spec is the typed schema of the data of a dashboard. Only the fields the backend validates are typed, the other
+// fields of the data are kept as they are.
+type Spec struct {
+	Version     SchemaVersion        `json:"version"`
+	Title       string               `json:"title" required:"true"`
+	Description string               `json:"description,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	Image       string               `json:"image,omitempty"`
+	Layout      []*LayoutItem        `json:"layout,omitempty"`
+	Widgets     []*Widget            `json:"widgets,omitempty"`
+	Variables   map[string]*Variable `json:"variables,omitempty"`
+}
+
+// LayoutItem is the position of a widget on the grid of the dashboard.
+type LayoutItem struct {
+	WidgetID string `json:"i" required:"true"`
+	X        int    `json:"x"`
+	Y        int    `json:"y"`
+	W        int    `json:"w" required:"true"`
+	H        int    `json:"h" required:"true"`
+}
+
+type Widget struct {
+	ID          string       `json:"id" required:"true"`
+	Title       string       `json:"title"`
+	Description string       `json:"description,omitempty"`
+	PanelType   PanelType    `json:"panelTypes" required:"true"`
+	Query       *WidgetQuery `json:"query,omitempty"`
+}
+
+// WidgetQuery is the query of a widget. Up to v4 the widget queries with the section of its query type, from v5 it
+// queries with the v5 query envelopes.
+type WidgetQuery struct {
+	QueryType     QueryType        `json:"queryType,omitempty"`
+	Builder       *BuilderSection  `json:"builder,omitempty"`
+	ClickHouseSQL []*RawQuery      `json:"clickhouse_sql,omitempty"`
+	PromQL        []*RawQuery      `json:"promql,omitempty"`
+	Queries       []*QueryEnvelope `json:"queries,omitempty"`
+}
+
+type BuilderSection struct {
+	QueryData     []*BuilderQuery   `json:"queryData"`
+	QueryFormulas []*BuilderFormula `json:"queryFormulas,omitempty"`
+}
+
+type BuilderQuery struct {
+	QueryName          string          `json:"queryName" required:"true"`
+	DataSource         DataSource      `json:"dataSource" required:"true"`
+	Disabled           bool            `json:"disabled"`
+	Expression         string          `json:"expression,omitempty"`
+	AggregateOperator  string          `json:"aggregateOperator,omitempty"`
+	AggregateAttribute *AttributeKey   `json:"aggregateAttribute,omitempty"`
+	TimeAggregation    string          `json:"timeAggregation,omitempty"`
+	SpaceAggregation   string          `json:"spaceAggregation,omitempty"`
+	Filters            *BuilderFilters `json:"filters,omitempty"`
+	GroupBy            []*AttributeKey `json:"groupBy,omitempty"`
+	OrderBy            []*OrderBy      `json:"orderBy,omitempty"`
+	Limit              *int            `json:"limit,omitempty"`
+	StepInterval       *int            `json:"stepInterval,omitempty"`
+	Legend             string          `json:"legend,omitempty"`
+}
+
+type AttributeKey struct {
+	Key      string `json:"key"`
+	DataType string `json:"dataType,omitempty"`
+	Type     string `json:"type,omitempty"`
+}
+
+type BuilderFilters struct {
+	Items []*FilterItem `json:"items"`
+	Op    string        `json:"op,omitempty"`
+}
+
+type FilterItem struct {
+	Key   *AttributeKey `json:"key"`
+	Op    string        `json:"op"`
+	Value any           `json:"value,omitempty"`
+}
+
+type OrderBy struct {
+	ColumnName string `json:"columnName"`
+	Order      string `json:"order,omitempty"`
+}
+
+type BuilderFormula struct {
+	QueryName  string `json:"queryName" required:"true"`
+	Expression string `json:"expression" required:"true"`
+	Disabled   bool   `json:"disabled"`
+	Legend     string `json:"legend,omitempty"`
+}
+
+// RawQuery is a clickhouse or promql query of the query editor.
+type RawQuery struct {
+	Name     string `json:"name" required:"true"`
+	Query    string `json:"query"`
+	Disabled bool   `json:"disabled"`
+	Legend   string `json:"legend,omitempty"`
+}
+
+// QueryEnvelope is a v5 query envelope, it is decoded on validation to report the widget it belongs to.
+type QueryEnvelope struct {
+	Type string          `json:"type" required:"true"`
+	Spec json.RawMessage `json:"spec" required:"true"`
+}
+
+type Variable struct {
+	ID            string       `json:"id"`
+	Name          string       `json:"name" required:"true"`
+	Description   string       `json:"description,omitempty"`
+	Type          VariableType `json:"type" required:"true"`
+	QueryValue    string       `json:"queryValue,omitempty"`
+	CustomValue   string       `json:"customValue,omitempty"`
+	TextboxValue  string       `json:"textboxValue,omitempty"`
+	Sort          VariableSort `json:"sort,omitempty"`
+	MultiSelect   bool         `json:"multiSelect"`
+	ShowALLOption bool         `json:"showALLOption"`
+}
+
+func (QueryEnvelope) JSONSchema() map[string]any {
+	return map[string]any{
+		"type":     "object",
+		"required": []string{"type", "spec"},
+		"properties": map[string]any{
+			"type": map[string]any{
+				"type": "string",
+				"enum": []string{
+					qbtypes.QueryTypeBuilder.StringValue(),
+					qbtypes.QueryTypeFormula.StringValue(),
+					qbtypes.QueryTypeJoin.StringValue(),
+					qbtypes.QueryTypePromQL.StringValue(),
+					qbtypes.QueryTypeClickHouseSQL.StringValue(),
+				},
+			},
+			"spec": map[string]any{"type": "object"},
+		},
+	}
+}
+
+// NewSpec decodes the data of a dashboard into its typed schema.
+func NewSpec(data StorableDashboardData) (*Spec, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.TypeInvalidInput, ErrCodeDashboardInvalid, "invalid dashboard: cannot encode data")
+	}
+
+	spec := new(Spec)
+	if err := json.Unmarshal(raw, spec); err != nil {
+		typeErr := new(json.UnmarshalTypeError)
+		if errors.As(err, &typeErr) {
+			return nil, errors.Newf(errors.TypeInvalidInput, ErrCodeDashboardInvalid, "invalid dashboard: %s must be of type %s, got %s", typeErr.Field, typeErr.Type.String(), typeErr.Value)
+		}
+
+		return nil, errors.Wrapf(err, errors.TypeInvalidInput, ErrCodeDashboardInvalid, "invalid dashboard: %s", err.Error())
+	}
+
+	if _, err := NewSchemaVersion(string(spec.Version)); err != nil {
+		return nil, err
+	}
+
+	if spec.Version == "" {
+		spec.Version = SchemaVersionV3
+	}
+
+	return spec, nil
+}
+
+// Validate validates the data against the schema of its version.
+func (storableDashboardData StorableDashboardData) Validate() error {
+	spec, err := NewSpec(storableDashboardData)
+	if err != nil {
+		return err
+	}
+
+	return spec.Validate()
+}
+
+// Validate validates the dashboard against the schema of its version, the errors point at the invalid field.
+func (spec *Spec) Validate() error {
+	if strings.TrimSpace(spec.Title) == "" {
+		return invalidf("title", "is required")
+	}
+
+	widgetIDs := map[string]bool{}
+	for i, widget := range spec.Widgets {
+		path := fmt.Sprintf("widgets[%d]", i)
+		if widget == nil {
+			return invalidf(path, "must be an object")
+		}
+
+		if widget.ID == "" {
+			return invalidf(path+".id", "is required")
+		}
+
+		if widgetIDs[widget.ID] {
+			return invalidf(path+".id", "%q is used by more than one widget", widget.ID)
+		}
+		widgetIDs[widget.ID] = true
+
+		if err := spec.validateWidget(path, widget); err != nil {
+			return err
+		}
+	}
+
+	layoutIDs := map[string]bool{}
+	for i, item := range spec.Layout {
+		path := fmt.Sprintf("layout[%d]", i)
+		if item == nil {
+			return invalidf(path, "must be an object")
+		}
+
+		if !widgetIDs[item.WidgetID] {
+			return invalidf(path+".i", "%q is not the id of a widget", item.WidgetID)
+		}
+
+		if layoutIDs[item.WidgetID] {
+			return invalidf(path+".i", "widget %q is laid out more than once", item.WidgetID)
+		}
+		layoutIDs[item.WidgetID] = true
+
+		if item.X < 0 || item.Y < 0 {
+			return invalidf(path, "x and y must not be negative")
+		}
+
+		if item.W <= 0 || item.H <= 0 {
+			return invalidf(path, "w and h must be positive")
+		}
+	}
+
+	variableNames := map[string]bool{}
+	for _, key := range slices.Sorted(maps.Keys(spec.Variables)) {
+		variable := spec.Variables[key]
+		path := fmt.Sprintf("variables[%q]", key)
+		if variable == nil {
+			return invalidf(path, "must be an object")
+		}
+
+		if variable.Name == "" {
+			return invalidf(path+".name", "is required")
+		}
+
+		if variableNames[variable.Name] {
+			return invalidf(path+".name", "%q is used by more than one variable", variable.Name)
+		}
+		variableNames[variable.Name] = true
+
+		if !slices.Contains(variableTypes, variable.Type) {
+			return invalidf(path+".type", "%q must be one of %s", variable.Type, joinEnum(variableTypes))
+		}
+
+		if variable.Sort != "" && !slices.Contains(variableSorts, variable.Sort) {
+			return invalidf(path+".sort", "%q must be one of %s", variable.Sort, joinEnum(variableSorts))
+		}
+	}
+
+	return nil
+}
+
+func (spec *Spec) validateWidget(path string, widget *Widget) error {
+	if !slices.Contains(panelTypes, widget.PanelType) {
+		return invalidf(path+".panelTypes", "%q must be one of %s", widget.PanelType, joinEnum(panelTypes))
+	}
+
+	if !widget.PanelType.queries() {
+		return nil
+	}
+
+	if widget.Query == nil {
+		return invalidf(path+".query", "is required for %s panels", widget.PanelType)
+	}
+
+	if spec.Version == SchemaVersionV5 {
+		return validateQueryEnvelopes(path+".query.queries", widget.Query.Queries, widget.PanelType.RequestType())
+	}
+
+	query := widget.Query
+	switch query.QueryType {
+	case QueryTypeBuilder:
+		if query.Builder == nil || len(query.Builder.QueryData) == 0 {
+			return invalidf(path+".query.builder.queryData", "must have at least one query")
+		}
+
+		names := map[string]bool{}
+		for i, builderQuery := range query.Builder.QueryData {
+			queryPath := fmt.Sprintf("%s.query.builder.queryData[%d]", path, i)
+			if builderQuery == nil {
+				return invalidf(queryPath, "must be an object")
+			}
+
+			if err := validateQueryName(queryPath+".queryName", builderQuery.QueryName, names); err != nil {
+				return err
+			}
+
+			if !slices.Contains(dataSources, builderQuery.DataSource) {
+				return invalidf(queryPath+".dataSource", "%q must be one of %s", builderQuery.DataSource, joinEnum(dataSources))
+			}
+		}
+
+		for i, formula := range query.Builder.QueryFormulas {
+			formulaPath := fmt.Sprintf("%s.query.builder.queryFormulas[%d]", path, i)
+			if formula == nil {
+				return invalidf(formulaPath, "must be an object")
+			}
+
+			if err := validateQueryName(formulaPath+".queryName", formula.QueryName, names); err != nil {
+				return err
+			}
+
+			if strings.TrimSpace(formula.Expression) == "" {
+				return invalidf(formulaPath+".expression", "is required")
+			}
+		}
+	case QueryTypeClickHouseSQL:
+		return validateRawQueries(path+".query.clickhouse_sql", query.ClickHouseSQL)
+	case QueryTypePromQL:
+		return validateRawQueries(path+".query.promql", query.PromQL)
+	default:
+		return invalidf(path+".query.queryType", "%q must be one of %s", query.QueryType, joinEnum(queryTypes))
+	}
+
+	return nil
+}
+
+func validateRawQueries(path string, queries []*RawQuery) error {
+	if len(queries) == 0 {
+		return invalidf(path, "must have at least one query")
+	}
+
+	names := map[string]bool{}
+	for i, query := range queries {
+		queryPath := fmt.Sprintf("%s[%d]", path, i)
+		if query == nil {
+			return invalidf(queryPath, "must be an object")
+		}
+
+		if err := validateQueryName(queryPath+".name", query.Name, names); err != nil {
+			return err
+		}
+
+		if !query.Disabled && strings.TrimSpace(query.Query) == "" {
+			return invalidf(queryPath+".query", "is required for enabled queries")
+		}
+	}
+
+	return nil
+}
+
+func validateQueryEnvelopes(path string, queries []*QueryEnvelope, requestType qbtypes.RequestType) error {
+	if len(queries) == 0 {
+		return invalidf(path, "must have at least one query")
+	}
+
+	envelopes := make([]qbtypes.QueryEnvelope, 0, len(queries))
+	for i, query := range queries {
+		queryPath := fmt.Sprintf("%s[%d]", path, i)
+		if query == nil {
+			return invalidf(queryPath, "must be an object")
+		}
+
+		raw, err := json.Marshal(query)
+		if err != nil {
+			return invalidf(queryPath, "%s", err.Error())
+		}
+
+		envelope := qbtypes.QueryEnvelope{}
+		if err := json.Unmarshal(raw, &envelope); err != nil {
+			return wrapInvalid(queryPath, err)
+		}
+
+		envelopes = append(envelopes, envelope)
+	}
+
+	composite := qbtypes.CompositeQuery{Queries: envelopes}
+	if err := composite.Validate(requestType); err != nil {
+		return wrapInvalid(path, err)
+	}
+
+	return nil
+}
+
+// QueryTexts returns the text of the queries of the widgets and the names of the metrics they aggregate, in the order
+// of the widgets. The queries that cannot be decoded are skipped.
+func (spec *Spec) QueryTexts() []string {
+	texts := make([]string, 0)
+	add := func(text string) {
+		if text != "" {
+			texts = append(texts, text)
+		}
+	}
+
+	for _, widget := range spec.Widgets {
+		if widget == nil || widget.Query == nil {
+			continue
+		}
+
+		if widget.Query.Builder != nil {
+			for _, query := range widget.Query.Builder.QueryData {
+				if query == nil {
+					continue
+				}
+
+				if query.AggregateAttribute != nil {
+					add(query.AggregateAttribute.Key)
+				}
+				add(query.Expression)
+			}
+		}
+
+		for _, query := range slices.Concat(widget.Query.ClickHouseSQL, widget.Query.PromQL) {
+			if query != nil {
+				add(query.Query)
+			}
+		}
+
+		for _, envelope := range widget.Query.Queries {
+			if envelope == nil {
+				continue
+			}
+
+			querySpec := struct {
+				Query        string `json:"query"`
+				Aggregations []struct {
+					MetricName string `json:"metricName"`
+					Expression string `json:"expression"`
+				} `json:"aggregations"`
+				Filter *struct {
+					Expression string `json:"expression"`
+				} `json:"filter"`
+			}{}
+			if err := json.Unmarshal(envelope.Spec, &querySpec); err != nil {
+				continue
+			}
+
+			add(querySpec.Query)
+			for _, aggregation := range querySpec.Aggregations {
+				add(aggregation.MetricName)
+				add(aggregation.Expression)
+			}
+			if querySpec.Filter != nil {
+				add(querySpec.Filter.Expression)
+			}
+		}
+	}
+
+	return texts
+}
+
+func validateQueryName(path string, name string, names map[string]bool) error {
+	if name == "" {
+		return invalidf(path, "is required")
+	}
+
+	if names[name] {
+		return invalidf(path, "%q is used by more than one query", name)
+	}
+	names[name] = true
+
+	return nil
+}
+
+func invalidf(path string, format string, args ...any) error {
+	return errors.Newf(errors.TypeInvalidInput, ErrCodeDashboardInvalid, "invalid dashboard: %s %s", path, fmt.Sprintf(format, args...))
+}
+
+// wrapInvalid points the error of a v5 query at its path in the dashboard, keeping its hints.
+func wrapInvalid(path string, err error) error {
+	_, _, message, _, _, additional := errors.Unwrapb(err)
+	return errors.Newf(errors.TypeInvalidInput, ErrCodeDashboardInvalid, "invalid dashboard: %s: %s", path, message).WithAdditional(additional...)
+}
+
+func enumStrings[T ~string](values []T) []string {
+	strs := make([]string, 0, len(values))
+	for _, value := range values {
+		strs = append(strs, string(value))
+	}
+
+	return strs
+}
+
+func joinEnum[T ~string](values []T) string {
+	return strings.Join(enumStrings(values), ", ")
+}
@@ -0,0 +1,268 @@
+package dashboardtypes
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/SigNoz/signoz/pkg/errors"
+	qbtypes "github.com/SigNoz/signoz/pkg/types/querybuildertypes/querybuildertypesv5"
+	"github.com/SigNoz/signoz/pkg/valuer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const v4Dashboard = `{
+	"version": "v4",
+	"title": "redis",
+	"layout": [{"i": "a", "x": 0, "y": 0, "w": 6, "h": 3}],
+	"widgets": [{
+		"id": "a",
+		"title": "hits",
+		"panelTypes": "graph",
+		"query": {
+			"queryType": "builder",
+			"builder": {
+				"queryData": [{
+					"queryName": "A",
+					"dataSource": "metrics",
+					"disabled": false,
+					"expression": "A",
+					"aggregateOperator": "sum_rate",
+					"aggregateAttribute": {"key": "redis_keyspace_hits", "dataType": "float64", "type": "Sum"},
+					"filters": {"items": [{"key": {"key": "host_name"}, "op": "in", "value": ["{{.host_name}}"]}, {"key": {"key": "db"}, "op": "=", "value": "0"}], "op": "AND"},
+					"groupBy": [{"key": "host_name"}],
+					"limit": null,
+					"stepInterval": 60
+				}],
+				"queryFormulas": []
+			},
+			"promql": [{"name": "A", "query": "", "disabled": false}],
+			"clickhouse_sql": [{"name": "A", "query": "", "disabled": false}]
+		}
+	}],
+	"variables": {"b": {"id": "b", "name": "host_name", "type": "QUERY", "queryValue": "SELECT 1", "sort": "ASC", "multiSelect": true}}
+}`
+
+func newTestData(t *testing.T, raw string) StorableDashboardData {
+	data := StorableDashboardData{}
+	require.NoError(t, json.Unmarshal([]byte(raw), &data))
+	return data
+}
+
+func TestStorableDashboardDataValidate(t *testing.T) {
+	testCases := []struct {
+		name    string
+		mutate  func(data StorableDashboardData)
+		message string
+	}{
+		{
+			name:   "Valid",
+			mutate: func(data StorableDashboardData) {},
+		},
+		{
+			name:    "MissingTitle",
+			mutate:  func(data StorableDashboardData) { delete(data, "title") },
+			message: "title is required",
+		},
+		{
+			name:    "UnknownVersion",
+			mutate:  func(data StorableDashboardData) { data["version"] = "v9" },
+			message: `unknown version "v9"`,
+		},
+		{
+			name:    "WrongType",
+			mutate:  func(data StorableDashboardData) { data["title"] = 1 },
+			message: "title must be of type string, got number",
+		},
+		{
+			name:    "UnknownPanelType",
+			mutate:  func(data StorableDashboardData) { widget(data)["panelTypes"] = "gauge" },
+			message: `widgets[0].panelTypes "gauge" must be one of`,
+		},
+		{
+			name:    "UnknownDataSource",
+			mutate:  func(data StorableDashboardData) { builderQuery(data)["dataSource"] = "events" },
+			message: `widgets[0].query.builder.queryData[0].dataSource "events" must be one of metrics, logs, traces`,
+		},
+		{
+			name: "EmptyPromQL",
+			mutate: func(data StorableDashboardData) {
+				widget(data)["query"].(map[string]any)["queryType"] = "promql"
+			},
+			message: "widgets[0].query.promql[0].query is required for enabled queries",
+		},
+		{
+			name: "LayoutOfUnknownWidget",
+			mutate: func(data StorableDashboardData) {
+				data["layout"].([]any)[0].(map[string]any)["i"] = "z"
+			},
+			message: `layout[0].i "z" is not the id of a widget`,
+		},
+		{
+			name: "UnknownVariableType",
+			mutate: func(data StorableDashboardData) {
+				data["variables"].(map[string]any)["b"].(map[string]any)["type"] = "DYNAMIC"
+			},
+			message: `variables["b"].type "DYNAMIC" must be one of QUERY, TEXTBOX, CUSTOM`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data := newTestData(t, v4Dashboard)
+			tc.mutate(data)
+
+			err := data.Validate()
+			if tc.message == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			assert.True(t, errors.Asc(err, ErrCodeDashboardInvalid))
+			_, _, message, _, _, _ := errors.Unwrapb(err)
+			assert.Contains(t, message, tc.message)
+		})
+	}
+}
+
+func TestStorableDashboardDataUpgrade(t *testing.T) {
+	t.Run("V3ToV4", func(t *testing.T) {
+		data := newTestData(t, `{
+			"title": "redis",
+			"widgets": [{"id": "a", "panelTypes": "row"}, {"id": "b", "panelTypes": "row"}],
+			"layout": [{"i": "a", "x": 0, "y": 0, "w": 12, "h": 1}],
+			"variables": [{"id": "c", "name": "host_name", "type": "TEXTBOX"}]
+		}`)
+
+		upgraded, err := data.Upgrade(SchemaVersionV4)
+		require.NoError(t, err)
+		assert.Equal(t, "v4", upgraded["version"])
+		assert.Contains(t, upgraded["variables"], "c")
+		require.Len(t, upgraded["layout"], 2)
+		assert.Equal(t, "b", upgraded["layout"].([]any)[1].(map[string]any)["i"])
+		assert.NoError(t, upgraded.Validate())
+
+		// the data of the request is left as it is
+		assert.Nil(t, data["version"])
+	})
+
+	t.Run("V4ToV5", func(t *testing.T) {
+		upgraded, err := newTestData(t, v4Dashboard).Upgrade(SchemaVersionV5)
+		require.NoError(t, err)
+		assert.Equal(t, "v5", upgraded["version"])
+		require.NoError(t, upgraded.Validate())
+
+		queries := widget(upgraded)["query"].(map[string]any)["queries"].([]any)
+		require.Len(t, queries, 1)
+		spec := queries[0].(map[string]any)["spec"].(map[string]any)
+		assert.Equal(t, "metrics", spec["signal"])
+		assert.Equal(t, map[string]any{"expression": "host_name IN [$host_name] AND db = '0'"}, spec["filter"])
+		assert.Equal(t, []any{map[string]any{"metricName": "redis_keyspace_hits", "timeAggregation": "rate", "spaceAggregation": "sum"}}, spec["aggregations"])
+	})
+
+	t.Run("InvalidV5Query", func(t *testing.T) {
+		upgraded, err := newTestData(t, v4Dashboard).Upgrade(SchemaVersionV5)
+		require.NoError(t, err)
+
+		queries := widget(upgraded)["query"].(map[string]any)["queries"].([]any)
+		queries[0].(map[string]any)["spec"].(map[string]any)["signal"] = "events"
+
+		err = upgraded.Validate()
+		require.Error(t, err)
+		_, _, message, _, _, _ := errors.Unwrapb(err)
+		assert.Contains(t, message, "widgets[0].query.queries[0]")
+	})
+
+	t.Run("Later", func(t *testing.T) {
+		upgraded, err := newTestData(t, v4Dashboard).Upgrade(SchemaVersionV3)
+		require.NoError(t, err)
+		assert.Equal(t, "v4", upgraded["version"])
+	})
+}
+
+func TestWidgetQueryCompositeQuery(t *testing.T) {
+	v4, err := NewSpec(newTestData(t, v4Dashboard))
+	require.NoError(t, err)
+
+	upgraded, err := newTestData(t, v4Dashboard).Upgrade(SchemaVersionV5)
+	require.NoError(t, err)
+	v5, err := NewSpec(upgraded)
+	require.NoError(t, err)
+
+	// the envelopes of a v4 widget are derived the way the upgrade derives them
+	for _, spec := range []*Spec{v4, v5} {
+		composite, err := spec.Widgets[0].Query.CompositeQuery()
+		require.NoError(t, err)
+		require.Len(t, composite.Queries, 1)
+		assert.Equal(t, qbtypes.QueryTypeBuilder, composite.Queries[0].Type)
+		assert.NoError(t, composite.Validate(spec.Widgets[0].PanelType.RequestType()))
+	}
+}
+
+func TestSpecQueryTexts(t *testing.T) {
+	v4, err := NewSpec(newTestData(t, v4Dashboard))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"redis_keyspace_hits", "A"}, v4.QueryTexts())
+
+	upgraded, err := newTestData(t, v4Dashboard).Upgrade(SchemaVersionV5)
+	require.NoError(t, err)
+	v5, err := NewSpec(upgraded)
+	require.NoError(t, err)
+	assert.Contains(t, v5.QueryTexts(), "redis_keyspace_hits")
+}
+
+func TestNewJSONSchema(t *testing.T) {
+	schema := NewJSONSchema(SchemaVersionV5)
+	_, err := json.Marshal(schema)
+	require.NoError(t, err)
+
+	properties := schema["properties"].(map[string]any)
+	assert.Equal(t, map[string]any{"type": "string", "const": "v5"}, properties["version"])
+	assert.Equal(t, []string{"title"}, schema["required"])
+
+	widgetProperties := properties["widgets"].(map[string]any)["items"].(map[string]any)["properties"].(map[string]any)
+	assert.Equal(t, panelTypes, toPanelTypes(widgetProperties["panelTypes"].(map[string]any)["enum"].([]string)))
+
+	queryProperties := widgetProperties["query"].(map[string]any)["properties"].(map[string]any)
+	assert.Contains(t, queryProperties, "queries")
+	assert.NotContains(t, queryProperties, "builder")
+
+	queryProperties = NewJSONSchema(SchemaVersionV4)["properties"].(map[string]any)["widgets"].(map[string]any)["items"].(map[string]any)["properties"].(map[string]any)["query"].(map[string]any)["properties"].(map[string]any)
+	assert.Contains(t, queryProperties, "builder")
+	assert.NotContains(t, queryProperties, "queries")
+}
+
+func widget(data StorableDashboardData) map[string]any {
+	return data["widgets"].([]any)[0].(map[string]any)
+}
+
+func builderQuery(data StorableDashboardData) map[string]any {
+	return widget(data)["query"].(map[string]any)["builder"].(map[string]any)["queryData"].([]any)[0].(map[string]any)
+}
+
+func toPanelTypes(values []string) []PanelType {
+	panelTypes := make([]PanelType, 0, len(values))
+	for _, value := range values {
+		panelTypes = append(panelTypes, PanelType(value))
+	}
+
+	return panelTypes
+}
+
+func TestDashboardUpdateLegacyViolations(t *testing.T) {
+	dashboard, err := NewDashboard(valuer.GenerateUUID(), "jane@example.com", newTestData(t, v4Dashboard))
+	require.NoError(t, err)
+
+	// the violations of updates to valid dashboards fail the update
+	invalid := newTestData(t, v4Dashboard)
+	invalid["title"] = ""
+	assert.True(t, errors.Asc(dashboard.Update(invalid, "jane@example.com"), ErrCodeDashboardInvalid))
+
+	// the ones of dashboards stored before the schema was enforced are reported instead
+	dashboard.Data = newTestData(t, v4Dashboard)
+	dashboard.Data["widgets"] = append(dashboard.Data["widgets"].([]any), widget(dashboard.Data))
+	require.NoError(t, dashboard.Update(invalid, "jane@example.com"))
+	assert.Len(t, dashboard.Warnings, 1)
+	assert.Equal(t, "", dashboard.Data["title"])
+}
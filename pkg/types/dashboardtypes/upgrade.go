@@ -0,0 +1,409 @@
+package dashboardtypes
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/SigNoz/signoz/pkg/errors"
+	qbtypes "github.com/SigNoz/signoz/pkg/types/querybuildertypes/querybuildertypesv5"
+	"github.com/SigNoz/signoz/pkg/valuer"
+)
+
+// upgraders upgrade the data of a version to the next one.
+var upgraders = map[SchemaVersion]func(StorableDashboardData) error{
+	SchemaVersionV3: upgradeV3ToV4,
+	SchemaVersionV4: upgradeV4ToV5,
+}
+
+// the default size of the widgets laid out on upgrade
+const (
+	defaultWidgetWidth  = 6
+	defaultWidgetHeight = 6
+)
+
+// variables are referenced as {{.name}}, [[name]] or $name in the filters of the builder
+var variableRegex = regexp.MustCompile(`^(?:\{\{\s*\.?(\w+)\s*\}\}|\[\[\s*(\w+)\s*\]\]|\$(\w+))$`)
+
+var filterOperators = map[string]string{
+	"=":         "=",
+	"!=":        "!=",
+	">":         ">",
+	">=":        ">=",
+	"<":         "<",
+	"<=":        "<=",
+	"in":        "IN",
+	"nin":       "NOT IN",
+	"like":      "LIKE",
+	"nlike":     "NOT LIKE",
+	"ilike":     "ILIKE",
+	"nilike":    "NOT ILIKE",
+	"contains":  "CONTAINS",
+	"ncontains": "NOT CONTAINS",
+	"regex":     "REGEXP",
+	"nregex":    "NOT REGEXP",
+	"exists":    "EXISTS",
+	"nexists":   "NOT EXISTS",
+}
+
+// Upgrade returns a copy of the data upgraded to the version, the data of the version or of a later one is returned
+// as it is.
+func (storableDashboardData StorableDashboardData) Upgrade(version SchemaVersion) (StorableDashboardData, error) {
+	data, err := storableDashboardData.clone()
+	if err != nil {
+		return nil, err
+	}
+
+	current, _ := data["version"].(string)
+	from, err := NewSchemaVersion(current)
+	if err != nil {
+		return nil, err
+	}
+
+	for from.before(version) {
+		if err := upgraders[from](data); err != nil {
+			return nil, err
+		}
+
+		from = schemaVersions[slices.Index(schemaVersions, from)+1]
+		data["version"] = string(from)
+	}
+
+	return data, nil
+}
+
+func (storableDashboardData StorableDashboardData) clone() (StorableDashboardData, error) {
+	raw, err := json.Marshal(storableDashboardData)
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.TypeInvalidInput, ErrCodeDashboardInvalid, "invalid dashboard: cannot encode data")
+	}
+
+	data := StorableDashboardData{}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, errors.Wrapf(err, errors.TypeInvalidInput, ErrCodeDashboardInvalid, "invalid dashboard: cannot decode data")
+	}
+
+	return data, nil
+}
+
+// upgradeV3ToV4 keys the variables listed in an array by their ids and lays out the widgets missing from the layout
+// below the others.
+func upgradeV3ToV4(data StorableDashboardData) error {
+	if variables, ok := data["variables"].([]any); ok {
+		keyed := map[string]any{}
+		for _, item := range variables {
+			variable, ok := item.(map[string]any)
+			if !ok {
+				continue
+			}
+
+			id, _ := variable["id"].(string)
+			if id == "" {
+				id = valuer.GenerateUUID().StringValue()
+				variable["id"] = id
+			}
+
+			keyed[id] = variable
+		}
+
+		data["variables"] = keyed
+	}
+
+	widgets, _ := data["widgets"].([]any)
+	layout, _ := data["layout"].([]any)
+
+	laidOut := map[string]bool{}
+	bottom := 0.0
+	for _, item := range layout {
+		if layoutItem, ok := item.(map[string]any); ok {
+			id, _ := layoutItem["i"].(string)
+			laidOut[id] = true
+
+			y, _ := layoutItem["y"].(float64)
+			h, _ := layoutItem["h"].(float64)
+			bottom = max(bottom, y+h)
+		}
+	}
+
+	for _, item := range widgets {
+		widget, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		id, _ := widget["id"].(string)
+		if id == "" || laidOut[id] {
+			continue
+		}
+
+		layout = append(layout, map[string]any{"i": id, "x": 0, "y": bottom, "w": defaultWidgetWidth, "h": defaultWidgetHeight})
+		laidOut[id] = true
+		bottom += defaultWidgetHeight
+	}
+
+	if len(layout) > 0 {
+		data["layout"] = layout
+	}
+
+	return nil
+}
+
+// upgradeV4ToV5 derives the v5 query envelopes of the widgets from the section of their query type. The sections are
+// kept for the clients still reading them.
+func upgradeV4ToV5(data StorableDashboardData) error {
+	widgets, _ := data["widgets"].([]any)
+	for i, item := range widgets {
+		widget, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		query, ok := widget["query"].(map[string]any)
+		if !ok {
+			continue
+		}
+
+		raw, err := json.Marshal(query)
+		if err != nil {
+			return invalidf(fmt.Sprintf("widgets[%d].query", i), "%s", err.Error())
+		}
+
+		widgetQuery := new(WidgetQuery)
+		if err := json.Unmarshal(raw, widgetQuery); err != nil {
+			return invalidf(fmt.Sprintf("widgets[%d].query", i), "%s", err.Error())
+		}
+
+		query["queries"] = newQueryEnvelopes(widgetQuery)
+	}
+
+	return nil
+}
+
+// CompositeQuery returns the v5 composite query of the widget query, the envelopes are derived from the section of
+// its query type when the query predates v5.
+func (query *WidgetQuery) CompositeQuery() (qbtypes.CompositeQuery, error) {
+	var envelopes any = query.Queries
+	if len(query.Queries) == 0 {
+		envelopes = newQueryEnvelopes(query)
+	}
+
+	raw, err := json.Marshal(envelopes)
+	if err != nil {
+		return qbtypes.CompositeQuery{}, errors.Wrapf(err, errors.TypeInvalidInput, ErrCodeDashboardInvalid, "invalid query: cannot encode queries")
+	}
+
+	composite := qbtypes.CompositeQuery{}
+	if err := json.Unmarshal(raw, &composite.Queries); err != nil {
+		return qbtypes.CompositeQuery{}, errors.Wrapf(err, errors.TypeInvalidInput, ErrCodeDashboardInvalid, "invalid query: %s", err.Error())
+	}
+
+	return composite, nil
+}
+
+func newQueryEnvelopes(query *WidgetQuery) []any {
+	envelopes := []any{}
+	switch query.QueryType {
+	case QueryTypeBuilder:
+		if query.Builder == nil {
+			return envelopes
+		}
+
+		for _, builderQuery := range query.Builder.QueryData {
+			if builderQuery != nil {
+				envelopes = append(envelopes, newBuilderQueryEnvelope(builderQuery))
+			}
+		}
+
+		for _, formula := range query.Builder.QueryFormulas {
+			if formula == nil || formula.Disabled {
+				continue
+			}
+
+			envelopes = append(envelopes, map[string]any{
+				"type": "builder_formula",
+				"spec": map[string]any{"name": formula.QueryName, "expression": formula.Expression},
+			})
+		}
+	case QueryTypeClickHouseSQL, QueryTypePromQL:
+		queries := query.ClickHouseSQL
+		if query.QueryType == QueryTypePromQL {
+			queries = query.PromQL
+		}
+
+		for _, rawQuery := range queries {
+			// the editor keeps an empty query around for every query type
+			if rawQuery == nil || strings.TrimSpace(rawQuery.Query) == "" {
+				continue
+			}
+
+			envelopes = append(envelopes, map[string]any{
+				"type": string(query.QueryType),
+				"spec": map[string]any{"name": rawQuery.Name, "query": rawQuery.Query, "disabled": rawQuery.Disabled},
+			})
+		}
+	}
+
+	return envelopes
+}
+
+func newBuilderQueryEnvelope(query *BuilderQuery) map[string]any {
+	spec := map[string]any{
+		"name":     query.QueryName,
+		"signal":   string(query.DataSource),
+		"disabled": query.Disabled,
+	}
+
+	if query.StepInterval != nil && *query.StepInterval > 0 {
+		spec["stepInterval"] = *query.StepInterval
+	}
+
+	if aggregation := newAggregation(query); aggregation != nil {
+		spec["aggregations"] = []any{aggregation}
+	}
+
+	if query.Filters != nil {
+		if expression := newFilterExpression(query.Filters); expression != "" {
+			spec["filter"] = map[string]any{"expression": expression}
+		}
+	}
+
+	groupBy := []any{}
+	for _, key := range query.GroupBy {
+		if key != nil && key.Key != "" {
+			groupBy = append(groupBy, map[string]any{"name": key.Key})
+		}
+	}
+	if len(groupBy) > 0 {
+		spec["groupBy"] = groupBy
+	}
+
+	order := []any{}
+	for _, orderBy := range query.OrderBy {
+		if orderBy == nil || orderBy.ColumnName == "" {
+			continue
+		}
+
+		direction := strings.ToLower(orderBy.Order)
+		if direction == "" {
+			direction = "desc"
+		}
+
+		order = append(order, map[string]any{"key": map[string]any{"name": orderBy.ColumnName}, "direction": direction})
+	}
+	if len(order) > 0 {
+		spec["order"] = order
+	}
+
+	if query.Limit != nil && *query.Limit > 0 {
+		spec["limit"] = *query.Limit
+	}
+
+	return map[string]any{"type": "builder_query", "spec": spec}
+}
+
+// newAggregation converts the aggregate operator of the query, nil when the query doesn't aggregate.
+func newAggregation(query *BuilderQuery) map[string]any {
+	operator := query.AggregateOperator
+	attribute := ""
+	if query.AggregateAttribute != nil {
+		attribute = query.AggregateAttribute.Key
+	}
+
+	if query.DataSource == "metrics" {
+		if attribute == "" {
+			return nil
+		}
+
+		timeAggregation, spaceAggregation := query.TimeAggregation, query.SpaceAggregation
+		if timeAggregation == "" && spaceAggregation == "" {
+			switch {
+			case operator == "rate":
+				timeAggregation, spaceAggregation = "rate", "sum"
+			case strings.HasSuffix(operator, "_rate"):
+				timeAggregation, spaceAggregation = "rate", strings.TrimSuffix(operator, "_rate")
+			case operator == "count":
+				timeAggregation, spaceAggregation = "count", "sum"
+			case operator != "" && operator != "noop":
+				timeAggregation, spaceAggregation = operator, operator
+			}
+		}
+
+		return map[string]any{"metricName": attribute, "timeAggregation": timeAggregation, "spaceAggregation": spaceAggregation}
+	}
+
+	switch operator {
+	case "", "noop":
+		return nil
+	case "count", "rate":
+		if attribute == "" {
+			return map[string]any{"expression": operator + "()"}
+		}
+	}
+
+	return map[string]any{"expression": fmt.Sprintf("%s(%s)", operator, attribute)}
+}
+
+func newFilterExpression(filters *BuilderFilters) string {
+	conditions := []string{}
+	for _, item := range filters.Items {
+		if item == nil || item.Key == nil || item.Key.Key == "" {
+			continue
+		}
+
+		operator, ok := filterOperators[strings.ToLower(item.Op)]
+		if !ok {
+			operator = strings.ToUpper(item.Op)
+		}
+
+		switch operator {
+		case "EXISTS", "NOT EXISTS":
+			conditions = append(conditions, fmt.Sprintf("%s %s", item.Key.Key, operator))
+		case "IN", "NOT IN":
+			values, ok := item.Value.([]any)
+			if !ok {
+				values = []any{item.Value}
+			}
+
+			formatted := make([]string, 0, len(values))
+			for _, value := range values {
+				formatted = append(formatted, formatFilterValue(value))
+			}
+
+			conditions = append(conditions, fmt.Sprintf("%s %s [%s]", item.Key.Key, operator, strings.Join(formatted, ", ")))
+		default:
+			value := item.Value
+			if values, ok := value.([]any); ok && len(values) > 0 {
+				value = values[0]
+			}
+
+			conditions = append(conditions, fmt.Sprintf("%s %s %s", item.Key.Key, operator, formatFilterValue(value)))
+		}
+	}
+
+	join := " AND "
+	if strings.EqualFold(filters.Op, "OR") {
+		join = " OR "
+	}
+
+	return strings.Join(conditions, join)
+}
+
+func formatFilterValue(value any) string {
+	switch value := value.(type) {
+	case string:
+		if matches := variableRegex.FindStringSubmatch(value); matches != nil {
+			return "$" + matches[1] + matches[2] + matches[3]
+		}
+
+		return "'" + strings.ReplaceAll(value, "'", `\'`) + "'"
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case nil:
+		return "''"
+	default:
+		return fmt.Sprint(value)
+	}
+}
@@ -0,0 +1,212 @@
+package derivedmetrictypes
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/errors"
+	"github.com/SigNoz/signoz/pkg/types"
+	qbtypes "github.com/SigNoz/signoz/pkg/types/querybuildertypes/querybuildertypesv5"
+	"github.com/SigNoz/signoz/pkg/types/telemetrytypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+	"github.com/uptrace/bun"
+)
+
+var (
+	ErrCodeDerivedMetricAlreadyExists = errors.MustNewCode("derived_metric_already_exists")
+	ErrCodeDerivedMetricNotFound      = errors.MustNewCode("derived_metric_not_found")
+)
+
+var (
+	metricNameRegex  = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:.]*$`)
+	aggregationRegex = regexp.MustCompile(`^(?i)\s*(count|sum)\s*\(\s*([^()]*?)\s*\)\s*$`)
+)
+
+type AggregationFunction struct{ valuer.String }
+
+var (
+	AggregationFunctionCount = AggregationFunction{valuer.NewString("count")}
+	AggregationFunctionSum   = AggregationFunction{valuer.NewString("sum")}
+)
+
+// Aggregation is the parsed form of the single aggregation of a derived metric,
+// `count()` or `sum(<numeric attribute>)`.
+type Aggregation struct {
+	Function AggregationFunction
+	// key summed up for `sum`, nil for `count`
+	Key *telemetrytypes.TelemetryFieldKey
+}
+
+// StorableDerivedMetric is a revision of a derived metric. Revisions are never updated so
+// that the earlier agent config versions keep deploying the metric they were created with.
+type StorableDerivedMetric struct {
+	bun.BaseModel `bun:"table:derived_metric_revision"`
+
+	types.Identifiable
+	types.TimeAuditable
+	types.UserAuditable
+	// id of the metric shared by all its revisions
+	DefinitionID valuer.UUID `bun:"definition_id,type:text,notnull"`
+	OrgID        string      `bun:"org_id,type:text,notnull"`
+	Name         string      `bun:"name,type:text,notnull"`
+	Description  string      `bun:"description,type:text"`
+	Query        string      `bun:"query,type:text,notnull"`
+	Enabled      bool        `bun:"enabled,notnull"`
+}
+
+// GettableDerivedMetric is a metric computed from logs by the collectors.
+type GettableDerivedMetric struct {
+	types.Identifiable
+	types.TimeAuditable
+	types.UserAuditable
+	Name        string                                            `json:"name"`
+	Description string                                            `json:"description"`
+	Query       qbtypes.QueryBuilderQuery[qbtypes.LogAggregation] `json:"query"`
+	Enabled     bool                                              `json:"enabled"`
+}
+
+type PostableDerivedMetric struct {
+	Name        string                                            `json:"name"`
+	Description string                                            `json:"description"`
+	Query       qbtypes.QueryBuilderQuery[qbtypes.LogAggregation] `json:"query"`
+	Enabled     bool                                              `json:"enabled"`
+}
+
+// Validate checks that the derived metric can be computed continuously from
+// individual log records. Only the filter, group by and a single aggregation of the
+// logs builder query are used, aggregations across records other than count and sum
+// can not be materialised without losing information.
+func (p *PostableDerivedMetric) Validate() error {
+	if !metricNameRegex.MatchString(p.Name) {
+		return errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "invalid metric name %q, metric names must match %s", p.Name, metricNameRegex.String())
+	}
+
+	if p.Query.Signal != telemetrytypes.SignalLogs {
+		return errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "derived metrics can only be computed from logs, got signal %q", p.Query.Signal.StringValue())
+	}
+
+	if len(p.Query.Aggregations) != 1 {
+		return errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "derived metrics must have exactly one aggregation, got %d", len(p.Query.Aggregations))
+	}
+
+	if _, err := ParseAggregation(p.Query.Aggregations[0].Expression); err != nil {
+		return err
+	}
+
+	for _, key := range p.Query.GroupBy {
+		if key.Name == "" {
+			return errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "group by keys of derived metrics can not be empty")
+		}
+	}
+
+	return nil
+}
+
+// ParseAggregation parses an aggregation expression supported by derived metrics.
+func ParseAggregation(expression string) (*Aggregation, error) {
+	matches := aggregationRegex.FindStringSubmatch(expression)
+	if matches == nil {
+		return nil, errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "unsupported aggregation %q, derived metrics support count() and sum(<attribute>)", expression)
+	}
+
+	function := AggregationFunction{valuer.NewString(strings.ToLower(matches[1]))}
+	argument := matches[2]
+
+	switch function {
+	case AggregationFunctionCount:
+		if argument != "" {
+			return nil, errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "count of derived metrics does not take an argument, got %q", argument)
+		}
+		return &Aggregation{Function: function}, nil
+	default:
+		if argument == "" {
+			return nil, errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "sum of derived metrics needs the attribute to sum up")
+		}
+		key := telemetrytypes.GetFieldKeyFromKeyText(argument)
+		return &Aggregation{Function: function, Key: &key}, nil
+	}
+}
+
+func NewStorableDerivedMetric(orgID valuer.UUID, createdBy string, postable *PostableDerivedMetric) (*StorableDerivedMetric, error) {
+	query, err := json.Marshal(postable.Query)
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.TypeInternal, errors.CodeInternal, "failed to serialize the query of the derived metric")
+	}
+
+	id := valuer.GenerateUUID()
+	return &StorableDerivedMetric{
+		Identifiable: types.Identifiable{ID: id},
+		DefinitionID: id,
+		TimeAuditable: types.TimeAuditable{
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		},
+		UserAuditable: types.UserAuditable{
+			CreatedBy: createdBy,
+			UpdatedBy: createdBy,
+		},
+		OrgID:       orgID.StringValue(),
+		Name:        postable.Name,
+		Description: postable.Description,
+		Query:       string(query),
+		Enabled:     postable.Enabled,
+	}, nil
+}
+
+// ElementID is the id of the revision in the agent config versions it is an element of.
+func (s *StorableDerivedMetric) ElementID() valuer.UUID {
+	return s.ID
+}
+
+func (s *StorableDerivedMetric) ElementDefinitionID() valuer.UUID {
+	return s.DefinitionID
+}
+
+func (s *StorableDerivedMetric) ElementName() string {
+	return s.Name
+}
+
+// NewRevision returns a new revision of the metric with the definition in `postable`.
+func (s *StorableDerivedMetric) NewRevision(updatedBy string, postable *PostableDerivedMetric) (*StorableDerivedMetric, error) {
+	query, err := json.Marshal(postable.Query)
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.TypeInternal, errors.CodeInternal, "failed to serialize the query of the derived metric")
+	}
+
+	return &StorableDerivedMetric{
+		Identifiable: types.Identifiable{ID: valuer.GenerateUUID()},
+		DefinitionID: s.DefinitionID,
+		TimeAuditable: types.TimeAuditable{
+			CreatedAt: s.CreatedAt,
+			UpdatedAt: time.Now(),
+		},
+		UserAuditable: types.UserAuditable{
+			CreatedBy: s.CreatedBy,
+			UpdatedBy: updatedBy,
+		},
+		OrgID:       s.OrgID,
+		Name:        postable.Name,
+		Description: postable.Description,
+		Query:       string(query),
+		Enabled:     postable.Enabled,
+	}, nil
+}
+
+func NewGettableDerivedMetric(storable *StorableDerivedMetric) (*GettableDerivedMetric, error) {
+	gettable := &GettableDerivedMetric{
+		Identifiable:  types.Identifiable{ID: storable.DefinitionID},
+		TimeAuditable: storable.TimeAuditable,
+		UserAuditable: storable.UserAuditable,
+		Name:          storable.Name,
+		Description:   storable.Description,
+		Enabled:       storable.Enabled,
+	}
+
+	if err := json.Unmarshal([]byte(storable.Query), &gettable.Query); err != nil {
+		return nil, errors.Wrapf(err, errors.TypeInternal, errors.CodeInternal, "failed to parse the query of derived metric %s", storable.Name)
+	}
+
+	return gettable, nil
+}
@@ -0,0 +1,55 @@
+package derivedmetrictypes
+
+import (
+	"testing"
+
+	qbtypes "github.com/SigNoz/signoz/pkg/types/querybuildertypes/querybuildertypesv5"
+	"github.com/SigNoz/signoz/pkg/types/telemetrytypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAggregation(t *testing.T) {
+	aggregation, err := ParseAggregation("count()")
+	require.NoError(t, err)
+	assert.Equal(t, AggregationFunctionCount, aggregation.Function)
+	assert.Nil(t, aggregation.Key)
+
+	aggregation, err = ParseAggregation(" SUM( attribute.bytes ) ")
+	require.NoError(t, err)
+	assert.Equal(t, AggregationFunctionSum, aggregation.Function)
+	assert.Equal(t, "bytes", aggregation.Key.Name)
+	assert.Equal(t, telemetrytypes.FieldContextAttribute, aggregation.Key.FieldContext)
+
+	for _, expression := range []string{"count(bytes)", "sum()", "avg(bytes)", "p99(duration)", "countIf(a > 1)"} {
+		_, err := ParseAggregation(expression)
+		assert.Error(t, err, expression)
+	}
+}
+
+func TestPostableDerivedMetricValidate(t *testing.T) {
+	valid := func() PostableDerivedMetric {
+		return PostableDerivedMetric{
+			Name: "checkout.errors",
+			Query: qbtypes.QueryBuilderQuery[qbtypes.LogAggregation]{
+				Signal:       telemetrytypes.SignalLogs,
+				Aggregations: []qbtypes.LogAggregation{{Expression: "count()"}},
+			},
+		}
+	}
+
+	metric := valid()
+	assert.NoError(t, metric.Validate())
+
+	metric = valid()
+	metric.Name = "1invalid name"
+	assert.Error(t, metric.Validate())
+
+	metric = valid()
+	metric.Query.Signal = telemetrytypes.SignalTraces
+	assert.Error(t, metric.Validate())
+
+	metric = valid()
+	metric.Query.Aggregations = append(metric.Query.Aggregations, qbtypes.LogAggregation{Expression: "sum(bytes)"})
+	assert.Error(t, metric.Validate())
+}
@@ -28,6 +28,7 @@ type SSOType string
 const (
 	SAML       SSOType = "SAML"
 	GoogleAuth SSOType = "GOOGLE_AUTH"
+	OIDC       SSOType = "OIDC"
 )
 
 // GettableOrgDomain identify org owned web domains for auth and other purposes
@@ -39,6 +40,10 @@ type GettableOrgDomain struct {
 
 	SamlConfig       *ssotypes.SamlConfig        `json:"samlConfig"`
 	GoogleAuthConfig *ssotypes.GoogleOAuthConfig `json:"googleAuthConfig"`
+	OIDCConfig       *ssotypes.OIDCConfig        `json:"oidcConfig"`
+
+	// optional, roles of the sso users are managed by the identity provider when set
+	RoleMapping *RoleMapping `json:"roleMapping"`
 
 	Org *Organization
 }
@@ -72,7 +77,26 @@ func (od *GettableOrgDomain) ValidNew() error {
 		return fmt.Errorf("name is required")
 	}
 
-	return nil
+	return od.ValidSsoConfig()
+}
+
+// ValidSsoConfig checks the config of the sso type of the domain
+func (od *GettableOrgDomain) ValidSsoConfig() error {
+	if od.RoleMapping != nil {
+		if err := od.RoleMapping.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if od.SsoType != OIDC {
+		return nil
+	}
+
+	if od.OIDCConfig == nil {
+		return fmt.Errorf("oidcConfig is required for OIDC domains")
+	}
+
+	return od.OIDCConfig.Validate()
 }
 
 // LoadConfig loads config params from json text
@@ -117,6 +141,28 @@ func (od *GettableOrgDomain) PrepareGoogleOAuthProvider(siteUrl *url.URL) (ssoty
 	return od.GoogleAuthConfig.GetProvider(od.Name, siteUrl)
 }
 
+// PrepareOIDCProvider creates the provider of the generic OpenID Connect
+// config of the domain
+func (od *GettableOrgDomain) PrepareOIDCProvider(siteUrl *url.URL) (ssotypes.OAuthCallbackProvider, error) {
+	if od.OIDCConfig == nil {
+		return nil, fmt.Errorf("OIDC is not setup correctly for this domain")
+	}
+
+	return od.OIDCConfig.GetProvider(od.Name, siteUrl)
+}
+
+// PrepareOAuthCallbackProvider creates the provider of the OAuth style sso type of the domain
+func (od *GettableOrgDomain) PrepareOAuthCallbackProvider(siteUrl *url.URL) (ssotypes.OAuthCallbackProvider, error) {
+	switch od.SsoType {
+	case GoogleAuth:
+		return od.PrepareGoogleOAuthProvider(siteUrl)
+	case OIDC:
+		return od.PrepareOIDCProvider(siteUrl)
+	default:
+		return nil, fmt.Errorf("sso type %s of the domain does not use OAuth", od.SsoType)
+	}
+}
+
 // PrepareSamlRequest creates a request accordingly gosaml2
 func (od *GettableOrgDomain) PrepareSamlRequest(siteUrl *url.URL) (*saml2.SAMLServiceProvider, error) {
 
@@ -180,6 +226,14 @@ func (od *GettableOrgDomain) BuildSsoUrl(siteUrl *url.URL) (ssoUrl string, err e
 		}
 		return googleProvider.BuildAuthURL(relayState)
 
+	case OIDC:
+
+		oidcProvider, err := od.PrepareOIDCProvider(siteUrl)
+		if err != nil {
+			return "", err
+		}
+		return oidcProvider.BuildAuthURL(relayState)
+
 	default:
 		return "", fmt.Errorf("unsupported SSO config for the domain")
 	}
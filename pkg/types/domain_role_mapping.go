@@ -0,0 +1,75 @@
+package types
+
+import (
+	"fmt"
+)
+
+const defaultGroupsAttribute = "groups"
+
+var roleRanks = map[Role]int{
+	RoleViewer: 1,
+	RoleEditor: 2,
+	RoleAdmin:  3,
+}
+
+// RoleMapping maps the groups the identity provider of a domain puts users in to SigNoz roles.
+// The role of a user is evaluated again at every login.
+type RoleMapping struct {
+	// SAML attribute or OIDC claim listing the groups of the user, defaults to `groups`
+	GroupsAttribute string `json:"groupsAttribute"`
+	// users in several mapped groups get the most privileged of their roles
+	GroupRoles map[string]Role `json:"groupRoles"`
+	// role of the users in none of the mapped groups, they keep their current role
+	// (VIEWER for new users) when not set
+	DefaultRole Role `json:"defaultRole,omitempty"`
+	// deny the login of the users in none of the mapped groups
+	DenyUnmatched bool `json:"denyUnmatched"`
+}
+
+func (m *RoleMapping) Validate() error {
+	if len(m.GroupRoles) == 0 {
+		return fmt.Errorf("role mapping must map at least one group")
+	}
+
+	for group, role := range m.GroupRoles {
+		if group == "" {
+			return fmt.Errorf("group names of role mapping can not be empty")
+		}
+		if _, err := NewRole(role.String()); err != nil {
+			return fmt.Errorf("invalid role %q for group %s", role, group)
+		}
+	}
+
+	if m.DefaultRole != "" {
+		if _, err := NewRole(m.DefaultRole.String()); err != nil {
+			return fmt.Errorf("invalid default role %q", m.DefaultRole)
+		}
+	}
+
+	return nil
+}
+
+func (m *RoleMapping) GetGroupsAttribute() string {
+	if m.GroupsAttribute == "" {
+		return defaultGroupsAttribute
+	}
+	return m.GroupsAttribute
+}
+
+// Resolve returns the role of a user in `groups` and whether any of them is mapped. The default
+// role, possibly empty, is returned when none is.
+func (m *RoleMapping) Resolve(groups []string) (Role, bool) {
+	var resolved Role
+	for _, group := range groups {
+		role, ok := m.GroupRoles[group]
+		if ok && roleRanks[role] > roleRanks[resolved] {
+			resolved = role
+		}
+	}
+
+	if resolved == "" {
+		return m.DefaultRole, false
+	}
+
+	return resolved, true
+}
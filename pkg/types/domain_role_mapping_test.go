@@ -0,0 +1,55 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoleMappingResolve(t *testing.T) {
+	mapping := &RoleMapping{
+		GroupRoles: map[string]Role{
+			"signoz-admins":  RoleAdmin,
+			"sre":            RoleEditor,
+			"engineering":    RoleViewer,
+			"on-call-rotors": RoleEditor,
+		},
+	}
+
+	testCases := []struct {
+		name    string
+		groups  []string
+		dflt    Role
+		role    Role
+		matched bool
+	}{
+		{name: "Single", groups: []string{"sre"}, role: RoleEditor, matched: true},
+		{name: "MostPrivileged", groups: []string{"engineering", "signoz-admins", "sre"}, role: RoleAdmin, matched: true},
+		{name: "UnmappedGroupsIgnored", groups: []string{"finance", "engineering"}, role: RoleViewer, matched: true},
+		{name: "NoneMapped", groups: []string{"finance"}, role: "", matched: false},
+		{name: "NoneMappedDefault", groups: nil, dflt: RoleViewer, role: RoleViewer, matched: false},
+		{name: "CaseSensitive", groups: []string{"SRE"}, role: "", matched: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mapping.DefaultRole = tc.dflt
+			role, matched := mapping.Resolve(tc.groups)
+			assert.Equal(t, tc.role, role)
+			assert.Equal(t, tc.matched, matched)
+		})
+	}
+}
+
+func TestRoleMappingValidate(t *testing.T) {
+	mapping := &RoleMapping{}
+	require.NoError(t, json.Unmarshal([]byte(`{"groupRoles": {"sre": "EDITOR"}, "denyUnmatched": true}`), mapping))
+	assert.NoError(t, mapping.Validate())
+	assert.Equal(t, "groups", mapping.GetGroupsAttribute())
+
+	assert.Error(t, json.Unmarshal([]byte(`{"groupRoles": {"sre": "OWNER"}}`), &RoleMapping{}))
+	assert.Error(t, (&RoleMapping{}).Validate())
+	assert.Error(t, (&RoleMapping{GroupRoles: map[string]Role{"": RoleViewer}}).Validate())
+}
@@ -0,0 +1,10 @@
+package emailtypes
+
+// Attachment is a file attached to an email. Inline attachments are not listed as attachments, they are referenced
+// from the HTML of the email by their name with cid:<name>.
+type Attachment struct {
+	Name        string
+	ContentType string
+	Data        []byte
+	Inline      bool
+}
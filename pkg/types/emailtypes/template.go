@@ -12,11 +12,12 @@ import (
 var (
 	// Templates is a list of all the templates that are supported by the emailing service.
 	// This list should be updated whenever a new template is added.
-	Templates = []TemplateName{TemplateNameInvitationEmail}
+	Templates = []TemplateName{TemplateNameInvitationEmail, TemplateNameReportEmail}
 )
 
 var (
 	TemplateNameInvitationEmail = TemplateName{valuer.NewString("invitation_email")}
+	TemplateNameReportEmail     = TemplateName{valuer.NewString("report_email")}
 )
 
 type TemplateName struct{ valuer.String }
@@ -25,6 +26,8 @@ func NewTemplateName(name string) (TemplateName, error) {
 	switch name {
 	case TemplateNameInvitationEmail.StringValue():
 		return TemplateNameInvitationEmail, nil
+	case TemplateNameReportEmail.StringValue():
+		return TemplateNameReportEmail, nil
 	default:
 		return TemplateName{}, errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "invalid template name: %s", name)
 	}
@@ -3,6 +3,10 @@ package types
 import (
 	"crypto/rand"
 	"encoding/base64"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/SigNoz/signoz/pkg/errors"
@@ -12,35 +16,95 @@ import (
 
 var NEVER_EXPIRES = time.Unix(0, 0)
 
+// APIKeyScope restricts an api key to the routes of one area, on top of what its role allows.
+type APIKeyScope struct{ valuer.String }
+
+var (
+	// running queries and reading their results
+	APIKeyScopeQueryRead = APIKeyScope{valuer.NewString("query:read")}
+	// managing the logs ingestion pipelines
+	APIKeyScopePipelinesManage = APIKeyScope{valuer.NewString("pipelines:manage")}
+	// managing alert rules, limited to the rules with the rule labels of the key when set, such keys can't list the rules
+	APIKeyScopeRulesManage = APIKeyScope{valuer.NewString("rules:manage")}
+)
+
+type apiKeyScopeRoute struct {
+	methods []string
+	// a trailing `/*` matches any path below it
+	path string
+}
+
+var apiKeyScopeRoutes = map[APIKeyScope][]apiKeyScopeRoute{
+	APIKeyScopeQueryRead: {
+		{methods: []string{http.MethodGet}, path: "/api/v1/query_range"},
+		{methods: []string{http.MethodGet}, path: "/api/v1/query"},
+		{methods: []string{http.MethodPost}, path: "/api/v3/query_range"},
+		{methods: []string{http.MethodPost}, path: "/api/v3/query_range/format"},
+		{methods: []string{http.MethodPost}, path: "/api/v4/query_range"},
+		{methods: []string{http.MethodPost}, path: "/api/v5/query_range"},
+		{methods: []string{http.MethodGet}, path: "/api/v3/autocomplete/*"},
+		{methods: []string{http.MethodPost}, path: "/api/v3/auto_complete/*"},
+		{methods: []string{http.MethodGet}, path: "/api/v3/filter_suggestions"},
+		{methods: []string{http.MethodGet}, path: "/api/v1/fields/*"},
+		{methods: []string{http.MethodGet}, path: "/api/v1/logs"},
+		{methods: []string{http.MethodGet}, path: "/api/v1/logs/tail"},
+		{methods: []string{http.MethodGet}, path: "/api/v1/logs/aggregate"},
+		{methods: []string{http.MethodGet}, path: "/api/v1/traces/*"},
+		{methods: []string{http.MethodPost}, path: "/api/v2/traces/flamegraph/*"},
+		{methods: []string{http.MethodPost}, path: "/api/v2/traces/waterfall/*"},
+	},
+	APIKeyScopePipelinesManage: {
+		{methods: []string{http.MethodGet, http.MethodPost}, path: "/api/v1/logs/pipelines"},
+		{methods: []string{http.MethodGet, http.MethodPost}, path: "/api/v1/logs/pipelines/*"},
+	},
+	APIKeyScopeRulesManage: {
+		{methods: []string{http.MethodGet, http.MethodPost}, path: "/api/v1/rules"},
+		{methods: []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}, path: "/api/v1/rules/*"},
+		{methods: []string{http.MethodPost}, path: "/api/v1/testRule"},
+	},
+}
+
+func (route apiKeyScopeRoute) matches(method string, path string) bool {
+	if !slices.Contains(route.methods, method) {
+		return false
+	}
+
+	if prefix, ok := strings.CutSuffix(route.path, "/*"); ok {
+		return strings.HasPrefix(path, prefix+"/")
+	}
+
+	return strings.TrimSuffix(path, "/") == route.path
+}
+
 type PostableAPIKey struct {
-	Name          string `json:"name"`
-	Role          Role   `json:"role"`
-	ExpiresInDays int64  `json:"expiresInDays"`
+	Name          string        `json:"name"`
+	Role          Role          `json:"role"`
+	ExpiresInDays int64         `json:"expiresInDays"`
+	Scopes        []APIKeyScope `json:"scopes"`
+	// only valid with the rules:manage scope
+	RuleLabels map[string]string `json:"ruleLabels"`
+	// ips or cidr ranges, the key can be used from anywhere when empty
+	AllowedIPs []string `json:"allowedIps"`
 }
 
 type GettableAPIKey struct {
 	Identifiable
 	TimeAuditable
 	UserAuditable
-	Token         string `json:"token"`
-	Role          Role   `json:"role"`
-	Name          string `json:"name"`
-	ExpiresAt     int64  `json:"expiresAt"`
-	LastUsed      int64  `json:"lastUsed"`
-	Revoked       bool   `json:"revoked"`
-	UserID        string `json:"userId"`
-	CreatedByUser *User  `json:"createdByUser"`
-	UpdatedByUser *User  `json:"updatedByUser"`
-}
-
-type OrgUserAPIKey struct {
-	*Organization `bun:",extend"`
-	Users         []*UserWithAPIKey `bun:"rel:has-many,join:id=org_id"`
-}
-
-type UserWithAPIKey struct {
-	*User   `bun:",extend"`
-	APIKeys []*StorableAPIKeyUser `bun:"rel:has-many,join:id=user_id"`
+	Token         string            `json:"token"`
+	Role          Role              `json:"role"`
+	Name          string            `json:"name"`
+	ExpiresAt     int64             `json:"expiresAt"`
+	LastUsed      int64             `json:"lastUsed"`
+	UsageCount    int64             `json:"usageCount"`
+	LastUsedIP    string            `json:"lastUsedIp"`
+	Scopes        []APIKeyScope     `json:"scopes"`
+	RuleLabels    map[string]string `json:"ruleLabels"`
+	AllowedIPs    []string          `json:"allowedIps"`
+	Revoked       bool              `json:"revoked"`
+	UserID        string            `json:"userId"`
+	CreatedByUser *User             `json:"createdByUser"`
+	UpdatedByUser *User             `json:"updatedByUser"`
 }
 
 type StorableAPIKeyUser struct {
@@ -56,16 +120,24 @@ type StorableAPIKey struct {
 	Identifiable
 	TimeAuditable
 	UserAuditable
-	Token     string      `json:"token" bun:"token,type:text,notnull,unique"`
-	Role      Role        `json:"role" bun:"role,type:text,notnull,default:'ADMIN'"`
-	Name      string      `json:"name" bun:"name,type:text,notnull"`
-	ExpiresAt time.Time   `json:"-" bun:"expires_at,notnull,nullzero,type:timestamptz"`
-	LastUsed  time.Time   `json:"-" bun:"last_used,notnull,nullzero,type:timestamptz"`
-	Revoked   bool        `json:"revoked" bun:"revoked,notnull,default:false"`
-	UserID    valuer.UUID `json:"userId" bun:"user_id,type:text,notnull"`
+	Token      string      `json:"token" bun:"token,type:text,notnull,unique"`
+	Role       Role        `json:"role" bun:"role,type:text,notnull,default:'ADMIN'"`
+	Name       string      `json:"name" bun:"name,type:text,notnull"`
+	ExpiresAt  time.Time   `json:"-" bun:"expires_at,notnull,nullzero,type:timestamptz"`
+	LastUsed   time.Time   `json:"-" bun:"last_used,notnull,nullzero,type:timestamptz"`
+	UsageCount int64       `json:"-" bun:"usage_count,notnull,default:0"`
+	LastUsedIP string      `json:"-" bun:"last_used_ip,type:text"`
+	Revoked    bool        `json:"revoked" bun:"revoked,notnull,default:false"`
+	UserID     valuer.UUID `json:"userId" bun:"user_id,type:text,notnull"`
+	// the key acts in this org with the role of the key, capped at the role of its user in the org
+	OrgID valuer.UUID `json:"orgId" bun:"org_id,type:text"`
+	// the key is unrestricted when no scope is set
+	Scopes     []APIKeyScope     `json:"scopes" bun:"scopes,type:text"`
+	RuleLabels map[string]string `json:"ruleLabels" bun:"rule_labels,type:text"`
+	AllowedIPs []string          `json:"allowedIps" bun:"allowed_ips,type:text"`
 }
 
-func NewStorableAPIKey(name string, userID valuer.UUID, role Role, expiresAt int64) (*StorableAPIKey, error) {
+func NewStorableAPIKey(name string, orgID valuer.UUID, userID valuer.UUID, role Role, expiresAt int64) (*StorableAPIKey, error) {
 	// validate
 
 	// we allow the APIKey if expiresAt is not set, which means it never expires
@@ -116,6 +188,7 @@ func NewStorableAPIKey(name string, userID valuer.UUID, role Role, expiresAt int
 		Name:      name,
 		Role:      role,
 		UserID:    userID,
+		OrgID:     orgID,
 		ExpiresAt: expiresAtTime,
 		LastUsed:  now,
 		Revoked:   false,
@@ -136,9 +209,94 @@ func NewGettableAPIKeyFromStorableAPIKey(storableAPIKey *StorableAPIKeyUser) *Ge
 		Name:          storableAPIKey.Name,
 		ExpiresAt:     storableAPIKey.ExpiresAt.Unix(),
 		LastUsed:      lastUsed,
+		UsageCount:    storableAPIKey.UsageCount,
+		LastUsedIP:    storableAPIKey.LastUsedIP,
+		Scopes:        storableAPIKey.Scopes,
+		RuleLabels:    storableAPIKey.RuleLabels,
+		AllowedIPs:    storableAPIKey.AllowedIPs,
 		Revoked:       storableAPIKey.Revoked,
 		UserID:        storableAPIKey.UserID.String(),
 		CreatedByUser: storableAPIKey.CreatedByUser,
 		UpdatedByUser: storableAPIKey.UpdatedByUser,
 	}
 }
+
+// ValidateRestrictions validates the scopes, rule labels and allowed ips of the key.
+func (key *StorableAPIKey) ValidateRestrictions() error {
+	for _, scope := range key.Scopes {
+		if _, ok := apiKeyScopeRoutes[scope]; !ok {
+			return errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "invalid scope: %s", scope.StringValue())
+		}
+	}
+
+	if len(key.RuleLabels) > 0 && !slices.Contains(key.Scopes, APIKeyScopeRulesManage) {
+		return errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "ruleLabels can only be set with the %s scope", APIKeyScopeRulesManage.StringValue())
+	}
+
+	for _, allowedIP := range key.AllowedIPs {
+		if net.ParseIP(allowedIP) != nil {
+			continue
+		}
+
+		if _, _, err := net.ParseCIDR(allowedIP); err != nil {
+			return errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "invalid ip or cidr range: %s", allowedIP)
+		}
+	}
+
+	return nil
+}
+
+// AllowsIP checks the address against the allowed ips of the key.
+func (key *StorableAPIKey) AllowsIP(address string) bool {
+	if len(key.AllowedIPs) == 0 {
+		return true
+	}
+
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return false
+	}
+
+	for _, allowedIP := range key.AllowedIPs {
+		if _, network, err := net.ParseCIDR(allowedIP); err == nil {
+			if network.Contains(ip) {
+				return true
+			}
+			continue
+		}
+
+		if allowed := net.ParseIP(allowedIP); allowed != nil && allowed.Equal(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ScopeOf returns the scope of the key allowing the request, the zero scope is returned for unrestricted keys.
+func (key *StorableAPIKey) ScopeOf(method string, path string) (APIKeyScope, error) {
+	if len(key.Scopes) == 0 {
+		return APIKeyScope{}, nil
+	}
+
+	for _, scope := range key.Scopes {
+		for _, route := range apiKeyScopeRoutes[scope] {
+			if route.matches(method, path) {
+				return scope, nil
+			}
+		}
+	}
+
+	return APIKeyScope{}, errors.Newf(errors.TypeForbidden, errors.CodeForbidden, "the scopes of the api key don't allow %s %s", method, path)
+}
+
+// MatchesRuleLabels checks whether the labels of a rule contain all the rule labels of the key.
+func (key *StorableAPIKey) MatchesRuleLabels(labels map[string]string) bool {
+	for name, value := range key.RuleLabels {
+		if labels[name] != value {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,79 @@
+package types
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStorableAPIKeyScopeOf(t *testing.T) {
+	testCases := []struct {
+		name   string
+		scopes []APIKeyScope
+		method string
+		path   string
+		scope  APIKeyScope
+		pass   bool
+	}{
+		{name: "Unrestricted", scopes: nil, method: http.MethodDelete, path: "/api/v1/user/id", scope: APIKeyScope{}, pass: true},
+		{name: "QueryRange", scopes: []APIKeyScope{APIKeyScopeQueryRead}, method: http.MethodPost, path: "/api/v5/query_range", scope: APIKeyScopeQueryRead, pass: true},
+		{name: "QueryOtherMethod", scopes: []APIKeyScope{APIKeyScopeQueryRead}, method: http.MethodDelete, path: "/api/v5/query_range", pass: false},
+		{name: "QueryDashboards", scopes: []APIKeyScope{APIKeyScopeQueryRead}, method: http.MethodGet, path: "/api/v1/dashboards", pass: false},
+		{name: "QueryLogsPipelines", scopes: []APIKeyScope{APIKeyScopeQueryRead}, method: http.MethodGet, path: "/api/v1/logs/pipelines/latest", pass: false},
+		{name: "Pipelines", scopes: []APIKeyScope{APIKeyScopeQueryRead, APIKeyScopePipelinesManage}, method: http.MethodGet, path: "/api/v1/logs/pipelines/latest", scope: APIKeyScopePipelinesManage, pass: true},
+		{name: "RuleByID", scopes: []APIKeyScope{APIKeyScopeRulesManage}, method: http.MethodPatch, path: "/api/v1/rules/id", scope: APIKeyScopeRulesManage, pass: true},
+		{name: "RulesPrefix", scopes: []APIKeyScope{APIKeyScopeRulesManage}, method: http.MethodGet, path: "/api/v1/rulesets", pass: false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			key := &StorableAPIKey{Scopes: testCase.scopes}
+			scope, err := key.ScopeOf(testCase.method, testCase.path)
+			if !testCase.pass {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.scope, scope)
+		})
+	}
+}
+
+func TestStorableAPIKeyAllowsIP(t *testing.T) {
+	key := &StorableAPIKey{AllowedIPs: []string{"10.0.0.0/8", "192.0.2.7", "2001:db8::/32"}}
+
+	assert.True(t, key.AllowsIP("10.1.2.3"))
+	assert.True(t, key.AllowsIP("192.0.2.7"))
+	assert.True(t, key.AllowsIP("2001:db8::1"))
+	assert.False(t, key.AllowsIP("192.0.2.8"))
+	assert.False(t, key.AllowsIP("not-an-ip"))
+	assert.True(t, (&StorableAPIKey{}).AllowsIP("192.0.2.8"))
+}
+
+func TestStorableAPIKeyValidateRestrictions(t *testing.T) {
+	testCases := []struct {
+		name string
+		key  *StorableAPIKey
+		pass bool
+	}{
+		{name: "Unrestricted", key: &StorableAPIKey{}, pass: true},
+		{name: "Valid", key: &StorableAPIKey{Scopes: []APIKeyScope{APIKeyScopeRulesManage}, RuleLabels: map[string]string{"team": "payments"}, AllowedIPs: []string{"10.0.0.0/8", "::1"}}, pass: true},
+		{name: "InvalidScope", key: &StorableAPIKey{Scopes: []APIKeyScope{{}}}, pass: false},
+		{name: "RuleLabelsWithoutScope", key: &StorableAPIKey{Scopes: []APIKeyScope{APIKeyScopeQueryRead}, RuleLabels: map[string]string{"team": "payments"}}, pass: false},
+		{name: "InvalidIP", key: &StorableAPIKey{AllowedIPs: []string{"10.0.0.0/33"}}, pass: false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := testCase.key.ValidateRestrictions()
+			if testCase.pass {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.Error(t, err)
+		})
+	}
+}
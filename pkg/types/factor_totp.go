@@ -0,0 +1,344 @@
+package types
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/errors"
+	"github.com/SigNoz/signoz/pkg/valuer"
+	"github.com/uptrace/bun"
+)
+
+var (
+	ErrTOTPAlreadyExists = errors.MustNewCode("totp_already_exists")
+	ErrTOTPNotFound      = errors.MustNewCode("totp_not_found")
+	ErrInvalidTOTPCode   = errors.MustNewCode("invalid_totp_code")
+	ErrTOTPLocked        = errors.MustNewCode("totp_locked")
+	ErrMFARequired       = errors.MustNewCode("mfa_required")
+)
+
+const (
+	TOTPIssuer string = "SigNoz"
+
+	// codes are valid for one period before and after the current one to account for clock drift
+	totpDigits int   = 6
+	totpPeriod int64 = 30
+	totpSkew   int64 = 1
+
+	totpSecretSize        int = 20
+	recoveryCodeCount     int = 10
+	maxTOTPFailedAttempts int = 10
+
+	// prefix of the sealed secrets, base32 encoded secrets stored before they were sealed never contain a colon
+	sealedTOTPSecretPrefix string = "v1:"
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+type FactorTOTP struct {
+	bun.BaseModel `bun:"table:factor_totp"`
+
+	Identifiable
+	TimeAuditable
+	// base32 encoded secret, sealed with the key of the module when stored
+	Secret string `bun:"secret,type:text,notnull" json:"-"`
+	// the factor is only enabled once the user verifies a first code
+	Enabled bool `bun:"enabled,type:boolean,notnull" json:"enabled"`
+	// the last time step a code was accepted for, codes of this step or earlier can't be replayed
+	LastUsedStep   int64 `bun:"last_used_step,notnull,default:0" json:"-"`
+	FailedAttempts int   `bun:"failed_attempts,notnull,default:0" json:"-"`
+	// comma separated sha256 hashes of the unused recovery codes
+	RecoveryCodes string `bun:"recovery_codes,type:text,notnull" json:"-"`
+	UserID        string `bun:"user_id,type:text,notnull,unique,references:user(id)" json:"userId"`
+}
+
+type GettableTOTPEnrollment struct {
+	Secret string `json:"secret"`
+	// otpauth uri to be rendered as a qr code for authenticator apps
+	URI           string   `json:"uri"`
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+type GettableMFAStatus struct {
+	Enabled           bool `json:"enabled"`
+	Required          bool `json:"required"`
+	RecoveryCodesLeft int  `json:"recoveryCodesLeft"`
+}
+
+type GettableMFAChallenge struct {
+	Token       string `json:"token"`
+	TokenExpiry int64  `json:"tokenExpiry"`
+	// set when the org requires mfa and the user has not enabled it yet
+	EnrollmentRequired bool `json:"enrollmentRequired"`
+}
+
+type PostableMFAChallenge struct {
+	Token        string `json:"token"`
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recoveryCode"`
+}
+
+type PostableMFAToken struct {
+	Token string `json:"token"`
+}
+
+type PostableTOTPCode struct {
+	Code string `json:"code"`
+}
+
+type GettableRecoveryCodes struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+func (p *PostableMFAChallenge) Validate() error {
+	if p.Token == "" {
+		return errors.New(errors.TypeInvalidInput, errors.CodeInvalidInput, "token is required")
+	}
+
+	if p.Code == "" && p.RecoveryCode == "" {
+		return errors.New(errors.TypeInvalidInput, errors.CodeInvalidInput, "code or recoveryCode is required")
+	}
+
+	return nil
+}
+
+// NewFactorTOTP generates a new secret and recovery codes for the user, the recovery codes are only returned here.
+func NewFactorTOTP(userID string) (*FactorTOTP, []string, error) {
+	secret := make([]byte, totpSecretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, nil, errors.Wrapf(err, errors.TypeInternal, errors.CodeInternal, "failed to generate totp secret")
+	}
+
+	factor := &FactorTOTP{
+		Identifiable: Identifiable{
+			ID: valuer.GenerateUUID(),
+		},
+		TimeAuditable: TimeAuditable{
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		},
+		Secret:  totpEncoding.EncodeToString(secret),
+		Enabled: false,
+		UserID:  userID,
+	}
+
+	recoveryCodes, err := factor.RegenerateRecoveryCodes()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return factor, recoveryCodes, nil
+}
+
+// URI returns the key uri of the factor as understood by authenticator apps.
+func (f *FactorTOTP) URI(email string) string {
+	values := url.Values{}
+	values.Set("secret", f.Secret)
+	values.Set("issuer", TOTPIssuer)
+	values.Set("digits", fmt.Sprint(totpDigits))
+	values.Set("period", fmt.Sprint(totpPeriod))
+
+	return (&url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + TOTPIssuer + ":" + email,
+		RawQuery: values.Encode(),
+	}).String()
+}
+
+// NewTOTPSecretKey derives the key the secrets of the factors are sealed with from the secret of the instance.
+func NewTOTPSecretKey(secret string) []byte {
+	key := sha256.Sum256([]byte("totp:" + secret))
+	return key[:]
+}
+
+// SealSecret encrypts the secret of the factor with the key so that it is not stored in clear.
+func (f *FactorTOTP) SealSecret(key []byte) error {
+	if strings.HasPrefix(f.Secret, sealedTOTPSecretPrefix) {
+		return nil
+	}
+
+	aead, err := newTOTPSecretAEAD(key)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return errors.Wrapf(err, errors.TypeInternal, errors.CodeInternal, "failed to generate nonce of totp secret")
+	}
+
+	sealed := aead.Seal(nonce, nonce, []byte(f.Secret), []byte(f.UserID))
+	f.Secret = sealedTOTPSecretPrefix + base64.StdEncoding.EncodeToString(sealed)
+	return nil
+}
+
+// OpenSecret decrypts the secret sealed with the key. Secrets stored before they were sealed are kept as they are,
+// they get sealed the next time the factor is updated.
+func (f *FactorTOTP) OpenSecret(key []byte) error {
+	encoded, ok := strings.CutPrefix(f.Secret, sealedTOTPSecretPrefix)
+	if !ok {
+		return nil
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return errors.Wrapf(err, errors.TypeInternal, errors.CodeInternal, "failed to decode totp secret")
+	}
+
+	aead, err := newTOTPSecretAEAD(key)
+	if err != nil {
+		return err
+	}
+
+	if len(sealed) < aead.NonceSize() {
+		return errors.New(errors.TypeInternal, errors.CodeInternal, "sealed totp secret is too short")
+	}
+
+	secret, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(f.UserID))
+	if err != nil {
+		return errors.Wrapf(err, errors.TypeInternal, errors.CodeInternal, "failed to open totp secret")
+	}
+
+	f.Secret = string(secret)
+	return nil
+}
+
+// Locked reports whether too many invalid codes were attempted in a row.
+func (f *FactorTOTP) Locked() bool {
+	return f.FailedAttempts >= maxTOTPFailedAttempts
+}
+
+// Verify checks the code against the time steps around now and marks the matching step as used.
+func (f *FactorTOTP) Verify(code string, now time.Time) bool {
+	secret, err := totpEncoding.DecodeString(f.Secret)
+	if err != nil {
+		return false
+	}
+
+	code = strings.ReplaceAll(code, " ", "")
+	step := now.Unix() / totpPeriod
+	for s := step - totpSkew; s <= step+totpSkew; s++ {
+		if s <= f.LastUsedStep {
+			continue
+		}
+
+		if subtle.ConstantTimeCompare([]byte(totpCode(secret, s, totpDigits)), []byte(code)) == 1 {
+			f.LastUsedStep = s
+			return true
+		}
+	}
+
+	return false
+}
+
+// Code returns the code of the factor at the given time.
+func (f *FactorTOTP) Code(now time.Time) (string, error) {
+	secret, err := totpEncoding.DecodeString(f.Secret)
+	if err != nil {
+		return "", errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "invalid totp secret")
+	}
+
+	return totpCode(secret, now.Unix()/totpPeriod, totpDigits), nil
+}
+
+// UseRecoveryCode consumes the recovery code, each code can only be used once.
+func (f *FactorTOTP) UseRecoveryCode(code string) bool {
+	hashes := f.recoveryCodeHashes()
+	hash := hashRecoveryCode(code)
+
+	index := slices.IndexFunc(hashes, func(h string) bool {
+		return subtle.ConstantTimeCompare([]byte(h), []byte(hash)) == 1
+	})
+	if index == -1 {
+		return false
+	}
+
+	f.RecoveryCodes = strings.Join(slices.Delete(hashes, index, index+1), ",")
+	return true
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes of the factor and returns the new ones.
+func (f *FactorTOTP) RegenerateRecoveryCodes() ([]string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		random := make([]byte, 5)
+		if _, err := rand.Read(random); err != nil {
+			return nil, errors.Wrapf(err, errors.TypeInternal, errors.CodeInternal, "failed to generate recovery code")
+		}
+
+		code := strings.ToLower(totpEncoding.EncodeToString(random))
+		codes[i] = code[:4] + "-" + code[4:]
+		hashes[i] = hashRecoveryCode(codes[i])
+	}
+
+	f.RecoveryCodes = strings.Join(hashes, ",")
+	return codes, nil
+}
+
+func (f *FactorTOTP) CountRecoveryCodes() int {
+	return len(f.recoveryCodeHashes())
+}
+
+func (f *FactorTOTP) recoveryCodeHashes() []string {
+	if f.RecoveryCodes == "" {
+		return []string{}
+	}
+
+	return strings.Split(f.RecoveryCodes, ",")
+}
+
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	hash := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(hash[:])
+}
+
+func newTOTPSecretAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.TypeInternal, errors.CodeInternal, "invalid key of totp secrets")
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.TypeInternal, errors.CodeInternal, "invalid key of totp secrets")
+	}
+
+	return aead, nil
+}
+
+// totpCode computes the code of the time step as specified in RFC 6238 with HMAC-SHA1.
+func totpCode(secret []byte, step int64, digits int) string {
+	message := make([]byte, 8)
+	binary.BigEndian.PutUint64(message, uint64(step))
+
+	mac := hmac.New(sha1.New, secret)
+	mac.Write(message)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	modulo := uint32(1)
+	for range digits {
+		modulo *= 10
+	}
+
+	return fmt.Sprintf("%0*d", digits, value%modulo)
+}
@@ -0,0 +1,108 @@
+package types
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTOTPCode(t *testing.T) {
+	// test vectors of RFC 6238 for HMAC-SHA1
+	secret := []byte("12345678901234567890")
+
+	testCases := []struct {
+		time int64
+		code string
+	}{
+		{time: 59, code: "94287082"},
+		{time: 1111111109, code: "07081804"},
+		{time: 1111111111, code: "14050471"},
+		{time: 1234567890, code: "89005924"},
+		{time: 2000000000, code: "69279037"},
+		{time: 20000000000, code: "65353130"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.code, func(t *testing.T) {
+			assert.Equal(t, testCase.code, totpCode(secret, testCase.time/totpPeriod, 8))
+		})
+	}
+}
+
+func TestFactorTOTPVerify(t *testing.T) {
+	factor, _, err := NewFactorTOTP("user")
+	require.NoError(t, err)
+
+	secret, err := totpEncoding.DecodeString(factor.Secret)
+	require.NoError(t, err)
+
+	now := time.Unix(1700000000, 0)
+	step := now.Unix() / totpPeriod
+
+	testCases := []struct {
+		name     string
+		code     string
+		expected bool
+	}{
+		{name: "PreviousStep", code: totpCode(secret, step-1, totpDigits), expected: true},
+		{name: "Replayed", code: totpCode(secret, step-1, totpDigits), expected: false},
+		{name: "CurrentStepWithSpaces", code: totpCode(secret, step, totpDigits)[:3] + " " + totpCode(secret, step, totpDigits)[3:], expected: true},
+		{name: "OutOfSkew", code: totpCode(secret, step+2, totpDigits), expected: false},
+		{name: "Empty", code: "", expected: false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, factor.Verify(testCase.code, now))
+		})
+	}
+}
+
+func TestFactorTOTPRecoveryCodes(t *testing.T) {
+	factor, codes, err := NewFactorTOTP("user")
+	require.NoError(t, err)
+	require.Len(t, codes, recoveryCodeCount)
+	assert.Equal(t, recoveryCodeCount, factor.CountRecoveryCodes())
+	assert.NotContains(t, factor.RecoveryCodes, codes[0])
+
+	assert.True(t, factor.UseRecoveryCode(strings.ToUpper(strings.ReplaceAll(codes[0], "-", ""))))
+	assert.False(t, factor.UseRecoveryCode(codes[0]))
+	assert.Equal(t, recoveryCodeCount-1, factor.CountRecoveryCodes())
+
+	regenerated, err := factor.RegenerateRecoveryCodes()
+	require.NoError(t, err)
+	assert.False(t, factor.UseRecoveryCode(codes[1]))
+	assert.True(t, factor.UseRecoveryCode(regenerated[1]))
+}
+
+func TestFactorTOTPURI(t *testing.T) {
+	factor := &FactorTOTP{Secret: "JBSWY3DPEHPK3PXP"}
+	assert.Equal(t, "otpauth://totp/SigNoz:jane@example.com?digits=6&issuer=SigNoz&period=30&secret=JBSWY3DPEHPK3PXP", factor.URI("jane@example.com"))
+}
+
+func TestFactorTOTPSealSecret(t *testing.T) {
+	factor := &FactorTOTP{Secret: "JBSWY3DPEHPK3PXP", UserID: "jane"}
+	key := NewTOTPSecretKey("secret")
+
+	require.NoError(t, factor.SealSecret(key))
+	assert.NotContains(t, factor.Secret, "JBSWY3DPEHPK3PXP")
+
+	// sealing twice does not seal the sealed secret
+	sealed := factor.Secret
+	require.NoError(t, factor.SealSecret(key))
+	assert.Equal(t, sealed, factor.Secret)
+
+	assert.Error(t, (&FactorTOTP{Secret: sealed, UserID: "jane"}).OpenSecret(NewTOTPSecretKey("other")))
+	assert.Error(t, (&FactorTOTP{Secret: sealed, UserID: "john"}).OpenSecret(key))
+
+	require.NoError(t, factor.OpenSecret(key))
+	assert.Equal(t, "JBSWY3DPEHPK3PXP", factor.Secret)
+
+	// secrets stored before they were sealed are read as they are
+	legacy := &FactorTOTP{Secret: "JBSWY3DPEHPK3PXP", UserID: "jane"}
+	require.NoError(t, legacy.OpenSecret(key))
+	assert.Equal(t, "JBSWY3DPEHPK3PXP", legacy.Secret)
+}
@@ -0,0 +1,61 @@
+package types
+
+import (
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/errors"
+	"github.com/SigNoz/signoz/pkg/valuer"
+	"github.com/uptrace/bun"
+)
+
+var (
+	ErrMembershipNotFound      = errors.MustNewCode("membership_not_found")
+	ErrMembershipAlreadyExists = errors.MustNewCode("membership_already_exists")
+)
+
+// OrgMembership grants a user access to an org with a role of its own. Users are created with the membership of their
+// home org, the org and role stored on the user, and can be invited to other orgs.
+type OrgMembership struct {
+	bun.BaseModel `bun:"table:org_membership"`
+
+	Identifiable
+	TimeAuditable
+	UserID string `bun:"user_id,type:text,notnull,unique:user_org" json:"userId"`
+	OrgID  string `bun:"org_id,type:text,notnull,unique:user_org" json:"orgId"`
+	Role   string `bun:"role,type:text,notnull" json:"role"`
+}
+
+type GettableOrgMembership struct {
+	OrgID        string `json:"orgId"`
+	Organization string `json:"organization"`
+	Role         string `json:"role"`
+	// set for the org the request is made in
+	Current bool `json:"current"`
+}
+
+type PostableSwitchOrg struct {
+	OrgID string `json:"orgId"`
+}
+
+func NewOrgMembership(userID string, orgID string, role Role) *OrgMembership {
+	return &OrgMembership{
+		Identifiable: Identifiable{
+			ID: valuer.GenerateUUID(),
+		},
+		TimeAuditable: TimeAuditable{
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		},
+		UserID: userID,
+		OrgID:  orgID,
+		Role:   role.String(),
+	}
+}
+
+func (p *PostableSwitchOrg) Validate() error {
+	if _, err := valuer.NewUUID(p.OrgID); err != nil {
+		return errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "orgId is not a valid uuid")
+	}
+
+	return nil
+}
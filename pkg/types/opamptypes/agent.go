@@ -43,10 +43,12 @@ func NewStorableAgent(store sqlstore.SQLStore, orgID valuer.UUID, agentID string
 type ElementType struct{ valuer.String }
 
 var (
-	ElementTypeSamplingRules = ElementType{valuer.NewString("sampling_rules")}
-	ElementTypeDropRules     = ElementType{valuer.NewString("drop_rules")}
-	ElementTypeLogPipelines  = ElementType{valuer.NewString("log_pipelines")}
-	ElementTypeLbExporter    = ElementType{valuer.NewString("lb_exporter")}
+	ElementTypeSamplingRules  = ElementType{valuer.NewString("sampling_rules")}
+	ElementTypeDropRules      = ElementType{valuer.NewString("drop_rules")}
+	ElementTypeLogPipelines   = ElementType{valuer.NewString("log_pipelines")}
+	ElementTypeLbExporter     = ElementType{valuer.NewString("lb_exporter")}
+	ElementTypeDerivedMetrics = ElementType{valuer.NewString("derived_metrics")}
+	ElementTypeSpanMetrics    = ElementType{valuer.NewString("span_metrics")}
 )
 
 // NewElementType creates a new ElementType from a string value.
@@ -62,6 +64,10 @@ func NewElementType(value string) ElementType {
 		return ElementTypeLogPipelines
 	case ElementTypeLbExporter.String:
 		return ElementTypeLbExporter
+	case ElementTypeDerivedMetrics.String:
+		return ElementTypeDerivedMetrics
+	case ElementTypeSpanMetrics.String:
+		return ElementTypeSpanMetrics
 	default:
 		return ElementType{valuer.NewString("")}
 	}
@@ -0,0 +1,108 @@
+package opamptypes
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/SigNoz/signoz/pkg/valuer"
+	"gopkg.in/yaml.v3"
+)
+
+type ChangeType struct{ valuer.String }
+
+var (
+	ChangeTypeAdded   = ChangeType{valuer.NewString("added")}
+	ChangeTypeRemoved = ChangeType{valuer.NewString("removed")}
+	ChangeTypeChanged = ChangeType{valuer.NewString("changed")}
+)
+
+// SettingDiff is a change to a single setting of a config, identified by its path in the config.
+type SettingDiff struct {
+	Path   string     `json:"path"`
+	Change ChangeType `json:"change"`
+	Before any        `json:"before,omitempty"`
+	After  any        `json:"after,omitempty"`
+}
+
+// ConfigVersionDiff captures the changes made to the settings of a feature between two config versions.
+type ConfigVersionDiff struct {
+	ElementType ElementType   `json:"elementType"`
+	FromVersion int           `json:"fromVersion"`
+	ToVersion   int           `json:"toVersion"`
+	Settings    []SettingDiff `json:"settings"`
+}
+
+// DiffSettings compares two serialized (yaml or json) configs setting by setting.
+func DiffSettings(from string, to string) ([]SettingDiff, error) {
+	fromSettings, err := flattenSettings(from)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the config to compare from: %w", err)
+	}
+
+	toSettings, err := flattenSettings(to)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the config to compare to: %w", err)
+	}
+
+	diffs := []SettingDiff{}
+	for path, before := range fromSettings {
+		after, ok := toSettings[path]
+		if !ok {
+			diffs = append(diffs, SettingDiff{Path: path, Change: ChangeTypeRemoved, Before: before})
+			continue
+		}
+
+		if !reflect.DeepEqual(before, after) {
+			diffs = append(diffs, SettingDiff{Path: path, Change: ChangeTypeChanged, Before: before, After: after})
+		}
+	}
+
+	for path, after := range toSettings {
+		if _, ok := fromSettings[path]; !ok {
+			diffs = append(diffs, SettingDiff{Path: path, Change: ChangeTypeAdded, After: after})
+		}
+	}
+
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Path < diffs[j].Path
+	})
+
+	return diffs, nil
+}
+
+func flattenSettings(serialized string) (map[string]any, error) {
+	settings := map[string]any{}
+	if serialized == "" {
+		return settings, nil
+	}
+
+	var parsed any
+	if err := yaml.Unmarshal([]byte(serialized), &parsed); err != nil {
+		return nil, err
+	}
+
+	flattenSetting("", parsed, settings)
+	return settings, nil
+}
+
+func flattenSetting(path string, value any, settings map[string]any) {
+	switch typed := value.(type) {
+	case map[string]any:
+		for key, nested := range typed {
+			nestedPath := key
+			if path != "" {
+				nestedPath = path + "." + key
+			}
+			flattenSetting(nestedPath, nested, settings)
+		}
+	case []any:
+		for idx, nested := range typed {
+			flattenSetting(fmt.Sprintf("%s[%d]", path, idx), nested, settings)
+		}
+	default:
+		if path != "" {
+			settings[path] = typed
+		}
+	}
+}
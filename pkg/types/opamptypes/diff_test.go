@@ -0,0 +1,43 @@
+package opamptypes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffSettings(t *testing.T) {
+	from := `
+metrics:
+  exclude:
+    match_type: strict
+    metric_names:
+      - http_requests_total
+      - grpc_requests_total
+error_mode: ignore
+`
+	to := `
+metrics:
+  exclude:
+    match_type: regexp
+    metric_names:
+      - http_requests_total
+`
+
+	diffs, err := DiffSettings(from, to)
+	require.NoError(t, err)
+
+	assert.Equal(t, []SettingDiff{
+		{Path: "error_mode", Change: ChangeTypeRemoved, Before: "ignore"},
+		{Path: "metrics.exclude.match_type", Change: ChangeTypeChanged, Before: "strict", After: "regexp"},
+		{Path: "metrics.exclude.metric_names[1]", Change: ChangeTypeRemoved, Before: "grpc_requests_total"},
+	}, diffs)
+
+	diffs, err = DiffSettings(to, to)
+	require.NoError(t, err)
+	assert.Empty(t, diffs)
+
+	_, err = DiffSettings("metrics: [", to)
+	assert.Error(t, err)
+}
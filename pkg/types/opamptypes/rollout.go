@@ -0,0 +1,241 @@
+package opamptypes
+
+import (
+	"hash/fnv"
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/errors"
+	"github.com/SigNoz/signoz/pkg/types"
+	"github.com/SigNoz/signoz/pkg/valuer"
+	"github.com/uptrace/bun"
+)
+
+var (
+	ErrCodeAgentGroupInvalidInput    = errors.MustNewCode("agent_group_invalid_input")
+	ErrCodeRolloutPolicyInvalidInput = errors.MustNewCode("rollout_policy_invalid_input")
+)
+
+// AgentGroup is a named set of agents selected by the attributes they report
+// over OpAMP in their agent description (eg: deployment.environment, k8s.cluster.name).
+type AgentGroup struct {
+	bun.BaseModel `bun:"table:agent_group"`
+
+	types.Identifiable
+	types.TimeAuditable
+	types.UserAuditable
+	OrgID    valuer.UUID       `json:"orgId" bun:"org_id,type:text,notnull,unique:org_id_name"`
+	Name     string            `json:"name" bun:"name,type:text,notnull,unique:org_id_name"`
+	Selector map[string]string `json:"selector" bun:"selector,type:text,notnull"`
+}
+
+type PostableAgentGroup struct {
+	Name     string            `json:"name"`
+	Selector map[string]string `json:"selector"`
+}
+
+func NewAgentGroup(orgID valuer.UUID, userID valuer.UUID, postable PostableAgentGroup) (*AgentGroup, error) {
+	if postable.Name == "" {
+		return nil, errors.New(errors.TypeInvalidInput, ErrCodeAgentGroupInvalidInput, "name is required for an agent group")
+	}
+
+	if len(postable.Selector) == 0 {
+		return nil, errors.New(errors.TypeInvalidInput, ErrCodeAgentGroupInvalidInput, "selector must have at least one attribute")
+	}
+
+	for key := range postable.Selector {
+		if key == "" {
+			return nil, errors.New(errors.TypeInvalidInput, ErrCodeAgentGroupInvalidInput, "selector attribute names cannot be empty")
+		}
+	}
+
+	return &AgentGroup{
+		Identifiable: types.Identifiable{ID: valuer.GenerateUUID()},
+		TimeAuditable: types.TimeAuditable{
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		},
+		UserAuditable: types.UserAuditable{CreatedBy: userID.String(), UpdatedBy: userID.String()},
+		OrgID:         orgID,
+		Name:          postable.Name,
+		Selector:      postable.Selector,
+	}, nil
+}
+
+// Matches returns true if every attribute in the selector is reported by the agent with the same value.
+func (group *AgentGroup) Matches(labels map[string]string) bool {
+	for key, value := range group.Selector {
+		if labels[key] != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// DefaultMinCanarySuccesses is the number of successful canary deployments promoting a
+// rollout when the policy doesn't specify one, a canary is never promoted before it is deployed.
+const DefaultMinCanarySuccesses = 1
+
+type RolloutStage struct{ valuer.String }
+
+var (
+	// The new config version is only recommended to the canary subset of the targeted agents.
+	RolloutStageCanary = RolloutStage{valuer.NewString("canary")}
+	// The new config version is recommended to all the targeted agents.
+	RolloutStagePromoted = RolloutStage{valuer.NewString("promoted")}
+	// The new config version has been withdrawn, targeted agents are moved back to the previous version.
+	RolloutStageRolledBack = RolloutStage{valuer.NewString("rolled_back")}
+)
+
+// PostableRolloutPolicy is specified along with a config change to stage its deployment.
+type PostableRolloutPolicy struct {
+	// GroupID restricts the config version to agents in the group, all agents are targeted if empty.
+	GroupID string `json:"groupId"`
+
+	// CanaryPercent is the percentage of the targeted agents that receive the config first.
+	CanaryPercent int `json:"canaryPercent"`
+
+	// MinCanarySuccesses is the number of successful canary deployments after which the
+	// config version is promoted to all the targeted agents, DefaultMinCanarySuccesses if zero.
+	MinCanarySuccesses int `json:"minCanarySuccesses"`
+
+	// FailureThreshold is the fraction of reported deployments that are allowed to fail
+	// before the rollout is halted and rolled back to the previous config version.
+	FailureThreshold float64 `json:"failureThreshold"`
+}
+
+func (policy *PostableRolloutPolicy) Validate() error {
+	if policy.GroupID != "" {
+		if _, err := valuer.NewUUID(policy.GroupID); err != nil {
+			return errors.Wrapf(err, errors.TypeInvalidInput, ErrCodeRolloutPolicyInvalidInput, "groupId must be a valid uuid")
+		}
+	}
+
+	if policy.CanaryPercent < 0 || policy.CanaryPercent > 100 {
+		return errors.Newf(errors.TypeInvalidInput, ErrCodeRolloutPolicyInvalidInput, "canaryPercent must be between 0 and 100, got %d", policy.CanaryPercent)
+	}
+
+	if policy.MinCanarySuccesses < 0 {
+		return errors.Newf(errors.TypeInvalidInput, ErrCodeRolloutPolicyInvalidInput, "minCanarySuccesses cannot be negative, got %d", policy.MinCanarySuccesses)
+	}
+
+	if policy.FailureThreshold < 0 || policy.FailureThreshold >= 1 {
+		return errors.Newf(errors.TypeInvalidInput, ErrCodeRolloutPolicyInvalidInput, "failureThreshold must be in the range [0, 1), got %v", policy.FailureThreshold)
+	}
+
+	return nil
+}
+
+// AgentConfigRollout tracks the staged deployment of a single agent config version.
+type AgentConfigRollout struct {
+	bun.BaseModel `bun:"table:agent_config_rollout"`
+
+	types.Identifiable
+	types.TimeAuditable
+	OrgID              valuer.UUID  `json:"orgId" bun:"org_id,type:text,notnull,unique:rollout_element_version_org_idx"`
+	ElementType        ElementType  `json:"elementType" bun:"element_type,type:text,notnull,unique:rollout_element_version_org_idx"`
+	Version            int          `json:"version" bun:"version,notnull,unique:rollout_element_version_org_idx"`
+	PreviousVersion    int          `json:"previousVersion" bun:"previous_version,notnull"`
+	GroupID            string       `json:"groupId" bun:"group_id,type:text"`
+	Stage              RolloutStage `json:"stage" bun:"stage,type:text,notnull"`
+	CanaryPercent      int          `json:"canaryPercent" bun:"canary_percent,notnull"`
+	MinCanarySuccesses int          `json:"minCanarySuccesses" bun:"min_canary_successes,notnull"`
+	FailureThreshold   float64      `json:"failureThreshold" bun:"failure_threshold,notnull"`
+	Succeeded          int          `json:"succeeded" bun:"succeeded,notnull"`
+	Failed             int          `json:"failed" bun:"failed,notnull"`
+	Message            string       `json:"message" bun:"message,type:text"`
+}
+
+func NewAgentConfigRollout(orgID valuer.UUID, elementType ElementType, version int, previousVersion int, policy PostableRolloutPolicy) *AgentConfigRollout {
+	stage := RolloutStageCanary
+	if policy.CanaryPercent == 0 || policy.CanaryPercent == 100 {
+		stage = RolloutStagePromoted
+	}
+
+	minCanarySuccesses := policy.MinCanarySuccesses
+	if minCanarySuccesses == 0 {
+		minCanarySuccesses = DefaultMinCanarySuccesses
+	}
+
+	return &AgentConfigRollout{
+		Identifiable: types.Identifiable{ID: valuer.GenerateUUID()},
+		TimeAuditable: types.TimeAuditable{
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		},
+		OrgID:              orgID,
+		ElementType:        elementType,
+		Version:            version,
+		PreviousVersion:    previousVersion,
+		GroupID:            policy.GroupID,
+		Stage:              stage,
+		CanaryPercent:      policy.CanaryPercent,
+		MinCanarySuccesses: minCanarySuccesses,
+		FailureThreshold:   policy.FailureThreshold,
+	}
+}
+
+// IsCanary deterministically places an agent in the canary subset of the rollout,
+// so that an agent doesn't flip between versions across reconnects.
+func (rollout *AgentConfigRollout) IsCanary(agentID string) bool {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(rollout.ID.StringValue() + agentID))
+	return int(hash.Sum32()%100) < rollout.CanaryPercent
+}
+
+// IsActive returns true if the config version of the rollout can be recommended to agents.
+func (rollout *AgentConfigRollout) IsActive() bool {
+	return rollout.Stage != RolloutStageRolledBack
+}
+
+// RecordResult records the outcome of a deployment of the rollout's config version to an agent.
+// It returns true if the outcome moved the rollout to a different stage.
+func (rollout *AgentConfigRollout) RecordResult(agentID string, err error) bool {
+	if !rollout.IsActive() {
+		return false
+	}
+
+	rollout.UpdatedAt = time.Now()
+	if err != nil {
+		rollout.Failed++
+	} else {
+		rollout.Succeeded++
+	}
+
+	failureRate := float64(rollout.Failed) / float64(rollout.Failed+rollout.Succeeded)
+	if err != nil && failureRate > rollout.FailureThreshold {
+		rollout.Stage = RolloutStageRolledBack
+		rollout.Message = "rolled back after " + agentID + " failed to apply the config: " + err.Error()
+		return true
+	}
+
+	if rollout.Stage == RolloutStageCanary && rollout.Succeeded >= rollout.MinCanarySuccesses {
+		rollout.Stage = RolloutStagePromoted
+		rollout.Message = "promoted after successful canary deployments"
+		return true
+	}
+
+	return false
+}
+
+func (rollout *AgentConfigRollout) Promote() error {
+	if rollout.Stage != RolloutStageCanary {
+		return errors.Newf(errors.TypeInvalidInput, ErrCodeRolloutPolicyInvalidInput, "only rollouts in canary stage can be promoted, rollout is %s", rollout.Stage.StringValue())
+	}
+
+	rollout.Stage = RolloutStagePromoted
+	rollout.Message = "promoted manually"
+	rollout.UpdatedAt = time.Now()
+	return nil
+}
+
+func (rollout *AgentConfigRollout) RollBack() error {
+	if !rollout.IsActive() {
+		return errors.New(errors.TypeInvalidInput, ErrCodeRolloutPolicyInvalidInput, "rollout has already been rolled back")
+	}
+
+	rollout.Stage = RolloutStageRolledBack
+	rollout.Message = "rolled back manually"
+	rollout.UpdatedAt = time.Now()
+	return nil
+}
@@ -0,0 +1,105 @@
+package opamptypes
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/SigNoz/signoz/pkg/valuer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAgentGroupMatches(t *testing.T) {
+	group := &AgentGroup{Selector: map[string]string{"deployment.environment": "prod", "k8s.cluster.name": "eu-1"}}
+
+	assert.True(t, group.Matches(map[string]string{"deployment.environment": "prod", "k8s.cluster.name": "eu-1", "role": "gateway"}))
+	assert.False(t, group.Matches(map[string]string{"deployment.environment": "prod"}))
+	assert.False(t, group.Matches(map[string]string{"deployment.environment": "staging", "k8s.cluster.name": "eu-1"}))
+}
+
+func TestPostableRolloutPolicyValidate(t *testing.T) {
+	testCases := []struct {
+		name   string
+		policy PostableRolloutPolicy
+		pass   bool
+	}{
+		{name: "Valid", policy: PostableRolloutPolicy{CanaryPercent: 10, MinCanarySuccesses: 1, FailureThreshold: 0.2}, pass: true},
+		{name: "ValidWithGroup", policy: PostableRolloutPolicy{GroupID: valuer.GenerateUUID().StringValue(), CanaryPercent: 100}, pass: true},
+		{name: "InvalidGroup", policy: PostableRolloutPolicy{GroupID: "prod"}, pass: false},
+		{name: "InvalidCanaryPercent", policy: PostableRolloutPolicy{CanaryPercent: 101}, pass: false},
+		{name: "NegativeCanarySuccesses", policy: PostableRolloutPolicy{MinCanarySuccesses: -1}, pass: false},
+		{name: "InvalidFailureThreshold", policy: PostableRolloutPolicy{FailureThreshold: 1}, pass: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.policy.Validate()
+			if tc.pass {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestAgentConfigRolloutRecordResult(t *testing.T) {
+	orgID := valuer.GenerateUUID()
+
+	t.Run("PromotedAfterCanarySuccesses", func(t *testing.T) {
+		rollout := NewAgentConfigRollout(orgID, ElementTypeLogPipelines, 2, 1, PostableRolloutPolicy{CanaryPercent: 10, MinCanarySuccesses: 2})
+		assert.Equal(t, RolloutStageCanary, rollout.Stage)
+
+		assert.False(t, rollout.RecordResult("agent-1", nil))
+		assert.True(t, rollout.RecordResult("agent-2", nil))
+		assert.Equal(t, RolloutStagePromoted, rollout.Stage)
+	})
+
+	t.Run("DefaultCanarySuccesses", func(t *testing.T) {
+		rollout := NewAgentConfigRollout(orgID, ElementTypeLogPipelines, 2, 1, PostableRolloutPolicy{CanaryPercent: 10})
+		assert.Equal(t, DefaultMinCanarySuccesses, rollout.MinCanarySuccesses)
+
+		assert.True(t, rollout.RecordResult("agent-1", nil))
+		assert.Equal(t, RolloutStagePromoted, rollout.Stage)
+	})
+
+	t.Run("RolledBackAboveFailureThreshold", func(t *testing.T) {
+		rollout := NewAgentConfigRollout(orgID, ElementTypeLogPipelines, 2, 1, PostableRolloutPolicy{CanaryPercent: 50, MinCanarySuccesses: 5, FailureThreshold: 0.5})
+
+		assert.False(t, rollout.RecordResult("agent-1", nil))
+		assert.False(t, rollout.RecordResult("agent-2", errors.New("invalid processor config")))
+		assert.Equal(t, RolloutStageCanary, rollout.Stage)
+
+		assert.True(t, rollout.RecordResult("agent-3", errors.New("invalid processor config")))
+		assert.Equal(t, RolloutStageRolledBack, rollout.Stage)
+		assert.False(t, rollout.IsActive())
+
+		// results reported after the rollback are ignored
+		assert.False(t, rollout.RecordResult("agent-4", nil))
+		assert.Equal(t, 1, rollout.Succeeded)
+		assert.Equal(t, 2, rollout.Failed)
+	})
+
+	t.Run("NoCanaryStage", func(t *testing.T) {
+		rollout := NewAgentConfigRollout(orgID, ElementTypeLogPipelines, 2, 1, PostableRolloutPolicy{})
+		assert.Equal(t, RolloutStagePromoted, rollout.Stage)
+		assert.Error(t, rollout.Promote())
+		assert.NoError(t, rollout.RollBack())
+		assert.Error(t, rollout.RollBack())
+	})
+}
+
+func TestAgentConfigRolloutIsCanary(t *testing.T) {
+	rollout := NewAgentConfigRollout(valuer.GenerateUUID(), ElementTypeLogPipelines, 2, 1, PostableRolloutPolicy{CanaryPercent: 30})
+
+	canaries := 0
+	for i := 0; i < 1000; i++ {
+		agentID := valuer.GenerateUUID().StringValue()
+		isCanary := rollout.IsCanary(agentID)
+		assert.Equal(t, isCanary, rollout.IsCanary(agentID), "canary selection must be stable for an agent")
+		if isCanary {
+			canaries++
+		}
+	}
+
+	assert.InDelta(t, 300, canaries, 75)
+}
@@ -0,0 +1,192 @@
+package pipelinetypes
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/SigNoz/signoz/pkg/types/opamptypes"
+)
+
+// PipelineDiff captures the changes made to a pipeline between two versions.
+// Pipelines are matched across versions by their alias since they are stored
+// with a new id every time they are saved.
+type PipelineDiff struct {
+	Alias         string                `json:"alias"`
+	Name          string                `json:"name"`
+	Change        opamptypes.ChangeType `json:"change"`
+	ChangedFields []string              `json:"changedFields,omitempty"`
+	Operators     []OperatorDiff        `json:"operators,omitempty"`
+}
+
+// OperatorDiff captures the changes made to an operator of a pipeline, operators are matched by id.
+type OperatorDiff struct {
+	ID     string                `json:"id"`
+	Type   string                `json:"type"`
+	Change opamptypes.ChangeType `json:"change"`
+	Before *PipelineOperator     `json:"before,omitempty"`
+	After  *PipelineOperator     `json:"after,omitempty"`
+}
+
+// DiffPipelines returns the pipelines that have been added, removed or changed going from one list of pipelines to another.
+func DiffPipelines(from []GettablePipeline, to []GettablePipeline) []PipelineDiff {
+	fromByAlias := map[string]GettablePipeline{}
+	for _, pipeline := range from {
+		fromByAlias[pipeline.Alias] = pipeline
+	}
+
+	toAliases := map[string]struct{}{}
+	diffs := []PipelineDiff{}
+	for _, after := range to {
+		toAliases[after.Alias] = struct{}{}
+
+		before, ok := fromByAlias[after.Alias]
+		if !ok {
+			diffs = append(diffs, PipelineDiff{
+				Alias:     after.Alias,
+				Name:      after.Name,
+				Change:    opamptypes.ChangeTypeAdded,
+				Operators: diffOperators(nil, after.Config),
+			})
+			continue
+		}
+
+		changedFields := diffPipelineFields(before, after)
+		operators := diffOperators(before.Config, after.Config)
+		if len(changedFields) == 0 && len(operators) == 0 {
+			continue
+		}
+
+		diffs = append(diffs, PipelineDiff{
+			Alias:         after.Alias,
+			Name:          after.Name,
+			Change:        opamptypes.ChangeTypeChanged,
+			ChangedFields: changedFields,
+			Operators:     operators,
+		})
+	}
+
+	for _, before := range from {
+		if _, ok := toAliases[before.Alias]; ok {
+			continue
+		}
+
+		diffs = append(diffs, PipelineDiff{
+			Alias:     before.Alias,
+			Name:      before.Name,
+			Change:    opamptypes.ChangeTypeRemoved,
+			Operators: diffOperators(before.Config, nil),
+		})
+	}
+
+	return diffs
+}
+
+func diffPipelineFields(before GettablePipeline, after GettablePipeline) []string {
+	changedFields := []string{}
+	if before.Name != after.Name {
+		changedFields = append(changedFields, "name")
+	}
+	if before.Description != after.Description {
+		changedFields = append(changedFields, "description")
+	}
+	if before.Enabled != after.Enabled {
+		changedFields = append(changedFields, "enabled")
+	}
+	if before.OrderID != after.OrderID {
+		changedFields = append(changedFields, "orderId")
+	}
+	if !isJSONEqual(before.Filter, after.Filter) {
+		changedFields = append(changedFields, "filter")
+	}
+
+	return changedFields
+}
+
+func diffOperators(from []PipelineOperator, to []PipelineOperator) []OperatorDiff {
+	fromByID := map[string]PipelineOperator{}
+	for _, operator := range from {
+		fromByID[operator.ID] = operator
+	}
+
+	toIDs := map[string]struct{}{}
+	diffs := []OperatorDiff{}
+	for _, after := range to {
+		toIDs[after.ID] = struct{}{}
+
+		before, ok := fromByID[after.ID]
+		if !ok {
+			diffs = append(diffs, OperatorDiff{ID: after.ID, Type: after.Type, Change: opamptypes.ChangeTypeAdded, After: &after})
+			continue
+		}
+
+		if !isJSONEqual(before, after) {
+			diffs = append(diffs, OperatorDiff{ID: after.ID, Type: after.Type, Change: opamptypes.ChangeTypeChanged, Before: &before, After: &after})
+		}
+	}
+
+	for _, before := range from {
+		if _, ok := toIDs[before.ID]; !ok {
+			diffs = append(diffs, OperatorDiff{ID: before.ID, Type: before.Type, Change: opamptypes.ChangeTypeRemoved, Before: &before})
+		}
+	}
+
+	return diffs
+}
+
+// compares values by their serialized form so that nil and empty values are considered equal.
+func isJSONEqual(a any, b any) bool {
+	normalizedA, errA := normalizeJSON(a)
+	normalizedB, errB := normalizeJSON(b)
+	if errA != nil || errB != nil {
+		return reflect.DeepEqual(a, b)
+	}
+
+	return reflect.DeepEqual(normalizedA, normalizedB)
+}
+
+// normalizeJSON returns the serialized form of the value with the nil and empty values left out.
+func normalizeJSON(v any) (any, error) {
+	serialized, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var decoded any
+	if err := json.Unmarshal(serialized, &decoded); err != nil {
+		return nil, err
+	}
+
+	return withoutEmpty(decoded), nil
+}
+
+func withoutEmpty(v any) any {
+	switch value := v.(type) {
+	case map[string]any:
+		normalized := map[string]any{}
+		for key, item := range value {
+			if item = withoutEmpty(item); item != nil {
+				normalized[key] = item
+			}
+		}
+		if len(normalized) == 0 {
+			return nil
+		}
+		return normalized
+	case []any:
+		if len(value) == 0 {
+			return nil
+		}
+		normalized := make([]any, 0, len(value))
+		for _, item := range value {
+			normalized = append(normalized, withoutEmpty(item))
+		}
+		return normalized
+	case string:
+		if value == "" {
+			return nil
+		}
+		return value
+	default:
+		return value
+	}
+}
@@ -0,0 +1,67 @@
+package pipelinetypes
+
+import (
+	"testing"
+
+	"github.com/SigNoz/signoz/pkg/types/opamptypes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffPipelines(t *testing.T) {
+	require := require.New(t)
+
+	grok := PipelineOperator{ID: "grok", Type: "grok_parser", Pattern: "%{WORD:method}", ParseTo: "attributes", Output: "move"}
+	move := PipelineOperator{ID: "move", Type: "move", From: "attributes.method", To: "attributes.http.method"}
+
+	pipeline := func(alias string, enabled bool, operators ...PipelineOperator) GettablePipeline {
+		return GettablePipeline{
+			StoreablePipeline: StoreablePipeline{Name: alias, Alias: alias, Enabled: enabled},
+			Config:            operators,
+		}
+	}
+
+	changedGrok := grok
+	changedGrok.Pattern = "%{WORD:method} %{URIPATH:path}"
+
+	from := []GettablePipeline{
+		pipeline("nginx", true, grok, move),
+		pipeline("unchanged", true, move),
+		pipeline("legacy", true, move),
+	}
+	to := []GettablePipeline{
+		pipeline("nginx", false, changedGrok),
+		pipeline("unchanged", true, move),
+		pipeline("java", true, grok),
+	}
+
+	diffs := DiffPipelines(from, to)
+	require.Equal(3, len(diffs))
+
+	require.Equal("nginx", diffs[0].Alias)
+	require.Equal(opamptypes.ChangeTypeChanged, diffs[0].Change)
+	require.Equal([]string{"enabled"}, diffs[0].ChangedFields)
+	require.Equal(2, len(diffs[0].Operators))
+	require.Equal("grok", diffs[0].Operators[0].ID)
+	require.Equal(opamptypes.ChangeTypeChanged, diffs[0].Operators[0].Change)
+	require.Equal(grok.Pattern, diffs[0].Operators[0].Before.Pattern)
+	require.Equal(changedGrok.Pattern, diffs[0].Operators[0].After.Pattern)
+	require.Equal("move", diffs[0].Operators[1].ID)
+	require.Equal(opamptypes.ChangeTypeRemoved, diffs[0].Operators[1].Change)
+
+	require.Equal("java", diffs[1].Alias)
+	require.Equal(opamptypes.ChangeTypeAdded, diffs[1].Change)
+	require.Equal(1, len(diffs[1].Operators))
+
+	require.Equal("legacy", diffs[2].Alias)
+	require.Equal(opamptypes.ChangeTypeRemoved, diffs[2].Change)
+}
+
+func TestIsJSONEqual(t *testing.T) {
+	require := require.New(t)
+
+	require.True(isJSONEqual(Processor{Operators: nil}, Processor{Operators: []PipelineOperator{}}))
+	require.True(isJSONEqual(map[string]any{"parse_to": nil}, map[string]any{"parse_to": ""}))
+	require.True(isJSONEqual([]string(nil), []string{}))
+	require.False(isJSONEqual(PipelineOperator{ID: "grok"}, PipelineOperator{ID: "move"}))
+	require.False(isJSONEqual([]string{"a"}, []string{}))
+}
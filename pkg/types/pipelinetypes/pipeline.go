@@ -10,6 +10,7 @@ import (
 	v3 "github.com/SigNoz/signoz/pkg/query-service/model/v3"
 	"github.com/SigNoz/signoz/pkg/query-service/queryBuilderToExpr"
 	"github.com/SigNoz/signoz/pkg/types"
+	"github.com/SigNoz/signoz/pkg/types/opamptypes"
 	"github.com/pkg/errors"
 	"github.com/uptrace/bun"
 )
@@ -119,6 +120,9 @@ type Route struct {
 
 type PostablePipelines struct {
 	Pipelines []PostablePipeline `json:"pipelines"`
+
+	// Rollout optionally stages the deployment of the pipelines to agents.
+	Rollout *opamptypes.PostableRolloutPolicy `json:"rollout,omitempty"`
 }
 
 // PostablePipeline captures user inputs in setting the pipeline
@@ -18,6 +18,9 @@ var (
 	NameWelcomeChecklistSetupSavedViewSkipped   = Name{valuer.NewString("welcome_checklist_setup_saved_view_skipped")}
 	NameSidenavPinned                           = Name{valuer.NewString("sidenav_pinned")}
 	NameNavShortcuts                            = Name{valuer.NewString("nav_shortcuts")}
+	NameRequireMFA                              = Name{valuer.NewString("require_mfa")}
+	NameFavorites                               = Name{valuer.NewString("favorites")}
+	NameDefaultSavedViews                       = Name{valuer.NewString("default_saved_views")}
 )
 
 type Name struct{ valuer.String }
@@ -35,6 +38,9 @@ func NewName(name string) (Name, error) {
 			NameWelcomeChecklistSetupSavedViewSkipped.StringValue(),
 			NameSidenavPinned.StringValue(),
 			NameNavShortcuts.StringValue(),
+			NameRequireMFA.StringValue(),
+			NameFavorites.StringValue(),
+			NameDefaultSavedViews.StringValue(),
 		},
 		name,
 	)
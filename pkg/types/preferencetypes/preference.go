@@ -145,6 +145,33 @@ func NewAvailablePreference() map[Name]Preference {
 			AllowedValues: []string{},
 			Value:         MustNewValue([]any{}, ValueTypeArray),
 		},
+		NameRequireMFA: {
+			Name:          NameRequireMFA,
+			Description:   "Require multi-factor authentication for password logins of all the users of the organisation.",
+			ValueType:     ValueTypeBoolean,
+			DefaultValue:  MustNewValue(false, ValueTypeBoolean),
+			AllowedScopes: []Scope{ScopeOrg},
+			AllowedValues: []string{},
+			Value:         MustNewValue(false, ValueTypeBoolean),
+		},
+		NameFavorites: {
+			Name:          NameFavorites,
+			Description:   "The dashboards, saved views and alert rules starred by the user, as a list of type:id.",
+			ValueType:     ValueTypeArray,
+			DefaultValue:  MustNewValue([]any{}, ValueTypeArray),
+			AllowedScopes: []Scope{ScopeUser},
+			AllowedValues: []string{},
+			Value:         MustNewValue([]any{}, ValueTypeArray),
+		},
+		NameDefaultSavedViews: {
+			Name:          NameDefaultSavedViews,
+			Description:   "The saved view opened by default on each explorer page, as the id of the view by source page.",
+			ValueType:     ValueTypeObject,
+			DefaultValue:  MustNewValue(map[string]any{}, ValueTypeObject),
+			AllowedScopes: []Scope{ScopeUser},
+			AllowedValues: []string{},
+			Value:         MustNewValue(map[string]any{}, ValueTypeObject),
+		},
 	}
 }
 
@@ -112,6 +112,52 @@ func (value Value) MarshalJSON() ([]byte, error) {
 	return []byte(value.stringValue), nil
 }
 
+// Bool returns the value of a boolean preference, false for values of other types.
+func (value Value) Bool() bool {
+	boolValue, _ := value.goValue.(bool)
+	return boolValue
+}
+
+// Strings returns the strings of an array preference, skipping its other elements, and nil for values of other types.
+func (value Value) Strings() []string {
+	if value.valueType != ValueTypeArray {
+		return nil
+	}
+
+	elements := reflect.ValueOf(value.goValue)
+	values := make([]string, 0, elements.Len())
+	for i := 0; i < elements.Len(); i++ {
+		if element, ok := elements.Index(i).Interface().(string); ok {
+			values = append(values, element)
+		}
+	}
+
+	return values
+}
+
+// StringMap returns the string entries of an object preference, skipping its other entries, and nil for values of other
+// types.
+func (value Value) StringMap() map[string]string {
+	if value.valueType != ValueTypeObject {
+		return nil
+	}
+
+	values := make(map[string]string)
+	entries := reflect.ValueOf(value.goValue).MapRange()
+	for entries.Next() {
+		key, ok := entries.Key().Interface().(string)
+		if !ok {
+			continue
+		}
+
+		if entry, ok := entries.Value().Interface().(string); ok {
+			values[key] = entry
+		}
+	}
+
+	return values
+}
+
 func (preference *Preference) UpdateValue(value Value) error {
 	if preference.ValueType != value.valueType {
 		return errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "value type does not match preference value type: %s", preference.ValueType)
@@ -72,3 +72,21 @@ func TestNewValue(t *testing.T) {
 		})
 	}
 }
+
+func TestValueStrings(t *testing.T) {
+	value, err := NewValueFromString(`["dashboard:a", 1, "rule:b"]`, ValueTypeArray)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"dashboard:a", "rule:b"}, value.Strings())
+
+	assert.Equal(t, []string{"view:c"}, MustNewValue([]string{"view:c"}, ValueTypeArray).Strings())
+	assert.Nil(t, MustNewValue(true, ValueTypeBoolean).Strings())
+}
+
+func TestValueStringMap(t *testing.T) {
+	value, err := NewValueFromString(`{"logs": "a", "traces": 1}`, ValueTypeObject)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"logs": "a"}, value.StringMap())
+
+	assert.Equal(t, map[string]string{"traces": "b"}, MustNewValue(map[string]string{"traces": "b"}, ValueTypeObject).StringMap())
+	assert.Nil(t, MustNewValue([]string{"view:c"}, ValueTypeArray).StringMap())
+}
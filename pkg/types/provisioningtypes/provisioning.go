@@ -0,0 +1,188 @@
+package provisioningtypes
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"regexp"
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/errors"
+	"github.com/SigNoz/signoz/pkg/types"
+	"github.com/SigNoz/signoz/pkg/valuer"
+	"github.com/uptrace/bun"
+)
+
+var (
+	ErrCodeResourceManaged = errors.MustNewCode("resource_managed")
+)
+
+// external ids are kept to the characters of file paths, e.g. `payments/latency.json`.
+var externalIDRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._/-]{0,254}$`)
+
+// Kind is the kind of resource a bundle manages.
+type Kind struct{ valuer.String }
+
+var (
+	KindChannel   = Kind{valuer.NewString("channel")}
+	KindRule      = Kind{valuer.NewString("rule")}
+	KindDashboard = Kind{valuer.NewString("dashboard")}
+	KindSavedView = Kind{valuer.NewString("saved_view")}
+)
+
+// Kinds are ordered the way they are created and updated, rules notify channels so channels come first. Deletes go
+// the other way round.
+var Kinds = []Kind{KindChannel, KindRule, KindDashboard, KindSavedView}
+
+type Action struct{ valuer.String }
+
+var (
+	ActionCreate    = Action{valuer.NewString("create")}
+	ActionUpdate    = Action{valuer.NewString("update")}
+	ActionDelete    = Action{valuer.NewString("delete")}
+	ActionUnchanged = Action{valuer.NewString("unchanged")}
+)
+
+// Managed records a resource of the org managed by the bundles applied to it. The resources managed by bundles are
+// read-only, they are only changed by applying bundles.
+type Managed struct {
+	bun.BaseModel `bun:"table:managed_resource"`
+
+	types.Identifiable
+	types.TimeAuditable
+	OrgID      valuer.UUID `bun:"org_id,type:text,notnull,unique:org_id_kind_external_id" json:"orgId"`
+	Kind       Kind        `bun:"kind,type:text,notnull,unique:org_id_kind_external_id" json:"kind"`
+	ExternalID string      `bun:"external_id,type:text,notnull,unique:org_id_kind_external_id" json:"externalId"`
+	ResourceID string      `bun:"resource_id,type:text,notnull" json:"resourceId"`
+	// checksum of the spec the resource was last applied with
+	Checksum string `bun:"checksum,type:text,notnull" json:"checksum"`
+}
+
+// Resource is a resource of a bundle, the spec is the body of the request creating the resource through the API of
+// its kind.
+type Resource struct {
+	ExternalID string          `json:"externalId"`
+	Spec       json.RawMessage `json:"spec"`
+}
+
+type Bundle struct {
+	Channels   []*Resource `json:"channels"`
+	Rules      []*Resource `json:"rules"`
+	Dashboards []*Resource `json:"dashboards"`
+	SavedViews []*Resource `json:"savedViews"`
+}
+
+type Change struct {
+	Kind       Kind   `json:"kind"`
+	ExternalID string `json:"externalId"`
+	Action     Action `json:"action"`
+	ResourceID string `json:"resourceId,omitempty"`
+}
+
+// Plan lists the changes applying a bundle makes, the changes of dry runs are not made.
+type Plan struct {
+	DryRun  bool      `json:"dryRun"`
+	Changes []*Change `json:"changes"`
+}
+
+func NewManaged(orgID valuer.UUID, kind Kind, externalID string, resourceID string, checksum string) *Managed {
+	return &Managed{
+		Identifiable: types.Identifiable{
+			ID: valuer.GenerateUUID(),
+		},
+		TimeAuditable: types.TimeAuditable{
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		},
+		OrgID:      orgID,
+		Kind:       kind,
+		ExternalID: externalID,
+		ResourceID: resourceID,
+		Checksum:   checksum,
+	}
+}
+
+func NewPlan(dryRun bool) *Plan {
+	return &Plan{DryRun: dryRun, Changes: []*Change{}}
+}
+
+func (plan *Plan) Add(kind Kind, externalID string, action Action, resourceID string) *Change {
+	change := &Change{Kind: kind, ExternalID: externalID, Action: action, ResourceID: resourceID}
+	plan.Changes = append(plan.Changes, change)
+	return change
+}
+
+// Resources gets the resources of the kind in the bundle.
+func (bundle *Bundle) Resources(kind Kind) []*Resource {
+	switch kind {
+	case KindChannel:
+		return bundle.Channels
+	case KindRule:
+		return bundle.Rules
+	case KindDashboard:
+		return bundle.Dashboards
+	case KindSavedView:
+		return bundle.SavedViews
+	default:
+		return nil
+	}
+}
+
+// Declares reports whether the bundle lists the resources of the kind, possibly none. The resources of the kinds left
+// out of the bundle are neither created nor deleted by applying it.
+func (bundle *Bundle) Declares(kind Kind) bool {
+	return bundle.Resources(kind) != nil
+}
+
+func (bundle *Bundle) Validate() error {
+	for _, kind := range Kinds {
+		externalIDs := map[string]bool{}
+		for i, resource := range bundle.Resources(kind) {
+			if resource == nil {
+				return errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "%s %d of the bundle is empty", kind.StringValue(), i)
+			}
+
+			if !externalIDRegex.MatchString(resource.ExternalID) {
+				return errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "invalid externalId %q of %s %d, must be alphanumeric characters, '.', '_', '-' or '/' of at most 255 characters", resource.ExternalID, kind.StringValue(), i)
+			}
+
+			if externalIDs[resource.ExternalID] {
+				return errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "externalId %q is used by more than one %s", resource.ExternalID, kind.StringValue())
+			}
+			externalIDs[resource.ExternalID] = true
+
+			if len(resource.Spec) == 0 || string(resource.Spec) == "null" {
+				return errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "spec of %s %q is required", kind.StringValue(), resource.ExternalID)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Checksum is the checksum of the spec regardless of its formatting and the order of its keys.
+func (resource *Resource) Checksum() (string, error) {
+	var spec any
+	if err := json.Unmarshal(resource.Spec, &spec); err != nil {
+		return "", errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "spec of %q is not valid json", resource.ExternalID)
+	}
+
+	canonical, err := json.Marshal(spec)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func (managed *Managed) Update(resourceID string, checksum string) {
+	managed.ResourceID = resourceID
+	managed.Checksum = checksum
+	managed.UpdatedAt = time.Now()
+}
+
+// NewErrManaged is the error of the changes made to a managed resource outside of its bundle.
+func NewErrManaged(managed *Managed) error {
+	return errors.Newf(errors.TypeForbidden, ErrCodeResourceManaged, "%s %s is managed by the bundle resource %q, change it in the bundle and apply it", managed.Kind.StringValue(), managed.ResourceID, managed.ExternalID)
+}
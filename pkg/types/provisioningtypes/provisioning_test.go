@@ -0,0 +1,76 @@
+package provisioningtypes
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/SigNoz/signoz/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundleValidate(t *testing.T) {
+	spec := json.RawMessage(`{"title": "redis"}`)
+
+	testCases := []struct {
+		name   string
+		bundle *Bundle
+		pass   bool
+	}{
+		{
+			name:   "Valid",
+			bundle: &Bundle{Dashboards: []*Resource{{ExternalID: "databases/redis.json", Spec: spec}}, Rules: []*Resource{{ExternalID: "databases/redis.json", Spec: spec}}},
+			pass:   true,
+		},
+		{
+			name:   "Empty",
+			bundle: &Bundle{},
+			pass:   true,
+		},
+		{
+			name:   "MissingExternalID",
+			bundle: &Bundle{Dashboards: []*Resource{{Spec: spec}}},
+		},
+		{
+			name:   "InvalidExternalID",
+			bundle: &Bundle{Dashboards: []*Resource{{ExternalID: "../redis", Spec: spec}}},
+		},
+		{
+			name:   "DuplicateExternalID",
+			bundle: &Bundle{SavedViews: []*Resource{{ExternalID: "redis", Spec: spec}, {ExternalID: "redis", Spec: spec}}},
+		},
+		{
+			name:   "MissingSpec",
+			bundle: &Bundle{Channels: []*Resource{{ExternalID: "redis", Spec: json.RawMessage(`null`)}}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.bundle.Validate()
+			if tc.pass {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.True(t, errors.Ast(err, errors.TypeInvalidInput))
+		})
+	}
+}
+
+func TestResourceChecksum(t *testing.T) {
+	checksum, err := (&Resource{ExternalID: "redis", Spec: json.RawMessage(`{"title": "redis", "tags": ["db"]}`)}).Checksum()
+	require.NoError(t, err)
+
+	// formatting and the order of the keys don't change the checksum
+	reformatted, err := (&Resource{ExternalID: "redis", Spec: json.RawMessage("{\n\t\"tags\": [\"db\"],\n\t\"title\": \"redis\"\n}")}).Checksum()
+	require.NoError(t, err)
+	assert.Equal(t, checksum, reformatted)
+
+	changed, err := (&Resource{ExternalID: "redis", Spec: json.RawMessage(`{"title": "redis", "tags": ["cache"]}`)}).Checksum()
+	require.NoError(t, err)
+	assert.NotEqual(t, checksum, changed)
+
+	_, err = (&Resource{ExternalID: "redis", Spec: json.RawMessage(`{`)}).Checksum()
+	assert.True(t, errors.Ast(err, errors.TypeInvalidInput))
+}
@@ -0,0 +1,17 @@
+package provisioningtypes
+
+import (
+	"context"
+
+	"github.com/SigNoz/signoz/pkg/valuer"
+)
+
+type Store interface {
+	List(context.Context, valuer.UUID) ([]*Managed, error)
+	GetByResourceID(context.Context, valuer.UUID, Kind, string) (*Managed, error)
+	// Upsert creates the record of the managed resource or updates the one with the same external id
+	Upsert(context.Context, *Managed) error
+	Delete(context.Context, valuer.UUID, Kind, string) error
+
+	RunInTx(context.Context, func(context.Context) error) error
+}
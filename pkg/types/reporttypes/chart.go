@@ -0,0 +1,169 @@
+package reporttypes
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+
+	"github.com/SigNoz/signoz/pkg/errors"
+)
+
+const (
+	ChartWidth  = 640
+	ChartHeight = 240
+	// MaxChartSeries is the number of series drawn on a chart, the other series are only listed in the tables.
+	MaxChartSeries = 10
+
+	chartPadding   = 8
+	chartGridLines = 4
+)
+
+var (
+	chartBackground = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	chartGrid       = color.RGBA{R: 0xe5, G: 0xe7, B: 0xeb, A: 0xff}
+	chartPalette    = []color.RGBA{
+		{R: 0x4e, G: 0x79, B: 0xa7, A: 0xff},
+		{R: 0xf2, G: 0x8e, B: 0x2b, A: 0xff},
+		{R: 0xe1, G: 0x57, B: 0x59, A: 0xff},
+		{R: 0x76, G: 0xb7, B: 0xb2, A: 0xff},
+		{R: 0x59, G: 0xa1, B: 0x4f, A: 0xff},
+		{R: 0xed, G: 0xc9, B: 0x48, A: 0xff},
+		{R: 0xb0, G: 0x7a, B: 0xa1, A: 0xff},
+		{R: 0xff, G: 0x9d, B: 0xa7, A: 0xff},
+		{R: 0x9c, G: 0x75, B: 0x5f, A: 0xff},
+		{R: 0xba, G: 0xb0, B: 0xac, A: 0xff},
+	}
+)
+
+// Chart is a line chart of time series, rendered as a PNG image. The chart has no text, its series are listed in a
+// table along with their colors.
+type Chart struct {
+	// Image is the PNG encoded chart.
+	Image []byte
+	// Min and Max are the bounds of the values axis.
+	Min float64
+	Max float64
+}
+
+// ChartColor returns the hex color of the i-th series of a chart.
+func ChartColor(i int) string {
+	c := chartPalette[i%len(chartPalette)]
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// NewChart draws the first MaxChartSeries series between start and end in epoch milliseconds.
+func NewChart(series []*Series, start int64, end int64) (*Chart, error) {
+	if len(series) > MaxChartSeries {
+		series = series[:MaxChartSeries]
+	}
+
+	minimum, maximum := math.Inf(1), math.Inf(-1)
+	for _, s := range series {
+		for _, point := range s.Points {
+			if math.IsNaN(point.Value) || math.IsInf(point.Value, 0) {
+				continue
+			}
+			minimum, maximum = min(minimum, point.Value), max(maximum, point.Value)
+		}
+	}
+
+	if math.IsInf(minimum, 1) {
+		minimum, maximum = 0, 1
+	}
+
+	// flat series are drawn in the middle of the chart
+	if minimum == maximum {
+		minimum, maximum = minimum-1, maximum+1
+	}
+
+	if end <= start {
+		end = start + 1
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, ChartWidth, ChartHeight))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: chartBackground}, image.Point{}, draw.Src)
+
+	plotWidth, plotHeight := ChartWidth-2*chartPadding, ChartHeight-2*chartPadding
+	for i := 0; i <= chartGridLines; i++ {
+		y := chartPadding + i*plotHeight/chartGridLines
+		drawLine(img, chartPadding, y, chartPadding+plotWidth, y, chartGrid)
+	}
+
+	toX := func(timestamp int64) int {
+		return chartPadding + int(float64(timestamp-start)/float64(end-start)*float64(plotWidth))
+	}
+	toY := func(value float64) int {
+		return chartPadding + plotHeight - int((value-minimum)/(maximum-minimum)*float64(plotHeight))
+	}
+
+	for i, s := range series {
+		c := chartPalette[i%len(chartPalette)]
+		previousX, previousY, previous := 0, 0, false
+		for _, point := range s.Points {
+			if math.IsNaN(point.Value) || math.IsInf(point.Value, 0) || point.Timestamp < start || point.Timestamp > end {
+				previous = false
+				continue
+			}
+
+			x, y := toX(point.Timestamp), toY(point.Value)
+			if previous {
+				// lines are two pixels thick
+				drawLine(img, previousX, previousY, x, y, c)
+				drawLine(img, previousX, previousY+1, x, y+1, c)
+			} else {
+				img.SetRGBA(x, y, c)
+				img.SetRGBA(x, y+1, c)
+			}
+			previousX, previousY, previous = x, y, true
+		}
+	}
+
+	buffer := &bytes.Buffer{}
+	if err := png.Encode(buffer, img); err != nil {
+		return nil, errors.Wrapf(err, errors.TypeInternal, errors.CodeInternal, "failed to encode chart")
+	}
+
+	return &Chart{Image: buffer.Bytes(), Min: minimum, Max: maximum}, nil
+}
+
+// drawLine draws a line between two points with Bresenham's algorithm.
+func drawLine(img *image.RGBA, x0, y0, x1, y1 int, c color.RGBA) {
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+
+	err := dx + dy
+	for {
+		img.SetRGBA(x0, y0, c)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			err += dx
+			y0 += sy
+		}
+	}
+}
+
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+
+	return value
+}
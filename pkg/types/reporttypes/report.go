@@ -0,0 +1,345 @@
+package reporttypes
+
+import (
+	"net/mail"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/errors"
+	"github.com/SigNoz/signoz/pkg/types"
+	"github.com/SigNoz/signoz/pkg/types/dashboardtypes"
+	qbtypes "github.com/SigNoz/signoz/pkg/types/querybuildertypes/querybuildertypesv5"
+	"github.com/SigNoz/signoz/pkg/valuer"
+	"github.com/prometheus/common/model"
+	"github.com/robfig/cron/v3"
+	"github.com/uptrace/bun"
+)
+
+var (
+	ErrCodeReportNotFound = errors.MustNewCode("report_not_found")
+	ErrCodeReportInvalid  = errors.MustNewCode("report_invalid")
+)
+
+const (
+	// MaxTimeRange is the longest time range the panels of a report can query.
+	MaxTimeRange = 31 * 24 * time.Hour
+	// MaxPanels is the number of panels a report can query.
+	MaxPanels = 20
+	// MaxRecipients is the number of addresses a report can be sent to.
+	MaxRecipients = 50
+	// MaxDeliveries is the number of deliveries kept in the history of a report.
+	MaxDeliveries = 100
+)
+
+type Trigger struct{ valuer.String }
+
+var (
+	TriggerSchedule = Trigger{valuer.NewString("schedule")}
+	TriggerManual   = Trigger{valuer.NewString("manual")}
+)
+
+type Status struct{ valuer.String }
+
+var (
+	StatusSuccess = Status{valuer.NewString("success")}
+	// StatusPartial is the status of reports which were sent with some panels failing to query.
+	StatusPartial = Status{valuer.NewString("partial")}
+	StatusFailed  = Status{valuer.NewString("failed")}
+)
+
+// Panel is a panel queried by a report which isn't read from a dashboard.
+type Panel struct {
+	Title     string                   `json:"title"`
+	PanelType dashboardtypes.PanelType `json:"panelType"`
+	Queries   []qbtypes.QueryEnvelope  `json:"queries"`
+}
+
+// Report periodically emails the results of the panels of a dashboard, or of its own panels, to its recipients.
+type Report struct {
+	bun.BaseModel `bun:"table:report"`
+
+	types.Identifiable
+	types.TimeAuditable
+	types.UserAuditable
+	OrgID       valuer.UUID `bun:"org_id,type:text,notnull" json:"orgId"`
+	Name        string      `bun:"name,type:text,notnull" json:"name"`
+	DashboardID string      `bun:"dashboard_id,type:text" json:"dashboardId,omitempty"`
+	// the widgets of the dashboard the report is restricted to, every widget of the dashboard is reported when empty
+	WidgetIDs []string `bun:"widget_ids,type:text" json:"widgetIds,omitempty"`
+	// the panels queried instead of the widgets of a dashboard
+	Panels    []*Panel       `bun:"panels,type:text" json:"panels,omitempty"`
+	Variables map[string]any `bun:"variables,type:text" json:"variables,omitempty"`
+	// the standard cron expression of the schedule of the report, evaluated in its timezone
+	Cron     string `bun:"cron,type:text,notnull" json:"cron"`
+	Timezone string `bun:"timezone,type:text,notnull" json:"timezone"`
+	// the time range queried by the panels, ending when the report is sent, e.g. 24h or 7d
+	TimeRange  string    `bun:"time_range,type:text,notnull" json:"timeRange"`
+	Recipients []string  `bun:"recipients,type:text,notnull" json:"recipients"`
+	AttachCSV  bool      `bun:"attach_csv,notnull,default:false" json:"attachCsv"`
+	Enabled    bool      `bun:"enabled,notnull,default:true" json:"enabled"`
+	NextRunAt  time.Time `bun:"next_run_at,notnull" json:"nextRunAt"`
+}
+
+type PostableReport struct {
+	Name        string         `json:"name"`
+	DashboardID string         `json:"dashboardId"`
+	WidgetIDs   []string       `json:"widgetIds"`
+	Panels      []*Panel       `json:"panels"`
+	Variables   map[string]any `json:"variables"`
+	Cron        string         `json:"cron"`
+	Timezone    string         `json:"timezone"`
+	TimeRange   string         `json:"timeRange"`
+	Recipients  []string       `json:"recipients"`
+	AttachCSV   bool           `json:"attachCsv"`
+	Enabled     bool           `json:"enabled"`
+}
+
+// PostableSend sends a report now, to the given recipients instead of those of the report when any.
+type PostableSend struct {
+	Recipients []string `json:"recipients"`
+}
+
+// Delivery is a run of a report, the deliveries of a report are its history.
+type Delivery struct {
+	bun.BaseModel `bun:"table:report_delivery"`
+
+	types.Identifiable
+	OrgID      valuer.UUID `bun:"org_id,type:text,notnull" json:"orgId"`
+	ReportID   valuer.UUID `bun:"report_id,type:text,notnull" json:"reportId"`
+	Trigger    Trigger     `bun:"trigger,type:text,notnull" json:"trigger"`
+	Status     Status      `bun:"status,type:text,notnull" json:"status"`
+	Recipients []string    `bun:"recipients,type:text,notnull" json:"recipients"`
+	Error      string      `bun:"error,type:text" json:"error,omitempty"`
+	StartedAt  time.Time   `bun:"started_at,notnull" json:"startedAt"`
+	FinishedAt time.Time   `bun:"finished_at,notnull" json:"finishedAt"`
+}
+
+func NewReport(orgID valuer.UUID, createdBy string, postable *PostableReport, now time.Time) (*Report, error) {
+	report := &Report{
+		Identifiable: types.Identifiable{
+			ID: valuer.GenerateUUID(),
+		},
+		TimeAuditable: types.TimeAuditable{
+			CreatedAt: now,
+			UpdatedAt: now,
+		},
+		UserAuditable: types.UserAuditable{
+			CreatedBy: createdBy,
+			UpdatedBy: createdBy,
+		},
+		OrgID: orgID,
+	}
+
+	if err := report.Update(createdBy, postable, now); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+func NewDelivery(report *Report, trigger Trigger, recipients []string, now time.Time) *Delivery {
+	return &Delivery{
+		Identifiable: types.Identifiable{
+			ID: valuer.GenerateUUID(),
+		},
+		OrgID:      report.OrgID,
+		ReportID:   report.ID,
+		Trigger:    trigger,
+		Status:     StatusSuccess,
+		Recipients: recipients,
+		StartedAt:  now,
+	}
+}
+
+// Update replaces the definition of the report and reschedules it from now.
+func (report *Report) Update(updatedBy string, postable *PostableReport, now time.Time) error {
+	if err := postable.Validate(); err != nil {
+		return err
+	}
+
+	report.Name = postable.Name
+	report.DashboardID = postable.DashboardID
+	report.WidgetIDs = postable.WidgetIDs
+	report.Panels = postable.Panels
+	report.Variables = postable.Variables
+	report.Cron = postable.Cron
+	report.Timezone = postable.Timezone
+	report.TimeRange = postable.TimeRange
+	report.Recipients = postable.Recipients
+	report.AttachCSV = postable.AttachCSV
+	report.Enabled = postable.Enabled
+	report.UpdatedBy = updatedBy
+	report.UpdatedAt = now
+
+	return report.Reschedule(now)
+}
+
+// Reschedule sets the next run of the report to the first time of its schedule after now.
+func (report *Report) Reschedule(now time.Time) error {
+	schedule, location, err := parseSchedule(report.Cron, report.Timezone)
+	if err != nil {
+		return err
+	}
+
+	report.NextRunAt = schedule.Next(now.In(location)).UTC()
+	return nil
+}
+
+// Due tells whether the scheduled run of the report has come.
+func (report *Report) Due(now time.Time) bool {
+	return report.Enabled && !now.Before(report.NextRunAt)
+}
+
+// Resolve returns the start and end of the time range of the report in epoch milliseconds, ending at now.
+func (report *Report) Resolve(now time.Time) (uint64, uint64) {
+	duration, _ := model.ParseDuration(report.TimeRange)
+	return uint64(now.Add(-time.Duration(duration)).UnixMilli()), uint64(now.UnixMilli())
+}
+
+// Location returns the timezone of the report, the times of the email are shown in it.
+func (report *Report) Location() *time.Location {
+	location, err := time.LoadLocation(report.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+
+	return location
+}
+
+// Finish records the end of the delivery, panelErrs are the errors of the panels which failed to query and err the
+// one which prevented the report from being sent.
+func (delivery *Delivery) Finish(panelErrs []error, err error, now time.Time) {
+	delivery.FinishedAt = now
+
+	switch {
+	case err != nil:
+		delivery.Status = StatusFailed
+		delivery.Error = ErrorMessage(err)
+	case len(panelErrs) > 0:
+		delivery.Status = StatusPartial
+		delivery.Error = ErrorMessage(errors.Join(panelErrs...))
+	default:
+		delivery.Status = StatusSuccess
+		delivery.Error = ""
+	}
+}
+
+func (p *PostableReport) Validate() error {
+	if p.Name == "" {
+		return errors.New(errors.TypeInvalidInput, ErrCodeReportInvalid, "name is required")
+	}
+
+	if (p.DashboardID == "") == (len(p.Panels) == 0) {
+		return errors.New(errors.TypeInvalidInput, ErrCodeReportInvalid, "report requires either a dashboardId or panels")
+	}
+
+	if p.DashboardID != "" {
+		if _, err := valuer.NewUUID(p.DashboardID); err != nil {
+			return errors.Wrapf(err, errors.TypeInvalidInput, ErrCodeReportInvalid, "invalid dashboardId: %s", p.DashboardID)
+		}
+	}
+
+	if len(p.WidgetIDs) > 0 && p.DashboardID == "" {
+		return errors.New(errors.TypeInvalidInput, ErrCodeReportInvalid, "widgetIds can only be set along with a dashboardId")
+	}
+
+	if len(p.Panels) > MaxPanels || len(p.WidgetIDs) > MaxPanels {
+		return errors.Newf(errors.TypeInvalidInput, ErrCodeReportInvalid, "report must not have more than %d panels", MaxPanels)
+	}
+
+	for i, panel := range p.Panels {
+		if err := panel.Validate(); err != nil {
+			return errors.Wrapf(err, errors.TypeInvalidInput, ErrCodeReportInvalid, "invalid panel: %d", i)
+		}
+	}
+
+	if _, _, err := parseSchedule(p.Cron, p.Timezone); err != nil {
+		return err
+	}
+
+	duration, err := model.ParseDuration(p.TimeRange)
+	if err != nil || duration <= 0 {
+		return errors.Newf(errors.TypeInvalidInput, ErrCodeReportInvalid, "invalid timeRange: %q, must be a duration such as 24h or 7d", p.TimeRange)
+	}
+
+	if time.Duration(duration) > MaxTimeRange {
+		return errors.Newf(errors.TypeInvalidInput, ErrCodeReportInvalid, "timeRange must not be longer than %s", MaxTimeRange)
+	}
+
+	return ValidateRecipients(p.Recipients)
+}
+
+func (panel *Panel) Validate() error {
+	if panel == nil {
+		return errors.New(errors.TypeInvalidInput, ErrCodeReportInvalid, "panel is required")
+	}
+
+	if panel.Title == "" {
+		return errors.New(errors.TypeInvalidInput, ErrCodeReportInvalid, "title is required")
+	}
+
+	if !slices.Contains(panel.PanelType.Enum(), string(panel.PanelType)) || panel.PanelType == dashboardtypes.PanelTypeRow || panel.PanelType == dashboardtypes.PanelTypeEmptyWidget {
+		return errors.Newf(errors.TypeInvalidInput, ErrCodeReportInvalid, "invalid panelType: %q", panel.PanelType)
+	}
+
+	compositeQuery := &qbtypes.CompositeQuery{Queries: panel.Queries}
+	return compositeQuery.Validate(panel.PanelType.RequestType())
+}
+
+// ValidateRecipients validates the addresses a report is sent to.
+func ValidateRecipients(recipients []string) error {
+	if len(recipients) == 0 {
+		return errors.New(errors.TypeInvalidInput, ErrCodeReportInvalid, "recipients are required")
+	}
+
+	if len(recipients) > MaxRecipients {
+		return errors.Newf(errors.TypeInvalidInput, ErrCodeReportInvalid, "report must not have more than %d recipients", MaxRecipients)
+	}
+
+	for _, recipient := range recipients {
+		if _, err := mail.ParseAddress(recipient); err != nil {
+			return errors.Wrapf(err, errors.TypeInvalidInput, ErrCodeReportInvalid, "invalid recipient: %q", recipient)
+		}
+	}
+
+	return nil
+}
+
+// ErrorMessage returns the messages of the error and of the errors it wraps, as shown in the emails and the history of
+// the reports.
+func ErrorMessage(err error) string {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		messages := make([]string, 0)
+		for _, err := range joined.Unwrap() {
+			messages = append(messages, ErrorMessage(err))
+		}
+
+		return strings.Join(messages, "; ")
+	}
+
+	_, _, message, cause, _, _ := errors.Unwrapb(err)
+	if cause == nil || cause == err {
+		return message
+	}
+
+	return message + ": " + ErrorMessage(cause)
+}
+
+func parseSchedule(expression string, timezone string) (cron.Schedule, *time.Location, error) {
+	schedule, err := cron.ParseStandard(expression)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, errors.TypeInvalidInput, ErrCodeReportInvalid, "invalid cron: %q, must be a standard cron expression such as 0 9 * * 1", expression)
+	}
+
+	if timezone == "" {
+		return nil, nil, errors.New(errors.TypeInvalidInput, ErrCodeReportInvalid, "timezone is required")
+	}
+
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, errors.TypeInvalidInput, ErrCodeReportInvalid, "invalid timezone: %q", timezone)
+	}
+
+	return schedule, location, nil
+}
@@ -0,0 +1,129 @@
+package reporttypes
+
+import (
+	"testing"
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/errors"
+	"github.com/SigNoz/signoz/pkg/types/dashboardtypes"
+	qbtypes "github.com/SigNoz/signoz/pkg/types/querybuildertypes/querybuildertypesv5"
+	"github.com/SigNoz/signoz/pkg/valuer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostableReportValidate(t *testing.T) {
+	valid := func() *PostableReport {
+		return &PostableReport{
+			Name:        "daily",
+			DashboardID: valuer.GenerateUUID().StringValue(),
+			Cron:        "0 9 * * *",
+			Timezone:    "Europe/Berlin",
+			TimeRange:   "1d",
+			Recipients:  []string{"oncall@example.com"},
+		}
+	}
+
+	require.NoError(t, valid().Validate())
+
+	testCases := []struct {
+		name   string
+		mutate func(*PostableReport)
+	}{
+		{name: "MissingName", mutate: func(p *PostableReport) { p.Name = "" }},
+		{name: "MissingSource", mutate: func(p *PostableReport) { p.DashboardID = "" }},
+		{name: "DashboardAndPanels", mutate: func(p *PostableReport) { p.Panels = []*Panel{{Title: "errors"}} }},
+		{name: "InvalidDashboardID", mutate: func(p *PostableReport) { p.DashboardID = "dashboard" }},
+		{name: "WidgetsWithoutDashboard", mutate: func(p *PostableReport) {
+			p.DashboardID = ""
+			p.WidgetIDs = []string{"a"}
+		}},
+		{name: "PanelWithoutQueries", mutate: func(p *PostableReport) {
+			p.DashboardID = ""
+			p.Panels = []*Panel{{Title: "errors", PanelType: dashboardtypes.PanelTypeGraph}}
+		}},
+		{name: "RowPanel", mutate: func(p *PostableReport) {
+			p.DashboardID = ""
+			p.Panels = []*Panel{{Title: "errors", PanelType: dashboardtypes.PanelTypeRow}}
+		}},
+		{name: "InvalidCron", mutate: func(p *PostableReport) { p.Cron = "every day" }},
+		{name: "MissingTimezone", mutate: func(p *PostableReport) { p.Timezone = "" }},
+		{name: "InvalidTimezone", mutate: func(p *PostableReport) { p.Timezone = "Mars/Olympus" }},
+		{name: "InvalidTimeRange", mutate: func(p *PostableReport) { p.TimeRange = "yesterday" }},
+		{name: "TimeRangeTooLong", mutate: func(p *PostableReport) { p.TimeRange = "90d" }},
+		{name: "MissingRecipients", mutate: func(p *PostableReport) { p.Recipients = nil }},
+		{name: "InvalidRecipient", mutate: func(p *PostableReport) { p.Recipients = []string{"oncall"} }},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			postable := valid()
+			testCase.mutate(postable)
+			assert.True(t, errors.Ast(postable.Validate(), errors.TypeInvalidInput))
+		})
+	}
+}
+
+func TestPanelValidate(t *testing.T) {
+	panel := &Panel{
+		Title:     "errors",
+		PanelType: dashboardtypes.PanelTypeValue,
+		Queries: []qbtypes.QueryEnvelope{{
+			Type: qbtypes.QueryTypePromQL,
+			Spec: qbtypes.PromQuery{Name: "A", Query: "sum(up)"},
+		}},
+	}
+
+	assert.NoError(t, panel.Validate())
+}
+
+func TestReportReschedule(t *testing.T) {
+	location, err := time.LoadLocation("Asia/Kolkata")
+	require.NoError(t, err)
+
+	report, err := NewReport(valuer.GenerateUUID(), "creator@example.com", &PostableReport{
+		Name:        "weekly",
+		DashboardID: valuer.GenerateUUID().StringValue(),
+		Cron:        "30 9 * * 1",
+		Timezone:    "Asia/Kolkata",
+		TimeRange:   "1w",
+		Recipients:  []string{"oncall@example.com"},
+		Enabled:     true,
+	}, time.Date(2025, 6, 4, 12, 0, 0, 0, location))
+	require.NoError(t, err)
+
+	// the next monday at 09:30 in the timezone of the report
+	assert.True(t, time.Date(2025, 6, 9, 9, 30, 0, 0, location).Equal(report.NextRunAt))
+	assert.False(t, report.Due(report.NextRunAt.Add(-time.Second)))
+	assert.True(t, report.Due(report.NextRunAt))
+
+	start, end := report.Resolve(report.NextRunAt)
+	assert.Equal(t, uint64(7*24*time.Hour/time.Millisecond), end-start)
+
+	require.NoError(t, report.Reschedule(report.NextRunAt))
+	assert.True(t, time.Date(2025, 6, 16, 9, 30, 0, 0, location).Equal(report.NextRunAt))
+
+	report.Enabled = false
+	assert.False(t, report.Due(report.NextRunAt))
+}
+
+func TestDeliveryFinish(t *testing.T) {
+	report := &Report{OrgID: valuer.GenerateUUID()}
+	now := time.Now()
+
+	delivery := NewDelivery(report, TriggerManual, []string{"oncall@example.com"}, now)
+	delivery.Finish(nil, nil, now)
+	assert.Equal(t, StatusSuccess, delivery.Status)
+	assert.Empty(t, delivery.Error)
+
+	delivery.Finish([]error{
+		errors.Wrapf(errors.New(errors.TypeInvalidInput, errors.CodeInvalidInput, "unknown metric"), errors.TypeInternal, errors.CodeInternal, "hits"),
+		errors.New(errors.TypeInternal, errors.CodeInternal, "keys"),
+	}, nil, now)
+	assert.Equal(t, StatusPartial, delivery.Status)
+	assert.Equal(t, "hits: unknown metric; keys", delivery.Error)
+
+	delivery.Finish(nil, errors.Join(errors.New(errors.TypeInternal, errors.CodeInternal, "smtp failed")), now)
+	assert.Equal(t, StatusFailed, delivery.Status)
+	assert.Equal(t, "smtp failed", delivery.Error)
+}
@@ -0,0 +1,273 @@
+package reporttypes
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/errors"
+	qbtypes "github.com/SigNoz/signoz/pkg/types/querybuildertypes/querybuildertypesv5"
+)
+
+// MaxRows is the number of rows of the tables of an email, the CSV attachments have every row.
+const MaxRows = 50
+
+// Table is a table of the results of a panel.
+type Table struct {
+	Columns []string
+	Rows    [][]string
+}
+
+// Series is a time series of the results of a panel, its points are sorted by time.
+type Series struct {
+	Name   string
+	Points []*qbtypes.TimeSeriesValue
+}
+
+// Results are the results of the queries of a panel.
+type Results struct {
+	RequestType qbtypes.RequestType
+	TimeSeries  []*qbtypes.TimeSeriesData
+	Scalar      []*qbtypes.ScalarData
+	Raw         []*qbtypes.RawData
+}
+
+// NewResults decodes the results of a query range response according to its request type.
+func NewResults(response *qbtypes.QueryRangeResponse) (*Results, error) {
+	raw, err := json.Marshal(response.Data)
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.TypeInternal, errors.CodeInternal, "failed to encode the results of the query")
+	}
+
+	envelope := struct {
+		Results []json.RawMessage `json:"results"`
+	}{}
+	if err := json.Unmarshal(raw, &envelope); err != nil {
+		return nil, errors.Wrapf(err, errors.TypeInternal, errors.CodeInternal, "failed to decode the results of the query")
+	}
+
+	results := &Results{RequestType: response.Type}
+	for _, result := range envelope.Results {
+		var err error
+		switch response.Type {
+		case qbtypes.RequestTypeTimeSeries:
+			data := new(qbtypes.TimeSeriesData)
+			err = json.Unmarshal(result, data)
+			results.TimeSeries = append(results.TimeSeries, data)
+		case qbtypes.RequestTypeScalar:
+			data := new(qbtypes.ScalarData)
+			err = json.Unmarshal(result, data)
+			results.Scalar = append(results.Scalar, data)
+		case qbtypes.RequestTypeRaw:
+			data := new(qbtypes.RawData)
+			err = json.Unmarshal(result, data)
+			results.Raw = append(results.Raw, data)
+		default:
+			return nil, errors.Newf(errors.TypeUnsupported, errors.CodeUnsupported, "results of type: %s cannot be reported", response.Type.StringValue())
+		}
+
+		if err != nil {
+			return nil, errors.Wrapf(err, errors.TypeInternal, errors.CodeInternal, "failed to decode the results of the query")
+		}
+	}
+
+	slices.SortFunc(results.TimeSeries, func(a, b *qbtypes.TimeSeriesData) int { return strings.Compare(a.QueryName, b.QueryName) })
+	slices.SortFunc(results.Scalar, func(a, b *qbtypes.ScalarData) int { return strings.Compare(a.QueryName, b.QueryName) })
+	slices.SortFunc(results.Raw, func(a, b *qbtypes.RawData) int { return strings.Compare(a.QueryName, b.QueryName) })
+	return results, nil
+}
+
+// Series flattens the time series of the results, the series are named after their query, aggregation and labels.
+func (results *Results) Series() []*Series {
+	series := make([]*Series, 0)
+	for _, data := range results.TimeSeries {
+		for _, bucket := range data.Aggregations {
+			if bucket == nil {
+				continue
+			}
+
+			name := data.QueryName
+			if bucket.Alias != "" {
+				name = bucket.Alias
+			} else if len(data.Aggregations) > 1 {
+				name = fmt.Sprintf("%s.%d", data.QueryName, bucket.Index)
+			}
+
+			for _, timeSeries := range bucket.Series {
+				if timeSeries == nil {
+					continue
+				}
+
+				points := slices.DeleteFunc(slices.Clone(timeSeries.Values), func(value *qbtypes.TimeSeriesValue) bool { return value == nil })
+				slices.SortFunc(points, func(a, b *qbtypes.TimeSeriesValue) int { return cmp.Compare(a.Timestamp, b.Timestamp) })
+				series = append(series, &Series{Name: name + formatLabels(timeSeries.Labels), Points: points})
+			}
+		}
+	}
+
+	return series
+}
+
+// Tables returns the tables of the results, a table per query. Time series are summarized in a single table with
+// their min, avg, max and last values, a row per series in the order of Series.
+func (results *Results) Tables() []*Table {
+	if results.RequestType != qbtypes.RequestTypeTimeSeries {
+		return results.rows()
+	}
+
+	table := &Table{Columns: []string{"series", "min", "avg", "max", "last"}}
+	for _, series := range results.Series() {
+		if len(series.Points) == 0 {
+			table.Rows = append(table.Rows, []string{series.Name, "", "", "", ""})
+			continue
+		}
+
+		minimum, maximum, sum := series.Points[0].Value, series.Points[0].Value, 0.0
+		for _, point := range series.Points {
+			minimum, maximum, sum = min(minimum, point.Value), max(maximum, point.Value), sum+point.Value
+		}
+
+		table.Rows = append(table.Rows, []string{
+			series.Name,
+			formatFloat(minimum),
+			formatFloat(sum / float64(len(series.Points))),
+			formatFloat(maximum),
+			formatFloat(series.Points[len(series.Points)-1].Value),
+		})
+	}
+
+	return []*Table{table}
+}
+
+// CSV returns the results as CSV, time series have a row per point and the tables of the other results are separated
+// by an empty line.
+func (results *Results) CSV() ([]byte, error) {
+	tables := results.rows()
+	if results.RequestType == qbtypes.RequestTypeTimeSeries {
+		table := &Table{Columns: []string{"series", "timestamp", "value"}}
+		for _, series := range results.Series() {
+			for _, point := range series.Points {
+				table.Rows = append(table.Rows, []string{series.Name, time.UnixMilli(point.Timestamp).UTC().Format(time.RFC3339), formatFloat(point.Value)})
+			}
+		}
+		tables = []*Table{table}
+	}
+
+	buffer := &bytes.Buffer{}
+	for i, table := range tables {
+		if i > 0 {
+			buffer.WriteString("\n")
+		}
+
+		writer := csv.NewWriter(buffer)
+		if err := writer.Write(table.Columns); err != nil {
+			return nil, errors.Wrapf(err, errors.TypeInternal, errors.CodeInternal, "failed to write csv")
+		}
+
+		if err := writer.WriteAll(table.Rows); err != nil {
+			return nil, errors.Wrapf(err, errors.TypeInternal, errors.CodeInternal, "failed to write csv")
+		}
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// rows returns a table with every row of each scalar and raw result.
+func (results *Results) rows() []*Table {
+	tables := make([]*Table, 0, len(results.Scalar)+len(results.Raw))
+	for _, data := range results.Scalar {
+		table := &Table{}
+		for _, column := range data.Columns {
+			name := column.Name
+			// unnamed aggregations are named after their query
+			if column.Type == qbtypes.ColumnTypeAggregation && (name == "" || strings.HasPrefix(name, "__result")) {
+				name = column.QueryName
+				if column.AggregationIndex > 0 {
+					name = fmt.Sprintf("%s.%d", column.QueryName, column.AggregationIndex)
+				}
+			}
+			table.Columns = append(table.Columns, name)
+		}
+
+		for _, row := range data.Data {
+			cells := make([]string, 0, len(row))
+			for _, value := range row {
+				cells = append(cells, formatValue(value))
+			}
+			table.Rows = append(table.Rows, cells)
+		}
+
+		tables = append(tables, table)
+	}
+
+	for _, data := range results.Raw {
+		keys := make(map[string]struct{})
+		for _, row := range data.Rows {
+			for key := range row.Data {
+				keys[key] = struct{}{}
+			}
+		}
+
+		columns := slices.Sorted(maps.Keys(keys))
+		table := &Table{Columns: append([]string{"timestamp"}, columns...)}
+		for _, row := range data.Rows {
+			cells := []string{row.Timestamp.UTC().Format(time.RFC3339)}
+			for _, column := range columns {
+				var value any
+				if pointer := row.Data[column]; pointer != nil {
+					value = *pointer
+				}
+				cells = append(cells, formatValue(value))
+			}
+			table.Rows = append(table.Rows, cells)
+		}
+
+		tables = append(tables, table)
+	}
+
+	return tables
+}
+
+func formatLabels(labels []*qbtypes.Label) string {
+	pairs := make([]string, 0, len(labels))
+	for _, label := range labels {
+		if label != nil {
+			pairs = append(pairs, label.Key.Name+"="+formatValue(label.Value))
+		}
+	}
+
+	if len(pairs) == 0 {
+		return ""
+	}
+
+	return "{" + strings.Join(pairs, ", ") + "}"
+}
+
+func formatValue(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case float64:
+		return formatFloat(value)
+	default:
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Sprint(value)
+		}
+
+		return string(raw)
+	}
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
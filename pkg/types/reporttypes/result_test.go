@@ -0,0 +1,97 @@
+package reporttypes
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+	"time"
+
+	qbtypes "github.com/SigNoz/signoz/pkg/types/querybuildertypes/querybuildertypesv5"
+	"github.com/SigNoz/signoz/pkg/types/telemetrytypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newResponse(requestType qbtypes.RequestType, results ...any) *qbtypes.QueryRangeResponse {
+	return &qbtypes.QueryRangeResponse{
+		Type: requestType,
+		Data: struct {
+			Results  []any    `json:"results"`
+			Warnings []string `json:"warnings"`
+		}{Results: results},
+	}
+}
+
+func TestResultsTimeSeries(t *testing.T) {
+	start := time.UnixMilli(1_700_000_000_000)
+	results, err := NewResults(newResponse(qbtypes.RequestTypeTimeSeries, &qbtypes.TimeSeriesData{
+		QueryName: "A",
+		Aggregations: []*qbtypes.AggregationBucket{{
+			Series: []*qbtypes.TimeSeries{{
+				Labels: []*qbtypes.Label{{Key: telemetrytypes.TelemetryFieldKey{Name: "host"}, Value: "redis-0"}},
+				Values: []*qbtypes.TimeSeriesValue{
+					{Timestamp: start.Add(time.Minute).UnixMilli(), Value: 4},
+					{Timestamp: start.UnixMilli(), Value: 2},
+				},
+			}, {
+				Labels: []*qbtypes.Label{{Key: telemetrytypes.TelemetryFieldKey{Name: "host"}, Value: "redis-1"}},
+			}},
+		}},
+	}))
+	require.NoError(t, err)
+
+	series := results.Series()
+	require.Len(t, series, 2)
+	assert.Equal(t, "A{host=redis-0}", series[0].Name)
+	assert.Equal(t, start.UnixMilli(), series[0].Points[0].Timestamp)
+
+	tables := results.Tables()
+	require.Len(t, tables, 1)
+	assert.Equal(t, [][]string{{"A{host=redis-0}", "2", "3", "4", "4"}, {"A{host=redis-1}", "", "", "", ""}}, tables[0].Rows)
+
+	csv, err := results.CSV()
+	require.NoError(t, err)
+	assert.Equal(t, "series,timestamp,value\nA{host=redis-0},2023-11-14T22:13:20Z,2\nA{host=redis-0},2023-11-14T22:14:20Z,4\n", string(csv))
+
+	chart, err := NewChart(series, start.UnixMilli(), start.Add(time.Hour).UnixMilli())
+	require.NoError(t, err)
+	assert.Equal(t, 2.0, chart.Min)
+	assert.Equal(t, 4.0, chart.Max)
+
+	img, err := png.Decode(bytes.NewReader(chart.Image))
+	require.NoError(t, err)
+	assert.Equal(t, ChartWidth, img.Bounds().Dx())
+	assert.Equal(t, ChartHeight, img.Bounds().Dy())
+}
+
+func TestResultsScalarAndRaw(t *testing.T) {
+	results, err := NewResults(newResponse(qbtypes.RequestTypeScalar, &qbtypes.ScalarData{
+		QueryName: "A",
+		Columns: []*qbtypes.ColumnDescriptor{
+			{TelemetryFieldKey: telemetrytypes.TelemetryFieldKey{Name: "service.name"}, QueryName: "A", Type: qbtypes.ColumnTypeGroup},
+			{TelemetryFieldKey: telemetrytypes.TelemetryFieldKey{Name: "__result_0"}, QueryName: "A", Type: qbtypes.ColumnTypeAggregation},
+		},
+		Data: [][]any{{"frontend", 12.5}, {"checkout", 3}},
+	}))
+	require.NoError(t, err)
+
+	tables := results.Tables()
+	require.Len(t, tables, 1)
+	assert.Equal(t, []string{"service.name", "A"}, tables[0].Columns)
+	assert.Equal(t, [][]string{{"frontend", "12.5"}, {"checkout", "3"}}, tables[0].Rows)
+
+	body := any("connection refused")
+	results, err = NewResults(newResponse(qbtypes.RequestTypeRaw, &qbtypes.RawData{
+		QueryName: "A",
+		Rows:      []*qbtypes.RawRow{{Timestamp: time.UnixMilli(1_700_000_000_000), Data: map[string]*any{"body": &body, "trace_id": nil}}},
+	}))
+	require.NoError(t, err)
+
+	tables = results.Tables()
+	require.Len(t, tables, 1)
+	assert.Equal(t, []string{"timestamp", "body", "trace_id"}, tables[0].Columns)
+	assert.Equal(t, [][]string{{"2023-11-14T22:13:20Z", "connection refused", ""}}, tables[0].Rows)
+
+	_, err = NewResults(newResponse(qbtypes.RequestTypeDistribution, &qbtypes.TimeSeriesData{}))
+	assert.Error(t, err)
+}
@@ -0,0 +1,23 @@
+package reporttypes
+
+import (
+	"context"
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/valuer"
+)
+
+type Store interface {
+	Create(context.Context, *Report) error
+	Get(context.Context, valuer.UUID, valuer.UUID) (*Report, error)
+	List(context.Context, valuer.UUID) ([]*Report, error)
+	// ListDue lists the enabled reports of the given orgs whose next run is due at the given time.
+	ListDue(context.Context, []valuer.UUID, time.Time) ([]*Report, error)
+	Update(context.Context, *Report) error
+	// Claim stores the next run of the report only while its run is still due at the given time, it reports whether
+	// the run was claimed so that a run is sent once when several instances find it due.
+	Claim(context.Context, *Report, time.Time) (bool, error)
+	Delete(context.Context, valuer.UUID, valuer.UUID) error
+	CreateDelivery(context.Context, *Delivery) error
+	ListDeliveries(context.Context, valuer.UUID, valuer.UUID) ([]*Delivery, error)
+}
@@ -32,6 +32,11 @@ func (r Role) String() string {
 	return string(r)
 }
 
+// IsLowerThan reports whether the role grants less than the other one.
+func (r Role) IsLowerThan(other Role) bool {
+	return roleRanks[r] < roleRanks[other]
+}
+
 func (r *Role) UnmarshalJSON(data []byte) error {
 	var s string
 	if err := json.Unmarshal(data, &s); err != nil {
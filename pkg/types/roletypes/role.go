@@ -0,0 +1,256 @@
+package roletypes
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/errors"
+	"github.com/SigNoz/signoz/pkg/types"
+	"github.com/SigNoz/signoz/pkg/types/authtypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+	"github.com/uptrace/bun"
+)
+
+var (
+	ErrCodeRoleAlreadyExists           = errors.MustNewCode("role_already_exists")
+	ErrCodeRoleNotFound                = errors.MustNewCode("role_not_found")
+	ErrCodeRoleAssignmentAlreadyExists = errors.MustNewCode("role_assignment_already_exists")
+	ErrCodeRoleAssignmentNotFound      = errors.MustNewCode("role_assignment_not_found")
+)
+
+var nameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9 _.-]{0,63}$`)
+
+// SubjectType is the kind of principal a custom role is assigned to.
+type SubjectType struct{ valuer.String }
+
+var (
+	SubjectTypeUser   = SubjectType{valuer.NewString("user")}
+	SubjectTypeAPIKey = SubjectType{valuer.NewString("api_key")}
+)
+
+func NewSubjectType(subjectType string) (SubjectType, error) {
+	switch subjectType {
+	case SubjectTypeUser.StringValue():
+		return SubjectTypeUser, nil
+	case SubjectTypeAPIKey.StringValue():
+		return SubjectTypeAPIKey, nil
+	}
+
+	return SubjectType{}, errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "invalid subject type: %s, must be one of user, api_key", subjectType)
+}
+
+func (s *SubjectType) UnmarshalJSON(data []byte) error {
+	var str valuer.String
+	if err := str.UnmarshalJSON(data); err != nil {
+		return err
+	}
+
+	subjectType, err := NewSubjectType(str.StringValue())
+	if err != nil {
+		return err
+	}
+
+	*s = subjectType
+	return nil
+}
+
+// Subject is a user or an api key a custom role is assigned to.
+type Subject struct {
+	Type SubjectType `json:"type"`
+	ID   valuer.UUID `json:"id"`
+}
+
+// NewSubjectFromClaims returns the subject whose custom roles apply to the claims, requests authenticated
+// with an api key get the permissions of the key and not the ones of the user who created it.
+func NewSubjectFromClaims(claims authtypes.Claims) (Subject, error) {
+	if claims.APIKeyID != "" {
+		id, err := valuer.NewUUID(claims.APIKeyID)
+		if err != nil {
+			return Subject{}, err
+		}
+		return Subject{Type: SubjectTypeAPIKey, ID: id}, nil
+	}
+
+	id, err := valuer.NewUUID(claims.UserID)
+	if err != nil {
+		return Subject{}, err
+	}
+	return Subject{Type: SubjectTypeUser, ID: id}, nil
+}
+
+// StorableRole is a custom role of an org. The permissions are stored as a JSON array.
+type StorableRole struct {
+	bun.BaseModel `bun:"table:custom_role"`
+
+	types.Identifiable
+	types.TimeAuditable
+	types.UserAuditable
+	OrgID       valuer.UUID `bun:"org_id,type:text,notnull"`
+	Name        string      `bun:"name,type:text,notnull"`
+	Description string      `bun:"description,type:text"`
+	Permissions string      `bun:"permissions,type:text,notnull"`
+}
+
+type StorableRoleAssignment struct {
+	bun.BaseModel `bun:"table:custom_role_assignment"`
+
+	types.Identifiable
+	types.TimeAuditable
+	OrgID       valuer.UUID `bun:"org_id,type:text,notnull"`
+	RoleID      valuer.UUID `bun:"role_id,type:text,notnull"`
+	SubjectType SubjectType `bun:"subject_type,type:text,notnull"`
+	SubjectID   valuer.UUID `bun:"subject_id,type:text,notnull"`
+}
+
+type GettableRole struct {
+	types.Identifiable
+	types.TimeAuditable
+	types.UserAuditable
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Permissions []authtypes.Permission `json:"permissions"`
+	Subjects    []Subject              `json:"subjects"`
+}
+
+type PostableRole struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Permissions []authtypes.Permission `json:"permissions"`
+}
+
+// GettablePermissions are the effective permissions of the caller.
+type GettablePermissions struct {
+	Role        types.Role             `json:"role"`
+	CustomRoles []string               `json:"customRoles"`
+	Permissions []authtypes.Permission `json:"permissions"`
+}
+
+func (p *PostableRole) Validate() error {
+	if !nameRegex.MatchString(p.Name) {
+		return errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "invalid name %q, names of roles must match %s", p.Name, nameRegex.String())
+	}
+
+	if _, err := types.NewRole(p.Name); err == nil {
+		return errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "%s is a built-in role", p.Name)
+	}
+
+	if len(p.Permissions) == 0 {
+		return errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "roles must have at least one permission")
+	}
+
+	for _, permission := range p.Permissions {
+		if _, err := authtypes.NewPermission(permission.StringValue()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func NewStorableRole(orgID valuer.UUID, createdBy string, postable *PostableRole) (*StorableRole, error) {
+	permissions, err := json.Marshal(postable.Permissions)
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.TypeInternal, errors.CodeInternal, "failed to serialize the permissions of the role")
+	}
+
+	return &StorableRole{
+		Identifiable: types.Identifiable{ID: valuer.GenerateUUID()},
+		TimeAuditable: types.TimeAuditable{
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		},
+		UserAuditable: types.UserAuditable{
+			CreatedBy: createdBy,
+			UpdatedBy: createdBy,
+		},
+		OrgID:       orgID,
+		Name:        postable.Name,
+		Description: postable.Description,
+		Permissions: string(permissions),
+	}, nil
+}
+
+// Update overwrites the definition of the stored role with `postable`.
+func (s *StorableRole) Update(updatedBy string, postable *PostableRole) error {
+	permissions, err := json.Marshal(postable.Permissions)
+	if err != nil {
+		return errors.Wrapf(err, errors.TypeInternal, errors.CodeInternal, "failed to serialize the permissions of the role")
+	}
+
+	s.Name = postable.Name
+	s.Description = postable.Description
+	s.Permissions = string(permissions)
+	s.UpdatedAt = time.Now()
+	s.UpdatedBy = updatedBy
+	return nil
+}
+
+// GetPermissions returns the permissions of the role, the ones that don't exist anymore are skipped.
+func (s *StorableRole) GetPermissions() ([]authtypes.Permission, error) {
+	var names []string
+	if err := json.Unmarshal([]byte(s.Permissions), &names); err != nil {
+		return nil, errors.Wrapf(err, errors.TypeInternal, errors.CodeInternal, "failed to parse the permissions of role %s", s.Name)
+	}
+
+	permissions := []authtypes.Permission{}
+	for _, name := range names {
+		if permission, err := authtypes.NewPermission(name); err == nil {
+			permissions = append(permissions, permission)
+		}
+	}
+
+	return permissions, nil
+}
+
+func NewGettableRole(storable *StorableRole, assignments []*StorableRoleAssignment) (*GettableRole, error) {
+	permissions, err := storable.GetPermissions()
+	if err != nil {
+		return nil, err
+	}
+
+	subjects := []Subject{}
+	for _, assignment := range assignments {
+		subjects = append(subjects, Subject{Type: assignment.SubjectType, ID: assignment.SubjectID})
+	}
+
+	return &GettableRole{
+		Identifiable:  storable.Identifiable,
+		TimeAuditable: storable.TimeAuditable,
+		UserAuditable: storable.UserAuditable,
+		Name:          storable.Name,
+		Description:   storable.Description,
+		Permissions:   permissions,
+		Subjects:      subjects,
+	}, nil
+}
+
+func NewStorableRoleAssignment(orgID valuer.UUID, roleID valuer.UUID, subject Subject) *StorableRoleAssignment {
+	return &StorableRoleAssignment{
+		Identifiable: types.Identifiable{ID: valuer.GenerateUUID()},
+		TimeAuditable: types.TimeAuditable{
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		},
+		OrgID:       orgID,
+		RoleID:      roleID,
+		SubjectType: subject.Type,
+		SubjectID:   subject.ID,
+	}
+}
+
+type Store interface {
+	Create(context.Context, *StorableRole) error
+	Get(context.Context, valuer.UUID, valuer.UUID) (*StorableRole, error)
+	List(context.Context, valuer.UUID) ([]*StorableRole, error)
+	Update(context.Context, *StorableRole) error
+	// Delete deletes the role along with its assignments.
+	Delete(context.Context, valuer.UUID, valuer.UUID) error
+
+	CreateAssignment(context.Context, *StorableRoleAssignment) error
+	DeleteAssignment(context.Context, valuer.UUID, valuer.UUID, Subject) error
+	ListAssignments(context.Context, valuer.UUID) ([]*StorableRoleAssignment, error)
+	// ListBySubject lists the roles assigned to the subject.
+	ListBySubject(context.Context, valuer.UUID, Subject) ([]*StorableRole, error)
+}
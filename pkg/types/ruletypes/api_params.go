@@ -54,6 +54,8 @@ type PostableRule struct {
 	RuleCondition *RuleCondition    `yaml:"condition,omitempty" json:"condition,omitempty"`
 	Labels        map[string]string `yaml:"labels,omitempty" json:"labels,omitempty"`
 	Annotations   map[string]string `yaml:"annotations,omitempty" json:"annotations,omitempty"`
+	// Tags group rules for searching, unlike labels they are not added to the alerts
+	Tags []string `yaml:"tags,omitempty" json:"tags,omitempty"`
 
 	Disabled bool `json:"disabled"`
 
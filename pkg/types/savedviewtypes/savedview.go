@@ -0,0 +1,393 @@
+package savedviewtypes
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/errors"
+	"github.com/SigNoz/signoz/pkg/types"
+	"github.com/SigNoz/signoz/pkg/types/authtypes"
+	"github.com/SigNoz/signoz/pkg/types/dashboardtypes"
+	qbtypes "github.com/SigNoz/signoz/pkg/types/querybuildertypes/querybuildertypesv5"
+	"github.com/SigNoz/signoz/pkg/valuer"
+	"github.com/uptrace/bun"
+)
+
+var (
+	ErrCodeSavedViewNotFound = errors.MustNewCode("saved_view_not_found")
+	ErrCodeSavedViewInvalid  = errors.MustNewCode("saved_view_invalid")
+)
+
+const maxNameLength = 256
+
+type Visibility struct{ valuer.String }
+
+var (
+	// VisibilityPrivate views are only visible to their creator.
+	VisibilityPrivate = Visibility{valuer.NewString("private")}
+	// VisibilityTeam views are visible to the members of their team.
+	VisibilityTeam = Visibility{valuer.NewString("team")}
+	// VisibilityOrg views are visible to every user of the org.
+	VisibilityOrg = Visibility{valuer.NewString("org")}
+)
+
+var visibilities = []Visibility{VisibilityPrivate, VisibilityTeam, VisibilityOrg}
+
+func NewVisibility(visibility string) (Visibility, error) {
+	for _, v := range visibilities {
+		if v.StringValue() == visibility {
+			return v, nil
+		}
+	}
+
+	return Visibility{}, errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "invalid visibility: %s, must be one of private, team, org", visibility)
+}
+
+func (v *Visibility) UnmarshalJSON(data []byte) error {
+	var value valuer.String
+	if err := value.UnmarshalJSON(data); err != nil {
+		return err
+	}
+
+	visibility, err := NewVisibility(value.StringValue())
+	if err != nil {
+		return err
+	}
+
+	*v = visibility
+	return nil
+}
+
+type StorableSavedView struct {
+	bun.BaseModel `bun:"table:saved_views"`
+
+	types.Identifiable
+	types.TimeAuditable
+	types.UserAuditable
+	OrgID      string `bun:"org_id,notnull"`
+	Name       string `bun:"name,type:text,notnull"`
+	Category   string `bun:"category,type:text,notnull"`
+	SourcePage string `bun:"source_page,type:text,notnull"`
+	// the tags joined with commas
+	Tags string `bun:"tags,type:text"`
+	// the queries of the view, see UpgradeData
+	Data       string     `bun:"data,type:text,notnull"`
+	ExtraData  string     `bun:"extra_data,type:text"`
+	Visibility Visibility `bun:"visibility,type:text,notnull"`
+	// the team of the views of team visibility
+	TeamID     string     `bun:"team_id,type:text"`
+	UsageCount int64      `bun:"usage_count,notnull"`
+	LastUsedAt *time.Time `bun:"last_used_at"`
+}
+
+// SavedView is a query of an explorer page saved to be opened again.
+type SavedView struct {
+	types.Identifiable
+	types.TimeAuditable
+	types.UserAuditable
+	OrgID          valuer.UUID              `json:"orgId"`
+	Name           string                   `json:"name"`
+	Category       string                   `json:"category"`
+	SourcePage     string                   `json:"sourcePage"`
+	Tags           []string                 `json:"tags"`
+	PanelType      dashboardtypes.PanelType `json:"panelType"`
+	CompositeQuery qbtypes.CompositeQuery   `json:"compositeQuery"`
+	// state of the explorer page kept as is, e.g. the selected columns
+	ExtraData  string     `json:"extraData"`
+	Visibility Visibility `json:"visibility"`
+	TeamID     string     `json:"teamId,omitempty"`
+	// number of times the view was opened and when it was last opened, nil for views never opened
+	UsageCount int64      `json:"usageCount"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+}
+
+type GettableSavedView struct {
+	*SavedView
+	// the view is opened by default on its source page for the user
+	Default bool `json:"default"`
+}
+
+type PostableSavedView struct {
+	Name           string                   `json:"name"`
+	Category       string                   `json:"category"`
+	SourcePage     string                   `json:"sourcePage"`
+	Tags           []string                 `json:"tags"`
+	PanelType      dashboardtypes.PanelType `json:"panelType"`
+	CompositeQuery qbtypes.CompositeQuery   `json:"compositeQuery"`
+	ExtraData      string                   `json:"extraData"`
+	// org when empty
+	Visibility Visibility `json:"visibility"`
+	TeamID     string     `json:"teamId"`
+}
+
+type UpdatableSavedView = PostableSavedView
+
+// ListParams filters the saved views, the zero value matches all of them.
+type ListParams struct {
+	SourcePage string
+	// the name and the category of the views contain them
+	Name     string
+	Category string
+	// the views not opened since then, including the views never opened that were created before then
+	UnusedSince *time.Time
+}
+
+func NewSavedView(orgID valuer.UUID, createdBy string, postable *PostableSavedView) (*SavedView, error) {
+	if err := postable.Validate(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return &SavedView{
+		Identifiable: types.Identifiable{
+			ID: valuer.GenerateUUID(),
+		},
+		TimeAuditable: types.TimeAuditable{
+			CreatedAt: now,
+			UpdatedAt: now,
+		},
+		UserAuditable: types.UserAuditable{
+			CreatedBy: createdBy,
+			UpdatedBy: createdBy,
+		},
+		OrgID:          orgID,
+		Name:           postable.Name,
+		Category:       postable.Category,
+		SourcePage:     postable.SourcePage,
+		Tags:           postable.Tags,
+		PanelType:      postable.PanelType,
+		CompositeQuery: postable.CompositeQuery,
+		ExtraData:      postable.ExtraData,
+		Visibility:     postable.Visibility,
+		TeamID:         postable.TeamID,
+	}, nil
+}
+
+func NewSavedViewFromStorable(storable *StorableSavedView) (*SavedView, error) {
+	orgID, err := valuer.NewUUID(storable.OrgID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := newData(storable.Data)
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.TypeInternal, ErrCodeSavedViewInvalid, "failed to decode saved view with id %s", storable.ID.StringValue())
+	}
+
+	tags := make([]string, 0)
+	for _, tag := range strings.Split(storable.Tags, ",") {
+		if tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+
+	visibility := storable.Visibility
+	if visibility.IsZero() {
+		visibility = VisibilityOrg
+	}
+
+	return &SavedView{
+		Identifiable:   storable.Identifiable,
+		TimeAuditable:  storable.TimeAuditable,
+		UserAuditable:  storable.UserAuditable,
+		OrgID:          orgID,
+		Name:           storable.Name,
+		Category:       storable.Category,
+		SourcePage:     storable.SourcePage,
+		Tags:           tags,
+		PanelType:      data.PanelType,
+		CompositeQuery: data.CompositeQuery,
+		ExtraData:      storable.ExtraData,
+		Visibility:     visibility,
+		TeamID:         storable.TeamID,
+		UsageCount:     storable.UsageCount,
+		LastUsedAt:     storable.LastUsedAt,
+	}, nil
+}
+
+func NewStorableSavedView(view *SavedView) (*StorableSavedView, error) {
+	data, err := newRawData(&data{Version: DataVersion, PanelType: view.PanelType, CompositeQuery: view.CompositeQuery})
+	if err != nil {
+		return nil, err
+	}
+
+	return &StorableSavedView{
+		Identifiable:  view.Identifiable,
+		TimeAuditable: view.TimeAuditable,
+		UserAuditable: view.UserAuditable,
+		OrgID:         view.OrgID.StringValue(),
+		Name:          view.Name,
+		Category:      view.Category,
+		SourcePage:    view.SourcePage,
+		Tags:          strings.Join(view.Tags, ","),
+		Data:          data,
+		ExtraData:     view.ExtraData,
+		Visibility:    view.Visibility,
+		TeamID:        view.TeamID,
+		UsageCount:    view.UsageCount,
+		LastUsedAt:    view.LastUsedAt,
+	}, nil
+}
+
+func NewGettableSavedView(view *SavedView, isDefault bool) *GettableSavedView {
+	return &GettableSavedView{SavedView: view, Default: isDefault}
+}
+
+func NewListParams(req *http.Request) (*ListParams, error) {
+	query := req.URL.Query()
+	params := &ListParams{
+		SourcePage: query.Get("sourcePage"),
+		Name:       query.Get("name"),
+		Category:   query.Get("category"),
+	}
+
+	if unusedSince := query.Get("unusedSince"); unusedSince != "" {
+		since, err := time.Parse(time.RFC3339, unusedSince)
+		if err != nil {
+			return nil, errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "unusedSince must be a RFC 3339 time")
+		}
+
+		params.UnusedSince = &since
+	}
+
+	return params, nil
+}
+
+func (view *SavedView) Update(updatedBy string, updatable *UpdatableSavedView) error {
+	if err := updatable.Validate(); err != nil {
+		return err
+	}
+
+	view.Name = updatable.Name
+	view.Category = updatable.Category
+	view.SourcePage = updatable.SourcePage
+	view.Tags = updatable.Tags
+	view.PanelType = updatable.PanelType
+	view.CompositeQuery = updatable.CompositeQuery
+	view.ExtraData = updatable.ExtraData
+	view.Visibility = updatable.Visibility
+	view.TeamID = updatable.TeamID
+	view.UpdatedBy = updatedBy
+	view.UpdatedAt = time.Now()
+	return nil
+}
+
+// VisibleTo checks that the user of the claims can see the view, the claims are nil for the calls made by signoz itself.
+// Admins and the creator of a view always see it.
+func (view *SavedView) VisibleTo(claims *authtypes.Claims, teamIDs []valuer.UUID) bool {
+	if view.ManageableBy(claims) {
+		return true
+	}
+
+	switch view.Visibility {
+	case VisibilityOrg:
+		return true
+	case VisibilityTeam:
+		return slices.ContainsFunc(teamIDs, func(teamID valuer.UUID) bool { return teamID.StringValue() == view.TeamID })
+	default:
+		return false
+	}
+}
+
+// ManageableBy checks that the user of the claims can change who sees the view, only admins and its creator can.
+func (view *SavedView) ManageableBy(claims *authtypes.Claims) bool {
+	return claims == nil || claims.Role == types.RoleAdmin || view.CreatedBy == claims.Email
+}
+
+// Unused checks that the view was not opened since the time.
+func (view *SavedView) Unused(since time.Time) bool {
+	if view.LastUsedAt == nil {
+		return view.CreatedAt.Before(since)
+	}
+
+	return view.LastUsedAt.Before(since)
+}
+
+func (p *PostableSavedView) Validate() error {
+	p.Name = strings.TrimSpace(p.Name)
+	if p.Name == "" {
+		return errors.New(errors.TypeInvalidInput, errors.CodeInvalidInput, "name is required")
+	}
+
+	if len(p.Name) > maxNameLength {
+		return errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "name must be at most %d characters", maxNameLength)
+	}
+
+	if p.SourcePage == "" {
+		return errors.New(errors.TypeInvalidInput, errors.CodeInvalidInput, "sourcePage is required")
+	}
+
+	if !slices.Contains(p.PanelType.Enum(), string(p.PanelType)) {
+		return errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "invalid panelType: %s", p.PanelType)
+	}
+
+	tags := make([]string, 0, len(p.Tags))
+	for _, tag := range p.Tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+
+		if strings.Contains(tag, ",") {
+			return errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "tag %s cannot contain a comma", tag)
+		}
+
+		tags = append(tags, tag)
+	}
+	p.Tags = tags
+
+	if err := p.CompositeQuery.Validate(p.PanelType.RequestType()); err != nil {
+		return err
+	}
+
+	if p.Visibility.IsZero() {
+		p.Visibility = VisibilityOrg
+	}
+
+	if p.Visibility != VisibilityTeam {
+		if p.TeamID != "" {
+			return errors.New(errors.TypeInvalidInput, errors.CodeInvalidInput, "teamId is only allowed for views of team visibility")
+		}
+
+		return nil
+	}
+
+	if p.TeamID == "" {
+		return errors.New(errors.TypeInvalidInput, errors.CodeInvalidInput, "teamId is required for views of team visibility")
+	}
+
+	if _, err := valuer.NewUUID(p.TeamID); err != nil {
+		return errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "teamId is not a valid uuid")
+	}
+
+	return nil
+}
+
+func (p *ListParams) Matches(view *SavedView) bool {
+	if p.SourcePage != "" && view.SourcePage != p.SourcePage {
+		return false
+	}
+
+	if !strings.Contains(view.Name, p.Name) || !strings.Contains(view.Category, p.Category) {
+		return false
+	}
+
+	return p.UnusedSince == nil || view.Unused(*p.UnusedSince)
+}
+
+func NewStatsFromSavedViews(savedViews []*StorableSavedView) map[string]any {
+	stats := make(map[string]any)
+	for _, savedView := range savedViews {
+		key := "savedview.source." + strings.ToLower(string(savedView.SourcePage)) + ".count"
+		if _, ok := stats[key]; !ok {
+			stats[key] = int64(1)
+		} else {
+			stats[key] = stats[key].(int64) + 1
+		}
+	}
+
+	stats["savedview.count"] = int64(len(savedViews))
+	return stats
+}
@@ -0,0 +1,126 @@
+package savedviewtypes
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/SigNoz/signoz/pkg/types"
+	"github.com/SigNoz/signoz/pkg/types/authtypes"
+	"github.com/SigNoz/signoz/pkg/types/dashboardtypes"
+	qbtypes "github.com/SigNoz/signoz/pkg/types/querybuildertypes/querybuildertypesv5"
+	"github.com/SigNoz/signoz/pkg/valuer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostableSavedViewValidate(t *testing.T) {
+	newPostable := func() *PostableSavedView {
+		return &PostableSavedView{
+			Name:       " errors ",
+			SourcePage: "logs",
+			Tags:       []string{"payments", " "},
+			PanelType:  dashboardtypes.PanelTypeList,
+			CompositeQuery: qbtypes.CompositeQuery{Queries: []qbtypes.QueryEnvelope{
+				{Type: qbtypes.QueryTypeClickHouseSQL, Spec: qbtypes.ClickHouseQuery{Name: "A", Query: "SELECT 1"}},
+			}},
+		}
+	}
+
+	postable := newPostable()
+	require.NoError(t, postable.Validate())
+	assert.Equal(t, "errors", postable.Name)
+	assert.Equal(t, []string{"payments"}, postable.Tags)
+	assert.Equal(t, VisibilityOrg, postable.Visibility)
+
+	for _, tc := range []struct {
+		name   string
+		modify func(*PostableSavedView)
+	}{
+		{name: "NoName", modify: func(p *PostableSavedView) { p.Name = "" }},
+		{name: "NoSourcePage", modify: func(p *PostableSavedView) { p.SourcePage = "" }},
+		{name: "UnknownPanelType", modify: func(p *PostableSavedView) { p.PanelType = "map" }},
+		{name: "CommaInTag", modify: func(p *PostableSavedView) { p.Tags = []string{"a,b"} }},
+		{name: "NoQueries", modify: func(p *PostableSavedView) { p.CompositeQuery.Queries = nil }},
+		{name: "TeamWithoutTeamID", modify: func(p *PostableSavedView) { p.Visibility = VisibilityTeam }},
+		{name: "PrivateWithTeamID", modify: func(p *PostableSavedView) {
+			p.Visibility, p.TeamID = VisibilityPrivate, valuer.GenerateUUID().StringValue()
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			postable := newPostable()
+			tc.modify(postable)
+			assert.Error(t, postable.Validate())
+		})
+	}
+}
+
+func TestSavedViewVisibleTo(t *testing.T) {
+	teamID := valuer.GenerateUUID()
+	view := &SavedView{UserAuditable: types.UserAuditable{CreatedBy: "jane@example.com"}, Visibility: VisibilityPrivate, TeamID: teamID.StringValue()}
+	creator := &authtypes.Claims{Email: "jane@example.com", Role: types.RoleViewer}
+	other := &authtypes.Claims{Email: "john@example.com", Role: types.RoleEditor}
+	admin := &authtypes.Claims{Email: "admin@example.com", Role: types.RoleAdmin}
+
+	assert.True(t, view.VisibleTo(nil, nil))
+	assert.True(t, view.VisibleTo(creator, nil))
+	assert.True(t, view.VisibleTo(admin, nil))
+	assert.False(t, view.VisibleTo(other, []valuer.UUID{teamID}))
+
+	view.Visibility = VisibilityTeam
+	assert.True(t, view.VisibleTo(other, []valuer.UUID{teamID}))
+	assert.False(t, view.VisibleTo(other, []valuer.UUID{valuer.GenerateUUID()}))
+	assert.False(t, view.ManageableBy(other))
+
+	view.Visibility = VisibilityOrg
+	assert.True(t, view.VisibleTo(other, nil))
+}
+
+func TestUpgradeData(t *testing.T) {
+	raw, err := UpgradeData(`{
+		"queryType": "builder",
+		"panelType": "list",
+		"builderQueries": {
+			"A": {"queryName": "A", "dataSource": "logs", "aggregateOperator": "noop", "expression": "A", "filters": {"op": "AND", "items": [{"key": {"key": "service.name", "type": "resource"}, "op": "=", "value": "payments"}]}}
+		}
+	}`)
+	require.NoError(t, err)
+
+	data, err := newData(raw)
+	require.NoError(t, err)
+	assert.Equal(t, DataVersion, data.Version)
+	assert.Equal(t, dashboardtypes.PanelTypeList, data.PanelType)
+	require.Len(t, data.CompositeQuery.Queries, 1)
+	assert.Equal(t, qbtypes.QueryTypeBuilder, data.CompositeQuery.Queries[0].Type)
+	assert.NoError(t, data.CompositeQuery.Validate(data.PanelType.RequestType()))
+
+	// the data already upgraded is left as is
+	upgraded, err := UpgradeData(raw)
+	require.NoError(t, err)
+	assert.JSONEq(t, raw, upgraded)
+
+	_, err = UpgradeData(`{"queryType": "unknown"}`)
+	assert.Error(t, err)
+}
+
+func TestNewWidget(t *testing.T) {
+	view := &SavedView{
+		Identifiable: types.Identifiable{ID: valuer.GenerateUUID()},
+		Name:         "slow queries",
+		PanelType:    dashboardtypes.PanelTypeTable,
+		CompositeQuery: qbtypes.CompositeQuery{Queries: []qbtypes.QueryEnvelope{
+			{Type: qbtypes.QueryTypePromQL, Spec: qbtypes.PromQuery{Name: "A", Query: "rate(http_requests_total[5m])"}},
+		}},
+	}
+
+	widget, err := NewWidget(view)
+	require.NoError(t, err)
+	assert.Equal(t, view.ID.StringValue(), widget.ID)
+	assert.Equal(t, dashboardtypes.PanelTypeTable, widget.PanelType)
+	require.Len(t, widget.Query.Queries, 1)
+
+	composite, err := widget.Query.CompositeQuery()
+	require.NoError(t, err)
+	raw, err := json.Marshal(composite)
+	require.NoError(t, err)
+	assert.Contains(t, string(raw), "rate(http_requests_total[5m])")
+}
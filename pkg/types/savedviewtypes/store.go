@@ -0,0 +1,22 @@
+package savedviewtypes
+
+import (
+	"context"
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/valuer"
+)
+
+type Store interface {
+	Create(context.Context, *StorableSavedView) error
+	Get(context.Context, valuer.UUID, valuer.UUID) (*StorableSavedView, error)
+	List(context.Context, valuer.UUID) ([]*StorableSavedView, error)
+	Update(context.Context, *StorableSavedView) error
+	Delete(context.Context, valuer.UUID, valuer.UUID) error
+
+	// RecordUsage counts an opening of the view at the time.
+	RecordUsage(context.Context, valuer.UUID, valuer.UUID, time.Time) error
+
+	ListTeamIDs(context.Context, valuer.UUID) ([]valuer.UUID, error)
+	TeamExists(context.Context, valuer.UUID, valuer.UUID) (bool, error)
+}
@@ -0,0 +1,148 @@
+package savedviewtypes
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+
+	"github.com/SigNoz/signoz/pkg/errors"
+	v3 "github.com/SigNoz/signoz/pkg/query-service/model/v3"
+	"github.com/SigNoz/signoz/pkg/types/dashboardtypes"
+	qbtypes "github.com/SigNoz/signoz/pkg/types/querybuildertypes/querybuildertypesv5"
+)
+
+// DataVersion is the version of the stored queries of the saved views, the views saved before it have no version and
+// store a v3 composite query.
+const DataVersion = "v5"
+
+type data struct {
+	Version        string                   `json:"version"`
+	PanelType      dashboardtypes.PanelType `json:"panelType"`
+	CompositeQuery qbtypes.CompositeQuery   `json:"compositeQuery"`
+}
+
+// UpgradeData upgrades the stored queries of a saved view to DataVersion, the queries already at DataVersion are
+// returned as is.
+func UpgradeData(raw string) (string, error) {
+	data, err := newData(raw)
+	if err != nil {
+		return "", err
+	}
+
+	return newRawData(data)
+}
+
+// NewWidget returns the widget querying the view, to query the view like a panel of a dashboard.
+func NewWidget(view *SavedView) (*dashboardtypes.Widget, error) {
+	raw, err := json.Marshal(view.CompositeQuery.Queries)
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.TypeInternal, ErrCodeSavedViewInvalid, "failed to encode the queries of saved view: %s", view.ID.StringValue())
+	}
+
+	query := &dashboardtypes.WidgetQuery{}
+	if err := json.Unmarshal(raw, &query.Queries); err != nil {
+		return nil, errors.Wrapf(err, errors.TypeInternal, ErrCodeSavedViewInvalid, "failed to decode the queries of saved view: %s", view.ID.StringValue())
+	}
+
+	return &dashboardtypes.Widget{
+		ID:        view.ID.StringValue(),
+		Title:     view.Name,
+		PanelType: view.PanelType,
+		Query:     query,
+	}, nil
+}
+
+func newData(raw string) (*data, error) {
+	version := struct {
+		Version string `json:"version"`
+	}{}
+	if err := json.Unmarshal([]byte(raw), &version); err != nil {
+		return nil, errors.Wrapf(err, errors.TypeInvalidInput, ErrCodeSavedViewInvalid, "invalid saved view data")
+	}
+
+	if version.Version == DataVersion {
+		data := new(data)
+		if err := json.Unmarshal([]byte(raw), data); err != nil {
+			return nil, errors.Wrapf(err, errors.TypeInvalidInput, ErrCodeSavedViewInvalid, "invalid saved view data")
+		}
+
+		return data, nil
+	}
+
+	compositeQuery := new(v3.CompositeQuery)
+	if err := json.Unmarshal([]byte(raw), compositeQuery); err != nil {
+		return nil, errors.Wrapf(err, errors.TypeInvalidInput, ErrCodeSavedViewInvalid, "invalid saved view data")
+	}
+
+	return newDataFromV3(compositeQuery)
+}
+
+func newRawData(data *data) (string, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return "", errors.Wrapf(err, errors.TypeInternal, ErrCodeSavedViewInvalid, "failed to encode saved view data")
+	}
+
+	return string(raw), nil
+}
+
+// newDataFromV3 converts the v3 composite query to the v5 queries through the query of a widget, the builder queries
+// whose expression isn't their own name are formulas.
+func newDataFromV3(compositeQuery *v3.CompositeQuery) (*data, error) {
+	query := &dashboardtypes.WidgetQuery{QueryType: dashboardtypes.QueryType(compositeQuery.QueryType)}
+
+	switch query.QueryType {
+	case dashboardtypes.QueryTypeBuilder:
+		query.Builder = &dashboardtypes.BuilderSection{}
+		for _, name := range slices.Sorted(maps.Keys(compositeQuery.BuilderQueries)) {
+			builderQuery := compositeQuery.BuilderQueries[name]
+			if builderQuery == nil {
+				continue
+			}
+
+			if builderQuery.Expression != "" && builderQuery.Expression != name {
+				query.Builder.QueryFormulas = append(query.Builder.QueryFormulas, &dashboardtypes.BuilderFormula{
+					QueryName:  name,
+					Expression: builderQuery.Expression,
+					Disabled:   builderQuery.Disabled,
+					Legend:     builderQuery.Legend,
+				})
+				continue
+			}
+
+			raw, err := json.Marshal(builderQuery)
+			if err != nil {
+				return nil, errors.Wrapf(err, errors.TypeInvalidInput, ErrCodeSavedViewInvalid, "invalid query: %s", name)
+			}
+
+			widgetQuery := new(dashboardtypes.BuilderQuery)
+			if err := json.Unmarshal(raw, widgetQuery); err != nil {
+				return nil, errors.Wrapf(err, errors.TypeInvalidInput, ErrCodeSavedViewInvalid, "invalid query: %s", name)
+			}
+
+			widgetQuery.QueryName = name
+			query.Builder.QueryData = append(query.Builder.QueryData, widgetQuery)
+		}
+	case dashboardtypes.QueryTypeClickHouseSQL:
+		for _, name := range slices.Sorted(maps.Keys(compositeQuery.ClickHouseQueries)) {
+			if chQuery := compositeQuery.ClickHouseQueries[name]; chQuery != nil {
+				query.ClickHouseSQL = append(query.ClickHouseSQL, &dashboardtypes.RawQuery{Name: name, Query: chQuery.Query, Disabled: chQuery.Disabled})
+			}
+		}
+	case dashboardtypes.QueryTypePromQL:
+		for _, name := range slices.Sorted(maps.Keys(compositeQuery.PromQueries)) {
+			if promQuery := compositeQuery.PromQueries[name]; promQuery != nil {
+				query.PromQL = append(query.PromQL, &dashboardtypes.RawQuery{Name: name, Query: promQuery.Query, Disabled: promQuery.Disabled})
+			}
+		}
+	default:
+		return nil, errors.Newf(errors.TypeInvalidInput, ErrCodeSavedViewInvalid, "unknown query type: %s", compositeQuery.QueryType)
+	}
+
+	composite, err := query.CompositeQuery()
+	if err != nil {
+		return nil, err
+	}
+
+	return &data{Version: DataVersion, PanelType: dashboardtypes.PanelType(compositeQuery.PanelType), CompositeQuery: composite}, nil
+}
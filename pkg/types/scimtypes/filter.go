@@ -0,0 +1,150 @@
+package scimtypes
+
+import (
+	"strings"
+
+	"github.com/SigNoz/signoz/pkg/errors"
+)
+
+// Condition compares an attribute of the resources to a value.
+type Condition struct {
+	Attribute string
+	Operator  string
+	Value     string
+}
+
+// Filter is a conjunction of conditions, the subset of the SCIM filter grammar directories use
+// to look up resources (`userName eq "jane@example.com"`, `displayName sw "sre" and ...`). An
+// empty filter matches every resource.
+type Filter []Condition
+
+var operators = map[string]bool{"eq": true, "ne": true, "co": true, "sw": true, "ew": true, "pr": true}
+
+func ParseFilter(filter string) (Filter, error) {
+	conditions := Filter{}
+	rest := strings.TrimSpace(filter)
+
+	for rest != "" {
+		var attribute, operator, value string
+		attribute, rest = nextToken(rest)
+		operator, rest = nextToken(rest)
+		operator = strings.ToLower(operator)
+
+		if strings.ContainsAny(attribute, "()[]") || strings.EqualFold(attribute, "not") {
+			return nil, errors.Newf(errors.TypeInvalidInput, ErrCodeInvalidFilter, "unsupported filter %q, only conditions joined by `and` are supported", filter)
+		}
+		if !operators[operator] {
+			return nil, errors.Newf(errors.TypeInvalidInput, ErrCodeInvalidFilter, "unsupported operator %q in filter %q", operator, filter)
+		}
+
+		if operator != "pr" {
+			var err error
+			value, rest, err = nextValue(rest)
+			if err != nil {
+				return nil, errors.Wrapf(err, errors.TypeInvalidInput, ErrCodeInvalidFilter, "invalid filter %q", filter)
+			}
+		}
+
+		conditions = append(conditions, Condition{Attribute: strings.ToLower(attribute), Operator: operator, Value: value})
+
+		if rest == "" {
+			break
+		}
+
+		var and string
+		and, rest = nextToken(rest)
+		if !strings.EqualFold(and, "and") || rest == "" {
+			return nil, errors.Newf(errors.TypeInvalidInput, ErrCodeInvalidFilter, "unsupported filter %q, only conditions joined by `and` are supported", filter)
+		}
+	}
+
+	return conditions, nil
+}
+
+func nextToken(s string) (string, string) {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, ' '); i >= 0 {
+		return s[:i], strings.TrimSpace(s[i+1:])
+	}
+	return s, ""
+}
+
+// nextValue reads a quoted string or a bare true, false, null or number.
+func nextValue(s string) (string, string, error) {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, `"`) {
+		value, rest := nextToken(s)
+		if value == "" {
+			return "", "", errors.New(errors.TypeInvalidInput, ErrCodeInvalidFilter, "missing value")
+		}
+		return value, rest, nil
+	}
+
+	var value strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if i+1 < len(s) {
+				i++
+				value.WriteByte(s[i])
+			}
+		case '"':
+			return value.String(), strings.TrimSpace(s[i+1:]), nil
+		default:
+			value.WriteByte(s[i])
+		}
+	}
+
+	return "", "", errors.New(errors.TypeInvalidInput, ErrCodeInvalidFilter, "unterminated string")
+}
+
+// Matches checks whether a resource with the attributes, keyed by their lowercase name,
+// matches the filter. Values are compared case insensitively.
+func (f Filter) Matches(attributes map[string][]string) bool {
+	for _, condition := range f {
+		if !condition.matches(attributes[condition.Attribute]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (c Condition) matches(values []string) bool {
+	if c.Operator == "ne" {
+		for _, value := range values {
+			if strings.EqualFold(value, c.Value) {
+				return false
+			}
+		}
+		return true
+	}
+
+	for _, value := range values {
+		v, expected := strings.ToLower(value), strings.ToLower(c.Value)
+		switch c.Operator {
+		case "eq":
+			if v == expected {
+				return true
+			}
+		case "co":
+			if strings.Contains(v, expected) {
+				return true
+			}
+		case "sw":
+			if strings.HasPrefix(v, expected) {
+				return true
+			}
+		case "ew":
+			if strings.HasSuffix(v, expected) {
+				return true
+			}
+		case "pr":
+			if v != "" {
+				return true
+			}
+		}
+	}
+
+	return false
+}
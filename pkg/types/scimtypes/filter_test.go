@@ -0,0 +1,85 @@
+package scimtypes
+
+import (
+	"testing"
+
+	"github.com/SigNoz/signoz/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFilter(t *testing.T) {
+	testCases := []struct {
+		name     string
+		filter   string
+		expected Filter
+		pass     bool
+	}{
+		{name: "Empty", filter: "", expected: Filter{}, pass: true},
+		{name: "Eq", filter: `userName eq "jane@example.com"`, expected: Filter{{Attribute: "username", Operator: "eq", Value: "jane@example.com"}}, pass: true},
+		{name: "Escaped", filter: `displayName eq "the \"sre\" team"`, expected: Filter{{Attribute: "displayname", Operator: "eq", Value: `the "sre" team`}}, pass: true},
+		{name: "Spaces", filter: `displayName EQ "on call"`, expected: Filter{{Attribute: "displayname", Operator: "eq", Value: "on call"}}, pass: true},
+		{name: "Present", filter: `externalId pr`, expected: Filter{{Attribute: "externalid", Operator: "pr"}}, pass: true},
+		{
+			name:   "And",
+			filter: `displayName sw "sre" and active eq true`,
+			expected: Filter{
+				{Attribute: "displayname", Operator: "sw", Value: "sre"},
+				{Attribute: "active", Operator: "eq", Value: "true"},
+			},
+			pass: true,
+		},
+		{name: "Or", filter: `userName eq "a" or userName eq "b"`, pass: false},
+		{name: "Grouping", filter: `(userName eq "a")`, pass: false},
+		{name: "ValuePath", filter: `emails[type eq "work"]`, pass: false},
+		{name: "UnsupportedOperator", filter: `meta.lastModified gt "2011-05-13T04:42:34Z"`, pass: false},
+		{name: "Unterminated", filter: `userName eq "jane`, pass: false},
+		{name: "DanglingAnd", filter: `userName eq "jane" and`, pass: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			filter, err := ParseFilter(tc.filter)
+			if !tc.pass {
+				assert.True(t, errors.Asc(err, ErrCodeInvalidFilter))
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, filter)
+		})
+	}
+}
+
+func TestFilterMatches(t *testing.T) {
+	user := (&User{
+		ID:          "1",
+		UserName:    "Jane@Example.com",
+		DisplayName: "Jane Doe",
+		Emails:      []MultiValued{{Value: "jane@example.com"}, {Value: "jd@example.com"}},
+	}).Attributes()
+
+	testCases := []struct {
+		filter  string
+		matches bool
+	}{
+		{filter: ``, matches: true},
+		{filter: `userName eq "jane@example.com"`, matches: true},
+		{filter: `username eq "john@example.com"`, matches: false},
+		{filter: `emails.value eq "jd@example.com"`, matches: true},
+		{filter: `emails co "jd@"`, matches: true},
+		{filter: `displayName sw "jane" and displayName ew "doe"`, matches: true},
+		{filter: `displayName sw "jane" and displayName ew "smith"`, matches: false},
+		{filter: `userName ne "john@example.com"`, matches: true},
+		{filter: `externalId pr`, matches: false},
+		{filter: `active eq true`, matches: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.filter, func(t *testing.T) {
+			filter, err := ParseFilter(tc.filter)
+			require.NoError(t, err)
+			assert.Equal(t, tc.matches, filter.Matches(user))
+		})
+	}
+}
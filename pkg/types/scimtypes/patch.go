@@ -0,0 +1,193 @@
+package scimtypes
+
+import (
+	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/SigNoz/signoz/pkg/errors"
+)
+
+type PatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value"`
+}
+
+type PatchRequest struct {
+	Schemas    []string         `json:"schemas"`
+	Operations []PatchOperation `json:"Operations"`
+}
+
+// forEachOperation calls `apply` with the lowercase op and path of every operation, operations
+// without a path are split into one operation per attribute of their value.
+func (p *PatchRequest) forEachOperation(apply func(op string, path string, value json.RawMessage) error) error {
+	for _, operation := range p.Operations {
+		op := strings.ToLower(operation.Op)
+		if op != "add" && op != "replace" && op != "remove" {
+			return errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "invalid patch op %q", operation.Op)
+		}
+
+		if operation.Path != "" {
+			if err := apply(op, strings.ToLower(operation.Path), operation.Value); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if op == "remove" {
+			return errors.New(errors.TypeInvalidInput, ErrCodeInvalidPath, "remove operations require a path")
+		}
+
+		values := map[string]json.RawMessage{}
+		if err := json.Unmarshal(operation.Value, &values); err != nil {
+			return errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "value of patch operations without path must be an object")
+		}
+		for path, value := range values {
+			if err := apply(op, strings.ToLower(path), value); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// ApplyPatch applies the operations to the user, operations on attributes SigNoz doesn't keep
+// are ignored as directories send them regardless of the schema.
+func (u *User) ApplyPatch(patch *PatchRequest) error {
+	return patch.forEachOperation(func(op string, path string, value json.RawMessage) error {
+		if u.Name == nil {
+			u.Name = &Name{}
+		}
+
+		switch path {
+		case "active":
+			active := true
+			if op != "remove" {
+				var err error
+				if active, err = decodeBool(value); err != nil {
+					return err
+				}
+			}
+			u.Active = &active
+		case "username":
+			return decodeString(op, value, &u.UserName)
+		case "externalid":
+			return decodeString(op, value, &u.ExternalID)
+		case "displayname":
+			return decodeString(op, value, &u.DisplayName)
+		case "name.formatted":
+			return decodeString(op, value, &u.Name.Formatted)
+		case "name.givenname":
+			return decodeString(op, value, &u.Name.GivenName)
+		case "name.familyname":
+			return decodeString(op, value, &u.Name.FamilyName)
+		case "name":
+			if op == "remove" {
+				u.Name = &Name{}
+				return nil
+			}
+			return decode(value, u.Name)
+		case "emails":
+			return patchMultiValued(op, value, &u.Emails)
+		case "roles":
+			return patchMultiValued(op, value, &u.Roles)
+		}
+
+		return nil
+	})
+}
+
+// ApplyPatch applies the operations to the group, members are added and removed by their value.
+func (g *Group) ApplyPatch(patch *PatchRequest) error {
+	return patch.forEachOperation(func(op string, path string, value json.RawMessage) error {
+		switch {
+		case path == "displayname":
+			return decodeString(op, value, &g.DisplayName)
+		case path == "externalid":
+			return decodeString(op, value, &g.ExternalID)
+		case path == "members":
+			return patchMultiValued(op, value, &g.Members)
+		case strings.HasPrefix(path, "members[") && strings.HasSuffix(path, "]") && op == "remove":
+			filter, err := ParseFilter(strings.TrimSuffix(strings.TrimPrefix(path, "members["), "]"))
+			if err != nil {
+				return errors.Wrapf(err, errors.TypeInvalidInput, ErrCodeInvalidPath, "invalid path %q", path)
+			}
+			g.Members = slices.DeleteFunc(g.Members, func(member MultiValued) bool {
+				return filter.Matches(map[string][]string{"value": {member.Value}})
+			})
+			return nil
+		}
+
+		return errors.Newf(errors.TypeInvalidInput, ErrCodeInvalidPath, "unsupported path %q for %s operations on groups", path, op)
+	})
+}
+
+func decode(value json.RawMessage, target interface{}) error {
+	if err := json.Unmarshal(value, target); err != nil {
+		return errors.Wrapf(err, errors.TypeInvalidInput, errors.CodeInvalidInput, "invalid value %s", string(value))
+	}
+	return nil
+}
+
+func decodeString(op string, value json.RawMessage, target *string) error {
+	if op == "remove" {
+		*target = ""
+		return nil
+	}
+	return decode(value, target)
+}
+
+// decodeBool accepts booleans as well as their string representation some directories send.
+func decodeBool(value json.RawMessage) (bool, error) {
+	var b bool
+	if err := json.Unmarshal(value, &b); err == nil {
+		return b, nil
+	}
+
+	var s string
+	if err := json.Unmarshal(value, &s); err == nil {
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b, nil
+		}
+	}
+
+	return false, errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "invalid boolean %s", string(value))
+}
+
+// patchMultiValued adds, replaces or removes values of a multi-valued attribute, the value of the
+// operations can be a list of values or a single one.
+func patchMultiValued(op string, value json.RawMessage, target *[]MultiValued) error {
+	if op == "remove" && len(value) == 0 {
+		*target = []MultiValued{}
+		return nil
+	}
+
+	values := []MultiValued{}
+	if err := json.Unmarshal(value, &values); err != nil {
+		single := MultiValued{}
+		if err := decode(value, &single); err != nil {
+			return err
+		}
+		values = append(values, single)
+	}
+
+	switch op {
+	case "replace":
+		*target = values
+	case "add":
+		for _, v := range values {
+			if !slices.ContainsFunc(*target, func(existing MultiValued) bool { return existing.Value == v.Value }) {
+				*target = append(*target, v)
+			}
+		}
+	case "remove":
+		*target = slices.DeleteFunc(*target, func(existing MultiValued) bool {
+			return slices.ContainsFunc(values, func(v MultiValued) bool { return existing.Value == v.Value })
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package scimtypes
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/SigNoz/signoz/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func patchRequest(t *testing.T, operations string) *PatchRequest {
+	patch := new(PatchRequest)
+	require.NoError(t, json.Unmarshal([]byte(`{"schemas": ["`+SchemaPatchOp+`"], "Operations": `+operations+`}`), patch))
+	return patch
+}
+
+func TestUserApplyPatch(t *testing.T) {
+	user := &User{UserName: "jane@example.com", DisplayName: "Jane", Roles: []MultiValued{{Value: "VIEWER"}}}
+
+	// the operations okta and azure send to rename and promote a user
+	require.NoError(t, user.ApplyPatch(patchRequest(t, `[
+		{"op": "replace", "value": {"displayName": "Jane Doe", "name": {"givenName": "Jane", "familyName": "Doe"}}},
+		{"op": "Replace", "path": "roles", "value": [{"value": "editor"}]},
+		{"op": "add", "path": "title", "value": "SRE"}
+	]`)))
+	assert.Equal(t, "Jane Doe", user.DisplayName)
+	assert.Equal(t, "Doe", user.Name.FamilyName)
+	role, ok := user.GetRole()
+	assert.True(t, ok)
+	assert.Equal(t, types.RoleEditor, role)
+	assert.Nil(t, user.Active)
+
+	// azure deactivates users with a string
+	require.NoError(t, user.ApplyPatch(patchRequest(t, `[{"op": "Replace", "path": "active", "value": "False"}]`)))
+	require.NotNil(t, user.Active)
+	assert.False(t, *user.Active)
+
+	assert.Error(t, user.ApplyPatch(patchRequest(t, `[{"op": "move", "path": "active", "value": false}]`)))
+	assert.Error(t, user.ApplyPatch(patchRequest(t, `[{"op": "replace", "path": "active", "value": "maybe"}]`)))
+}
+
+func TestGroupApplyPatch(t *testing.T) {
+	group := &Group{DisplayName: "sre", Members: []MultiValued{{Value: "1"}}}
+
+	require.NoError(t, group.ApplyPatch(patchRequest(t, `[{"op": "add", "path": "members", "value": [{"value": "2"}, {"value": "1"}, {"value": "3"}]}]`)))
+	assert.Equal(t, []MultiValued{{Value: "1"}, {Value: "2"}, {Value: "3"}}, group.Members)
+
+	require.NoError(t, group.ApplyPatch(patchRequest(t, `[{"op": "remove", "path": "members[value eq \"2\"]"}]`)))
+	assert.Equal(t, []MultiValued{{Value: "1"}, {Value: "3"}}, group.Members)
+
+	require.NoError(t, group.ApplyPatch(patchRequest(t, `[{"op": "remove", "path": "members", "value": [{"value": "1"}]}]`)))
+	assert.Equal(t, []MultiValued{{Value: "3"}}, group.Members)
+
+	require.NoError(t, group.ApplyPatch(patchRequest(t, `[{"op": "replace", "value": {"displayName": "on-call", "members": [{"value": "4"}]}}]`)))
+	assert.Equal(t, "on-call", group.DisplayName)
+	assert.Equal(t, []MultiValued{{Value: "4"}}, group.Members)
+
+	require.NoError(t, group.ApplyPatch(patchRequest(t, `[{"op": "remove", "path": "members"}]`)))
+	assert.Empty(t, group.Members)
+
+	assert.Error(t, group.ApplyPatch(patchRequest(t, `[{"op": "replace", "path": "owner", "value": "4"}]`)))
+}
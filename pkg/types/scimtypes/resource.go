@@ -0,0 +1,357 @@
+package scimtypes
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/errors"
+	"github.com/SigNoz/signoz/pkg/types"
+)
+
+const (
+	SchemaUser                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SchemaGroup                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	SchemaListResponse          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SchemaPatchOp               = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SchemaError                 = "urn:ietf:params:scim:api:messages:2.0:Error"
+	SchemaServiceProviderConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+
+	// ContentType is the media type of the requests and responses of SCIM.
+	ContentType = "application/scim+json"
+
+	// MaxResults is the largest page of resources returned by list requests.
+	MaxResults = 1000
+)
+
+type Meta struct {
+	ResourceType string    `json:"resourceType"`
+	Created      time.Time `json:"created"`
+	LastModified time.Time `json:"lastModified"`
+	Location     string    `json:"location,omitempty"`
+}
+
+type Name struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+// MultiValued is an entry of the emails, roles, groups and members attributes.
+type MultiValued struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+// User is the SCIM representation of a user, the userName is the email of the user and
+// the roles are the built-in role of the user. Users can't be deactivated, deactivating
+// a user deletes it.
+type User struct {
+	Schemas     []string      `json:"schemas"`
+	ID          string        `json:"id,omitempty"`
+	ExternalID  string        `json:"externalId,omitempty"`
+	UserName    string        `json:"userName"`
+	Name        *Name         `json:"name,omitempty"`
+	DisplayName string        `json:"displayName,omitempty"`
+	Emails      []MultiValued `json:"emails,omitempty"`
+	Active      *bool         `json:"active,omitempty"`
+	Roles       []MultiValued `json:"roles,omitempty"`
+	Groups      []MultiValued `json:"groups,omitempty"`
+	Meta        *Meta         `json:"meta,omitempty"`
+}
+
+type Group struct {
+	Schemas     []string      `json:"schemas"`
+	ID          string        `json:"id,omitempty"`
+	ExternalID  string        `json:"externalId,omitempty"`
+	DisplayName string        `json:"displayName"`
+	Members     []MultiValued `json:"members,omitempty"`
+	Meta        *Meta         `json:"meta,omitempty"`
+}
+
+type ListResponse struct {
+	Schemas      []string    `json:"schemas"`
+	TotalResults int         `json:"totalResults"`
+	StartIndex   int         `json:"startIndex"`
+	ItemsPerPage int         `json:"itemsPerPage"`
+	Resources    interface{} `json:"Resources"`
+}
+
+// ListQuery holds the filtering and pagination parameters of list requests.
+type ListQuery struct {
+	Filter     Filter
+	StartIndex int
+	Count      int
+	// excludedAttributes=members, directories poll groups without their members
+	ExcludeMembers bool
+}
+
+type Error struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail"`
+}
+
+type supported struct {
+	Supported  bool `json:"supported"`
+	MaxResults int  `json:"maxResults,omitempty"`
+}
+
+type ServiceProviderConfig struct {
+	Schemas               []string    `json:"schemas"`
+	Patch                 supported   `json:"patch"`
+	Bulk                  supported   `json:"bulk"`
+	Filter                supported   `json:"filter"`
+	ChangePassword        supported   `json:"changePassword"`
+	Sort                  supported   `json:"sort"`
+	ETag                  supported   `json:"etag"`
+	AuthenticationSchemes []authnType `json:"authenticationSchemes"`
+}
+
+type authnType struct {
+	Type        string `json:"type"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Primary     bool   `json:"primary"`
+}
+
+func NewServiceProviderConfig() *ServiceProviderConfig {
+	return &ServiceProviderConfig{
+		Schemas: []string{SchemaServiceProviderConfig},
+		Patch:   supported{Supported: true},
+		Filter:  supported{Supported: true, MaxResults: MaxResults},
+		AuthenticationSchemes: []authnType{{
+			Type:        "oauthbearertoken",
+			Name:        "OAuth Bearer Token",
+			Description: "Authentication with a provisioning token of the org",
+			Primary:     true,
+		}},
+	}
+}
+
+func NewListQuery(r *http.Request) (*ListQuery, error) {
+	q := r.URL.Query()
+
+	filter, err := ParseFilter(q.Get("filter"))
+	if err != nil {
+		return nil, err
+	}
+
+	query := &ListQuery{Filter: filter, StartIndex: 1, Count: MaxResults}
+	if v := q.Get("startIndex"); v != "" {
+		startIndex, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "invalid startIndex %q", v)
+		}
+		// values lower than 1 are interpreted as 1
+		query.StartIndex = max(startIndex, 1)
+	}
+	if v := q.Get("count"); v != "" {
+		count, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "invalid count %q", v)
+		}
+		query.Count = min(max(count, 0), MaxResults)
+	}
+
+	for _, attribute := range strings.Split(q.Get("excludedAttributes"), ",") {
+		if strings.EqualFold(strings.TrimSpace(attribute), "members") {
+			query.ExcludeMembers = true
+		}
+	}
+
+	return query, nil
+}
+
+// NewListResponse returns the page of `resources` requested by the query.
+func NewListResponse[T any](query *ListQuery, resources []T) *ListResponse {
+	page := []T{}
+	if start := query.StartIndex - 1; start < len(resources) {
+		page = resources[start:min(start+query.Count, len(resources))]
+	}
+
+	return &ListResponse{
+		Schemas:      []string{SchemaListResponse},
+		TotalResults: len(resources),
+		StartIndex:   query.StartIndex,
+		ItemsPerPage: len(page),
+		Resources:    page,
+	}
+}
+
+func NewUser(user *types.User, groups []*StorableGroup) *User {
+	scimUser := &User{
+		Schemas:     []string{SchemaUser},
+		ID:          user.ID.StringValue(),
+		UserName:    user.Email,
+		Name:        &Name{Formatted: user.DisplayName},
+		DisplayName: user.DisplayName,
+		Emails:      []MultiValued{{Value: user.Email, Type: "work", Primary: true}},
+		Active:      &[]bool{true}[0],
+		Roles:       []MultiValued{{Value: user.Role, Primary: true}},
+		Groups:      []MultiValued{},
+		Meta: &Meta{
+			ResourceType: "User",
+			Created:      user.CreatedAt,
+			LastModified: user.UpdatedAt,
+			Location:     "/Users/" + user.ID.StringValue(),
+		},
+	}
+
+	for _, group := range groups {
+		scimUser.Groups = append(scimUser.Groups, MultiValued{Value: group.ID.StringValue(), Display: group.DisplayName})
+	}
+
+	return scimUser
+}
+
+// Email returns the email of the user, the userName if it is one or else the primary email.
+func (u *User) Email() (string, error) {
+	if strings.Contains(u.UserName, "@") {
+		return u.UserName, nil
+	}
+
+	for _, email := range u.Emails {
+		if email.Primary || len(u.Emails) == 1 {
+			return email.Value, nil
+		}
+	}
+
+	return "", errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "userName %q is not an email and the user has no primary email", u.UserName)
+}
+
+// GetDisplayName returns the display name of the user, defaulting to its name and then
+// to the local part of its email.
+func (u *User) GetDisplayName(email string) string {
+	if u.DisplayName != "" {
+		return u.DisplayName
+	}
+
+	if u.Name != nil {
+		if u.Name.Formatted != "" {
+			return u.Name.Formatted
+		}
+		if name := strings.TrimSpace(u.Name.GivenName + " " + u.Name.FamilyName); name != "" {
+			return name
+		}
+	}
+
+	return strings.Split(email, "@")[0]
+}
+
+// GetRole returns the most privileged built-in role among the roles of the user, roles
+// that are not built-in ones are ignored.
+func (u *User) GetRole() (types.Role, bool) {
+	var role types.Role
+	for _, value := range u.Roles {
+		r, err := types.NewRole(strings.ToUpper(value.Value))
+		if err != nil {
+			continue
+		}
+		if role == "" || r == types.RoleAdmin || (r == types.RoleEditor && role == types.RoleViewer) {
+			role = r
+		}
+	}
+
+	return role, role != ""
+}
+
+func NewGroup(group *StorableGroup, members []*types.User) *Group {
+	scimGroup := &Group{
+		Schemas:     []string{SchemaGroup},
+		ID:          group.ID.StringValue(),
+		ExternalID:  group.ExternalID,
+		DisplayName: group.DisplayName,
+		Members:     []MultiValued{},
+		Meta: &Meta{
+			ResourceType: "Group",
+			Created:      group.CreatedAt,
+			LastModified: group.UpdatedAt,
+			Location:     "/Groups/" + group.ID.StringValue(),
+		},
+	}
+
+	for _, member := range members {
+		scimGroup.Members = append(scimGroup.Members, MultiValued{Value: member.ID.StringValue(), Display: member.Email})
+	}
+
+	return scimGroup
+}
+
+// NewError returns the SCIM error response and status code of `err`.
+func NewError(err error) (int, *Error) {
+	t, c, m, _, _, _ := errors.Unwrapb(err)
+
+	status := http.StatusInternalServerError
+	scimType := ""
+	switch t {
+	case errors.TypeInvalidInput:
+		status = http.StatusBadRequest
+		scimType = "invalidValue"
+	case errors.TypeNotFound:
+		status = http.StatusNotFound
+	case errors.TypeAlreadyExists:
+		status = http.StatusConflict
+		scimType = "uniqueness"
+	case errors.TypeUnauthenticated:
+		status = http.StatusUnauthorized
+	case errors.TypeForbidden:
+		status = http.StatusForbidden
+	case errors.TypeUnsupported:
+		status = http.StatusNotImplemented
+	}
+
+	switch c {
+	case ErrCodeInvalidFilter:
+		scimType = "invalidFilter"
+	case ErrCodeInvalidPath:
+		scimType = "invalidPath"
+	case ErrCodeMutability:
+		scimType = "mutability"
+	}
+
+	return status, &Error{
+		Schemas:  []string{SchemaError},
+		Status:   strconv.Itoa(status),
+		ScimType: scimType,
+		Detail:   m,
+	}
+}
+
+// Attributes returns the attributes of the user filters can refer to.
+func (u *User) Attributes() map[string][]string {
+	emails := []string{}
+	for _, email := range u.Emails {
+		emails = append(emails, email.Value)
+	}
+
+	return map[string][]string{
+		"id":           {u.ID},
+		"externalid":   {u.ExternalID},
+		"username":     {u.UserName},
+		"displayname":  {u.DisplayName},
+		"emails":       emails,
+		"emails.value": emails,
+		"active":       {"true"},
+	}
+}
+
+// Attributes returns the attributes of the group filters can refer to.
+func (g *Group) Attributes() map[string][]string {
+	members := []string{}
+	for _, member := range g.Members {
+		members = append(members, member.Value)
+	}
+
+	return map[string][]string{
+		"id":            {g.ID},
+		"externalid":    {g.ExternalID},
+		"displayname":   {g.DisplayName},
+		"members":       members,
+		"members.value": members,
+	}
+}
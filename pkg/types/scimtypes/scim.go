@@ -0,0 +1,159 @@
+package scimtypes
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/errors"
+	"github.com/SigNoz/signoz/pkg/types"
+	"github.com/SigNoz/signoz/pkg/valuer"
+	"github.com/uptrace/bun"
+)
+
+const tokenPrefix = "scim_"
+
+var (
+	ErrCodeTokenNotFound      = errors.MustNewCode("scim_token_not_found")
+	ErrCodeGroupNotFound      = errors.MustNewCode("scim_group_not_found")
+	ErrCodeGroupAlreadyExists = errors.MustNewCode("scim_group_already_exists")
+	ErrCodeInvalidFilter      = errors.MustNewCode("scim_invalid_filter")
+	ErrCodeInvalidPath        = errors.MustNewCode("scim_invalid_path")
+	ErrCodeMutability         = errors.MustNewCode("scim_mutability")
+)
+
+// StorableToken is a provisioning token of an org, only the hash of the token is stored.
+type StorableToken struct {
+	bun.BaseModel `bun:"table:scim_token"`
+
+	types.Identifiable
+	types.TimeAuditable
+	types.UserAuditable
+	OrgID     valuer.UUID `bun:"org_id,type:text,notnull"`
+	Name      string      `bun:"name,type:text,notnull"`
+	TokenHash string      `bun:"token_hash,type:text,notnull,unique"`
+	LastUsed  time.Time   `bun:"last_used,type:timestamptz,nullzero"`
+}
+
+type PostableToken struct {
+	Name string `json:"name"`
+}
+
+type GettableToken struct {
+	types.Identifiable
+	types.TimeAuditable
+	types.UserAuditable
+	Name     string     `json:"name"`
+	LastUsed *time.Time `json:"lastUsed"`
+	// only returned when the token is created
+	Token string `json:"token,omitempty"`
+}
+
+// StorableGroup is a group pushed by the directory of an org.
+type StorableGroup struct {
+	bun.BaseModel `bun:"table:scim_group"`
+
+	types.Identifiable
+	types.TimeAuditable
+	OrgID       valuer.UUID `bun:"org_id,type:text,notnull"`
+	DisplayName string      `bun:"display_name,type:text,notnull"`
+	ExternalID  string      `bun:"external_id,type:text"`
+}
+
+type StorableGroupMember struct {
+	bun.BaseModel `bun:"table:scim_group_member"`
+
+	OrgID   valuer.UUID `bun:"org_id,type:text,notnull"`
+	GroupID valuer.UUID `bun:"group_id,type:text,notnull"`
+	UserID  valuer.UUID `bun:"user_id,type:text,notnull"`
+}
+
+// NewStorableToken returns the token to store along with the plain token, which is
+// shown to the admin once and can't be recovered afterwards.
+func NewStorableToken(orgID valuer.UUID, createdBy string, postable *PostableToken) (*StorableToken, string, error) {
+	if postable.Name == "" {
+		return nil, "", errors.New(errors.TypeInvalidInput, errors.CodeInvalidInput, "name of the token is required")
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", errors.Wrapf(err, errors.TypeInternal, errors.CodeInternal, "failed to generate token")
+	}
+	token := tokenPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	return &StorableToken{
+		Identifiable: types.Identifiable{ID: valuer.GenerateUUID()},
+		TimeAuditable: types.TimeAuditable{
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		},
+		UserAuditable: types.UserAuditable{
+			CreatedBy: createdBy,
+			UpdatedBy: createdBy,
+		},
+		OrgID:     orgID,
+		Name:      postable.Name,
+		TokenHash: HashToken(token),
+	}, token, nil
+}
+
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func NewGettableToken(storable *StorableToken) *GettableToken {
+	gettable := &GettableToken{
+		Identifiable:  storable.Identifiable,
+		TimeAuditable: storable.TimeAuditable,
+		UserAuditable: storable.UserAuditable,
+		Name:          storable.Name,
+	}
+	if !storable.LastUsed.IsZero() {
+		gettable.LastUsed = &storable.LastUsed
+	}
+
+	return gettable
+}
+
+func NewStorableGroup(orgID valuer.UUID, group *Group) (*StorableGroup, error) {
+	if group.DisplayName == "" {
+		return nil, errors.New(errors.TypeInvalidInput, errors.CodeInvalidInput, "displayName of the group is required")
+	}
+
+	return &StorableGroup{
+		Identifiable: types.Identifiable{ID: valuer.GenerateUUID()},
+		TimeAuditable: types.TimeAuditable{
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		},
+		OrgID:       orgID,
+		DisplayName: group.DisplayName,
+		ExternalID:  group.ExternalID,
+	}, nil
+}
+
+type Store interface {
+	CreateToken(context.Context, *StorableToken) error
+	ListTokens(context.Context, valuer.UUID) ([]*StorableToken, error)
+	DeleteToken(context.Context, valuer.UUID, valuer.UUID) error
+	GetTokenByHash(context.Context, string) (*StorableToken, error)
+	UpdateTokenLastUsed(context.Context, valuer.UUID, time.Time) error
+
+	// CreateGroup creates the group along with its members.
+	CreateGroup(context.Context, *StorableGroup, []valuer.UUID) error
+	GetGroup(context.Context, valuer.UUID, valuer.UUID) (*StorableGroup, error)
+	ListGroups(context.Context, valuer.UUID) ([]*StorableGroup, error)
+	// UpdateGroup updates the group and replaces its members.
+	UpdateGroup(context.Context, *StorableGroup, []valuer.UUID) error
+	// DeleteGroup deletes the group along with its memberships.
+	DeleteGroup(context.Context, valuer.UUID, valuer.UUID) error
+
+	// ListMembers lists the memberships of all the groups of the org.
+	ListMembers(context.Context, valuer.UUID) ([]*StorableGroupMember, error)
+	// DeleteMemberships removes the user from all the groups of the org.
+	DeleteMemberships(context.Context, valuer.UUID, valuer.UUID) error
+}
@@ -0,0 +1,339 @@
+package searchtypes
+
+import (
+	"cmp"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/errors"
+	v3 "github.com/SigNoz/signoz/pkg/query-service/model/v3"
+	"github.com/SigNoz/signoz/pkg/types/dashboardtypes"
+	"github.com/SigNoz/signoz/pkg/types/ruletypes"
+	"github.com/SigNoz/signoz/pkg/types/savedviewtypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+
+	// MaxFavorites is the number of items a user can star.
+	MaxFavorites = 200
+)
+
+type Type struct{ valuer.String }
+
+var (
+	TypeDashboard = Type{valuer.NewString("dashboard")}
+	TypeView      = Type{valuer.NewString("view")}
+	TypeRule      = Type{valuer.NewString("rule")}
+)
+
+var searchTypes = []Type{TypeDashboard, TypeView, TypeRule}
+
+func NewType(searchType string) (Type, error) {
+	for _, t := range searchTypes {
+		if t.StringValue() == searchType {
+			return t, nil
+		}
+	}
+
+	return Type{}, errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "invalid type: %s, must be one of dashboard, view, rule", searchType)
+}
+
+// Item is a dashboard, a saved view or an alert rule found by a search.
+type Item struct {
+	Type        Type      `json:"type"`
+	ID          string    `json:"id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description,omitempty"`
+	Tags        []string  `json:"tags"`
+	FolderID    string    `json:"folderId,omitempty"`
+	Favorite    bool      `json:"favorite"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+
+	// ids of the folder of the item and of its ancestors
+	folderIDs []string
+	// text of the queries of the item and names of the metrics they aggregate
+	queries []string
+}
+
+type GettableItems struct {
+	Items []*Item `json:"items"`
+	Total int     `json:"total"`
+}
+
+// Params filters and pages the items, the zero value matches all of them.
+type Params struct {
+	// words matched against the title, the tags, the description and the queries of the items
+	Query string
+	Types []Type
+	// the items must have all the tags
+	Tags []string
+	// the dashboards of the folder and of its subfolders, only dashboards are kept in folders so saved views and
+	// alert rules never match
+	FolderID  string
+	Favorites bool
+	Limit     int
+	Offset    int
+}
+
+func NewDashboardItem(dashboard *dashboardtypes.Dashboard, path []*dashboardtypes.Folder) *Item {
+	item := &Item{Type: TypeDashboard, ID: dashboard.ID, Tags: []string{}, FolderID: dashboard.FolderID, UpdatedAt: dashboard.UpdatedAt}
+	for _, folder := range path {
+		item.folderIDs = append(item.folderIDs, folder.ID.StringValue())
+	}
+
+	spec, err := dashboardtypes.NewSpec(dashboard.Data)
+	if err != nil {
+		// the title of dashboards of an unknown schema is still searchable
+		item.Title, _ = dashboard.Data["title"].(string)
+		return item
+	}
+
+	item.Title = spec.Title
+	item.Description = spec.Description
+	item.Tags = append(item.Tags, spec.Tags...)
+	item.queries = spec.QueryTexts()
+	return item
+}
+
+func NewViewItem(view *savedviewtypes.SavedView) *Item {
+	item := &Item{
+		Type:      TypeView,
+		ID:        view.ID.StringValue(),
+		Title:     view.Name,
+		Tags:      append([]string{}, view.Tags...),
+		UpdatedAt: view.UpdatedAt,
+	}
+
+	// the queries of a view are searched like the ones of a panel of a dashboard
+	if widget, err := savedviewtypes.NewWidget(view); err == nil {
+		spec := &dashboardtypes.Spec{Widgets: []*dashboardtypes.Widget{widget}}
+		item.queries = spec.QueryTexts()
+	}
+
+	return item
+}
+
+func NewRuleItem(rule *ruletypes.Rule) (*Item, error) {
+	postable := new(ruletypes.PostableRule)
+	if err := json.Unmarshal([]byte(rule.Data), postable); err != nil {
+		return nil, errors.Wrapf(err, errors.TypeInternal, errors.CodeInternal, "failed to decode rule with id %s", rule.ID.StringValue())
+	}
+
+	item := &Item{
+		Type:        TypeRule,
+		ID:          rule.ID.StringValue(),
+		Title:       postable.AlertName,
+		Description: postable.Description,
+		Tags:        append([]string{}, postable.Tags...),
+		UpdatedAt:   rule.UpdatedAt,
+	}
+	if postable.RuleCondition != nil {
+		item.queries = compositeQueryTexts(postable.RuleCondition.CompositeQuery)
+	}
+
+	return item, nil
+}
+
+// NewFavorite returns the key of an item in the favorites preference of a user.
+func NewFavorite(searchType Type, id string) string {
+	return searchType.StringValue() + ":" + id
+}
+
+func NewParams(req *http.Request) (*Params, error) {
+	query := req.URL.Query()
+	params := &Params{
+		Query:     strings.TrimSpace(query.Get("q")),
+		FolderID:  query.Get("folderId"),
+		Favorites: query.Get("favorites") == "true",
+		Limit:     DefaultLimit,
+	}
+
+	for _, value := range query["type"] {
+		searchType, err := NewType(value)
+		if err != nil {
+			return nil, err
+		}
+
+		params.Types = append(params.Types, searchType)
+	}
+
+	if params.FolderID != "" && slices.ContainsFunc(params.Types, func(searchType Type) bool { return searchType != TypeDashboard }) {
+		return nil, errors.New(errors.TypeInvalidInput, errors.CodeInvalidInput, "only dashboards are kept in folders, folderId can not be used with other types")
+	}
+
+	for _, tag := range query["tag"] {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			params.Tags = append(params.Tags, tag)
+		}
+	}
+
+	var err error
+	if limit := query.Get("limit"); limit != "" {
+		if params.Limit, err = strconv.Atoi(limit); err != nil || params.Limit <= 0 || params.Limit > MaxLimit {
+			return nil, errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "limit must be between 1 and %d", MaxLimit)
+		}
+	}
+
+	if offset := query.Get("offset"); offset != "" {
+		if params.Offset, err = strconv.Atoi(offset); err != nil || params.Offset < 0 {
+			return nil, errors.New(errors.TypeInvalidInput, errors.CodeInvalidInput, "offset must be a positive integer")
+		}
+	}
+
+	return params, nil
+}
+
+// Search filters the items with the params and ranks them, the best matches first. Without a query the favorites
+// come first and then the most recently updated items.
+func Search(items []*Item, params *Params) *GettableItems {
+	words := strings.Fields(strings.ToLower(params.Query))
+	scores := make(map[*Item]int, len(items))
+	matches := make([]*Item, 0, len(items))
+	for _, item := range items {
+		if !item.matches(params) {
+			continue
+		}
+
+		score, ok := item.score(words)
+		if !ok {
+			continue
+		}
+
+		scores[item] = score
+		matches = append(matches, item)
+	}
+
+	slices.SortStableFunc(matches, func(a, b *Item) int {
+		if score := cmp.Compare(scores[b], scores[a]); score != 0 {
+			return score
+		}
+
+		if a.Favorite != b.Favorite {
+			if a.Favorite {
+				return -1
+			}
+			return 1
+		}
+
+		return b.UpdatedAt.Compare(a.UpdatedAt)
+	})
+
+	page := matches[min(params.Offset, len(matches)):]
+	if params.Limit > 0 {
+		page = page[:min(params.Limit, len(page))]
+	}
+
+	return &GettableItems{Items: page, Total: len(matches)}
+}
+
+func (item *Item) matches(params *Params) bool {
+	if len(params.Types) > 0 && !slices.Contains(params.Types, item.Type) {
+		return false
+	}
+
+	if params.FolderID != "" && !slices.Contains(item.folderIDs, params.FolderID) {
+		return false
+	}
+
+	if params.Favorites && !item.Favorite {
+		return false
+	}
+
+	for _, tag := range params.Tags {
+		if !slices.ContainsFunc(item.Tags, func(itemTag string) bool { return strings.EqualFold(itemTag, tag) }) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// score scores how well the item matches the words, every word must match. Matches of the title weigh the most, then
+// the ones of the tags, of the description and of the queries.
+func (item *Item) score(words []string) (int, bool) {
+	title := strings.ToLower(item.Title)
+	description := strings.ToLower(item.Description)
+	tags := make([]string, 0, len(item.Tags))
+	for _, tag := range item.Tags {
+		tags = append(tags, strings.ToLower(tag))
+	}
+	queries := strings.ToLower(strings.Join(item.queries, "\n"))
+
+	total := 0
+	for _, word := range words {
+		score := 0
+		switch {
+		case title == word:
+			score += 16
+		case strings.HasPrefix(title, word):
+			score += 12
+		case strings.Contains(title, word):
+			score += 8
+		}
+
+		if slices.Contains(tags, word) {
+			score += 6
+		} else if slices.ContainsFunc(tags, func(tag string) bool { return strings.Contains(tag, word) }) {
+			score += 4
+		}
+
+		if strings.Contains(description, word) {
+			score += 2
+		}
+
+		if strings.Contains(queries, word) {
+			score += 1
+		}
+
+		if score == 0 {
+			return 0, false
+		}
+
+		total += score
+	}
+
+	return total, true
+}
+
+func compositeQueryTexts(compositeQuery *v3.CompositeQuery) []string {
+	if compositeQuery == nil {
+		return nil
+	}
+
+	texts := make([]string, 0)
+	for _, name := range slices.Sorted(maps.Keys(compositeQuery.BuilderQueries)) {
+		query := compositeQuery.BuilderQueries[name]
+		if query == nil {
+			continue
+		}
+
+		if query.AggregateAttribute.Key != "" {
+			texts = append(texts, query.AggregateAttribute.Key)
+		}
+		if query.Expression != "" {
+			texts = append(texts, query.Expression)
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(compositeQuery.PromQueries)) {
+		if query := compositeQuery.PromQueries[name]; query != nil && query.Query != "" {
+			texts = append(texts, query.Query)
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(compositeQuery.ClickHouseQueries)) {
+		if query := compositeQuery.ClickHouseQueries[name]; query != nil && query.Query != "" {
+			texts = append(texts, query.Query)
+		}
+	}
+
+	return texts
+}
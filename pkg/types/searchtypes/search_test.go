@@ -0,0 +1,53 @@
+package searchtypes
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewParams(t *testing.T) {
+	params, err := NewParams(httptest.NewRequest("GET", "/api/v1/search?q=+redis+&type=dashboard&type=rule&tag=cache&favorites=true&limit=5&offset=10", nil))
+	require.NoError(t, err)
+	assert.Equal(t, &Params{Query: "redis", Types: []Type{TypeDashboard, TypeRule}, Tags: []string{"cache"}, Favorites: true, Limit: 5, Offset: 10}, params)
+
+	_, err = NewParams(httptest.NewRequest("GET", "/api/v1/search?type=panel", nil))
+	assert.Error(t, err)
+
+	_, err = NewParams(httptest.NewRequest("GET", "/api/v1/search?limit=1000", nil))
+	assert.Error(t, err)
+
+	// only dashboards are kept in folders
+	_, err = NewParams(httptest.NewRequest("GET", "/api/v1/search?folderId=databases&type=view", nil))
+	assert.Error(t, err)
+}
+
+func TestSearch(t *testing.T) {
+	now := time.Now()
+	latency := &Item{Type: TypeDashboard, ID: "latency", Title: "latency", Tags: []string{"http"}, UpdatedAt: now}
+	checkout := &Item{Type: TypeDashboard, ID: "checkout", Title: "checkout", Description: "latency of the checkout", UpdatedAt: now.Add(-time.Hour), Favorite: true}
+	alert := &Item{Type: TypeRule, ID: "errors", Title: "errors", Tags: []string{"HTTP"}, UpdatedAt: now.Add(-2 * time.Hour), queries: []string{"http_server_latency_count"}}
+	items := []*Item{alert, latency, checkout}
+
+	// without a query the favorites come first, then the most recent items
+	results := Search(items, &Params{Limit: DefaultLimit})
+	assert.Equal(t, []*Item{checkout, latency, alert}, results.Items)
+
+	results = Search(items, &Params{Query: "Latency", Limit: DefaultLimit})
+	assert.Equal(t, []*Item{latency, checkout, alert}, results.Items)
+
+	// every word must match
+	results = Search(items, &Params{Query: "latency checkout", Limit: DefaultLimit})
+	assert.Equal(t, []*Item{checkout}, results.Items)
+
+	results = Search(items, &Params{Tags: []string{"http"}, Limit: 1, Offset: 1})
+	assert.Equal(t, 2, results.Total)
+	assert.Equal(t, []*Item{alert}, results.Items)
+
+	results = Search(items, &Params{Types: []Type{TypeRule}, Offset: 5, Limit: DefaultLimit})
+	assert.Equal(t, 1, results.Total)
+	assert.Empty(t, results.Items)
+}
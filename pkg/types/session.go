@@ -0,0 +1,115 @@
+package types
+
+import (
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/errors"
+	"github.com/SigNoz/signoz/pkg/valuer"
+	"github.com/uptrace/bun"
+)
+
+var (
+	ErrSessionNotFound = errors.MustNewCode("session_not_found")
+	ErrSessionRevoked  = errors.MustNewCode("session_revoked")
+	ErrSessionExpired  = errors.MustNewCode("session_expired")
+)
+
+// SessionRevocationReason is why a session was ended before its expiry.
+type SessionRevocationReason struct{ valuer.String }
+
+var (
+	SessionRevocationReasonLogout         = SessionRevocationReason{valuer.NewString("logout")}
+	SessionRevocationReasonForced         = SessionRevocationReason{valuer.NewString("forced")}
+	SessionRevocationReasonPasswordChange = SessionRevocationReason{valuer.NewString("password_change")}
+	SessionRevocationReasonRoleDowngrade  = SessionRevocationReason{valuer.NewString("role_downgrade")}
+	SessionRevocationReasonOrgSwitch      = SessionRevocationReason{valuer.NewString("org_switch")}
+	// a refresh token was used again after it had been rotated, the session is assumed to be stolen
+	SessionRevocationReasonRefreshTokenReuse = SessionRevocationReason{valuer.NewString("refresh_token_reuse")}
+)
+
+// StorableSession is the server side record of the tokens issued to a user on login, the tokens of a session are only
+// accepted as long as the session is neither revoked nor expired.
+type StorableSession struct {
+	bun.BaseModel `bun:"table:session"`
+
+	Identifiable
+	TimeAuditable
+	OrgID  string `bun:"org_id,type:text,notnull"`
+	UserID string `bun:"user_id,type:text,notnull"`
+	// id of the only refresh token of the session which can be exchanged, it changes on every refresh
+	RefreshTokenID string                  `bun:"refresh_token_id,type:text,notnull"`
+	ClientAddress  string                  `bun:"client_address,type:text"`
+	UserAgent      string                  `bun:"user_agent,type:text"`
+	ExpiresAt      time.Time               `bun:"expires_at,notnull"`
+	RevokedAt      time.Time               `bun:"revoked_at,nullzero"`
+	RevokedReason  SessionRevocationReason `bun:"revoked_reason,type:text"`
+}
+
+type GettableSession struct {
+	ID            valuer.UUID `json:"id"`
+	ClientAddress string      `json:"clientAddress"`
+	UserAgent     string      `json:"userAgent"`
+	CreatedAt     time.Time   `json:"createdAt"`
+	LastUsedAt    time.Time   `json:"lastUsedAt"`
+	ExpiresAt     time.Time   `json:"expiresAt"`
+	// set for the session of the token the request is made with
+	Current bool `json:"current"`
+}
+
+func NewStorableSession(orgID string, userID string, clientAddress string, userAgent string, expiry time.Duration) *StorableSession {
+	return &StorableSession{
+		Identifiable: Identifiable{
+			ID: valuer.GenerateUUID(),
+		},
+		TimeAuditable: TimeAuditable{
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		},
+		OrgID:          orgID,
+		UserID:         userID,
+		RefreshTokenID: valuer.GenerateUUID().StringValue(),
+		ClientAddress:  clientAddress,
+		UserAgent:      userAgent,
+		ExpiresAt:      time.Now().Add(expiry),
+	}
+}
+
+func NewGettableSession(session *StorableSession, currentID string) *GettableSession {
+	return &GettableSession{
+		ID:            session.ID,
+		ClientAddress: session.ClientAddress,
+		UserAgent:     session.UserAgent,
+		CreatedAt:     session.CreatedAt,
+		LastUsedAt:    session.UpdatedAt,
+		ExpiresAt:     session.ExpiresAt,
+		Current:       session.ID.StringValue() == currentID,
+	}
+}
+
+// Validate checks that the tokens of the session can still be used.
+func (session *StorableSession) Validate() error {
+	if !session.RevokedAt.IsZero() {
+		return errors.Newf(errors.TypeUnauthenticated, ErrSessionRevoked, "session has been revoked: %s", session.RevokedReason.StringValue())
+	}
+
+	if time.Now().After(session.ExpiresAt) {
+		return errors.New(errors.TypeUnauthenticated, ErrSessionExpired, "session has expired")
+	}
+
+	return nil
+}
+
+// Rotate invalidates the current refresh token of the session and extends it for the one issued in its place.
+func (session *StorableSession) Rotate(clientAddress string, userAgent string, expiry time.Duration) {
+	session.RefreshTokenID = valuer.GenerateUUID().StringValue()
+	session.ClientAddress = clientAddress
+	session.UserAgent = userAgent
+	session.ExpiresAt = time.Now().Add(expiry)
+	session.UpdatedAt = time.Now()
+}
+
+func (session *StorableSession) Revoke(reason SessionRevocationReason) {
+	session.RevokedAt = time.Now()
+	session.RevokedReason = reason
+	session.UpdatedAt = time.Now()
+}
@@ -0,0 +1,353 @@
+package sharetypes
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/errors"
+	"github.com/SigNoz/signoz/pkg/types"
+	"github.com/SigNoz/signoz/pkg/valuer"
+	"github.com/uptrace/bun"
+)
+
+var (
+	ErrCodeShareLinkNotFound = errors.MustNewCode("share_link_not_found")
+	ErrCodeShareLinkRevoked  = errors.MustNewCode("share_link_revoked")
+	ErrCodeShareLinkExpired  = errors.MustNewCode("share_link_expired")
+	ErrCodeShareLinkLocked   = errors.MustNewCode("share_link_locked")
+	ErrCodeShareLinkPassword = errors.MustNewCode("share_link_password_invalid")
+	ErrCodeUnlockThrottled   = errors.MustNewCode("share_link_throttled")
+	ErrCodeWidgetNotShared   = errors.MustNewCode("share_link_widget_not_shared")
+)
+
+// HeaderToken is the header carrying the token of a share link.
+const HeaderToken = "SIGNOZ-SHARE-TOKEN"
+
+const (
+	// MaxExpiry is how far in the future a link can expire.
+	MaxExpiry = 365 * 24 * time.Hour
+	// MaxTimeRange is the longest time range the shared panels can query.
+	MaxTimeRange = 30 * 24 * time.Hour
+	// UnlockExpiry is how long the token of a protected link stays unlocked after its password has been verified.
+	UnlockExpiry = 12 * time.Hour
+
+	minPasswordLength = 8
+)
+
+// relative time ranges are a number of minutes, hours, days or weeks, e.g. 15m or 7d.
+var relativeRegex = regexp.MustCompile(`^([1-9][0-9]*)([mhdw])$`)
+
+var relativeUnits = map[string]time.Duration{
+	"m": time.Minute,
+	"h": time.Hour,
+	"d": 24 * time.Hour,
+	"w": 7 * 24 * time.Hour,
+}
+
+type shareLinkKey struct{}
+
+// Authenticator resolves the link of a share token, see middleware.Share.
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (*ShareLink, error)
+}
+
+type ResourceType struct{ valuer.String }
+
+var (
+	ResourceTypeDashboard = ResourceType{valuer.NewString("dashboard")}
+	ResourceTypeSavedView = ResourceType{valuer.NewString("saved_view")}
+)
+
+func NewResourceType(resourceType string) (ResourceType, error) {
+	switch resourceType {
+	case ResourceTypeDashboard.StringValue():
+		return ResourceTypeDashboard, nil
+	case ResourceTypeSavedView.StringValue():
+		return ResourceTypeSavedView, nil
+	default:
+		return ResourceType{}, errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "invalid resource type: %s, must be one of dashboard, saved_view", resourceType)
+	}
+}
+
+func (r *ResourceType) UnmarshalJSON(data []byte) error {
+	var value valuer.String
+	if err := value.UnmarshalJSON(data); err != nil {
+		return err
+	}
+
+	resourceType, err := NewResourceType(value.StringValue())
+	if err != nil {
+		return err
+	}
+
+	*r = resourceType
+	return nil
+}
+
+// TimeRange is the time range the shared panels query, either fixed between start and end in epoch milliseconds or
+// relative to the time of the query.
+type TimeRange struct {
+	Start    uint64 `json:"start,omitempty"`
+	End      uint64 `json:"end,omitempty"`
+	Relative string `json:"relative,omitempty"`
+}
+
+// ShareLink grants read access to a dashboard, a panel of a dashboard or a saved view to the holders of its token
+// until it expires or is revoked.
+type ShareLink struct {
+	bun.BaseModel `bun:"table:share_link"`
+
+	types.Identifiable
+	types.TimeAuditable
+	OrgID        valuer.UUID  `bun:"org_id,type:text,notnull" json:"orgId"`
+	ResourceType ResourceType `bun:"resource_type,type:text,notnull" json:"resourceType"`
+	ResourceID   string       `bun:"resource_id,type:text,notnull" json:"resourceId"`
+	// the panel of the dashboard the link is restricted to, the whole dashboard is shared when empty
+	WidgetID  string    `bun:"widget_id,type:text" json:"widgetId,omitempty"`
+	TimeRange TimeRange `bun:"time_range,type:text,notnull" json:"timeRange"`
+	// the values of the variables of the shared panels are fixed when the link is created
+	Variables    map[string]any `bun:"variables,type:text" json:"variables,omitempty"`
+	PasswordHash string         `bun:"password_hash,type:text" json:"-"`
+	ExpiresAt    time.Time      `bun:"expires_at,notnull" json:"expiresAt"`
+	RevokedAt    *time.Time     `bun:"revoked_at" json:"revokedAt,omitempty"`
+	CreatedBy    string         `bun:"created_by,type:text,notnull" json:"createdBy"`
+}
+
+type GettableShareLink struct {
+	*ShareLink
+	Protected bool `json:"protected"`
+	// the token of an active link, it is signed again on every read
+	Token string `json:"token,omitempty"`
+}
+
+type PostableShareLink struct {
+	ResourceType ResourceType   `json:"resourceType"`
+	ResourceID   string         `json:"resourceId"`
+	WidgetID     string         `json:"widgetId"`
+	TimeRange    TimeRange      `json:"timeRange"`
+	Variables    map[string]any `json:"variables"`
+	ExpiresAt    time.Time      `json:"expiresAt"`
+	Password     string         `json:"password"`
+}
+
+type PostableUnlock struct {
+	Password string `json:"password"`
+}
+
+type GettableToken struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// GettableSharedResource is what the holders of a link read, the dashboard is restricted to the shared widget.
+type GettableSharedResource struct {
+	ResourceType ResourceType `json:"resourceType"`
+	WidgetID     string       `json:"widgetId,omitempty"`
+	Start        uint64       `json:"start"`
+	End          uint64       `json:"end"`
+	ExpiresAt    time.Time    `json:"expiresAt"`
+	Data         any          `json:"data"`
+}
+
+// PostableSharedQuery selects the panel of the shared dashboard to query, the queries themselves are read from the
+// dashboard.
+type PostableSharedQuery struct {
+	WidgetID string `json:"widgetId"`
+}
+
+func NewShareLink(orgID valuer.UUID, createdBy string, postable *PostableShareLink) (*ShareLink, error) {
+	passwordHash := ""
+	if postable.Password != "" {
+		hash, err := types.HashPassword(postable.Password)
+		if err != nil {
+			return nil, errors.Wrapf(err, errors.TypeInternal, errors.CodeInternal, "failed to hash the password of the share link")
+		}
+
+		passwordHash = hash
+	}
+
+	return &ShareLink{
+		Identifiable: types.Identifiable{
+			ID: valuer.GenerateUUID(),
+		},
+		TimeAuditable: types.TimeAuditable{
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		},
+		OrgID:        orgID,
+		ResourceType: postable.ResourceType,
+		ResourceID:   postable.ResourceID,
+		WidgetID:     postable.WidgetID,
+		TimeRange:    postable.TimeRange,
+		Variables:    postable.Variables,
+		PasswordHash: passwordHash,
+		ExpiresAt:    postable.ExpiresAt,
+		CreatedBy:    createdBy,
+	}, nil
+}
+
+func NewGettableShareLink(link *ShareLink, token string) *GettableShareLink {
+	return &GettableShareLink{ShareLink: link, Protected: link.PasswordHash != "", Token: token}
+}
+
+// NewContextWithShareLink attaches the link a request was authenticated with to the context.
+func NewContextWithShareLink(ctx context.Context, link *ShareLink) context.Context {
+	return context.WithValue(ctx, shareLinkKey{}, link)
+}
+
+func ShareLinkFromContext(ctx context.Context) (*ShareLink, error) {
+	link, ok := ctx.Value(shareLinkKey{}).(*ShareLink)
+	if !ok || link == nil {
+		return nil, errors.New(errors.TypeUnauthenticated, errors.CodeUnauthenticated, "missing share token")
+	}
+
+	return link, nil
+}
+
+// CheckActive checks that the link can still be used, the password of a protected link must have been verified.
+func (link *ShareLink) CheckActive(now time.Time, unlocked bool) error {
+	if link.RevokedAt != nil {
+		return errors.New(errors.TypeForbidden, ErrCodeShareLinkRevoked, "share link has been revoked")
+	}
+
+	if !now.Before(link.ExpiresAt) {
+		return errors.New(errors.TypeForbidden, ErrCodeShareLinkExpired, "share link has expired")
+	}
+
+	if link.PasswordHash != "" && !unlocked {
+		return errors.New(errors.TypeUnauthenticated, ErrCodeShareLinkLocked, "share link is protected by a password")
+	}
+
+	return nil
+}
+
+// CheckPassword checks the password of a protected link, links without a password accept any.
+func (link *ShareLink) CheckPassword(password string) error {
+	if link.PasswordHash == "" {
+		return nil
+	}
+
+	if !types.ComparePassword(link.PasswordHash, password) {
+		return errors.New(errors.TypeUnauthenticated, ErrCodeShareLinkPassword, "invalid password")
+	}
+
+	return nil
+}
+
+// CheckWidget checks that the widget of a query is shared by the link.
+func (link *ShareLink) CheckWidget(widgetID string) error {
+	if link.ResourceType == ResourceTypeSavedView {
+		if widgetID != "" {
+			return errors.New(errors.TypeInvalidInput, errors.CodeInvalidInput, "saved views have no widgets")
+		}
+
+		return nil
+	}
+
+	if widgetID == "" {
+		return errors.New(errors.TypeInvalidInput, errors.CodeInvalidInput, "widgetId is required")
+	}
+
+	if link.WidgetID != "" && link.WidgetID != widgetID {
+		return errors.Newf(errors.TypeForbidden, ErrCodeWidgetNotShared, "widget: %s is not shared by the link", widgetID)
+	}
+
+	return nil
+}
+
+func (link *ShareLink) Revoke() {
+	now := time.Now()
+	link.RevokedAt = &now
+	link.UpdatedAt = now
+}
+
+// Resolve returns the start and end of the time range in epoch milliseconds, relative ranges end at now.
+func (timeRange TimeRange) Resolve(now time.Time) (uint64, uint64) {
+	if timeRange.Relative == "" {
+		return timeRange.Start, timeRange.End
+	}
+
+	duration, _ := parseRelative(timeRange.Relative)
+	return uint64(now.Add(-duration).UnixMilli()), uint64(now.UnixMilli())
+}
+
+func (timeRange TimeRange) Validate() error {
+	if timeRange.Relative != "" {
+		if timeRange.Start != 0 || timeRange.End != 0 {
+			return errors.New(errors.TypeInvalidInput, errors.CodeInvalidInput, "timeRange must be either relative or between start and end")
+		}
+
+		duration, ok := parseRelative(timeRange.Relative)
+		if !ok {
+			return errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "invalid relative time range: %q, must be a number of minutes, hours, days or weeks such as 15m or 7d", timeRange.Relative)
+		}
+
+		if duration > MaxTimeRange {
+			return errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "relative time range: %s must not be longer than %s", timeRange.Relative, MaxTimeRange)
+		}
+
+		return nil
+	}
+
+	if timeRange.Start == 0 || timeRange.End == 0 {
+		return errors.New(errors.TypeInvalidInput, errors.CodeInvalidInput, "timeRange requires either relative or both start and end")
+	}
+
+	if timeRange.Start >= timeRange.End {
+		return errors.New(errors.TypeInvalidInput, errors.CodeInvalidInput, "start of the timeRange must be before its end")
+	}
+
+	if time.Duration(timeRange.End-timeRange.Start)*time.Millisecond > MaxTimeRange {
+		return errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "timeRange must not be longer than %s", MaxTimeRange)
+	}
+
+	return nil
+}
+
+func (p *PostableShareLink) Validate(now time.Time) error {
+	if p.ResourceType.IsZero() {
+		return errors.New(errors.TypeInvalidInput, errors.CodeInvalidInput, "resourceType is required")
+	}
+
+	if p.ResourceID == "" {
+		return errors.New(errors.TypeInvalidInput, errors.CodeInvalidInput, "resourceId is required")
+	}
+
+	if p.ResourceType == ResourceTypeSavedView && p.WidgetID != "" {
+		return errors.New(errors.TypeInvalidInput, errors.CodeInvalidInput, "widgetId can only be set for dashboards")
+	}
+
+	if err := p.TimeRange.Validate(); err != nil {
+		return err
+	}
+
+	if !p.ExpiresAt.After(now) {
+		return errors.New(errors.TypeInvalidInput, errors.CodeInvalidInput, "expiresAt must be in the future")
+	}
+
+	if p.ExpiresAt.After(now.Add(MaxExpiry)) {
+		return errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "expiresAt must be within %s", MaxExpiry)
+	}
+
+	if p.Password != "" && len(p.Password) < minPasswordLength {
+		return errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "password must be at least %d characters long", minPasswordLength)
+	}
+
+	return nil
+}
+
+func parseRelative(relative string) (time.Duration, bool) {
+	matches := relativeRegex.FindStringSubmatch(relative)
+	if matches == nil {
+		return 0, false
+	}
+
+	count, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return 0, false
+	}
+
+	return time.Duration(count) * relativeUnits[matches[2]], true
+}
@@ -0,0 +1,99 @@
+package sharetypes
+
+import (
+	"testing"
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/errors"
+	"github.com/SigNoz/signoz/pkg/valuer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostableShareLinkValidate(t *testing.T) {
+	now := time.Now()
+	valid := func() *PostableShareLink {
+		return &PostableShareLink{
+			ResourceType: ResourceTypeDashboard,
+			ResourceID:   valuer.GenerateUUID().StringValue(),
+			WidgetID:     "a",
+			TimeRange:    TimeRange{Relative: "1h"},
+			ExpiresAt:    now.Add(24 * time.Hour),
+		}
+	}
+
+	require.NoError(t, valid().Validate(now))
+
+	testCases := []struct {
+		name   string
+		mutate func(*PostableShareLink)
+	}{
+		{name: "MissingResourceType", mutate: func(p *PostableShareLink) { p.ResourceType = ResourceType{} }},
+		{name: "MissingResourceID", mutate: func(p *PostableShareLink) { p.ResourceID = "" }},
+		{name: "SavedViewWidget", mutate: func(p *PostableShareLink) { p.ResourceType = ResourceTypeSavedView }},
+		{name: "InvalidRelative", mutate: func(p *PostableShareLink) { p.TimeRange = TimeRange{Relative: "1y"} }},
+		{name: "RelativeTooLong", mutate: func(p *PostableShareLink) { p.TimeRange = TimeRange{Relative: "5w"} }},
+		{name: "RelativeAndFixed", mutate: func(p *PostableShareLink) { p.TimeRange.Start = 1 }},
+		{name: "MissingTimeRange", mutate: func(p *PostableShareLink) { p.TimeRange = TimeRange{} }},
+		{name: "EndBeforeStart", mutate: func(p *PostableShareLink) { p.TimeRange = TimeRange{Start: 2000, End: 1000} }},
+		{name: "Expired", mutate: func(p *PostableShareLink) { p.ExpiresAt = now.Add(-time.Minute) }},
+		{name: "ExpiryTooFar", mutate: func(p *PostableShareLink) { p.ExpiresAt = now.Add(MaxExpiry + time.Hour) }},
+		{name: "ShortPassword", mutate: func(p *PostableShareLink) { p.Password = "secret" }},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			postable := valid()
+			testCase.mutate(postable)
+			assert.True(t, errors.Ast(postable.Validate(now), errors.TypeInvalidInput))
+		})
+	}
+}
+
+func TestTimeRangeResolve(t *testing.T) {
+	now := time.UnixMilli(1_700_000_000_000)
+
+	start, end := TimeRange{Relative: "7d"}.Resolve(now)
+	assert.Equal(t, uint64(now.UnixMilli()), end)
+	assert.Equal(t, uint64(now.Add(-7*24*time.Hour).UnixMilli()), start)
+
+	start, end = TimeRange{Start: 1000, End: 2000}.Resolve(now)
+	assert.Equal(t, uint64(1000), start)
+	assert.Equal(t, uint64(2000), end)
+}
+
+func TestShareLinkCheckActive(t *testing.T) {
+	now := time.Now()
+	link, err := NewShareLink(valuer.GenerateUUID(), "creator@example.com", &PostableShareLink{
+		ResourceType: ResourceTypeDashboard,
+		ResourceID:   "dashboard",
+		TimeRange:    TimeRange{Relative: "1h"},
+		ExpiresAt:    now.Add(time.Hour),
+		Password:     "correct horse",
+	})
+	require.NoError(t, err)
+
+	assert.True(t, errors.Asc(link.CheckActive(now, false), ErrCodeShareLinkLocked))
+	assert.NoError(t, link.CheckActive(now, true))
+	assert.True(t, errors.Asc(link.CheckActive(now.Add(2*time.Hour), true), ErrCodeShareLinkExpired))
+
+	assert.True(t, errors.Asc(link.CheckPassword("battery staple"), ErrCodeShareLinkPassword))
+	assert.NoError(t, link.CheckPassword("correct horse"))
+
+	link.Revoke()
+	assert.True(t, errors.Asc(link.CheckActive(now, true), ErrCodeShareLinkRevoked))
+}
+
+func TestShareLinkCheckWidget(t *testing.T) {
+	dashboard := &ShareLink{ResourceType: ResourceTypeDashboard}
+	assert.NoError(t, dashboard.CheckWidget("a"))
+	assert.True(t, errors.Ast(dashboard.CheckWidget(""), errors.TypeInvalidInput))
+
+	panel := &ShareLink{ResourceType: ResourceTypeDashboard, WidgetID: "a"}
+	assert.NoError(t, panel.CheckWidget("a"))
+	assert.True(t, errors.Asc(panel.CheckWidget("b"), ErrCodeWidgetNotShared))
+
+	savedView := &ShareLink{ResourceType: ResourceTypeSavedView}
+	assert.NoError(t, savedView.CheckWidget(""))
+	assert.True(t, errors.Ast(savedView.CheckWidget("a"), errors.TypeInvalidInput))
+}
@@ -0,0 +1,14 @@
+package sharetypes
+
+import (
+	"context"
+
+	"github.com/SigNoz/signoz/pkg/valuer"
+)
+
+type Store interface {
+	Create(context.Context, *ShareLink) error
+	Get(context.Context, valuer.UUID, valuer.UUID) (*ShareLink, error)
+	List(context.Context, valuer.UUID) ([]*ShareLink, error)
+	Update(context.Context, *ShareLink) error
+}
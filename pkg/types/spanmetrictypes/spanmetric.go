@@ -0,0 +1,205 @@
+package spanmetrictypes
+
+import (
+	"encoding/json"
+	"regexp"
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/errors"
+	"github.com/SigNoz/signoz/pkg/types"
+	qbtypes "github.com/SigNoz/signoz/pkg/types/querybuildertypes/querybuildertypesv5"
+	"github.com/SigNoz/signoz/pkg/valuer"
+	"github.com/uptrace/bun"
+)
+
+var (
+	ErrCodeGeneratorAlreadyExists = errors.MustNewCode("span_metrics_generator_already_exists")
+	ErrCodeGeneratorNotFound      = errors.MustNewCode("span_metrics_generator_not_found")
+)
+
+const (
+	// cardinality cap used when none is specified
+	DefaultCardinalityLimit = 1000
+	MaxCardinalityLimit     = 100000
+	MaxDimensions           = 20
+)
+
+var namespaceRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_.]*$`)
+
+// Dimension is an attribute of the spans added as a label to the generated metrics.
+// It is looked up in the span attributes and then in the resource attributes.
+type Dimension struct {
+	Name string `json:"name"`
+	// value used when the span doesn't have the attribute, spans without the attribute are
+	// aggregated without the label if no default is set
+	Default *string `json:"default,omitempty"`
+}
+
+// GeneratorConfig is the definition of the RED metrics generated from spans.
+type GeneratorConfig struct {
+	// only spans matching the filter are aggregated, all spans if empty
+	Filter     *qbtypes.Filter `json:"filter,omitempty"`
+	Dimensions []Dimension     `json:"dimensions"`
+	// explicit bucket boundaries of the duration histogram in milliseconds,
+	// the defaults of the collector are used if empty
+	HistogramBuckets []float64 `json:"histogramBuckets,omitempty"`
+	// maximum number of distinct dimension combinations tracked by the collector
+	CardinalityLimit int `json:"cardinalityLimit"`
+}
+
+// StorableGenerator is a revision of a span metrics generator. Revisions are never updated so
+// that the earlier agent config versions keep deploying the generator they were created with.
+type StorableGenerator struct {
+	bun.BaseModel `bun:"table:span_metrics_generator_revision"`
+
+	types.Identifiable
+	types.TimeAuditable
+	types.UserAuditable
+	// id of the generator shared by all its revisions
+	DefinitionID valuer.UUID `bun:"definition_id,type:text,notnull"`
+	OrgID        string      `bun:"org_id,type:text,notnull"`
+	Name         string      `bun:"name,type:text,notnull"`
+	Description  string      `bun:"description,type:text"`
+	Config       string      `bun:"config,type:text,notnull"`
+	Enabled      bool        `bun:"enabled,notnull"`
+}
+
+// GettableGenerator generates `<name>.calls` and `<name>.duration` metrics from spans.
+type GettableGenerator struct {
+	types.Identifiable
+	types.TimeAuditable
+	types.UserAuditable
+	GeneratorConfig
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Enabled     bool   `json:"enabled"`
+}
+
+type PostableGenerator struct {
+	GeneratorConfig
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Enabled     bool   `json:"enabled"`
+}
+
+// Validate checks the generator and fills in the defaults.
+func (p *PostableGenerator) Validate() error {
+	if !namespaceRegex.MatchString(p.Name) {
+		return errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "invalid name %q, names of span metrics generators must match %s", p.Name, namespaceRegex.String())
+	}
+
+	if len(p.Dimensions) > MaxDimensions {
+		return errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "span metrics generators can have at most %d dimensions, got %d", MaxDimensions, len(p.Dimensions))
+	}
+
+	seen := map[string]struct{}{}
+	for _, dimension := range p.Dimensions {
+		if dimension.Name == "" {
+			return errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "dimension names can not be empty")
+		}
+		if _, ok := seen[dimension.Name]; ok {
+			return errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "duplicate dimension %s", dimension.Name)
+		}
+		seen[dimension.Name] = struct{}{}
+	}
+
+	for i, bucket := range p.HistogramBuckets {
+		if bucket <= 0 {
+			return errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "histogram buckets must be positive, got %v", bucket)
+		}
+		if i > 0 && bucket <= p.HistogramBuckets[i-1] {
+			return errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "histogram buckets must be in increasing order")
+		}
+	}
+
+	if p.CardinalityLimit == 0 {
+		p.CardinalityLimit = DefaultCardinalityLimit
+	}
+	if p.CardinalityLimit < 0 || p.CardinalityLimit > MaxCardinalityLimit {
+		return errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "cardinality limit must be between 1 and %d, got %d", MaxCardinalityLimit, p.CardinalityLimit)
+	}
+
+	return nil
+}
+
+func NewStorableGenerator(orgID valuer.UUID, createdBy string, postable *PostableGenerator) (*StorableGenerator, error) {
+	config, err := json.Marshal(postable.GeneratorConfig)
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.TypeInternal, errors.CodeInternal, "failed to serialize the config of the span metrics generator")
+	}
+
+	id := valuer.GenerateUUID()
+	return &StorableGenerator{
+		Identifiable: types.Identifiable{ID: id},
+		DefinitionID: id,
+		TimeAuditable: types.TimeAuditable{
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		},
+		UserAuditable: types.UserAuditable{
+			CreatedBy: createdBy,
+			UpdatedBy: createdBy,
+		},
+		OrgID:       orgID.StringValue(),
+		Name:        postable.Name,
+		Description: postable.Description,
+		Config:      string(config),
+		Enabled:     postable.Enabled,
+	}, nil
+}
+
+// ElementID is the id of the revision in the agent config versions it is an element of.
+func (s *StorableGenerator) ElementID() valuer.UUID {
+	return s.ID
+}
+
+func (s *StorableGenerator) ElementDefinitionID() valuer.UUID {
+	return s.DefinitionID
+}
+
+func (s *StorableGenerator) ElementName() string {
+	return s.Name
+}
+
+// NewRevision returns a new revision of the generator with the definition in `postable`.
+func (s *StorableGenerator) NewRevision(updatedBy string, postable *PostableGenerator) (*StorableGenerator, error) {
+	config, err := json.Marshal(postable.GeneratorConfig)
+	if err != nil {
+		return nil, errors.Wrapf(err, errors.TypeInternal, errors.CodeInternal, "failed to serialize the config of the span metrics generator")
+	}
+
+	return &StorableGenerator{
+		Identifiable: types.Identifiable{ID: valuer.GenerateUUID()},
+		DefinitionID: s.DefinitionID,
+		TimeAuditable: types.TimeAuditable{
+			CreatedAt: s.CreatedAt,
+			UpdatedAt: time.Now(),
+		},
+		UserAuditable: types.UserAuditable{
+			CreatedBy: s.CreatedBy,
+			UpdatedBy: updatedBy,
+		},
+		OrgID:       s.OrgID,
+		Name:        postable.Name,
+		Description: postable.Description,
+		Config:      string(config),
+		Enabled:     postable.Enabled,
+	}, nil
+}
+
+func NewGettableGenerator(storable *StorableGenerator) (*GettableGenerator, error) {
+	gettable := &GettableGenerator{
+		Identifiable:  types.Identifiable{ID: storable.DefinitionID},
+		TimeAuditable: storable.TimeAuditable,
+		UserAuditable: storable.UserAuditable,
+		Name:          storable.Name,
+		Description:   storable.Description,
+		Enabled:       storable.Enabled,
+	}
+
+	if err := json.Unmarshal([]byte(storable.Config), &gettable.GeneratorConfig); err != nil {
+		return nil, errors.Wrapf(err, errors.TypeInternal, errors.CodeInternal, "failed to parse the config of span metrics generator %s", storable.Name)
+	}
+
+	return gettable, nil
+}
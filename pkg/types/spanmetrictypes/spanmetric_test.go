@@ -0,0 +1,40 @@
+package spanmetrictypes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostableGeneratorValidate(t *testing.T) {
+	valid := func() PostableGenerator {
+		return PostableGenerator{
+			Name: "tenant_red",
+			GeneratorConfig: GeneratorConfig{
+				Dimensions:       []Dimension{{Name: "http.route"}, {Name: "tenant.id"}},
+				HistogramBuckets: []float64{5, 10, 100, 1000},
+			},
+		}
+	}
+
+	generator := valid()
+	require.NoError(t, generator.Validate())
+	assert.Equal(t, DefaultCardinalityLimit, generator.CardinalityLimit)
+
+	generator = valid()
+	generator.Name = "tenant red"
+	assert.Error(t, generator.Validate())
+
+	generator = valid()
+	generator.Dimensions = append(generator.Dimensions, Dimension{Name: "tenant.id"})
+	assert.Error(t, generator.Validate())
+
+	generator = valid()
+	generator.HistogramBuckets = []float64{10, 5}
+	assert.Error(t, generator.Validate())
+
+	generator = valid()
+	generator.CardinalityLimit = MaxCardinalityLimit + 1
+	assert.Error(t, generator.Validate())
+}
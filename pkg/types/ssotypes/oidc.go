@@ -0,0 +1,276 @@
+package ssotypes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+const (
+	oidcWellKnownPath = "/.well-known/openid-configuration"
+
+	// discovery documents (and the JWKS they point to) are cached for this long, keys rotated in
+	// between are fetched when a token signed with an unknown key is received.
+	oidcProviderCacheTTL = time.Hour
+)
+
+var defaultOIDCScopes = []string{oidc.ScopeOpenID, "email", "profile"}
+
+// OIDCClaimMapping maps the claims of the ID token to the attributes of the user.
+type OIDCClaimMapping struct {
+	// claim holding the email of the user, defaults to `email`
+	Email string `json:"email"`
+	// claim holding the display name of the user, defaults to `name`
+	Name string `json:"name"`
+}
+
+// OIDCConfig configures a generic OpenID Connect provider (Keycloak, Okta, Auth0, ...).
+type OIDCConfig struct {
+	// the discovery URL of the provider, either the issuer or the full
+	// `<issuer>/.well-known/openid-configuration` URL
+	DiscoveryURL string           `json:"discoveryUrl"`
+	ClientID     string           `json:"clientId"`
+	ClientSecret string           `json:"clientSecret"`
+	Scopes       []string         `json:"scopes"`
+	ClaimMapping OIDCClaimMapping `json:"claimMapping"`
+	// accept emails the provider reports as not verified
+	InsecureSkipEmailVerified bool `json:"insecureSkipEmailVerified"`
+}
+
+func (c *OIDCConfig) Validate() error {
+	if c.DiscoveryURL == "" {
+		return fmt.Errorf("discovery URL is required")
+	}
+
+	discoveryURL, err := url.Parse(c.DiscoveryURL)
+	if err != nil || discoveryURL.Host == "" || (discoveryURL.Scheme != "https" && discoveryURL.Scheme != "http") {
+		return fmt.Errorf("invalid discovery URL %q", c.DiscoveryURL)
+	}
+
+	if c.ClientID == "" || c.ClientSecret == "" {
+		return fmt.Errorf("client id and client secret are required")
+	}
+
+	return nil
+}
+
+// Issuer returns the issuer URL the discovery document is served under.
+func (c *OIDCConfig) Issuer() string {
+	return strings.TrimSuffix(strings.TrimSuffix(c.DiscoveryURL, "/"), oidcWellKnownPath)
+}
+
+func (c *OIDCConfig) scopes() []string {
+	if len(c.Scopes) == 0 {
+		return defaultOIDCScopes
+	}
+
+	// the openid scope is what makes the provider return an ID token
+	for _, scope := range c.Scopes {
+		if scope == oidc.ScopeOpenID {
+			return c.Scopes
+		}
+	}
+	return append([]string{oidc.ScopeOpenID}, c.Scopes...)
+}
+
+func (c *OIDCConfig) GetProvider(domain string, siteUrl *url.URL) (OAuthCallbackProvider, error) {
+	provider, err := oidcProviders.get(context.Background(), c.Issuer())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get provider: %v", err)
+	}
+
+	// this is the url the provider will call after login completion
+	redirectURL := fmt.Sprintf("%s://%s/%s",
+		siteUrl.Scheme,
+		siteUrl.Host,
+		"api/v1/complete/oidc")
+
+	emailClaim := c.ClaimMapping.Email
+	if emailClaim == "" {
+		emailClaim = "email"
+	}
+	nameClaim := c.ClaimMapping.Name
+	if nameClaim == "" {
+		nameClaim = "name"
+	}
+
+	return &OIDCProvider{
+		OAuth2Config: &oauth2.Config{
+			ClientID:     c.ClientID,
+			ClientSecret: c.ClientSecret,
+			Endpoint:     provider.Endpoint(),
+			Scopes:       c.scopes(),
+			RedirectURL:  redirectURL,
+		},
+		Provider:                  provider,
+		Verifier:                  provider.Verifier(&oidc.Config{ClientID: c.ClientID}),
+		Domain:                    domain,
+		EmailClaim:                emailClaim,
+		NameClaim:                 nameClaim,
+		InsecureSkipEmailVerified: c.InsecureSkipEmailVerified,
+	}, nil
+}
+
+type OIDCProvider struct {
+	OAuth2Config              *oauth2.Config
+	Provider                  *oidc.Provider
+	Verifier                  *oidc.IDTokenVerifier
+	Domain                    string
+	EmailClaim                string
+	NameClaim                 string
+	InsecureSkipEmailVerified bool
+}
+
+func (o *OIDCProvider) BuildAuthURL(state string) (string, error) {
+	return o.OAuth2Config.AuthCodeURL(state), nil
+}
+
+func (o *OIDCProvider) HandleCallback(r *http.Request) (identity *SSOIdentity, err error) {
+	q := r.URL.Query()
+	if errType := q.Get("error"); errType != "" {
+		return identity, &oauth2Error{errType, q.Get("error_description")}
+	}
+
+	token, err := o.OAuth2Config.Exchange(r.Context(), q.Get("code"))
+	if err != nil {
+		return identity, fmt.Errorf("oidc: failed to get token: %v", err)
+	}
+
+	return o.createIdentity(r.Context(), token)
+}
+
+func (o *OIDCProvider) createIdentity(ctx context.Context, token *oauth2.Token) (identity *SSOIdentity, err error) {
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return identity, errors.New("oidc: no id_token in token response")
+	}
+	idToken, err := o.Verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return identity, fmt.Errorf("oidc: failed to verify ID Token: %v", err)
+	}
+
+	claims := map[string]interface{}{}
+	if err := idToken.Claims(&claims); err != nil {
+		return identity, fmt.Errorf("oidc: failed to decode claims: %v", err)
+	}
+
+	// providers may only return the profile of the user from the userinfo endpoint
+	if _, ok := claims[o.EmailClaim]; !ok && o.Provider.UserInfoEndpoint() != "" {
+		userInfo, err := o.Provider.UserInfo(ctx, oauth2.StaticTokenSource(token))
+		if err != nil {
+			return identity, fmt.Errorf("oidc: failed to get user info: %v", err)
+		}
+		if userInfo.Subject != idToken.Subject {
+			return identity, fmt.Errorf("oidc: subject of user info %q does not match the ID token", userInfo.Subject)
+		}
+		if err := userInfo.Claims(&claims); err != nil {
+			return identity, fmt.Errorf("oidc: failed to decode user info claims: %v", err)
+		}
+	}
+
+	email, _ := claims[o.EmailClaim].(string)
+	if email == "" {
+		return identity, fmt.Errorf("oidc: missing %s claim", o.EmailClaim)
+	}
+
+	// the identity is matched to users by email, the provider of a domain must not
+	// be able to sign in users of other domains.
+	if !strings.EqualFold(emailDomain(email), o.Domain) {
+		return identity, fmt.Errorf("oidc: email %s does not belong to domain %s", email, o.Domain)
+	}
+
+	emailVerified := true
+	if verified, ok := claims["email_verified"].(bool); ok {
+		emailVerified = verified
+	}
+	if !emailVerified && !o.InsecureSkipEmailVerified {
+		return identity, fmt.Errorf("oidc: email %s is not verified", email)
+	}
+
+	name, _ := claims[o.NameClaim].(string)
+	preferredUsername, _ := claims["preferred_username"].(string)
+
+	identity = &SSOIdentity{
+		UserID:            idToken.Subject,
+		Username:          name,
+		PreferredUsername: preferredUsername,
+		Email:             email,
+		EmailVerified:     emailVerified,
+		ConnectorData:     []byte(token.RefreshToken),
+		Attributes:        stringClaims(claims),
+	}
+
+	return identity, nil
+}
+
+// stringClaims returns the claims holding a string or a list of strings, group claims are
+// lists while some providers send a single group as a string.
+func stringClaims(claims map[string]interface{}) map[string][]string {
+	attributes := map[string][]string{}
+	for name, claim := range claims {
+		switch value := claim.(type) {
+		case string:
+			attributes[name] = []string{value}
+		case []interface{}:
+			values := []string{}
+			for _, v := range value {
+				if s, ok := v.(string); ok {
+					values = append(values, s)
+				}
+			}
+			attributes[name] = values
+		}
+	}
+
+	return attributes
+}
+
+func emailDomain(email string) string {
+	if i := strings.LastIndex(email, "@"); i >= 0 {
+		return email[i+1:]
+	}
+	return ""
+}
+
+type cachedOIDCProvider struct {
+	provider  *oidc.Provider
+	expiresAt time.Time
+}
+
+// oidcProviderCache keeps the discovered providers so that their JWKS are fetched once
+// and reused across logins instead of on every callback.
+type oidcProviderCache struct {
+	mu        sync.Mutex
+	providers map[string]cachedOIDCProvider
+	ttl       time.Duration
+}
+
+var oidcProviders = &oidcProviderCache{providers: map[string]cachedOIDCProvider{}, ttl: oidcProviderCacheTTL}
+
+func (c *oidcProviderCache) get(ctx context.Context, issuer string) (*oidc.Provider, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if cached, ok := c.providers[issuer]; ok && time.Now().Before(cached.expiresAt) {
+		return cached.provider, nil
+	}
+
+	discoveryCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	provider, err := oidc.NewProvider(discoveryCtx, issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	c.providers[issuer] = cachedOIDCProvider{provider: provider, expiresAt: time.Now().Add(c.ttl)}
+	return provider, nil
+}
@@ -0,0 +1,254 @@
+package ssotypes
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const stubClientID = "signoz"
+
+// stubIdP is a minimal OpenID Connect provider issuing ID tokens with the configured claims.
+type stubIdP struct {
+	server       *httptest.Server
+	key          *rsa.PrivateKey
+	claims       jwt.MapClaims
+	userInfo     map[string]interface{}
+	jwksRequests atomic.Int32
+}
+
+func newStubIdP(t *testing.T) *stubIdP {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	idp := &stubIdP{key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(rw http.ResponseWriter, r *http.Request) {
+		writeJSON(rw, map[string]interface{}{
+			"issuer":                                idp.server.URL,
+			"authorization_endpoint":                idp.server.URL + "/authorize",
+			"token_endpoint":                        idp.server.URL + "/token",
+			"jwks_uri":                              idp.server.URL + "/jwks",
+			"userinfo_endpoint":                     idp.server.URL + "/userinfo",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	})
+	mux.HandleFunc("/jwks", func(rw http.ResponseWriter, r *http.Request) {
+		idp.jwksRequests.Add(1)
+		writeJSON(rw, map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"alg": "RS256",
+				"use": "sig",
+				"kid": "stub",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(rw http.ResponseWriter, r *http.Request) {
+		claims := jwt.MapClaims{
+			"iss": idp.server.URL,
+			"aud": stubClientID,
+			"sub": "user-1",
+			"iat": time.Now().Unix(),
+			"exp": time.Now().Add(time.Hour).Unix(),
+		}
+		for k, v := range idp.claims {
+			claims[k] = v
+		}
+
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = "stub"
+		idToken, err := token.SignedString(key)
+		require.NoError(t, err)
+
+		writeJSON(rw, map[string]interface{}{
+			"access_token": "access-token",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+			"id_token":     idToken,
+		})
+	})
+	mux.HandleFunc("/userinfo", func(rw http.ResponseWriter, r *http.Request) {
+		writeJSON(rw, idp.userInfo)
+	})
+
+	idp.server = httptest.NewServer(mux)
+	t.Cleanup(idp.server.Close)
+	return idp
+}
+
+func writeJSON(rw http.ResponseWriter, data interface{}) {
+	rw.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(rw).Encode(data)
+}
+
+func (idp *stubIdP) config() *OIDCConfig {
+	return &OIDCConfig{
+		DiscoveryURL: idp.server.URL + "/.well-known/openid-configuration",
+		ClientID:     stubClientID,
+		ClientSecret: "secret",
+	}
+}
+
+func callback(t *testing.T, config *OIDCConfig) (*SSOIdentity, error) {
+	siteUrl, err := url.Parse("https://signoz.example.com/login")
+	require.NoError(t, err)
+
+	provider, err := config.GetProvider("example.com", siteUrl)
+	require.NoError(t, err)
+
+	return provider.HandleCallback(httptest.NewRequest(http.MethodGet, "/api/v1/complete/oidc?code=code&state=state", nil))
+}
+
+func TestOIDCProviderBuildAuthURL(t *testing.T) {
+	idp := newStubIdP(t)
+
+	siteUrl, err := url.Parse("https://signoz.example.com/login")
+	require.NoError(t, err)
+
+	provider, err := idp.config().GetProvider("example.com", siteUrl)
+	require.NoError(t, err)
+
+	authURL, err := provider.BuildAuthURL("https://signoz.example.com/login?domainId=1")
+	require.NoError(t, err)
+
+	parsed, err := url.Parse(authURL)
+	require.NoError(t, err)
+	assert.Equal(t, idp.server.URL+"/authorize", parsed.Scheme+"://"+parsed.Host+parsed.Path)
+	assert.Equal(t, stubClientID, parsed.Query().Get("client_id"))
+	assert.Equal(t, "https://signoz.example.com/api/v1/complete/oidc", parsed.Query().Get("redirect_uri"))
+	assert.Equal(t, "openid email profile", parsed.Query().Get("scope"))
+	assert.Equal(t, "https://signoz.example.com/login?domainId=1", parsed.Query().Get("state"))
+}
+
+func TestOIDCProviderHandleCallback(t *testing.T) {
+	testCases := []struct {
+		name     string
+		claims   jwt.MapClaims
+		userInfo map[string]interface{}
+		mapping  OIDCClaimMapping
+		email    string
+		username string
+		pass     bool
+	}{
+		{
+			name:     "Valid",
+			claims:   jwt.MapClaims{"email": "jane@example.com", "email_verified": true, "name": "Jane"},
+			email:    "jane@example.com",
+			username: "Jane",
+			pass:     true,
+		},
+		{
+			name:    "CustomClaims",
+			claims:  jwt.MapClaims{"upn": "jane@example.com", "display_name": "Jane"},
+			mapping: OIDCClaimMapping{Email: "upn", Name: "display_name"},
+			// the upn is in the token, the userinfo endpoint is not used
+			userInfo: map[string]interface{}{"sub": "other"},
+			email:    "jane@example.com",
+			username: "Jane",
+			pass:     true,
+		},
+		{
+			name:     "UserInfo",
+			claims:   jwt.MapClaims{},
+			userInfo: map[string]interface{}{"sub": "user-1", "email": "jane@example.com", "name": "Jane"},
+			email:    "jane@example.com",
+			username: "Jane",
+			pass:     true,
+		},
+		{
+			name:     "UserInfoOfOtherSubject",
+			claims:   jwt.MapClaims{},
+			userInfo: map[string]interface{}{"sub": "user-2", "email": "jane@example.com"},
+			pass:     false,
+		},
+		{
+			name:   "OtherDomain",
+			claims: jwt.MapClaims{"email": "jane@other.com", "email_verified": true},
+			pass:   false,
+		},
+		{
+			name:   "NotVerified",
+			claims: jwt.MapClaims{"email": "jane@example.com", "email_verified": false},
+			pass:   false,
+		},
+		{
+			name:   "OtherAudience",
+			claims: jwt.MapClaims{"email": "jane@example.com", "aud": "grafana"},
+			pass:   false,
+		},
+		{
+			name:   "Expired",
+			claims: jwt.MapClaims{"email": "jane@example.com", "exp": time.Now().Add(-time.Hour).Unix()},
+			pass:   false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			idp := newStubIdP(t)
+			idp.claims = tc.claims
+			idp.userInfo = tc.userInfo
+
+			config := idp.config()
+			config.ClaimMapping = tc.mapping
+
+			identity, err := callback(t, config)
+			if !tc.pass {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.email, identity.Email)
+			assert.Equal(t, tc.username, identity.Username)
+			assert.Equal(t, "user-1", identity.UserID)
+		})
+	}
+}
+
+func TestOIDCProviderAttributes(t *testing.T) {
+	idp := newStubIdP(t)
+	idp.claims = jwt.MapClaims{"email": "jane@example.com", "groups": []string{"sre", "engineering"}, "team": "platform", "level": 3}
+
+	identity, err := callback(t, idp.config())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sre", "engineering"}, identity.Attributes["groups"])
+	assert.Equal(t, []string{"platform"}, identity.Attributes["team"])
+	assert.NotContains(t, identity.Attributes, "level")
+}
+
+func TestOIDCProviderCachesJWKS(t *testing.T) {
+	idp := newStubIdP(t)
+	idp.claims = jwt.MapClaims{"email": "jane@example.com"}
+
+	for i := 0; i < 3; i++ {
+		_, err := callback(t, idp.config())
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, int32(1), idp.jwksRequests.Load())
+}
+
+func TestOIDCConfigValidate(t *testing.T) {
+	assert.NoError(t, (&OIDCConfig{DiscoveryURL: "https://idp.example.com/realms/signoz", ClientID: "id", ClientSecret: "secret"}).Validate())
+	assert.Error(t, (&OIDCConfig{DiscoveryURL: "idp.example.com", ClientID: "id", ClientSecret: "secret"}).Validate())
+	assert.Error(t, (&OIDCConfig{DiscoveryURL: "https://idp.example.com"}).Validate())
+
+	assert.Equal(t, "https://idp.example.com/realms/signoz", (&OIDCConfig{DiscoveryURL: "https://idp.example.com/realms/signoz/.well-known/openid-configuration"}).Issuer())
+	assert.Equal(t, []string{"openid", "groups"}, (&OIDCConfig{Scopes: []string{"groups"}}).scopes())
+}
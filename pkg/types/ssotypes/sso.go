@@ -18,6 +18,8 @@ type SSOIdentity struct {
 	Email             string
 	EmailVerified     bool
 	ConnectorData     []byte
+	// string valued SAML attributes or OIDC claims of the user, by name
+	Attributes map[string][]string
 }
 
 // OAuthCallbackProvider is an interface implemented by connectors which use an OAuth
@@ -0,0 +1,29 @@
+package teamtypes
+
+import (
+	"context"
+
+	"github.com/SigNoz/signoz/pkg/valuer"
+)
+
+type Store interface {
+	Create(context.Context, *Team) error
+	Get(context.Context, valuer.UUID, valuer.UUID) (*Team, error)
+	List(context.Context, valuer.UUID) ([]*Team, error)
+	Update(context.Context, *Team) error
+	// Delete deletes the team along with its members and ownerships
+	Delete(context.Context, valuer.UUID, valuer.UUID) error
+
+	CreateMember(context.Context, *Member) error
+	GetMember(context.Context, valuer.UUID, valuer.UUID) (*Member, error)
+	ListMembers(context.Context, valuer.UUID) ([]*Member, error)
+	UpdateMember(context.Context, *Member) error
+	DeleteMember(context.Context, valuer.UUID, valuer.UUID) error
+
+	// SetOwnership assigns the resource of the ownership to its team, taking it away from the team owning it before
+	SetOwnership(context.Context, *Ownership) error
+	DeleteOwnership(context.Context, valuer.UUID, ResourceType, string) error
+	ListOwnedResourceIDs(context.Context, valuer.UUID, valuer.UUID, ResourceType) ([]string, error)
+
+	RunInTx(context.Context, func(context.Context) error) error
+}
@@ -0,0 +1,293 @@
+package teamtypes
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/SigNoz/signoz/pkg/errors"
+	"github.com/SigNoz/signoz/pkg/types"
+	"github.com/SigNoz/signoz/pkg/types/alertmanagertypes"
+	"github.com/SigNoz/signoz/pkg/valuer"
+	"github.com/uptrace/bun"
+)
+
+var (
+	ErrCodeTeamNotFound            = errors.MustNewCode("team_not_found")
+	ErrCodeTeamAlreadyExists       = errors.MustNewCode("team_already_exists")
+	ErrCodeTeamMemberNotFound      = errors.MustNewCode("team_member_not_found")
+	ErrCodeTeamMemberAlreadyExists = errors.MustNewCode("team_member_already_exists")
+	ErrCodeTeamOwnershipNotFound   = errors.MustNewCode("team_ownership_not_found")
+)
+
+// RuleLabel is the label of the rules owned by a team, its value is the name of the team. The alerts of the rules are
+// routed to the channels of the team.
+const RuleLabel = alertmanagertypes.TeamMatcherName
+
+// names are used as label values, they are kept to the characters of a slug.
+var nameRegex = regexp.MustCompile(`^[a-z0-9]([a-z0-9_-]{0,61}[a-z0-9])?$`)
+
+type Role struct{ valuer.String }
+
+var (
+	RoleMember Role = Role{valuer.NewString("member")}
+	// maintainers manage the members, channels and resources of the team
+	RoleMaintainer Role = Role{valuer.NewString("maintainer")}
+)
+
+func NewRole(role string) (Role, error) {
+	switch role {
+	case RoleMember.StringValue():
+		return RoleMember, nil
+	case RoleMaintainer.StringValue():
+		return RoleMaintainer, nil
+	default:
+		return Role{}, errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "invalid team role: %s, must be one of member, maintainer", role)
+	}
+}
+
+func (r *Role) UnmarshalJSON(data []byte) error {
+	var value valuer.String
+	if err := value.UnmarshalJSON(data); err != nil {
+		return err
+	}
+
+	role, err := NewRole(value.StringValue())
+	if err != nil {
+		return err
+	}
+
+	*r = role
+	return nil
+}
+
+// ResourceType is the kind of resource a team can own. Rules are owned through their team label instead.
+type ResourceType struct{ valuer.String }
+
+var (
+	ResourceTypeDashboard = ResourceType{valuer.NewString("dashboard")}
+	ResourceTypeSavedView = ResourceType{valuer.NewString("saved_view")}
+)
+
+func NewResourceType(resourceType string) (ResourceType, error) {
+	switch resourceType {
+	case ResourceTypeDashboard.StringValue():
+		return ResourceTypeDashboard, nil
+	case ResourceTypeSavedView.StringValue():
+		return ResourceTypeSavedView, nil
+	default:
+		return ResourceType{}, errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "invalid resource type: %s, must be one of dashboard, saved_view", resourceType)
+	}
+}
+
+func (r *ResourceType) UnmarshalJSON(data []byte) error {
+	var value valuer.String
+	if err := value.UnmarshalJSON(data); err != nil {
+		return err
+	}
+
+	resourceType, err := NewResourceType(value.StringValue())
+	if err != nil {
+		return err
+	}
+
+	*r = resourceType
+	return nil
+}
+
+type Team struct {
+	bun.BaseModel `bun:"table:team"`
+
+	types.Identifiable
+	types.TimeAuditable
+	OrgID valuer.UUID `bun:"org_id,type:text,notnull,unique:org_id_name" json:"orgId"`
+	// the name can't be changed, it is the value of the team label of the rules
+	Name        string `bun:"name,type:text,notnull,unique:org_id_name" json:"name"`
+	DisplayName string `bun:"display_name,type:text,notnull" json:"displayName"`
+}
+
+type Member struct {
+	bun.BaseModel `bun:"table:team_member"`
+
+	types.Identifiable
+	types.TimeAuditable
+	TeamID valuer.UUID `bun:"team_id,type:text,notnull,unique:team_id_user_id"`
+	UserID valuer.UUID `bun:"user_id,type:text,notnull,unique:team_id_user_id"`
+	Role   Role        `bun:"role,type:text,notnull"`
+}
+
+// Ownership assigns a resource to the team owning it, a resource is owned by one team at most.
+type Ownership struct {
+	bun.BaseModel `bun:"table:team_ownership"`
+
+	types.Identifiable
+	types.TimeAuditable
+	OrgID        valuer.UUID  `bun:"org_id,type:text,notnull,unique:org_id_resource_type_resource_id"`
+	TeamID       valuer.UUID  `bun:"team_id,type:text,notnull"`
+	ResourceType ResourceType `bun:"resource_type,type:text,notnull,unique:org_id_resource_type_resource_id"`
+	ResourceID   string       `bun:"resource_id,type:text,notnull,unique:org_id_resource_type_resource_id"`
+}
+
+type GettableTeam struct {
+	*Team
+	// names of the channels the alerts of the team are sent to
+	Channels []string `json:"channels"`
+}
+
+type GettableMember struct {
+	UserID      valuer.UUID `json:"userId"`
+	Email       string      `json:"email"`
+	DisplayName string      `json:"displayName"`
+	Role        Role        `json:"role"`
+	CreatedAt   time.Time   `json:"createdAt"`
+}
+
+type PostableTeam struct {
+	Name        string   `json:"name"`
+	DisplayName string   `json:"displayName"`
+	Channels    []string `json:"channels"`
+}
+
+type UpdatableTeam struct {
+	DisplayName string   `json:"displayName"`
+	Channels    []string `json:"channels"`
+}
+
+type PostableMember struct {
+	UserID valuer.UUID `json:"userId"`
+	Role   Role        `json:"role"`
+}
+
+type UpdatableMember struct {
+	Role Role `json:"role"`
+}
+
+type PostableOwnership struct {
+	ResourceType ResourceType `json:"resourceType"`
+	ResourceID   string       `json:"resourceId"`
+}
+
+func NewTeam(orgID valuer.UUID, name string, displayName string) *Team {
+	return &Team{
+		Identifiable: types.Identifiable{
+			ID: valuer.GenerateUUID(),
+		},
+		TimeAuditable: types.TimeAuditable{
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		},
+		OrgID:       orgID,
+		Name:        name,
+		DisplayName: displayName,
+	}
+}
+
+func NewMember(teamID valuer.UUID, userID valuer.UUID, role Role) *Member {
+	return &Member{
+		Identifiable: types.Identifiable{
+			ID: valuer.GenerateUUID(),
+		},
+		TimeAuditable: types.TimeAuditable{
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		},
+		TeamID: teamID,
+		UserID: userID,
+		Role:   role,
+	}
+}
+
+func NewOwnership(orgID valuer.UUID, teamID valuer.UUID, resourceType ResourceType, resourceID string) *Ownership {
+	return &Ownership{
+		Identifiable: types.Identifiable{
+			ID: valuer.GenerateUUID(),
+		},
+		TimeAuditable: types.TimeAuditable{
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		},
+		OrgID:        orgID,
+		TeamID:       teamID,
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+	}
+}
+
+func NewGettableTeam(team *Team, channels []string) *GettableTeam {
+	if channels == nil {
+		channels = []string{}
+	}
+
+	return &GettableTeam{Team: team, Channels: channels}
+}
+
+func NewGettableMember(member *Member, user *types.GettableUser) *GettableMember {
+	return &GettableMember{
+		UserID:      member.UserID,
+		Email:       user.Email,
+		DisplayName: user.DisplayName,
+		Role:        member.Role,
+		CreatedAt:   member.CreatedAt,
+	}
+}
+
+func (team *Team) Update(displayName string) {
+	team.DisplayName = displayName
+	team.UpdatedAt = time.Now()
+}
+
+func (member *Member) Update(role Role) {
+	member.Role = role
+	member.UpdatedAt = time.Now()
+}
+
+func (p *PostableTeam) Validate() error {
+	if !nameRegex.MatchString(p.Name) {
+		return errors.Newf(errors.TypeInvalidInput, errors.CodeInvalidInput, "invalid team name: %q, must be lowercase alphanumeric characters, '-' or '_' of at most 63 characters", p.Name)
+	}
+
+	if p.DisplayName == "" {
+		p.DisplayName = p.Name
+	}
+
+	return nil
+}
+
+func (p *UpdatableTeam) Validate() error {
+	if p.DisplayName == "" {
+		return errors.New(errors.TypeInvalidInput, errors.CodeInvalidInput, "displayName is required")
+	}
+
+	return nil
+}
+
+func (p *PostableMember) Validate() error {
+	if p.UserID.IsZero() {
+		return errors.New(errors.TypeInvalidInput, errors.CodeInvalidInput, "userId is required")
+	}
+
+	if p.Role.IsZero() {
+		p.Role = RoleMember
+	}
+
+	return nil
+}
+
+func (p *UpdatableMember) Validate() error {
+	if p.Role.IsZero() {
+		return errors.New(errors.TypeInvalidInput, errors.CodeInvalidInput, "role is required")
+	}
+
+	return nil
+}
+
+func (p *PostableOwnership) Validate() error {
+	if p.ResourceType.IsZero() {
+		return errors.New(errors.TypeInvalidInput, errors.CodeInvalidInput, "resourceType is required")
+	}
+
+	if p.ResourceID == "" {
+		return errors.New(errors.TypeInvalidInput, errors.CodeInvalidInput, "resourceId is required")
+	}
+
+	return nil
+}
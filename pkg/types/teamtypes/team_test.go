@@ -0,0 +1,49 @@
+package teamtypes
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostableTeamValidate(t *testing.T) {
+	testCases := []struct {
+		name string
+		team string
+		pass bool
+	}{
+		{name: "Slug", team: "payments-eu_1", pass: true},
+		{name: "SingleCharacter", team: "a", pass: true},
+		{name: "Empty", team: "", pass: false},
+		{name: "Uppercase", team: "Payments", pass: false},
+		{name: "Space", team: "payments eu", pass: false},
+		{name: "TrailingDash", team: "payments-", pass: false},
+		{name: "TooLong", team: "a234567890123456789012345678901234567890123456789012345678901234", pass: false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			team := &PostableTeam{Name: testCase.team}
+			err := team.Validate()
+			if !testCase.pass {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.team, team.DisplayName)
+		})
+	}
+}
+
+func TestPostableMemberUnmarshalJSON(t *testing.T) {
+	member := new(PostableMember)
+	require.NoError(t, json.Unmarshal([]byte(`{"userId":"0196f794-ff30-7bee-a5f4-ef5ad315715e"}`), member))
+	require.NoError(t, member.Validate())
+	assert.Equal(t, RoleMember, member.Role)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"userId":"0196f794-ff30-7bee-a5f4-ef5ad315715e","role":"owner"}`), member))
+	assert.Error(t, json.Unmarshal([]byte(`{"resourceType":"rule","resourceId":"id"}`), new(PostableOwnership)))
+}
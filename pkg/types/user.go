@@ -36,6 +36,8 @@ type UserStore interface {
 	CreateUserWithPassword(ctx context.Context, user *User, password *FactorPassword) (*User, error)
 	CreateUser(ctx context.Context, user *User) error
 	GetUserByID(ctx context.Context, orgID string, id string) (*GettableUser, error)
+	// GetUser gets the user in their home org
+	GetUser(ctx context.Context, id string) (*User, error)
 	GetUserByEmailInOrg(ctx context.Context, orgID string, email string) (*GettableUser, error)
 	GetUsersByEmail(ctx context.Context, email string) ([]*GettableUser, error)
 	GetUsersByRoleInOrg(ctx context.Context, orgID string, role Role) ([]*GettableUser, error)
@@ -53,6 +55,26 @@ type UserStore interface {
 	UpdatePassword(ctx context.Context, userID string, password string) error
 	UpdatePasswordAndDeleteResetPasswordEntry(ctx context.Context, userID string, password string) error
 
+	// totp
+	CreateFactorTOTP(ctx context.Context, factor *FactorTOTP) error
+	GetFactorTOTPByUserID(ctx context.Context, userID string) (*FactorTOTP, error)
+	UpdateFactorTOTP(ctx context.Context, factor *FactorTOTP) error
+	// IncrementFactorTOTPAttempts counts an attempt as failed and returns the failed attempts including it.
+	IncrementFactorTOTPAttempts(ctx context.Context, id valuer.UUID) (int, error)
+	DeleteFactorTOTP(ctx context.Context, userID string) error
+
+	// membership
+	CreateMembership(ctx context.Context, membership *OrgMembership) error
+	ListMemberships(ctx context.Context, userID string) ([]*OrgMembership, error)
+
+	// session
+	CreateSession(ctx context.Context, session *StorableSession) error
+	GetSession(ctx context.Context, id string) (*StorableSession, error)
+	ListSessions(ctx context.Context, orgID string, userID string) ([]*StorableSession, error)
+	RotateSession(ctx context.Context, session *StorableSession, refreshTokenID string) error
+	RevokeSession(ctx context.Context, session *StorableSession) error
+	RevokeSessions(ctx context.Context, userID string, excludedID string, reason SessionRevocationReason) error
+
 	// Auth Domain
 	GetDomainByName(ctx context.Context, name string) (*StorableOrgDomain, error)
 	// org domain (auth domains) CRUD ops
@@ -243,6 +265,8 @@ type GettableUserJwt struct {
 type GettableLoginResponse struct {
 	GettableUserJwt
 	UserID string `json:"userId"`
+	// set instead of the tokens when the user has to complete the mfa challenge
+	MFA *GettableMFAChallenge `json:"mfa,omitempty"`
 }
 
 type GettableLoginPrecheck struct {